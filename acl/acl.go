@@ -68,6 +68,13 @@ type ACL struct {
 	variables         *iradix.Tree[capabilitySet]
 	wildcardVariables *iradix.Tree[capabilitySet]
 
+	// jobs and groups are keyed by the namespace and job ID, and additionally
+	// the task group name for groups, separated by null bytes.
+	jobs           *iradix.Tree[capabilitySet]
+	wildcardJobs   *iradix.Tree[capabilitySet]
+	groups         *iradix.Tree[capabilitySet]
+	wildcardGroups *iradix.Tree[capabilitySet]
+
 	// The attributes below store the policy value for policies that don't have
 	// fine-grained capabilities.
 	agent    string
@@ -115,6 +122,12 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	svTxn := iradix.New[capabilitySet]().Txn()
 	wsvTxn := iradix.New[capabilitySet]().Txn()
 
+	jobTxn := iradix.New[capabilitySet]().Txn()
+	wjobTxn := iradix.New[capabilitySet]().Txn()
+
+	grpTxn := iradix.New[capabilitySet]().Txn()
+	wgrpTxn := iradix.New[capabilitySet]().Txn()
+
 	for _, policy := range policies {
 	NAMESPACES:
 		for _, ns := range policy.Namespaces {
@@ -169,6 +182,25 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 				}
 			}
 
+			for _, job := range ns.Jobs {
+				key := ns.Name + "\x00" + job.Name
+				jobGlob := globDefinition || strings.Contains(job.Name, "*")
+
+				txn := jobTxn
+				if jobGlob {
+					txn = wjobTxn
+				}
+				mergeCapabilities(txn, key, job.Capabilities, JobCapabilityDeny)
+
+				for _, group := range job.Groups {
+					txn := grpTxn
+					if jobGlob || strings.Contains(group.Name, "*") {
+						txn = wgrpTxn
+					}
+					mergeCapabilities(txn, key+"\x00"+group.Name, group.Capabilities, JobCapabilityDeny)
+				}
+			}
+
 			// Deny always takes precedence
 			if capabilities.Check(NamespaceCapabilityDeny) {
 				continue NAMESPACES
@@ -296,9 +328,38 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	acl.variables = svTxn.Commit()
 	acl.wildcardVariables = wsvTxn.Commit()
 
+	acl.jobs = jobTxn.Commit()
+	acl.wildcardJobs = wjobTxn.Commit()
+
+	acl.groups = grpTxn.Commit()
+	acl.wildcardGroups = wgrpTxn.Commit()
+
 	return acl, nil
 }
 
+// mergeCapabilities adds caps to the capabilitySet stored at key in txn,
+// creating it if necessary. The deny capability overwrites any existing
+// capabilities and prevents further ones from being added.
+func mergeCapabilities(txn *iradix.Txn[capabilitySet], key string, caps []string, deny string) {
+	capabilities, ok := txn.Get([]byte(key))
+	if !ok {
+		capabilities = make(capabilitySet)
+		txn.Insert([]byte(key), capabilities)
+	}
+
+	if capabilities.Check(deny) {
+		return
+	}
+	for _, cap := range caps {
+		if cap == deny {
+			capabilities.Clear()
+			capabilities.Set(deny)
+			return
+		}
+		capabilities.Set(cap)
+	}
+}
+
 // AllowNsOp is shorthand for AllowNamespaceOperation
 func (a *ACL) AllowNsOp(ns string, op string) bool {
 	return a.AllowNamespaceOperation(ns, op)
@@ -372,6 +433,97 @@ func (a *ACL) AllowNamespace(ns string) bool {
 	return !capabilities.Check(PolicyDeny)
 }
 
+// AllowJobOp is shorthand for AllowJobOperation
+func (a *ACL) AllowJobOp(ns, jobID, op string) bool {
+	return a.AllowJobOperation(ns, jobID, op)
+}
+
+// AllowJobOperation checks if a given operation is allowed for a job. The
+// capabilities granted by job policies matching the job are added to those
+// granted by the namespace, and a deny in either takes precedence.
+func (a *ACL) AllowJobOperation(ns, jobID, op string) bool {
+	return a.AllowJobGroupOperation(ns, jobID, "", op)
+}
+
+// AllowJobGroupOperation checks if a given operation is allowed for a task
+// group of a job. The capabilities granted by task group policies matching the
+// group are added to those granted by the job and the namespace, and a deny at
+// any level takes precedence. If group is empty only the job and namespace
+// policies are considered.
+func (a *ACL) AllowJobGroupOperation(ns, jobID, group, op string) bool {
+	// Hot path if ACL is not enabled or if it's a management token.
+	if a == nil || a.management {
+		return true
+	}
+
+	nsCaps, nsOk := a.matchingNamespaceCapabilitySet(ns)
+	if nsOk && nsCaps.Check(NamespaceCapabilityDeny) {
+		return false
+	}
+
+	allow := nsOk && nsCaps.Check(op)
+
+	key := ns + "\x00" + jobID
+	if caps, ok := a.matchingJobCapabilitySet(a.jobs, a.wildcardJobs, key); ok {
+		if caps.Check(JobCapabilityDeny) {
+			return false
+		}
+		allow = allow || caps.Check(op)
+	}
+
+	if group != "" {
+		key += "\x00" + group
+		if caps, ok := a.matchingJobCapabilitySet(a.groups, a.wildcardGroups, key); ok {
+			if caps.Check(JobCapabilityDeny) {
+				return false
+			}
+			allow = allow || caps.Check(op)
+		}
+	}
+
+	return allow
+}
+
+// AllowJobSearch returns true if the operation is allowed for the namespace
+// or for at least one job policy that applies to the namespace.
+//
+// This is a very loose check and is expected that callers filter the jobs
+// they return with AllowJobOperation.
+func (a *ACL) AllowJobSearch(ns, op string) bool {
+	// Hot path if ACL is not enabled or if it's a management token.
+	if a == nil || a.management {
+		return true
+	}
+
+	if a.AllowNamespaceOperation(ns, op) {
+		return true
+	}
+
+	if ns != AllNamespacesSentinel {
+		nsCaps, ok := a.matchingNamespaceCapabilitySet(ns)
+		if ok && nsCaps.Check(NamespaceCapabilityDeny) {
+			return false
+		}
+	}
+
+	allow := false
+	checkFn := func(k []byte, v capabilitySet) bool {
+		jobNs, _, _ := strings.Cut(string(k), "\x00")
+		if ns == AllNamespacesSentinel || glob.Glob(jobNs, ns) {
+			allow = v.Check(op)
+		}
+		return allow
+	}
+
+	a.jobs.Root().Walk(checkFn)
+	if allow {
+		return true
+	}
+
+	a.wildcardJobs.Root().Walk(checkFn)
+	return allow
+}
+
 // AllowNodePoolOperation returns true if the given operation is allowed in the
 // node pool specified.
 func (a *ACL) AllowNodePoolOperation(pool string, op string) bool {
@@ -573,6 +725,17 @@ func (a *ACL) anyNamespaceAllows(cb func(capabilitySet) bool) bool {
 	return allow
 }
 
+// matchingJobCapabilitySet returns the capabilitySet stored at key in the
+// concrete tree, or the closest matching glob in the wildcard tree.
+func (a *ACL) matchingJobCapabilitySet(concrete, wildcard *iradix.Tree[capabilitySet], key string) (capabilitySet, bool) {
+	raw, ok := concrete.Get([]byte(key))
+	if ok {
+		return raw, true
+	}
+
+	return a.findClosestMatchingGlob(wildcard, key)
+}
+
 // matchingNodePoolCapabilitySet returns the capabilitySet that closest match
 // the node pool.
 func (a *ACL) matchingNodePoolCapabilitySet(pool string) (capabilitySet, bool) {
@@ -637,17 +800,27 @@ type matchingGlob struct {
 }
 
 func (a *ACL) findClosestMatchingGlob(radix *iradix.Tree[capabilitySet], ns string) (capabilitySet, bool) {
+	match, ok := closestMatchingGlob(radix, ns)
+	if !ok {
+		return capabilitySet{}, false
+	}
+	return match.capabilitySet, true
+}
+
+// closestMatchingGlob returns the glob of the radix tree which matches name
+// the closest.
+func closestMatchingGlob(radix *iradix.Tree[capabilitySet], name string) (matchingGlob, bool) {
 	// First, find all globs that match.
-	matchingGlobs := findAllMatchingWildcards(radix, ns)
+	matchingGlobs := findAllMatchingWildcards(radix, name)
 
 	// If none match, let's return.
 	if len(matchingGlobs) == 0 {
-		return capabilitySet{}, false
+		return matchingGlob{}, false
 	}
 
 	// If a single matches, lets be efficient and return early.
 	if len(matchingGlobs) == 1 {
-		return matchingGlobs[0].capabilitySet, true
+		return matchingGlobs[0], true
 	}
 
 	// Stable sort the matched globs, based on the character difference between
@@ -657,7 +830,7 @@ func (a *ACL) findClosestMatchingGlob(radix *iradix.Tree[capabilitySet], ns stri
 		return matchingGlobs[i].difference <= matchingGlobs[j].difference
 	})
 
-	return matchingGlobs[0].capabilitySet, true
+	return matchingGlobs[0], true
 }
 
 func findAllMatchingWildcards(radix *iradix.Tree[capabilitySet], name string) []matchingGlob {
@@ -923,6 +923,206 @@ func TestVariablesMatching(t *testing.T) {
 
 }
 
+func TestJobMatching(t *testing.T) {
+	ci.Parallel(t)
+
+	tests := []struct {
+		name   string
+		policy string
+		ns     string
+		job    string
+		group  string
+		op     string
+		allow  bool
+	}{
+		{
+			name: "job glob grants capability",
+			policy: `namespace "default" {
+				job "payments-*" { capabilities = ["submit-job"] }}`,
+			ns:    "default",
+			job:   "payments-api",
+			op:    NamespaceCapabilitySubmitJob,
+			allow: true,
+		},
+		{
+			name: "job glob does not match other jobs",
+			policy: `namespace "default" {
+				job "payments-*" { capabilities = ["submit-job"] }}`,
+			ns:    "default",
+			job:   "billing",
+			op:    NamespaceCapabilitySubmitJob,
+			allow: false,
+		},
+		{
+			name: "job glob does not match other namespaces",
+			policy: `namespace "default" {
+				job "payments-*" { capabilities = ["submit-job"] }}`,
+			ns:    "prod",
+			job:   "payments-api",
+			op:    NamespaceCapabilitySubmitJob,
+			allow: false,
+		},
+		{
+			name: "namespace glob with concrete job",
+			policy: `namespace "prod-*" {
+				job "payments" { policy = "write" }}`,
+			ns:    "prod-eu",
+			job:   "payments",
+			op:    NamespaceCapabilityDispatchJob,
+			allow: true,
+		},
+		{
+			name: "namespace capabilities apply to jobs",
+			policy: `namespace "default" {
+				policy = "read"
+				job "payments-*" { capabilities = ["submit-job"] }}`,
+			ns:    "default",
+			job:   "payments-api",
+			op:    NamespaceCapabilityReadJob,
+			allow: true,
+		},
+		{
+			name: "job deny overrides namespace",
+			policy: `namespace "default" {
+				policy = "write"
+				job "payments-*" { policy = "deny" }}`,
+			ns:    "default",
+			job:   "payments-api",
+			op:    NamespaceCapabilityReadJob,
+			allow: false,
+		},
+		{
+			name: "namespace deny overrides job",
+			policy: `namespace "default" {
+				capabilities = ["deny"]
+				job "payments-*" { policy = "write" }}`,
+			ns:    "default",
+			job:   "payments-api",
+			op:    NamespaceCapabilitySubmitJob,
+			allow: false,
+		},
+		{
+			name: "group grants capability",
+			policy: `namespace "default" {
+				job "payments-*" {
+					policy = "read"
+					group "api" { capabilities = ["alloc-exec"] }
+				}}`,
+			ns:    "default",
+			job:   "payments-api",
+			group: "api",
+			op:    NamespaceCapabilityAllocExec,
+			allow: true,
+		},
+		{
+			name: "group capability does not apply to other groups",
+			policy: `namespace "default" {
+				job "payments-*" {
+					policy = "read"
+					group "api" { capabilities = ["alloc-exec"] }
+				}}`,
+			ns:    "default",
+			job:   "payments-api",
+			group: "db",
+			op:    NamespaceCapabilityAllocExec,
+			allow: false,
+		},
+		{
+			name: "group inherits job capabilities",
+			policy: `namespace "default" {
+				job "payments-*" {
+					policy = "write"
+					group "api" { capabilities = ["read-logs"] }
+				}}`,
+			ns:    "default",
+			job:   "payments-api",
+			group: "api",
+			op:    NamespaceCapabilityAllocExec,
+			allow: true,
+		},
+		{
+			name: "group deny overrides job",
+			policy: `namespace "default" {
+				job "payments-*" {
+					policy = "write"
+					group "db" { policy = "deny" }
+				}}`,
+			ns:    "default",
+			job:   "payments-api",
+			group: "db",
+			op:    NamespaceCapabilityReadLogs,
+			allow: false,
+		},
+		{
+			name: "closest job glob wins",
+			policy: `namespace "default" {
+				job "payments-*" { policy = "deny" }
+				job "payments-api-*" { policy = "read" }}`,
+			ns:    "default",
+			job:   "payments-api-eu",
+			op:    NamespaceCapabilityReadJob,
+			allow: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := Parse(tc.policy)
+			must.NoError(t, err)
+
+			acl, err := NewACL(false, []*Policy{policy})
+			must.NoError(t, err)
+			must.Eq(t, tc.allow, acl.AllowJobGroupOperation(tc.ns, tc.job, tc.group, tc.op))
+			if tc.group == "" {
+				must.Eq(t, tc.allow, acl.AllowJobOp(tc.ns, tc.job, tc.op))
+			}
+		})
+	}
+
+	t.Run("merge policies", func(t *testing.T) {
+		p1, err := Parse(`namespace "default" { job "payments-*" { capabilities = ["read-job"] }}`)
+		must.NoError(t, err)
+		p2, err := Parse(`namespace "default" { job "payments-*" { capabilities = ["submit-job"] }}`)
+		must.NoError(t, err)
+		p3, err := Parse(`namespace "default" { job "payments-db" { policy = "deny" }}`)
+		must.NoError(t, err)
+
+		acl, err := NewACL(false, []*Policy{p1, p2, p3})
+		must.NoError(t, err)
+		must.True(t, acl.AllowJobOp("default", "payments-api", NamespaceCapabilityReadJob))
+		must.True(t, acl.AllowJobOp("default", "payments-api", NamespaceCapabilitySubmitJob))
+		must.False(t, acl.AllowJobOp("default", "payments-db", NamespaceCapabilityReadJob))
+	})
+
+	t.Run("search", func(t *testing.T) {
+		policy, err := Parse(`
+namespace "default" {
+	job "payments-*" { policy = "read" }
+}
+namespace "denied" {
+	policy = "deny"
+	job "payments-*" { policy = "read" }
+}`)
+		must.NoError(t, err)
+
+		acl, err := NewACL(false, []*Policy{policy})
+		must.NoError(t, err)
+		must.False(t, acl.AllowNsOp("default", NamespaceCapabilityListJobs))
+		must.True(t, acl.AllowJobSearch("default", NamespaceCapabilityListJobs))
+		must.True(t, acl.AllowJobSearch(AllNamespacesSentinel, NamespaceCapabilityListJobs))
+		must.False(t, acl.AllowJobSearch("default", NamespaceCapabilitySubmitJob))
+		must.False(t, acl.AllowJobSearch("denied", NamespaceCapabilityListJobs))
+		must.False(t, acl.AllowJobSearch("other", NamespaceCapabilityListJobs))
+	})
+
+	t.Run("management and disabled", func(t *testing.T) {
+		var disabled *ACL
+		must.True(t, disabled.AllowJobOp("default", "example", NamespaceCapabilitySubmitJob))
+		must.True(t, ManagementACL.AllowJobGroupOperation("default", "example", "web", NamespaceCapabilityAllocExec))
+		must.True(t, ManagementACL.AllowJobSearch("default", NamespaceCapabilityListJobs))
+	})
+}
+
 func TestACL_matchingCapabilitySet_returnsAllMatches(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,290 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package acl
+
+import (
+	"strings"
+
+	iradix "github.com/hashicorp/go-immutable-radix/v2"
+)
+
+const (
+	// The following are the kinds of resources whose effective capabilities
+	// can be explained.
+	ExplainResourceNamespace  = "namespace"
+	ExplainResourceNodePool   = "node_pool"
+	ExplainResourceHostVolume = "host_volume"
+	ExplainResourceVariable   = "variable"
+)
+
+var (
+	// explainNamespaceCapabilities are the namespace capabilities reported
+	// by ExplainNamespace.
+	explainNamespaceCapabilities = []string{
+		NamespaceCapabilityListJobs,
+		NamespaceCapabilityParseJob,
+		NamespaceCapabilityReadJob,
+		NamespaceCapabilitySubmitJob,
+		NamespaceCapabilityDispatchJob,
+		NamespaceCapabilityReadLogs,
+		NamespaceCapabilityReadFS,
+		NamespaceCapabilityWriteFS,
+		NamespaceCapabilityAllocExec,
+		NamespaceCapabilityAllocNodeExec,
+		NamespaceCapabilityAllocLifecycle,
+		NamespaceCapabilityAllocPortForward,
+		NamespaceCapabilityReadExecRecording,
+		NamespaceCapabilitySentinelOverride,
+		NamespaceCapabilityCSIRegisterPlugin,
+		NamespaceCapabilityCSIWriteVolume,
+		NamespaceCapabilityCSIReadVolume,
+		NamespaceCapabilityCSIListVolume,
+		NamespaceCapabilityCSIMountVolume,
+		NamespaceCapabilityListScalingPolicies,
+		NamespaceCapabilityReadScalingPolicy,
+		NamespaceCapabilityReadJobScaling,
+		NamespaceCapabilityScaleJob,
+		NamespaceCapabilitySubmitRecommendation,
+	}
+
+	// explainNodePoolCapabilities are the node pool capabilities reported by
+	// ExplainNodePool.
+	explainNodePoolCapabilities = []string{
+		NodePoolCapabilityRead,
+		NodePoolCapabilityWrite,
+		NodePoolCapabilityDelete,
+	}
+
+	// explainHostVolumeCapabilities are the host volume capabilities
+	// reported by ExplainHostVolume.
+	explainHostVolumeCapabilities = []string{
+		HostVolumeCapabilityMountReadOnly,
+		HostVolumeCapabilityMountReadWrite,
+	}
+
+	// explainVariablesCapabilities are the variables capabilities reported
+	// by ExplainVariable.
+	explainVariablesCapabilities = []string{
+		VariablesCapabilityList,
+		VariablesCapabilityRead,
+		VariablesCapabilityWrite,
+		VariablesCapabilityDestroy,
+	}
+)
+
+// NamedPolicy is a parsed policy along with the name it is stored under, so
+// the capabilities it grants or denies can be attributed to it.
+type NamedPolicy struct {
+	Name   string
+	Policy *Policy
+}
+
+// Explanation describes the effective capabilities a set of policies grants
+// on a single resource, and which policies are responsible for them.
+type Explanation struct {
+	// Resource is the kind of resource explained, one of the
+	// ExplainResource constants.
+	Resource string
+
+	// Namespace is the namespace of the variable path explained. It is only
+	// set for variables.
+	Namespace string
+
+	// Name is the name of the resource explained, or the variable path.
+	Name string
+
+	// RuleNamespace and Rule identify the rule, possibly a glob, which
+	// matched the resource. Rule is empty if no rule matched, in which case
+	// every capability is denied. RuleNamespace is only set for variables.
+	RuleNamespace string
+	Rule          string
+
+	// Capabilities holds the explanation of each capability that applies to
+	// the resource.
+	Capabilities []*CapabilityExplanation
+}
+
+// CapabilityExplanation describes whether a capability is allowed and which
+// policies granted or denied it.
+type CapabilityExplanation struct {
+	Capability string
+	Allowed    bool
+
+	// GrantedBy and DeniedBy list the names of the policies whose matching
+	// rule grants the capability or holds the deny capability.
+	GrantedBy []string
+	DeniedBy  []string
+}
+
+// ExplainNamespace explains the capabilities the policies grant on the
+// namespace.
+func ExplainNamespace(management bool, policies []*NamedPolicy, ns string) (*Explanation, error) {
+	aclObj, err := newNamedACL(management, policies)
+	if err != nil {
+		return nil, err
+	}
+
+	exp := &Explanation{Resource: ExplainResourceNamespace, Name: ns}
+	if !management {
+		exp.Rule = matchingRule(aclObj.namespaces, aclObj.wildcardNamespaces, ns)
+	}
+
+	exp.explain(policies, explainNamespaceCapabilities, NamespaceCapabilityDeny,
+		func(op string) bool { return aclObj.AllowNamespaceOperation(ns, op) },
+		func(p *Policy) []string {
+			var caps []string
+			for _, nsPolicy := range p.Namespaces {
+				if nsPolicy.Name == exp.Rule {
+					caps = append(caps, nsPolicy.Capabilities...)
+				}
+			}
+			return caps
+		})
+	return exp, nil
+}
+
+// ExplainNodePool explains the capabilities the policies grant on the node
+// pool.
+func ExplainNodePool(management bool, policies []*NamedPolicy, pool string) (*Explanation, error) {
+	aclObj, err := newNamedACL(management, policies)
+	if err != nil {
+		return nil, err
+	}
+
+	exp := &Explanation{Resource: ExplainResourceNodePool, Name: pool}
+	if !management {
+		exp.Rule = matchingRule(aclObj.nodePools, aclObj.wildcardNodePools, pool)
+	}
+
+	exp.explain(policies, explainNodePoolCapabilities, NodePoolCapabilityDeny,
+		func(op string) bool { return aclObj.AllowNodePoolOperation(pool, op) },
+		func(p *Policy) []string {
+			var caps []string
+			for _, npPolicy := range p.NodePools {
+				if npPolicy.Name == exp.Rule {
+					caps = append(caps, npPolicy.Capabilities...)
+				}
+			}
+			return caps
+		})
+	return exp, nil
+}
+
+// ExplainHostVolume explains the capabilities the policies grant on the host
+// volume.
+func ExplainHostVolume(management bool, policies []*NamedPolicy, volume string) (*Explanation, error) {
+	aclObj, err := newNamedACL(management, policies)
+	if err != nil {
+		return nil, err
+	}
+
+	exp := &Explanation{Resource: ExplainResourceHostVolume, Name: volume}
+	if !management {
+		exp.Rule = matchingRule(aclObj.hostVolumes, aclObj.wildcardHostVolumes, volume)
+	}
+
+	exp.explain(policies, explainHostVolumeCapabilities, HostVolumeCapabilityDeny,
+		func(op string) bool { return aclObj.AllowHostVolumeOperation(volume, op) },
+		func(p *Policy) []string {
+			var caps []string
+			for _, hvPolicy := range p.HostVolumes {
+				if hvPolicy.Name == exp.Rule {
+					caps = append(caps, hvPolicy.Capabilities...)
+				}
+			}
+			return caps
+		})
+	return exp, nil
+}
+
+// ExplainVariable explains the capabilities the policies grant on the
+// variable path within the namespace.
+func ExplainVariable(management bool, policies []*NamedPolicy, ns, path string) (*Explanation, error) {
+	aclObj, err := newNamedACL(management, policies)
+	if err != nil {
+		return nil, err
+	}
+
+	exp := &Explanation{Resource: ExplainResourceVariable, Namespace: ns, Name: path}
+	if !management {
+		rule := matchingRule(aclObj.variables, aclObj.wildcardVariables, ns+"\x00"+path)
+		exp.RuleNamespace, exp.Rule, _ = strings.Cut(rule, "\x00")
+	}
+
+	exp.explain(policies, explainVariablesCapabilities, VariablesCapabilityDeny,
+		func(op string) bool { return aclObj.AllowVariableOperation(ns, path, op, nil) },
+		func(p *Policy) []string {
+			var caps []string
+			for _, nsPolicy := range p.Namespaces {
+				if nsPolicy.Name != exp.RuleNamespace || nsPolicy.Variables == nil {
+					continue
+				}
+				for _, pathPolicy := range nsPolicy.Variables.Paths {
+					if pathPolicy.PathSpec == exp.Rule {
+						caps = append(caps, pathPolicy.Capabilities...)
+					}
+				}
+			}
+			return caps
+		})
+	return exp, nil
+}
+
+// newNamedACL compiles the named policies into an ACL object.
+func newNamedACL(management bool, policies []*NamedPolicy) (*ACL, error) {
+	parsed := make([]*Policy, 0, len(policies))
+	for _, policy := range policies {
+		parsed = append(parsed, policy.Policy)
+	}
+	return NewACL(management, parsed)
+}
+
+// explain fills in the explanation of each capability. allowed reports
+// whether the compiled ACL allows a capability, and ruleCaps returns the
+// capabilities granted by the rules of a policy matching the explained rule.
+func (e *Explanation) explain(policies []*NamedPolicy, caps []string, deny string,
+	allowed func(string) bool, ruleCaps func(*Policy) []string) {
+
+	e.Capabilities = make([]*CapabilityExplanation, 0, len(caps))
+	for _, cap := range caps {
+		e.Capabilities = append(e.Capabilities, &CapabilityExplanation{
+			Capability: cap,
+			Allowed:    allowed(cap),
+		})
+	}
+
+	// Without a matching rule no policy is responsible for any capability.
+	if e.Rule == "" {
+		return
+	}
+
+	for _, policy := range policies {
+		granted := make(capabilitySet)
+		for _, cap := range ruleCaps(policy.Policy) {
+			granted.Set(cap)
+		}
+
+		for _, capExp := range e.Capabilities {
+			if granted.Check(capExp.Capability) {
+				capExp.GrantedBy = append(capExp.GrantedBy, policy.Name)
+			}
+			if granted.Check(deny) {
+				capExp.DeniedBy = append(capExp.DeniedBy, policy.Name)
+			}
+		}
+	}
+}
+
+// matchingRule returns the key of the concrete tree matching name, or the
+// closest matching glob of the wildcard tree. It returns an empty string if
+// nothing matches.
+func matchingRule(concrete, wildcard *iradix.Tree[capabilitySet], name string) string {
+	if _, ok := concrete.Get([]byte(name)); ok {
+		return name
+	}
+	if match, ok := closestMatchingGlob(wildcard, name); ok {
+		return match.name
+	}
+	return ""
+}
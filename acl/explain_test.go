@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package acl
+
+import (
+	"testing"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/shoenig/test/must"
+)
+
+func testNamedPolicy(t *testing.T, name, rules string) *NamedPolicy {
+	t.Helper()
+	p, err := Parse(rules)
+	must.NoError(t, err)
+	return &NamedPolicy{Name: name, Policy: p}
+}
+
+// findCapability returns the explanation of the capability.
+func findCapability(t *testing.T, exp *Explanation, capability string) *CapabilityExplanation {
+	t.Helper()
+	for _, c := range exp.Capabilities {
+		if c.Capability == capability {
+			return c
+		}
+	}
+	t.Fatalf("capability %q not explained", capability)
+	return nil
+}
+
+func TestExplainNamespace(t *testing.T) {
+	ci.Parallel(t)
+
+	policies := []*NamedPolicy{
+		testNamedPolicy(t, "readers", `namespace "prod-*" { policy = "read" }`),
+		testNamedPolicy(t, "deployers", `namespace "prod-*" { capabilities = ["submit-job", "read-job"] }`),
+		testNamedPolicy(t, "lockdown", `namespace "prod-api" { policy = "deny" }`),
+		testNamedPolicy(t, "dev", `namespace "dev" { policy = "write" }`),
+	}
+
+	t.Run("glob", func(t *testing.T) {
+		exp, err := ExplainNamespace(false, policies, "prod-web")
+		must.NoError(t, err)
+		must.Eq(t, ExplainResourceNamespace, exp.Resource)
+		must.Eq(t, "prod-web", exp.Name)
+		must.Eq(t, "prod-*", exp.Rule)
+
+		readJob := findCapability(t, exp, NamespaceCapabilityReadJob)
+		must.True(t, readJob.Allowed)
+		must.Eq(t, []string{"readers", "deployers"}, readJob.GrantedBy)
+		must.SliceEmpty(t, readJob.DeniedBy)
+
+		submitJob := findCapability(t, exp, NamespaceCapabilitySubmitJob)
+		must.True(t, submitJob.Allowed)
+		must.Eq(t, []string{"deployers"}, submitJob.GrantedBy)
+
+		allocExec := findCapability(t, exp, NamespaceCapabilityAllocExec)
+		must.False(t, allocExec.Allowed)
+		must.SliceEmpty(t, allocExec.GrantedBy)
+	})
+
+	t.Run("concrete deny", func(t *testing.T) {
+		exp, err := ExplainNamespace(false, policies, "prod-api")
+		must.NoError(t, err)
+		must.Eq(t, "prod-api", exp.Rule)
+
+		for _, c := range exp.Capabilities {
+			must.False(t, c.Allowed)
+			must.Eq(t, []string{"lockdown"}, c.DeniedBy)
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		exp, err := ExplainNamespace(false, policies, "staging")
+		must.NoError(t, err)
+		must.Eq(t, "", exp.Rule)
+		for _, c := range exp.Capabilities {
+			must.False(t, c.Allowed)
+			must.SliceEmpty(t, c.GrantedBy)
+		}
+	})
+
+	t.Run("management", func(t *testing.T) {
+		exp, err := ExplainNamespace(true, nil, "staging")
+		must.NoError(t, err)
+		for _, c := range exp.Capabilities {
+			must.True(t, c.Allowed)
+		}
+	})
+}
+
+func TestExplainNodePool(t *testing.T) {
+	ci.Parallel(t)
+
+	policies := []*NamedPolicy{
+		testNamedPolicy(t, "pools", `node_pool "*" { policy = "read" }`),
+		testNamedPolicy(t, "gpu", `node_pool "gpu" { policy = "write" }`),
+	}
+
+	exp, err := ExplainNodePool(false, policies, "gpu")
+	must.NoError(t, err)
+	must.Eq(t, "gpu", exp.Rule)
+	must.Eq(t, []string{"gpu"}, findCapability(t, exp, NodePoolCapabilityRead).GrantedBy)
+	must.True(t, findCapability(t, exp, NodePoolCapabilityWrite).Allowed)
+
+	exp, err = ExplainNodePool(false, policies, "default")
+	must.NoError(t, err)
+	must.Eq(t, "*", exp.Rule)
+	must.True(t, findCapability(t, exp, NodePoolCapabilityRead).Allowed)
+	must.False(t, findCapability(t, exp, NodePoolCapabilityWrite).Allowed)
+}
+
+func TestExplainHostVolume(t *testing.T) {
+	ci.Parallel(t)
+
+	policies := []*NamedPolicy{
+		testNamedPolicy(t, "volumes", `host_volume "shared-*" { policy = "read" }`),
+	}
+
+	exp, err := ExplainHostVolume(false, policies, "shared-data")
+	must.NoError(t, err)
+	must.Eq(t, "shared-*", exp.Rule)
+
+	readOnly := findCapability(t, exp, HostVolumeCapabilityMountReadOnly)
+	must.True(t, readOnly.Allowed)
+	must.Eq(t, []string{"volumes"}, readOnly.GrantedBy)
+	must.False(t, findCapability(t, exp, HostVolumeCapabilityMountReadWrite).Allowed)
+}
+
+func TestExplainVariable(t *testing.T) {
+	ci.Parallel(t)
+
+	policies := []*NamedPolicy{
+		testNamedPolicy(t, "secrets", `
+namespace "default" {
+  variables {
+    path "app/*" { capabilities = ["read"] }
+    path "app/db" { capabilities = ["write"] }
+  }
+}`),
+	}
+
+	exp, err := ExplainVariable(false, policies, "default", "app/web")
+	must.NoError(t, err)
+	must.Eq(t, ExplainResourceVariable, exp.Resource)
+	must.Eq(t, "default", exp.Namespace)
+	must.Eq(t, "default", exp.RuleNamespace)
+	must.Eq(t, "app/*", exp.Rule)
+
+	read := findCapability(t, exp, VariablesCapabilityRead)
+	must.True(t, read.Allowed)
+	must.Eq(t, []string{"secrets"}, read.GrantedBy)
+	must.True(t, findCapability(t, exp, VariablesCapabilityList).Allowed)
+	must.False(t, findCapability(t, exp, VariablesCapabilityWrite).Allowed)
+
+	exp, err = ExplainVariable(false, policies, "default", "app/db")
+	must.NoError(t, err)
+	must.Eq(t, "app/db", exp.Rule)
+	must.True(t, findCapability(t, exp, VariablesCapabilityWrite).Allowed)
+	must.False(t, findCapability(t, exp, VariablesCapabilityRead).Allowed)
+}
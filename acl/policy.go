@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/hcl/hcl/ast"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -37,9 +38,12 @@ const (
 	NamespaceCapabilityDispatchJob          = "dispatch-job"
 	NamespaceCapabilityReadLogs             = "read-logs"
 	NamespaceCapabilityReadFS               = "read-fs"
+	NamespaceCapabilityWriteFS              = "write-fs"
 	NamespaceCapabilityAllocExec            = "alloc-exec"
 	NamespaceCapabilityAllocNodeExec        = "alloc-node-exec"
 	NamespaceCapabilityAllocLifecycle       = "alloc-lifecycle"
+	NamespaceCapabilityAllocPortForward     = "alloc-port-forward"
+	NamespaceCapabilityReadExecRecording    = "read-exec-recording"
 	NamespaceCapabilitySentinelOverride     = "sentinel-override"
 	NamespaceCapabilityCSIRegisterPlugin    = "csi-register-plugin"
 	NamespaceCapabilityCSIWriteVolume       = "csi-write-volume"
@@ -57,6 +61,31 @@ var (
 	validNamespace = regexp.MustCompile("^[a-zA-Z0-9-*]{1,128}$")
 )
 
+const (
+	// The following are the fine-grained capabilities that can be granted
+	// to jobs matching a job policy within a namespace. They are a subset of
+	// the namespace capabilities and are added to those granted by the
+	// namespace itself. If the deny capability is present, it takes
+	// precedence over any capability granted to the job by the namespace.
+	//
+	// Task group policies within a job policy may only grant the capabilities
+	// that act on allocations.
+
+	JobCapabilityDeny             = NamespaceCapabilityDeny
+	JobCapabilityListJobs         = NamespaceCapabilityListJobs
+	JobCapabilityReadJob          = NamespaceCapabilityReadJob
+	JobCapabilitySubmitJob        = NamespaceCapabilitySubmitJob
+	JobCapabilityDispatchJob      = NamespaceCapabilityDispatchJob
+	JobCapabilityReadLogs         = NamespaceCapabilityReadLogs
+	JobCapabilityReadFS           = NamespaceCapabilityReadFS
+	JobCapabilityWriteFS          = NamespaceCapabilityWriteFS
+	JobCapabilityAllocExec        = NamespaceCapabilityAllocExec
+	JobCapabilityAllocLifecycle   = NamespaceCapabilityAllocLifecycle
+	JobCapabilityAllocPortForward = NamespaceCapabilityAllocPortForward
+	JobCapabilityReadJobScaling   = NamespaceCapabilityReadJobScaling
+	JobCapabilityScaleJob         = NamespaceCapabilityScaleJob
+)
+
 const (
 	// The following are the fine-grained capabilities that can be granted for
 	// node volume management.
@@ -134,6 +163,24 @@ type NamespacePolicy struct {
 	Policy       string
 	Capabilities []string
 	Variables    *VariablesPolicy `hcl:"variables"`
+	Jobs         []*JobPolicy     `hcl:"job,expand"`
+}
+
+// JobPolicy is the policy for the jobs within a namespace whose ID matches
+// Name, which may be a glob.
+type JobPolicy struct {
+	Name         string `hcl:",key"`
+	Policy       string
+	Capabilities []string
+	Groups       []*JobGroupPolicy `hcl:"group,expand"`
+}
+
+// JobGroupPolicy is the policy for the task groups of a job whose name
+// matches Name, which may be a glob.
+type JobGroupPolicy struct {
+	Name         string `hcl:",key"`
+	Policy       string
+	Capabilities []string
 }
 
 // NodePoolPolicy is the policfy for a specific node pool.
@@ -203,8 +250,9 @@ func isNamespaceCapabilityValid(cap string) bool {
 	switch cap {
 	case NamespaceCapabilityDeny, NamespaceCapabilityParseJob, NamespaceCapabilityListJobs, NamespaceCapabilityReadJob,
 		NamespaceCapabilitySubmitJob, NamespaceCapabilityDispatchJob, NamespaceCapabilityReadLogs,
-		NamespaceCapabilityReadFS, NamespaceCapabilityAllocLifecycle,
-		NamespaceCapabilityAllocExec, NamespaceCapabilityAllocNodeExec,
+		NamespaceCapabilityReadFS, NamespaceCapabilityWriteFS, NamespaceCapabilityAllocLifecycle,
+		NamespaceCapabilityAllocExec, NamespaceCapabilityAllocNodeExec, NamespaceCapabilityAllocPortForward,
+		NamespaceCapabilityReadExecRecording,
 		NamespaceCapabilityCSIReadVolume, NamespaceCapabilityCSIWriteVolume, NamespaceCapabilityCSIListVolume, NamespaceCapabilityCSIMountVolume, NamespaceCapabilityCSIRegisterPlugin,
 		NamespaceCapabilityListScalingPolicies, NamespaceCapabilityReadScalingPolicy, NamespaceCapabilityReadJobScaling, NamespaceCapabilityScaleJob:
 		return true
@@ -216,6 +264,31 @@ func isNamespaceCapabilityValid(cap string) bool {
 	}
 }
 
+// isJobCapabilityValid ensures the given capability is valid for a job policy
+func isJobCapabilityValid(cap string) bool {
+	switch cap {
+	case JobCapabilityDeny, JobCapabilityListJobs, JobCapabilityReadJob,
+		JobCapabilitySubmitJob, JobCapabilityDispatchJob, JobCapabilityReadLogs,
+		JobCapabilityReadFS, JobCapabilityWriteFS, JobCapabilityAllocExec, JobCapabilityAllocLifecycle,
+		JobCapabilityAllocPortForward, JobCapabilityReadJobScaling, JobCapabilityScaleJob:
+		return true
+	default:
+		return false
+	}
+}
+
+// isJobGroupCapabilityValid ensures the given capability is valid for a task
+// group policy
+func isJobGroupCapabilityValid(cap string) bool {
+	switch cap {
+	case JobCapabilityDeny, JobCapabilityReadLogs, JobCapabilityReadFS, JobCapabilityWriteFS,
+		JobCapabilityAllocExec, JobCapabilityAllocLifecycle, JobCapabilityAllocPortForward:
+		return true
+	default:
+		return false
+	}
+}
+
 // isPathCapabilityValid ensures the given capability is valid for a
 // variables path policy
 func isPathCapabilityValid(cap string) bool {
@@ -251,8 +324,10 @@ func expandNamespacePolicy(policy string) []string {
 		NamespaceCapabilityDispatchJob,
 		NamespaceCapabilityReadLogs,
 		NamespaceCapabilityReadFS,
+		NamespaceCapabilityWriteFS,
 		NamespaceCapabilityAllocExec,
 		NamespaceCapabilityAllocLifecycle,
+		NamespaceCapabilityAllocPortForward,
 		NamespaceCapabilityCSIMountVolume,
 		NamespaceCapabilityCSIWriteVolume,
 		NamespaceCapabilitySubmitRecommendation,
@@ -277,6 +352,64 @@ func expandNamespacePolicy(policy string) []string {
 	}
 }
 
+// expandJobPolicy provides the equivalent set of capabilities for a job
+// policy
+func expandJobPolicy(policy string) []string {
+	read := []string{
+		JobCapabilityListJobs,
+		JobCapabilityReadJob,
+		JobCapabilityReadJobScaling,
+	}
+
+	switch policy {
+	case PolicyDeny:
+		return []string{JobCapabilityDeny}
+	case PolicyRead:
+		return read
+	case PolicyWrite:
+		return append(read, []string{
+			JobCapabilityScaleJob,
+			JobCapabilitySubmitJob,
+			JobCapabilityDispatchJob,
+			JobCapabilityReadLogs,
+			JobCapabilityReadFS,
+			JobCapabilityWriteFS,
+			JobCapabilityAllocExec,
+			JobCapabilityAllocLifecycle,
+			JobCapabilityAllocPortForward,
+		}...)
+	case PolicyScale:
+		return []string{
+			JobCapabilityReadJobScaling,
+			JobCapabilityScaleJob,
+		}
+	default:
+		return nil
+	}
+}
+
+// expandJobGroupPolicy provides the equivalent set of capabilities for a task
+// group policy
+func expandJobGroupPolicy(policy string) []string {
+	switch policy {
+	case PolicyDeny:
+		return []string{JobCapabilityDeny}
+	case PolicyRead:
+		return []string{JobCapabilityReadLogs, JobCapabilityReadFS}
+	case PolicyWrite:
+		return []string{
+			JobCapabilityReadLogs,
+			JobCapabilityReadFS,
+			JobCapabilityWriteFS,
+			JobCapabilityAllocExec,
+			JobCapabilityAllocLifecycle,
+			JobCapabilityAllocPortForward,
+		}
+	default:
+		return nil
+	}
+}
+
 func isNodePoolCapabilityValid(cap string) bool {
 	switch cap {
 	case NodePoolCapabilityDelete, NodePoolCapabilityRead, NodePoolCapabilityWrite,
@@ -407,6 +540,11 @@ func Parse(rules string) (*Policy, error) {
 
 		}
 
+		for _, job := range ns.Jobs {
+			if err := parseJobPolicy(ns.Name, job); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	for _, np := range p.NodePools {
@@ -471,6 +609,70 @@ func Parse(rules string) (*Policy, error) {
 	return p, nil
 }
 
+// parseJobPolicy validates a job policy within the namespace ns and expands
+// its short hand policies into capabilities.
+func parseJobPolicy(ns string, job *JobPolicy) error {
+	if job.Name == "" {
+		return fmt.Errorf("Invalid missing job name in namespace %s", ns)
+	}
+	if job.Policy != "" && !isPolicyValid(job.Policy) {
+		return fmt.Errorf("Invalid job policy '%s' for job %s in namespace %s", job.Policy, job.Name, ns)
+	}
+	for _, cap := range job.Capabilities {
+		if !isJobCapabilityValid(cap) {
+			return fmt.Errorf("Invalid job capability '%s' for job %s in namespace %s", cap, job.Name, ns)
+		}
+	}
+	if job.Policy != "" {
+		job.Capabilities = append(job.Capabilities, expandJobPolicy(job.Policy)...)
+	}
+
+	for _, group := range job.Groups {
+		if group.Name == "" {
+			return fmt.Errorf("Invalid missing group name for job %s in namespace %s", job.Name, ns)
+		}
+		if group.Policy != "" && expandJobGroupPolicy(group.Policy) == nil {
+			return fmt.Errorf("Invalid group policy '%s' for group %s of job %s in namespace %s",
+				group.Policy, group.Name, job.Name, ns)
+		}
+		for _, cap := range group.Capabilities {
+			if !isJobGroupCapabilityValid(cap) {
+				return fmt.Errorf("Invalid group capability '%s' for group %s of job %s in namespace %s",
+					cap, group.Name, job.Name, ns)
+			}
+		}
+		if group.Policy != "" {
+			group.Capabilities = append(group.Capabilities, expandJobGroupPolicy(group.Policy)...)
+		}
+	}
+	return nil
+}
+
+// unflattenBlocks restores the nested blocks of JSON objects labeled with
+// block that only contain one of them. The JSON parser flattens an object with
+// a single nested object into additional keys, which the decoder would
+// otherwise apply to the labeled block itself. Other keys are left as they are
+// to keep decoding existing policies the same way.
+func unflattenBlocks(list *ast.ObjectList, block string, nested ...string) {
+	for _, item := range list.Items {
+		if len(item.Keys) < 3 || item.Keys[0].Token.Value() != block {
+			continue
+		}
+		key, _ := item.Keys[2].Token.Value().(string)
+		if !slices.Contains(nested, key) {
+			continue
+		}
+
+		item.Val = &ast.ObjectType{
+			List: &ast.ObjectList{Items: []*ast.ObjectItem{{
+				Keys: item.Keys[2:],
+				Val:  item.Val,
+			}}},
+		}
+		item.Keys = item.Keys[:2]
+	}
+}
+
 // hclDecode wraps hcl.Decode function but handles any unexpected panics
 func hclDecode(p *Policy, rules string) (err error) {
 	defer func() {
@@ -479,10 +681,6 @@ func hclDecode(p *Policy, rules string) (err error) {
 		}
 	}()
 
-	if err = hcl.Decode(p, rules); err != nil {
-		return err
-	}
-
 	// Manually parse the policy to fix blocks without labels.
 	//
 	// Due to a bug in the way HCL decodes files, a block without a label may
@@ -502,6 +700,16 @@ func hclDecode(p *Policy, rules string) (err error) {
 		return errors.New("error parsing: root should be an object")
 	}
 
+	unflattenBlocks(list, "namespace", "job", "variables")
+	for _, item := range list.Items {
+		if ot, ok := item.Val.(*ast.ObjectType); ok {
+			unflattenBlocks(ot.List, "job", "group")
+		}
+	}
+	if err = hcl.DecodeObject(p, root); err != nil {
+		return err
+	}
+
 	nsList := list.Filter("namespace")
 	for i, nsObj := range nsList.Items {
 		// Fix missing namespace key.
@@ -514,6 +722,31 @@ func hclDecode(p *Policy, rules string) (err error) {
 		if !ok {
 			continue
 		}
+
+		// Fix missing job and group names.
+		jobList := nsOT.List.Filter("job")
+		for j, jobObj := range jobList.Items {
+			if j >= len(p.Namespaces[i].Jobs) {
+				break
+			}
+			if len(jobObj.Keys) == 0 {
+				p.Namespaces[i].Jobs[j].Name = ""
+			}
+			if len(jobObj.Keys) > 1 {
+				return fmt.Errorf("unexpected nested keys in job %s", jobObj.Keys[0].Token.Text)
+			}
+			jobOT, ok := jobObj.Val.(*ast.ObjectType)
+			if !ok {
+				continue
+			}
+			groups := jobOT.List.Filter("group")
+			for k, group := range groups.Items {
+				if k < len(p.Namespaces[i].Jobs[j].Groups) && len(group.Keys) == 0 {
+					p.Namespaces[i].Jobs[j].Groups[k].Name = ""
+				}
+			}
+		}
+
 		varsList := nsOT.List.Filter("variables")
 		if varsList == nil || len(varsList.Items) == 0 {
 			continue
@@ -136,8 +136,10 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityDispatchJob,
 							NamespaceCapabilityReadLogs,
 							NamespaceCapabilityReadFS,
+							NamespaceCapabilityWriteFS,
 							NamespaceCapabilityAllocExec,
 							NamespaceCapabilityAllocLifecycle,
+							NamespaceCapabilityAllocPortForward,
 							NamespaceCapabilityCSIMountVolume,
 							NamespaceCapabilityCSIWriteVolume,
 							NamespaceCapabilitySubmitRecommendation,
@@ -356,8 +358,10 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityDispatchJob,
 							NamespaceCapabilityReadLogs,
 							NamespaceCapabilityReadFS,
+							NamespaceCapabilityWriteFS,
 							NamespaceCapabilityAllocExec,
 							NamespaceCapabilityAllocLifecycle,
+							NamespaceCapabilityAllocPortForward,
 							NamespaceCapabilityCSIMountVolume,
 							NamespaceCapabilityCSIWriteVolume,
 							NamespaceCapabilitySubmitRecommendation,
@@ -549,6 +553,234 @@ func TestParse(t *testing.T) {
 			"invalid acl policy",
 			nil,
 		},
+		{
+			`
+			namespace "default" {
+				job "payments-*" {
+					policy = "read"
+					capabilities = ["submit-job"]
+
+					group "api" {
+						capabilities = ["alloc-exec"]
+					}
+					group "*" {
+						policy = "read"
+					}
+				}
+				job "payments-db" {
+					policy = "deny"
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "default",
+						Jobs: []*JobPolicy{
+							{
+								Name:   "payments-*",
+								Policy: PolicyRead,
+								Capabilities: []string{
+									JobCapabilitySubmitJob,
+									JobCapabilityListJobs,
+									JobCapabilityReadJob,
+									JobCapabilityReadJobScaling,
+								},
+								Groups: []*JobGroupPolicy{
+									{
+										Name:         "api",
+										Capabilities: []string{JobCapabilityAllocExec},
+									},
+									{
+										Name:   "*",
+										Policy: PolicyRead,
+										Capabilities: []string{
+											JobCapabilityReadLogs,
+											JobCapabilityReadFS,
+										},
+									},
+								},
+							},
+							{
+								Name:         "payments-db",
+								Policy:       PolicyDeny,
+								Capabilities: []string{JobCapabilityDeny},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			namespace "default" {
+				job "payments-*" {
+					capabilities = ["csi-write-volume"]
+				}
+			}
+			`,
+			"Invalid job capability 'csi-write-volume'",
+			nil,
+		},
+		{
+			`
+			namespace "default" {
+				job {
+					policy = "read"
+				}
+			}
+			`,
+			"Invalid missing job name in namespace default",
+			nil,
+		},
+		{
+			`
+			namespace "default" {
+				job "payments-*" {
+					group "api" {
+						capabilities = ["submit-job"]
+					}
+				}
+			}
+			`,
+			"Invalid group capability 'submit-job'",
+			nil,
+		},
+		{
+			`
+			namespace "default" {
+				job "payments-*" {
+					group "api" {
+						policy = "scale"
+					}
+				}
+			}
+			`,
+			"Invalid group policy 'scale'",
+			nil,
+		},
+		{
+			`
+			{
+				"namespace": {
+					"default": {
+						"job": {
+							"payments-*": {
+								"policy": "write"
+							}
+						}
+					}
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "default",
+						Jobs: []*JobPolicy{
+							{
+								Name:   "payments-*",
+								Policy: PolicyWrite,
+								Capabilities: []string{
+									JobCapabilityListJobs,
+									JobCapabilityReadJob,
+									JobCapabilityReadJobScaling,
+									JobCapabilityScaleJob,
+									JobCapabilitySubmitJob,
+									JobCapabilityDispatchJob,
+									JobCapabilityReadLogs,
+									JobCapabilityReadFS,
+									JobCapabilityWriteFS,
+									JobCapabilityAllocExec,
+									JobCapabilityAllocLifecycle,
+									JobCapabilityAllocPortForward,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			{
+				"namespace": {
+					"default": {
+						"job": {
+							"payments-*": {
+								"group": {
+									"api": {
+										"policy": "read"
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "default",
+						Jobs: []*JobPolicy{
+							{
+								Name: "payments-*",
+								Groups: []*JobGroupPolicy{
+									{
+										Name:   "api",
+										Policy: PolicyRead,
+										Capabilities: []string{
+											JobCapabilityReadLogs,
+											JobCapabilityReadFS,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			{
+				"namespace": {
+					"default": {
+						"variables": {
+							"path": {
+								"project/*": {
+									"capabilities": ["read"]
+								}
+							}
+						}
+					}
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "default",
+						Variables: &VariablesPolicy{
+							Paths: []*VariablesPathPolicy{
+								{
+									PathSpec: "project/*",
+									Capabilities: []string{
+										VariablesCapabilityRead,
+										VariablesCapabilityList,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			`
 			agent {
@@ -624,6 +856,25 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		{
+			`
+			namespace "default" {
+				capabilities = ["read-exec-recording"]
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name:   "default",
+						Policy: "",
+						Capabilities: []string{
+							NamespaceCapabilityReadExecRecording,
+						},
+					},
+				},
+			},
+		},
 		{
 			`
 			node_pool "pool-read-only" {
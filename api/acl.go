@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"time"
 )
 
@@ -177,6 +178,40 @@ func (a *ACLTokens) Self(q *QueryOptions) (*ACLToken, *QueryMeta, error) {
 	return &resp, wm, nil
 }
 
+// Check is used to explain the effective permissions of a token, or of a set
+// of policies, on the namespace of the query and optionally a node pool, host
+// volume and variable path. The token making the request is checked if the
+// request sets neither an accessor ID nor policies.
+func (a *ACLTokens) Check(req *ACLTokenCheckRequest, q *QueryOptions) (*ACLTokenCheck, *QueryMeta, error) {
+	if req == nil {
+		req = &ACLTokenCheckRequest{}
+	}
+
+	v := url.Values{}
+	if req.AccessorID != "" {
+		v.Set("accessor", req.AccessorID)
+	}
+	for _, policy := range req.Policies {
+		v.Add("policy", policy)
+	}
+	if req.NodePool != "" {
+		v.Set("node_pool", req.NodePool)
+	}
+	if req.HostVolume != "" {
+		v.Set("host_volume", req.HostVolume)
+	}
+	if req.VariablePath != "" {
+		v.Set("variable", req.VariablePath)
+	}
+
+	var resp ACLTokenCheck
+	qm, err := a.client.query("/v1/acl/token/check?"+v.Encode(), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // UpsertOneTimeToken is used to create a one-time token
 func (a *ACLTokens) UpsertOneTimeToken(q *WriteOptions) (*OneTimeToken, *WriteMeta, error) {
 	var resp *OneTimeTokenUpsertResponse
@@ -557,6 +592,12 @@ type ACLToken struct {
 	// creation. This is a string version of a time.Duration like "2m".
 	ExpirationTTL time.Duration `json:",omitempty"`
 
+	// LastUsedTime and LastUsedAddress record when and from where the token
+	// was last used to authenticate a request. A nil LastUsedTime indicates
+	// the token has not been used since usage tracking was introduced.
+	LastUsedTime    *time.Time `json:",omitempty"`
+	LastUsedAddress string     `json:",omitempty"`
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -636,10 +677,93 @@ type ACLTokenListStub struct {
 	// indicates no expiration has been set on the token.
 	ExpirationTime *time.Time `json:",omitempty"`
 
+	// LastUsedTime and LastUsedAddress record when and from where the token
+	// was last used to authenticate a request.
+	LastUsedTime    *time.Time `json:",omitempty"`
+	LastUsedAddress string     `json:",omitempty"`
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// ACLTokenCheckRequest describes the token or policies whose effective
+// permissions are explained by ACLTokens.Check, and the resources to explain
+// in addition to the namespace of the query.
+type ACLTokenCheckRequest struct {
+	// AccessorID is the token to check.
+	AccessorID string
+
+	// Policies simulates a token linked to the named policies.
+	Policies []string
+
+	NodePool     string
+	HostVolume   string
+	VariablePath string
+}
+
+// ACLTokenCheck is the explanation of the effective permissions of a token or
+// a set of policies.
+type ACLTokenCheck struct {
+	// AccessorID is the accessor of the checked token, empty when simulating
+	// a set of policies.
+	AccessorID string
+
+	// Management is true if the checked token is a management token, in which
+	// case every capability is allowed.
+	Management bool
+
+	// Policies are the policies considered, directly linked or through a
+	// role.
+	Policies []*ACLTokenCheckPolicy
+
+	// Explanations holds the explanation of each resource checked.
+	Explanations []*ACLExplanation
+}
+
+// ACLTokenCheckPolicy is a policy considered by an ACL token check.
+type ACLTokenCheckPolicy struct {
+	Name string
+
+	// Role is the name of the role linking the policy to the token, empty if
+	// the policy is linked directly.
+	Role string
+
+	// Missing is true if the policy does not exist, in which case it grants
+	// nothing.
+	Missing bool
+}
+
+// ACLExplanation describes the effective capabilities on a single resource.
+type ACLExplanation struct {
+	// Resource is the kind of resource explained: "namespace", "node_pool",
+	// "host_volume" or "variable".
+	Resource string
+
+	// Namespace is the namespace of the variable path explained. It is only
+	// set for variables.
+	Namespace string
+
+	// Name is the name of the resource explained, or the variable path.
+	Name string
+
+	// RuleNamespace and Rule identify the rule, possibly a glob, which
+	// matched the resource. Rule is empty if no rule matched. RuleNamespace
+	// is only set for variables.
+	RuleNamespace string
+	Rule          string
+
+	Capabilities []*ACLCapabilityExplanation
+}
+
+// ACLCapabilityExplanation describes whether a capability is allowed and
+// which policies granted or denied it.
+type ACLCapabilityExplanation struct {
+	Capability string
+	Allowed    bool
+	GrantedBy  []string
+	DeniedBy   []string
+}
+
 type OneTimeToken struct {
 	OneTimeSecretID string
 	AccessorID      string
@@ -851,6 +975,40 @@ type ACLAuthMethodConfig struct {
 	// (value).
 	ClaimMappings     map[string]string
 	ListClaimMappings map[string]string
+	// List of LDAP server URLs to try in order
+	LDAPURLs []string
+	// Issue a StartTLS command after connecting to an ldap:// URL
+	LDAPStartTLS bool
+	// Skip verification of the LDAP server certificate
+	LDAPInsecureTLS bool
+	// PEM encoded CA cert for use by the TLS client used to talk with the
+	// LDAP server
+	LDAPCACert string
+	// Credentials used to search the directory, anonymous bind if empty
+	LDAPBindDN       string
+	LDAPBindPassword string
+	// Base DN under which to search for users
+	LDAPUserDN string
+	// Attribute holding the username, defaults to "uid"
+	LDAPUserAttr string
+	// Template of the user search filter
+	LDAPUserFilter string
+	// Base DN under which to search for groups; the memberOf attribute of the
+	// user is used if empty
+	LDAPGroupDN string
+	// Template of the group search filter
+	LDAPGroupFilter string
+	// Attribute of a group entry holding its name, defaults to "cn"
+	LDAPGroupAttr string
+	// PEM encoded CA certs client certificates must chain to
+	TLSCertCACerts []string
+	// Glob patterns restricting the accepted client certificates
+	TLSCertAllowedCommonNames []string
+	TLSCertAllowedDNSSANs     []string
+	TLSCertAllowedEmailSANs   []string
+	TLSCertAllowedURISANs     []string
+	// Extensions the client certificate must carry, as "<oid>:<glob>"
+	TLSCertRequiredExtensions []string
 }
 
 // MarshalJSON implements the json.Marshaler interface and allows
@@ -970,6 +1128,15 @@ const (
 	// ACLAuthMethodTypeJWT the ACLAuthMethod.Type and represents an auth-method
 	// which uses the JWT type.
 	ACLAuthMethodTypeJWT = "JWT"
+
+	// ACLAuthMethodTypeLDAP the ACLAuthMethod.Type and represents an
+	// auth-method which authenticates users against an LDAP directory.
+	ACLAuthMethodTypeLDAP = "LDAP"
+
+	// ACLAuthMethodTypeTLSCert the ACLAuthMethod.Type and represents an
+	// auth-method which authenticates callers using their TLS client
+	// certificate.
+	ACLAuthMethodTypeTLSCert = "TLSCert"
 )
 
 // ACLBindingRule contains a direct relation to an ACLAuthMethod and represents
@@ -1107,5 +1274,9 @@ type ACLLoginRequest struct {
 	// is a required parameter.
 	AuthMethodName string
 	// LoginToken is the token used to login. This is a required parameter.
+	// For LDAP auth methods this is the password of the user.
 	LoginToken string
+	// Username is the name of the user logging in. It is only used, and
+	// required, by LDAP auth methods.
+	Username string
 }
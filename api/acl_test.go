@@ -421,6 +421,60 @@ func TestACLTokens_Self(t *testing.T) {
 	must.Eq(t, out, out2)
 }
 
+func TestACLTokens_Check(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+	at := c.ACLTokens()
+
+	policy := &ACLPolicy{
+		Name:  "readers",
+		Rules: `namespace "default" { policy = "read" }`,
+	}
+	wm, err := c.ACLPolicies().Upsert(policy, nil)
+	must.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	token, _, err := at.Create(&ACLToken{
+		Name:     "foo",
+		Type:     "client",
+		Policies: []string{"readers"},
+	}, nil)
+	must.NoError(t, err)
+
+	// Check the token as the management token
+	out, qm, err := at.Check(&ACLTokenCheckRequest{
+		AccessorID: token.AccessorID,
+		NodePool:   "default",
+	}, nil)
+	must.NoError(t, err)
+	assertQueryMeta(t, qm)
+	must.Eq(t, token.AccessorID, out.AccessorID)
+	must.False(t, out.Management)
+	must.Eq(t, []*ACLTokenCheckPolicy{{Name: "readers"}}, out.Policies)
+	must.Len(t, 2, out.Explanations)
+	must.Eq(t, "namespace", out.Explanations[0].Resource)
+	must.Eq(t, "default", out.Explanations[0].Rule)
+	must.Eq(t, "node_pool", out.Explanations[1].Resource)
+
+	for _, c := range out.Explanations[0].Capabilities {
+		if c.Capability == "read-job" {
+			must.True(t, c.Allowed)
+			must.Eq(t, []string{"readers"}, c.GrantedBy)
+		}
+		if c.Capability == "submit-job" {
+			must.False(t, c.Allowed)
+		}
+	}
+
+	// Simulate a set of policies
+	out, _, err = at.Check(&ACLTokenCheckRequest{Policies: []string{"readers", "missing"}}, nil)
+	must.NoError(t, err)
+	must.Eq(t, "", out.AccessorID)
+	must.Eq(t, []*ACLTokenCheckPolicy{{Name: "readers"}, {Name: "missing", Missing: true}}, out.Policies)
+}
+
 func TestACLTokens_Delete(t *testing.T) {
 	testutil.Parallel(t)
 
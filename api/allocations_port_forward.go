@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/gorilla/websocket"
+)
+
+// PortForward forwards a connection to a port of a running allocation, given
+// either as the label of an allocated port or as a port number. Allocations
+// with their own network namespace can be forwarded to any port listening on
+// the loopback interface of the namespace. The connection is closed when
+// either side closes it or the context is cancelled.
+//
+// PortForward is safe to call concurrently with the same query options.
+func (a *Allocations) PortForward(ctx context.Context, alloc *Allocation, port string,
+	conn io.ReadWriteCloser, q *QueryOptions) error {
+
+	defer conn.Close()
+
+	ws, err := a.portForwardConnection(alloc, port, q)
+	if err != nil {
+		return err
+	}
+	defer ws.Close()
+
+	errCh := make(chan error, 2)
+
+	// Copy the local connection to the allocation
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := conn.Read(buf)
+			if n > 0 {
+				if err := ws.WriteMessage(websocket.BinaryMessage, buf[:n]); err != nil {
+					errCh <- err
+					return
+				}
+			}
+			if err != nil {
+				// The local connection closed, stop the session.
+				ws.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				errCh <- nil
+				return
+			}
+		}
+	}()
+
+	// Copy the connection of the allocation to the local connection
+	go func() {
+		for {
+			_, data, err := ws.ReadMessage()
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				errCh <- nil
+				return
+			} else if err != nil {
+				errCh <- err
+				return
+			}
+			if _, err := conn.Write(data); err != nil {
+				errCh <- nil
+				return
+			}
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *Allocations) portForwardConnection(alloc *Allocation, port string, q *QueryOptions) (*websocket.Conn, error) {
+	// First, attempt to connect to the node directly, but may fail due to network isolation
+	// and network errors.  Fallback to using server-side forwarding instead.
+	nodeClient, err := a.client.GetNodeClientWithTimeout(alloc.NodeID, ClientConnTimeout, q)
+	if err == NodeDownErr {
+		return nil, NodeDownErr
+	}
+
+	// Copy the query options so concurrent sessions don't share parameters
+	var qc QueryOptions
+	if q != nil {
+		qc = *q
+	}
+	qc.Params = make(map[string]string, len(qc.Params)+1)
+	if q != nil {
+		for k, v := range q.Params {
+			qc.Params[k] = v
+		}
+	}
+	qc.Params["port"] = port
+
+	reqPath := fmt.Sprintf("/v1/client/allocation/%s/port-forward", alloc.ID)
+
+	var ws *websocket.Conn
+	if nodeClient != nil {
+		ws, _, _ = nodeClient.websocket(reqPath, &qc)
+	}
+
+	if ws == nil {
+		ws, _, err = a.client.websocket(reqPath, &qc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ws, nil
+}
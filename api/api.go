@@ -43,6 +43,15 @@ const (
 	// by the API which indicates the caller does not have permission to
 	// perform the action.
 	PermissionDeniedErrorContent = "Permission denied"
+
+	// DefaultRateLimitRetries is the number of times a request rejected by
+	// the rate limits of the servers is retried when the configuration does
+	// not set RateLimitRetries.
+	DefaultRateLimitRetries = 3
+
+	// maxRateLimitRetryWait caps the time waited before retrying a request
+	// rejected by the rate limits of the servers.
+	maxRateLimitRetryWait = 30 * time.Second
 )
 
 // QueryOptions are used to parametrize a query
@@ -200,6 +209,11 @@ type Config struct {
 	TLSConfig *TLSConfig
 
 	Headers http.Header
+
+	// RateLimitRetries is the number of times a request rejected with a 429
+	// status by the rate limits of the servers is retried. Zero uses
+	// DefaultRateLimitRetries and a negative value disables retries.
+	RateLimitRetries int
 }
 
 // ClientConfig copies the configuration with a new client address, region, and
@@ -218,6 +232,8 @@ func (c *Config) ClientConfig(region, address string, tlsEnabled bool) *Config {
 		HttpAuth:   c.HttpAuth,
 		WaitTime:   c.WaitTime,
 		TLSConfig:  c.TLSConfig.Copy(),
+
+		RateLimitRetries: c.RateLimitRetries,
 	}
 
 	// Update the tls server name for connecting to a client
@@ -788,6 +804,62 @@ func (m *multiCloser) Read(p []byte) (int, error) {
 
 // doRequest runs a request with our client
 func (c *Client) doRequest(r *request) (time.Duration, *http.Response, error) {
+	retries := c.config.RateLimitRetries
+	if retries == 0 {
+		retries = DefaultRateLimitRetries
+	}
+
+	// Only requests whose body can be encoded again are retried; raw bodies
+	// may have been consumed by the first attempt.
+	retryable := r.body == nil
+
+	for attempt := 0; ; attempt++ {
+		diff, resp, err := c.doRequestOnce(r)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests ||
+			!retryable || attempt >= retries {
+			return diff, resp, err
+		}
+
+		wait := rateLimitRetryWait(resp, attempt)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		// Encode the body again for the next attempt
+		r.body = nil
+
+		ctx := r.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0, nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// rateLimitRetryWait returns how long to wait before retrying a request
+// rejected by the rate limits of the servers. It honors the Retry-After
+// header and otherwise backs off exponentially.
+func rateLimitRetryWait(resp *http.Response, attempt int) time.Duration {
+	wait := maxRateLimitRetryWait
+	if attempt < 5 {
+		wait = time.Second << attempt
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+		wait = time.Duration(secs) * time.Second
+	}
+	if wait > maxRateLimitRetryWait {
+		wait = maxRateLimitRetryWait
+	}
+	return wait
+}
+
+// doRequestOnce runs the request a single time.
+func (c *Client) doRequestOnce(r *request) (time.Duration, *http.Response, error) {
 	req, err := r.toHTTP()
 	if err != nil {
 		return 0, nil, err
@@ -112,6 +112,53 @@ func TestRequestTime(t *testing.T) {
 	}
 }
 
+func TestClient_RateLimitRetries(t *testing.T) {
+	testutil.Parallel(t)
+
+	var requests int
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if requests%3 != 0 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte("token rate limit exceeded"))
+			return
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	conf := DefaultConfig()
+	conf.Address = srv.URL
+	client, err := NewClient(conf)
+	must.NoError(t, err)
+
+	// The request succeeds on the third attempt, and its body is sent each
+	// time.
+	var out struct{}
+	_, err = client.put("/", struct{ S string }{"input"}, &out, nil)
+	must.NoError(t, err)
+	must.Eq(t, 3, requests)
+	for _, body := range bodies {
+		must.StrContains(t, body, "input")
+	}
+
+	// Retries can be disabled.
+	conf.RateLimitRetries = -1
+	client, err = NewClient(conf)
+	must.NoError(t, err)
+
+	_, err = client.query("/", &out, nil)
+	must.Error(t, err)
+	var respErr UnexpectedResponseError
+	must.True(t, errors.As(err, &respErr))
+	must.Eq(t, http.StatusTooManyRequests, respErr.StatusCode())
+	must.Eq(t, 4, requests)
+}
+
 func TestDefaultConfig_env(t *testing.T) {
 
 	testURL := "http://1.2.3.4:5678"
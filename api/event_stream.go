@@ -14,14 +14,16 @@ import (
 )
 
 const (
-	TopicDeployment Topic = "Deployment"
-	TopicEvaluation Topic = "Evaluation"
-	TopicAllocation Topic = "Allocation"
-	TopicJob        Topic = "Job"
-	TopicNode       Topic = "Node"
-	TopicNodePool   Topic = "NodePool"
-	TopicService    Topic = "Service"
-	TopicAll        Topic = "*"
+	TopicDeployment    Topic = "Deployment"
+	TopicEvaluation    Topic = "Evaluation"
+	TopicAllocation    Topic = "Allocation"
+	TopicJob           Topic = "Job"
+	TopicNode          Topic = "Node"
+	TopicNodePool      Topic = "NodePool"
+	TopicService       Topic = "Service"
+	TopicJobDependency Topic = "JobDependency"
+	TopicExecSession   Topic = "ExecSession"
+	TopicAll           Topic = "*"
 )
 
 // Events is a set of events for a corresponding index. Events returned for the
@@ -120,6 +122,27 @@ func (e *Event) Service() (*ServiceRegistration, error) {
 	return out.Service, nil
 }
 
+// JobDependencyRun returns a JobDependencyRun struct from a given event
+// payload. If the Event Topic is JobDependency this will return a valid
+// JobDependencyRun.
+func (e *Event) JobDependencyRun() (*JobDependencyRun, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Run, nil
+}
+
+// ExecSession returns an ExecSession struct from a given event payload. If the
+// Event Topic is ExecSession this will return a valid ExecSession.
+func (e *Event) ExecSession() (*ExecSession, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Session, nil
+}
+
 type eventPayload struct {
 	Allocation *Allocation          `mapstructure:"Allocation"`
 	Deployment *Deployment          `mapstructure:"Deployment"`
@@ -128,6 +151,8 @@ type eventPayload struct {
 	Node       *Node                `mapstructure:"Node"`
 	NodePool   *NodePool            `mapstructure:"NodePool"`
 	Service    *ServiceRegistration `mapstructure:"Service"`
+	Run        *JobDependencyRun    `mapstructure:"Run"`
+	Session    *ExecSession         `mapstructure:"Session"`
 }
 
 func (e *Event) decodePayload() (*eventPayload, error) {
@@ -37,6 +37,10 @@ func TestTopic_String(t *testing.T) {
 			inputTopic:     TopicJob,
 			expectedOutput: "Job",
 		},
+		{
+			inputTopic:     TopicJobDependency,
+			expectedOutput: "JobDependency",
+		},
 		{
 			inputTopic:     TopicNode,
 			expectedOutput: "Node",
@@ -293,6 +297,23 @@ func TestEventStream_PayloadValueHelpers(t *testing.T) {
 				}, j)
 			},
 		},
+		{
+			desc:  "job_dependency",
+			input: []byte(`{"Topic": "JobDependency", "Payload": {"Run":{"JobID":"some-id","Namespace":"some-namespace-id","Status":"blocked","Upstreams":[{"JobID":"upstream-id","Status":"running"}]}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				must.Eq(t, TopicJobDependency, event.Topic)
+				run, err := event.JobDependencyRun()
+				must.NoError(t, err)
+				must.Eq(t, &JobDependencyRun{
+					JobID:     "some-id",
+					Namespace: "some-namespace-id",
+					Status:    JobDependencyRunStatusBlocked,
+					Upstreams: []*JobDependencyUpstream{
+						{JobID: "upstream-id", Status: JobDependencyStatusRunning},
+					},
+				}, run)
+			},
+		},
 		{
 			desc:  "node",
 			input: []byte(`{"Topic": "Node", "Payload": {"Node":{"ID":"some-id","Datacenter":"some-dc-id"}}}`),
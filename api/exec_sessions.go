@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"time"
+)
+
+// ExecSessions is used to access the exec session endpoints.
+type ExecSessions struct {
+	client *Client
+}
+
+// ExecSessions returns a handle on the exec session endpoints.
+func (c *Client) ExecSessions() *ExecSessions {
+	return &ExecSessions{client: c}
+}
+
+// ExecSession is an exec session into a task, which was recorded because its
+// namespace has exec recording enabled.
+type ExecSession struct {
+	ID            string
+	Namespace     string
+	JobID         string
+	AllocID       string
+	TaskName      string
+	NodeID        string
+	Command       []string
+	Tty           bool
+	AccessorID    string
+	TokenName     string
+	StartedAt     time.Time
+	EndedAt       time.Time
+	ExitCode      int
+	Error         string
+	RecordingSize int64
+	CreateIndex   uint64
+	ModifyIndex   uint64
+}
+
+// Ended returns whether the exec session has ended.
+func (e *ExecSession) Ended() bool {
+	return !e.EndedAt.IsZero()
+}
+
+// List is used to list the exec sessions.
+func (e *ExecSessions) List(q *QueryOptions) ([]*ExecSession, *QueryMeta, error) {
+	var resp []*ExecSession
+	qm, err := e.client.query("/v1/exec-sessions", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// PrefixList is used to list the exec sessions whose ID matches the prefix.
+func (e *ExecSessions) PrefixList(prefix string, q *QueryOptions) ([]*ExecSession, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	q.Prefix = prefix
+	return e.List(q)
+}
+
+// Info is used to fetch the details of an exec session.
+func (e *ExecSessions) Info(id string, q *QueryOptions) (*ExecSession, *QueryMeta, error) {
+	if id == "" {
+		return nil, nil, errors.New("missing exec session ID")
+	}
+
+	var resp ExecSession
+	qm, err := e.client.query("/v1/exec-session/"+url.PathEscape(id), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Recording returns the asciicast v2 recording of an exec session, streamed
+// from the client that ran it. The caller must close the returned reader.
+func (e *ExecSessions) Recording(id string, q *QueryOptions) (io.ReadCloser, error) {
+	if id == "" {
+		return nil, errors.New("missing exec session ID")
+	}
+	return e.client.rawQuery("/v1/exec-session/"+url.PathEscape(id)+"/recording", q)
+}
@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"testing"
+
+	"github.com/open-wander/wander/api/internal/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestExecSessions(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	sessions := c.ExecSessions()
+
+	// Listing without any recorded sessions returns an empty list
+	resp, qm, err := sessions.List(nil)
+	must.NoError(t, err)
+	must.SliceEmpty(t, resp)
+	must.NotNil(t, qm)
+
+	resp, _, err = sessions.PrefixList("abcd", nil)
+	must.NoError(t, err)
+	must.SliceEmpty(t, resp)
+
+	// Unknown sessions are not found
+	_, _, err = sessions.Info("e5ea5d25-ccd2-4d4c-9b3c-1f8d1f0e2f4a", nil)
+	must.ErrorContains(t, err, "exec session not found")
+
+	_, err = sessions.Recording("e5ea5d25-ccd2-4d4c-9b3c-1f8d1f0e2f4a", nil)
+	must.ErrorContains(t, err, "not found")
+
+	_, _, err = sessions.Info("", nil)
+	must.ErrorContains(t, err, "missing exec session ID")
+}
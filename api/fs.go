@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
+	"os"
 	"strconv"
 	"sync"
 	"time"
@@ -137,6 +139,73 @@ func (a *AllocFS) Cat(alloc *Allocation, path string, q *QueryOptions) (io.ReadC
 		})
 }
 
+// Upload writes the content read from r to the file at the given path of an
+// allocation directory, replacing it if it exists. If archive is true, r is a
+// tar archive, optionally gzip compressed, which is extracted into the
+// directory at the given path instead. A zero mode defaults to 0644.
+func (a *AllocFS) Upload(alloc *Allocation, path string, r io.Reader, archive bool, mode os.FileMode, q *QueryOptions) (*QueryMeta, error) {
+	req, err := a.client.newRequest(http.MethodPut, fmt.Sprintf("/v1/client/fs/upload/%s", alloc.ID))
+	if err != nil {
+		return nil, err
+	}
+	req.setQueryOptions(q)
+	req.params.Set("path", path)
+	if archive {
+		req.params.Set("archive", "true")
+	}
+	if mode != 0 {
+		req.params.Set("mode", strconv.FormatUint(uint64(mode.Perm()), 8))
+	}
+	req.body = r
+
+	rtt, resp, err := requireOK(a.client.doRequest(req))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+	return qm, nil
+}
+
+// Remove removes the file or directory at the given path of an allocation
+// directory, including the content of directories.
+func (a *AllocFS) Remove(alloc *Allocation, path string, q *QueryOptions) (*QueryMeta, error) {
+	req, err := a.client.newRequest(http.MethodDelete, fmt.Sprintf("/v1/client/fs/rm/%s", alloc.ID))
+	if err != nil {
+		return nil, err
+	}
+	req.setQueryOptions(q)
+	req.params.Set("path", path)
+
+	rtt, resp, err := requireOK(a.client.doRequest(req))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+	return qm, nil
+}
+
+// Archive returns a gzip compressed tar archive of the file or directory at
+// the given path of an allocation directory. Entries are relative to the
+// directory, or named after the file.
+// Note: for cluster topologies where API consumers don't have network access to
+// Nomad clients, set api.ClientConnTimeout to a small value (ex 1ms) to avoid
+// long pauses on this API call.
+func (a *AllocFS) Archive(alloc *Allocation, path string, q *QueryOptions) (io.ReadCloser, error) {
+	reqPath := fmt.Sprintf("/v1/client/fs/archive/%s", alloc.ID)
+	return queryClientNode(a.client, alloc, reqPath, q,
+		func(q *QueryOptions) {
+			q.Params["path"] = path
+		})
+}
+
 // Stream streams the content of a file blocking on EOF.
 // The parameters are:
 // * path: path to file to stream.
@@ -4,8 +4,11 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"sort"
 	"strconv"
@@ -35,6 +38,12 @@ const (
 	// PeriodicSpecCron is used for a cron spec.
 	PeriodicSpecCron = "cron"
 
+	// PeriodicCatchupNone, PeriodicCatchupLast and PeriodicCatchupAll are
+	// the policies for launches missed while there was no leader.
+	PeriodicCatchupNone = "none"
+	PeriodicCatchupLast = "last"
+	PeriodicCatchupAll  = "all"
+
 	// DefaultNamespace is the default namespace.
 	DefaultNamespace = "default"
 
@@ -432,6 +441,16 @@ func (j *Jobs) PeriodicForce(jobID string, q *WriteOptions) (string, *WriteMeta,
 	return resp.EvalID, wm, nil
 }
 
+// PeriodicHistory is used to list the child launches of a periodic job.
+func (j *Jobs) PeriodicHistory(jobID string, q *QueryOptions) (*PeriodicHistory, *QueryMeta, error) {
+	var resp PeriodicHistory
+	qm, err := j.client.query("/v1/job/"+url.PathEscape(jobID)+"/periodic/history", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // PlanOptions is used to pass through job planning parameters
 type PlanOptions struct {
 	Diff           bool
@@ -477,16 +496,108 @@ func (j *Jobs) Summary(jobID string, q *QueryOptions) (*JobSummary, *QueryMeta,
 	return &resp, qm, nil
 }
 
+// Dependencies is used to read the status of the dependencies of a job.
+func (j *Jobs) Dependencies(jobID string, q *QueryOptions) (*JobDependencyRun, *QueryMeta, error) {
+	var resp JobDependencyRun
+	qm, err := j.client.query("/v1/job/"+url.PathEscape(jobID)+"/dependencies", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Explain is used to explain why the task groups of a job can or can't be
+// placed on the nodes of its node pool. If taskGroup is not empty only that
+// task group is explained.
+func (j *Jobs) Explain(jobID, taskGroup string, q *QueryOptions) (*JobExplainResponse, *QueryMeta, error) {
+	u, err := url.Parse("/v1/job/" + url.PathEscape(jobID) + "/explain")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if taskGroup != "" {
+		v := u.Query()
+		v.Add("task_group", taskGroup)
+		u.RawQuery = v.Encode()
+	}
+
+	var resp JobExplainResponse
+	qm, err := j.client.query(u.String(), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Logs streams the logs of the tasks of the allocations of a job, merged
+// into a single stream of lines. Lines which failed to stream are returned
+// with an Error. The channel is closed when the logs end or ctx is canceled.
+func (j *Jobs) Logs(ctx context.Context, jobID string, opts *JobLogsOptions, q *QueryOptions) (<-chan *JobLogLine, error) {
+	r, err := j.client.newRequest("GET", "/v1/job/"+url.PathEscape(jobID)+"/logs")
+	if err != nil {
+		return nil, err
+	}
+	q = q.WithContext(ctx)
+	if q.Params == nil {
+		q.Params = map[string]string{}
+	}
+	if opts != nil {
+		opts.setParams(q.Params)
+	}
+	r.setQueryOptions(q)
+
+	_, resp, err := requireOK(j.client.doRequest(r))
+	if err != nil {
+		return nil, err
+	}
+
+	linesCh := make(chan *JobLogLine, 10)
+	go func() {
+		defer resp.Body.Close()
+		defer close(linesCh)
+
+		dec := json.NewDecoder(resp.Body)
+		for ctx.Err() == nil {
+			var line JobLogLine
+			if err := dec.Decode(&line); err != nil {
+				if err == io.EOF || ctx.Err() != nil {
+					return
+				}
+				line = JobLogLine{Error: fmt.Sprintf("failed to decode job logs: %v", err)}
+			} else if line.IsHeartbeat() {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case linesCh <- &line:
+			}
+			if line.AllocID == "" && line.Error != "" {
+				return
+			}
+		}
+	}()
+
+	return linesCh, nil
+}
+
 func (j *Jobs) Dispatch(jobID string, meta map[string]string,
 	payload []byte, idPrefixTemplate string, q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
-	var resp JobDispatchResponse
 	req := &JobDispatchRequest{
 		JobID:            jobID,
 		Meta:             meta,
 		Payload:          payload,
 		IdPrefixTemplate: idPrefixTemplate,
 	}
-	wm, err := j.client.put("/v1/job/"+url.PathEscape(jobID)+"/dispatch", req, &resp, q)
+	return j.DispatchOpts(req, q)
+}
+
+// DispatchOpts is used to dispatch a parameterized job with all the options of
+// the request, such as the deadline of the dispatched job.
+func (j *Jobs) DispatchOpts(req *JobDispatchRequest, q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
+	var resp JobDispatchResponse
+	wm, err := j.client.put("/v1/job/"+url.PathEscape(req.JobID)+"/dispatch", req, &resp, q)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -544,6 +655,21 @@ type periodicForceResponse struct {
 	EvalID string
 }
 
+// PeriodicHistory is the launch history of a periodic job.
+type PeriodicHistory struct {
+	Periodic *PeriodicConfig
+	Launch   time.Time
+	Children []*PeriodicChild
+}
+
+// PeriodicChild describes a single child launch of a periodic job. Status is
+// empty if the child has been garbage collected.
+type PeriodicChild struct {
+	JobID  string
+	Launch time.Time
+	Status string
+}
+
 // UpdateStrategy defines a task groups update strategy.
 type UpdateStrategy struct {
 	Stagger          *time.Duration `mapstructure:"stagger" hcl:"stagger,optional"`
@@ -825,8 +951,10 @@ type PeriodicConfig struct {
 	Spec            *string  `hcl:"cron,optional"`
 	Specs           []string `hcl:"crons,optional"`
 	SpecType        *string
-	ProhibitOverlap *bool   `mapstructure:"prohibit_overlap" hcl:"prohibit_overlap,optional"`
-	TimeZone        *string `mapstructure:"time_zone" hcl:"time_zone,optional"`
+	ProhibitOverlap *bool          `mapstructure:"prohibit_overlap" hcl:"prohibit_overlap,optional"`
+	TimeZone        *string        `mapstructure:"time_zone" hcl:"time_zone,optional"`
+	Catchup         *string        `mapstructure:"catchup" hcl:"catchup,optional"`
+	CatchupWindow   *time.Duration `mapstructure:"catchup_window" hcl:"catchup_window,optional"`
 }
 
 func (p *PeriodicConfig) Canonicalize() {
@@ -848,6 +976,12 @@ func (p *PeriodicConfig) Canonicalize() {
 	if p.TimeZone == nil || *p.TimeZone == "" {
 		p.TimeZone = pointerOf("UTC")
 	}
+	if p.Catchup == nil || *p.Catchup == "" {
+		p.Catchup = pointerOf(PeriodicCatchupLast)
+	}
+	if p.CatchupWindow == nil {
+		p.CatchupWindow = pointerOf(time.Duration(0))
+	}
 }
 
 // Next returns the closest time instant matching the spec that is after the
@@ -902,6 +1036,42 @@ func (p *PeriodicConfig) GetLocation() (*time.Location, error) {
 	return time.LoadLocation(*p.TimeZone)
 }
 
+const (
+	JobDependencyRunStatusBlocked   = "blocked"
+	JobDependencyRunStatusTriggered = "triggered"
+	JobDependencyRunStatusFailed    = "failed"
+
+	JobDependencyStatusPending  = "pending"
+	JobDependencyStatusRunning  = "running"
+	JobDependencyStatusComplete = "complete"
+	JobDependencyStatusFailed   = "failed"
+)
+
+// JobDependency declares an upstream job that must complete successfully
+// before the job declaring it is evaluated.
+type JobDependency struct {
+	JobID string `mapstructure:"job" hcl:"job"`
+}
+
+// JobDependencyRun is the status of the dependencies of a job.
+type JobDependencyRun struct {
+	Namespace         string
+	JobID             string
+	JobModifyIndex    uint64
+	Status            string
+	StatusDescription string
+	Upstreams         []*JobDependencyUpstream
+	EvalID            string
+	CreateIndex       uint64
+	ModifyIndex       uint64
+}
+
+// JobDependencyUpstream is the observed status of an upstream job.
+type JobDependencyUpstream struct {
+	JobID  string
+	Status string
+}
+
 // ParameterizedJobConfig is used to configure the parameterized job.
 type ParameterizedJobConfig struct {
 	Payload      string   `hcl:"payload,optional"`
@@ -975,6 +1145,7 @@ type Job struct {
 	Spreads          []*Spread               `hcl:"spread,block"`
 	Periodic         *PeriodicConfig         `hcl:"periodic,block"`
 	ParameterizedJob *ParameterizedJobConfig `hcl:"parameterized,block"`
+	DependsOn        []*JobDependency        `hcl:"depends_on,block"`
 	Reschedule       *ReschedulePolicy       `hcl:"reschedule,block"`
 	Migrate          *MigrateStrategy        `hcl:"migrate,block"`
 	Meta             map[string]string       `hcl:"meta,block"`
@@ -987,6 +1158,7 @@ type Job struct {
 	ParentID                 *string
 	Dispatched               bool
 	DispatchIdempotencyToken *string
+	DispatchDeadline         time.Time
 	Payload                  []byte
 	ConsulNamespace          *string `mapstructure:"consul_namespace"`
 	VaultNamespace           *string `mapstructure:"vault_namespace"`
@@ -1403,6 +1575,125 @@ type JobPlanResponse struct {
 	Warnings string
 }
 
+// JobExplainResponse is used to return the placement explanation of a job.
+type JobExplainResponse struct {
+	TaskGroups map[string]*TaskGroupExplanation
+}
+
+// TaskGroupExplanation is the result of running every candidate node of a
+// job through the placement pipeline for a single task group.
+type TaskGroupExplanation struct {
+	TaskGroup      string
+	NodesEvaluated int
+	NodesAvailable map[string]int
+	NodesFeasible  int
+	Requested      *ExplainResources
+	NodesFailed    map[string]int
+	Nodes          []*NodeExplanation
+}
+
+// NodeExplanation describes the result of each placement check for a single
+// node.
+type NodeExplanation struct {
+	NodeID     string
+	NodeName   string
+	NodePool   string
+	NodeClass  string
+	Datacenter string
+	Feasible   bool
+	Closest    bool
+	Score      float64
+	Available  *ExplainResources
+	Checks     []*NodeExplainCheck
+}
+
+// NodeExplainCheck is the result of a single placement check on a node.
+type NodeExplainCheck struct {
+	Name   string
+	Target string
+	Passed bool
+	Reason string
+}
+
+// ExplainResources is a simplified view of the resources requested by a
+// task group or available on a node.
+type ExplainResources struct {
+	CPU      int64
+	MemoryMB int64
+	DiskMB   int64
+}
+
+// JobLogsOptions are the options of a job logs request.
+type JobLogsOptions struct {
+	// Group and Task restrict the logs to the allocations of a task group
+	// and to a task.
+	Group string
+	Task  string
+
+	// Type is the log type to stream, stdout or stderr. Defaults to stdout.
+	Type string
+
+	// Follow streams new lines until the allocations stop.
+	Follow bool
+
+	// Regex and Contains only return the lines matching the regular
+	// expression and containing the string.
+	Regex    string
+	Contains string
+
+	// Since only returns the logs written after this time, either a
+	// duration before now, such as "1h", or an RFC3339 timestamp. Log lines
+	// aren't timestamped on disk, so the window applies to log files.
+	Since string
+
+	// All includes the logs of terminal allocations.
+	All bool
+}
+
+func (o *JobLogsOptions) setParams(params map[string]string) {
+	for k, v := range map[string]string{
+		"group":    o.Group,
+		"task":     o.Task,
+		"type":     o.Type,
+		"regex":    o.Regex,
+		"contains": o.Contains,
+		"since":    o.Since,
+	} {
+		if v != "" {
+			params[k] = v
+		}
+	}
+	if o.Follow {
+		params["follow"] = "true"
+	}
+	if o.All {
+		params["all"] = "true"
+	}
+}
+
+// JobLogLine is a line of the logs of a task of a job. Time is when the
+// agent serving the request received the line.
+type JobLogLine struct {
+	AllocID   string
+	TaskGroup string
+	Task      string
+	Type      string
+
+	// Time is the time the line was received by the agent while following
+	// the logs. It is not set for the lines logged before the request.
+	Time *time.Time `json:",omitempty"`
+	Line string
+
+	// Error is set if the logs of the task failed to stream.
+	Error string `json:",omitempty"`
+}
+
+// IsHeartbeat returns whether the line is an empty heartbeat sent to keep
+// followed streams alive.
+func (l *JobLogLine) IsHeartbeat() bool {
+	return l.AllocID == "" && l.Error == "" && l.Line == "" && l.Time == nil
+}
+
 type JobDiff struct {
 	Type       string
 	ID         string
@@ -1463,6 +1754,10 @@ type JobDispatchRequest struct {
 	Payload          []byte
 	Meta             map[string]string
 	IdPrefixTemplate string
+
+	// Deadline is the time by which the tasks of the dispatched job must
+	// have completed before they are killed.
+	Deadline time.Time
 }
 
 type JobDispatchResponse struct {
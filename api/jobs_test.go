@@ -4,6 +4,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"testing"
@@ -838,6 +839,8 @@ func TestJobs_Canonicalize(t *testing.T) {
 					SpecType:        pointerOf(PeriodicSpecCron),
 					ProhibitOverlap: pointerOf(false),
 					TimeZone:        pointerOf("UTC"),
+					Catchup:         pointerOf(PeriodicCatchupLast),
+					CatchupWindow:   pointerOf(time.Duration(0)),
 				},
 			},
 		},
@@ -2128,6 +2131,88 @@ func TestJobs_JobSummary(t *testing.T) {
 	must.True(t, ok)
 }
 
+func TestJobs_Explain(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	jobs := c.Jobs()
+
+	// Explaining a job that doesn't exist returns an error
+	_, _, err := jobs.Explain("job1", "", nil)
+	must.ErrorContains(t, err, "not found")
+
+	// Register the job
+	job := testJob()
+	taskGroup := *job.TaskGroups[0].Name
+	_, wm, err := jobs.Register(job, nil)
+	must.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	result, qm, err := jobs.Explain("job1", taskGroup, nil)
+	must.NoError(t, err)
+	assertQueryMeta(t, qm)
+	must.MapContainsKey(t, result.TaskGroups, taskGroup)
+	must.Eq(t, taskGroup, result.TaskGroups[taskGroup].TaskGroup)
+
+	// Explaining an unknown task group returns an error
+	_, _, err = jobs.Explain("job1", "nope", nil)
+	must.ErrorContains(t, err, "not found")
+}
+
+func TestJobs_Logs(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	jobs := c.Jobs()
+
+	job := testJob()
+	_, wm, err := jobs.Register(job, nil)
+	must.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	// Invalid options are rejected
+	_, err = jobs.Logs(context.Background(), *job.ID, &JobLogsOptions{Regex: "("}, nil)
+	must.ErrorContains(t, err, "invalid regex")
+
+	// The job has no running tasks to stream the logs of
+	_, err = jobs.Logs(context.Background(), *job.ID, &JobLogsOptions{Type: "stderr"}, nil)
+	must.ErrorContains(t, err, "no task logs found")
+}
+
+func TestJobs_JobLogsOptions(t *testing.T) {
+	testutil.Parallel(t)
+
+	params := map[string]string{}
+	(&JobLogsOptions{}).setParams(params)
+	must.MapEmpty(t, params)
+
+	(&JobLogsOptions{
+		Group:    "cache",
+		Task:     "redis",
+		Type:     "stderr",
+		Follow:   true,
+		Regex:    "^WARN",
+		Contains: "timeout",
+		Since:    "1h",
+		All:      true,
+	}).setParams(params)
+	must.Eq(t, map[string]string{
+		"group":    "cache",
+		"task":     "redis",
+		"type":     "stderr",
+		"follow":   "true",
+		"regex":    "^WARN",
+		"contains": "timeout",
+		"since":    "1h",
+		"all":      "true",
+	}, params)
+
+	must.True(t, (&JobLogLine{}).IsHeartbeat())
+	must.False(t, (&JobLogLine{Line: "x"}).IsHeartbeat())
+}
+
 func TestJobs_NewBatchJob(t *testing.T) {
 	testutil.Parallel(t)
 
@@ -73,8 +73,9 @@ type Namespace struct {
 	Name                  string
 	Description           string
 	Quota                 string
-	Capabilities          *NamespaceCapabilities          `hcl:"capabilities,block"`
-	NodePoolConfiguration *NamespaceNodePoolConfiguration `hcl:"node_pool_config,block"`
+	Capabilities          *NamespaceCapabilities               `hcl:"capabilities,block"`
+	NodePoolConfiguration *NamespaceNodePoolConfiguration      `hcl:"node_pool_config,block"`
+	ExecRecording         *NamespaceExecRecordingConfiguration `hcl:"exec_recording,block"`
 	Meta                  map[string]string
 	CreateIndex           uint64
 	ModifyIndex           uint64
@@ -87,6 +88,12 @@ type NamespaceCapabilities struct {
 	DisabledTaskDrivers []string `hcl:"disabled_task_drivers"`
 }
 
+// NamespaceExecRecordingConfiguration stores configuration about the
+// recording of exec sessions into the tasks of a namespace.
+type NamespaceExecRecordingConfiguration struct {
+	Enabled bool
+}
+
 // NamespaceNodePoolConfiguration stores configuration about node pools for a
 // namespace.
 type NamespaceNodePoolConfiguration struct {
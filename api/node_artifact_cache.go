@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"net/url"
+)
+
+// ArtifactCacheEntry is an artifact in the artifact cache of a Node.
+type ArtifactCacheEntry struct {
+	Key         string
+	Source      string
+	Mode        string
+	Size        int64
+	Hits        uint64
+	CreateTime  int64
+	LastUseTime int64
+}
+
+// ArtifactCacheListResponse contains the artifacts and the statistics of the
+// artifact cache of a Node.
+type ArtifactCacheListResponse struct {
+	Enabled bool
+	MaxSize int64
+	Size    int64
+	Hits    uint64
+	Misses  uint64
+	Entries []*ArtifactCacheEntry
+}
+
+// ArtifactCachePurgeResponse contains the artifacts removed from the artifact
+// cache of a Node.
+type ArtifactCachePurgeResponse struct {
+	Purged []string
+	Size   int64
+}
+
+// NodeArtifactCache is a client for the artifact cache of Nodes.
+type NodeArtifactCache struct {
+	client *Client
+}
+
+// ArtifactCache returns a NodeArtifactCache client.
+func (n *Nodes) ArtifactCache() *NodeArtifactCache {
+	return &NodeArtifactCache{client: n.client}
+}
+
+// List the artifacts cached by a Node. If nodeID is empty then the cache of
+// the Node receiving the request is listed.
+func (n *NodeArtifactCache) List(nodeID string, qo *QueryOptions) (*ArtifactCacheListResponse, error) {
+	if qo == nil {
+		qo = &QueryOptions{}
+	}
+
+	if qo.Params == nil {
+		qo.Params = make(map[string]string)
+	}
+
+	if nodeID != "" {
+		qo.Params["node_id"] = nodeID
+	}
+
+	var out ArtifactCacheListResponse
+	_, err := n.client.query("/v1/client/artifact-cache", &out, qo)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Purge removes the artifact of the key from the cache of a Node, or all the
+// artifacts not in use if key is empty. If nodeID is empty then the cache of
+// the Node receiving the request is purged.
+func (n *NodeArtifactCache) Purge(nodeID, key string, wo *WriteOptions) (*ArtifactCachePurgeResponse, error) {
+	params := url.Values{}
+	if nodeID != "" {
+		params.Set("node_id", nodeID)
+	}
+	if key != "" {
+		params.Set("key", key)
+	}
+
+	endpoint := "/v1/client/artifact-cache"
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	var out ArtifactCachePurgeResponse
+	_, err := n.client.delete(endpoint, nil, &out, wo)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
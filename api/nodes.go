@@ -568,6 +568,7 @@ type Node struct {
 	CSIControllerPlugins  map[string]*CSIInfo
 	CSINodePlugins        map[string]*CSIInfo
 	LastDrain             *DrainMetadata
+	Conditions            []*NodeCondition
 	CreateIndex           uint64
 	ModifyIndex           uint64
 }
@@ -767,8 +768,23 @@ const (
 	NodeEventSubsystemDriver    = "Driver"
 	NodeEventSubsystemHeartbeat = "Heartbeat"
 	NodeEventSubsystemCluster   = "Cluster"
+	NodeEventSubsystemEviction  = "Eviction"
 )
 
+const (
+	NodeConditionMemoryPressure = "MemoryPressure"
+	NodeConditionDiskPressure   = "DiskPressure"
+	NodeConditionInodePressure  = "InodePressure"
+)
+
+// NodeCondition is a resource pressure condition reported by a client, such
+// as it running low on memory.
+type NodeCondition struct {
+	Type    string
+	Message string
+	Since   time.Time
+}
+
 // NodeEvent is a single unit representing a node’s state change
 type NodeEvent struct {
 	Message     string
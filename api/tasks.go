@@ -93,6 +93,10 @@ type RestartPolicy struct {
 	Delay           *time.Duration `hcl:"delay,optional"`
 	Mode            *string        `hcl:"mode,optional"`
 	RenderTemplates *bool          `mapstructure:"render_templates" hcl:"render_templates,optional"`
+
+	// RestartOnTimeout restarts tasks killed for exceeding their max run
+	// duration according to the policy.
+	RestartOnTimeout *bool `mapstructure:"restart_on_timeout" hcl:"restart_on_timeout,optional"`
 }
 
 func (r *RestartPolicy) Merge(rp *RestartPolicy) {
@@ -111,6 +115,9 @@ func (r *RestartPolicy) Merge(rp *RestartPolicy) {
 	if rp.RenderTemplates != nil {
 		r.RenderTemplates = rp.RenderTemplates
 	}
+	if rp.RestartOnTimeout != nil {
+		r.RestartOnTimeout = rp.RestartOnTimeout
+	}
 }
 
 // Reschedule configures how Tasks are rescheduled  when they crash or fail.
@@ -134,6 +141,10 @@ type ReschedulePolicy struct {
 
 	// Unlimited allows rescheduling attempts until they succeed
 	Unlimited *bool `mapstructure:"unlimited" hcl:"unlimited,optional"`
+
+	// RescheduleOnTimeout reschedules allocations whose tasks were killed for
+	// exceeding their max run duration according to the policy.
+	RescheduleOnTimeout *bool `mapstructure:"reschedule_on_timeout" hcl:"reschedule_on_timeout,optional"`
 }
 
 func (r *ReschedulePolicy) Merge(rp *ReschedulePolicy) {
@@ -158,6 +169,9 @@ func (r *ReschedulePolicy) Merge(rp *ReschedulePolicy) {
 	if rp.Unlimited != nil {
 		r.Unlimited = rp.Unlimited
 	}
+	if rp.RescheduleOnTimeout != nil {
+		r.RescheduleOnTimeout = rp.RescheduleOnTimeout
+	}
 }
 
 func (r *ReschedulePolicy) Canonicalize(jobType string) {
@@ -653,6 +667,26 @@ type LogConfig struct {
 	Enabled *bool `mapstructure:"enabled" hcl:"enabled,optional"`
 
 	Disabled *bool `mapstructure:"disabled" hcl:"disabled,optional"`
+
+	Sinks []*LogSink `mapstructure:"sink" hcl:"sink,block"`
+}
+
+// LogSink configures a destination the task logs are shipped to, in addition
+// to the rotated log files.
+type LogSink struct {
+	Type       string            `hcl:"type,label"`
+	Address    string            `mapstructure:"address" hcl:"address,optional"`
+	Path       string            `mapstructure:"path" hcl:"path,optional"`
+	Facility   string            `mapstructure:"facility" hcl:"facility,optional"`
+	Tag        string            `mapstructure:"tag" hcl:"tag,optional"`
+	Headers    map[string]string `mapstructure:"headers" hcl:"headers,optional"`
+	BufferSize *int              `mapstructure:"buffer_size" hcl:"buffer_size,optional"`
+}
+
+func (s *LogSink) Canonicalize() {
+	if s.BufferSize == nil {
+		s.BufferSize = pointerOf(1024)
+	}
 }
 
 func DefaultLogConfig() *LogConfig {
@@ -673,6 +707,9 @@ func (l *LogConfig) Canonicalize() {
 	if l.Disabled == nil {
 		l.Disabled = pointerOf(false)
 	}
+	for _, s := range l.Sinks {
+		s.Canonicalize()
+	}
 }
 
 // DispatchPayloadConfig configures how a task gets its input from a job dispatch
@@ -696,12 +733,32 @@ func (l *TaskLifecycle) Empty() bool {
 	return l == nil || (l.Hook == "")
 }
 
+const (
+	TaskDependencyConditionStarted  = "started"
+	TaskDependencyConditionHealthy  = "healthy"
+	TaskDependencyConditionComplete = "complete"
+)
+
+// TaskDependency declares a sibling task that must meet a condition before
+// the task declaring it is allowed to start.
+type TaskDependency struct {
+	Task      string `mapstructure:"task" hcl:"task"`
+	Condition string `mapstructure:"condition" hcl:"condition,optional"`
+}
+
+func (d *TaskDependency) Canonicalize() {
+	if d.Condition == "" {
+		d.Condition = TaskDependencyConditionStarted
+	}
+}
+
 // Task is a single process in a task group.
 type Task struct {
 	Name            string                 `hcl:"name,label"`
 	Driver          string                 `hcl:"driver,optional"`
 	User            string                 `hcl:"user,optional"`
 	Lifecycle       *TaskLifecycle         `hcl:"lifecycle,block"`
+	DependsOn       []*TaskDependency      `mapstructure:"depends_on" hcl:"depends_on,block"`
 	Config          map[string]interface{} `hcl:"config,block"`
 	Constraints     []*Constraint          `hcl:"constraint,block"`
 	Affinities      []*Affinity            `hcl:"affinity,block"`
@@ -721,9 +778,14 @@ type Task struct {
 	Leader          bool                   `hcl:"leader,optional"`
 	ShutdownDelay   time.Duration          `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
 	KillSignal      string                 `mapstructure:"kill_signal" hcl:"kill_signal,optional"`
+	MaxRunDuration  time.Duration          `mapstructure:"max_run_duration" hcl:"max_run_duration,optional"`
 	Kind            string                 `hcl:"kind,optional"`
 	ScalingPolicies []*ScalingPolicy       `hcl:"scaling,block"`
 	Identity        *WorkloadIdentity      `hcl:"identity,block"`
+
+	// Identities are the additional named workload identities. In HCL they
+	// are declared as identity blocks with a name.
+	Identities []*WorkloadIdentity
 }
 
 func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
@@ -761,6 +823,9 @@ func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
 	if t.Lifecycle.Empty() {
 		t.Lifecycle = nil
 	}
+	for _, dep := range t.DependsOn {
+		dep.Canonicalize()
+	}
 	if t.CSIPluginConfig != nil {
 		t.CSIPluginConfig.Canonicalize()
 	}
@@ -776,11 +841,37 @@ func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
 
 // TaskArtifact is used to download artifacts before running a task.
 type TaskArtifact struct {
-	GetterSource  *string           `mapstructure:"source" hcl:"source,optional"`
-	GetterOptions map[string]string `mapstructure:"options" hcl:"options,block"`
-	GetterHeaders map[string]string `mapstructure:"headers" hcl:"headers,block"`
-	GetterMode    *string           `mapstructure:"mode" hcl:"mode,optional"`
-	RelativeDest  *string           `mapstructure:"destination" hcl:"destination,optional"`
+	GetterSource  *string            `mapstructure:"source" hcl:"source,optional"`
+	GetterOptions map[string]string  `mapstructure:"options" hcl:"options,block"`
+	GetterHeaders map[string]string  `mapstructure:"headers" hcl:"headers,block"`
+	GetterMode    *string            `mapstructure:"mode" hcl:"mode,optional"`
+	RelativeDest  *string            `mapstructure:"destination" hcl:"destination,optional"`
+	Signature     *ArtifactSignature `mapstructure:"signature" hcl:"signature,block"`
+	ReadOnly      *bool              `mapstructure:"read_only" hcl:"read_only,optional"`
+}
+
+// ArtifactSignature is a detached signature an artifact is verified against
+// before it is unpacked into the task directory.
+type ArtifactSignature struct {
+	Source   *string  `mapstructure:"source" hcl:"source,optional"`
+	Format   *string  `mapstructure:"format" hcl:"format,optional"`
+	Keys     []string `mapstructure:"keys" hcl:"keys,optional"`
+	Variable *string  `mapstructure:"variable" hcl:"variable,optional"`
+}
+
+func (s *ArtifactSignature) Canonicalize() {
+	if s.Source == nil {
+		s.Source = pointerOf("")
+	}
+	if s.Format == nil {
+		s.Format = pointerOf("minisign")
+	}
+	if len(s.Keys) == 0 {
+		s.Keys = nil
+	}
+	if s.Variable == nil {
+		s.Variable = pointerOf("")
+	}
 }
 
 func (a *TaskArtifact) Canonicalize() {
@@ -810,6 +901,12 @@ func (a *TaskArtifact) Canonicalize() {
 			a.RelativeDest = pointerOf("local/")
 		}
 	}
+	if a.Signature != nil {
+		a.Signature.Canonicalize()
+	}
+	if a.ReadOnly == nil {
+		a.ReadOnly = pointerOf(false)
+	}
 }
 
 // WaitConfig is the Min/Max duration to wait for the Consul cluster to reach a
@@ -928,6 +1025,7 @@ type Vault struct {
 	DisableFile  *bool    `mapstructure:"disable_file" hcl:"disable_file,optional"`
 	ChangeMode   *string  `mapstructure:"change_mode" hcl:"change_mode,optional"`
 	ChangeSignal *string  `mapstructure:"change_signal" hcl:"change_signal,optional"`
+	Role         string   `mapstructure:"role" hcl:"role,optional"`
 }
 
 func (v *Vault) Canonicalize() {
@@ -1014,6 +1112,7 @@ type TaskState struct {
 	LastRestart time.Time
 	StartedAt   time.Time
 	FinishedAt  time.Time
+	TimedOut    bool
 	Events      []*TaskEvent
 
 	// Experimental -  TaskHandle is based on drivers.TaskHandle and used
@@ -1029,26 +1128,31 @@ type TaskHandle struct {
 }
 
 const (
-	TaskSetup                  = "Task Setup"
-	TaskSetupFailure           = "Setup Failure"
-	TaskDriverFailure          = "Driver Failure"
-	TaskDriverMessage          = "Driver"
-	TaskReceived               = "Received"
-	TaskFailedValidation       = "Failed Validation"
-	TaskStarted                = "Started"
-	TaskTerminated             = "Terminated"
-	TaskKilling                = "Killing"
-	TaskKilled                 = "Killed"
-	TaskRestarting             = "Restarting"
-	TaskNotRestarting          = "Not Restarting"
-	TaskDownloadingArtifacts   = "Downloading Artifacts"
-	TaskArtifactDownloadFailed = "Failed Artifact Download"
-	TaskSiblingFailed          = "Sibling Task Failed"
-	TaskSignaling              = "Signaling"
-	TaskRestartSignal          = "Restart Signaled"
-	TaskLeaderDead             = "Leader Task Dead"
-	TaskBuildingTaskDir        = "Building Task Directory"
-	TaskClientReconnected      = "Reconnected"
+	TaskSetup                      = "Task Setup"
+	TaskSetupFailure               = "Setup Failure"
+	TaskDriverFailure              = "Driver Failure"
+	TaskDriverMessage              = "Driver"
+	TaskReceived                   = "Received"
+	TaskFailedValidation           = "Failed Validation"
+	TaskStarted                    = "Started"
+	TaskTerminated                 = "Terminated"
+	TaskKilling                    = "Killing"
+	TaskKilled                     = "Killed"
+	TaskRestarting                 = "Restarting"
+	TaskNotRestarting              = "Not Restarting"
+	TaskDownloadingArtifacts       = "Downloading Artifacts"
+	TaskArtifactDownloadFailed     = "Failed Artifact Download"
+	TaskArtifactVerificationFailed = "Failed Artifact Verification"
+	TaskSiblingFailed              = "Sibling Task Failed"
+	TaskSignaling                  = "Signaling"
+	TaskRestartSignal              = "Restart Signaled"
+	TaskLeaderDead                 = "Leader Task Dead"
+	TaskDependencyFailed           = "Task Dependency Failed"
+	TaskBuildingTaskDir            = "Building Task Directory"
+	TaskMigratingDisk              = "Migrating Disk"
+	TaskClientReconnected          = "Reconnected"
+	TaskRunDurationExceeded        = "Run Duration Exceeded"
+	TaskDeadlineExceeded           = "Deadline Exceeded"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -1145,6 +1249,9 @@ func (t *TaskCSIPluginConfig) Canonicalize() {
 // WorkloadIdentity is the jobspec block which determines if and how a workload
 // identity is exposed to tasks.
 type WorkloadIdentity struct {
-	Env  bool `hcl:"env,optional"`
-	File bool `hcl:"file,optional"`
+	Name     string        `hcl:"name,optional"`
+	Audience []string      `mapstructure:"aud" hcl:"aud,optional"`
+	Env      bool          `hcl:"env,optional"`
+	File     bool          `hcl:"file,optional"`
+	TTL      time.Duration `mapstructure:"ttl" hcl:"ttl,optional"`
 }
@@ -362,6 +362,28 @@ func TestTask_Canonicalize_TaskLifecycle(t *testing.T) {
 	}
 }
 
+func TestTask_Canonicalize_DependsOn(t *testing.T) {
+	testutil.Parallel(t)
+
+	task := &Task{
+		DependsOn: []*TaskDependency{
+			{Task: "db"},
+			{Task: "migrate", Condition: TaskDependencyConditionComplete},
+		},
+	}
+	tg := &TaskGroup{
+		Name: pointerOf("foo"),
+	}
+	j := &Job{
+		ID: pointerOf("test"),
+	}
+	task.Canonicalize(tg, j)
+	must.Eq(t, []*TaskDependency{
+		{Task: "db", Condition: TaskDependencyConditionStarted},
+		{Task: "migrate", Condition: TaskDependencyConditionComplete},
+	}, task.DependsOn)
+}
+
 func TestTask_Template_WaitConfig_Canonicalize_and_Copy(t *testing.T) {
 	testutil.Parallel(t)
 
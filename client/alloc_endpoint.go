@@ -9,6 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/armon/go-metrics"
@@ -21,6 +23,16 @@ import (
 	"github.com/open-wander/wander/plugins/drivers"
 )
 
+const (
+	// portForwardDialTimeout is the timeout to connect to the port of an
+	// allocation.
+	portForwardDialTimeout = 10 * time.Second
+
+	// portForwardFrameSize is the maximum size of the payload of the frames
+	// sent by port forwarding sessions.
+	portForwardFrameSize = 32 * 1024
+)
+
 // Allocations endpoint is used for interacting with client allocations
 type Allocations struct {
 	c *Client
@@ -29,6 +41,7 @@ type Allocations struct {
 func NewAllocationsEndpoint(c *Client) *Allocations {
 	a := &Allocations{c: c}
 	a.c.streamingRpcs.Register("Allocations.Exec", a.exec)
+	a.c.streamingRpcs.Register("Allocations.PortForward", a.portForward)
 	return a
 }
 
@@ -59,7 +72,7 @@ func (a *Allocations) GarbageCollect(args *nstructs.AllocSpecificRequest, reply
 	// Check namespace submit job permission.
 	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilitySubmitJob) {
 		return nstructs.ErrPermissionDenied
 	}
 
@@ -82,7 +95,7 @@ func (a *Allocations) Signal(args *nstructs.AllocSignalRequest, reply *nstructs.
 	// Check namespace alloc-lifecycle permission.
 	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocLifecycle) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityAllocLifecycle) {
 		return nstructs.ErrPermissionDenied
 	}
 
@@ -101,7 +114,7 @@ func (a *Allocations) Restart(args *nstructs.AllocRestartRequest, reply *nstruct
 	// Check namespace alloc-lifecycle permission.
 	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocLifecycle) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityAllocLifecycle) {
 		return nstructs.ErrPermissionDenied
 	}
 
@@ -120,7 +133,7 @@ func (a *Allocations) Stats(args *cstructs.AllocStatsRequest, reply *cstructs.Al
 	// Check read-job permission.
 	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadJob) {
 		return nstructs.ErrPermissionDenied
 	}
 
@@ -152,7 +165,7 @@ func (a *Allocations) Checks(args *cstructs.AllocChecksRequest, reply *cstructs.
 	// Check read-job permission
 	if aclObj, aclErr := a.c.ResolveToken(args.AuthToken); aclErr != nil {
 		return aclErr
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadJob) {
 		return nstructs.ErrPermissionDenied
 	}
 
@@ -239,7 +252,7 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 	// Check alloc-exec permission.
 	if err != nil {
 		return nil, err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocExec) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityAllocExec) {
 		return nil, nstructs.ErrPermissionDenied
 	}
 
@@ -297,7 +310,21 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 		return pointer.Of(int64(404)), fmt.Errorf("task %q is not running.", req.Task)
 	}
 
-	err = h(ctx, req.Cmd, req.Tty, newExecStream(decoder, encoder))
+	// Register the session with the servers, recording it if the namespace
+	// requires it
+	var stream drivers.ExecTaskStream = newExecStream(decoder, encoder)
+	session, recorder, err := a.c.startExecSession(execID, alloc, req.Task, req.Cmd, req.Tty, ident, stream)
+	if err != nil {
+		return pointer.Of(int64(500)), err
+	}
+	if recorder != nil {
+		stream = recorder
+	}
+
+	err = h(ctx, req.Cmd, req.Tty, stream)
+	if session != nil {
+		a.c.endExecSession(session, recorder, err)
+	}
 	if err != nil {
 		code := pointer.Of(int64(500))
 		return code, err
@@ -306,6 +333,168 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 	return nil, nil
 }
 
+// portForward is used to forward a TCP connection to a port of a running
+// allocation.
+func (a *Allocations) portForward(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "allocations", "port_forward"}, time.Now())
+	defer conn.Close()
+
+	decoder := codec.NewDecoder(conn, nstructs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, nstructs.MsgpackHandle)
+
+	code, err := a.portForwardImpl(encoder, decoder)
+	if err != nil {
+		a.c.logger.Info("port forward session ended with an error", "error", err, "code", code)
+		handleStreamResultError(err, code, encoder)
+	}
+}
+
+func (a *Allocations) portForwardImpl(encoder *codec.Encoder, decoder *codec.Decoder) (*int64, error) {
+
+	// Decode the arguments
+	var req cstructs.AllocPortForwardRequest
+	if err := decoder.Decode(&req); err != nil {
+		return pointer.Of(int64(500)), err
+	}
+
+	if req.AllocID == "" {
+		return pointer.Of(int64(400)), allocIDNotPresentErr
+	}
+	ar, err := a.c.getAllocRunner(req.AllocID)
+	if err != nil {
+		code := pointer.Of(int64(500))
+		if nstructs.IsErrUnknownAllocation(err) {
+			code = pointer.Of(int64(404))
+		}
+
+		return code, err
+	}
+	alloc := ar.Alloc()
+
+	// Check alloc-port-forward permission.
+	if aclObj, err := a.c.ResolveToken(req.QueryOptions.AuthToken); err != nil {
+		return nil, err
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityAllocPortForward) {
+		return nil, nstructs.ErrPermissionDenied
+	}
+
+	if status := ar.AllocState().ClientStatus; status != nstructs.AllocClientStatusRunning {
+		return pointer.Of(int64(400)), fmt.Errorf("allocation is not running: client status is %q", status)
+	}
+
+	addr, netns, err := portForwardTarget(alloc, ar.NetworkIsolation(), req.Port)
+	if err != nil {
+		return pointer.Of(int64(400)), err
+	}
+
+	target, err := dialAllocPort(netns, addr)
+	if err != nil {
+		return pointer.Of(int64(502)), fmt.Errorf("failed to connect to port %s: %v", req.Port, err)
+	}
+	defer target.Close()
+
+	a.c.logger.Debug("port forward session starting", "alloc_id", alloc.ID, "port", req.Port, "address", addr)
+
+	errCh := make(chan error, 2)
+
+	// Copy the data sent by the allocation to the stream
+	go func() {
+		buf := make([]byte, portForwardFrameSize)
+		for {
+			n, err := target.Read(buf)
+			if n > 0 {
+				if err := encoder.Encode(&cstructs.StreamErrWrapper{Payload: buf[:n]}); err != nil {
+					errCh <- nil
+					return
+				}
+			}
+			if err == io.EOF {
+				errCh <- nil
+				return
+			} else if err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+
+	// Copy the data sent to the stream to the allocation
+	go func() {
+		for {
+			var frame cstructs.StreamErrWrapper
+			if err := decoder.Decode(&frame); err != nil {
+				errCh <- nil
+				return
+			}
+			if _, err := target.Write(frame.Payload); err != nil {
+				// The allocation closed the connection, which is
+				// reported by the other goroutine.
+				errCh <- nil
+				return
+			}
+		}
+	}()
+
+	if err := <-errCh; err != nil {
+		return pointer.Of(int64(502)), err
+	}
+	return nil, nil
+}
+
+// portForwardTarget returns the address to dial to forward a connection to a
+// port of an allocation, a port label or number, and the path of the network
+// namespace to dial it from. Allocations with their own network namespace can
+// be forwarded to any port listening on the loopback interface of the
+// namespace. Allocations using the host network can only be forwarded to
+// their allocated ports.
+func portForwardTarget(alloc *nstructs.Allocation, spec *drivers.NetworkIsolationSpec, port string) (string, string, error) {
+	if port == "" {
+		return "", "", errors.New("port is not present")
+	}
+
+	var ports nstructs.AllocatedPorts
+	if alloc.AllocatedResources != nil {
+		ports = alloc.AllocatedResources.Shared.Ports
+	}
+	mapping, found := ports.Get(port)
+	number, numErr := strconv.Atoi(port)
+
+	if spec != nil && spec.Mode == drivers.NetIsolationModeGroup && spec.Path != "" {
+		switch {
+		case found && mapping.To > 0:
+			number = mapping.To
+		case found:
+			number = mapping.Value
+		case numErr != nil:
+			return "", "", fmt.Errorf("unknown port label %q", port)
+		case number < 1 || number > 65535:
+			return "", "", fmt.Errorf("invalid port number %d", number)
+		}
+		return net.JoinHostPort("127.0.0.1", strconv.Itoa(number)), spec.Path, nil
+	}
+
+	if !found && numErr == nil {
+		for _, p := range ports {
+			if p.Value == number {
+				mapping, found = p, true
+				break
+			}
+		}
+	}
+	if !found {
+		return "", "", fmt.Errorf("port %q is not allocated to the allocation", port)
+	}
+
+	host := mapping.HostIP
+	switch host {
+	case "", "0.0.0.0":
+		host = "127.0.0.1"
+	case "::":
+		host = "::1"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(mapping.Value)), "", nil
+}
+
 // newExecStream returns a new exec stream as expected by drivers that interpolate with RPC streaming format
 func newExecStream(decoder *codec.Decoder, encoder *codec.Encoder) drivers.ExecTaskStream {
 	buf := new(bytes.Buffer)
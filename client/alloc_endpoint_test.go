@@ -1442,3 +1442,201 @@ func decodeFrames(t *testing.T, p1 net.Conn, frames chan<- *drivers.ExecTaskStre
 		frames <- &frame
 	}
 }
+
+func TestAlloc_PortForward(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a listener for the allocation to expose
+	l, err := net.Listen("tcp", "0.0.0.0:0")
+	must.NoError(t, err)
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	// Start a server and client
+	s, cleanupS := nomad.TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Networks = []*nstructs.NetworkResource{{
+		ReservedPorts: []nstructs.Port{{Label: "echo", Value: port}},
+	}}
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+	alloc := testutil.WaitForRunning(t, s.RPC, job)[0]
+
+	handler, err := client.StreamingRpcHandler("Allocations.PortForward")
+	must.NoError(t, err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+	go handler(p2)
+
+	encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+	decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
+	must.NoError(t, encoder.Encode(&cstructs.AllocPortForwardRequest{
+		AllocID: alloc.ID,
+		Port:    "echo",
+		QueryOptions: nstructs.QueryOptions{
+			Region:    "global",
+			Namespace: nstructs.DefaultNamespace,
+		},
+	}))
+	must.NoError(t, encoder.Encode(&cstructs.StreamErrWrapper{Payload: []byte("ping")}))
+
+	var received []byte
+	for len(received) < 4 {
+		var frame cstructs.StreamErrWrapper
+		must.NoError(t, decoder.Decode(&frame))
+		must.Nil(t, frame.Error)
+		received = append(received, frame.Payload...)
+	}
+	must.Eq(t, "ping", string(received))
+}
+
+func TestAlloc_PortForward_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server and client
+	s, root, cleanupS := nomad.TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanupC := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	policyBad := mock.NamespacePolicy(nstructs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocExec, acl.NamespaceCapabilityReadJob})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(nstructs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocPortForward})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid", policyGood)
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+	alloc := testutil.WaitForRunningWithToken(t, s.RPC, job, root.SecretID)[0]
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: nstructs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			ExpectedError: "is not allocated",
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: "is not allocated",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			handler, err := client.StreamingRpcHandler("Allocations.PortForward")
+			must.NoError(t, err)
+
+			p1, p2 := net.Pipe()
+			defer p1.Close()
+			defer p2.Close()
+			go handler(p2)
+
+			encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+			must.NoError(t, encoder.Encode(&cstructs.AllocPortForwardRequest{
+				AllocID: alloc.ID,
+				Port:    "8080",
+				QueryOptions: nstructs.QueryOptions{
+					Region:    "global",
+					AuthToken: c.Token,
+					Namespace: nstructs.DefaultNamespace,
+				},
+			}))
+
+			var frame cstructs.StreamErrWrapper
+			decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
+			must.NoError(t, decoder.Decode(&frame))
+			must.NotNil(t, frame.Error)
+			must.StrContains(t, frame.Error.Error(), c.ExpectedError)
+		})
+	}
+}
+
+func TestAlloc_PortForwardTarget(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	alloc.AllocatedResources.Shared.Ports = nstructs.AllocatedPorts{
+		{Label: "http", Value: 25000, To: 8080, HostIP: "10.0.0.5"},
+		{Label: "admin", Value: 25001, HostIP: "0.0.0.0"},
+		{Label: "v6", Value: 25002, HostIP: "::"},
+	}
+	bridge := &drivers.NetworkIsolationSpec{
+		Mode: drivers.NetIsolationModeGroup,
+		Path: "/var/run/netns/example",
+	}
+
+	cases := []struct {
+		name  string
+		spec  *drivers.NetworkIsolationSpec
+		port  string
+		addr  string
+		netns string
+		err   string
+	}{
+		{name: "host label", port: "http", addr: "10.0.0.5:25000"},
+		{name: "host number", port: "25000", addr: "10.0.0.5:25000"},
+		{name: "host any address", port: "admin", addr: "127.0.0.1:25001"},
+		{name: "host any ipv6 address", port: "v6", addr: "[::1]:25002"},
+		{name: "host unallocated", port: "8080", err: "is not allocated"},
+		{name: "host unknown label", port: "grpc", err: "is not allocated"},
+		{name: "bridge label with to", spec: bridge, port: "http", addr: "127.0.0.1:8080", netns: bridge.Path},
+		{name: "bridge label", spec: bridge, port: "admin", addr: "127.0.0.1:25001", netns: bridge.Path},
+		{name: "bridge any port", spec: bridge, port: "9090", addr: "127.0.0.1:9090", netns: bridge.Path},
+		{name: "bridge unknown label", spec: bridge, port: "grpc", err: "unknown port label"},
+		{name: "bridge invalid port", spec: bridge, port: "70000", err: "invalid port number"},
+		{name: "missing port", err: "port is not present"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			addr, netns, err := portForwardTarget(alloc, tc.spec, tc.port)
+			if tc.err != "" {
+				must.ErrorContains(t, err, tc.err)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.addr, addr)
+			must.Eq(t, tc.netns, netns)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build linux
+
+package client
+
+import (
+	"net"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+)
+
+// dialAllocPort connects to the TCP address of an allocation. If netns is set
+// the connection is made from the network namespace at this path.
+func dialAllocPort(netns, addr string) (net.Conn, error) {
+	if netns == "" {
+		return net.DialTimeout("tcp", addr, portForwardDialTimeout)
+	}
+
+	netNS, err := ns.GetNS(netns)
+	if err != nil {
+		return nil, err
+	}
+	defer netNS.Close()
+
+	// The socket belongs to the network namespace of the thread it is
+	// created on, so it keeps using the namespace after Do returns.
+	var conn net.Conn
+	err = netNS.Do(func(ns.NetNS) error {
+		var err error
+		conn, err = net.DialTimeout("tcp", addr, portForwardDialTimeout)
+		return err
+	})
+	return conn, err
+}
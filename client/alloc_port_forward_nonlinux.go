@@ -0,0 +1,20 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !linux
+
+package client
+
+import (
+	"errors"
+	"net"
+)
+
+// dialAllocPort connects to the TCP address of an allocation. Network
+// namespaces are only supported on Linux.
+func dialAllocPort(netns, addr string) (net.Conn, error) {
+	if netns != "" {
+		return nil, errors.New("port forwarding into network namespaces is only supported on Linux")
+	}
+	return net.DialTimeout("tcp", addr, portForwardDialTimeout)
+}
@@ -5,9 +5,14 @@ package allocdir
 
 import (
 	"archive/tar"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -28,9 +33,21 @@ const (
 	// idUnsupported is what the uid/gid will be set to on platforms (eg
 	// Windows) that don't support integer ownership identifiers.
 	idUnsupported = -1
+
+	// MaxSnapshotChunkSize is the maximum size of a chunk of a snapshot
+	// file.
+	MaxSnapshotChunkSize = 64 * 1024 * 1024
+
+	// snapshotChunkChecksumPrefix prefixes the checksum of the snapshot
+	// chunks held in their gzip header comment.
+	snapshotChunkChecksumPrefix = "sha256:"
 )
 
 var (
+	// SnapshotChunkSize is the size of the chunks the regular files of a
+	// snapshot manifest are transferred in.
+	SnapshotChunkSize int64 = 8 * 1024 * 1024
+
 	// SnapshotErrorTime is the sentinel time that will be used on the
 	// error file written by Snapshot when it encounters as error.
 	SnapshotErrorTime = time.Date(2000, 0, 0, 0, 0, 0, 0, time.UTC)
@@ -107,7 +124,13 @@ type AllocDirFS interface {
 	List(path string) ([]*cstructs.AllocFileInfo, error)
 	Stat(path string) (*cstructs.AllocFileInfo, error)
 	ReadAt(path string, offset int64) (io.ReadCloser, error)
+	WriteFile(path string, r io.Reader, mode os.FileMode) error
+	Untar(path string, r io.Reader) error
+	Remove(path string) error
+	Archive(path string, w io.Writer) error
 	Snapshot(w io.Writer) error
+	SnapshotManifest() (*cstructs.AllocSnapshotManifest, error)
+	SnapshotChunk(path string, offset, length int64, w io.Writer) error
 	BlockUntilExists(ctx context.Context, path string) (chan error, error)
 	ChangeEvents(ctx context.Context, path string, curOffset int64) (*watch.FileChanges, error)
 }
@@ -146,11 +169,7 @@ func (d *AllocDir) Snapshot(w io.Writer) error {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
-	allocDataDir := filepath.Join(d.SharedDir, SharedDataDir)
-	rootPaths := []string{allocDataDir}
-	for _, taskdir := range d.TaskDirs {
-		rootPaths = append(rootPaths, taskdir.LocalDir)
-	}
+	rootPaths := d.snapshotRoots()
 
 	tw := tar.NewWriter(w)
 	defer tw.Close()
@@ -221,6 +240,158 @@ func (d *AllocDir) Snapshot(w io.Writer) error {
 	return nil
 }
 
+// snapshotRoots returns the directories included in snapshots: the data dir
+// of the allocation and the task local directories. The caller must hold the
+// lock.
+func (d *AllocDir) snapshotRoots() []string {
+	rootPaths := []string{filepath.Join(d.SharedDir, SharedDataDir)}
+	for _, taskdir := range d.TaskDirs {
+		rootPaths = append(rootPaths, taskdir.LocalDir)
+	}
+	return rootPaths
+}
+
+// SnapshotManifest returns the manifest of the files and directories included
+// in a Snapshot, so they can be migrated in chunks with SnapshotChunk.
+func (d *AllocDir) SnapshotManifest() (*cstructs.AllocSnapshotManifest, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	manifest := &cstructs.AllocSnapshotManifest{
+		ChunkSize: SnapshotChunkSize,
+	}
+
+	walkFn := func(path string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(d.AllocDir, path)
+		if err != nil {
+			return err
+		}
+		link := ""
+		if fileInfo.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return fmt.Errorf("error reading symlink: %v", err)
+			}
+			link = target
+		}
+		hdr, err := tar.FileInfoHeader(fileInfo, link)
+		if err != nil {
+			return fmt.Errorf("error creating file header: %v", err)
+		}
+
+		manifest.Entries = append(manifest.Entries, &cstructs.AllocSnapshotEntry{
+			Name:     relPath,
+			Typeflag: hdr.Typeflag,
+			Mode:     hdr.Mode,
+			Uid:      hdr.Uid,
+			Gid:      hdr.Gid,
+			Size:     hdr.Size,
+			ModTime:  hdr.ModTime,
+			Linkname: hdr.Linkname,
+		})
+		return nil
+	}
+
+	for _, path := range d.snapshotRoots() {
+		if err := filepath.Walk(path, walkFn); err != nil {
+			return nil, fmt.Errorf("failed to snapshot %s: %v", path, err)
+		}
+	}
+
+	return manifest, nil
+}
+
+// SnapshotChunk writes a chunk of the content of a regular file of the
+// snapshot manifest to w, as a gzip stream whose header comment holds the
+// SHA-256 checksum of the uncompressed chunk. The chunk is read from the
+// offset and is at most length bytes long.
+func (d *AllocDir) SnapshotChunk(path string, offset, length int64, w io.Writer) error {
+	if offset < 0 || length <= 0 || length > MaxSnapshotChunkSize {
+		return fmt.Errorf("invalid chunk offset %d and length %d", offset, length)
+	}
+
+	p, err := d.resolvePath(path)
+	if err != nil {
+		return err
+	}
+
+	d.mu.RLock()
+	rootPaths := d.snapshotRoots()
+	d.mu.RUnlock()
+	included := false
+	for _, root := range rootPaths {
+		if p == root || strings.HasPrefix(p, root+string(filepath.Separator)) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return fmt.Errorf("File %s is not part of the snapshot", path)
+	}
+
+	f, err := openFileBeneath(d.AllocDir, d.relPath(p))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("File %s is not a regular file", path)
+	}
+
+	buf := make([]byte, length)
+	n, err := io.ReadFull(io.NewSectionReader(f, offset, length), buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return fmt.Errorf("error reading chunk: %v", err)
+	}
+	buf = buf[:n]
+
+	sum := sha256.Sum256(buf)
+	gw := gzip.NewWriter(w)
+	gw.Comment = snapshotChunkChecksumPrefix + hex.EncodeToString(sum[:])
+	if _, err := gw.Write(buf); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// ReadSnapshotChunk reads a chunk written by SnapshotChunk and returns its
+// content once its checksum is verified.
+func ReadSnapshotChunk(r io.Reader) ([]byte, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading chunk: %v", err)
+	}
+	defer gr.Close()
+
+	checksum, ok := strings.CutPrefix(gr.Comment, snapshotChunkChecksumPrefix)
+	if !ok {
+		return nil, fmt.Errorf("chunk is missing its checksum")
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(gr, MaxSnapshotChunkSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading chunk: %v", err)
+	}
+	if int64(len(buf)) > MaxSnapshotChunkSize {
+		return nil, fmt.Errorf("chunk exceeds the maximum size of %d bytes", MaxSnapshotChunkSize)
+	}
+
+	sum := sha256.Sum256(buf)
+	if hex.EncodeToString(sum[:]) != checksum {
+		return nil, fmt.Errorf("chunk checksum mismatch")
+	}
+	return buf, nil
+}
+
 // Move other alloc directory's shared path and local dir to this alloc dir.
 func (d *AllocDir) Move(other *AllocDir, tasks []*structs.Task) error {
 	d.mu.RLock()
@@ -266,6 +437,51 @@ func (d *AllocDir) Move(other *AllocDir, tasks []*structs.Task) error {
 	return nil
 }
 
+// DiskUsage returns the bytes and inodes used by the data of the allocation,
+// which is the content of its shared directory and the local directories of
+// its tasks.
+func (d *AllocDir) DiskUsage() (uint64, uint64, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var bytes, inodes uint64
+	walkFn := func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Files may be removed by the tasks while walking
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		inodes++
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		bytes += uint64(info.Size())
+		return nil
+	}
+
+	roots := []string{d.SharedDir}
+	for _, taskdir := range d.TaskDirs {
+		roots = append(roots, taskdir.LocalDir)
+	}
+	for _, root := range roots {
+		if err := filepath.WalkDir(root, walkFn); err != nil {
+			return 0, 0, fmt.Errorf("failed to compute disk usage of %s: %v", root, err)
+		}
+	}
+
+	return bytes, inodes, nil
+}
+
 // Destroy tears down previously build directory structure.
 func (d *AllocDir) Destroy() error {
 	// Unmount all mounted shared alloc dirs.
@@ -475,6 +691,244 @@ func (d *AllocDir) ReadAt(path string, offset int64) (io.ReadCloser, error) {
 	return f, nil
 }
 
+// WriteFile writes the content of r to the file at a path relative to the
+// alloc dir, creating the missing parent directories. The content is written
+// to a temporary file which is renamed once complete, so tasks never read a
+// partially written file.
+func (d *AllocDir) WriteFile(path string, r io.Reader, mode os.FileMode) error {
+	p, err := d.resolvePath(path)
+	if err != nil {
+		return err
+	}
+	if p == d.AllocDir {
+		return fmt.Errorf("Writing the alloc directory prohibited")
+	}
+	return writeFileBeneath(d.AllocDir, d.relPath(p), r, mode)
+}
+
+// Untar extracts the tar archive read from r into the directory at a path
+// relative to the alloc dir. Only regular files and directories can be
+// extracted, and every entry is checked the same way as WriteFile.
+func (d *AllocDir) Untar(path string, r io.Reader) error {
+	dir, err := d.resolvePath(path)
+	if err != nil {
+		return err
+	}
+	if err := mkdirBeneath(d.AllocDir, d.relPath(dir), 0755); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading archive: %v", err)
+		}
+
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(name) || escapingfs.PathEscapesSandbox(dir, filepath.Join(dir, name)) {
+			return fmt.Errorf("Archive entry %q escapes the destination directory", hdr.Name)
+		}
+
+		p, err := d.resolvePath(filepath.Join(path, name))
+		if err != nil {
+			return fmt.Errorf("error extracting %q: %v", hdr.Name, err)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := mkdirBeneath(d.AllocDir, d.relPath(p), hdr.FileInfo().Mode().Perm()); err != nil {
+				return fmt.Errorf("error extracting %q: %v", hdr.Name, err)
+			}
+		case tar.TypeReg:
+			if err := writeFileBeneath(d.AllocDir, d.relPath(p), tr, hdr.FileInfo().Mode().Perm()); err != nil {
+				return fmt.Errorf("error extracting %q: %v", hdr.Name, err)
+			}
+		default:
+			return fmt.Errorf("Archive entry %q is not a regular file or directory", hdr.Name)
+		}
+	}
+}
+
+// Remove deletes the file or directory at a path relative to the alloc dir.
+// Symlinks are removed rather than their target. The alloc, shared and task
+// directories themselves can't be removed.
+func (d *AllocDir) Remove(path string) error {
+	clean := filepath.Clean(string(filepath.Separator) + path)
+	if clean == string(filepath.Separator) {
+		return fmt.Errorf("Removing the alloc directory prohibited")
+	}
+
+	parent, err := d.resolvePath(filepath.Dir(clean))
+	if err != nil {
+		return err
+	}
+	p := filepath.Join(parent, filepath.Base(clean))
+	if d.isSecretPath(p) {
+		return fmt.Errorf("Removing secret or private file prohibited: %s", path)
+	}
+
+	d.mu.RLock()
+	protected := []string{d.SharedDir}
+	for _, dir := range d.TaskDirs {
+		protected = append(protected, dir.Dir)
+	}
+	d.mu.RUnlock()
+	for _, dir := range protected {
+		if p == dir {
+			return fmt.Errorf("Removing %s prohibited", path)
+		}
+	}
+
+	return removeBeneath(d.AllocDir, d.relPath(p))
+}
+
+// Archive writes a gzip compressed tar archive of the file or directory at a
+// path relative to the alloc dir to w. The archive entries are relative to
+// the directory, and the secrets and private directories of the tasks are
+// skipped.
+func (d *AllocDir) Archive(path string, w io.Writer) error {
+	root, err := d.resolvePath(path)
+	if err != nil {
+		return err
+	}
+	rootRel := d.relPath(root)
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	// The archive entries are relative to the directory, or to the parent
+	// directory of the file being archived
+	base := ""
+	walkFn := func(rel string, fileInfo os.FileInfo, link string, r io.Reader) error {
+		if base == "" {
+			base = rootRel
+			if !fileInfo.IsDir() {
+				base = filepath.Dir(rootRel)
+			}
+		}
+		if d.isSecretPath(filepath.Join(d.AllocDir, rel)) {
+			if fileInfo.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(base, rel)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(fileInfo, link)
+		if err != nil {
+			return fmt.Errorf("error creating file header: %v", err)
+		}
+		hdr.Name = filepath.ToSlash(relPath)
+		if fileInfo.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		// Only regular files have content
+		if r == nil {
+			return nil
+		}
+		_, err = io.CopyN(tw, r, hdr.Size)
+		return err
+	}
+
+	if err := walkBeneath(d.AllocDir, rootRel, walkFn); err != nil {
+		return fmt.Errorf("failed to archive %s: %v", path, err)
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// walkBeneathFunc is called by walkBeneath for each file or directory with
+// its path relative to root and its info. The target of symlinks is given as
+// link, and the content of regular files can be read from r. Returning
+// filepath.SkipDir for a directory skips its content.
+type walkBeneathFunc func(rel string, info os.FileInfo, link string, r io.Reader) error
+
+// resolvePath returns the absolute path of a path relative to the alloc dir,
+// with the symlinks of its existing part resolved. It returns an error if the
+// path escapes the alloc dir or leads into the secrets or private directory
+// of a task.
+func (d *AllocDir) resolvePath(path string) (string, error) {
+	if escapes, err := escapingfs.PathEscapesAllocViaRelative("", path); err != nil {
+		return "", fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
+	} else if escapes {
+		return "", fmt.Errorf("Path escapes the alloc directory")
+	}
+
+	root, err := filepath.EvalSymlinks(d.AllocDir)
+	if err != nil {
+		return "", err
+	}
+
+	// Resolve the longest prefix of the path which exists
+	existing, missing := filepath.Join(d.AllocDir, path), ""
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			existing = resolved
+			break
+		}
+		if !os.IsNotExist(err) || existing == d.AllocDir {
+			return "", err
+		}
+		missing = filepath.Join(filepath.Base(existing), missing)
+		existing = filepath.Dir(existing)
+	}
+
+	rel, err := filepath.Rel(root, filepath.Join(existing, missing))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("Path escapes the alloc directory")
+	}
+
+	p := filepath.Join(d.AllocDir, rel)
+	if d.isSecretPath(p) {
+		return "", fmt.Errorf("Accessing secret or private file prohibited: %s", path)
+	}
+	return p, nil
+}
+
+// relPath returns the path relative to the alloc dir of a path returned by
+// resolvePath.
+func (d *AllocDir) relPath(p string) string {
+	rel, err := filepath.Rel(d.AllocDir, p)
+	if err != nil {
+		// resolvePath only returns paths within the alloc dir
+		return p
+	}
+	return rel
+}
+
+// isSecretPath returns whether an absolute path is within the secrets or
+// private directory of a task.
+func (d *AllocDir) isSecretPath(p string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, dir := range d.TaskDirs {
+		for _, secret := range []string{dir.SecretsDir, dir.PrivateDir} {
+			if p == secret || strings.HasPrefix(p, secret+string(filepath.Separator)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // BlockUntilExists blocks until the passed file relative the allocation
 // directory exists. The block can be cancelled with the passed context.
 func (d *AllocDir) BlockUntilExists(ctx context.Context, path string) (chan error, error) {
@@ -6,6 +6,7 @@ package allocdir
 import (
 	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"io"
 	"io/fs"
@@ -219,6 +220,31 @@ func TestAllocDir_Snapshot(t *testing.T) {
 	}
 }
 
+func TestAllocDir_DiskUsage(t *testing.T) {
+	ci.Parallel(t)
+
+	tmp := t.TempDir()
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	defer d.Destroy()
+	require.NoError(t, d.Build())
+
+	td := d.NewTaskDir(t1.Name)
+	require.NoError(t, td.Build(false, nil))
+
+	bytesBefore, inodesBefore, err := d.DiskUsage()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(d.SharedDir, "data", "foo"), make([]byte, 1024), 0666))
+	require.NoError(t, os.WriteFile(filepath.Join(td.LocalDir, "bar"), make([]byte, 512), 0666))
+	require.NoError(t, os.Symlink("bar", filepath.Join(td.LocalDir, "baz")))
+
+	bytesAfter, inodesAfter, err := d.DiskUsage()
+	require.NoError(t, err)
+	require.Equal(t, bytesBefore+1536, bytesAfter)
+	require.Equal(t, inodesBefore+3, inodesAfter)
+}
+
 func TestAllocDir_Move(t *testing.T) {
 	ci.Parallel(t)
 
@@ -520,3 +546,311 @@ func TestAllocDir_SkipAllocDir(t *testing.T) {
 		t.Fatalf("expected chroot to not exist but error is: %v", err)
 	}
 }
+
+func TestAllocDir_WriteFile(t *testing.T) {
+	ci.Parallel(t)
+	tmp := t.TempDir()
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	require.NoError(t, d.Build())
+	defer func() {
+		_ = d.Destroy()
+	}()
+
+	td := d.NewTaskDir(t1.Name)
+	require.NoError(t, td.Build(false, nil))
+
+	// Missing parent directories are created
+	target := filepath.Join(t1.Name, TaskLocal, "conf", "app.json")
+	require.NoError(t, d.WriteFile(target, strings.NewReader(`{"debug":true}`), 0640))
+
+	out, err := os.ReadFile(filepath.Join(d.AllocDir, target))
+	require.NoError(t, err)
+	require.Equal(t, `{"debug":true}`, string(out))
+
+	info, err := os.Stat(filepath.Join(d.AllocDir, target))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	// Existing files are replaced
+	require.NoError(t, d.WriteFile(target, strings.NewReader("{}"), 0640))
+	out, err = os.ReadFile(filepath.Join(d.AllocDir, target))
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(out))
+
+	// Escaping paths and secrets are rejected
+	err = d.WriteFile("../../escape", strings.NewReader("x"), 0644)
+	require.ErrorContains(t, err, "Path escapes the alloc directory")
+
+	err = d.WriteFile(filepath.Join(t1.Name, TaskSecrets, "token"), strings.NewReader("x"), 0644)
+	require.ErrorContains(t, err, "prohibited")
+
+	// Symlinks leading out of the alloc dir or into secrets are rejected
+	outside := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(d.SharedDir, "out")))
+	err = d.WriteFile(filepath.Join(SharedAllocName, "out", "file"), strings.NewReader("x"), 0644)
+	require.ErrorContains(t, err, "Path escapes the alloc directory")
+	require.NoFileExists(t, filepath.Join(outside, "file"))
+
+	require.NoError(t, os.Symlink(td.SecretsDir, filepath.Join(d.SharedDir, "secrets")))
+	err = d.WriteFile(filepath.Join(SharedAllocName, "secrets", "file"), strings.NewReader("x"), 0644)
+	require.ErrorContains(t, err, "prohibited")
+}
+
+func TestAllocDir_Untar(t *testing.T) {
+	ci.Parallel(t)
+	tmp := t.TempDir()
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	require.NoError(t, d.Build())
+	defer func() {
+		_ = d.Destroy()
+	}()
+
+	td := d.NewTaskDir(t1.Name)
+	require.NoError(t, td.Build(false, nil))
+
+	archive := func(hdrs ...*tar.Header) io.Reader {
+		buf := new(bytes.Buffer)
+		tw := tar.NewWriter(buf)
+		for _, hdr := range hdrs {
+			require.NoError(t, tw.WriteHeader(hdr))
+			if hdr.Typeflag == tar.TypeReg {
+				_, err := tw.Write([]byte(hdr.Name))
+				require.NoError(t, err)
+			}
+		}
+		require.NoError(t, tw.Close())
+		return buf
+	}
+	file := func(name string) *tar.Header {
+		return &tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(name))}
+	}
+
+	dest := filepath.Join(t1.Name, TaskLocal, "site")
+	require.NoError(t, d.Untar(dest, archive(
+		&tar.Header{Name: "css/", Typeflag: tar.TypeDir, Mode: 0755},
+		file("css/main.css"),
+		file("index.html"),
+	)))
+
+	for _, name := range []string{"css/main.css", "index.html"} {
+		out, err := os.ReadFile(filepath.Join(d.AllocDir, dest, name))
+		require.NoError(t, err)
+		require.Equal(t, name, string(out))
+	}
+
+	err := d.Untar(dest, archive(file("../../../../escape")))
+	require.ErrorContains(t, err, "escapes the destination directory")
+
+	err = d.Untar(t1.Name, archive(file("secrets/token")))
+	require.ErrorContains(t, err, "prohibited")
+
+	err = d.Untar(dest, archive(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc"}))
+	require.ErrorContains(t, err, "is not a regular file or directory")
+}
+
+func TestAllocDir_Remove(t *testing.T) {
+	ci.Parallel(t)
+	tmp := t.TempDir()
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	require.NoError(t, d.Build())
+	defer func() {
+		_ = d.Destroy()
+	}()
+
+	td := d.NewTaskDir(t1.Name)
+	require.NoError(t, td.Build(false, nil))
+
+	dumps := filepath.Join(td.LocalDir, "dumps")
+	require.NoError(t, os.MkdirAll(dumps, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dumps, "heap.1"), []byte("x"), 0644))
+
+	// Symlinks are removed, not their target
+	link := filepath.Join(d.SharedDir, "dumps")
+	require.NoError(t, os.Symlink(dumps, link))
+	require.NoError(t, d.Remove(filepath.Join(SharedAllocName, "dumps")))
+	require.NoFileExists(t, link)
+	require.FileExists(t, filepath.Join(dumps, "heap.1"))
+
+	require.NoError(t, d.Remove(filepath.Join(t1.Name, TaskLocal, "dumps")))
+	require.NoDirExists(t, dumps)
+
+	err := d.Remove(filepath.Join(t1.Name, TaskLocal, "dumps"))
+	require.True(t, os.IsNotExist(err))
+
+	for _, path := range []string{"/", SharedAllocName, t1.Name} {
+		err := d.Remove(path)
+		require.ErrorContains(t, err, "prohibited", path)
+	}
+
+	err = d.Remove(filepath.Join(t1.Name, TaskSecrets))
+	require.ErrorContains(t, err, "prohibited")
+	require.DirExists(t, td.SecretsDir)
+}
+
+func TestAllocDir_Archive(t *testing.T) {
+	ci.Parallel(t)
+	tmp := t.TempDir()
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	require.NoError(t, d.Build())
+	defer func() {
+		_ = d.Destroy()
+	}()
+
+	td := d.NewTaskDir(t1.Name)
+	require.NoError(t, td.Build(false, nil))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(td.LocalDir, "dumps"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(td.LocalDir, "dumps", "heap.1"), []byte("heap"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(td.SecretsDir, "token"), []byte("secret"), 0600))
+
+	entries := func(path string) map[string]string {
+		buf := new(bytes.Buffer)
+		require.NoError(t, d.Archive(path, buf))
+
+		gr, err := gzip.NewReader(buf)
+		require.NoError(t, err)
+		tr := tar.NewReader(gr)
+
+		files := make(map[string]string)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return files
+			}
+			require.NoError(t, err)
+			content, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			files[hdr.Name] = string(content)
+		}
+	}
+
+	require.Equal(t, map[string]string{
+		"dumps/":       "",
+		"dumps/heap.1": "heap",
+	}, entries(filepath.Join(t1.Name, TaskLocal)))
+
+	// The secrets of the tasks are skipped
+	files := entries(t1.Name)
+	require.Equal(t, "heap", files["local/dumps/heap.1"])
+	require.NotContains(t, files, "secrets/")
+	require.NotContains(t, files, "secrets/token")
+
+	// Files are archived by their name
+	require.Equal(t, map[string]string{"heap.1": "heap"},
+		entries(filepath.Join(t1.Name, TaskLocal, "dumps", "heap.1")))
+
+	require.ErrorContains(t, d.Archive(filepath.Join(t1.Name, TaskSecrets), io.Discard), "prohibited")
+	require.ErrorContains(t, d.Archive("../..", io.Discard), "Path escapes the alloc directory")
+}
+
+func TestAllocDir_SnapshotManifest(t *testing.T) {
+	ci.Parallel(t)
+	tmp := t.TempDir()
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	require.NoError(t, d.Build())
+	defer func() {
+		_ = d.Destroy()
+	}()
+
+	td := d.NewTaskDir(t1.Name)
+	require.NoError(t, td.Build(false, nil))
+
+	dataDir := filepath.Join(d.SharedDir, SharedDataDir)
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "cache"), []byte("cached"), 0640))
+	require.NoError(t, os.Symlink("cache", filepath.Join(dataDir, "link")))
+	require.NoError(t, os.WriteFile(filepath.Join(td.LocalDir, "state"), []byte("state"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(td.SecretsDir, "token"), []byte("secret"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(d.SharedDir, LogDirName, "web.stdout.0"), []byte("log"), 0600))
+
+	manifest, err := d.SnapshotManifest()
+	require.NoError(t, err)
+	require.Equal(t, SnapshotChunkSize, manifest.ChunkSize)
+
+	entries := make(map[string]byte)
+	for _, e := range manifest.Entries {
+		entries[e.Name] = e.Typeflag
+	}
+
+	// Only the data dir and the task local dirs are included
+	require.Equal(t, map[string]byte{
+		filepath.Join(SharedAllocName, SharedDataDir):          tar.TypeDir,
+		filepath.Join(SharedAllocName, SharedDataDir, "cache"): tar.TypeReg,
+		filepath.Join(SharedAllocName, SharedDataDir, "link"):  tar.TypeSymlink,
+		filepath.Join(t1.Name, TaskLocal):                      tar.TypeDir,
+		filepath.Join(t1.Name, TaskLocal, "state"):             tar.TypeReg,
+	}, entries)
+	require.Equal(t, 2, manifest.Chunks())
+	require.Equal(t, int64(len("cached")+len("state")), manifest.Size())
+}
+
+func TestAllocDir_SnapshotChunk(t *testing.T) {
+	ci.Parallel(t)
+	tmp := t.TempDir()
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	require.NoError(t, d.Build())
+	defer func() {
+		_ = d.Destroy()
+	}()
+
+	td := d.NewTaskDir(t1.Name)
+	require.NoError(t, td.Build(false, nil))
+
+	dataDir := filepath.Join(d.SharedDir, SharedDataDir)
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "cache"), []byte("hello world"), 0640))
+	require.NoError(t, os.Symlink("cache", filepath.Join(dataDir, "link")))
+	require.NoError(t, os.WriteFile(filepath.Join(td.SecretsDir, "token"), []byte("secret"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(d.SharedDir, LogDirName, "web.stdout.0"), []byte("log"), 0600))
+
+	chunk := func(path string, offset, length int64) ([]byte, error) {
+		buf := new(bytes.Buffer)
+		if err := d.SnapshotChunk(path, offset, length, buf); err != nil {
+			return nil, err
+		}
+		return ReadSnapshotChunk(buf)
+	}
+
+	cache := filepath.Join(SharedAllocName, SharedDataDir, "cache")
+	data, err := chunk(cache, 0, 5)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data))
+
+	// The last chunk of a file may be shorter
+	data, err = chunk(cache, 8, 5)
+	require.NoError(t, err)
+	require.Equal(t, "rld", string(data))
+
+	// Only the regular files of the snapshot can be read
+	_, err = chunk(filepath.Join(SharedAllocName, LogDirName, "web.stdout.0"), 0, 5)
+	require.ErrorContains(t, err, "not part of the snapshot")
+	_, err = chunk(filepath.Join(t1.Name, TaskSecrets, "token"), 0, 5)
+	require.ErrorContains(t, err, "prohibited")
+	_, err = chunk(filepath.Join(SharedAllocName, SharedDataDir), 0, 5)
+	require.ErrorContains(t, err, "not a regular file")
+	_, err = chunk("../../etc/passwd", 0, 5)
+	require.ErrorContains(t, err, "Path escapes the alloc directory")
+	_, err = chunk(cache, 0, MaxSnapshotChunkSize+1)
+	require.ErrorContains(t, err, "invalid chunk")
+
+	// Corrupted chunks are detected
+	buf := new(bytes.Buffer)
+	require.NoError(t, d.SnapshotChunk(cache, 0, 5, buf))
+	gr, err := gzip.NewReader(buf)
+	require.NoError(t, err)
+	content, err := io.ReadAll(gr)
+	require.NoError(t, err)
+
+	corrupted := new(bytes.Buffer)
+	gw := gzip.NewWriter(corrupted)
+	gw.Comment = gr.Comment
+	_, err = gw.Write(append(content[:4], 'x'))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	_, err = ReadSnapshotChunk(corrupted)
+	require.ErrorContains(t, err, "checksum mismatch")
+}
@@ -6,14 +6,20 @@
 package allocdir
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/user"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/open-wander/wander/helper/users"
+	"github.com/open-wander/wander/helper/uuid"
 	"golang.org/x/sys/unix"
 )
 
@@ -109,3 +115,288 @@ func getOwner(fi os.FileInfo) (int, int) {
 	}
 	return int(stat.Uid), int(stat.Gid)
 }
+
+// openDirBeneath opens the directory at a path relative to root, creating the
+// missing directories with the given mode if create is true. Each directory
+// is opened relative to its parent without following symlinks, so a task
+// can't swap a directory for a symlink escaping root after the path was
+// checked. The returned file descriptor must be closed by the caller.
+func openDirBeneath(root, rel string, create bool, mode os.FileMode) (int, error) {
+	fd, err := unix.Open(root, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return -1, &os.PathError{Op: "open", Path: root, Err: err}
+	}
+
+	path := root
+	for _, name := range strings.Split(filepath.Clean(rel), string(filepath.Separator)) {
+		if name == "" || name == "." {
+			continue
+		}
+		if name == ".." {
+			unix.Close(fd)
+			return -1, fmt.Errorf("Path escapes the alloc directory")
+		}
+		path = filepath.Join(path, name)
+
+		if create {
+			err := unix.Mkdirat(fd, name, uint32(mode.Perm()))
+			if err != nil && !errors.Is(err, unix.EEXIST) {
+				unix.Close(fd)
+				return -1, &os.PathError{Op: "mkdir", Path: path, Err: err}
+			}
+		}
+
+		next, err := unix.Openat(fd, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+		unix.Close(fd)
+		if err != nil {
+			return -1, &os.PathError{Op: "open", Path: path, Err: err}
+		}
+		fd = next
+	}
+	return fd, nil
+}
+
+// openFileBeneath opens the file at a path relative to root for reading,
+// without following symlinks.
+func openFileBeneath(root, rel string) (*os.File, error) {
+	dirFd, err := openDirBeneath(root, filepath.Dir(rel), false, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer unix.Close(dirFd)
+
+	path := filepath.Join(root, rel)
+	fd, err := unix.Openat(dirFd, filepath.Base(rel), unix.O_RDONLY|unix.O_NOFOLLOW|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	return os.NewFile(uintptr(fd), path), nil
+}
+
+// mkdirBeneath creates the directory at a path relative to root along with
+// its missing parents, without following symlinks.
+func mkdirBeneath(root, rel string, mode os.FileMode) error {
+	fd, err := openDirBeneath(root, rel, true, mode)
+	if err != nil {
+		return err
+	}
+	return unix.Close(fd)
+}
+
+// writeFileBeneath writes the content of r to a temporary file next to the
+// file at a path relative to root and renames it to the file. The temporary
+// file is created and renamed relative to the opened parent directory, so
+// symlinks are never followed.
+func writeFileBeneath(root, rel string, r io.Reader, mode os.FileMode) error {
+	dirFd, err := openDirBeneath(root, filepath.Dir(rel), true, 0755)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(dirFd)
+
+	name := filepath.Base(rel)
+	tmpName := fmt.Sprintf(".%s.%s.tmp", name, uuid.Short())
+	tmpPath := filepath.Join(root, filepath.Dir(rel), tmpName)
+
+	fd, err := unix.Openat(dirFd, tmpName,
+		unix.O_WRONLY|unix.O_CREAT|unix.O_EXCL|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0600)
+	if err != nil {
+		return &os.PathError{Op: "open", Path: tmpPath, Err: err}
+	}
+	tmp := os.NewFile(uintptr(fd), tmpPath)
+
+	renamed := false
+	defer func() {
+		if !renamed {
+			unix.Unlinkat(dirFd, tmpName, 0)
+		}
+	}()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := unix.Renameat(dirFd, tmpName, dirFd, name); err != nil {
+		return &os.PathError{Op: "rename", Path: tmpPath, Err: err}
+	}
+	renamed = true
+	return nil
+}
+
+// removeBeneath deletes the file or directory at a path relative to root.
+// Directories are emptied relative to their opened file descriptor, so a task
+// can't swap a directory for a symlink to delete files outside of root.
+func removeBeneath(root, rel string) error {
+	dirFd, err := openDirBeneath(root, filepath.Dir(rel), false, 0)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(dirFd)
+	return removeAt(dirFd, filepath.Base(rel), filepath.Join(root, rel))
+}
+
+// removeAt deletes the file or directory with the given name in the opened
+// directory, without following symlinks.
+func removeAt(dirFd int, name, path string) error {
+	var st unix.Stat_t
+	if err := unix.Fstatat(dirFd, name, &st, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		return &os.PathError{Op: "lstat", Path: path, Err: err}
+	}
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		if err := unix.Unlinkat(dirFd, name, 0); err != nil {
+			return &os.PathError{Op: "unlink", Path: path, Err: err}
+		}
+		return nil
+	}
+
+	fd, err := unix.Openat(dirFd, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	dir := os.NewFile(uintptr(fd), path)
+	names, err := dir.Readdirnames(-1)
+	if err == nil {
+		for _, child := range names {
+			if err = removeAt(fd, child, filepath.Join(path, child)); err != nil {
+				break
+			}
+		}
+	}
+	dir.Close()
+	if err != nil {
+		return err
+	}
+
+	if err := unix.Unlinkat(dirFd, name, unix.AT_REMOVEDIR); err != nil {
+		return &os.PathError{Op: "rmdir", Path: path, Err: err}
+	}
+	return nil
+}
+
+// walkBeneath walks the file tree at a path relative to root in lexical
+// order. Each file and directory is opened relative to its parent without
+// following symlinks, so a task can't swap them for symlinks to get the
+// content of files outside of root.
+func walkBeneath(root, rel string, fn walkBeneathFunc) error {
+	dirFd, err := openDirBeneath(root, filepath.Dir(rel), false, 0)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(dirFd)
+
+	err = walkAt(dirFd, filepath.Base(rel), filepath.Clean(rel), filepath.Join(root, rel), fn)
+	if err == filepath.SkipDir {
+		return nil
+	}
+	return err
+}
+
+func walkAt(dirFd int, name, rel, path string, fn walkBeneathFunc) error {
+	var st unix.Stat_t
+	if err := unix.Fstatat(dirFd, name, &st, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		return &os.PathError{Op: "lstat", Path: path, Err: err}
+	}
+
+	switch st.Mode & unix.S_IFMT {
+	case unix.S_IFLNK:
+		buf := make([]byte, unix.PathMax)
+		n, err := unix.Readlinkat(dirFd, name, buf)
+		if err != nil {
+			return &os.PathError{Op: "readlink", Path: path, Err: err}
+		}
+		return fn(rel, &statFileInfo{name: name, st: st}, string(buf[:n]), nil)
+	case unix.S_IFREG, unix.S_IFDIR:
+	default:
+		return fn(rel, &statFileInfo{name: name, st: st}, "", nil)
+	}
+
+	// Only open directories and regular files, and check the opened file is
+	// still the one which was stat'ed
+	fd, err := unix.Openat(dirFd, name, unix.O_RDONLY|unix.O_NOFOLLOW|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	f := os.NewFile(uintptr(fd), path)
+	defer f.Close()
+
+	var fst unix.Stat_t
+	if err := unix.Fstat(fd, &fst); err != nil {
+		return &os.PathError{Op: "fstat", Path: path, Err: err}
+	}
+	if fst.Dev != st.Dev || fst.Ino != st.Ino {
+		return fmt.Errorf("%s was replaced while being read", path)
+	}
+	info := &statFileInfo{name: name, st: fst}
+
+	if !info.IsDir() {
+		return fn(rel, info, "", f)
+	}
+	if err := fn(rel, info, "", nil); err != nil {
+		return err
+	}
+
+	names, err := f.Readdirnames(-1)
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	for _, child := range names {
+		err := walkAt(fd, child, filepath.Join(rel, child), filepath.Join(path, child), fn)
+		if err != nil && err != filepath.SkipDir {
+			return err
+		}
+	}
+	return nil
+}
+
+// statFileInfo implements os.FileInfo for the result of a stat syscall.
+type statFileInfo struct {
+	name string
+	st   unix.Stat_t
+}
+
+func (fi *statFileInfo) Name() string       { return fi.name }
+func (fi *statFileInfo) Size() int64        { return fi.st.Size }
+func (fi *statFileInfo) ModTime() time.Time { return time.Unix(fi.st.Mtim.Unix()) }
+func (fi *statFileInfo) IsDir() bool        { return fi.Mode().IsDir() }
+
+// Sys only returns the owner of the file, as the layout of syscall.Stat_t
+// differs between platforms.
+func (fi *statFileInfo) Sys() any {
+	return &syscall.Stat_t{Uid: fi.st.Uid, Gid: fi.st.Gid}
+}
+
+func (fi *statFileInfo) Mode() os.FileMode {
+	mode := os.FileMode(fi.st.Mode & 0o777)
+	switch fi.st.Mode & unix.S_IFMT {
+	case unix.S_IFDIR:
+		mode |= os.ModeDir
+	case unix.S_IFLNK:
+		mode |= os.ModeSymlink
+	case unix.S_IFIFO:
+		mode |= os.ModeNamedPipe
+	case unix.S_IFSOCK:
+		mode |= os.ModeSocket
+	case unix.S_IFCHR:
+		mode |= os.ModeDevice | os.ModeCharDevice
+	case unix.S_IFBLK:
+		mode |= os.ModeDevice
+	}
+	if fi.st.Mode&unix.S_ISUID != 0 {
+		mode |= os.ModeSetuid
+	}
+	if fi.st.Mode&unix.S_ISGID != 0 {
+		mode |= os.ModeSetgid
+	}
+	if fi.st.Mode&unix.S_ISVTX != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
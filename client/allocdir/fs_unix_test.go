@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build unix
+
+package allocdir
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/shoenig/test/must"
+)
+
+// TestWriteFileBeneath_Symlink asserts that files are never written through
+// a symlink, as a task may swap a directory for a symlink after the path was
+// checked.
+func TestWriteFileBeneath_Symlink(t *testing.T) {
+	ci.Parallel(t)
+
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	must.NoError(t, writeFileBeneath(root, filepath.Join("a", "b", "file"), strings.NewReader("x"), 0644))
+	out, err := os.ReadFile(filepath.Join(root, "a", "b", "file"))
+	must.NoError(t, err)
+	must.Eq(t, "x", string(out))
+
+	// Swap a directory for a symlink leading out of root
+	must.NoError(t, os.RemoveAll(filepath.Join(root, "a", "b")))
+	must.NoError(t, os.Symlink(outside, filepath.Join(root, "a", "b")))
+
+	err = writeFileBeneath(root, filepath.Join("a", "b", "file"), strings.NewReader("x"), 0644)
+	must.Error(t, err)
+	err = mkdirBeneath(root, filepath.Join("a", "b", "dir"), 0755)
+	must.Error(t, err)
+
+	entries, err := os.ReadDir(outside)
+	must.NoError(t, err)
+	must.SliceEmpty(t, entries)
+
+	// A symlink at the location of the file is replaced rather than followed
+	must.NoError(t, os.Symlink(filepath.Join(outside, "target"), filepath.Join(root, "a", "link")))
+	must.NoError(t, writeFileBeneath(root, filepath.Join("a", "link"), strings.NewReader("y"), 0644))
+	must.FileNotExists(t, filepath.Join(outside, "target"))
+
+	info, err := os.Lstat(filepath.Join(root, "a", "link"))
+	must.NoError(t, err)
+	must.True(t, info.Mode().IsRegular())
+}
+
+// TestRemoveBeneath_Symlink asserts that files are never removed through a
+// symlink.
+func TestRemoveBeneath_Symlink(t *testing.T) {
+	ci.Parallel(t)
+
+	root := t.TempDir()
+	outside := t.TempDir()
+	must.NoError(t, os.WriteFile(filepath.Join(outside, "file"), []byte("x"), 0644))
+
+	// Symlinks within a removed directory are removed rather than followed
+	must.NoError(t, os.MkdirAll(filepath.Join(root, "a", "b"), 0755))
+	must.NoError(t, os.Symlink(outside, filepath.Join(root, "a", "b", "link")))
+	must.NoError(t, removeBeneath(root, "a"))
+	must.DirNotExists(t, filepath.Join(root, "a"))
+	must.FileExists(t, filepath.Join(outside, "file"))
+
+	// A directory swapped for a symlink isn't followed
+	must.NoError(t, os.Symlink(outside, filepath.Join(root, "swapped")))
+	err := removeBeneath(root, filepath.Join("swapped", "file"))
+	must.Error(t, err)
+	must.FileExists(t, filepath.Join(outside, "file"))
+
+	err = removeBeneath(root, "missing")
+	must.True(t, os.IsNotExist(err))
+}
+
+// TestWalkBeneath_Symlink asserts that the walked files are never read
+// through a symlink.
+func TestWalkBeneath_Symlink(t *testing.T) {
+	ci.Parallel(t)
+
+	root := t.TempDir()
+	outside := t.TempDir()
+	must.NoError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0644))
+
+	must.NoError(t, os.MkdirAll(filepath.Join(root, "a"), 0755))
+	must.NoError(t, os.WriteFile(filepath.Join(root, "a", "file"), []byte("x"), 0644))
+	must.NoError(t, os.Symlink(outside, filepath.Join(root, "a", "link")))
+
+	type entry struct {
+		mode    os.FileMode
+		link    string
+		content string
+	}
+	walked := map[string]entry{}
+	err := walkBeneath(root, "a", func(rel string, info os.FileInfo, link string, r io.Reader) error {
+		e := entry{mode: info.Mode().Type(), link: link}
+		if r != nil {
+			b, err := io.ReadAll(r)
+			must.NoError(t, err)
+			e.content = string(b)
+		}
+		walked[rel] = e
+		return nil
+	})
+	must.NoError(t, err)
+	must.Eq(t, map[string]entry{
+		"a":                        {mode: os.ModeDir},
+		filepath.Join("a", "file"): {content: "x"},
+		filepath.Join("a", "link"): {mode: os.ModeSymlink, link: outside},
+	}, walked)
+
+	// A directory swapped for a symlink isn't followed
+	err = walkBeneath(root, filepath.Join("a", "link", "secret"), func(string, os.FileInfo, string, io.Reader) error {
+		t.Fatal("symlink was followed")
+		return nil
+	})
+	must.Error(t, err)
+}
+
+// TestOpenFileBeneath_Symlink asserts that files are never opened through a
+// symlink.
+func TestOpenFileBeneath_Symlink(t *testing.T) {
+	ci.Parallel(t)
+
+	root := t.TempDir()
+	outside := t.TempDir()
+	must.NoError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0644))
+
+	must.NoError(t, os.MkdirAll(filepath.Join(root, "a"), 0755))
+	must.NoError(t, os.WriteFile(filepath.Join(root, "a", "file"), []byte("x"), 0644))
+	f, err := openFileBeneath(root, filepath.Join("a", "file"))
+	must.NoError(t, err)
+	b, err := io.ReadAll(f)
+	must.NoError(t, err)
+	must.Eq(t, "x", string(b))
+	must.NoError(t, f.Close())
+
+	// Files and directories swapped for symlinks aren't followed
+	must.NoError(t, os.Symlink(filepath.Join(outside, "secret"), filepath.Join(root, "a", "link")))
+	_, err = openFileBeneath(root, filepath.Join("a", "link"))
+	must.Error(t, err)
+
+	must.NoError(t, os.Symlink(outside, filepath.Join(root, "dir")))
+	_, err = openFileBeneath(root, filepath.Join("dir", "secret"))
+	must.Error(t, err)
+}
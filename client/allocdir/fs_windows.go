@@ -4,6 +4,7 @@
 package allocdir
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 )
@@ -62,3 +63,83 @@ func MountSpecialDirs(taskDir string) error {
 func getOwner(os.FileInfo) (int, int) {
 	return idUnsupported, idUnsupported
 }
+
+// openFileBeneath opens the file at a path relative to root for reading.
+func openFileBeneath(root, rel string) (*os.File, error) {
+	return os.Open(filepath.Join(root, rel))
+}
+
+// mkdirBeneath creates the directory at a path relative to root along with
+// its missing parents.
+func mkdirBeneath(root, rel string, mode os.FileMode) error {
+	return os.MkdirAll(filepath.Join(root, rel), mode)
+}
+
+// writeFileBeneath writes the content of r to a temporary file next to the
+// file at a path relative to root and renames it to the file.
+func writeFileBeneath(root, rel string, r io.Reader, mode os.FileMode) error {
+	path := filepath.Join(root, rel)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// removeBeneath deletes the file or directory at a path relative to root.
+func removeBeneath(root, rel string) error {
+	path := filepath.Join(root, rel)
+	if _, err := os.Lstat(path); err != nil {
+		return err
+	}
+	return os.RemoveAll(path)
+}
+
+// walkBeneath walks the file tree at a path relative to root in lexical
+// order.
+func walkBeneath(root, rel string, fn walkBeneathFunc) error {
+	return filepath.Walk(filepath.Join(root, rel), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		entryRel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return fn(entryRel, info, link, nil)
+		case info.Mode().IsRegular():
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return fn(entryRel, info, "", f)
+		}
+		return fn(entryRel, info, "", nil)
+	})
+}
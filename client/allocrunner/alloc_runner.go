@@ -132,6 +132,11 @@ type allocRunner struct {
 	// allocDir is used to build the allocations directory structure.
 	allocDir *allocdir.AllocDir
 
+	// networkIsolationSpec describes the network namespace of the
+	// allocation, if it has one. Must acquire networkIsolationLock to access.
+	networkIsolationSpec *drivers.NetworkIsolationSpec
+	networkIsolationLock sync.RWMutex
+
 	// runnerHooks are alloc runner lifecycle hooks that should be run on state
 	// transitions.
 	runnerHooks []interfaces.RunnerHook
@@ -288,6 +293,7 @@ func (ar *allocRunner) initTaskRunners(tasks []*structs.Task) error {
 			ConsulProxies:       ar.consulProxiesClient,
 			ConsulSI:            ar.sidsClient,
 			Vault:               ar.vaultClient,
+			RPCClient:           ar.rpcClient,
 			DeviceStatsReporter: ar.deviceStatsReporter,
 			CSIManager:          ar.csiManager,
 			DeviceManager:       ar.devicemanager,
@@ -421,6 +427,14 @@ func (ar *allocRunner) GetAllocDir() *allocdir.AllocDir {
 	return ar.allocDir
 }
 
+// NetworkIsolation returns the network namespace of the allocation, or nil if
+// the allocation doesn't have one.
+func (ar *allocRunner) NetworkIsolation() *drivers.NetworkIsolationSpec {
+	ar.networkIsolationLock.RLock()
+	defer ar.networkIsolationLock.RUnlock()
+	return ar.networkIsolationSpec
+}
+
 // Restore state from database. Must be called after NewAllocRunner but before
 // Run.
 func (ar *allocRunner) Restore() error {
@@ -549,6 +563,21 @@ func (ar *allocRunner) handleTaskStateUpdates() {
 			}
 		}
 
+		// Kill the remaining tasks if a task waits on a dependency that
+		// exited without meeting its condition, since the task would never
+		// start.
+		if killEvent == nil {
+			if name, dep := ar.taskCoordinator.UnmetTaskDependency(states); name != "" {
+				ar.tasks[name].EmitEvent(structs.NewTaskEvent(structs.TaskDependencyFailed).
+					SetFailsTask().
+					SetMessage(fmt.Sprintf("Task dependency %q exited without meeting its condition", dep)))
+
+				killTask = name
+				killEvent = structs.NewTaskEvent(structs.TaskSiblingFailed).
+					SetFailedSibling(name)
+			}
+		}
+
 		// kill remaining live tasks
 		if len(liveRunners) > 0 {
 
@@ -684,31 +713,41 @@ func (ar *allocRunner) killTasks() map[string]*structs.TaskState {
 		break
 	}
 
-	// Kill the rest non-sidecar and non-poststop tasks concurrently
+	// Kill the rest non-sidecar and non-poststop tasks concurrently, in the
+	// reverse order of their dependencies so tasks are stopped before the
+	// tasks they depend on.
 	wg := sync.WaitGroup{}
-	for name, tr := range ar.tasks {
-		// Filter out poststop and sidecar tasks so that they stop after all the other tasks are killed
-		if tr.IsLeader() || tr.IsPoststopTask() || tr.IsSidecarTask() {
-			continue
-		}
+	levels := ar.taskDependencyLevels()
+	for i := len(levels) - 1; i >= 0; i-- {
+		for _, name := range levels[i] {
+			tr, ok := ar.tasks[name]
+			if !ok {
+				continue
+			}
 
-		wg.Add(1)
-		go func(name string, tr *taskrunner.TaskRunner) {
-			defer wg.Done()
-			taskEvent := structs.NewTaskEvent(structs.TaskKilling)
-			taskEvent.SetKillTimeout(tr.Task().KillTimeout, ar.clientConfig.MaxKillTimeout)
-			err := tr.Kill(context.TODO(), taskEvent)
-			if err != nil && err != taskrunner.ErrTaskNotRunning {
-				ar.logger.Warn("error stopping task", "error", err, "task_name", name)
+			// Filter out poststop and sidecar tasks so that they stop after all the other tasks are killed
+			if tr.IsLeader() || tr.IsPoststopTask() || tr.IsSidecarTask() {
+				continue
 			}
 
-			taskState := tr.TaskState()
-			mu.Lock()
-			states[name] = taskState
-			mu.Unlock()
-		}(name, tr)
+			wg.Add(1)
+			go func(name string, tr *taskrunner.TaskRunner) {
+				defer wg.Done()
+				taskEvent := structs.NewTaskEvent(structs.TaskKilling)
+				taskEvent.SetKillTimeout(tr.Task().KillTimeout, ar.clientConfig.MaxKillTimeout)
+				err := tr.Kill(context.TODO(), taskEvent)
+				if err != nil && err != taskrunner.ErrTaskNotRunning {
+					ar.logger.Warn("error stopping task", "error", err, "task_name", name)
+				}
+
+				taskState := tr.TaskState()
+				mu.Lock()
+				states[name] = taskState
+				mu.Unlock()
+			}(name, tr)
+		}
+		wg.Wait()
 	}
-	wg.Wait()
 
 	// Kill the sidecar tasks last.
 	for name, tr := range ar.tasks {
@@ -750,6 +789,20 @@ func (ar *allocRunner) killTasks() map[string]*structs.TaskState {
 	return states
 }
 
+// taskDependencyLevels returns the names of the tasks of the allocation
+// grouped by their depth in the task dependency graph.
+func (ar *allocRunner) taskDependencyLevels() [][]string {
+	tg := ar.Alloc().Job.LookupTaskGroup(ar.Alloc().TaskGroup)
+	if tg == nil {
+		names := make([]string, 0, len(ar.tasks))
+		for name := range ar.tasks {
+			names = append(names, name)
+		}
+		return [][]string{names}
+	}
+	return tg.TaskDependencyLevels()
+}
+
 // clientAlloc takes in the task states and returns an Allocation populated with
 // Client specific fields. Note: this mutates the allocRunner's state to store
 // the taskStates!
@@ -1241,6 +1294,13 @@ func (ar *allocRunner) LatestAllocStats(taskFilter string) (*cstructs.AllocResou
 	return astat, nil
 }
 
+// emitTaskEvent emits a task event to all the tasks of the allocation.
+func (ar *allocRunner) emitTaskEvent(event *structs.TaskEvent) {
+	for _, tr := range ar.tasks {
+		tr.EmitEvent(event.Copy())
+	}
+}
+
 func (ar *allocRunner) GetTaskEventHandler(taskName string) drivermanager.EventHandler {
 	if tr, ok := ar.tasks[taskName]; ok {
 		return func(ev *drivers.TaskEvent) {
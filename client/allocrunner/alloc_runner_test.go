@@ -2520,3 +2520,160 @@ func TestAllocRunner_GetUpdatePriority(t *testing.T) {
 	calloc = ar.clientAlloc(map[string]*structs.TaskState{})
 	must.Eq(t, cstructs.AllocUpdatePriorityUrgent, ar.GetUpdatePriority(calloc))
 }
+
+func TestAllocRunner_TaskDependencies_Order(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.BatchAlloc()
+	tr := alloc.AllocatedResources.Tasks[alloc.Job.TaskGroups[0].Tasks[0].Name]
+
+	base := alloc.Job.TaskGroups[0].Tasks[0]
+	base.Driver = "mock_driver"
+	base.KillTimeout = 10 * time.Millisecond
+	base.Services = nil
+
+	newTask := func(name, runFor string, deps ...*structs.TaskDependency) *structs.Task {
+		task := base.Copy()
+		task.Name = name
+		task.Config = map[string]interface{}{"run_for": runFor}
+		task.DependsOn = deps
+		alloc.AllocatedResources.Tasks[name] = tr
+		return task
+	}
+
+	db := newTask("db", "100s")
+	app := newTask("app", "100s", &structs.TaskDependency{
+		Task: db.Name, Condition: structs.TaskDependencyConditionStarted,
+	})
+	migrate := newTask("migrate", "100ms")
+	worker := newTask("worker", "100s", &structs.TaskDependency{
+		Task: migrate.Name, Condition: structs.TaskDependencyConditionComplete,
+	})
+	alloc.Job.TaskGroups[0].Tasks = []*structs.Task{worker, app, db, migrate}
+
+	conf, cleanup := testAllocRunnerConfig(t, alloc)
+	defer cleanup()
+	ar, err := NewAllocRunner(conf)
+	must.NoError(t, err)
+	defer destroy(ar)
+	go ar.Run()
+
+	upd := conf.StateUpdater.(*MockStateUpdater)
+
+	// Wait for the long running tasks to be running and the migration to
+	// complete.
+	testutil.WaitForResult(func() (bool, error) {
+		last := upd.Last()
+		if last == nil {
+			return false, fmt.Errorf("No updates")
+		}
+
+		for _, task := range []*structs.Task{db, app, worker} {
+			if s := last.TaskStates[task.Name].State; s != structs.TaskStateRunning {
+				return false, fmt.Errorf("expected %s task to be running not %s", task.Name, s)
+			}
+		}
+		if !last.TaskStates[migrate.Name].Successful() {
+			return false, fmt.Errorf("expected migrate task to be successful")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("error waiting for initial state:\n%v", err)
+	})
+
+	last := upd.Last()
+	must.False(t, last.TaskStates[app.Name].StartedAt.Before(last.TaskStates[db.Name].StartedAt))
+	must.False(t, last.TaskStates[worker.Name].StartedAt.Before(last.TaskStates[migrate.Name].FinishedAt))
+
+	// Tell the alloc to stop
+	stopAlloc := alloc.Copy()
+	stopAlloc.DesiredStatus = structs.AllocDesiredStatusStop
+	ar.Update(stopAlloc)
+
+	// Wait for tasks to stop.
+	testutil.WaitForResult(func() (bool, error) {
+		last := upd.Last()
+		for _, task := range []*structs.Task{db, app, worker} {
+			if s := last.TaskStates[task.Name].State; s != structs.TaskStateDead {
+				return false, fmt.Errorf("expected %s task to be dead not %s", task.Name, s)
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("error waiting for kill state:\n%v", err)
+	})
+
+	// Tasks are stopped before the tasks they depend on.
+	last = upd.Last()
+	must.True(t, last.TaskStates[app.Name].FinishedAt.Before(last.TaskStates[db.Name].FinishedAt))
+	must.True(t, last.TaskStates[worker.Name].FinishedAt.Before(last.TaskStates[db.Name].FinishedAt))
+}
+
+func TestAllocRunner_TaskDependencies_Unmet(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.BatchAlloc()
+	tr := alloc.AllocatedResources.Tasks[alloc.Job.TaskGroups[0].Tasks[0].Name]
+	alloc.Job.TaskGroups[0].RestartPolicy.Attempts = 0
+
+	// The db task completes without ever being healthy since it has no
+	// checks, so app can never start.
+	db := alloc.Job.TaskGroups[0].Tasks[0]
+	db.Name = "db"
+	db.Driver = "mock_driver"
+	db.RestartPolicy.Attempts = 0
+	db.Config = map[string]interface{}{
+		"run_for": "10ms",
+	}
+
+	app := db.Copy()
+	app.Name = "app"
+	app.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+	app.DependsOn = []*structs.TaskDependency{
+		{Task: db.Name, Condition: structs.TaskDependencyConditionHealthy},
+	}
+	alloc.Job.TaskGroups[0].Tasks = append(alloc.Job.TaskGroups[0].Tasks, app)
+	alloc.AllocatedResources.Tasks[db.Name] = tr
+	alloc.AllocatedResources.Tasks[app.Name] = tr
+
+	conf, cleanup := testAllocRunnerConfig(t, alloc)
+	defer cleanup()
+	ar, err := NewAllocRunner(conf)
+	must.NoError(t, err)
+	defer destroy(ar)
+	go ar.Run()
+
+	upd := conf.StateUpdater.(*MockStateUpdater)
+
+	testutil.WaitForResult(func() (bool, error) {
+		last := upd.Last()
+		if last == nil {
+			return false, fmt.Errorf("No updates")
+		}
+		if last.ClientStatus != structs.AllocClientStatusFailed {
+			return false, fmt.Errorf("expected alloc to be failed not %s", last.ClientStatus)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("error waiting for alloc to fail:\n%v", err)
+	})
+
+	last := upd.Last()
+	must.True(t, last.TaskStates[db.Name].Successful())
+
+	appState := last.TaskStates[app.Name]
+	must.Eq(t, structs.TaskStateDead, appState.State)
+	must.True(t, appState.Failed)
+	must.True(t, appState.StartedAt.IsZero())
+
+	found := false
+	for _, event := range appState.Events {
+		if event.Type == structs.TaskDependencyFailed {
+			found = true
+			must.StrContains(t, event.DisplayMessage, `"db"`)
+		}
+	}
+	must.True(t, found)
+}
@@ -46,6 +46,7 @@ type AllocRunner interface {
 	GetTaskEventHandler(taskName string) drivermanager.EventHandler
 	GetTaskExecHandler(taskName string) drivermanager.TaskExecHandler
 	GetTaskDriverCapabilities(taskName string) (*drivers.Capabilities, error)
+	NetworkIsolation() *drivers.NetworkIsolationSpec
 	StatsReporter() AllocStatsReporter
 	Listener() *cstructs.AllocListener
 	GetAllocDir() *allocdir.AllocDir
@@ -6,24 +6,41 @@ package allocrunner
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
+	humanize "github.com/dustin/go-humanize"
 	log "github.com/hashicorp/go-hclog"
 	"github.com/open-wander/wander/client/allocdir"
 	"github.com/open-wander/wander/client/config"
+	cstructs "github.com/open-wander/wander/client/structs"
+	"github.com/open-wander/wander/nomad/structs"
 )
 
+// migrateProgressInterval is the minimum interval between the task events
+// reporting the progress of a migration.
+const migrateProgressInterval = 30 * time.Second
+
 // diskMigrationHook migrates ephemeral disk volumes. Depends on alloc dir
 // being built but must be run before anything else manipulates the alloc dir.
 type diskMigrationHook struct {
 	allocDir     *allocdir.AllocDir
 	allocWatcher config.PrevAllocMigrator
 	logger       log.Logger
+
+	// emitEvent emits a task event to the tasks of the allocation.
+	emitEvent func(*structs.TaskEvent)
+
+	// lastProgress is when the progress of the migration was last reported.
+	lastProgress time.Time
 }
 
-func newDiskMigrationHook(logger log.Logger, allocWatcher config.PrevAllocMigrator, allocDir *allocdir.AllocDir) *diskMigrationHook {
+func newDiskMigrationHook(logger log.Logger, allocWatcher config.PrevAllocMigrator, allocDir *allocdir.AllocDir,
+	emitEvent func(*structs.TaskEvent)) *diskMigrationHook {
 	h := &diskMigrationHook{
 		allocDir:     allocDir,
 		allocWatcher: allocWatcher,
+		emitEvent:    emitEvent,
 	}
 	h.logger = logger.Named(h.Name())
 	return h
@@ -42,7 +59,7 @@ func (h *diskMigrationHook) Prerun() error {
 	}
 
 	// Wait for data to be migrated from a previous alloc if applicable
-	if err := h.allocWatcher.Migrate(ctx, h.allocDir); err != nil {
+	if err := h.allocWatcher.Migrate(ctx, h.allocDir, h.progress); err != nil {
 		if err == context.Canceled {
 			return err
 		}
@@ -59,3 +76,45 @@ func (h *diskMigrationHook) Prerun() error {
 
 	return nil
 }
+
+// progress reports the progress of a migration from a remote node as task
+// events. Events are emitted when the migration starts and completes, and at
+// most every migrateProgressInterval in between.
+func (h *diskMigrationHook) progress(p *cstructs.MigrateProgress) {
+	start := h.lastProgress.IsZero()
+	done := p.Chunks == p.TotalChunks
+	if !start && !done && time.Since(h.lastProgress) < migrateProgressInterval {
+		return
+	}
+	h.lastProgress = time.Now()
+
+	percent := 100
+	if p.TotalBytes > 0 {
+		percent = int(p.Bytes * 100 / p.TotalBytes)
+	}
+
+	var msg string
+	switch {
+	case done && !start:
+		msg = fmt.Sprintf("Migrated %s of data from previous allocation", humanize.IBytes(uint64(p.TotalBytes)))
+	case start && p.Resumed:
+		msg = fmt.Sprintf("Resuming migration of data from previous allocation at %s of %s (%d%%)",
+			humanize.IBytes(uint64(p.Bytes)), humanize.IBytes(uint64(p.TotalBytes)), percent)
+	case start:
+		msg = fmt.Sprintf("Migrating %s of data from previous allocation", humanize.IBytes(uint64(p.TotalBytes)))
+	default:
+		msg = fmt.Sprintf("Migrated %s of %s of data from previous allocation (%d%%)",
+			humanize.IBytes(uint64(p.Bytes)), humanize.IBytes(uint64(p.TotalBytes)), percent)
+	}
+
+	event := structs.NewTaskEvent(structs.TaskMigratingDisk).SetMessage(msg)
+	event.Details = map[string]string{
+		"previous_alloc_id": p.PrevAllocID,
+		"bytes":             strconv.FormatInt(p.Bytes, 10),
+		"total_bytes":       strconv.FormatInt(p.TotalBytes, 10),
+		"chunks":            strconv.Itoa(p.Chunks),
+		"total_chunks":      strconv.Itoa(p.TotalChunks),
+		"resumed":           strconv.FormatBool(p.Resumed),
+	}
+	h.emitEvent(event)
+}
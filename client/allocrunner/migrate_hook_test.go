@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package allocrunner
+
+import (
+	"testing"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/client/allocwatcher"
+	cstructs "github.com/open-wander/wander/client/structs"
+	"github.com/open-wander/wander/helper/testlog"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestDiskMigrationHook_Progress(t *testing.T) {
+	ci.Parallel(t)
+
+	var events []*structs.TaskEvent
+	h := newDiskMigrationHook(testlog.HCLogger(t), allocwatcher.NoopPrevAlloc{}, nil, func(e *structs.TaskEvent) {
+		events = append(events, e)
+	})
+
+	progress := func(chunks int, resumed bool) {
+		h.progress(&cstructs.MigrateProgress{
+			PrevAllocID: "prev",
+			Chunks:      chunks,
+			TotalChunks: 4,
+			Bytes:       int64(chunks) * 1024 * 1024,
+			TotalBytes:  4 * 1024 * 1024,
+			Resumed:     resumed,
+		})
+	}
+
+	// The start and the completion of the migration are reported, but not
+	// the chunks in between
+	progress(1, true)
+	progress(2, true)
+	progress(3, true)
+	progress(4, true)
+
+	must.Len(t, 2, events)
+	must.Eq(t, structs.TaskMigratingDisk, events[0].Type)
+	must.Eq(t, "Resuming migration of data from previous allocation at 1.0 MiB of 4.0 MiB (25%)", events[0].Message)
+	must.Eq(t, "1", events[0].Details["chunks"])
+	must.Eq(t, "true", events[0].Details["resumed"])
+	must.Eq(t, "Migrated 4.0 MiB of data from previous allocation", events[1].Message)
+	must.Eq(t, "4194304", events[1].Details["bytes"])
+}
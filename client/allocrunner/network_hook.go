@@ -40,6 +40,10 @@ type allocNetworkIsolationSetter struct {
 }
 
 func (a *allocNetworkIsolationSetter) SetNetworkIsolation(n *drivers.NetworkIsolationSpec) {
+	a.ar.networkIsolationLock.Lock()
+	a.ar.networkIsolationSpec = n
+	a.ar.networkIsolationLock.Unlock()
+
 	for _, tr := range a.ar.tasks {
 		tr.SetNetworkIsolation(n)
 	}
@@ -0,0 +1,235 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package allocrunner
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/go-hclog"
+	"github.com/open-wander/wander/client/serviceregistration"
+	"github.com/open-wander/wander/client/serviceregistration/checks/checkstore"
+	"github.com/open-wander/wander/helper"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+const (
+	// taskDependencyHookName is the name of this hook as appears in logs
+	taskDependencyHookName = "task_dependency"
+
+	// taskDependencyCheckInterval is the interval at which the checks of
+	// tasks that other tasks wait to be healthy are looked up.
+	taskDependencyCheckInterval = 500 * time.Millisecond
+)
+
+// taskHealthSetter is used to report the health of the service checks of a
+// task. It is implemented by the tasklifecycle.Coordinator.
+type taskHealthSetter interface {
+	TaskHealthUpdated(task string, healthy bool)
+}
+
+// taskDependencyHook watches the service checks of the tasks that sibling
+// tasks depend on with the "healthy" condition, and reports their health so
+// the dependent tasks are only started once the checks pass.
+type taskDependencyHook struct {
+	logger       hclog.Logger
+	allocID      string
+	setter       taskHealthSetter
+	consulClient serviceregistration.Handler
+	checkStore   checkstore.Shim
+
+	// tasks are the tasks whose health is watched.
+	tasks []*structs.Task
+
+	// interval is the interval at which checks are looked up.
+	interval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newTaskDependencyHook(
+	logger hclog.Logger,
+	alloc *structs.Allocation,
+	setter taskHealthSetter,
+	consulClient serviceregistration.Handler,
+	checkStore checkstore.Shim,
+) *taskDependencyHook {
+	h := &taskDependencyHook{
+		logger:       logger.Named(taskDependencyHookName),
+		allocID:      alloc.ID,
+		setter:       setter,
+		consulClient: consulClient,
+		checkStore:   checkStore,
+		interval:     taskDependencyCheckInterval,
+	}
+	h.ctx, h.cancel = context.WithCancel(context.Background())
+
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return h
+	}
+
+	watched := make(map[string]struct{})
+	for _, task := range tg.Tasks {
+		for _, dep := range task.DependsOn {
+			if dep.Condition == structs.TaskDependencyConditionHealthy {
+				watched[dep.Task] = struct{}{}
+			}
+		}
+	}
+	for _, task := range tg.Tasks {
+		if _, ok := watched[task.Name]; ok {
+			h.tasks = append(h.tasks, task)
+		}
+	}
+
+	return h
+}
+
+func (h *taskDependencyHook) Name() string {
+	return taskDependencyHookName
+}
+
+func (h *taskDependencyHook) Prerun() error {
+	if len(h.tasks) > 0 {
+		go h.watch()
+	}
+	return nil
+}
+
+func (h *taskDependencyHook) Postrun() error {
+	h.cancel()
+	return nil
+}
+
+func (h *taskDependencyHook) Shutdown() {
+	h.cancel()
+}
+
+// watch periodically reports the health of the watched tasks until the hook
+// is stopped.
+func (h *taskDependencyHook) watch() {
+	timer, stop := helper.NewSafeTimer(h.interval)
+	defer stop()
+
+	// Only log the first of consecutive Consul errors.
+	consulErr := false
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-timer.C:
+			timer.Reset(h.interval)
+		}
+
+		var allocReg *serviceregistration.AllocRegistration
+		if h.hasConsulChecks() {
+			reg, err := h.consulClient.AllocRegistrations(h.allocID)
+			if err != nil {
+				if !consulErr {
+					h.logger.Warn("error looking up Consul registrations for allocation", "error", err)
+				}
+				consulErr = true
+				continue
+			}
+			consulErr = false
+			allocReg = reg
+		}
+
+		var results map[structs.CheckID]*structs.CheckQueryResult
+		if h.checkStore != nil {
+			results = h.checkStore.List(h.allocID)
+		}
+
+		for _, task := range h.tasks {
+			h.setter.TaskHealthUpdated(task.Name, isTaskHealthy(task, allocReg, results))
+		}
+	}
+}
+
+// hasConsulChecks returns true if any of the watched tasks has Consul service
+// checks.
+func (h *taskDependencyHook) hasConsulChecks() bool {
+	for _, task := range h.tasks {
+		if consul, _ := countTaskChecks(task); consul > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isTaskHealthy returns true if all the Consul and Nomad service checks of
+// the task are registered and passing. Failing readiness checks are ignored.
+func isTaskHealthy(
+	task *structs.Task,
+	allocReg *serviceregistration.AllocRegistration,
+	results map[structs.CheckID]*structs.CheckQueryResult,
+) bool {
+	consulChecks, nomadChecks := countTaskChecks(task)
+
+	if consulChecks > 0 {
+		if allocReg == nil {
+			return false
+		}
+		taskReg, ok := allocReg.Tasks[task.Name]
+		if !ok || taskReg == nil {
+			return false
+		}
+
+		registered := 0
+		for _, service := range taskReg.Services {
+			for _, check := range service.Checks {
+				if check.Status != api.HealthPassing {
+					return false
+				}
+				registered++
+			}
+		}
+		if registered != consulChecks {
+			return false
+		}
+	}
+
+	if nomadChecks > 0 {
+		executed := 0
+		for _, result := range results {
+			if result.Task != task.Name {
+				continue
+			}
+			executed++
+
+			switch result.Status {
+			case structs.CheckSuccess:
+			case structs.CheckFailure:
+				if result.Mode != structs.Readiness {
+					return false
+				}
+			default:
+				return false
+			}
+		}
+		if executed != nomadChecks {
+			return false
+		}
+	}
+
+	return true
+}
+
+// countTaskChecks returns the number of Consul and Nomad service checks of
+// the task.
+func countTaskChecks(task *structs.Task) (consul, nomad int) {
+	for _, service := range task.Services {
+		switch service.Provider {
+		case structs.ServiceProviderNomad:
+			nomad += len(service.Checks)
+		default:
+			consul += len(service.Checks)
+		}
+	}
+	return
+}
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package allocrunner
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/client/allocrunner/interfaces"
+	"github.com/open-wander/wander/client/serviceregistration"
+	regMock "github.com/open-wander/wander/client/serviceregistration/mock"
+	"github.com/open-wander/wander/helper/testlog"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+var (
+	_ interfaces.RunnerPrerunHook  = (*taskDependencyHook)(nil)
+	_ interfaces.RunnerPostrunHook = (*taskDependencyHook)(nil)
+	_ interfaces.ShutdownHook      = (*taskDependencyHook)(nil)
+)
+
+// mockTaskHealthSetter records the health reported for each task.
+type mockTaskHealthSetter struct {
+	lock   sync.Mutex
+	health map[string]bool
+}
+
+func (m *mockTaskHealthSetter) TaskHealthUpdated(task string, healthy bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.health[task] = healthy
+}
+
+func (m *mockTaskHealthSetter) get(task string) (bool, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	healthy, ok := m.health[task]
+	return healthy, ok
+}
+
+func TestTaskDependencyHook_NomadChecks(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+	alloc := mock.Alloc()
+	tg := alloc.Job.TaskGroups[0]
+
+	db := tg.Tasks[0]
+	db.Name = "db"
+	db.Services = []*structs.Service{{
+		Name:     "db",
+		Provider: structs.ServiceProviderNomad,
+		Checks: []*structs.ServiceCheck{{
+			Name: "alive",
+			Type: structs.ServiceCheckTCP,
+		}},
+	}}
+
+	app := db.Copy()
+	app.Name = "app"
+	app.Services = nil
+	app.DependsOn = []*structs.TaskDependency{
+		{Task: db.Name, Condition: structs.TaskDependencyConditionHealthy},
+	}
+	tg.Tasks = append(tg.Tasks, app)
+
+	checkStore := makeCheckStore(logger)
+	setter := &mockTaskHealthSetter{health: make(map[string]bool)}
+
+	h := newTaskDependencyHook(logger, alloc, setter, regMock.NewServiceRegistrationHandler(logger), checkStore)
+	h.interval = 10 * time.Millisecond
+	must.Len(t, 1, h.tasks)
+	must.Eq(t, db.Name, h.tasks[0].Name)
+
+	must.NoError(t, h.Prerun())
+	defer func() { must.NoError(t, h.Postrun()) }()
+
+	// Without check results the task is unhealthy.
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool {
+			healthy, ok := setter.get(db.Name)
+			return ok && !healthy
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(10*time.Millisecond),
+	))
+
+	must.NoError(t, checkStore.Set(alloc.ID, &structs.CheckQueryResult{
+		ID:     "abc123",
+		Mode:   structs.Healthiness,
+		Status: structs.CheckSuccess,
+		Task:   db.Name,
+	}))
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool {
+			healthy, _ := setter.get(db.Name)
+			return healthy
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(10*time.Millisecond),
+	))
+
+	_, ok := setter.get(app.Name)
+	must.False(t, ok)
+}
+
+func TestTaskDependencyHook_isTaskHealthy(t *testing.T) {
+	ci.Parallel(t)
+
+	task := &structs.Task{
+		Name: "db",
+		Services: []*structs.Service{
+			{
+				Name: "db",
+				Checks: []*structs.ServiceCheck{
+					{Name: "alive"},
+					{Name: "ready"},
+				},
+			},
+		},
+	}
+
+	allocReg := func(statuses ...string) *serviceregistration.AllocRegistration {
+		checks := make([]*consulapi.AgentCheck, len(statuses))
+		for i, status := range statuses {
+			checks[i] = &consulapi.AgentCheck{Status: status}
+		}
+		return &serviceregistration.AllocRegistration{
+			Tasks: map[string]*serviceregistration.ServiceRegistrations{
+				task.Name: {
+					Services: map[string]*serviceregistration.ServiceRegistration{
+						"db": {Checks: checks},
+					},
+				},
+			},
+		}
+	}
+
+	must.False(t, isTaskHealthy(task, nil, nil))
+	must.False(t, isTaskHealthy(task, allocReg(consulapi.HealthPassing), nil))
+	must.False(t, isTaskHealthy(task, allocReg(consulapi.HealthPassing, consulapi.HealthCritical), nil))
+	must.True(t, isTaskHealthy(task, allocReg(consulapi.HealthPassing, consulapi.HealthPassing), nil))
+
+	// Failing readiness checks are ignored for Nomad services.
+	task.Services[0].Provider = structs.ServiceProviderNomad
+	results := map[structs.CheckID]*structs.CheckQueryResult{
+		"a": {Task: task.Name, Mode: structs.Healthiness, Status: structs.CheckSuccess},
+		"b": {Task: task.Name, Mode: structs.Readiness, Status: structs.CheckFailure},
+		"c": {Task: "other", Mode: structs.Healthiness, Status: structs.CheckFailure},
+	}
+	must.True(t, isTaskHealthy(task, nil, results))
+
+	results["a"].Status = structs.CheckPending
+	must.False(t, isTaskHealthy(task, nil, results))
+}
@@ -94,6 +94,18 @@ type Coordinator struct {
 
 	// gates store the gates that control each task lifecycle stage.
 	gates map[lifecycleStage]*Gate
+
+	// dependencies maps the name of each main task that declares
+	// dependencies to the sibling tasks it depends on. These tasks have their
+	// own gate in taskGates, which is only opened when the main tasks are
+	// allowed to run and their dependencies are met.
+	dependencies map[string][]*structs.TaskDependency
+	taskGates    map[string]*Gate
+
+	// taskStates and taskHealth are the latest known state and health of
+	// each task. They must only be accessed while holding the lock.
+	taskStates map[string]*structs.TaskState
+	taskHealth map[string]bool
 }
 
 // NewCoordinator returns a new Coordinator with all tasks initially blocked.
@@ -102,12 +114,24 @@ func NewCoordinator(logger hclog.Logger, tasks []*structs.Task, shutdownCh <-cha
 		logger:           logger.Named("task_coordinator"),
 		tasksByLifecycle: indexTasksByLifecycle(tasks),
 		gates:            make(map[lifecycleStage]*Gate),
+		dependencies:     make(map[string][]*structs.TaskDependency),
+		taskGates:        make(map[string]*Gate),
+		taskStates:       make(map[string]*structs.TaskState),
+		taskHealth:       make(map[string]bool),
 	}
 
 	for lifecycle := range c.tasksByLifecycle {
 		c.gates[lifecycle] = NewGate(shutdownCh)
 	}
 
+	for _, task := range tasks {
+		if len(task.DependsOn) == 0 || taskLifecycleStage(task) != lifecycleStageMain {
+			continue
+		}
+		c.dependencies[task.Name] = task.DependsOn
+		c.taskGates[task.Name] = NewGate(shutdownCh)
+	}
+
 	c.enterStateLocked(coordinatorStateInit)
 	return c
 }
@@ -118,6 +142,7 @@ func NewCoordinator(logger hclog.Logger, tasks []*structs.Task, shutdownCh <-cha
 func (c *Coordinator) Restart() {
 	c.currentStateLock.Lock()
 	defer c.currentStateLock.Unlock()
+	c.taskHealth = make(map[string]bool)
 	c.enterStateLocked(coordinatorStateInit)
 }
 
@@ -134,6 +159,9 @@ func (c *Coordinator) Restore(states map[string]*structs.TaskState) {
 // StartConditionForTask returns a channel that is unblocked when the task is
 // allowed to run.
 func (c *Coordinator) StartConditionForTask(task *structs.Task) <-chan struct{} {
+	if gate, ok := c.taskGates[task.Name]; ok {
+		return gate.WaitCh()
+	}
 	lifecycle := taskLifecycleStage(task)
 	return c.gates[lifecycle].WaitCh()
 }
@@ -144,6 +172,8 @@ func (c *Coordinator) TaskStateUpdated(states map[string]*structs.TaskState) {
 	c.currentStateLock.Lock()
 	defer c.currentStateLock.Unlock()
 
+	c.taskStates = states
+
 	// We may be able to move directly through some states (for example, when
 	// an alloc doesn't have any prestart task we can skip the prestart state),
 	// so loop until we stabilize.
@@ -152,11 +182,52 @@ func (c *Coordinator) TaskStateUpdated(states map[string]*structs.TaskState) {
 	for {
 		nextState := c.nextStateLocked(states)
 		if nextState == c.currentState {
-			return
+			break
 		}
 
 		c.enterStateLocked(nextState)
 	}
+
+	c.updateTaskGatesLocked()
+}
+
+// TaskHealthUpdated notifies that the health of a task's service checks has
+// changed. This may allow tasks that depend on it to start.
+func (c *Coordinator) TaskHealthUpdated(task string, healthy bool) {
+	c.currentStateLock.Lock()
+	defer c.currentStateLock.Unlock()
+
+	if c.taskHealth[task] == healthy {
+		return
+	}
+	c.taskHealth[task] = healthy
+	c.updateTaskGatesLocked()
+}
+
+// UnmetTaskDependency returns the name of a task that is still waiting on a
+// dependency that can no longer be met, because the dependency is dead, along
+// with the name of that dependency. Empty strings are returned if there is no
+// such task.
+func (c *Coordinator) UnmetTaskDependency(states map[string]*structs.TaskState) (string, string) {
+	c.currentStateLock.RLock()
+	defer c.currentStateLock.RUnlock()
+
+	for task, deps := range c.dependencies {
+		if state := states[task]; state == nil || state.State != structs.TaskStatePending {
+			continue
+		}
+
+		for _, dep := range deps {
+			depState := states[dep.Task]
+			if depState == nil || depState.State != structs.TaskStateDead {
+				continue
+			}
+			if !c.isDependencyMetLocked(dep, states) {
+				return task, dep.Task
+			}
+		}
+	}
+	return "", ""
 }
 
 // nextStateLocked returns the state the FSM should transition to given its
@@ -272,6 +343,70 @@ func (c *Coordinator) enterStateLocked(state coordinatorState) {
 	}
 
 	c.currentState = state
+	c.updateTaskGatesLocked()
+}
+
+// updateTaskGatesLocked opens the gate of each task with dependencies if main
+// tasks are allowed to run and all of its dependencies are met, and closes it
+// otherwise. Tasks that are already running are not blocked by their
+// dependencies, so they can be restored.
+// The currentStateLock must be held before calling this method.
+func (c *Coordinator) updateTaskGatesLocked() {
+	mainAllowed := false
+	switch c.currentState {
+	case coordinatorStateMain, coordinatorStatePoststart, coordinatorStateWaitAlloc:
+		mainAllowed = true
+	}
+
+	for task, gate := range c.taskGates {
+		if !mainAllowed {
+			gate.Close()
+			continue
+		}
+
+		state := c.taskStates[task]
+		if (state != nil && state.State == structs.TaskStateRunning) || c.areDependenciesMetLocked(task) {
+			gate.Open()
+		} else {
+			gate.Close()
+		}
+	}
+}
+
+// areDependenciesMetLocked returns true if all the dependencies of the given
+// task are met.
+// The currentStateLock must be held before calling this method.
+func (c *Coordinator) areDependenciesMetLocked(task string) bool {
+	for _, dep := range c.dependencies[task] {
+		if !c.isDependencyMetLocked(dep, c.taskStates) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDependencyMetLocked returns true if the task the dependency refers to
+// meets its condition:
+//   - started: the task is running or has run.
+//   - healthy: the task is running and its service checks are passing.
+//   - complete: the task completed successfully.
+//
+// The currentStateLock must be held before calling this method.
+func (c *Coordinator) isDependencyMetLocked(dep *structs.TaskDependency, states map[string]*structs.TaskState) bool {
+	state := states[dep.Task]
+	if state == nil {
+		return false
+	}
+
+	switch dep.Condition {
+	case structs.TaskDependencyConditionHealthy:
+		return state.State == structs.TaskStateRunning && c.taskHealth[dep.Task]
+	case structs.TaskDependencyConditionComplete:
+		return state.Successful()
+	default:
+		return state.State == structs.TaskStateRunning ||
+			(state.State == structs.TaskStateDead && !state.StartedAt.IsZero())
+	}
 }
 
 // isInitDone returns true when the following conditions are met:
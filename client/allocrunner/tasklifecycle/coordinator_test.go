@@ -11,6 +11,7 @@ import (
 	"github.com/open-wander/wander/helper/testlog"
 	"github.com/open-wander/wander/nomad/mock"
 	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
 )
 
 func TestCoordinator_OnlyMainApp(t *testing.T) {
@@ -561,3 +562,125 @@ func TestCoordinator_Restore(t *testing.T) {
 		})
 	}
 }
+
+func TestCoordinator_TaskDependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+	task := mock.Job().TaskGroups[0].Tasks[0]
+
+	db := task.Copy()
+	db.Name = "db"
+
+	app := task.Copy()
+	app.Name = "app"
+	app.DependsOn = []*structs.TaskDependency{
+		{Task: db.Name, Condition: structs.TaskDependencyConditionHealthy},
+	}
+
+	web := task.Copy()
+	web.Name = "web"
+	web.DependsOn = []*structs.TaskDependency{
+		{Task: app.Name, Condition: structs.TaskDependencyConditionStarted},
+	}
+
+	migrate := task.Copy()
+	migrate.Name = "migrate"
+
+	worker := task.Copy()
+	worker.Name = "worker"
+	worker.DependsOn = []*structs.TaskDependency{
+		{Task: migrate.Name, Condition: structs.TaskDependencyConditionComplete},
+	}
+
+	tasks := []*structs.Task{db, app, web, migrate, worker}
+
+	shutdownCh := make(chan struct{})
+	defer close(shutdownCh)
+	coord := NewCoordinator(logger, tasks, shutdownCh)
+
+	// All tasks start blocked.
+	for _, task := range tasks {
+		RequireTaskBlocked(t, coord, task)
+	}
+
+	// Only tasks without dependencies are allowed to start.
+	states := map[string]*structs.TaskState{
+		db.Name:      {State: structs.TaskStatePending},
+		app.Name:     {State: structs.TaskStatePending},
+		web.Name:     {State: structs.TaskStatePending},
+		migrate.Name: {State: structs.TaskStatePending},
+		worker.Name:  {State: structs.TaskStatePending},
+	}
+	coord.TaskStateUpdated(states)
+	RequireTaskAllowed(t, coord, db)
+	RequireTaskAllowed(t, coord, migrate)
+	RequireTaskBlocked(t, coord, app)
+	RequireTaskBlocked(t, coord, web)
+	RequireTaskBlocked(t, coord, worker)
+
+	// Running is not enough for the healthy condition.
+	states[db.Name] = &structs.TaskState{State: structs.TaskStateRunning}
+	states[migrate.Name] = &structs.TaskState{State: structs.TaskStateRunning}
+	coord.TaskStateUpdated(states)
+	RequireTaskBlocked(t, coord, app)
+	RequireTaskBlocked(t, coord, worker)
+
+	coord.TaskHealthUpdated(db.Name, true)
+	RequireTaskAllowed(t, coord, app)
+	RequireTaskBlocked(t, coord, web)
+
+	// Dependents wait for the task to be healthy again.
+	coord.TaskHealthUpdated(db.Name, false)
+	RequireTaskBlocked(t, coord, app)
+	coord.TaskHealthUpdated(db.Name, true)
+
+	states[app.Name] = &structs.TaskState{State: structs.TaskStateRunning}
+	coord.TaskStateUpdated(states)
+	RequireTaskAllowed(t, coord, web)
+
+	// The complete condition requires the task to succeed.
+	states[migrate.Name] = &structs.TaskState{State: structs.TaskStateDead, Failed: true}
+	coord.TaskStateUpdated(states)
+	RequireTaskBlocked(t, coord, worker)
+
+	blocked, dep := coord.UnmetTaskDependency(states)
+	must.Eq(t, worker.Name, blocked)
+	must.Eq(t, migrate.Name, dep)
+
+	states[migrate.Name] = &structs.TaskState{State: structs.TaskStateDead}
+	coord.TaskStateUpdated(states)
+	RequireTaskAllowed(t, coord, worker)
+
+	blocked, dep = coord.UnmetTaskDependency(states)
+	must.Eq(t, "", blocked)
+	must.Eq(t, "", dep)
+}
+
+func TestCoordinator_TaskDependencies_Restore(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+	task := mock.Job().TaskGroups[0].Tasks[0]
+
+	db := task.Copy()
+	db.Name = "db"
+
+	app := task.Copy()
+	app.Name = "app"
+	app.DependsOn = []*structs.TaskDependency{
+		{Task: db.Name, Condition: structs.TaskDependencyConditionHealthy},
+	}
+
+	shutdownCh := make(chan struct{})
+	defer close(shutdownCh)
+	coord := NewCoordinator(logger, []*structs.Task{db, app}, shutdownCh)
+
+	// Running tasks are not blocked by their dependencies when restored.
+	coord.Restore(map[string]*structs.TaskState{
+		db.Name:  {State: structs.TaskStateRunning},
+		app.Name: {State: structs.TaskStateRunning},
+	})
+	RequireTaskAllowed(t, coord, db)
+	RequireTaskAllowed(t, coord, app)
+}
@@ -17,6 +17,11 @@ The allocRunner feeds task state updates to the Coordinator that then uses this
 information to determine which Gates it should open or close. Each Gate is
 connected to a taskRunner with a matching lifecycle configuration.
 
+Main tasks that declare dependencies on sibling tasks are connected to a Gate of
+their own instead. The Coordinator only opens it while main tasks are allowed
+to run and the dependencies of the task meet their conditions, based on the
+task states and on the health of service checks reported by the allocRunner.
+
 In the diagrams below, a solid line from a Gate indicates that it's open
 (active), while a dashed line indicates that it's closed (inactive). A
 taskRunner connected to an open Gate is allowed to run, while one that is
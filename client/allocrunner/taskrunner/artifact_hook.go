@@ -5,11 +5,13 @@ package taskrunner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/open-wander/wander/client/allocrunner/interfaces"
+	"github.com/open-wander/wander/client/allocrunner/taskrunner/getter"
 	ti "github.com/open-wander/wander/client/allocrunner/taskrunner/interfaces"
 	ci "github.com/open-wander/wander/client/interfaces"
 	"github.com/open-wander/wander/nomad/structs"
@@ -20,12 +22,21 @@ type artifactHook struct {
 	eventEmitter ti.EventEmitter
 	logger       log.Logger
 	getter       ci.ArtifactGetter
+
+	// variables reads the Variables holding the trusted keys of artifact
+	// signatures
+	variables variableReader
 }
 
-func newArtifactHook(e ti.EventEmitter, getter ci.ArtifactGetter, logger log.Logger) *artifactHook {
+// variableReader returns the items of the Variable at the path, read with
+// the identity of the task.
+type variableReader func(path string) (map[string]string, error)
+
+func newArtifactHook(e ti.EventEmitter, getter ci.ArtifactGetter, variables variableReader, logger log.Logger) *artifactHook {
 	h := &artifactHook{
 		eventEmitter: e,
 		getter:       getter,
+		variables:    variables,
 	}
 	h.logger = logger.Named(h.Name())
 	return h
@@ -45,7 +56,32 @@ func (h *artifactHook) doWork(req *interfaces.TaskPrestartRequest, resp *interfa
 
 		h.logger.Debug("downloading artifact", "artifact", artifact.GetterSource, "aid", aid)
 
-		if err := h.getter.Get(req.TaskEnv, artifact); err != nil {
+		keys, err := h.trustedKeys(artifact)
+		if err != nil {
+			wrapped := structs.NewRecoverableError(
+				fmt.Errorf("failed to verify artifact %q: %v", artifact.GetterSource, err),
+				true,
+			)
+			herr := NewHookError(wrapped, structs.NewTaskEvent(structs.TaskArtifactVerificationFailed).SetDownloadError(wrapped))
+
+			errorChannel <- herr
+			continue
+		}
+
+		if err := h.getter.Get(req.TaskEnv, artifact, keys); err != nil {
+			if errors.Is(err, getter.ErrVerificationFailed) {
+				// Downloading the artifact again cannot make it trusted
+				wrapped := structs.NewRecoverableError(
+					fmt.Errorf("failed to verify artifact %q: %v", artifact.GetterSource, err),
+					false,
+				)
+				herr := NewHookError(wrapped, structs.NewTaskEvent(structs.TaskArtifactVerificationFailed).
+					SetDownloadError(wrapped).SetFailsTask())
+
+				errorChannel <- herr
+				continue
+			}
+
 			wrapped := structs.NewRecoverableError(
 				fmt.Errorf("failed to download artifact %q: %v", artifact.GetterSource, err),
 				true,
@@ -65,6 +101,19 @@ func (h *artifactHook) doWork(req *interfaces.TaskPrestartRequest, resp *interfa
 	}
 }
 
+// trustedKeys returns the keys of the Variable of the signature of the
+// artifact, if any.
+func (h *artifactHook) trustedKeys(artifact *structs.TaskArtifact) (map[string]string, error) {
+	sig := artifact.Signature
+	if sig == nil || sig.Variable == "" {
+		return nil, nil
+	}
+	if h.variables == nil {
+		return nil, fmt.Errorf("cannot read trusted keys of Variable %q", sig.Variable)
+	}
+	return h.variables(sig.Variable)
+}
+
 func (*artifactHook) Name() string {
 	// Copied in client/state when upgrading from <0.9 schemas, so if you
 	// change it here you also must change it there.
@@ -127,3 +176,29 @@ func (h *artifactHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 	resp.Done = true
 	return nil
 }
+
+// readVariableItems returns the items of the Variable at the path in the
+// namespace of the allocation, read with the Nomad token of the task.
+func (tr *TaskRunner) readVariableItems(path string) (map[string]string, error) {
+	if tr.rpcClient == nil {
+		return nil, fmt.Errorf("failed to read Variable %q: no RPC client", path)
+	}
+
+	args := &structs.VariablesReadRequest{
+		Path: path,
+		QueryOptions: structs.QueryOptions{
+			Region:     tr.clientConfig.Region,
+			Namespace:  tr.Alloc().Namespace,
+			AllowStale: true,
+			AuthToken:  tr.getNomadToken(),
+		},
+	}
+	var reply structs.VariablesReadResponse
+	if err := tr.rpcClient.RPC(structs.VariablesReadRPCMethod, args, &reply); err != nil {
+		return nil, fmt.Errorf("failed to read Variable %q: %w", path, err)
+	}
+	if reply.Data == nil {
+		return nil, fmt.Errorf("variable %q not found", path)
+	}
+	return reply.Data.Items, nil
+}
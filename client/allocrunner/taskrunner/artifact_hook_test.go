@@ -17,6 +17,7 @@ import (
 	"github.com/open-wander/wander/client/allocdir"
 	"github.com/open-wander/wander/client/allocrunner/interfaces"
 	"github.com/open-wander/wander/client/allocrunner/taskrunner/getter"
+	cinterfaces "github.com/open-wander/wander/client/interfaces"
 	"github.com/open-wander/wander/client/taskenv"
 	"github.com/open-wander/wander/client/testutil"
 	"github.com/open-wander/wander/helper/testlog"
@@ -43,7 +44,7 @@ func TestTaskRunner_ArtifactHook_Recoverable(t *testing.T) {
 
 	me := &mockEmitter{}
 	sbox := getter.TestSandbox(t)
-	artifactHook := newArtifactHook(me, sbox, testlog.HCLogger(t))
+	artifactHook := newArtifactHook(me, sbox, nil, testlog.HCLogger(t))
 
 	req := &interfaces.TaskPrestartRequest{
 		TaskEnv: taskenv.NewEmptyTaskEnv(),
@@ -69,6 +70,78 @@ func TestTaskRunner_ArtifactHook_Recoverable(t *testing.T) {
 	require.Equal(t, structs.TaskDownloadingArtifacts, me.events[0].Type)
 }
 
+// mockArtifactGetter records the trusted keys of the artifacts and returns
+// err.
+type mockArtifactGetter struct {
+	keys []map[string]string
+	err  error
+}
+
+func (m *mockArtifactGetter) Get(_ cinterfaces.EnvReplacer, _ *structs.TaskArtifact, keys map[string]string) error {
+	m.keys = append(m.keys, keys)
+	return m.err
+}
+
+// TestTaskRunner_ArtifactHook_Verification asserts that artifacts failing
+// signature verification fail the task, and that the trusted keys of the
+// Variable of the signature are passed to the getter.
+func TestTaskRunner_ArtifactHook_Verification(t *testing.T) {
+	ci.Parallel(t)
+
+	req := &interfaces.TaskPrestartRequest{
+		TaskEnv: taskenv.NewEmptyTaskEnv(),
+		TaskDir: &allocdir.TaskDir{Dir: os.TempDir()},
+		Task: &structs.Task{
+			Artifacts: []*structs.TaskArtifact{
+				{
+					GetterSource: "https://example.com/app.tar.gz",
+					GetterMode:   structs.GetterModeAny,
+					Signature: &structs.ArtifactSignature{
+						Source:   "https://example.com/app.tar.gz.minisig",
+						Format:   "minisign",
+						Variable: "nomad/jobs/example/keys",
+					},
+				},
+			},
+		},
+	}
+
+	var paths []string
+	variables := func(path string) (map[string]string, error) {
+		paths = append(paths, path)
+		return map[string]string{"release": "RWQ"}, nil
+	}
+
+	mg := &mockArtifactGetter{
+		err: &getter.Error{
+			Err:         fmt.Errorf("%w: signature was not made by a trusted key", getter.ErrVerificationFailed),
+			Recoverable: false,
+		},
+	}
+	artifactHook := newArtifactHook(&mockEmitter{}, mg, variables, testlog.HCLogger(t))
+
+	err := artifactHook.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{})
+	require.ErrorContains(t, err, "signature was not made by a trusted key")
+	require.False(t, structs.IsRecoverable(err))
+	require.Equal(t, []string{"nomad/jobs/example/keys"}, paths)
+	require.Equal(t, []map[string]string{{"release": "RWQ"}}, mg.keys)
+
+	herr, ok := err.(*hookError)
+	require.True(t, ok)
+	require.Equal(t, structs.TaskArtifactVerificationFailed, herr.taskEvent.Type)
+	require.True(t, herr.taskEvent.FailsTask)
+
+	// Failing to read the Variable is recoverable
+	variables = func(path string) (map[string]string, error) {
+		return nil, fmt.Errorf("variable %q not found", path)
+	}
+	artifactHook = newArtifactHook(&mockEmitter{}, &mockArtifactGetter{}, variables, testlog.HCLogger(t))
+
+	err = artifactHook.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{})
+	require.ErrorContains(t, err, "not found")
+	require.True(t, structs.IsRecoverable(err))
+}
+
 // TestTaskRunnerArtifactHook_PartialDone asserts that the artifact hook skips
 // already downloaded artifacts when subsequent artifacts fail and cause a
 // restart.
@@ -78,7 +151,7 @@ func TestTaskRunner_ArtifactHook_PartialDone(t *testing.T) {
 
 	me := &mockEmitter{}
 	sbox := getter.TestSandbox(t)
-	artifactHook := newArtifactHook(me, sbox, testlog.HCLogger(t))
+	artifactHook := newArtifactHook(me, sbox, nil, testlog.HCLogger(t))
 
 	// Create a source directory with 1 of the 2 artifacts
 	srcdir := t.TempDir()
@@ -168,7 +241,7 @@ func TestTaskRunner_ArtifactHook_ConcurrentDownloadSuccess(t *testing.T) {
 
 	me := &mockEmitter{}
 	sbox := getter.TestSandbox(t)
-	artifactHook := newArtifactHook(me, sbox, testlog.HCLogger(t))
+	artifactHook := newArtifactHook(me, sbox, nil, testlog.HCLogger(t))
 
 	// Create a source directory all 7 artifacts
 	srcdir := t.TempDir()
@@ -256,7 +329,7 @@ func TestTaskRunner_ArtifactHook_ConcurrentDownloadFailure(t *testing.T) {
 
 	me := &mockEmitter{}
 	sbox := getter.TestSandbox(t)
-	artifactHook := newArtifactHook(me, sbox, testlog.HCLogger(t))
+	artifactHook := newArtifactHook(me, sbox, nil, testlog.HCLogger(t))
 
 	// Create a source directory with 3 of the 4 artifacts
 	srcdir := t.TempDir()
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package taskrunner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/open-wander/wander/plugins/drivers"
+)
+
+// runDeadline returns the time at which the current run of the task must be
+// killed and the type of the event emitted when it is: either the end of its
+// max run duration or the deadline of its dispatched job, whichever comes
+// first. The zero time is returned if the task may run forever.
+func (tr *TaskRunner) runDeadline() (time.Time, string) {
+	var deadline time.Time
+	var eventType string
+
+	if d := tr.Task().MaxRunDuration; d > 0 {
+		startedAt := tr.TaskState().StartedAt
+		if startedAt.IsZero() {
+			startedAt = time.Now()
+		}
+		deadline = startedAt.Add(d)
+		eventType = structs.TaskRunDurationExceeded
+	}
+
+	// Poststop tasks may still clean up once the deadline of the job passed
+	if tr.IsPoststopTask() {
+		return deadline, eventType
+	}
+
+	if jobDeadline := tr.Alloc().Job.DispatchDeadline; !jobDeadline.IsZero() &&
+		(deadline.IsZero() || jobDeadline.Before(deadline)) {
+		deadline = jobDeadline
+		eventType = structs.TaskDeadlineExceeded
+	}
+
+	return deadline, eventType
+}
+
+// jobDeadlineExceeded returns true if the task must not be started as the
+// deadline of its dispatched job has passed.
+func (tr *TaskRunner) jobDeadlineExceeded() bool {
+	return !tr.IsPoststopTask() && tr.Alloc().Job.DeadlineExceeded(time.Now())
+}
+
+// deadlineEvent returns the event of the task running out of time.
+func (tr *TaskRunner) deadlineEvent(eventType string) *structs.TaskEvent {
+	event := structs.NewTaskEvent(eventType)
+	switch eventType {
+	case structs.TaskDeadlineExceeded:
+		deadline := tr.Alloc().Job.DispatchDeadline
+		event.SetMessage(fmt.Sprintf("Task exceeded the deadline of its job at %s",
+			deadline.UTC().Format(time.RFC3339)))
+	default:
+		event.SetMessage(fmt.Sprintf("Task exceeded its max run duration of %v",
+			tr.Task().MaxRunDuration))
+	}
+	return event
+}
+
+// handleRunDeadline kills the task with its kill signal once it ran out of
+// time and returns its exit result. The restart tracker is told so that the
+// restart policy decides whether to run it again.
+func (tr *TaskRunner) handleRunDeadline(resultCh <-chan *drivers.ExitResult, eventType string) *drivers.ExitResult {
+	if eventType == structs.TaskDeadlineExceeded {
+		tr.restartTracker.SetDeadlineExceeded()
+	} else {
+		tr.restartTracker.SetTimedOut()
+	}
+
+	event := tr.deadlineEvent(eventType)
+	tr.logger.Info("killing task that ran out of time", "reason", event.Message)
+	tr.EmitEvent(event)
+
+	// Run the pre-kill hooks so that services are deregistered before the
+	// task is killed
+	tr.preKill()
+
+	handle := tr.getDriverHandle()
+	if handle == nil {
+		return nil
+	}
+
+	// Kill the task using an exponential backoff in-case of failures.
+	result, err := tr.killTask(handle, resultCh)
+	if err != nil {
+		tr.logger.Error("failed to kill task. Resources may have been leaked", "error", err)
+	}
+	if result != nil {
+		return result
+	}
+
+	select {
+	case result := <-resultCh:
+		return result
+	case <-tr.shutdownCtx.Done():
+		return nil
+	}
+}
@@ -0,0 +1,771 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/go-hclog"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+const (
+	// cacheContent and cacheMeta are the names of the content and of the
+	// metadata of an artifact in its cache entry directory.
+	cacheContent = "content"
+	cacheMeta    = "meta.json"
+
+	// cacheTmpPrefix prefixes the entry directories being added to the
+	// cache, which are removed when the cache is loaded.
+	cacheTmpPrefix = ".tmp-"
+
+	// cacheStagePrefix prefixes the directories of the task directory into
+	// which cacheable artifacts are downloaded before being cached, as the
+	// sandboxed download may only write into the task directory.
+	cacheStagePrefix = ".nomad-artifact-"
+)
+
+var (
+	// ErrCacheEntryNotFound is returned when purging an artifact missing
+	// from the cache.
+	ErrCacheEntryNotFound = errors.New("artifact not found in cache")
+
+	// ErrCacheEntryInUse is returned when purging an artifact being placed
+	// into a task directory.
+	ErrCacheEntryInUse = errors.New("artifact is in use")
+)
+
+// Cache is a content-addressed cache of the artifacts downloaded by the
+// allocations of a client. Only artifacts whose content is pinned by a
+// checksum are cached, keyed by their source, getter mode and headers. Cached
+// artifacts are reflinked or copied into the task directories, or hardlinked
+// if the artifact may be read-only, in which case they are verified against
+// the digest of their content before each later use. The least recently used
+// artifacts are evicted once the cache exceeds its maximum size.
+type Cache struct {
+	logger  hclog.Logger
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	size    int64
+	hits    uint64
+	misses  uint64
+
+	// inflight holds a channel per artifact being downloaded into the
+	// cache, closed once the download is done.
+	inflight map[string]chan struct{}
+}
+
+// cacheEntry is an artifact of the cache.
+type cacheEntry struct {
+	*structs.ArtifactCacheEntry
+
+	// digest is the digest of the content of the artifact, verified before
+	// placing it as hardlinked files may have been modified by tasks.
+	digest string
+
+	// linked is set once files of the artifact have been hardlinked into a
+	// task directory. Other entries can't be modified by the tasks, so they
+	// are not verified.
+	linked bool
+
+	// modes are the permissions of the regular files of the artifact before
+	// they were made read-only in the cache, keyed by relative path.
+	modes map[string]fs.FileMode
+
+	// users is the number of tasks the artifact is being placed for. Entries
+	// in use are not evicted.
+	users int
+
+	// discarded is set once the entry is removed from the cache while in use.
+	// Its directory is removed when the last task releases it.
+	discarded bool
+}
+
+// cacheEntryMeta is the metadata of an entry stored in its directory.
+type cacheEntryMeta struct {
+	*structs.ArtifactCacheEntry
+	Digest string
+	Modes  map[string]fs.FileMode `json:",omitempty"`
+	Linked bool                   `json:",omitempty"`
+}
+
+// NewCache returns the artifact cache stored in the directory, bounded to
+// maxSize bytes. Entries left by a previous run of the client are kept.
+func NewCache(dir string, maxSize int64, logger hclog.Logger) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create artifact cache dir: %w", err)
+	}
+
+	c := &Cache{
+		logger:   logger.Named("artifact_cache"),
+		dir:      dir,
+		maxSize:  maxSize,
+		entries:  make(map[string]*cacheEntry),
+		inflight: make(map[string]chan struct{}),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	// The cache may have been shrunk since the previous run
+	c.mu.Lock()
+	c.evict()
+	c.mu.Unlock()
+	return c, nil
+}
+
+// load indexes the entries of the cache directory, removing the incomplete
+// and invalid ones.
+func (c *Cache) load() error {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read artifact cache dir: %w", err)
+	}
+
+	for _, d := range dirEntries {
+		path := filepath.Join(c.dir, d.Name())
+		if !d.IsDir() || strings.HasPrefix(d.Name(), cacheTmpPrefix) {
+			os.RemoveAll(path)
+			continue
+		}
+
+		meta, err := readCacheMeta(path)
+		switch {
+		case err != nil:
+		case meta.ArtifactCacheEntry == nil || meta.Key != d.Name():
+			err = errors.New("key mismatch")
+		case meta.Digest == "":
+			err = errors.New("missing digest")
+		}
+		if err == nil {
+			_, err = os.Lstat(filepath.Join(path, cacheContent))
+		}
+		if err != nil {
+			c.logger.Warn("removing invalid cache entry", "key", d.Name(), "error", err)
+			os.RemoveAll(path)
+			continue
+		}
+
+		c.entries[meta.Key] = &cacheEntry{
+			ArtifactCacheEntry: meta.ArtifactCacheEntry,
+			digest:             meta.Digest,
+			modes:              meta.Modes,
+			linked:             meta.Linked,
+		}
+		c.size += meta.Size
+	}
+	return nil
+}
+
+// acquire returns the entry of the key and marks it in use, waiting for the
+// artifact to be downloaded if another task is already downloading it. On a
+// miss it returns nil, and the caller must download the artifact and then
+// call done.
+func (c *Cache) acquire(key string) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		if e, ok := c.entries[key]; ok {
+			e.users++
+			e.Hits++
+			e.LastUseTime = time.Now().UnixNano()
+			c.hits++
+			metrics.IncrCounter([]string{"client", "artifact_cache", "hit"}, 1)
+			return e
+		}
+
+		ch, ok := c.inflight[key]
+		if !ok {
+			break
+		}
+		c.mu.Unlock()
+		<-ch
+		c.mu.Lock()
+	}
+
+	c.inflight[key] = make(chan struct{})
+	c.misses++
+	metrics.IncrCounter([]string{"client", "artifact_cache", "miss"}, 1)
+	return nil
+}
+
+// done wakes up the tasks waiting for the artifact of the key to be
+// downloaded.
+func (c *Cache) done(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ch, ok := c.inflight[key]; ok {
+		close(ch)
+		delete(c.inflight, key)
+	}
+}
+
+// release marks the entry as no longer in use and persists its usage.
+func (c *Cache) release(e *cacheEntry) {
+	c.mu.Lock()
+	e.users--
+	meta := c.meta(e)
+	cached := c.entries[e.Key] == e
+	c.removeDiscarded(e)
+	c.evict()
+	c.mu.Unlock()
+
+	if !cached {
+		return
+	}
+	if err := writeCacheMeta(filepath.Join(c.dir, e.Key), meta); err != nil {
+		c.logger.Warn("failed to update cache entry", "key", e.Key, "error", err)
+	}
+}
+
+// meta returns a copy of the metadata of the entry. The lock must be held.
+func (c *Cache) meta(e *cacheEntry) *cacheEntryMeta {
+	entry := *e.ArtifactCacheEntry
+	return &cacheEntryMeta{
+		ArtifactCacheEntry: &entry,
+		Digest:             e.digest,
+		Modes:              e.modes,
+		Linked:             e.linked,
+	}
+}
+
+// add moves the downloaded artifact at path into the cache and returns its
+// entry marked in use. On error the artifact is left at path.
+func (c *Cache) add(key, path string, entry *structs.ArtifactCacheEntry) (*cacheEntry, error) {
+	size, err := treeSize(path)
+	if err != nil {
+		return nil, err
+	}
+	if size > c.maxSize {
+		return nil, fmt.Errorf("artifact size %s exceeds the cache size %s",
+			humanize.IBytes(uint64(size)), humanize.IBytes(uint64(c.maxSize)))
+	}
+
+	tmp, err := os.MkdirTemp(c.dir, cacheTmpPrefix)
+	if err != nil {
+		return nil, err
+	}
+	content := filepath.Join(tmp, cacheContent)
+	if err := os.Rename(path, content); err != nil {
+		// The task directory is on another filesystem
+		if err := placeTree(path, content, copyFile); err != nil {
+			os.RemoveAll(tmp)
+			return nil, err
+		}
+	}
+
+	now := time.Now().UnixNano()
+	entry.Key = key
+	entry.Size = size
+	entry.CreateTime = now
+	entry.LastUseTime = now
+
+	// Cached files may be hardlinked into task directories, so they must not
+	// be modified by the tasks.
+	meta := &cacheEntryMeta{ArtifactCacheEntry: entry}
+	meta.Digest, err = treeDigest(content)
+	if err == nil {
+		meta.Modes, err = readOnlyTree(content)
+	}
+	if err == nil {
+		err = writeCacheMeta(tmp, meta)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil {
+		err = os.Rename(tmp, filepath.Join(c.dir, key))
+	}
+	if err != nil {
+		if _, statErr := os.Lstat(path); errors.Is(statErr, fs.ErrNotExist) {
+			os.Rename(content, path)
+		}
+		os.RemoveAll(tmp)
+		return nil, err
+	}
+
+	e := &cacheEntry{
+		ArtifactCacheEntry: entry,
+		digest:             meta.Digest,
+		modes:              meta.Modes,
+		users:              1,
+	}
+	c.entries[key] = e
+	c.size += size
+	c.evict()
+	return e, nil
+}
+
+// verify returns an error if the content of the entry doesn't match its
+// digest. Entries that were never hardlinked into a task directory are not
+// verified.
+func (c *Cache) verify(e *cacheEntry) error {
+	c.mu.Lock()
+	linked := e.linked
+	c.mu.Unlock()
+	if !linked {
+		return nil
+	}
+
+	digest, err := treeDigest(filepath.Join(c.dir, e.Key, cacheContent))
+	if err != nil {
+		return err
+	}
+	if digest != e.digest {
+		return errors.New("content digest mismatch")
+	}
+	return nil
+}
+
+// discard removes the entry in use from the cache, so that the next lookup
+// of its key is a miss. Its directory is removed once the other tasks placing
+// the artifact release it.
+func (c *Cache) discard(e *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e.users--
+	if c.entries[e.Key] == e {
+		delete(c.entries, e.Key)
+		c.size -= e.Size
+		e.discarded = true
+
+		// Without its metadata the entry is removed if the client restarts
+		dir := filepath.Join(c.dir, e.Key)
+		if err := os.Remove(filepath.Join(dir, cacheMeta)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			c.logger.Warn("failed to invalidate cache entry", "key", e.Key, "error", err)
+		}
+	}
+	c.removeDiscarded(e)
+}
+
+// removeDiscarded removes the directory of the discarded entry once it is no
+// longer in use. The lock must be held.
+func (c *Cache) removeDiscarded(e *cacheEntry) {
+	if !e.discarded || e.users > 0 {
+		return
+	}
+	if err := os.RemoveAll(filepath.Join(c.dir, e.Key)); err != nil {
+		c.logger.Error("failed to remove artifact", "key", e.Key, "error", err)
+	}
+}
+
+// link marks the entry as hardlinked into a task directory, persisting it
+// before the first link is created so that the entry is still verified after
+// a restart of the client.
+func (c *Cache) link(e *cacheEntry) error {
+	c.mu.Lock()
+	if e.linked {
+		c.mu.Unlock()
+		return nil
+	}
+	e.linked = true
+	meta := c.meta(e)
+	cached := c.entries[e.Key] == e
+	c.mu.Unlock()
+
+	if !cached {
+		return nil
+	}
+	return writeCacheMeta(filepath.Join(c.dir, e.Key), meta)
+}
+
+// place reflinks or copies the content of the entry into the destination,
+// restoring the permissions of its files. If readOnly is set and reflinks are
+// not supported, the read-only files of the cache are hardlinked instead.
+func (c *Cache) place(e *cacheEntry, destination string, readOnly bool) error {
+	content := filepath.Join(c.dir, e.Key, cacheContent)
+	return placeTree(content, destination, func(src, dst string) error {
+		err := reflink(src, dst)
+		if err != nil && readOnly {
+			if err = c.link(e); err == nil {
+				err = os.Link(src, dst)
+			}
+			if err == nil {
+				return nil
+			}
+		}
+		if err != nil {
+			err = copyFile(src, dst)
+		}
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(content, src)
+		if err != nil {
+			return err
+		}
+		if mode, ok := e.modes[rel]; ok {
+			return os.Chmod(dst, mode)
+		}
+		return nil
+	})
+}
+
+// evict removes the least recently used entries not in use until the cache
+// fits its maximum size. The lock must be held.
+func (c *Cache) evict() {
+	for c.size > c.maxSize {
+		var oldest *cacheEntry
+		for _, e := range c.entries {
+			if e.users == 0 && (oldest == nil || e.LastUseTime < oldest.LastUseTime) {
+				oldest = e
+			}
+		}
+		if oldest == nil {
+			return
+		}
+
+		c.logger.Debug("evicting artifact", "key", oldest.Key, "size", oldest.Size)
+		if err := c.remove(oldest); err != nil {
+			c.logger.Error("failed to evict artifact", "key", oldest.Key, "error", err)
+			return
+		}
+		metrics.IncrCounter([]string{"client", "artifact_cache", "evict"}, 1)
+	}
+}
+
+// remove deletes the entry from the cache. The lock must be held.
+func (c *Cache) remove(e *cacheEntry) error {
+	if err := os.RemoveAll(filepath.Join(c.dir, e.Key)); err != nil {
+		return err
+	}
+	delete(c.entries, e.Key)
+	c.size -= e.Size
+	return nil
+}
+
+// List returns the cached artifacts and the statistics of the cache.
+func (c *Cache) List() *structs.ArtifactCacheListResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	resp := &structs.ArtifactCacheListResponse{
+		Enabled: true,
+		MaxSize: c.maxSize,
+		Size:    c.size,
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: make([]*structs.ArtifactCacheEntry, 0, len(c.entries)),
+	}
+	for _, e := range c.entries {
+		entry := *e.ArtifactCacheEntry
+		resp.Entries = append(resp.Entries, &entry)
+	}
+	sort.Slice(resp.Entries, func(i, j int) bool {
+		return resp.Entries[i].LastUseTime > resp.Entries[j].LastUseTime
+	})
+	return resp
+}
+
+// Purge removes the artifact of the key from the cache, or all the artifacts
+// not in use if the key is empty. It returns the keys of the removed artifacts
+// and the number of bytes freed.
+func (c *Cache) Purge(key string) ([]string, int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key != "" {
+		e, ok := c.entries[key]
+		switch {
+		case !ok:
+			return nil, 0, ErrCacheEntryNotFound
+		case e.users > 0:
+			return nil, 0, ErrCacheEntryInUse
+		}
+		if err := c.remove(e); err != nil {
+			return nil, 0, err
+		}
+		return []string{key}, e.Size, nil
+	}
+
+	var purged []string
+	var size int64
+	var mErr []error
+	for _, e := range c.entries {
+		if e.users > 0 {
+			continue
+		}
+		if err := c.remove(e); err != nil {
+			mErr = append(mErr, err)
+			continue
+		}
+		purged = append(purged, e.Key)
+		size += e.Size
+	}
+	sort.Strings(purged)
+	return purged, size, errors.Join(mErr...)
+}
+
+// Stats returns the size in bytes and the number of entries of the cache.
+func (c *Cache) Stats() (int64, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size, len(c.entries)
+}
+
+// cacheKey returns the key of the artifact in the cache, or false if the
+// artifact is not cacheable because its content is not pinned by a checksum.
+func cacheKey(artifact *structs.TaskArtifact, params *parameters) (string, bool) {
+	if artifact.GetterOptions["checksum"] == "" {
+		return "", false
+	}
+
+	// Artifacts with a signature are verified on each download, against the
+	// keys trusted at that time.
+	if artifact.Signature != nil {
+		return "", false
+	}
+
+	// The source includes the getter options, and map keys are encoded in
+	// sorted order.
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, v := range []interface{}{params.Mode, params.Source, params.Headers} {
+		if err := enc.Encode(v); err != nil {
+			return "", false
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// redactSource removes the credentials and the query parameters other than
+// the checksum from the source URL of an artifact.
+func redactSource(source string) string {
+	prefix := ""
+	if strings.HasPrefix(source, githubPrefixSSH) {
+		prefix, source = githubPrefixSSH, source[len(githubPrefixSSH):]
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		source, _, _ = strings.Cut(source, "?")
+		return prefix + source
+	}
+	u.User = nil
+	q := u.Query()
+	u.RawQuery = ""
+	if checksum := q.Get("checksum"); checksum != "" {
+		u.RawQuery = url.Values{"checksum": []string{checksum}}.Encode()
+	}
+	return prefix + u.String()
+}
+
+func readCacheMeta(dir string) (*cacheEntryMeta, error) {
+	b, err := os.ReadFile(filepath.Join(dir, cacheMeta))
+	if err != nil {
+		return nil, err
+	}
+	var meta cacheEntryMeta
+	if err := json.Unmarshal(b, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// writeCacheMeta atomically writes the metadata of the entry into its
+// directory.
+func writeCacheMeta(dir string, meta *cacheEntryMeta) error {
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, cacheMeta+cacheTmpPrefix)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filepath.Join(dir, cacheMeta))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// treeSize returns the size of the regular files of the tree at path.
+func treeSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// treeDigest returns the SHA-256 digest of the paths, types and contents of
+// the tree at path.
+func treeDigest(root string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			fmt.Fprintf(h, "d %q\n", rel)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "l %q %q\n", rel, link)
+		case d.Type().IsRegular():
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			info, err := f.Stat()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "f %q %d\n", rel, info.Size())
+			if n, err := io.Copy(h, f); err != nil {
+				return err
+			} else if n != info.Size() {
+				return fmt.Errorf("file %q changed while hashing", rel)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// readOnlyTree removes the write permissions of the regular files of the
+// tree at path, and returns the previous permissions of the modified files
+// keyed by relative path.
+func readOnlyTree(root string) (map[string]fs.FileMode, error) {
+	modes := make(map[string]fs.FileMode)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		perm := info.Mode().Perm()
+		if perm&0o222 == 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		modes[rel] = perm
+		return os.Chmod(path, perm&^0o222)
+	})
+	if len(modes) == 0 {
+		modes = nil
+	}
+	return modes, err
+}
+
+// placeTree recreates the file or directory tree at src into dst, merging
+// directories and replacing files, using place to create regular files.
+func placeTree(src, dst string, place func(src, dst string) error) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return place(path, target)
+		}
+		return nil
+	})
+}
+
+// copyFile copies the regular file src to dst, preserving its permissions.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
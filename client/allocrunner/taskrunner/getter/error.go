@@ -22,6 +22,10 @@ func (e *Error) IsRecoverable() bool {
 	return e.Recoverable
 }
 
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
 func (e *Error) Equal(o *Error) bool {
 	if e == nil || o == nil {
 		return e == o
@@ -40,6 +40,11 @@ type parameters struct {
 	Destination string              `json:"artifact_destination"`
 	Headers     map[string][]string `json:"artifact_headers"`
 
+	// Signature
+	SignatureSource string            `json:"signature_source"`
+	SignatureFormat string            `json:"signature_format"`
+	SignatureKeys   map[string]string `json:"signature_keys"`
+
 	// Task Filesystem
 	AllocDir string `json:"alloc_dir"`
 	TaskDir  string `json:"task_dir"`
@@ -110,6 +115,12 @@ func (p *parameters) Equal(o *parameters) bool {
 		return false
 	case !maps.EqualFunc(p.Headers, o.Headers, headersCompareFn):
 		return false
+	case p.SignatureSource != o.SignatureSource:
+		return false
+	case p.SignatureFormat != o.SignatureFormat:
+		return false
+	case !maps.Equal(p.SignatureKeys, o.SignatureKeys):
+		return false
 	}
 
 	return true
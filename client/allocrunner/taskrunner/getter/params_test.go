@@ -32,6 +32,11 @@ const paramsAsJSON = `
   "artifact_headers": {
     "X-Nomad-Artifact": ["hi"]
   },
+  "signature_source": "https://example.com/file.txt.minisig",
+  "signature_format": "minisign",
+  "signature_keys": {
+    "build": "RWQ"
+  },
   "alloc_dir": "/path/to/alloc",
   "task_dir": "/path/to/alloc/task"
 }`
@@ -55,6 +60,9 @@ var paramsAsStruct = &parameters{
 	Headers: map[string][]string{
 		"X-Nomad-Artifact": {"hi"},
 	},
+	SignatureSource: "https://example.com/file.txt.minisig",
+	SignatureFormat: "minisign",
+	SignatureKeys:   map[string]string{"build": "RWQ"},
 }
 
 func TestParameters_reader(t *testing.T) {
@@ -4,17 +4,23 @@
 package getter
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+
 	"github.com/hashicorp/go-hclog"
 	"github.com/open-wander/wander/client/config"
 	"github.com/open-wander/wander/client/interfaces"
 	"github.com/open-wander/wander/nomad/structs"
 )
 
-// New creates a Sandbox with the given ArtifactConfig.
-func New(ac *config.ArtifactConfig, logger hclog.Logger) *Sandbox {
+// New creates a Sandbox with the given ArtifactConfig. Artifacts pinned by a
+// checksum are shared through the cache, unless it is nil.
+func New(ac *config.ArtifactConfig, cache *Cache, logger hclog.Logger) *Sandbox {
 	return &Sandbox{
 		logger: logger.Named("artifact"),
 		ac:     ac,
+		cache:  cache,
 	}
 }
 
@@ -22,9 +28,13 @@ func New(ac *config.ArtifactConfig, logger hclog.Logger) *Sandbox {
 type Sandbox struct {
 	logger hclog.Logger
 	ac     *config.ArtifactConfig
+	cache  *Cache
 }
 
-func (s *Sandbox) Get(env interfaces.EnvReplacer, artifact *structs.TaskArtifact) error {
+// Get downloads the artifact into the task directory. The signature of the
+// artifact, if any, is verified against the keys trusted by the Client and
+// the given keys.
+func (s *Sandbox) Get(env interfaces.EnvReplacer, artifact *structs.TaskArtifact, keys map[string]string) error {
 	s.logger.Debug("get", "source", artifact.GetterSource, "destination", artifact.RelativeDest)
 
 	source, err := getURL(env, artifact)
@@ -65,8 +75,98 @@ func (s *Sandbox) Get(env interfaces.EnvReplacer, artifact *structs.TaskArtifact
 		TaskDir:  taskDir,
 	}
 
+	if sig := artifact.Signature; sig != nil {
+		trusted, err := s.trustedKeys(sig, keys)
+		if err != nil {
+			return &Error{
+				URL:         artifact.GetterSource,
+				Err:         err,
+				Recoverable: false,
+			}
+		}
+		params.SignatureSource = env.ReplaceEnv(sig.Source)
+		params.SignatureFormat = sig.Format
+		params.SignatureKeys = trusted
+	}
+
+	if s.cache != nil {
+		if key, ok := cacheKey(artifact, params); ok {
+			return s.getCached(key, artifact, params)
+		}
+	}
+
 	if err = s.runCmd(params); err != nil {
 		return err
 	}
 	return nil
 }
+
+// getCached places the artifact of the key from the cache, downloading it
+// into the cache first on a miss.
+func (s *Sandbox) getCached(key string, artifact *structs.TaskArtifact, params *parameters) error {
+	e := s.cache.acquire(key)
+	if e != nil {
+		if err := s.cache.verify(e); err != nil {
+			s.logger.Warn("cached artifact is invalid, downloading it again",
+				"source", artifact.GetterSource, "key", key, "error", err)
+			s.cache.discard(e)
+			e = s.cache.acquire(key)
+		}
+	}
+	if e != nil {
+		defer s.cache.release(e)
+
+		s.logger.Debug("using cached artifact", "source", artifact.GetterSource, "key", key)
+		return s.place(e, artifact, params)
+	}
+	defer s.cache.done(key)
+
+	// Download into the task directory, as the sandboxed download may only
+	// write there, and move the artifact into the cache.
+	stage, err := os.MkdirTemp(params.TaskDir, cacheStagePrefix)
+	if err != nil {
+		return &Error{
+			URL:         params.Source,
+			Err:         fmt.Errorf("failed to create artifact staging dir: %v", err),
+			Recoverable: true,
+		}
+	}
+	defer os.RemoveAll(stage)
+
+	staged := *params
+	staged.Destination = filepath.Join(stage, cacheContent)
+	if err := s.runCmd(&staged); err != nil {
+		return err
+	}
+
+	e, err = s.cache.add(key, staged.Destination, &structs.ArtifactCacheEntry{
+		Source: redactSource(params.Source),
+		Mode:   artifact.GetterMode,
+	})
+	if err != nil {
+		// The artifact can still be used without caching it
+		s.logger.Warn("failed to cache artifact", "source", artifact.GetterSource, "error", err)
+		if err := placeTree(staged.Destination, params.Destination, os.Rename); err != nil {
+			return &Error{
+				URL:         params.Source,
+				Err:         fmt.Errorf("failed to place artifact: %v", err),
+				Recoverable: true,
+			}
+		}
+		return nil
+	}
+	defer s.cache.release(e)
+
+	return s.place(e, artifact, params)
+}
+
+func (s *Sandbox) place(e *cacheEntry, artifact *structs.TaskArtifact, params *parameters) error {
+	if err := s.cache.place(e, params.Destination, artifact.ReadOnly); err != nil {
+		return &Error{
+			URL:         params.Source,
+			Err:         fmt.Errorf("failed to place cached artifact: %v", err),
+			Recoverable: true,
+		}
+	}
+	return nil
+}
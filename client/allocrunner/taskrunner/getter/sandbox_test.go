@@ -4,8 +4,13 @@
 package getter
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -34,7 +39,7 @@ func TestSandbox_Get_http(t *testing.T) {
 	logger := testlog.HCLogger(t)
 
 	ac := artifactConfig(10 * time.Second)
-	sbox := New(ac, logger)
+	sbox := New(ac, nil, logger)
 
 	_, taskDir := SetupDir(t)
 	env := noopTaskEnv(taskDir)
@@ -44,10 +49,81 @@ func TestSandbox_Get_http(t *testing.T) {
 		RelativeDest: "local/downloads",
 	}
 
-	err := sbox.Get(env, artifact)
+	err := sbox.Get(env, artifact, nil)
 	must.NoError(t, err)
 
 	b, err := os.ReadFile(filepath.Join(taskDir, "local", "downloads", "go.mod"))
 	must.NoError(t, err)
 	must.StrContains(t, string(b), "module github.com/hashicorp/go-set")
 }
+
+func TestSandbox_Get_cached(t *testing.T) {
+	testutil.RequireRoot(t)
+	logger := testlog.HCLogger(t)
+
+	content := []byte("hello world")
+	sum := sha256.Sum256(content)
+
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write(content)
+	}))
+	defer ts.Close()
+
+	cache, err := NewCache(t.TempDir(), 1024, logger)
+	must.NoError(t, err)
+	sbox := New(artifactConfig(10*time.Second), cache, logger)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource:  ts.URL + "/hello.txt",
+		GetterOptions: map[string]string{"checksum": "sha256:" + hex.EncodeToString(sum[:])},
+		GetterMode:    structs.GetterModeFile,
+		RelativeDest:  "local/hello.txt",
+		ReadOnly:      true,
+	}
+
+	// The artifact is downloaded once and placed into both tasks
+	var placed string
+	for i := 0; i < 2; i++ {
+		_, taskDir := SetupDir(t)
+		placed = filepath.Join(taskDir, "local", "hello.txt")
+		must.NoError(t, sbox.Get(noopTaskEnv(taskDir), artifact, nil))
+
+		b, err := os.ReadFile(filepath.Join(taskDir, "local", "hello.txt"))
+		must.NoError(t, err)
+		must.Eq(t, content, b)
+
+		// The staging dir was removed
+		staged, err := filepath.Glob(filepath.Join(taskDir, cacheStagePrefix+"*"))
+		must.NoError(t, err)
+		must.SliceEmpty(t, staged)
+	}
+	must.Eq(t, 1, requests.Load())
+
+	list := cache.List()
+	must.Eq(t, 1, list.Hits)
+	must.Eq(t, 1, list.Misses)
+	must.Len(t, 1, list.Entries)
+	must.Eq(t, ts.URL+"/hello.txt?checksum=sha256%3A"+hex.EncodeToString(sum[:]), list.Entries[0].Source)
+
+	// A task modifies the artifact, which is downloaded again if it was
+	// hardlinked rather than reflinked
+	placedInfo, err := os.Stat(placed)
+	must.NoError(t, err)
+	cachedInfo, err := os.Stat(filepath.Join(cache.dir, list.Entries[0].Key, cacheContent))
+	must.NoError(t, err)
+	expected := int32(1)
+	if os.SameFile(placedInfo, cachedInfo) {
+		expected = 2
+	}
+	must.NoError(t, os.Chmod(placed, 0o644))
+	must.NoError(t, os.WriteFile(placed, []byte("corrupted"), 0o644))
+
+	_, taskDir := SetupDir(t)
+	must.NoError(t, sbox.Get(noopTaskEnv(taskDir), artifact, nil))
+	b, err := os.ReadFile(filepath.Join(taskDir, "local", "hello.txt"))
+	must.NoError(t, err)
+	must.Eq(t, content, b)
+	must.Eq(t, expected, requests.Load())
+}
@@ -0,0 +1,201 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+
+	"github.com/hashicorp/go-getter"
+	"github.com/open-wander/wander/helper/signature"
+	"github.com/open-wander/wander/helper/subproc"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+const (
+	// exitVerificationFailed is the exit code of the getter sub-process when
+	// the signature of the artifact could not be verified.
+	exitVerificationFailed = subproc.ExitTimeout + 1
+
+	// signatureStagePrefix is the prefix of the directories of the task
+	// into which artifacts and their signatures are downloaded before the
+	// artifacts are verified and unpacked.
+	signatureStagePrefix = ".nomad-signature-"
+)
+
+// ErrVerificationFailed is returned when the signature of an artifact could
+// not be verified against the trusted keys.
+var ErrVerificationFailed = errors.New("artifact signature verification failed")
+
+// forcedRegexp matches the go-getter sources forcing a getter, such as
+// "s3::https://bucket.s3.amazonaws.com/file".
+var forcedRegexp = regexp.MustCompile(`^([A-Za-z0-9]+)::(.+)$`)
+
+// verificationError is an error verifying the signature of an artifact, as
+// opposed to an error downloading the artifact or its signature.
+type verificationError struct {
+	err error
+}
+
+func (e *verificationError) Error() string {
+	return e.err.Error()
+}
+
+// trustedKeys returns the trusted keys the signature of the artifact may be
+// verified against: the keys of the Client and the given keys, restricted
+// to the keys named by the signature.
+func (s *Sandbox) trustedKeys(sig *structs.ArtifactSignature, keys map[string]string) (map[string]string, error) {
+	trusted := make(map[string]string, len(s.ac.TrustedKeys)+len(keys))
+	for name, key := range keys {
+		trusted[name] = key
+	}
+
+	// The keys of the Client cannot be overridden
+	for name, key := range s.ac.TrustedKeys {
+		trusted[name] = key
+	}
+
+	if len(sig.Keys) > 0 {
+		named := make(map[string]string, len(sig.Keys))
+		for _, name := range sig.Keys {
+			key, ok := trusted[name]
+			if !ok {
+				return nil, fmt.Errorf("%w: trusted key %q not found", ErrVerificationFailed, name)
+			}
+			named[name] = key
+		}
+		trusted = named
+	}
+
+	if len(trusted) == 0 {
+		return nil, fmt.Errorf("%w: %v", ErrVerificationFailed, signature.ErrNoKeys)
+	}
+	return trusted, nil
+}
+
+// getVerified downloads the artifact and its signature into a staging
+// directory of the task, verifies the signature of the artifact and only
+// then unpacks the artifact into its destination. It runs in the getter
+// sub-process.
+func (p *parameters) getVerified(ctx context.Context) error {
+	stage, err := os.MkdirTemp(p.TaskDir, signatureStagePrefix)
+	if err != nil {
+		return fmt.Errorf("failed to create staging dir: %w", err)
+	}
+	defer os.RemoveAll(stage)
+
+	raw, file, unpack, err := p.stagedSources(filepath.Join(stage, "artifact"))
+	if err != nil {
+		return err
+	}
+
+	// Download the artifact without unpacking it, so that the signed file is
+	// verified rather than its content.
+	artifact := *p
+	artifact.Source = raw
+	artifact.Destination = file
+	artifact.Mode = getter.ClientModeFile
+	if err := artifact.client(ctx).Get(); err != nil {
+		return err
+	}
+
+	sig := *p
+	sig.Source = p.SignatureSource
+	sig.Destination = filepath.Join(stage, "signature")
+	sig.Mode = getter.ClientModeFile
+	if err := sig.client(ctx).Get(); err != nil {
+		return fmt.Errorf("failed to download signature: %w", err)
+	}
+
+	if err := p.verify(file, sig.Destination); err != nil {
+		return &verificationError{err: err}
+	}
+
+	// Unpack the verified artifact from the staging directory. Only this
+	// client may get local files.
+	local := *p
+	local.Source = unpack
+	c := local.client(ctx)
+	c.Getters["file"] = &getter.FileGetter{Copy: true}
+	return c.Get()
+}
+
+// verify verifies the signature of the artifact file against the trusted
+// keys.
+func (p *parameters) verify(artifact, sig string) error {
+	keys, err := signature.ParseKeys(p.SignatureKeys)
+	if err != nil {
+		return err
+	}
+
+	b, err := os.ReadFile(sig)
+	if err != nil {
+		return fmt.Errorf("failed to read signature: %w", err)
+	}
+
+	name, err := signature.VerifyFile(p.SignatureFormat, artifact, b, keys)
+	if err != nil {
+		return err
+	}
+	subproc.Print("artifact signature was verified with key %q", name)
+	return nil
+}
+
+// stagedSources returns the source downloading the artifact as is, the file
+// of the dir it is downloaded to and the source unpacking that file into the
+// destination of the artifact like the original source would have.
+func (p *parameters) stagedSources(dir string) (string, string, string, error) {
+	src, subDir := getter.SourceDirSubdir(p.Source)
+
+	var force string
+	if m := forcedRegexp.FindStringSubmatch(src); m != nil {
+		force, src = m[1], m[2]
+	}
+
+	u, err := url.Parse(src)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to parse source URL: %w", err)
+	}
+
+	// The archive and filename parameters are applied when unpacking
+	q := u.Query()
+	local := make(url.Values)
+	for _, k := range []string{"archive", "filename"} {
+		if v := q.Get(k); v != "" {
+			local.Set(k, v)
+			q.Del(k)
+		}
+	}
+	q.Set("archive", "false")
+	u.RawQuery = q.Encode()
+
+	raw := u.String()
+	if force != "" {
+		raw = force + "::" + raw
+	}
+
+	// Keep the name of the file so that archives are detected by their
+	// extension.
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		name = "artifact"
+	}
+	file := filepath.Join(dir, name)
+
+	unpack := file
+	if subDir != "" {
+		unpack += "//" + subDir
+	}
+	if len(local) > 0 {
+		unpack += "?" + local.Encode()
+	}
+
+	return raw, file, unpack, nil
+}
@@ -0,0 +1,196 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getter
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/client/testutil"
+	"github.com/open-wander/wander/helper/testlog"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// signedArtifactServer serves a tar.gz archive with a single file, its
+// signature by key and a signature by another key.
+func signedArtifactServer(t *testing.T, key *ecdsa.PrivateKey) *httptest.Server {
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	content := []byte("#!/bin/sh\necho hello\n")
+	must.NoError(t, tw.WriteHeader(&tar.Header{Name: "bin/app", Mode: 0o755, Size: int64(len(content))}))
+	_, err := tw.Write(content)
+	must.NoError(t, err)
+	must.NoError(t, tw.Close())
+	must.NoError(t, gz.Close())
+	archive := buf.Bytes()
+
+	sign := func(key *ecdsa.PrivateKey) []byte {
+		digest := sha256.Sum256(archive)
+		sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		must.NoError(t, err)
+		return []byte(base64.StdEncoding.EncodeToString(sig))
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must.NoError(t, err)
+
+	files := map[string][]byte{
+		"/app.tar.gz":           archive,
+		"/app.tar.gz.sig":       sign(key),
+		"/app.tar.gz.other.sig": sign(other),
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(b)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func testPublicKey(t *testing.T, key *ecdsa.PrivateKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	must.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// setupSignatureDir creates a task directory with the temporary directory
+// archives are downloaded into before being unpacked.
+func setupSignatureDir(t *testing.T) string {
+	_, taskDir := SetupDir(t)
+	must.NoError(t, os.Mkdir(filepath.Join(taskDir, "tmp"), 0o755))
+	return taskDir
+}
+
+func TestSandbox_Get_signature(t *testing.T) {
+	testutil.RequireRoot(t)
+	logger := testlog.HCLogger(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must.NoError(t, err)
+	ts := signedArtifactServer(t, key)
+
+	ac := artifactConfig(10 * time.Second)
+	ac.TrustedKeys = map[string]string{"build": testPublicKey(t, key)}
+	sbox := New(ac, nil, logger)
+
+	artifact := func(sig string) *structs.TaskArtifact {
+		return &structs.TaskArtifact{
+			GetterSource: ts.URL + "/app.tar.gz",
+			RelativeDest: "local/app",
+			Signature: &structs.ArtifactSignature{
+				Source: ts.URL + sig,
+				Format: "cosign",
+			},
+		}
+	}
+
+	t.Run("verified", func(t *testing.T) {
+		taskDir := setupSignatureDir(t)
+		must.NoError(t, sbox.Get(noopTaskEnv(taskDir), artifact("/app.tar.gz.sig"), nil))
+
+		// The verified archive was unpacked
+		b, err := os.ReadFile(filepath.Join(taskDir, "local", "app", "bin", "app"))
+		must.NoError(t, err)
+		must.StrContains(t, string(b), "echo hello")
+
+		staged, err := filepath.Glob(filepath.Join(taskDir, signatureStagePrefix+"*"))
+		must.NoError(t, err)
+		must.SliceEmpty(t, staged)
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		taskDir := setupSignatureDir(t)
+		err := sbox.Get(noopTaskEnv(taskDir), artifact("/app.tar.gz.other.sig"), nil)
+		must.ErrorIs(t, err, ErrVerificationFailed)
+		must.ErrorContains(t, err, "signature was not made by a trusted key")
+		must.False(t, structs.IsRecoverable(err))
+		must.DirNotExists(t, filepath.Join(taskDir, "local", "app"))
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		taskDir := setupSignatureDir(t)
+		err := sbox.Get(noopTaskEnv(taskDir), artifact("/missing.sig"), nil)
+		must.ErrorContains(t, err, "failed to download signature")
+		must.False(t, errors.Is(err, ErrVerificationFailed))
+		must.True(t, structs.IsRecoverable(err))
+	})
+
+	t.Run("variable keys", func(t *testing.T) {
+		// The keys of the Client cannot be overridden
+		taskDir := setupSignatureDir(t)
+		a := artifact("/app.tar.gz.sig")
+		a.Signature.Keys = []string{"build"}
+		must.NoError(t, sbox.Get(noopTaskEnv(taskDir), a, map[string]string{"build": "bad"}))
+
+		a.Signature.Keys = []string{"release"}
+		err := sbox.Get(noopTaskEnv(taskDir), a, nil)
+		must.ErrorIs(t, err, ErrVerificationFailed)
+		must.ErrorContains(t, err, `trusted key "release" not found`)
+
+		noKeys := New(artifactConfig(10*time.Second), nil, logger)
+		taskDir = setupSignatureDir(t)
+		must.NoError(t, noKeys.Get(noopTaskEnv(taskDir), artifact("/app.tar.gz.sig"),
+			map[string]string{"release": testPublicKey(t, key)}))
+	})
+}
+
+func TestParameters_stagedSources(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		source string
+		raw    string
+		file   string
+		unpack string
+	}{
+		{
+			source: "https://example.com/app.tar.gz?checksum=sha256:abc",
+			raw:    "https://example.com/app.tar.gz?archive=false&checksum=sha256%3Aabc",
+			file:   "/stage/app.tar.gz",
+			unpack: "/stage/app.tar.gz",
+		},
+		{
+			source: "s3::https://bucket.s3.amazonaws.com/app?archive=zip&filename=app.bin",
+			raw:    "s3::https://bucket.s3.amazonaws.com/app?archive=false",
+			file:   "/stage/app",
+			unpack: "/stage/app?archive=zip&filename=app.bin",
+		},
+		{
+			source: "https://example.com/app.tgz//bin",
+			raw:    "https://example.com/app.tgz?archive=false",
+			file:   "/stage/app.tgz",
+			unpack: "/stage/app.tgz//bin",
+		},
+	}
+
+	for _, tc := range cases {
+		p := &parameters{Source: tc.source}
+		raw, file, unpack, err := p.stagedSources("/stage")
+		must.NoError(t, err)
+		must.Eq(t, tc.raw, raw)
+		must.Eq(t, tc.file, file)
+		must.Eq(t, tc.unpack, unpack)
+	}
+}
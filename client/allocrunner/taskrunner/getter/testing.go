@@ -23,7 +23,7 @@ func TestSandbox(t *testing.T) *Sandbox {
 	defaultConfig.DecompressionFileCountLimit = pointer.Of(10)
 	ac, err := cconfig.ArtifactConfigFromAgent(defaultConfig)
 	must.NoError(t, err)
-	return New(ac, testlog.HCLogger(t))
+	return New(ac, nil, testlog.HCLogger(t))
 }
 
 // SetupDir creates a directory suitable for testing artifact - i.e. it is
@@ -5,6 +5,7 @@ package getter
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -145,6 +146,16 @@ func (s *Sandbox) runCmd(env *parameters) error {
 	if err := cmd.Run(); err != nil {
 		msg := subproc.Log(output, s.logger.Error)
 
+		// retrying cannot fix an artifact that is not trusted
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == exitVerificationFailed {
+			return &Error{
+				URL:         env.Source,
+				Err:         fmt.Errorf("%w: %s", ErrVerificationFailed, msg),
+				Recoverable: false,
+			}
+		}
+
 		return &Error{
 			URL:         env.Source,
 			Err:         fmt.Errorf("getter subprocess failed: %v: %v", err, msg),
@@ -6,6 +6,7 @@
 package getter
 
 import (
+	"errors"
 	"path/filepath"
 )
 
@@ -23,3 +24,8 @@ func defaultEnvironment(taskDir string) map[string]string {
 		"TMPDIR": tmpDir,
 	}
 }
+
+// reflink is not supported, so cached artifacts are hardlinked or copied.
+func reflink(string, string) error {
+	return errors.ErrUnsupported
+}
@@ -6,6 +6,7 @@
 package getter
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 )
@@ -27,3 +28,8 @@ func defaultEnvironment(taskDir string) map[string]string {
 		"TEMP":        tmpDir,
 	}
 }
+
+// reflink is not supported, so cached artifacts are hardlinked or copied.
+func reflink(string, string) error {
+	return errors.ErrUnsupported
+}
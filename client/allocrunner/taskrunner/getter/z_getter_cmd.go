@@ -4,6 +4,7 @@
 package getter
 
 import (
+	"errors"
 	"os"
 
 	"github.com/open-wander/wander/helper/subproc"
@@ -40,6 +41,23 @@ func init() {
 			}
 		}
 
+		// artifacts with a signature are verified before being unpacked
+		if env.SignatureSource != "" {
+			err := env.getVerified(ctx)
+			var verr *verificationError
+			switch {
+			case errors.As(err, &verr):
+				subproc.Print("%v", verr)
+				return exitVerificationFailed
+			case err != nil:
+				subproc.Print("failed to download artifact: %v", err)
+				return subproc.ExitFailure
+			}
+
+			subproc.Print("artifact download was a success")
+			return subproc.ExitSuccess
+		}
+
 		// create the go-getter client
 		// options were already transformed into url query parameters
 		// headers were already replaced and are usable now
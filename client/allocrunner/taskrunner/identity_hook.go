@@ -8,20 +8,29 @@ import (
 	"fmt"
 	"path/filepath"
 	"sync"
+	"time"
 
 	log "github.com/hashicorp/go-hclog"
 
 	"github.com/open-wander/wander/client/allocrunner/interfaces"
+	"github.com/open-wander/wander/helper"
 	"github.com/open-wander/wander/helper/users"
+	"github.com/open-wander/wander/nomad/structs"
 )
 
 // identityHook sets the task runner's Nomad workload identity token
-// based on the signed identity stored on the Allocation
+// based on the signed identity stored on the Allocation, fetches the named
+// workload identities of the task and renews identities before they expire.
 
 const (
 	// wiTokenFile is the name of the file holding the Nomad token inside the
 	// task's secret directory
 	wiTokenFile = "nomad_token"
+
+	// identityRenewRetryMin and identityRenewRetryMax bound the wait between
+	// failed attempts to renew identities.
+	identityRenewRetryMin = 1 * time.Second
+	identityRenewRetryMax = 1 * time.Minute
 )
 
 type identityHook struct {
@@ -32,6 +41,12 @@ type identityHook struct {
 
 	// tokenPath is the path in which to read and write the token
 	tokenPath string
+
+	// secretsDir is the directory in which named tokens are written
+	secretsDir string
+
+	// stopRenew stops the renewal of identities, if running
+	stopRenew context.CancelFunc
 }
 
 func newIdentityHook(tr *TaskRunner, logger log.Logger) *identityHook {
@@ -51,8 +66,54 @@ func (h *identityHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 	h.lock.Lock()
 	defer h.lock.Unlock()
 	h.tokenPath = filepath.Join(req.TaskDir.SecretsDir, wiTokenFile)
+	h.secretsDir = req.TaskDir.SecretsDir
+
+	// Stop renewing the identities of a previous run of the task.
+	h.stopRenewLocked()
+
+	if err := h.setToken(); err != nil {
+		return err
+	}
+
+	requests := h.identityRequests()
+	if len(requests) == 0 {
+		return nil
+	}
 
-	return h.setToken()
+	signed, err := h.signIdentities(requests)
+	if err != nil {
+		// The servers may be temporarily unavailable.
+		return structs.NewRecoverableError(err, true)
+	}
+	if err := h.setIdentities(signed); err != nil {
+		return err
+	}
+
+	renewCtx, cancel := context.WithCancel(context.Background())
+	h.stopRenew = cancel
+	go h.renew(renewCtx, signed)
+
+	return nil
+}
+
+func (h *identityHook) Stop(context.Context, *interfaces.TaskStopRequest, *interfaces.TaskStopResponse) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.stopRenewLocked()
+	return nil
+}
+
+func (h *identityHook) Shutdown() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.stopRenewLocked()
+}
+
+func (h *identityHook) stopRenewLocked() {
+	if h.stopRenew != nil {
+		h.stopRenew()
+		h.stopRenew = nil
+	}
 }
 
 // setToken adds the Nomad token to the task's environment and writes it to a
@@ -66,7 +127,7 @@ func (h *identityHook) setToken() error {
 	h.tr.setNomadToken(token)
 
 	if id := h.tr.task.Identity; id != nil && id.File {
-		if err := h.writeToken(token); err != nil {
+		if err := h.writeToken(h.tokenPath, token); err != nil {
 			return err
 		}
 	}
@@ -74,10 +135,152 @@ func (h *identityHook) setToken() error {
 	return nil
 }
 
+// identityRequests returns the identities of the task that must be signed by
+// the servers: the named identities, which are not signed with the
+// allocation, and the default identity if it expires.
+func (h *identityHook) identityRequests() []*structs.WorkloadIdentityRequest {
+	var requests []*structs.WorkloadIdentityRequest
+
+	if id := h.tr.task.Identity; id != nil && id.TTL > 0 {
+		requests = append(requests, &structs.WorkloadIdentityRequest{
+			AllocID:      h.tr.alloc.ID,
+			TaskName:     h.taskName,
+			IdentityName: structs.WorkloadIdentityDefaultName,
+		})
+	}
+
+	for _, wi := range h.tr.task.Identities {
+		requests = append(requests, &structs.WorkloadIdentityRequest{
+			AllocID:      h.tr.alloc.ID,
+			TaskName:     h.taskName,
+			IdentityName: wi.Name,
+		})
+	}
+
+	return requests
+}
+
+// signIdentities requests the servers to sign the identities.
+func (h *identityHook) signIdentities(requests []*structs.WorkloadIdentityRequest) ([]*structs.SignedWorkloadIdentity, error) {
+	if h.tr.rpcClient == nil {
+		return nil, fmt.Errorf("failed to sign workload identities: no RPC client")
+	}
+
+	args := &structs.AllocIdentitiesRequest{
+		Identities: requests,
+		QueryOptions: structs.QueryOptions{
+			Region:     h.tr.clientConfig.Region,
+			AllowStale: true,
+			AuthToken:  h.tr.clientConfig.Node.SecretID,
+		},
+	}
+	var reply structs.AllocIdentitiesResponse
+	if err := h.tr.rpcClient.RPC(structs.AllocSignIdentitiesRPCMethod, args, &reply); err != nil {
+		return nil, fmt.Errorf("failed to sign workload identities: %w", err)
+	}
+	return reply.SignedIdentities, nil
+}
+
+// setIdentities exposes the signed identities to the task through its
+// environment and secrets directory, as requested by the jobspec.
+func (h *identityHook) setIdentities(signed []*structs.SignedWorkloadIdentity) error {
+	for _, swi := range signed {
+		wi := h.tr.task.LookupIdentity(swi.IdentityName)
+		if wi == nil {
+			continue
+		}
+
+		if wi.IsDefault() {
+			h.tr.setNomadToken(swi.JWT)
+			if wi.File {
+				if err := h.writeToken(h.tokenPath, swi.JWT); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		h.tr.setWorkloadToken(wi.Name, swi.JWT, wi.Env)
+		if wi.File {
+			path := filepath.Join(h.secretsDir, fmt.Sprintf("nomad_%s.jwt", wi.Name))
+			if err := h.writeToken(path, swi.JWT); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// renew renews the identities that expire until the context is cancelled.
+// Renewed tokens are written to the secrets directory and used on the next
+// restart of the task, but the environment of a running task can not be
+// updated.
+func (h *identityHook) renew(ctx context.Context, signed []*structs.SignedWorkloadIdentity) {
+	var requests []*structs.WorkloadIdentityRequest
+	for _, swi := range signed {
+		if !swi.Expiration.IsZero() {
+			req := swi.WorkloadIdentityRequest
+			requests = append(requests, &req)
+		}
+	}
+	if len(requests) == 0 {
+		return
+	}
+
+	timer, stop := helper.NewSafeTimer(renewalWait(signed, time.Now()))
+	defer stop()
+
+	retry := identityRenewRetryMin
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		renewed, err := h.signIdentities(requests)
+		if err != nil {
+			h.logger.Warn("failed to renew workload identities", "error", err, "retry", retry)
+			timer.Reset(retry)
+			retry = min(retry*2, identityRenewRetryMax)
+			continue
+		}
+		retry = identityRenewRetryMin
+
+		h.lock.Lock()
+		if ctx.Err() == nil {
+			err = h.setIdentities(renewed)
+		}
+		h.lock.Unlock()
+		if err != nil {
+			h.logger.Error("failed to update renewed workload identities", "error", err)
+		}
+
+		timer.Reset(renewalWait(renewed, time.Now()))
+	}
+}
+
+// renewalWait returns how long to wait before renewing the identities, which
+// is half of the remaining lifetime of the first identity to expire.
+func renewalWait(signed []*structs.SignedWorkloadIdentity, now time.Time) time.Duration {
+	var wait time.Duration
+	for _, swi := range signed {
+		if swi.Expiration.IsZero() {
+			continue
+		}
+		remaining := swi.Expiration.Sub(now) / 2
+		if wait == 0 || remaining < wait {
+			wait = remaining
+		}
+	}
+	return max(wait, identityRenewRetryMin)
+}
+
 // writeToken writes the given token to disk
-func (h *identityHook) writeToken(token string) error {
+func (h *identityHook) writeToken(path, token string) error {
 	// Write token as owner readable only
-	if err := users.WriteFileFor(h.tokenPath, []byte(token), h.tr.task.User); err != nil {
+	if err := users.WriteFileFor(path, []byte(token), h.tr.task.User); err != nil {
 		return fmt.Errorf("failed to write nomad token: %w", err)
 	}
 
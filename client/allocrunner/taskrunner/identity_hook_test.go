@@ -3,8 +3,74 @@
 
 package taskrunner
 
-import "github.com/open-wander/wander/client/allocrunner/interfaces"
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
 
-var _ interfaces.TaskPrestartHook = (*identityHook)(nil)
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/client/allocrunner/interfaces"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+var (
+	_ interfaces.TaskPrestartHook = (*identityHook)(nil)
+	_ interfaces.TaskStopHook     = (*identityHook)(nil)
+	_ interfaces.ShutdownHook     = (*identityHook)(nil)
+)
 
 // See task_runner_test.go:TestTaskRunner_IdentityHook
+
+// mockIdentitySigner signs workload identities as "<name>-<n>" where n is the
+// number of times the identity has been signed.
+type mockIdentitySigner struct {
+	lock   sync.Mutex
+	ttl    time.Duration
+	signed map[string]int
+}
+
+func newMockIdentitySigner(ttl time.Duration) *mockIdentitySigner {
+	return &mockIdentitySigner{
+		ttl:    ttl,
+		signed: make(map[string]int),
+	}
+}
+
+func (m *mockIdentitySigner) RPC(method string, args, reply any) error {
+	if method != structs.AllocSignIdentitiesRPCMethod {
+		return fmt.Errorf("unexpected RPC method %q", method)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	req := args.(*structs.AllocIdentitiesRequest)
+	resp := reply.(*structs.AllocIdentitiesResponse)
+	for _, idReq := range req.Identities {
+		m.signed[idReq.IdentityName]++
+		resp.SignedIdentities = append(resp.SignedIdentities, &structs.SignedWorkloadIdentity{
+			WorkloadIdentityRequest: *idReq,
+			JWT:                     fmt.Sprintf("%s-%d", idReq.IdentityName, m.signed[idReq.IdentityName]),
+			Expiration:              time.Now().Add(m.ttl),
+		})
+	}
+	return nil
+}
+
+func TestIdentityHook_renewalWait(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+	signed := []*structs.SignedWorkloadIdentity{
+		{JWT: "a"},
+		{JWT: "b", Expiration: now.Add(time.Hour)},
+		{JWT: "c", Expiration: now.Add(10 * time.Minute)},
+	}
+	must.Eq(t, 5*time.Minute, renewalWait(signed, now))
+
+	// Identities are not renewed more often than the minimum retry wait.
+	signed[2].Expiration = now.Add(time.Second)
+	must.Eq(t, identityRenewRetryMin, renewalWait(signed, now))
+}
@@ -16,6 +16,7 @@ import (
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/open-wander/wander/client/allocrunner/interfaces"
 	"github.com/open-wander/wander/client/logmon"
+	"github.com/open-wander/wander/client/logmon/logging"
 	"github.com/open-wander/wander/helper/uuid"
 	"github.com/open-wander/wander/nomad/structs"
 	bstructs "github.com/open-wander/wander/plugins/base/structs"
@@ -192,7 +193,8 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 		}
 	}
 
-	err := h.logmon.Start(&logmon.LogConfig{
+	alloc := h.runner.Alloc()
+	cfg := &logmon.LogConfig{
 		LogDir:        h.config.logDir,
 		StdoutLogFile: fmt.Sprintf("%s.stdout", req.Task.Name),
 		StderrLogFile: fmt.Sprintf("%s.stderr", req.Task.Name),
@@ -200,7 +202,25 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 		StderrFifo:    h.config.stderrFifo,
 		MaxFiles:      req.Task.LogConfig.MaxFiles,
 		MaxFileSizeMB: req.Task.LogConfig.MaxFileSizeMB,
-	})
+		AllocID:       alloc.ID,
+		Namespace:     alloc.Namespace,
+		JobID:         alloc.JobID,
+		TaskGroup:     alloc.TaskGroup,
+		TaskName:      req.Task.Name,
+	}
+	for _, sink := range req.Task.LogConfig.Sinks {
+		cfg.Sinks = append(cfg.Sinks, &logging.SinkConfig{
+			Type:       sink.Type,
+			Address:    sink.Address,
+			Path:       sink.Path,
+			Facility:   sink.Facility,
+			Tag:        sink.Tag,
+			Headers:    sink.Headers,
+			BufferSize: sink.BufferSize,
+		})
+	}
+
+	err := h.logmon.Start(cfg)
 	if err != nil {
 		h.logger.Error("failed to start logmon", "error", err)
 		return err
@@ -36,7 +36,7 @@ func TestTaskRunner_LogmonHook_StartCrashStop(t *testing.T) {
 	dir := t.TempDir()
 
 	hookConf := newLogMonHookConfig(task.Name, task.LogConfig, dir)
-	runner := &TaskRunner{logmonHookConfig: hookConf}
+	runner := &TaskRunner{logmonHookConfig: hookConf, alloc: alloc}
 	hook := newLogMonHook(runner, testlog.HCLogger(t))
 
 	req := interfaces.TaskPrestartRequest{
@@ -101,7 +101,7 @@ func TestTaskRunner_LogmonHook_ShutdownMidStart(t *testing.T) {
 	dir := t.TempDir()
 
 	hookConf := newLogMonHookConfig(task.Name, task.LogConfig, dir)
-	runner := &TaskRunner{logmonHookConfig: hookConf}
+	runner := &TaskRunner{logmonHookConfig: hookConf, alloc: alloc}
 	hook := newLogMonHook(runner, testlog.HCLogger(t))
 
 	req := interfaces.TaskPrestartRequest{
@@ -21,6 +21,8 @@ const (
 	ReasonUnrecoverableError = "Error was unrecoverable"
 	ReasonWithinPolicy       = "Restart within policy"
 	ReasonDelay              = "Exceeded allowed attempts, applying a delay"
+	ReasonRunDuration        = "Exceeded max run duration"
+	ReasonDeadline           = "Exceeded deadline of the job"
 )
 
 func NewRestartTracker(policy *structs.RestartPolicy, jobType string, tlc *structs.TaskLifecycleConfig) *RestartTracker {
@@ -59,6 +61,8 @@ type RestartTracker struct {
 	startErr         error
 	killed           bool      // Whether the task has been killed
 	restartTriggered bool      // Whether the task has been signalled to be restarted
+	timedOut         bool      // Whether the task exceeded its max run duration
+	deadline         bool      // Whether the task exceeded the deadline of its job
 	failure          bool      // Whether a failure triggered the restart
 	count            int       // Current number of attempts.
 	onSuccess        bool      // Whether to restart on successful exit code.
@@ -125,6 +129,24 @@ func (r *RestartTracker) SetKilled() *RestartTracker {
 	return r
 }
 
+// SetTimedOut is used to mark that the task has been killed for exceeding its
+// max run duration. It is only restarted if the policy restarts timeouts.
+func (r *RestartTracker) SetTimedOut() *RestartTracker {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.timedOut = true
+	return r
+}
+
+// SetDeadlineExceeded is used to mark that the task has exceeded the deadline
+// of its job. It is never restarted.
+func (r *RestartTracker) SetDeadlineExceeded() *RestartTracker {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.deadline = true
+	return r
+}
+
 // GetReason returns a human-readable description for the last state returned by
 // GetState.
 func (r *RestartTracker) GetReason() string {
@@ -161,6 +183,8 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 		r.restartTriggered = false
 		r.failure = false
 		r.killed = false
+		r.timedOut = false
+		r.deadline = false
 	}()
 
 	// Hot path if task was killed
@@ -169,6 +193,16 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 		return structs.TaskKilled, 0
 	}
 
+	// Hot path if the task ran out of time
+	if r.deadline {
+		r.reason = ReasonDeadline
+		return structs.TaskNotRestarting, 0
+	}
+	if r.timedOut && !r.policy.RestartOnTimeout {
+		r.reason = ReasonRunDuration
+		return structs.TaskNotRestarting, 0
+	}
+
 	// Hot path if a restart was triggered
 	if r.restartTriggered {
 		r.reason = ""
@@ -181,7 +215,7 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 
 		// If the task does not restart on a successful exit code and
 		// the exit code was successful: terminate.
-		if !r.onSuccess && r.exitRes != nil && r.exitRes.Successful() {
+		if !r.onSuccess && !r.timedOut && r.exitRes != nil && r.exitRes.Successful() {
 			return structs.TaskTerminated, 0
 		}
 
@@ -213,8 +247,9 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 		}
 	} else if r.exitRes != nil {
 		// If the task started successfully and restart on success isn't specified,
-		// don't restart but don't mark as failed.
-		if r.exitRes.Successful() && !r.onSuccess {
+		// don't restart but don't mark as failed. Tasks that timed out did not
+		// succeed whatever their exit code.
+		if r.exitRes.Successful() && !r.onSuccess && !r.timedOut {
 			r.reason = "Restart unnecessary as task terminated successfully"
 			return structs.TaskTerminated, 0
 		}
@@ -157,6 +157,35 @@ func TestClient_RestartTracker_RestartTriggered_Failure(t *testing.T) {
 	}
 }
 
+func TestClient_RestartTracker_TimedOut(t *testing.T) {
+	ci.Parallel(t)
+	p := testPolicy(true, structs.RestartPolicyModeFail)
+
+	// Timeouts are not restarted by default, even if the task exited
+	// successfully once signalled
+	rt := NewRestartTracker(p, structs.JobTypeBatch, nil)
+	state, when := rt.SetTimedOut().SetExitResult(testExitResult(0)).GetState()
+	require.Equal(t, structs.TaskNotRestarting, state)
+	require.Zero(t, when)
+	require.Equal(t, ReasonRunDuration, rt.GetReason())
+
+	// Timeouts are restarted within the policy if it restarts them
+	p.RestartOnTimeout = true
+	rt = NewRestartTracker(p, structs.JobTypeBatch, nil)
+	state, when = rt.SetTimedOut().SetExitResult(testExitResult(0)).GetState()
+	require.Equal(t, structs.TaskRestarting, state)
+	require.True(t, withinJitter(p.Delay, when))
+
+	// The next successful exit was not a timeout
+	state, _ = rt.SetExitResult(testExitResult(0)).GetState()
+	require.Equal(t, structs.TaskTerminated, state)
+
+	// Tasks past the deadline of their job are never restarted
+	state, _ = rt.SetDeadlineExceeded().SetExitResult(testExitResult(1)).GetState()
+	require.Equal(t, structs.TaskNotRestarting, state)
+	require.Equal(t, ReasonDeadline, rt.GetReason())
+}
+
 func TestClient_RestartTracker_StartError_Recoverable_Fail(t *testing.T) {
 	ci.Parallel(t)
 	p := testPolicy(true, structs.RestartPolicyModeFail)
@@ -198,6 +198,9 @@ type TaskRunner struct {
 	// vaultClient is the client to use to derive and renew Vault tokens
 	vaultClient vaultclient.VaultClient
 
+	// rpcClient is the client to use to make RPC calls to the servers
+	rpcClient config.RPCer
+
 	// vaultToken is the current Vault token. It should be accessed with the
 	// getter.
 	vaultToken     string
@@ -208,6 +211,11 @@ type TaskRunner struct {
 	nomadToken     string
 	nomadTokenLock sync.Mutex
 
+	// workloadTokens are the current named workload identity tokens, keyed
+	// by identity name. They should be accessed with the getter.
+	workloadTokens     map[string]string
+	workloadTokensLock sync.Mutex
+
 	// baseLabels are used when emitting tagged metrics. All task runner metrics
 	// will have these tags, and optionally more.
 	baseLabels []metrics.Label
@@ -291,6 +299,10 @@ type Config struct {
 	// Vault is the client to use to derive and renew Vault tokens
 	Vault vaultclient.VaultClient
 
+	// RPCClient is the client to use to make RPC calls to the servers, such
+	// as signing workload identities
+	RPCClient config.RPCer
+
 	// StateDB is used to store and restore state.
 	StateDB cstate.StateDB
 
@@ -375,6 +387,7 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		consulProxiesClient:    config.ConsulProxies,
 		siClient:               config.ConsulSI,
 		vaultClient:            config.Vault,
+		rpcClient:              config.RPCClient,
 		state:                  tstate,
 		localState:             state.NewLocalState(),
 		allocHookResources:     config.AllocHookResources,
@@ -584,6 +597,14 @@ MAIN:
 			// yay proceed
 		}
 
+		// Do not start tasks of dispatched jobs past their deadline
+		if tr.jobDeadlineExceeded() {
+			tr.logger.Info("not starting task past the deadline of its job")
+			tr.restartTracker.SetDeadlineExceeded()
+			tr.EmitEvent(tr.deadlineEvent(structs.TaskDeadlineExceeded))
+			goto RESTART
+		}
+
 		// Run the prestart hooks
 		if err := tr.prestart(); err != nil {
 			tr.logger.Error("prestart failed", "error", err)
@@ -624,11 +645,19 @@ MAIN:
 			handle := tr.getDriverHandle()
 			result = nil
 
+			// Kill the task once it runs out of time
+			deadline, deadlineEventType := tr.runDeadline()
+			deadlineTimer, stopDeadlineTimer := helper.NewStoppedTimer()
+			if !deadline.IsZero() {
+				deadlineTimer.Reset(time.Until(deadline))
+			}
+
 			// Do *not* use tr.killCtx here as it would cause
 			// Wait() to unblock before the task exits when Kill()
 			// is called.
 			if resultCh, err := handle.WaitCh(context.Background()); err != nil {
 				tr.logger.Error("wait task failed", "error", err)
+				stopDeadlineTimer()
 			} else {
 				select {
 				case <-tr.killCtx.Done():
@@ -637,9 +666,14 @@ MAIN:
 					result = tr.handleKill(resultCh)
 				case <-tr.shutdownCtx.Done():
 					// TaskRunner was told to exit immediately
+					stopDeadlineTimer()
 					return
+				case <-deadlineTimer.C:
+					// The restart tracker knows the task ran out of time
+					result = tr.handleRunDeadline(resultCh, deadlineEventType)
 				case result = <-resultCh:
 				}
+				stopDeadlineTimer()
 
 				// WaitCh returned a result
 				if retryWait := tr.handleTaskExitResult(result); retryWait {
@@ -1304,6 +1338,7 @@ func (tr *TaskRunner) updateStateImpl(state string) error {
 		// Capture the start time if it is just starting
 		if oldState != structs.TaskStateRunning {
 			taskState.StartedAt = time.Now().UTC()
+			taskState.TimedOut = false
 			metrics.IncrCounterWithLabels([]string{"client", "allocs", "running"}, 1, tr.baseLabels)
 		}
 	case structs.TaskStateDead:
@@ -1373,6 +1408,12 @@ func (tr *TaskRunner) appendEvent(event *structs.TaskEvent) error {
 		tr.state.Failed = true
 	}
 
+	// Propagate timeouts from event to task state
+	switch event.Type {
+	case structs.TaskRunDurationExceeded, structs.TaskDeadlineExceeded:
+		tr.state.TimedOut = true
+	}
+
 	// XXX This seems like a super awkward spot for this? Why not shouldRestart?
 	// Update restart metrics
 	if event.Type == structs.TaskRestarting {
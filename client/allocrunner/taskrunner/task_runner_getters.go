@@ -85,6 +85,27 @@ func (tr *TaskRunner) getNomadToken() string {
 	return tr.nomadToken
 }
 
+// getWorkloadToken returns the current token of the named workload identity,
+// or an empty string if it has not been signed yet.
+func (tr *TaskRunner) getWorkloadToken(name string) string {
+	tr.workloadTokensLock.Lock()
+	defer tr.workloadTokensLock.Unlock()
+	return tr.workloadTokens[name]
+}
+
+// setWorkloadToken updates the token of a named workload identity on the task
+// runner as well as in the task's environment.
+func (tr *TaskRunner) setWorkloadToken(name, token string, inject bool) {
+	tr.workloadTokensLock.Lock()
+	defer tr.workloadTokensLock.Unlock()
+
+	if tr.workloadTokens == nil {
+		tr.workloadTokens = make(map[string]string)
+	}
+	tr.workloadTokens[name] = token
+	tr.envBuilder.SetNamedWorkloadToken(name, token, inject)
+}
+
 func (tr *TaskRunner) setNomadToken(token string) {
 	tr.nomadTokenLock.Lock()
 	defer tr.nomadTokenLock.Unlock()
@@ -68,7 +68,7 @@ func (tr *TaskRunner) initHooks() {
 		newLogMonHook(tr, hookLogger),
 		newDispatchHook(alloc, hookLogger),
 		newVolumeHook(tr, hookLogger),
-		newArtifactHook(tr, tr.getter, hookLogger),
+		newArtifactHook(tr, tr.getter, tr.readVariableItems, hookLogger),
 		newStatsHook(tr, tr.clientConfig.StatsCollectionInterval, hookLogger),
 		newDeviceHook(tr.devicemanager, hookLogger),
 		newAPIHook(tr.shutdownCtx, tr.clientConfig.APIListenerRegistrar, hookLogger),
@@ -89,15 +89,24 @@ func (tr *TaskRunner) initHooks() {
 
 	// If Vault is enabled, add the hook
 	if task.Vault != nil {
+		// Tasks with a Vault identity log in to Vault with it instead of
+		// requesting a token from the servers.
+		var identityName string
+		if task.LookupIdentity(structs.WorkloadIdentityVaultName) != nil {
+			identityName = structs.WorkloadIdentityVaultName
+		}
+
 		tr.runnerHooks = append(tr.runnerHooks, newVaultHook(&vaultHookConfig{
-			vaultBlock: task.Vault,
-			client:     tr.vaultClient,
-			events:     tr,
-			lifecycle:  tr,
-			updater:    tr,
-			logger:     hookLogger,
-			alloc:      tr.Alloc(),
-			task:       tr.taskName,
+			vaultBlock:   task.Vault,
+			client:       tr.vaultClient,
+			events:       tr,
+			lifecycle:    tr,
+			updater:      tr,
+			logger:       hookLogger,
+			alloc:        tr.Alloc(),
+			task:         tr.taskName,
+			identityName: identityName,
+			identities:   tr,
 		}))
 	}
 
@@ -19,6 +19,7 @@ import (
 	"github.com/kr/pretty"
 	"github.com/shoenig/test"
 	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/open-wander/wander/client/allocdir"
 	"github.com/open-wander/wander/client/allocrunner/interfaces"
 	"github.com/open-wander/wander/client/allocrunner/taskrunner/getter"
+	"github.com/open-wander/wander/client/allocrunner/taskrunner/restarts"
 	"github.com/open-wander/wander/client/config"
 	consulapi "github.com/open-wander/wander/client/consul"
 	"github.com/open-wander/wander/client/devicemanager"
@@ -1728,6 +1730,123 @@ func TestTaskRunner_DisableFileForVaultToken(t *testing.T) {
 	must.ErrorIs(t, err, os.ErrNotExist)
 }
 
+// TestTaskRunner_VaultIdentity asserts that tasks with a Vault identity log in
+// to Vault with it instead of requesting a token from the servers.
+func TestTaskRunner_VaultIdentity(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Config = map[string]any{
+		"run_for": "0s",
+	}
+	task.Vault = &structs.Vault{
+		Namespace: "ns1",
+		Role:      "nomad-workloads",
+	}
+	task.Identities = []*structs.WorkloadIdentity{
+		{
+			Name:     structs.WorkloadIdentityVaultName,
+			Audience: []string{structs.WorkloadIdentityVaultAud},
+			TTL:      time.Hour,
+		},
+	}
+
+	conf, cleanup := testTaskRunnerConfig(t, alloc, task.Name)
+	defer cleanup()
+	conf.RPCClient = newMockIdentitySigner(time.Hour)
+
+	// Setup a test Vault client which fails if the servers are asked for a
+	// token
+	token := "1234"
+	vaultClient := conf.Vault.(*vaultclient.MockVaultClient)
+	vaultClient.DeriveTokenFn = func(*structs.Allocation, []string) (map[string]string, error) {
+		return nil, fmt.Errorf("unexpected token derivation")
+	}
+	vaultClient.DeriveTokenWithJWTFn = func(context.Context, vaultclient.JWTLoginRequest) (*vaultclient.JWTLoginResponse, error) {
+		return &vaultclient.JWTLoginResponse{Token: token, Renewable: true}, nil
+	}
+
+	tr, err := NewTaskRunner(conf)
+	must.NoError(t, err)
+	defer tr.Kill(context.Background(), structs.NewTaskEvent("cleanup"))
+	go tr.Run()
+
+	testWaitForTaskToDie(t, tr)
+
+	finalState := tr.TaskState()
+	must.Eq(t, structs.TaskStateDead, finalState.State)
+	must.False(t, finalState.Failed)
+
+	must.Eq(t, []vaultclient.JWTLoginRequest{{
+		JWT:       "vault-1",
+		Role:      "nomad-workloads",
+		Namespace: "ns1",
+	}}, vaultClient.JWTLogins())
+
+	data, err := os.ReadFile(filepath.Join(conf.TaskDir.SecretsDir, vaultTokenFile))
+	must.NoError(t, err)
+	must.Eq(t, token, string(data))
+}
+
+// TestTaskRunner_VaultIdentity_NotRenewable asserts that tasks log in to Vault
+// again before a token which can't be renewed expires, applying the change
+// mode.
+func TestTaskRunner_VaultIdentity_NotRenewable(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Config = map[string]any{
+		"run_for": "10s",
+	}
+	task.Vault = &structs.Vault{
+		Role:         "nomad-workloads",
+		ChangeMode:   structs.VaultChangeModeSignal,
+		ChangeSignal: "SIGUSR1",
+	}
+	task.Identities = []*structs.WorkloadIdentity{
+		{
+			Name:     structs.WorkloadIdentityVaultName,
+			Audience: []string{structs.WorkloadIdentityVaultAud},
+			TTL:      time.Hour,
+		},
+	}
+
+	conf, cleanup := testTaskRunnerConfig(t, alloc, task.Name)
+	defer cleanup()
+	conf.RPCClient = newMockIdentitySigner(time.Hour)
+
+	vaultClient := conf.Vault.(*vaultclient.MockVaultClient)
+	vaultClient.DeriveTokenWithJWTFn = func(context.Context, vaultclient.JWTLoginRequest) (*vaultclient.JWTLoginResponse, error) {
+		return &vaultclient.JWTLoginResponse{
+			Token:         uuid.Generate(),
+			LeaseDuration: 300 * time.Millisecond,
+		}, nil
+	}
+
+	tr, err := NewTaskRunner(conf)
+	must.NoError(t, err)
+	defer tr.Kill(context.Background(), structs.NewTaskEvent("cleanup"))
+	go tr.Run()
+
+	testWaitForTaskToStart(t, tr)
+
+	testutil.WaitForResult(func() (bool, error) {
+		if logins := len(vaultClient.JWTLogins()); logins < 2 {
+			return false, fmt.Errorf("expected to log in again, got %d logins", logins)
+		}
+		for _, e := range tr.TaskState().Events {
+			if e.Type == structs.TaskSignaling {
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("no signaling event yet")
+	}, func(err error) {
+		must.NoError(t, err)
+	})
+}
+
 // TestTaskRunner_DeriveToken_Retry asserts that if a recoverable error is
 // returned when deriving a vault token a task will continue to block while
 // it's retried.
@@ -2214,6 +2333,139 @@ func TestTaskRunner_Run_RecoverableStartError(t *testing.T) {
 	require.Equal(t, structs.TaskNotRestarting, state.Events[5].Type)
 }
 
+// TestTaskRunner_MaxRunDuration asserts that tasks running longer than their
+// max run duration are killed and only restarted if the restart policy
+// restarts timeouts.
+func TestTaskRunner_MaxRunDuration(t *testing.T) {
+	ci.Parallel(t)
+
+	eventTypes := func(events []*structs.TaskEvent) []string {
+		types := make([]string, 0, len(events))
+		for _, e := range events {
+			types = append(types, e.Type)
+		}
+		return types
+	}
+
+	t.Run("not restarted", func(t *testing.T) {
+		alloc := mock.BatchAlloc()
+		task := alloc.Job.TaskGroups[0].Tasks[0]
+		task.MaxRunDuration = 200 * time.Millisecond
+		task.Config = map[string]interface{}{
+			"run_for": "10s",
+		}
+
+		tr, _, cleanup := runTestTaskRunner(t, alloc, task.Name)
+		defer cleanup()
+
+		testWaitForTaskToDie(t, tr)
+
+		state := tr.TaskState()
+		require.True(t, state.Failed)
+		require.True(t, state.TimedOut)
+		require.Contains(t, eventTypes(state.Events), structs.TaskRunDurationExceeded)
+
+		last := state.Events[len(state.Events)-1]
+		require.Equal(t, structs.TaskNotRestarting, last.Type)
+		require.Equal(t, restarts.ReasonRunDuration, last.RestartReason)
+	})
+
+	t.Run("restarted", func(t *testing.T) {
+		alloc := mock.BatchAlloc()
+		task := alloc.Job.TaskGroups[0].Tasks[0]
+		task.MaxRunDuration = 200 * time.Millisecond
+		task.Config = map[string]interface{}{
+			"run_for": "10s",
+		}
+		rp := &structs.RestartPolicy{
+			Attempts:         1,
+			Interval:         10 * time.Minute,
+			Delay:            0,
+			Mode:             structs.RestartPolicyModeFail,
+			RestartOnTimeout: true,
+		}
+		alloc.Job.TaskGroups[0].RestartPolicy = rp
+		task.RestartPolicy = rp
+
+		tr, _, cleanup := runTestTaskRunner(t, alloc, task.Name)
+		defer cleanup()
+
+		testWaitForTaskToDie(t, tr)
+
+		state := tr.TaskState()
+		require.True(t, state.Failed)
+		require.True(t, state.TimedOut)
+		require.Equal(t, uint64(1), state.Restarts)
+
+		var timeouts int
+		for _, e := range state.Events {
+			if e.Type == structs.TaskRunDurationExceeded {
+				timeouts++
+			}
+		}
+		require.Equal(t, 2, timeouts, pretty.Sprint(state.Events))
+	})
+}
+
+// TestTaskRunner_DispatchDeadline asserts that the tasks of dispatched jobs
+// are killed at the deadline of their job, and not started past it.
+func TestTaskRunner_DispatchDeadline(t *testing.T) {
+	ci.Parallel(t)
+
+	t.Run("killed", func(t *testing.T) {
+		alloc := mock.BatchAlloc()
+		alloc.Job.DispatchDeadline = time.Now().Add(500 * time.Millisecond)
+		task := alloc.Job.TaskGroups[0].Tasks[0]
+		task.MaxRunDuration = time.Hour
+		task.Config = map[string]interface{}{
+			"run_for": "10s",
+		}
+
+		tr, _, cleanup := runTestTaskRunner(t, alloc, task.Name)
+		defer cleanup()
+
+		testWaitForTaskToDie(t, tr)
+
+		state := tr.TaskState()
+		require.True(t, state.Failed)
+		require.True(t, state.TimedOut)
+
+		var exceeded *structs.TaskEvent
+		for _, e := range state.Events {
+			if e.Type == structs.TaskDeadlineExceeded {
+				exceeded = e
+			}
+		}
+		require.NotNil(t, exceeded, pretty.Sprint(state.Events))
+		require.Contains(t, exceeded.DisplayMessage, "deadline of its job")
+
+		last := state.Events[len(state.Events)-1]
+		require.Equal(t, restarts.ReasonDeadline, last.RestartReason)
+	})
+
+	t.Run("not started", func(t *testing.T) {
+		alloc := mock.BatchAlloc()
+		alloc.Job.DispatchDeadline = time.Now().Add(-time.Minute)
+		task := alloc.Job.TaskGroups[0].Tasks[0]
+		task.Config = map[string]interface{}{
+			"run_for": "10s",
+		}
+
+		tr, _, cleanup := runTestTaskRunner(t, alloc, task.Name)
+		defer cleanup()
+
+		testWaitForTaskToDie(t, tr)
+
+		state := tr.TaskState()
+		require.True(t, state.Failed)
+		require.True(t, state.TimedOut)
+		require.True(t, state.StartedAt.IsZero())
+		for _, e := range state.Events {
+			require.NotEqual(t, structs.TaskStarted, e.Type)
+		}
+	})
+}
+
 // TestTaskRunner_Template_Artifact asserts that tasks can use artifacts as templates.
 func TestTaskRunner_Template_Artifact(t *testing.T) {
 	ci.Parallel(t)
@@ -2709,3 +2961,53 @@ func TestTaskRunner_IdentityHook_Disabled(t *testing.T) {
 	taskEnv := tr.envBuilder.Build()
 	must.MapNotContainsKey(t, taskEnv.EnvMap, "NOMAD_TOKEN")
 }
+
+// TestTaskRunner_IdentityHook_Named asserts that the identity hook exposes
+// named workload identities to a task and renews them before they expire.
+func TestTaskRunner_IdentityHook_Named(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+	task.Identities = []*structs.WorkloadIdentity{
+		{
+			Name:     "consul",
+			Audience: []string{"consul.io"},
+			Env:      true,
+			File:     true,
+			TTL:      2 * time.Second,
+		},
+	}
+
+	conf, cleanup := testTaskRunnerConfig(t, alloc, task.Name)
+	defer cleanup()
+	conf.RPCClient = newMockIdentitySigner(2 * time.Second)
+
+	tr, err := NewTaskRunner(conf)
+	must.NoError(t, err)
+	go tr.Run()
+	defer tr.Kill(context.Background(), structs.NewTaskEvent("cleanup"))
+
+	testWaitForTaskToStart(t, tr)
+
+	tokenPath := filepath.Join(tr.taskDir.SecretsDir, "nomad_consul.jwt")
+	tokenBytes, err := os.ReadFile(tokenPath)
+	must.NoError(t, err)
+	must.Eq(t, "consul-1", string(tokenBytes))
+
+	taskEnv := tr.envBuilder.Build()
+	must.Eq(t, "consul-1", taskEnv.EnvMap["NOMAD_TOKEN_consul"])
+
+	// Assert the token is renewed before it expires
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool {
+			tokenBytes, err := os.ReadFile(tokenPath)
+			return err == nil && string(tokenBytes) == "consul-2"
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(100*time.Millisecond),
+	))
+}
@@ -39,6 +39,11 @@ type vaultTokenUpdateHandler interface {
 	updatedVaultToken(token string)
 }
 
+// workloadTokenGetter returns the current token of a named workload identity.
+type workloadTokenGetter interface {
+	getWorkloadToken(name string) string
+}
+
 func (tr *TaskRunner) updatedVaultToken(token string) {
 	// Update the task runner and environment
 	tr.setVaultToken(token)
@@ -56,6 +61,13 @@ type vaultHookConfig struct {
 	logger     log.Logger
 	alloc      *structs.Allocation
 	task       string
+
+	// identityName is the name of the workload identity used to log in to
+	// Vault. If empty, the Vault token is derived by the servers.
+	identityName string
+
+	// identities is used to retrieve the workload identity used to log in
+	identities workloadTokenGetter
 }
 
 type vaultHook struct {
@@ -95,6 +107,13 @@ type vaultHook struct {
 	// taskName is the name of the task
 	taskName string
 
+	// identityName is the name of the workload identity used to log in to
+	// Vault. If empty, the Vault token is derived by the servers.
+	identityName string
+
+	// identities is used to retrieve the workload identity used to log in
+	identities workloadTokenGetter
+
 	// firstRun stores whether it is the first run for the hook
 	firstRun bool
 
@@ -112,6 +131,8 @@ func newVaultHook(config *vaultHookConfig) *vaultHook {
 		updater:      config.updater,
 		alloc:        config.alloc,
 		taskName:     config.task,
+		identityName: config.identityName,
+		identities:   config.identities,
 		firstRun:     true,
 		ctx:          ctx,
 		cancel:       cancel,
@@ -208,12 +229,16 @@ OUTER:
 		// Clear the token
 		h.future.Clear()
 
+		// Tokens recovered from disk are assumed to be renewable
+		renewable := true
+		var leaseDuration time.Duration
+
 		// Check if there already is a token which can be the case for
 		// restoring the TaskRunner
 		if token == "" {
 			// Get a token
 			var exit bool
-			token, exit = h.deriveVaultToken()
+			token, renewable, leaseDuration, exit = h.deriveVaultToken()
 			if exit {
 				// Exit the manager
 				return
@@ -241,13 +266,29 @@ OUTER:
 		//
 		// If Vault is having availability issues or is overloaded, a large
 		// number of initial token renews can exacerbate the problem.
-		renewCh, err := h.client.RenewToken(token, 30)
-
-		// An error returned means the token is not being renewed
-		if err != nil {
-			h.logger.Error("failed to start renewal of Vault token", "error", err)
-			token = ""
-			goto OUTER
+		//
+		// Tokens obtained by logging in with a workload identity may not be
+		// renewable, in which case a new token is obtained by logging in
+		// again before the token expires.
+		var renewCh <-chan error
+		var expireTimer *time.Timer
+		var expireCh <-chan time.Time
+		if renewable {
+			var err error
+			renewCh, err = h.client.RenewToken(token, 30)
+
+			// An error returned means the token is not being renewed
+			if err != nil {
+				h.logger.Error("failed to start renewal of Vault token", "error", err)
+				token = ""
+				goto OUTER
+			}
+		} else if leaseDuration > 0 {
+			h.logger.Debug("Vault token is not renewable", "ttl", leaseDuration)
+			expireTimer = time.NewTimer(leaseDuration * 2 / 3)
+			expireCh = expireTimer.C
+		} else {
+			h.logger.Debug("Vault token is not renewable")
 		}
 
 		// The Vault token is valid now, so set it
@@ -301,21 +342,33 @@ OUTER:
 			h.logger.Error("failed to renew Vault token", "error", err)
 			stopRenewal()
 			updatedToken = true
+		case <-expireCh:
+			// Log in again before the token expires
+			token = ""
+			h.logger.Debug("Vault token is about to expire")
+			updatedToken = true
 		case <-h.ctx.Done():
+			if expireTimer != nil {
+				expireTimer.Stop()
+			}
 			stopRenewal()
 			return
 		}
+		if expireTimer != nil {
+			expireTimer.Stop()
+		}
 	}
 }
 
 // deriveVaultToken derives the Vault token using exponential backoffs. It
-// returns the Vault token and whether the manager should exit.
-func (h *vaultHook) deriveVaultToken() (token string, exit bool) {
+// returns the Vault token, whether it is renewable, its time to live if known
+// and whether the manager should exit.
+func (h *vaultHook) deriveVaultToken() (token string, renewable bool, ttl time.Duration, exit bool) {
 	attempts := 0
 	for {
-		tokens, err := h.client.DeriveToken(h.alloc, []string{h.taskName})
+		token, renewable, ttl, err := h.deriveToken()
 		if err == nil {
-			return tokens[h.taskName], false
+			return token, renewable, ttl, false
 		}
 
 		// Check if this is a server side error
@@ -325,7 +378,7 @@ func (h *vaultHook) deriveVaultToken() (token string, exit bool) {
 				structs.NewTaskEvent(structs.TaskKilling).
 					SetFailsTask().
 					SetDisplayMessage(fmt.Sprintf("Vault: server failed to derive vault token: %v", err)))
-			return "", true
+			return "", false, 0, true
 		}
 
 		// Check if we can't recover from the error
@@ -335,7 +388,7 @@ func (h *vaultHook) deriveVaultToken() (token string, exit bool) {
 				structs.NewTaskEvent(structs.TaskKilling).
 					SetFailsTask().
 					SetDisplayMessage(fmt.Sprintf("Vault: failed to derive vault token: %v", err)))
-			return "", true
+			return "", false, 0, true
 		}
 
 		// Handle the retry case
@@ -350,12 +403,42 @@ func (h *vaultHook) deriveVaultToken() (token string, exit bool) {
 		// Wait till retrying
 		select {
 		case <-h.ctx.Done():
-			return "", true
+			return "", false, 0, true
 		case <-time.After(backoff):
 		}
 	}
 }
 
+// deriveToken derives a Vault token for the task, either by logging in to
+// Vault with the workload identity of the task or by requesting the servers
+// to derive it. It returns the token, whether it is renewable and its time to
+// live if known.
+func (h *vaultHook) deriveToken() (string, bool, time.Duration, error) {
+	if h.identityName == "" {
+		tokens, err := h.client.DeriveToken(h.alloc, []string{h.taskName})
+		if err != nil {
+			return "", false, 0, err
+		}
+		return tokens[h.taskName], true, 0, nil
+	}
+
+	jwt := h.identities.getWorkloadToken(h.identityName)
+	if jwt == "" {
+		return "", false, 0, structs.NewRecoverableError(
+			fmt.Errorf("workload identity %q is not available", h.identityName), true)
+	}
+
+	resp, err := h.client.DeriveTokenWithJWT(h.ctx, vaultclient.JWTLoginRequest{
+		JWT:       jwt,
+		Role:      h.vaultBlock.Role,
+		Namespace: h.vaultBlock.Namespace,
+	})
+	if err != nil {
+		return "", false, 0, err
+	}
+	return resp.Token, resp.Renewable, resp.LeaseDuration, nil
+}
+
 // writeToken writes the given token to disk
 func (h *vaultHook) writeToken(token string) error {
 	// Handle upgrade path by first checking if the tasks private directory
@@ -6,8 +6,10 @@ package allocwatcher
 import (
 	"archive/tar"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sync"
@@ -21,6 +23,7 @@ import (
 	cstructs "github.com/open-wander/wander/client/structs"
 	"github.com/open-wander/wander/helper"
 	"github.com/open-wander/wander/nomad/structs"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -71,6 +74,11 @@ type Config struct {
 	// enabled.
 	MigrateToken string
 
+	// MigrateLimiter limits the bandwidth of the migrations of remote alloc
+	// dirs. It's shared by all the migrations of the client and nil if the
+	// bandwidth is not limited.
+	MigrateLimiter *rate.Limiter
+
 	Logger hclog.Logger
 }
 
@@ -103,6 +111,8 @@ func newMigratorForAlloc(c Config, tg *structs.TaskGroup, watchedAllocID string,
 		migrate:      migrate,
 		rpc:          c.RPC,
 		migrateToken: c.MigrateToken,
+		limiter:      c.MigrateLimiter,
+		retryWait:    migrateChunkRetryWait,
 		logger:       logger,
 	}
 }
@@ -262,7 +272,7 @@ func (p *localPrevAlloc) Wait(ctx context.Context) error {
 }
 
 // Migrate from previous local alloc dir to destination alloc dir.
-func (p *localPrevAlloc) Migrate(ctx context.Context, dest *allocdir.AllocDir) error {
+func (p *localPrevAlloc) Migrate(ctx context.Context, dest *allocdir.AllocDir, _ func(*cstructs.MigrateProgress)) error {
 	if !p.sticky {
 		// Not a sticky volume, nothing to migrate
 		return nil
@@ -320,6 +330,12 @@ type remotePrevAlloc struct {
 	// migrateToken allows a client to migrate data in an ACL-protected remote
 	// volume
 	migrateToken string
+
+	// limiter limits the bandwidth of the migration, if not nil.
+	limiter *rate.Limiter
+
+	// retryWait is the initial wait before retrying to fetch a chunk.
+	retryWait time.Duration
 }
 
 // IsWaiting returns true if there's a concurrent call inside Wait
@@ -425,7 +441,7 @@ func (p *remotePrevAlloc) Wait(ctx context.Context) error {
 
 // Migrate alloc data from a remote node if the new alloc has migration enabled
 // and the old alloc hasn't been GC'd.
-func (p *remotePrevAlloc) Migrate(ctx context.Context, dest *allocdir.AllocDir) error {
+func (p *remotePrevAlloc) Migrate(ctx context.Context, dest *allocdir.AllocDir, progress func(*cstructs.MigrateProgress)) error {
 	if !p.migrate {
 		// Volume wasn't configured to be migrated, return early
 		return nil
@@ -453,7 +469,7 @@ func (p *remotePrevAlloc) Migrate(ctx context.Context, dest *allocdir.AllocDir)
 		return err
 	}
 
-	prevAllocDir, err := p.migrateAllocDir(ctx, addr)
+	prevAllocDir, err := p.migrateAllocDir(ctx, addr, progress)
 	if err != nil {
 		return err
 	}
@@ -511,7 +527,11 @@ func (p *remotePrevAlloc) getNodeAddr(ctx context.Context, nodeID string) (strin
 
 // migrate a remote alloc dir to local node. Caller is responsible for calling
 // Destroy on the returned allocdir if no error occurs.
-func (p *remotePrevAlloc) migrateAllocDir(ctx context.Context, nodeAddr string) (*allocdir.AllocDir, error) {
+//
+// The alloc dir is migrated in compressed chunks, resuming an interrupted
+// migration from its last completed chunk. Nodes which don't support chunked
+// migrations stream a snapshot of the alloc dir instead.
+func (p *remotePrevAlloc) migrateAllocDir(ctx context.Context, nodeAddr string, progress func(*cstructs.MigrateProgress)) (*allocdir.AllocDir, error) {
 	// Create the previous alloc dir
 	prevAllocDir := allocdir.NewAllocDir(p.logger, p.config.AllocDir, p.prevAllocID)
 	if err := prevAllocDir.Build(); err != nil {
@@ -532,6 +552,41 @@ func (p *remotePrevAlloc) migrateAllocDir(ctx context.Context, nodeAddr string)
 		return nil, err
 	}
 
+	// Keep the chunks migrated so far on failure, so that the next attempt
+	// resumes the migration.
+	cleanup := func() {
+		if !hasMigrateCheckpoint(prevAllocDir.AllocDir) {
+			prevAllocDir.Destroy()
+		}
+	}
+
+	manifest, err := p.getSnapshotManifest(apiClient)
+	if err == nil {
+		// The chunks migrated for another manifest can't be resumed
+		if migrateCheckpointStale(prevAllocDir.AllocDir, manifest) {
+			p.logger.Debug("previous alloc changed since the last migration attempt; restarting migration")
+			if err := prevAllocDir.Destroy(); err != nil {
+				return nil, fmt.Errorf("error destroying alloc dir for previous alloc %q: %v", p.prevAllocID, err)
+			}
+			if err := prevAllocDir.Build(); err != nil {
+				return nil, fmt.Errorf("error building alloc dir for previous alloc %q: %v", p.prevAllocID, err)
+			}
+		}
+
+		if err := p.migrateChunks(ctx, apiClient, manifest, prevAllocDir.AllocDir, progress); err != nil {
+			cleanup()
+			return nil, err
+		}
+		return prevAllocDir, nil
+	}
+
+	var respErr nomadapi.UnexpectedResponseError
+	if !errors.As(err, &respErr) || respErr.StatusCode() != http.StatusNotFound {
+		cleanup()
+		return nil, fmt.Errorf("error getting snapshot manifest from previous alloc %q: %v", p.prevAllocID, err)
+	}
+	p.logger.Debug("previous node doesn't support chunked migrations; streaming snapshot")
+
 	url := fmt.Sprintf("/v1/client/allocation/%v/snapshot", p.prevAllocID)
 	qo := &nomadapi.QueryOptions{AuthToken: p.migrateToken}
 	resp, err := apiClient.Raw().Response(url, qo)
@@ -677,7 +732,9 @@ type NoopPrevAlloc struct{}
 func (NoopPrevAlloc) Wait(context.Context) error { return nil }
 
 // Migrate returns nil immediately.
-func (NoopPrevAlloc) Migrate(context.Context, *allocdir.AllocDir) error { return nil }
+func (NoopPrevAlloc) Migrate(context.Context, *allocdir.AllocDir, func(*cstructs.MigrateProgress)) error {
+	return nil
+}
 
 func (NoopPrevAlloc) IsWaiting() bool   { return false }
 func (NoopPrevAlloc) IsMigrating() bool { return false }
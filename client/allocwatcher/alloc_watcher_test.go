@@ -108,7 +108,7 @@ func TestPrevAlloc_Noop(t *testing.T) {
 	go func() {
 		watcher.Wait(context.Background())
 		done <- 1
-		migrator.Migrate(context.Background(), nil)
+		migrator.Migrate(context.Background(), nil, nil)
 		done <- 1
 	}()
 	require.False(t, watcher.IsWaiting())
@@ -0,0 +1,390 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package allocwatcher
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	nomadapi "github.com/open-wander/wander/api"
+	"github.com/open-wander/wander/client/allocdir"
+	cstructs "github.com/open-wander/wander/client/structs"
+	"github.com/open-wander/wander/helper"
+	"github.com/open-wander/wander/helper/escapingfs"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// migrateCheckpointFile is the file of the previous alloc dir recording
+	// the chunks migrated so far, so an interrupted migration is resumed.
+	migrateCheckpointFile = ".nomad-migrate-checkpoint.json"
+
+	// migrateChunkAttempts is the number of times fetching a chunk is
+	// attempted before failing the migration.
+	migrateChunkAttempts = 5
+
+	// migrateChunkRetryWait is the initial wait before retrying to fetch a
+	// chunk. It doubles on every attempt, up to migrateChunkMaxRetryWait.
+	migrateChunkRetryWait    = 1 * time.Second
+	migrateChunkMaxRetryWait = 30 * time.Second
+
+	// migrateMaxBurst is the maximum burst of bytes of the migration
+	// bandwidth limiter.
+	migrateMaxBurst = 1024 * 1024
+)
+
+// NewMigrateLimiter returns a limiter of the bandwidth of migrations to limit
+// bytes per second, or nil if limit is zero.
+func NewMigrateLimiter(limit int64) *rate.Limiter {
+	if limit <= 0 {
+		return nil
+	}
+	burst := limit
+	if burst > migrateMaxBurst {
+		burst = migrateMaxBurst
+	}
+	return rate.NewLimiter(rate.Limit(limit), int(burst))
+}
+
+// migrateCheckpoint records the progress of the migration of a snapshot
+// manifest.
+type migrateCheckpoint struct {
+	// Manifest is the checksum of the manifest being migrated, so that the
+	// checkpoint is ignored if the previous alloc dir changed.
+	Manifest string
+
+	// Chunks is the number of chunks of the manifest migrated so far.
+	Chunks int
+}
+
+// getSnapshotManifest gets the snapshot manifest of the previous alloc.
+func (p *remotePrevAlloc) getSnapshotManifest(apiClient *nomadapi.Client) (*cstructs.AllocSnapshotManifest, error) {
+	url := fmt.Sprintf("/v1/client/allocation/%v/snapshot-manifest", p.prevAllocID)
+	qo := &nomadapi.QueryOptions{AuthToken: p.migrateToken}
+
+	var manifest cstructs.AllocSnapshotManifest
+	if _, err := apiClient.Raw().Query(url, &manifest, qo); err != nil {
+		return nil, err
+	}
+	if manifest.ChunkSize <= 0 || manifest.ChunkSize > allocdir.MaxSnapshotChunkSize {
+		return nil, fmt.Errorf("invalid snapshot chunk size %d", manifest.ChunkSize)
+	}
+	return &manifest, nil
+}
+
+// migrateChunks migrates the entries of the snapshot manifest of the previous
+// alloc to dest, skipping the chunks migrated by an earlier attempt. The
+// migrated chunks are kept in dest on error, so the migration can be resumed.
+func (p *remotePrevAlloc) migrateChunks(ctx context.Context, apiClient *nomadapi.Client,
+	manifest *cstructs.AllocSnapshotManifest, dest string, progress func(*cstructs.MigrateProgress)) error {
+
+	checksum, err := manifestChecksum(manifest)
+	if err != nil {
+		return err
+	}
+	checkpointPath := filepath.Join(dest, migrateCheckpointFile)
+	checkpoint := readMigrateCheckpoint(checkpointPath, checksum)
+
+	state := &cstructs.MigrateProgress{
+		PrevAllocID: p.prevAllocID,
+		Chunks:      checkpoint.Chunks,
+		TotalChunks: manifest.Chunks(),
+		Bytes:       migratedBytes(manifest, checkpoint.Chunks),
+		TotalBytes:  manifest.Size(),
+		Resumed:     checkpoint.Chunks > 0,
+	}
+	report := func() {
+		if progress != nil {
+			s := *state
+			progress(&s)
+		}
+	}
+
+	p.logger.Debug("migrating previous alloc in chunks", "destination", dest,
+		"chunks", state.TotalChunks, "bytes", state.TotalBytes, "resumed_chunks", checkpoint.Chunks)
+	report()
+
+	// Cache effective uid as we only run Chown if we're root
+	euid := syscall.Geteuid()
+
+	// chunk is the index of the first chunk of the next file
+	chunk := 0
+	for _, entry := range manifest.Entries {
+		if err := ctx.Err(); err != nil {
+			p.logger.Info("migration of previous alloc canceled")
+			return err
+		}
+
+		name := filepath.Join(dest, entry.Name)
+		if escapingfs.PathEscapesSandbox(dest, name) {
+			return fmt.Errorf("snapshot entry %q escapes the alloc dir", entry.Name)
+		}
+
+		switch entry.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(name, os.FileMode(entry.Mode)); err != nil {
+				return fmt.Errorf("error creating directory: %v", err)
+			}
+
+			// Can't change owner if not root or on Windows.
+			if euid == 0 {
+				if err := os.Chown(name, entry.Uid, entry.Gid); err != nil {
+					return fmt.Errorf("error chowning directory %v", err)
+				}
+			}
+
+		case tar.TypeSymlink:
+			// The symlink may exist if the migration is resumed.
+			if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("error creating symlink: %v", err)
+			}
+			if err := os.Symlink(entry.Linkname, name); err != nil {
+				return fmt.Errorf("error creating symlink: %v", err)
+			}
+
+		case tar.TypeReg:
+			// Skip the files migrated by an earlier attempt
+			chunks := entry.Chunks(manifest.ChunkSize)
+			if chunks == 0 || chunk+chunks > checkpoint.Chunks {
+				if err := p.migrateFile(ctx, apiClient, manifest.ChunkSize, entry, name, chunk,
+					checkpoint, checkpointPath, state, report); err != nil {
+					return err
+				}
+			}
+			chunk += chunks
+
+			// Setting the permissions of the file as the origin.
+			if err := os.Chmod(name, os.FileMode(entry.Mode)); err != nil {
+				return fmt.Errorf("error chmoding file %v", err)
+			}
+
+			// Can't change owner if not root or on Windows.
+			if euid == 0 {
+				if err := os.Chown(name, entry.Uid, entry.Gid); err != nil {
+					return fmt.Errorf("error chowning file %v", err)
+				}
+			}
+		}
+	}
+
+	// The checkpoint is no longer needed once the migration completed.
+	os.Remove(checkpointPath)
+	return nil
+}
+
+// migrateFile migrates the chunks of a regular file of the snapshot manifest
+// to path, recording a checkpoint after each chunk. First is the index of the
+// first chunk of the file in the manifest. The caller sets the permissions of
+// the file once complete.
+func (p *remotePrevAlloc) migrateFile(ctx context.Context, apiClient *nomadapi.Client, chunkSize int64,
+	entry *cstructs.AllocSnapshotEntry, path string, first int, checkpoint *migrateCheckpoint,
+	checkpointPath string, state *cstructs.MigrateProgress, report func()) error {
+
+	// The file is writable until complete, as the migration of a read-only
+	// file may be resumed.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("error creating file: %v", err)
+	}
+	defer f.Close()
+
+	for i, offset := first, int64(0); offset < entry.Size; i, offset = i+1, offset+chunkSize {
+		if i < checkpoint.Chunks {
+			// Chunk migrated by an earlier attempt
+			continue
+		}
+
+		length := chunkSize
+		if remaining := entry.Size - offset; remaining < length {
+			length = remaining
+		}
+
+		data, err := p.getSnapshotChunk(ctx, apiClient, entry.Name, offset, length)
+		if err != nil {
+			return err
+		}
+		if int64(len(data)) != length {
+			return fmt.Errorf("error migrating %q: expected chunk of %d bytes at offset %d but got %d bytes",
+				entry.Name, length, offset, len(data))
+		}
+		if _, err := f.WriteAt(data, offset); err != nil {
+			return fmt.Errorf("error writing to file %q: %v", f.Name(), err)
+		}
+
+		state.Chunks++
+		state.Bytes += length
+		checkpoint.Chunks = state.Chunks
+		if err := writeMigrateCheckpoint(checkpointPath, checkpoint); err != nil {
+			p.logger.Warn("failed to record migration checkpoint", "error", err)
+		}
+		report()
+	}
+	return f.Close()
+}
+
+// getSnapshotChunk fetches a chunk of a file of the previous alloc and
+// verifies its checksum, retrying with a backoff on failure.
+func (p *remotePrevAlloc) getSnapshotChunk(ctx context.Context, apiClient *nomadapi.Client,
+	name string, offset, length int64) ([]byte, error) {
+
+	url := fmt.Sprintf("/v1/client/allocation/%v/snapshot-chunk", p.prevAllocID)
+	qo := &nomadapi.QueryOptions{
+		AuthToken: p.migrateToken,
+		Params: map[string]string{
+			"path":   name,
+			"offset": strconv.FormatInt(offset, 10),
+			"length": strconv.FormatInt(length, 10),
+		},
+	}
+
+	wait := p.retryWait
+	for attempt := 1; ; attempt++ {
+		data, err := p.fetchSnapshotChunk(ctx, apiClient, url, qo)
+		if err == nil {
+			return data, nil
+		}
+		if attempt >= migrateChunkAttempts {
+			return nil, fmt.Errorf("error migrating %q from previous alloc %q: %v", name, p.prevAllocID, err)
+		}
+
+		p.logger.Warn("failed to migrate chunk of previous alloc; retrying",
+			"file", name, "offset", offset, "attempt", attempt, "wait", wait, "error", err)
+		timer, stop := helper.NewSafeTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			stop()
+			return nil, ctx.Err()
+		}
+		wait *= 2
+		if wait > migrateChunkMaxRetryWait {
+			wait = migrateChunkMaxRetryWait
+		}
+	}
+}
+
+func (p *remotePrevAlloc) fetchSnapshotChunk(ctx context.Context, apiClient *nomadapi.Client,
+	url string, qo *nomadapi.QueryOptions) ([]byte, error) {
+
+	resp, err := apiClient.Raw().Response(url, qo)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+
+	var r io.Reader = resp
+	if p.limiter != nil {
+		r = &rateLimitedReader{ctx: ctx, r: resp, limiter: p.limiter}
+	}
+	return allocdir.ReadSnapshotChunk(r)
+}
+
+// migratedBytes returns the size in bytes of the first chunks of a snapshot
+// manifest.
+func migratedBytes(manifest *cstructs.AllocSnapshotManifest, chunks int) int64 {
+	var bytes int64
+	for _, e := range manifest.Entries {
+		if chunks <= 0 {
+			break
+		}
+		n := e.Chunks(manifest.ChunkSize)
+		if n <= chunks {
+			bytes += e.Size
+		} else {
+			bytes += int64(chunks) * manifest.ChunkSize
+		}
+		chunks -= n
+	}
+	return bytes
+}
+
+// manifestChecksum returns the checksum of a snapshot manifest.
+func manifestChecksum(manifest *cstructs.AllocSnapshotManifest) (string, error) {
+	buf, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// readMigrateCheckpoint returns the checkpoint of the migration of the
+// manifest with the given checksum, or an empty checkpoint if there is none.
+func readMigrateCheckpoint(path, manifest string) *migrateCheckpoint {
+	empty := &migrateCheckpoint{Manifest: manifest}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return empty
+	}
+	var checkpoint migrateCheckpoint
+	if err := json.Unmarshal(buf, &checkpoint); err != nil || checkpoint.Manifest != manifest {
+		return empty
+	}
+	return &checkpoint
+}
+
+// hasMigrateCheckpoint returns whether dest holds the chunks of an incomplete
+// migration.
+func hasMigrateCheckpoint(dest string) bool {
+	_, err := os.Stat(filepath.Join(dest, migrateCheckpointFile))
+	return err == nil
+}
+
+// migrateCheckpointStale returns whether dest holds the chunks of an
+// incomplete migration of another manifest, which can't be resumed.
+func migrateCheckpointStale(dest string, manifest *cstructs.AllocSnapshotManifest) bool {
+	if !hasMigrateCheckpoint(dest) {
+		return false
+	}
+	checksum, err := manifestChecksum(manifest)
+	if err != nil {
+		return true
+	}
+	return readMigrateCheckpoint(filepath.Join(dest, migrateCheckpointFile), checksum).Chunks == 0
+}
+
+// writeMigrateCheckpoint records the checkpoint of a migration.
+func writeMigrateCheckpoint(path string, checkpoint *migrateCheckpoint) error {
+	buf, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// rateLimitedReader limits the rate at which its reader is read.
+type rateLimitedReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *rate.Limiter
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	if burst := r.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if waitErr := r.limiter.WaitN(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
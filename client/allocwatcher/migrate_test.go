@@ -0,0 +1,339 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package allocwatcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/client/allocdir"
+	"github.com/open-wander/wander/client/config"
+	cstructs "github.com/open-wander/wander/client/structs"
+	"github.com/open-wander/wander/helper/testlog"
+	"github.com/open-wander/wander/helper/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// migrateTestChunkSize is the chunk size of the manifests served by
+// migrateTestServer.
+const migrateTestChunkSize = 4
+
+// migrateTestServer serves the snapshot endpoints of a source alloc dir.
+type migrateTestServer struct {
+	src *allocdir.AllocDir
+
+	// legacy makes the server only support snapshot streaming.
+	legacy bool
+
+	// corrupt is the number of chunk responses to corrupt.
+	corrupt int
+
+	// failAfter makes the chunk requests fail once that many chunks were
+	// served, if set.
+	failAfter int
+
+	mu     sync.Mutex
+	chunks int
+}
+
+func (s *migrateTestServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/snapshot"):
+		s.src.Snapshot(w)
+
+	case strings.HasSuffix(req.URL.Path, "/snapshot-manifest") && !s.legacy:
+		manifest, err := s.src.SnapshotManifest()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		manifest.ChunkSize = migrateTestChunkSize
+		json.NewEncoder(w).Encode(manifest)
+
+	case strings.HasSuffix(req.URL.Path, "/snapshot-chunk") && !s.legacy:
+		q := req.URL.Query()
+		offset, _ := strconv.ParseInt(q.Get("offset"), 10, 64)
+		length, _ := strconv.ParseInt(q.Get("length"), 10, 64)
+
+		buf := new(bytes.Buffer)
+		if err := s.src.SnapshotChunk(q.Get("path"), offset, length, buf); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		if s.failAfter > 0 && s.chunks >= s.failAfter {
+			s.mu.Unlock()
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		s.chunks++
+		corrupt := s.corrupt > 0
+		s.corrupt--
+		s.mu.Unlock()
+		if corrupt {
+			// Drop the end of the gzip stream
+			buf.Truncate(buf.Len() - 4)
+		}
+		w.Write(buf.Bytes())
+
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (s *migrateTestServer) chunkRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.chunks
+}
+
+// newMigrateTestSource returns a source alloc dir with a file of 3 chunks in
+// its data dir, and an empty file and a symlink in the local dir of its task.
+func newMigrateTestSource(t *testing.T) *allocdir.AllocDir {
+	src := allocdir.NewAllocDir(testlog.HCLogger(t), t.TempDir(), uuid.Generate())
+	require.NoError(t, src.Build())
+	t.Cleanup(func() { src.Destroy() })
+	td := src.NewTaskDir("web")
+	require.NoError(t, td.Build(false, nil))
+
+	dataDir := filepath.Join(src.SharedDir, allocdir.SharedDataDir)
+	require.NoError(t, os.Mkdir(filepath.Join(dataDir, "sub"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "sub", "cache"), []byte("hello world"), 0440))
+	require.NoError(t, os.WriteFile(filepath.Join(td.LocalDir, "empty"), nil, 0600))
+	require.NoError(t, os.Symlink("empty", filepath.Join(td.LocalDir, "link")))
+	return src
+}
+
+func newMigrateTestPrevAlloc(t *testing.T, src *allocdir.AllocDir) *remotePrevAlloc {
+	conf := config.DefaultConfig()
+	conf.AllocDir = t.TempDir()
+
+	return &remotePrevAlloc{
+		allocID:     uuid.Generate(),
+		prevAllocID: filepath.Base(src.AllocDir),
+		config:      conf,
+		migrate:     true,
+		retryWait:   time.Millisecond,
+		logger:      testlog.HCLogger(t),
+	}
+}
+
+// requireMigrated asserts the data of the source alloc dir was migrated.
+func requireMigrated(t *testing.T, dest string) {
+	content, err := os.ReadFile(filepath.Join(dest, "alloc", "data", "sub", "cache"))
+	require.NoError(t, err)
+	require.Equal(t, "hello world", string(content))
+
+	info, err := os.Stat(filepath.Join(dest, "alloc", "data", "sub", "cache"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0440), info.Mode().Perm())
+
+	info, err = os.Stat(filepath.Join(dest, "web", "local", "empty"))
+	require.NoError(t, err)
+	require.Zero(t, info.Size())
+
+	link, err := os.Readlink(filepath.Join(dest, "web", "local", "link"))
+	require.NoError(t, err)
+	require.Equal(t, "empty", link)
+
+	require.NoFileExists(t, filepath.Join(dest, migrateCheckpointFile))
+}
+
+func TestPrevAlloc_MigrateChunks(t *testing.T) {
+	ci.Parallel(t)
+
+	src := newMigrateTestSource(t)
+	server := &migrateTestServer{src: src}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	prevAlloc := newMigrateTestPrevAlloc(t, src)
+	prevAlloc.limiter = NewMigrateLimiter(1024)
+
+	var reports []*cstructs.MigrateProgress
+	dir, err := prevAlloc.migrateAllocDir(context.Background(), ts.URL, func(p *cstructs.MigrateProgress) {
+		reports = append(reports, p)
+	})
+	require.NoError(t, err)
+	defer dir.Destroy()
+
+	requireMigrated(t, dir.AllocDir)
+	require.Equal(t, 3, server.chunkRequests())
+
+	// The progress is reported on start and after each chunk
+	require.Len(t, reports, 4)
+	require.Equal(t, &cstructs.MigrateProgress{
+		PrevAllocID: prevAlloc.prevAllocID,
+		TotalChunks: 3,
+		TotalBytes:  11,
+	}, reports[0])
+	require.Equal(t, &cstructs.MigrateProgress{
+		PrevAllocID: prevAlloc.prevAllocID,
+		Chunks:      3,
+		TotalChunks: 3,
+		Bytes:       11,
+		TotalBytes:  11,
+	}, reports[3])
+}
+
+func TestPrevAlloc_MigrateChunks_Resume(t *testing.T) {
+	ci.Parallel(t)
+
+	src := newMigrateTestSource(t)
+	server := &migrateTestServer{src: src}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	prevAlloc := newMigrateTestPrevAlloc(t, src)
+
+	// Record that the first 2 chunks were migrated by an earlier attempt
+	manifest, err := src.SnapshotManifest()
+	require.NoError(t, err)
+	manifest.ChunkSize = migrateTestChunkSize
+	checksum, err := manifestChecksum(manifest)
+	require.NoError(t, err)
+
+	dest := filepath.Join(prevAlloc.config.AllocDir, prevAlloc.prevAllocID)
+	require.NoError(t, os.MkdirAll(filepath.Join(dest, "alloc", "data", "sub"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "alloc", "data", "sub", "cache"), []byte("hello wo"), 0600))
+	require.NoError(t, writeMigrateCheckpoint(filepath.Join(dest, migrateCheckpointFile),
+		&migrateCheckpoint{Manifest: checksum, Chunks: 2}))
+
+	var reports []*cstructs.MigrateProgress
+	dir, err := prevAlloc.migrateAllocDir(context.Background(), ts.URL, func(p *cstructs.MigrateProgress) {
+		reports = append(reports, p)
+	})
+	require.NoError(t, err)
+	defer dir.Destroy()
+
+	// Only the last chunk was fetched
+	requireMigrated(t, dir.AllocDir)
+	require.Equal(t, 1, server.chunkRequests())
+	require.Len(t, reports, 2)
+	require.True(t, reports[0].Resumed)
+	require.Equal(t, 2, reports[0].Chunks)
+	require.Equal(t, int64(8), reports[0].Bytes)
+}
+
+func TestPrevAlloc_MigrateChunks_Retry(t *testing.T) {
+	ci.Parallel(t)
+
+	src := newMigrateTestSource(t)
+	server := &migrateTestServer{src: src, corrupt: 2}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	prevAlloc := newMigrateTestPrevAlloc(t, src)
+	dir, err := prevAlloc.migrateAllocDir(context.Background(), ts.URL, nil)
+	require.NoError(t, err)
+	defer dir.Destroy()
+
+	// The corrupted chunks were fetched again
+	requireMigrated(t, dir.AllocDir)
+	require.Equal(t, 5, server.chunkRequests())
+
+	// The migration fails once the attempts are exhausted
+	server.mu.Lock()
+	server.corrupt = migrateChunkAttempts
+	server.mu.Unlock()
+	prevAlloc = newMigrateTestPrevAlloc(t, src)
+	_, err = prevAlloc.migrateAllocDir(context.Background(), ts.URL, nil)
+	require.ErrorContains(t, err, "error migrating")
+	require.NoDirExists(t, filepath.Join(prevAlloc.config.AllocDir, prevAlloc.prevAllocID))
+}
+
+func TestPrevAlloc_MigrateChunks_ResumeAfterFailure(t *testing.T) {
+	ci.Parallel(t)
+
+	src := newMigrateTestSource(t)
+	server := &migrateTestServer{src: src, failAfter: 2}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	// The migration fails after 2 chunks
+	prevAlloc := newMigrateTestPrevAlloc(t, src)
+	_, err := prevAlloc.migrateAllocDir(context.Background(), ts.URL, nil)
+	require.ErrorContains(t, err, "error migrating")
+
+	// The migrated chunks are kept for the next attempt
+	dest := filepath.Join(prevAlloc.config.AllocDir, prevAlloc.prevAllocID)
+	content, err := os.ReadFile(filepath.Join(dest, "alloc", "data", "sub", "cache"))
+	require.NoError(t, err)
+	require.Equal(t, "hello wo", string(content[:8]))
+	require.FileExists(t, filepath.Join(dest, migrateCheckpointFile))
+
+	server.mu.Lock()
+	server.failAfter = 0
+	server.chunks = 0
+	server.mu.Unlock()
+
+	var reports []*cstructs.MigrateProgress
+	dir, err := prevAlloc.migrateAllocDir(context.Background(), ts.URL, func(p *cstructs.MigrateProgress) {
+		reports = append(reports, p)
+	})
+	require.NoError(t, err)
+	defer dir.Destroy()
+
+	// Only the last chunk was fetched
+	requireMigrated(t, dir.AllocDir)
+	require.Equal(t, 1, server.chunkRequests())
+	require.True(t, reports[0].Resumed)
+}
+
+func TestPrevAlloc_MigrateChunks_Stale(t *testing.T) {
+	ci.Parallel(t)
+
+	src := newMigrateTestSource(t)
+	server := &migrateTestServer{src: src}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	prevAlloc := newMigrateTestPrevAlloc(t, src)
+
+	// Record the chunks of an earlier attempt for another manifest
+	dest := filepath.Join(prevAlloc.config.AllocDir, prevAlloc.prevAllocID)
+	require.NoError(t, os.MkdirAll(filepath.Join(dest, "alloc", "data"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "alloc", "data", "removed"), []byte("x"), 0600))
+	require.NoError(t, writeMigrateCheckpoint(filepath.Join(dest, migrateCheckpointFile),
+		&migrateCheckpoint{Manifest: "other", Chunks: 2}))
+
+	dir, err := prevAlloc.migrateAllocDir(context.Background(), ts.URL, nil)
+	require.NoError(t, err)
+	defer dir.Destroy()
+
+	// The migration was restarted
+	requireMigrated(t, dir.AllocDir)
+	require.Equal(t, 3, server.chunkRequests())
+	require.NoFileExists(t, filepath.Join(dest, "alloc", "data", "removed"))
+}
+
+func TestPrevAlloc_MigrateChunks_Legacy(t *testing.T) {
+	ci.Parallel(t)
+
+	src := newMigrateTestSource(t)
+	server := &migrateTestServer{src: src, legacy: true}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	// Nodes without chunked migrations stream a snapshot
+	prevAlloc := newMigrateTestPrevAlloc(t, src)
+	dir, err := prevAlloc.migrateAllocDir(context.Background(), ts.URL, nil)
+	require.NoError(t, err)
+	defer dir.Destroy()
+
+	requireMigrated(t, dir.AllocDir)
+	require.Zero(t, server.chunkRequests())
+}
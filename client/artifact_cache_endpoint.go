@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/open-wander/wander/client/allocrunner/taskrunner/getter"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+// ArtifactCache endpoint is used to inspect and purge the artifact cache of
+// the client.
+type ArtifactCache struct {
+	c *Client
+}
+
+func newArtifactCacheEndpoint(c *Client) *ArtifactCache {
+	return &ArtifactCache{c: c}
+}
+
+// List is used to list the cached artifacts.
+func (a *ArtifactCache) List(args *structs.NodeSpecificRequest, reply *structs.ArtifactCacheListResponse) error {
+	defer metrics.MeasureSince([]string{"client", "artifact_cache", "list"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	if a.c.artifactCache == nil {
+		reply.Entries = []*structs.ArtifactCacheEntry{}
+		return nil
+	}
+	*reply = *a.c.artifactCache.List()
+	return nil
+}
+
+// Purge is used to remove an artifact, or all of them, from the cache.
+func (a *ArtifactCache) Purge(args *structs.ArtifactCachePurgeRequest, reply *structs.ArtifactCachePurgeResponse) error {
+	defer metrics.MeasureSince([]string{"client", "artifact_cache", "purge"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if a.c.artifactCache == nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "artifact cache is disabled")
+	}
+
+	purged, size, err := a.c.artifactCache.Purge(args.Key)
+	switch {
+	case errors.Is(err, getter.ErrCacheEntryNotFound):
+		return structs.NewErrRPCCoded(http.StatusNotFound, err.Error())
+	case errors.Is(err, getter.ErrCacheEntryInUse):
+		return structs.NewErrRPCCoded(http.StatusConflict, err.Error())
+	case err != nil:
+		return err
+	}
+
+	reply.Purged = purged
+	reply.Size = size
+	return nil
+}
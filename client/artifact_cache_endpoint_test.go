@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"testing"
+
+	"github.com/open-wander/wander/acl"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/client/config"
+	"github.com/open-wander/wander/nomad"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/structs"
+	sconfig "github.com/open-wander/wander/nomad/structs/config"
+	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
+)
+
+// enableArtifactCache enables the artifact cache of a test client.
+func enableArtifactCache(t *testing.T, c *config.Config) {
+	ac, err := config.ArtifactConfigFromAgent(sconfig.DefaultArtifactConfig())
+	must.NoError(t, err)
+	ac.CacheSize = 1 << 20
+	c.Artifact = ac
+}
+
+func TestArtifactCache_Disabled(t *testing.T) {
+	ci.Parallel(t)
+
+	c1, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	var listResp structs.ArtifactCacheListResponse
+	err := c1.ClientRPC("ArtifactCache.List", &structs.NodeSpecificRequest{}, &listResp)
+	must.NoError(t, err)
+	must.False(t, listResp.Enabled)
+
+	var purgeResp structs.ArtifactCachePurgeResponse
+	err = c1.ClientRPC("ArtifactCache.Purge", &structs.ArtifactCachePurgeRequest{}, &purgeResp)
+	must.ErrorContains(t, err, "artifact cache is disabled")
+}
+
+func TestArtifactCache_ListPurge(t *testing.T) {
+	ci.Parallel(t)
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		enableArtifactCache(t, c)
+	})
+	defer cleanup()
+
+	// The cache defaults to the state dir
+	must.DirExists(t, c1.GetConfig().Artifact.CacheDir)
+
+	var listResp structs.ArtifactCacheListResponse
+	err := c1.ClientRPC("ArtifactCache.List", &structs.NodeSpecificRequest{}, &listResp)
+	must.NoError(t, err)
+	must.True(t, listResp.Enabled)
+	must.Eq(t, 1<<20, listResp.MaxSize)
+	must.SliceEmpty(t, listResp.Entries)
+
+	var purgeResp structs.ArtifactCachePurgeResponse
+	err = c1.ClientRPC("ArtifactCache.Purge", &structs.ArtifactCachePurgeRequest{Key: "unknown"}, &purgeResp)
+	must.ErrorContains(t, err, "artifact not found in cache")
+	code, _, ok := structs.CodeFromRPCCodedErr(err)
+	must.True(t, ok)
+	must.Eq(t, 404, code)
+
+	err = c1.ClientRPC("ArtifactCache.Purge", &structs.ArtifactCachePurgeRequest{}, &purgeResp)
+	must.NoError(t, err)
+	must.SliceEmpty(t, purgeResp.Purged)
+}
+
+func TestArtifactCache_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s, _, cleanupS := nomad.TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+		enableArtifactCache(t, c)
+	})
+	defer cleanup()
+
+	// The endpoints should fail without auth
+	listReq := &structs.NodeSpecificRequest{NodeID: c1.NodeID()}
+	var listResp structs.ArtifactCacheListResponse
+	err := c1.ClientRPC("ArtifactCache.List", listReq, &listResp)
+	must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+
+	purgeReq := &structs.ArtifactCachePurgeRequest{NodeID: c1.NodeID()}
+	var purgeResp structs.ArtifactCachePurgeResponse
+	err = c1.ClientRPC("ArtifactCache.Purge", purgeReq, &purgeResp)
+	must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+
+	// Listing requires node:read and purging node:write
+	tokenRead := mock.CreatePolicyAndToken(t, s.State(), 1009, "read", mock.NodePolicy(acl.PolicyRead))
+	listReq.AuthToken = tokenRead.SecretID
+	must.NoError(t, c1.ClientRPC("ArtifactCache.List", listReq, &listResp))
+
+	purgeReq.AuthToken = tokenRead.SecretID
+	err = c1.ClientRPC("ArtifactCache.Purge", purgeReq, &purgeResp)
+	must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+
+	tokenWrite := mock.CreatePolicyAndToken(t, s.State(), 1011, "write", mock.NodePolicy(acl.PolicyWrite))
+	purgeReq.AuthToken = tokenWrite.SecretID
+	must.NoError(t, c1.ClientRPC("ArtifactCache.Purge", purgeReq, &purgeResp))
+}
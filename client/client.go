@@ -17,6 +17,7 @@ import (
 	"time"
 
 	metrics "github.com/armon/go-metrics"
+	"github.com/dustin/go-humanize"
 	consulapi "github.com/hashicorp/consul/api"
 	hclog "github.com/hashicorp/go-hclog"
 	multierror "github.com/hashicorp/go-multierror"
@@ -60,6 +61,7 @@ import (
 	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/shirou/gopsutil/v3/host"
 	"golang.org/x/exp/maps"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -323,6 +325,14 @@ type Client struct {
 
 	// getter is an interface for retrieving artifacts.
 	getter cinterfaces.ArtifactGetter
+
+	// artifactCache is the cache of artifacts shared by the allocations, or
+	// nil if disabled.
+	artifactCache *getter.Cache
+
+	// migrateLimiter limits the bandwidth used by the migrations of the
+	// ephemeral disk of previous allocations from other nodes.
+	migrateLimiter *rate.Limiter
 }
 
 var (
@@ -383,7 +393,7 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		registeredCh:         make(chan struct{}),
 		registeredOnce:       sync.Once{},
 		cpusetManager:        cgutil.CreateCPUSetManager(cfg.CgroupParent, cfg.ReservableCores, logger),
-		getter:               getter.New(cfg.Artifact, logger),
+		migrateLimiter:       allocwatcher.NewMigrateLimiter(cfg.MigrateBandwidthLimit),
 		EnterpriseClient:     newEnterpriseClient(logger),
 		allocrunnerFactory:   cfg.AllocRunnerFactory,
 	}
@@ -521,6 +531,12 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 	c.garbageCollector = NewAllocGarbageCollector(c.logger, statsCollector, c, gcConfig)
 	go c.garbageCollector.Run()
 
+	// Evict allocations while the node runs low on resources
+	if cfg.Eviction != nil {
+		evictor := newNodeEvictor(c.logger, statsCollector, c, cfg.Eviction, c.shutdownCh)
+		c.shutdownGroup.Go(evictor.Run)
+	}
+
 	// Set the preconfigured list of static servers
 	if len(cfg.Servers) > 0 {
 		if _, err := c.setServersImpl(cfg.Servers, true); err != nil {
@@ -584,6 +600,9 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 	// Start collecting stats
 	c.shutdownGroup.Go(c.emitStats)
 
+	// Remove the expired exec session recordings
+	c.shutdownGroup.Go(c.pruneExecRecordings)
+
 	c.logger.Info("started client", "node_id", c.NodeID())
 	return c, nil
 }
@@ -668,6 +687,33 @@ func (c *Client) init() error {
 
 	c.logger.Info("using alloc directory", "alloc_dir", conf.AllocDir)
 
+	// Ensure the exec recording dir exists, defaulting to the state dir
+	if conf.ExecRecordingDir == "" {
+		conf = c.UpdateConfig(func(c *config.Config) {
+			c.ExecRecordingDir = filepath.Join(c.StateDir, "exec_recordings")
+		})
+	}
+	if err := os.MkdirAll(conf.ExecRecordingDir, 0700); err != nil {
+		return fmt.Errorf("failed creating exec recording dir: %s", err)
+	}
+
+	// Open the artifact cache if enabled, defaulting to the state dir
+	if conf.Artifact != nil && conf.Artifact.CacheSize > 0 {
+		if conf.Artifact.CacheDir == "" {
+			conf = c.UpdateConfig(func(c *config.Config) {
+				c.Artifact.CacheDir = filepath.Join(c.StateDir, "artifact_cache")
+			})
+		}
+		cache, err := getter.NewCache(conf.Artifact.CacheDir, conf.Artifact.CacheSize, c.logger)
+		if err != nil {
+			return err
+		}
+		c.artifactCache = cache
+		c.logger.Info("using artifact cache", "cache_dir", conf.Artifact.CacheDir,
+			"cache_size", humanize.IBytes(uint64(conf.Artifact.CacheSize)))
+	}
+	c.getter = getter.New(conf.Artifact, c.artifactCache, c.logger)
+
 	reserved := "<none>"
 	if conf.Node != nil && conf.Node.ReservedResources != nil {
 		// Node should always be non-nil due to initialization in the
@@ -2708,6 +2754,7 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 		RPC:              c,
 		Config:           c.GetConfig(),
 		MigrateToken:     migrateToken,
+		MigrateLimiter:   c.migrateLimiter,
 		Logger:           c.logger,
 	}
 	prevAllocWatcher, prevAllocMigrator := allocwatcher.NewAllocWatcher(watcherConfig)
@@ -3268,6 +3315,12 @@ func (c *Client) emitClientMetrics() {
 	metrics.SetGaugeWithLabels([]string{"client", "allocations", "pending"}, float32(pending), labels)
 	metrics.SetGaugeWithLabels([]string{"client", "allocations", "running"}, float32(running), labels)
 	metrics.SetGaugeWithLabels([]string{"client", "allocations", "terminal"}, float32(terminal), labels)
+
+	if c.artifactCache != nil {
+		size, entries := c.artifactCache.Stats()
+		metrics.SetGaugeWithLabels([]string{"client", "artifact_cache", "size"}, float32(size), labels)
+		metrics.SetGaugeWithLabels([]string{"client", "artifact_cache", "entries"}, float32(entries), labels)
+	}
 }
 
 // labels takes the base labels and appends the node state
@@ -149,6 +149,10 @@ func (ar *emptyAllocRunner) GetTaskDriverCapabilities(taskName string) (*drivers
 	return nil, nil
 }
 
+func (ar *emptyAllocRunner) NetworkIsolation() *drivers.NetworkIsolationSpec {
+	return nil
+}
+
 func (ar *emptyAllocRunner) StatsReporter() interfaces.AllocStatsReporter { return ar }
 func (ar *emptyAllocRunner) Listener() *cstructs.AllocListener            { return nil }
 func (ar *emptyAllocRunner) GetAllocDir() *allocdir.AllocDir              { return nil }
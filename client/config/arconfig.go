@@ -21,6 +21,7 @@ import (
 	"github.com/open-wander/wander/client/serviceregistration/checks/checkstore"
 	"github.com/open-wander/wander/client/serviceregistration/wrapper"
 	cstate "github.com/open-wander/wander/client/state"
+	cstructs "github.com/open-wander/wander/client/structs"
 	"github.com/open-wander/wander/client/vaultclient"
 	"github.com/open-wander/wander/nomad/structs"
 )
@@ -131,6 +132,7 @@ type PrevAllocMigrator interface {
 	// IsMigrating returns true if a concurrent caller is in Migrate
 	IsMigrating() bool
 
-	// Migrate data from previous alloc
-	Migrate(ctx context.Context, dest *allocdir.AllocDir) error
+	// Migrate data from previous alloc. The progress of migrations from
+	// remote nodes is reported to progress if it's not nil.
+	Migrate(ctx context.Context, dest *allocdir.AllocDir, progress func(*cstructs.MigrateProgress)) error
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/dustin/go-humanize"
 	"github.com/open-wander/wander/nomad/structs/config"
+	"golang.org/x/exp/maps"
 )
 
 // ArtifactConfig is the internal readonly copy of the client agent's
@@ -27,6 +28,11 @@ type ArtifactConfig struct {
 
 	DisableFilesystemIsolation bool
 	SetEnvironmentVariables    string
+
+	CacheSize int64
+	CacheDir  string
+
+	TrustedKeys map[string]string
 }
 
 // ArtifactConfigFromAgent creates a new internal readonly copy of the client
@@ -67,6 +73,11 @@ func ArtifactConfigFromAgent(c *config.ArtifactConfig) (*ArtifactConfig, error)
 		return nil, fmt.Errorf("error parsing DecompressionLimitSize: %w", err)
 	}
 
+	cacheSize, err := humanize.ParseBytes(*c.CacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing CacheSize: %w", err)
+	}
+
 	return &ArtifactConfig{
 		HTTPReadTimeout:             httpReadTimeout,
 		HTTPMaxBytes:                int64(httpMaxSize),
@@ -78,6 +89,9 @@ func ArtifactConfigFromAgent(c *config.ArtifactConfig) (*ArtifactConfig, error)
 		DecompressionLimitSize:      int64(decompressionSizeLimit),
 		DisableFilesystemIsolation:  *c.DisableFilesystemIsolation,
 		SetEnvironmentVariables:     *c.SetEnvironmentVariables,
+		CacheSize:                   int64(cacheSize),
+		CacheDir:                    *c.CacheDir,
+		TrustedKeys:                 maps.Clone(c.TrustedKeys),
 	}, nil
 }
 
@@ -87,5 +101,6 @@ func (a *ArtifactConfig) Copy() *ArtifactConfig {
 	}
 
 	newCopy := *a
+	newCopy.TrustedKeys = maps.Clone(a.TrustedKeys)
 	return &newCopy
 }
@@ -108,6 +108,15 @@ func TestArtifactConfigFromAgent(t *testing.T) {
 			},
 			expErr: "error parsing S3Timeout",
 		},
+		{
+			name: "invalid cache size",
+			config: func() *config.ArtifactConfig {
+				c := config.DefaultArtifactConfig()
+				c.CacheSize = pointer.Of("invalid")
+				return c
+			}(),
+			expErr: "error parsing CacheSize",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -98,6 +98,19 @@ type Config struct {
 	// AllocDir is where we store data for allocations
 	AllocDir string
 
+	// ExecRecordingDir is where the recordings of exec sessions are stored.
+	// Defaults to a directory of the StateDir.
+	ExecRecordingDir string
+
+	// ExecRecordingRetention is how long exec session recordings are kept
+	// before being removed.
+	ExecRecordingRetention time.Duration
+
+	// MigrateBandwidthLimit is the maximum rate in bytes per second at which
+	// the ephemeral disk of previous allocations is migrated from other
+	// nodes. Zero means no limit.
+	MigrateBandwidthLimit int64
+
 	// Logger provides a logger to the client
 	Logger log.InterceptLogger
 
@@ -323,6 +336,10 @@ type Config struct {
 	// Drain configuration from the agent's config file.
 	Drain *DrainConfig
 
+	// Eviction configuration from the agent's config file. Allocations are
+	// not evicted if nil.
+	Eviction *EvictionConfig
+
 	// ExtraAllocHooks are run with other allocation hooks, mainly for testing.
 	ExtraAllocHooks []interfaces.RunnerHook
 }
@@ -765,6 +782,7 @@ func DefaultConfig() *Config {
 		StatsCollectionInterval: 1 * time.Second,
 		TLSConfig:               &structsc.TLSConfig{},
 		GCInterval:              1 * time.Minute,
+		ExecRecordingRetention:  720 * time.Hour,
 		GCParallelDestroys:      2,
 		GCDiskUsageThreshold:    80,
 		GCInodeUsageThreshold:   70,
@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/open-wander/wander/nomad/structs/config"
+)
+
+// EvictionConfig describes when a Node running low on resources evicts its
+// allocations.
+type EvictionConfig struct {
+	// Interval is the duration between checks of the resource usage.
+	Interval time.Duration
+
+	// MemoryThreshold is the percentage of used memory above which the node
+	// is under memory pressure.
+	MemoryThreshold float64
+
+	// DiskThreshold is the percentage of used disk space in the alloc dir
+	// above which the node is under disk pressure.
+	DiskThreshold float64
+
+	// InodeThreshold is the percentage of used inodes in the alloc dir above
+	// which the node is under inode pressure.
+	InodeThreshold float64
+}
+
+// EvictionConfigFromAgent creates the internal read-only copy of the client
+// agent's EvictionConfig. Eviction is disabled if the agent has no eviction
+// block.
+func EvictionConfigFromAgent(c *config.EvictionConfig) (*EvictionConfig, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	newConfig := &EvictionConfig{
+		Interval:        10 * time.Second,
+		MemoryThreshold: 95,
+		DiskThreshold:   95,
+		InodeThreshold:  95,
+	}
+
+	if c.Interval != nil {
+		interval, err := time.ParseDuration(*c.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing interval: %w", err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("interval must be positive")
+		}
+		newConfig.Interval = interval
+	}
+
+	thresholds := []struct {
+		name  string
+		value *float64
+		dst   *float64
+	}{
+		{"memory_threshold", c.MemoryThreshold, &newConfig.MemoryThreshold},
+		{"disk_threshold", c.DiskThreshold, &newConfig.DiskThreshold},
+		{"inode_threshold", c.InodeThreshold, &newConfig.InodeThreshold},
+	}
+	for _, threshold := range thresholds {
+		if threshold.value == nil {
+			continue
+		}
+		if *threshold.value <= 0 || *threshold.value > 100 {
+			return nil, fmt.Errorf("%s must be a percentage between 0 and 100", threshold.name)
+		}
+		*threshold.dst = *threshold.value
+	}
+
+	return newConfig, nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/pointer"
+	"github.com/open-wander/wander/nomad/structs/config"
+	"github.com/shoenig/test/must"
+)
+
+func TestEvictionConfigFromAgent(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		config *config.EvictionConfig
+		exp    *EvictionConfig
+		expErr string
+	}{
+		{
+			name:   "disabled",
+			config: nil,
+			exp:    nil,
+		},
+		{
+			name:   "defaults",
+			config: &config.EvictionConfig{},
+			exp: &EvictionConfig{
+				Interval:        10 * time.Second,
+				MemoryThreshold: 95,
+				DiskThreshold:   95,
+				InodeThreshold:  95,
+			},
+		},
+		{
+			name: "from config",
+			config: &config.EvictionConfig{
+				Interval:        pointer.Of("1m"),
+				MemoryThreshold: pointer.Of(90.0),
+				DiskThreshold:   pointer.Of(85.5),
+				InodeThreshold:  pointer.Of(80.0),
+			},
+			exp: &EvictionConfig{
+				Interval:        time.Minute,
+				MemoryThreshold: 90,
+				DiskThreshold:   85.5,
+				InodeThreshold:  80,
+			},
+		},
+		{
+			name: "invalid interval",
+			config: &config.EvictionConfig{
+				Interval: pointer.Of("bad"),
+			},
+			expErr: "error parsing interval",
+		},
+		{
+			name: "negative interval",
+			config: &config.EvictionConfig{
+				Interval: pointer.Of("-1s"),
+			},
+			expErr: "interval must be positive",
+		},
+		{
+			name: "invalid threshold",
+			config: &config.EvictionConfig{
+				DiskThreshold: pointer.Of(120.0),
+			},
+			expErr: "disk_threshold must be a percentage",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := EvictionConfigFromAgent(tc.config)
+
+			if tc.expErr != "" {
+				must.Error(t, err)
+				must.StrContains(t, err.Error(), tc.expErr)
+			} else {
+				must.NoError(t, err)
+				must.Eq(t, tc.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+
+	"github.com/open-wander/wander/client/config"
+	"github.com/open-wander/wander/client/stats"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+// evictionCandidate is an allocation that may be evicted to relieve the
+// resource pressure of its node.
+type evictionCandidate struct {
+	allocID  string
+	priority int
+
+	// usage and request are the amounts of the resource under pressure used
+	// and requested by the allocation
+	usage   uint64
+	request uint64
+}
+
+// overRequest returns how much the allocation uses over its request of the
+// resource under pressure.
+func (c *evictionCandidate) overRequest() int64 {
+	return int64(c.usage) - int64(c.request)
+}
+
+// evictionHandler is used by the nodeEvictor to list and evict allocations
+// and to report the conditions of the node. It is generally fulfilled by the
+// Client.
+type evictionHandler interface {
+	// evictionCandidates returns the allocations that may be evicted along
+	// with their usage of the resource of the condition
+	evictionCandidates(condition string) []*evictionCandidate
+
+	// evictAllocs asks the servers to migrate the allocations away
+	evictAllocs(allocIDs []string, reason string) error
+
+	// setNodeConditions reports the conditions of the node to the servers
+	setNodeConditions(conditions []*structs.NodeCondition)
+}
+
+// nodeEvictor evicts allocations from a node running low on memory or disk
+// so that they are replaced on other nodes, rather than having the kernel
+// kill tasks arbitrarily.
+type nodeEvictor struct {
+	config *config.EvictionConfig
+
+	// statsCollector for node based thresholds (eg memory)
+	statsCollector stats.NodeStatsCollector
+
+	handler evictionHandler
+
+	// conditions are the pressure conditions of the node by type
+	conditions map[string]*structs.NodeCondition
+
+	// evicted are the allocations already evicted, which are not evicted
+	// again while they are migrated
+	evicted map[string]struct{}
+
+	// shutdownCh is closed when the evictor's run method should exit
+	shutdownCh <-chan struct{}
+
+	logger hclog.Logger
+}
+
+// newNodeEvictor returns an evictor of the allocations of a node. Must call
+// Run() in a goroutine to enable periodic evictions.
+func newNodeEvictor(logger hclog.Logger, statsCollector stats.NodeStatsCollector,
+	handler evictionHandler, config *config.EvictionConfig, shutdownCh <-chan struct{}) *nodeEvictor {
+	return &nodeEvictor{
+		config:         config,
+		statsCollector: statsCollector,
+		handler:        handler,
+		conditions:     make(map[string]*structs.NodeCondition),
+		evicted:        make(map[string]struct{}),
+		shutdownCh:     shutdownCh,
+		logger:         logger.Named("eviction"),
+	}
+}
+
+// Run the periodic evictions.
+func (e *nodeEvictor) Run() {
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-e.shutdownCh:
+			return
+		}
+
+		if err := e.evict(); err != nil {
+			e.logger.Error("error evicting allocations", "error", err)
+		}
+	}
+}
+
+// evict updates the conditions of the node and evicts one allocation if the
+// node is under pressure. Evicting a single allocation per interval gives the
+// usage time to settle before evicting more.
+func (e *nodeEvictor) evict() error {
+	if err := e.statsCollector.Collect(); err != nil {
+		return err
+	}
+
+	conditions := e.pressureConditions(e.statsCollector.Stats())
+	e.updateConditions(conditions)
+	if len(conditions) == 0 {
+		// Nothing is being migrated for pressure anymore
+		e.evicted = make(map[string]struct{})
+		return nil
+	}
+
+	// Relieve memory pressure first, as it is the one the kernel resolves by
+	// killing tasks
+	var condition *structs.NodeCondition
+	for _, c := range conditions {
+		if condition == nil || conditionOrder(c.Type) < conditionOrder(condition.Type) {
+			condition = c
+		}
+	}
+
+	candidates := e.handler.evictionCandidates(condition.Type)
+
+	// Forget about the evicted allocations that stopped
+	live := make(map[string]struct{}, len(candidates))
+	for _, c := range candidates {
+		live[c.allocID] = struct{}{}
+	}
+	for allocID := range e.evicted {
+		if _, ok := live[allocID]; !ok {
+			delete(e.evicted, allocID)
+		}
+	}
+
+	candidates = sortEvictionCandidates(candidates)
+	for _, c := range candidates {
+		if _, ok := e.evicted[c.allocID]; ok {
+			continue
+		}
+
+		e.logger.Warn("evicting allocation", "alloc_id", c.allocID, "reason", condition.Message)
+		if err := e.handler.evictAllocs([]string{c.allocID}, condition.Message); err != nil {
+			return fmt.Errorf("failed to evict allocation %s: %v", c.allocID, err)
+		}
+		e.evicted[c.allocID] = struct{}{}
+		return nil
+	}
+
+	e.logger.Warn("eviction skipped because no allocations can be evicted", "reason", condition.Message)
+	return nil
+}
+
+// pressureConditions returns the conditions of the node for the host stats.
+func (e *nodeEvictor) pressureConditions(hostStats *stats.HostStats) []*structs.NodeCondition {
+	if hostStats == nil {
+		return nil
+	}
+
+	var conditions []*structs.NodeCondition
+	addCondition := func(conditionType, resource string, used, threshold float64) {
+		if used <= threshold {
+			return
+		}
+		conditions = append(conditions, &structs.NodeCondition{
+			Type: conditionType,
+			Message: fmt.Sprintf("%s usage of %.0f%% is over the eviction threshold of %.0f%%",
+				resource, used, threshold),
+		})
+	}
+
+	if memory := hostStats.Memory; memory != nil && memory.Total > 0 {
+		used := float64(memory.Total-memory.Available) / float64(memory.Total) * 100
+		addCondition(structs.NodeConditionMemoryPressure, "memory", used, e.config.MemoryThreshold)
+	}
+	if disk := hostStats.AllocDirStats; disk != nil {
+		addCondition(structs.NodeConditionDiskPressure, "disk", disk.UsedPercent, e.config.DiskThreshold)
+		addCondition(structs.NodeConditionInodePressure, "inode", disk.InodesUsedPercent, e.config.InodeThreshold)
+	}
+	return conditions
+}
+
+// updateConditions reports the conditions of the node if their types
+// changed. Conditions which were already reported keep the time they were
+// first observed.
+func (e *nodeEvictor) updateConditions(conditions []*structs.NodeCondition) {
+	now := time.Now().UTC()
+	changed := len(conditions) != len(e.conditions)
+
+	current := make(map[string]*structs.NodeCondition, len(conditions))
+	for _, c := range conditions {
+		if existing, ok := e.conditions[c.Type]; ok {
+			c.Since = existing.Since
+		} else {
+			c.Since = now
+			changed = true
+			e.logger.Warn("node is under resource pressure", "condition", c.Type, "reason", c.Message)
+		}
+		current[c.Type] = c
+	}
+	for conditionType := range e.conditions {
+		if _, ok := current[conditionType]; !ok {
+			e.logger.Info("node is no longer under resource pressure", "condition", conditionType)
+		}
+	}
+	e.conditions = current
+
+	if changed {
+		e.handler.setNodeConditions(conditions)
+	}
+}
+
+// conditionOrder returns the order in which the pressure of the conditions
+// is relieved.
+func conditionOrder(conditionType string) int {
+	switch conditionType {
+	case structs.NodeConditionMemoryPressure:
+		return 0
+	case structs.NodeConditionDiskPressure:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// sortEvictionCandidates sorts the candidates in the order they are evicted:
+// allocations of lower priority jobs first, then the ones using the most
+// over their request.
+func sortEvictionCandidates(candidates []*evictionCandidate) []*evictionCandidate {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].priority != candidates[j].priority {
+			return candidates[i].priority < candidates[j].priority
+		}
+		return candidates[i].overRequest() > candidates[j].overRequest()
+	})
+	return candidates
+}
+
+// evictionCandidates returns the running allocations of the client that may
+// be evicted, along with their usage of the resource of the condition.
+// System jobs are never evicted as they cannot run elsewhere.
+func (c *Client) evictionCandidates(condition string) []*evictionCandidate {
+	var candidates []*evictionCandidate
+	for allocID, ar := range c.getAllocRunners() {
+		alloc := ar.Alloc()
+		if alloc.Job == nil || alloc.TerminalStatus() || alloc.ClientTerminalStatus() ||
+			alloc.DesiredTransition.ShouldMigrate() {
+			continue
+		}
+		switch alloc.Job.Type {
+		case structs.JobTypeSystem, structs.JobTypeSysBatch:
+			continue
+		}
+
+		candidate := &evictionCandidate{
+			allocID:  allocID,
+			priority: alloc.Job.Priority,
+		}
+
+		switch condition {
+		case structs.NodeConditionMemoryPressure:
+			if alloc.AllocatedResources != nil {
+				candidate.request = uint64(alloc.AllocatedResources.Comparable().Flattened.Memory.MemoryMB) * MB
+			}
+			usage, err := ar.StatsReporter().LatestAllocStats("")
+			if err == nil && usage != nil && usage.ResourceUsage != nil && usage.ResourceUsage.MemoryStats != nil {
+				candidate.usage = usage.ResourceUsage.MemoryStats.RSS
+				if candidate.usage == 0 {
+					candidate.usage = usage.ResourceUsage.MemoryStats.Usage
+				}
+			}
+		default:
+			if alloc.AllocatedResources != nil && condition == structs.NodeConditionDiskPressure {
+				candidate.request = uint64(alloc.AllocatedResources.Shared.DiskMB) * MB
+			}
+			if allocDir := ar.GetAllocDir(); allocDir != nil {
+				bytes, inodes, err := allocDir.DiskUsage()
+				if err != nil {
+					c.logger.Debug("failed to compute disk usage of allocation", "alloc_id", allocID, "error", err)
+				} else if condition == structs.NodeConditionDiskPressure {
+					candidate.usage = bytes
+				} else {
+					candidate.usage = inodes
+				}
+			}
+		}
+
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// evictAllocs asks the servers to migrate the allocations to other nodes and
+// records the evictions as node events.
+func (c *Client) evictAllocs(allocIDs []string, reason string) error {
+	req := structs.NodeEvictAllocsRequest{
+		NodeID:   c.NodeID(),
+		AllocIDs: allocIDs,
+		Reason:   reason,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.Region(),
+			AuthToken: c.secretNodeID(),
+		},
+	}
+	var resp structs.GenericResponse
+	if err := c.RPC("Node.EvictAllocs", &req, &resp); err != nil {
+		return err
+	}
+
+	for _, allocID := range allocIDs {
+		event := structs.NewNodeEvent().
+			SetSubsystem(structs.NodeEventSubsystemEviction).
+			SetMessage("Allocation evicted").
+			AddDetail("alloc_id", allocID).
+			AddDetail("reason", reason)
+		c.triggerNodeEvent(event)
+	}
+	return nil
+}
+
+// setNodeConditions updates the conditions of the node and sends them to
+// the servers.
+func (c *Client) setNodeConditions(conditions []*structs.NodeCondition) {
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	newConfig := c.config.Copy()
+	newConfig.Node.Conditions = conditions
+	c.config = newConfig
+	c.updateNode()
+}
@@ -0,0 +1,195 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/client/config"
+	"github.com/open-wander/wander/client/stats"
+	"github.com/open-wander/wander/helper/testlog"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// mockEvictionHandler implements the evictionHandler interface.
+type mockEvictionHandler struct {
+	candidates map[string][]*evictionCandidate
+	evicted    []string
+	reasons    []string
+	conditions [][]*structs.NodeCondition
+}
+
+func (m *mockEvictionHandler) evictionCandidates(condition string) []*evictionCandidate {
+	return m.candidates[condition]
+}
+
+func (m *mockEvictionHandler) evictAllocs(allocIDs []string, reason string) error {
+	m.evicted = append(m.evicted, allocIDs...)
+	m.reasons = append(m.reasons, reason)
+	return nil
+}
+
+func (m *mockEvictionHandler) setNodeConditions(conditions []*structs.NodeCondition) {
+	m.conditions = append(m.conditions, conditions)
+}
+
+// mockHostStatsCollector implements the NodeStatsCollector interface.
+type mockHostStatsCollector struct {
+	stats *stats.HostStats
+}
+
+func (m *mockHostStatsCollector) Collect() error {
+	return nil
+}
+
+func (m *mockHostStatsCollector) Stats() *stats.HostStats {
+	return m.stats
+}
+
+func evictionConfig() *config.EvictionConfig {
+	return &config.EvictionConfig{
+		Interval:        time.Second,
+		MemoryThreshold: 90,
+		DiskThreshold:   90,
+		InodeThreshold:  90,
+	}
+}
+
+func hostStats(memoryUsed, diskUsed, inodesUsed float64) *stats.HostStats {
+	return &stats.HostStats{
+		Memory: &stats.MemoryStats{
+			Total:     100 * MB,
+			Available: uint64(100-memoryUsed) * MB,
+		},
+		AllocDirStats: &stats.DiskStats{
+			UsedPercent:       diskUsed,
+			InodesUsedPercent: inodesUsed,
+		},
+	}
+}
+
+func TestNodeEvictor_PressureConditions(t *testing.T) {
+	ci.Parallel(t)
+
+	evictor := newNodeEvictor(testlog.HCLogger(t), &mockHostStatsCollector{},
+		&mockEvictionHandler{}, evictionConfig(), nil)
+
+	testCases := []struct {
+		name  string
+		stats *stats.HostStats
+		exp   []string
+	}{
+		{
+			name:  "no stats",
+			stats: nil,
+			exp:   nil,
+		},
+		{
+			name:  "no pressure",
+			stats: hostStats(50, 90, 10),
+			exp:   nil,
+		},
+		{
+			name:  "memory",
+			stats: hostStats(95, 10, 10),
+			exp:   []string{structs.NodeConditionMemoryPressure},
+		},
+		{
+			name:  "disk and inodes",
+			stats: hostStats(10, 91, 99),
+			exp:   []string{structs.NodeConditionDiskPressure, structs.NodeConditionInodePressure},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var types []string
+			for _, c := range evictor.pressureConditions(tc.stats) {
+				types = append(types, c.Type)
+				must.StrContains(t, c.Message, "over the eviction threshold of 90%")
+			}
+			must.Eq(t, tc.exp, types)
+		})
+	}
+}
+
+func TestNodeEvictor_SortCandidates(t *testing.T) {
+	ci.Parallel(t)
+
+	candidates := []*evictionCandidate{
+		{allocID: "high", priority: 80, usage: 900, request: 100},
+		{allocID: "low-within", priority: 20, usage: 50, request: 100},
+		{allocID: "low-over", priority: 20, usage: 300, request: 100},
+		{allocID: "mid", priority: 50, usage: 100, request: 100},
+	}
+
+	var order []string
+	for _, c := range sortEvictionCandidates(candidates) {
+		order = append(order, c.allocID)
+	}
+	must.Eq(t, []string{"low-over", "low-within", "mid", "high"}, order)
+}
+
+func TestNodeEvictor_Evict(t *testing.T) {
+	ci.Parallel(t)
+
+	collector := &mockHostStatsCollector{stats: hostStats(10, 10, 10)}
+	handler := &mockEvictionHandler{
+		candidates: map[string][]*evictionCandidate{
+			structs.NodeConditionMemoryPressure: {
+				{allocID: "a", priority: 50, usage: 200 * MB, request: 100 * MB},
+				{allocID: "b", priority: 50, usage: 400 * MB, request: 100 * MB},
+			},
+			structs.NodeConditionDiskPressure: {
+				{allocID: "a", priority: 50, usage: 900 * MB, request: 100 * MB},
+				{allocID: "b", priority: 50, usage: 100 * MB, request: 100 * MB},
+			},
+		},
+	}
+	evictor := newNodeEvictor(testlog.HCLogger(t), collector, handler, evictionConfig(), nil)
+
+	// Nothing is evicted nor reported without pressure
+	must.NoError(t, evictor.evict())
+	must.SliceEmpty(t, handler.evicted)
+	must.SliceEmpty(t, handler.conditions)
+
+	// Memory pressure is relieved before disk pressure, evicting the
+	// allocation using the most memory over its request
+	collector.stats = hostStats(95, 95, 10)
+	must.NoError(t, evictor.evict())
+	must.Eq(t, []string{"b"}, handler.evicted)
+	must.StrContains(t, handler.reasons[0], "memory usage of 95%")
+	must.Len(t, 1, handler.conditions)
+	must.Len(t, 2, handler.conditions[0])
+	since := handler.conditions[0][0].Since
+	must.False(t, since.IsZero())
+
+	// Evicted allocations are not evicted again, and unchanged conditions
+	// are not reported again
+	must.NoError(t, evictor.evict())
+	must.Eq(t, []string{"b", "a"}, handler.evicted)
+	must.Len(t, 1, handler.conditions)
+
+	// Nothing left to evict
+	must.NoError(t, evictor.evict())
+	must.Len(t, 2, handler.evicted)
+
+	// Conditions going away are reported
+	collector.stats = hostStats(10, 95, 10)
+	must.NoError(t, evictor.evict())
+	must.Len(t, 2, handler.conditions)
+	must.Len(t, 1, handler.conditions[1])
+	must.Eq(t, structs.NodeConditionDiskPressure, handler.conditions[1][0].Type)
+	must.Eq(t, since, handler.conditions[1][0].Since)
+
+	// Once the pressure is relieved the evicted allocations are forgotten
+	collector.stats = hostStats(10, 10, 10)
+	must.NoError(t, evictor.evict())
+	must.Len(t, 3, handler.conditions)
+	must.SliceEmpty(t, handler.conditions[2])
+	must.MapEmpty(t, evictor.evicted)
+}
@@ -0,0 +1,379 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-wander/wander/helper"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/open-wander/wander/plugins/drivers"
+)
+
+const (
+	// execRecordingExt is the extension of the exec session recordings,
+	// which are asciicast v2 files.
+	execRecordingExt = ".cast"
+
+	// execRecordingPruneInterval is how often recordings older than the
+	// retention period are removed.
+	execRecordingPruneInterval = 1 * time.Hour
+
+	// Asciicast event codes of the output, input and terminal resizes of
+	// exec sessions.
+	execRecordingEventOutput = "o"
+	execRecordingEventInput  = "i"
+	execRecordingEventResize = "r"
+
+	// execRecordingDefaultWidth and execRecordingDefaultHeight are the
+	// terminal size recorded in the header, as the actual size is only
+	// known once the session sends it.
+	execRecordingDefaultWidth  = 80
+	execRecordingDefaultHeight = 24
+)
+
+// execRecordingHeader is the asciicast v2 header of an exec session
+// recording. The Nomad field holds the session details for auditing.
+type execRecordingHeader struct {
+	Version   int                `json:"version"`
+	Width     int                `json:"width"`
+	Height    int                `json:"height"`
+	Timestamp int64              `json:"timestamp"`
+	Command   string             `json:"command"`
+	Title     string             `json:"title"`
+	Nomad     *execRecordingMeta `json:"nomad"`
+}
+
+// execRecordingMeta identifies the exec session of a recording.
+type execRecordingMeta struct {
+	SessionID  string `json:"session_id"`
+	Namespace  string `json:"namespace"`
+	JobID      string `json:"job_id"`
+	AllocID    string `json:"alloc_id"`
+	Task       string `json:"task"`
+	NodeID     string `json:"node_id"`
+	AccessorID string `json:"accessor_id,omitempty"`
+	TokenName  string `json:"token_name,omitempty"`
+	Tty        bool   `json:"tty"`
+}
+
+// execRecordingPath returns the path of the recording of the exec session
+// with the given ID.
+func (c *Client) execRecordingPath(id string) string {
+	return filepath.Join(c.GetConfig().ExecRecordingDir, id+execRecordingExt)
+}
+
+// startExecSession registers an exec session with the servers. If recording
+// is enabled for the namespace of the allocation, it returns the session and
+// a recorder wrapping its stream, otherwise it returns nil. The session must
+// be refused if an error is returned.
+//
+// Servers which don't support exec sessions can't require them to be
+// recorded, so only these sessions are allowed without being registered.
+func (c *Client) startExecSession(id string, alloc *structs.Allocation, task string, cmd []string, tty bool,
+	ident *structs.AuthenticatedIdentity, stream drivers.ExecTaskStream) (*structs.ExecSession, *execRecorder, error) {
+
+	session := &structs.ExecSession{
+		ID:        id,
+		Namespace: alloc.Namespace,
+		JobID:     alloc.JobID,
+		AllocID:   alloc.ID,
+		TaskName:  task,
+		NodeID:    c.NodeID(),
+		Command:   cmd,
+		Tty:       tty,
+		StartedAt: time.Now().UTC(),
+	}
+	if ident != nil && ident.ACLToken != nil {
+		session.AccessorID = ident.ACLToken.AccessorID
+		session.TokenName = ident.ACLToken.Name
+	}
+
+	req := &structs.ExecSessionStartRequest{
+		Session: session,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.Region(),
+			AuthToken: c.secretNodeID(),
+		},
+	}
+	var resp structs.ExecSessionStartResponse
+	if err := c.RPC("ExecSession.Start", req, &resp); err != nil {
+		if isUnknownRPCMethod(err) {
+			c.logger.Debug("servers don't support exec session recording", "exec_id", id)
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to register exec session: %w", err)
+	}
+	if !resp.Record {
+		return nil, nil, nil
+	}
+
+	recorder, err := newExecRecorder(c.execRecordingPath(id), session, stream)
+	if err != nil {
+		// Don't leave the session running on the servers.
+		c.endExecSession(session, nil, err)
+		return nil, nil, fmt.Errorf("failed to record exec session: %w", err)
+	}
+	return session, recorder, nil
+}
+
+// isUnknownRPCMethod returns whether the error is due to the servers not
+// knowing the RPC method, such as during upgrades.
+func isUnknownRPCMethod(err error) bool {
+	return structs.IsErrUnknownMethod(err) ||
+		strings.Contains(err.Error(), "rpc: can't find service") ||
+		strings.Contains(err.Error(), "rpc: can't find method")
+}
+
+// endExecSession closes the recording of an exec session and updates the
+// session on the servers with its outcome.
+func (c *Client) endExecSession(session *structs.ExecSession, recorder *execRecorder, sessionErr error) {
+	ended := session.Copy()
+	if recorder != nil {
+		if err := recorder.Close(); err != nil && sessionErr == nil {
+			sessionErr = err
+		}
+		ended.ExitCode = recorder.ExitCode()
+		ended.RecordingSize = recorder.Size()
+	}
+	ended.EndedAt = time.Now().UTC()
+	if sessionErr != nil {
+		ended.Error = sessionErr.Error()
+	}
+
+	req := &structs.ExecSessionEndRequest{
+		Session: ended,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.Region(),
+			AuthToken: c.secretNodeID(),
+		},
+	}
+	if err := c.RPC("ExecSession.End", req, &structs.GenericResponse{}); err != nil {
+		c.logger.Error("failed to update ended exec session", "exec_id", session.ID, "error", err)
+	}
+}
+
+// pruneExecRecordings periodically removes the exec session recordings older
+// than the retention period.
+func (c *Client) pruneExecRecordings() {
+	timer, stop := helper.NewSafeTimer(0)
+	defer stop()
+
+	for {
+		select {
+		case <-timer.C:
+			conf := c.GetConfig()
+			if err := pruneExecRecordings(conf.ExecRecordingDir, time.Now().Add(-conf.ExecRecordingRetention)); err != nil {
+				c.logger.Error("failed to prune exec session recordings", "error", err)
+			}
+			timer.Reset(execRecordingPruneInterval)
+		case <-c.shutdownCh:
+			return
+		}
+	}
+}
+
+// pruneExecRecordings removes the recordings of the directory last modified
+// before the cutoff.
+func pruneExecRecordings(dir string, cutoff time.Time) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var mErr []error
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != execRecordingExt {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().Before(cutoff) {
+			if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+				mErr = append(mErr, err)
+			}
+		}
+	}
+	return errors.Join(mErr...)
+}
+
+// readExecRecordingHeader returns the header of the recording file.
+func readExecRecordingHeader(f *os.File) (*execRecordingHeader, error) {
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording header: %w", err)
+	}
+
+	var header execRecordingHeader
+	if err := json.Unmarshal(line, &header); err != nil {
+		return nil, fmt.Errorf("failed to decode recording header: %w", err)
+	}
+	if header.Nomad == nil {
+		return nil, errors.New("recording header is missing the session details")
+	}
+	return &header, nil
+}
+
+// execRecorder is an exec stream recording the input and output of an exec
+// session as an asciicast v2 file. A session is ended if its recording
+// fails, so that no unrecorded input reaches the task.
+type execRecorder struct {
+	stream drivers.ExecTaskStream
+	start  time.Time
+
+	mu       sync.Mutex
+	f        *os.File
+	size     int64
+	exitCode int
+}
+
+// newExecRecorder creates the recording file of the session and returns a
+// recorder wrapping the stream of the session.
+func newExecRecorder(path string, session *structs.ExecSession, stream drivers.ExecTaskStream) (*execRecorder, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &execRecorder{
+		stream: stream,
+		start:  time.Now(),
+		f:      f,
+	}
+
+	header, err := json.Marshal(&execRecordingHeader{
+		Version:   2,
+		Width:     execRecordingDefaultWidth,
+		Height:    execRecordingDefaultHeight,
+		Timestamp: r.start.Unix(),
+		Command:   strings.Join(session.Command, " "),
+		Title:     fmt.Sprintf("%s/%s", session.AllocID, session.TaskName),
+		Nomad: &execRecordingMeta{
+			SessionID:  session.ID,
+			Namespace:  session.Namespace,
+			JobID:      session.JobID,
+			AllocID:    session.AllocID,
+			Task:       session.TaskName,
+			NodeID:     session.NodeID,
+			AccessorID: session.AccessorID,
+			TokenName:  session.TokenName,
+			Tty:        session.Tty,
+		},
+	})
+	if err == nil {
+		err = r.write(header)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return nil, err
+	}
+	return r, nil
+}
+
+// Send records the output of the task before sending it to the user.
+func (r *execRecorder) Send(m *drivers.ExecTaskStreamingResponseMsg) error {
+	if m.Stdout != nil && len(m.Stdout.Data) > 0 {
+		if err := r.event(execRecordingEventOutput, string(m.Stdout.Data)); err != nil {
+			return err
+		}
+	}
+	if m.Stderr != nil && len(m.Stderr.Data) > 0 {
+		if err := r.event(execRecordingEventOutput, string(m.Stderr.Data)); err != nil {
+			return err
+		}
+	}
+	if m.Exited && m.Result != nil {
+		r.mu.Lock()
+		r.exitCode = int(m.Result.ExitCode)
+		r.mu.Unlock()
+	}
+	return r.stream.Send(m)
+}
+
+// Recv records the input and terminal resizes of the user before passing
+// them to the task.
+func (r *execRecorder) Recv() (*drivers.ExecTaskStreamingRequestMsg, error) {
+	m, err := r.stream.Recv()
+	if err != nil {
+		return m, err
+	}
+	if m.Stdin != nil && len(m.Stdin.Data) > 0 {
+		if err := r.event(execRecordingEventInput, string(m.Stdin.Data)); err != nil {
+			return nil, err
+		}
+	}
+	if m.TtySize != nil {
+		size := fmt.Sprintf("%dx%d", m.TtySize.Width, m.TtySize.Height)
+		if err := r.event(execRecordingEventResize, size); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// event records an asciicast event with the time elapsed since the start of
+// the session.
+func (r *execRecorder) event(code, data string) error {
+	elapsed := float64(time.Since(r.start).Microseconds()) / 1e6
+	line, err := json.Marshal([]interface{}{elapsed, code, data})
+	if err != nil {
+		return err
+	}
+	return r.write(line)
+}
+
+func (r *execRecorder) write(line []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		return errors.New("exec session recording is closed")
+	}
+	n, err := r.f.Write(append(line, '\n'))
+	r.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to record exec session: %w", err)
+	}
+	return nil
+}
+
+// Close closes the recording file.
+func (r *execRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+// Size returns the size in bytes of the recording.
+func (r *execRecorder) Size() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.size
+}
+
+// ExitCode returns the exit code of the recorded command, once it exited.
+func (r *execRecorder) ExitCode() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.exitCode
+}
+
+var _ drivers.ExecTaskStream = (*execRecorder)(nil)
@@ -0,0 +1,227 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/client/config"
+	"github.com/open-wander/wander/helper/uuid"
+	"github.com/open-wander/wander/nomad"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/open-wander/wander/plugins/drivers"
+	dproto "github.com/open-wander/wander/plugins/drivers/proto"
+	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
+)
+
+// testExecStream is an exec stream replaying requests and collecting the
+// responses.
+type testExecStream struct {
+	requests  []*drivers.ExecTaskStreamingRequestMsg
+	responses []*drivers.ExecTaskStreamingResponseMsg
+}
+
+func (s *testExecStream) Send(m *drivers.ExecTaskStreamingResponseMsg) error {
+	s.responses = append(s.responses, m)
+	return nil
+}
+
+func (s *testExecStream) Recv() (*drivers.ExecTaskStreamingRequestMsg, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	m := s.requests[0]
+	s.requests = s.requests[1:]
+	return m, nil
+}
+
+func TestExecRecorder(t *testing.T) {
+	ci.Parallel(t)
+
+	session := mock.ExecSession()
+	path := filepath.Join(t.TempDir(), session.ID+execRecordingExt)
+
+	stream := &testExecStream{
+		requests: []*drivers.ExecTaskStreamingRequestMsg{
+			{TtySize: &dproto.ExecTaskStreamingRequest_TerminalSize{Height: 40, Width: 120}},
+			{Stdin: &dproto.ExecTaskStreamingIOOperation{Data: []byte("ls\n")}},
+		},
+	}
+	recorder, err := newExecRecorder(path, session, stream)
+	must.NoError(t, err)
+
+	// Recordings are never overwritten
+	_, err = newExecRecorder(path, session, stream)
+	must.ErrorIs(t, err, os.ErrExist)
+
+	for i := 0; i < 2; i++ {
+		_, err := recorder.Recv()
+		must.NoError(t, err)
+	}
+	_, err = recorder.Recv()
+	must.ErrorIs(t, err, io.EOF)
+
+	must.NoError(t, recorder.Send(&drivers.ExecTaskStreamingResponseMsg{
+		Stdout: &dproto.ExecTaskStreamingIOOperation{Data: []byte("file\n")},
+	}))
+	must.NoError(t, recorder.Send(&drivers.ExecTaskStreamingResponseMsg{
+		Stderr: &dproto.ExecTaskStreamingIOOperation{Data: []byte("oops\n")},
+	}))
+	must.NoError(t, recorder.Send(&drivers.ExecTaskStreamingResponseMsg{
+		Exited: true,
+		Result: &dproto.ExitResult{ExitCode: 2},
+	}))
+	must.Len(t, 3, stream.responses)
+	must.NoError(t, recorder.Close())
+	must.Eq(t, 2, recorder.ExitCode())
+
+	// Writes fail once the recording is closed
+	err = recorder.Send(&drivers.ExecTaskStreamingResponseMsg{
+		Stdout: &dproto.ExecTaskStreamingIOOperation{Data: []byte("late\n")},
+	})
+	must.ErrorContains(t, err, "closed")
+
+	info, err := os.Stat(path)
+	must.NoError(t, err)
+	must.Eq(t, info.Size(), recorder.Size())
+
+	f, err := os.Open(path)
+	must.NoError(t, err)
+	defer f.Close()
+
+	header, err := readExecRecordingHeader(f)
+	must.NoError(t, err)
+	must.Eq(t, 2, header.Version)
+	must.Eq(t, session.ID, header.Nomad.SessionID)
+	must.Eq(t, session.AllocID, header.Nomad.AllocID)
+	must.Eq(t, session.AccessorID, header.Nomad.AccessorID)
+
+	_, err = f.Seek(0, io.SeekStart)
+	must.NoError(t, err)
+	scanner := bufio.NewScanner(f)
+	scanner.Scan()
+
+	var events [][]interface{}
+	for scanner.Scan() {
+		var event []interface{}
+		must.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	must.Len(t, 4, events)
+	must.Eq(t, []interface{}{"r", "120x40"}, events[0][1:])
+	must.Eq(t, []interface{}{"i", "ls\n"}, events[1][1:])
+	must.Eq(t, []interface{}{"o", "file\n"}, events[2][1:])
+	must.Eq(t, []interface{}{"o", "oops\n"}, events[3][1:])
+}
+
+func TestPruneExecRecordings(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	old := filepath.Join(dir, "old"+execRecordingExt)
+	recent := filepath.Join(dir, "recent"+execRecordingExt)
+	other := filepath.Join(dir, "other.txt")
+	for _, path := range []string{old, recent, other} {
+		must.NoError(t, os.WriteFile(path, []byte("{}\n"), 0600))
+	}
+	past := time.Now().Add(-48 * time.Hour)
+	must.NoError(t, os.Chtimes(old, past, past))
+	must.NoError(t, os.Chtimes(other, past, past))
+
+	must.NoError(t, pruneExecRecordings(dir, time.Now().Add(-24*time.Hour)))
+
+	must.FileNotExists(t, old)
+	must.FileExists(t, recent)
+	must.FileExists(t, other)
+}
+
+// execSessionRPCHandler forwards RPCs to a server, except the exec session
+// ones which are answered with the configured recording requirement or error.
+type execSessionRPCHandler struct {
+	srv *nomad.Server
+
+	lock   sync.Mutex
+	record bool
+	err    error
+}
+
+func (h *execSessionRPCHandler) set(record bool, err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.record, h.err = record, err
+}
+
+func (h *execSessionRPCHandler) RPC(method string, args any, reply any) error {
+	switch method {
+	case "ExecSession.Start":
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		if h.err != nil {
+			return h.err
+		}
+		reply.(*structs.ExecSessionStartResponse).Record = h.record
+		return nil
+	case "ExecSession.End":
+		return nil
+	default:
+		return h.srv.RPC(method, args, reply)
+	}
+}
+
+func TestClient_StartExecSession(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := testServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	handler := &execSessionRPCHandler{srv: s1}
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.RPCHandler = handler
+		c.ExecRecordingDir = t.TempDir()
+	})
+	defer cleanup()
+
+	alloc := mock.Alloc()
+	start := func() (*structs.ExecSession, *execRecorder, error) {
+		return c1.startExecSession(uuid.Generate(), alloc, "web", []string{"/bin/sh"}, true, nil, &testExecStream{})
+	}
+
+	// Servers which don't know about exec sessions can't require recording
+	handler.set(false, errors.New("rpc: can't find service ExecSession.Start"))
+	session, recorder, err := start()
+	must.NoError(t, err)
+	must.Nil(t, session)
+	must.Nil(t, recorder)
+
+	// Sessions are refused when the servers can't be reached, as the
+	// namespace may require recording
+	handler.set(false, errors.New("no servers"))
+	_, _, err = start()
+	must.ErrorContains(t, err, "failed to register exec session")
+
+	handler.set(true, nil)
+	session, recorder, err = start()
+	must.NoError(t, err)
+	must.NotNil(t, session)
+	must.NotNil(t, recorder)
+	c1.endExecSession(session, recorder, nil)
+
+	handler.set(false, nil)
+	session, recorder, err = start()
+	must.NoError(t, err)
+	must.Nil(t, session)
+	must.Nil(t, recorder)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/open-wander/wander/acl"
+	cstructs "github.com/open-wander/wander/client/structs"
+	"github.com/open-wander/wander/helper"
+	"github.com/open-wander/wander/helper/pointer"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+// ExecSession endpoint is used for accessing the recordings of the exec
+// sessions of the client.
+type ExecSession struct {
+	c *Client
+}
+
+// NewExecSessionEndpoint returns a new ExecSession endpoint.
+func NewExecSessionEndpoint(c *Client) *ExecSession {
+	e := &ExecSession{c}
+	e.c.streamingRpcs.Register("ExecSession.Recording", e.recording)
+	return e
+}
+
+// recording is used to stream the recording of an exec session.
+func (e *ExecSession) recording(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "exec_session", "recording"}, time.Now())
+	defer conn.Close()
+
+	// Decode the arguments
+	var req cstructs.ExecSessionRecordingRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&req); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(http.StatusInternalServerError)), encoder)
+		return
+	}
+
+	if !helper.IsUUID(req.SessionID) {
+		handleStreamResultError(errors.New("invalid exec session ID"), pointer.Of(int64(http.StatusBadRequest)), encoder)
+		return
+	}
+
+	f, err := os.Open(e.c.execRecordingPath(req.SessionID))
+	if err != nil {
+		code := pointer.Of(int64(http.StatusInternalServerError))
+		if os.IsNotExist(err) {
+			code = pointer.Of(int64(http.StatusNotFound))
+			err = errors.New("exec session recording not found")
+		}
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+	defer f.Close()
+
+	// The namespace of the session is only known from the recording
+	header, err := readExecRecordingHeader(f)
+	if err != nil {
+		handleStreamResultError(err, pointer.Of(int64(http.StatusInternalServerError)), encoder)
+		return
+	}
+	if aclObj, err := e.c.ResolveToken(req.AuthToken); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(http.StatusForbidden)), encoder)
+		return
+	} else if aclObj != nil && !aclObj.AllowNsOp(header.Nomad.Namespace, acl.NamespaceCapabilityReadExecRecording) {
+		handleStreamResultError(structs.ErrPermissionDenied, pointer.Of(int64(http.StatusForbidden)), encoder)
+		return
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(http.StatusInternalServerError)), encoder)
+		return
+	}
+
+	w := bufio.NewWriterSize(&frameWriter{encoder: encoder}, streamFrameSize)
+	if _, err := io.Copy(w, f); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(http.StatusInternalServerError)), encoder)
+		return
+	}
+	if err := w.Flush(); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(http.StatusInternalServerError)), encoder)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/client/config"
+	cstructs "github.com/open-wander/wander/client/structs"
+	"github.com/open-wander/wander/helper/uuid"
+	"github.com/open-wander/wander/nomad"
+	"github.com/open-wander/wander/nomad/mock"
+	nstructs "github.com/open-wander/wander/nomad/structs"
+	"github.com/open-wander/wander/plugins/drivers"
+	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+func TestExecSession_RecordAndStream(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server and client
+	s, cleanupS := nomad.TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	// Enable exec recording for the namespace of the job
+	ns := mock.Namespace()
+	ns.ExecRecording = &nstructs.NamespaceExecRecordingConfiguration{Enabled: true}
+	nsReq := &nstructs.NamespaceUpsertRequest{
+		Namespaces:   []*nstructs.Namespace{ns},
+		WriteRequest: nstructs.WriteRequest{Region: "global"},
+	}
+	must.NoError(t, s.RPC("Namespace.UpsertNamespaces", nsReq, &nstructs.GenericResponse{}))
+
+	expectedStdout := "Hello from the other side\n"
+	job := mock.BatchJob()
+	job.Namespace = ns.Name
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+		"exec_command": map[string]interface{}{
+			"run_for":       "1ms",
+			"stdout_string": expectedStdout,
+			"exit_code":     3,
+		},
+	}
+	testutil.WaitForRunning(t, s.RPC, job)
+
+	args := nstructs.AllocListRequest{}
+	args.Region = "global"
+	args.Namespace = ns.Name
+	resp := nstructs.AllocListResponse{}
+	must.NoError(t, s.RPC("Alloc.List", &args, &resp))
+	must.Len(t, 1, resp.Allocations)
+	allocID := resp.Allocations[0].ID
+
+	req := &cstructs.AllocExecRequest{
+		AllocID:      allocID,
+		Task:         job.TaskGroups[0].Tasks[0].Name,
+		Tty:          true,
+		Cmd:          []string{"placeholder command"},
+		QueryOptions: nstructs.QueryOptions{Region: "global"},
+	}
+
+	handler, err := c.StreamingRpcHandler("Allocations.Exec")
+	must.NoError(t, err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	errCh := make(chan error)
+	frames := make(chan *drivers.ExecTaskStreamingResponseMsg)
+	go handler(p2)
+	go decodeFrames(t, p1, frames, errCh)
+
+	encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+	must.NoError(t, encoder.Encode(req))
+
+	timeout := time.After(3 * time.Second)
+OUTER:
+	for {
+		select {
+		case <-timeout:
+			t.Fatal("timed out waiting for the exec session to exit")
+		case err := <-errCh:
+			must.NoError(t, err)
+		case f := <-frames:
+			if f.Exited {
+				break OUTER
+			}
+		}
+	}
+
+	// The session is ended on the servers once the exec returns
+	var session *nstructs.ExecSession
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool {
+			iter, err := s.State().ExecSessionsByNamespace(nil, ns.Name)
+			must.NoError(t, err)
+			raw := iter.Next()
+			if raw == nil {
+				return false
+			}
+			session = raw.(*nstructs.ExecSession)
+			return session.Ended()
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(50*time.Millisecond),
+	))
+	must.Eq(t, allocID, session.AllocID)
+	must.Eq(t, c.NodeID(), session.NodeID)
+	must.Eq(t, 3, session.ExitCode)
+	must.Positive(t, session.RecordingSize)
+
+	// Stream the recording from the client
+	recording, err := streamExecRecording(t, c, session.ID)
+	must.NoError(t, err)
+	must.Eq(t, session.RecordingSize, int64(len(recording)))
+	must.StrContains(t, recording, `"session_id":"`+session.ID+`"`)
+	must.StrContains(t, recording, `"o","Hello from the other side\n"`)
+
+	// Unknown recordings are not found
+	_, err = streamExecRecording(t, c, uuid.Generate())
+	must.ErrorContains(t, err, "not found")
+}
+
+// streamExecRecording streams the recording of an exec session from the
+// client, returning the first error frame received.
+func streamExecRecording(t *testing.T, c *Client, id string) (string, error) {
+	handler, err := c.StreamingRpcHandler("ExecSession.Recording")
+	must.NoError(t, err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	go handler(p2)
+
+	encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+	decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
+	must.NoError(t, encoder.Encode(&cstructs.ExecSessionRecordingRequest{
+		SessionID:    id,
+		QueryOptions: nstructs.QueryOptions{Region: "global"},
+	}))
+
+	var buf bytes.Buffer
+	for {
+		var frame cstructs.StreamErrWrapper
+		if err := decoder.Decode(&frame); err != nil {
+			if strings.Contains(err.Error(), "EOF") || strings.Contains(err.Error(), "closed") {
+				return buf.String(), nil
+			}
+			return "", err
+		}
+		if frame.Error != nil {
+			return "", frame.Error
+		}
+		buf.Write(frame.Payload)
+	}
+}
@@ -4,7 +4,9 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -82,6 +84,8 @@ func NewFileSystemEndpoint(c *Client) *FileSystem {
 	f := &FileSystem{c}
 	f.c.streamingRpcs.Register("FileSystem.Logs", f.logs)
 	f.c.streamingRpcs.Register("FileSystem.Stream", f.stream)
+	f.c.streamingRpcs.Register("FileSystem.Upload", f.upload)
+	f.c.streamingRpcs.Register("FileSystem.Archive", f.archive)
 	return f
 }
 
@@ -111,7 +115,7 @@ func (f *FileSystem) List(args *cstructs.FsListRequest, reply *cstructs.FsListRe
 	// Check namespace read-fs permission.
 	if aclObj, err := f.c.ResolveToken(args.QueryOptions.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadFS) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -140,7 +144,7 @@ func (f *FileSystem) Stat(args *cstructs.FsStatRequest, reply *cstructs.FsStatRe
 	// Check namespace read-fs permission.
 	if aclObj, err := f.c.ResolveToken(args.QueryOptions.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadFS) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -157,6 +161,213 @@ func (f *FileSystem) Stat(args *cstructs.FsStatRequest, reply *cstructs.FsStatRe
 	return nil
 }
 
+// Remove is used to remove a file or directory from an allocation's
+// directory.
+func (f *FileSystem) Remove(args *cstructs.FsRemoveRequest, reply *cstructs.FsRemoveResponse) error {
+	defer metrics.MeasureSince([]string{"client", "file_system", "remove"}, time.Now())
+
+	alloc, err := f.c.GetAlloc(args.AllocID)
+	if err != nil {
+		return err
+	}
+
+	// Check namespace write-fs permission.
+	if aclObj, err := f.c.ResolveToken(args.QueryOptions.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityWriteFS) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.Path == "" {
+		return pathNotPresentErr
+	}
+
+	fs, err := f.c.GetAllocFS(args.AllocID)
+	if err != nil {
+		return err
+	}
+	return fs.Remove(args.Path)
+}
+
+// upload is used to write a file, or extract a tar archive, into an
+// allocation's directory.
+func (f *FileSystem) upload(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "file_system", "upload"}, time.Now())
+	defer conn.Close()
+
+	// Decode the arguments
+	var req cstructs.FsUploadRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&req); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(http.StatusInternalServerError)), encoder)
+		return
+	}
+
+	fs, code, err := f.streamAllocFS(req.AllocID, req.Path, req.AuthToken, acl.NamespaceCapabilityWriteFS)
+	if err != nil {
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	// Copy the payload of the frames until the empty frame ending the upload
+	pr, pw := io.Pipe()
+	go func() {
+		for {
+			var frame cstructs.StreamErrWrapper
+			if err := decoder.Decode(&frame); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if len(frame.Payload) == 0 {
+				pw.Close()
+				return
+			}
+			if _, err := pw.Write(frame.Payload); err != nil {
+				return
+			}
+		}
+	}()
+
+	if req.Archive {
+		err = untar(fs, req.Path, pr)
+	} else {
+		mode := os.FileMode(req.FileMode).Perm()
+		if mode == 0 {
+			mode = 0644
+		}
+		err = fs.WriteFile(req.Path, pr, mode)
+	}
+
+	// Drain the upload so the sender isn't blocked before reading the result
+	if err == nil {
+		_, err = io.Copy(io.Discard, pr)
+	}
+	pr.CloseWithError(io.ErrClosedPipe)
+
+	if err != nil {
+		code := pointer.Of(int64(http.StatusBadRequest))
+		if os.IsNotExist(err) {
+			code = pointer.Of(int64(http.StatusNotFound))
+		}
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	encoder.Encode(&cstructs.StreamErrWrapper{})
+}
+
+// untar extracts a tar archive, which is decompressed first if it is gzip
+// compressed.
+func untar(fs allocdir.AllocDirFS, path string, r io.Reader) error {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		return fs.Untar(path, gr)
+	}
+	return fs.Untar(path, br)
+}
+
+// archive is used to stream a gzip compressed tar archive of a file or
+// directory of an allocation's directory.
+func (f *FileSystem) archive(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "file_system", "archive"}, time.Now())
+	defer conn.Close()
+
+	// Decode the arguments
+	var req cstructs.FsArchiveRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&req); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(http.StatusInternalServerError)), encoder)
+		return
+	}
+
+	fs, code, err := f.streamAllocFS(req.AllocID, req.Path, req.AuthToken, acl.NamespaceCapabilityReadFS)
+	if err != nil {
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	if _, err := fs.Stat(req.Path); err != nil {
+		code := pointer.Of(int64(http.StatusBadRequest))
+		if os.IsNotExist(err) {
+			code = pointer.Of(int64(http.StatusNotFound))
+		}
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	w := bufio.NewWriterSize(&frameWriter{encoder: encoder}, streamFrameSize)
+	if err := fs.Archive(req.Path, w); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(http.StatusInternalServerError)), encoder)
+		return
+	}
+	if err := w.Flush(); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(http.StatusInternalServerError)), encoder)
+	}
+}
+
+// streamAllocFS returns the filesystem of an allocation for a streaming
+// filesystem request, after checking the token has the capability for the
+// allocation. On error, it returns the status code of the error.
+func (f *FileSystem) streamAllocFS(allocID, path, token, capability string) (allocdir.AllocDirFS, *int64, error) {
+	if allocID == "" {
+		return nil, pointer.Of(int64(http.StatusBadRequest)), allocIDNotPresentErr
+	}
+
+	ar, err := f.c.getAllocRunner(allocID)
+	if err != nil {
+		return nil, pointer.Of(int64(http.StatusNotFound)), structs.NewErrUnknownAllocation(allocID)
+	}
+	if ar.IsDestroyed() {
+		return nil, pointer.Of(int64(http.StatusNotFound)),
+			fmt.Errorf("state for allocation %s not found on client", allocID)
+	}
+	alloc := ar.Alloc()
+
+	if aclObj, err := f.c.ResolveToken(token); err != nil {
+		return nil, pointer.Of(int64(http.StatusForbidden)), err
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, capability) {
+		return nil, pointer.Of(int64(http.StatusForbidden)), structs.ErrPermissionDenied
+	}
+
+	if path == "" {
+		return nil, pointer.Of(int64(http.StatusBadRequest)), pathNotPresentErr
+	}
+
+	fs, err := f.c.GetAllocFS(allocID)
+	if err != nil {
+		code := pointer.Of(int64(http.StatusInternalServerError))
+		if structs.IsErrUnknownAllocation(err) {
+			code = pointer.Of(int64(http.StatusNotFound))
+		}
+		return nil, code, err
+	}
+	return fs, nil, nil
+}
+
+// frameWriter sends the data written to it as the payload of stream frames.
+type frameWriter struct {
+	encoder *codec.Encoder
+}
+
+func (w *frameWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if err := w.encoder.Encode(&cstructs.StreamErrWrapper{Payload: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
 // stream is is used to stream the contents of file in an allocation's
 // directory.
 func (f *FileSystem) stream(conn io.ReadWriteCloser) {
@@ -197,7 +408,7 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	if aclObj, err := f.c.ResolveToken(req.QueryOptions.AuthToken); err != nil {
 		handleStreamResultError(err, pointer.Of(int64(http.StatusForbidden)), encoder)
 		return
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadFS) {
 		handleStreamResultError(structs.ErrPermissionDenied, pointer.Of(int64(http.StatusForbidden)), encoder)
 		return
 	}
@@ -383,8 +594,8 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 		handleStreamResultError(err, nil, encoder)
 		return
 	} else if aclObj != nil {
-		readfs := aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS)
-		logs := aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadLogs)
+		readfs := aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadFS)
+		logs := aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadLogs)
 		if !readfs && !logs {
 			handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 			return
@@ -4,6 +4,9 @@
 package client
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -320,6 +323,276 @@ func TestFS_List_ACL(t *testing.T) {
 	}
 }
 
+// testFSUpload uploads the payload with the FileSystem.Upload streaming RPC
+// and returns the result frame.
+func testFSUpload(t *testing.T, c *Client, req *cstructs.FsUploadRequest, payload []byte) *cstructs.StreamErrWrapper {
+	handler, err := c.StreamingRpcHandler("FileSystem.Upload")
+	must.NoError(t, err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+	go handler(p2)
+
+	resultCh := make(chan *cstructs.StreamErrWrapper, 1)
+	go func() {
+		var msg cstructs.StreamErrWrapper
+		decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+		if err := decoder.Decode(&msg); err != nil {
+			msg.Error = cstructs.NewRpcError(err, nil)
+		}
+		resultCh <- &msg
+	}()
+
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	go func() {
+		if err := encoder.Encode(req); err != nil {
+			return
+		}
+		for len(payload) > 0 {
+			n := min(len(payload), 4)
+			if err := encoder.Encode(&cstructs.StreamErrWrapper{Payload: payload[:n]}); err != nil {
+				return
+			}
+			payload = payload[n:]
+		}
+		encoder.Encode(&cstructs.StreamErrWrapper{})
+	}()
+
+	select {
+	case msg := <-resultCh:
+		return msg
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for upload")
+	}
+	return nil
+}
+
+// testFSArchive returns the entries of the archive streamed by the
+// FileSystem.Archive streaming RPC, mapped to the content of files.
+func testFSArchive(t *testing.T, c *Client, req *cstructs.FsArchiveRequest) (map[string]string, *cstructs.RpcError) {
+	handler, err := c.StreamingRpcHandler("FileSystem.Archive")
+	must.NoError(t, err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+	go handler(p2)
+
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	must.NoError(t, encoder.Encode(req))
+
+	var buf bytes.Buffer
+	decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+	for {
+		var msg cstructs.StreamErrWrapper
+		if err := decoder.Decode(&msg); err == io.EOF {
+			break
+		} else {
+			must.NoError(t, err)
+		}
+		if msg.Error != nil {
+			return nil, msg.Error
+		}
+		buf.Write(msg.Payload)
+	}
+
+	gr, err := gzip.NewReader(&buf)
+	must.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	entries := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		must.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		must.NoError(t, err)
+		entries[hdr.Name] = string(data)
+	}
+	return entries, nil
+}
+
+func TestFS_Upload(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server and client
+	s, cleanupS := nomad.TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+
+	// Wait for alloc to be running
+	alloc := testutil.WaitForRunning(t, s.RPC, job)[0]
+	queryOpts := structs.QueryOptions{Region: "global"}
+
+	// Upload a single file
+	msg := testFSUpload(t, c, &cstructs.FsUploadRequest{
+		AllocID:      alloc.ID,
+		Path:         "alloc/data/config.json",
+		FileMode:     0600,
+		QueryOptions: queryOpts,
+	}, []byte(`{"debug": true}`))
+	must.Nil(t, msg.Error)
+
+	var stat cstructs.FsStatResponse
+	must.NoError(t, c.ClientRPC("FileSystem.Stat", &cstructs.FsStatRequest{
+		AllocID:      alloc.ID,
+		Path:         "alloc/data/config.json",
+		QueryOptions: queryOpts,
+	}, &stat))
+	must.Eq(t, 15, stat.Info.Size)
+	must.Eq(t, os.FileMode(0600).String(), stat.Info.FileMode)
+
+	// Upload a gzip compressed archive into a new directory
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	must.NoError(t, tw.WriteHeader(&tar.Header{Name: "dump/", Typeflag: tar.TypeDir, Mode: 0755}))
+	must.NoError(t, tw.WriteHeader(&tar.Header{Name: "dump/heap.prof", Typeflag: tar.TypeReg, Mode: 0644, Size: 4}))
+	_, err := tw.Write([]byte("heap"))
+	must.NoError(t, err)
+	must.NoError(t, tw.Close())
+	must.NoError(t, gw.Close())
+
+	msg = testFSUpload(t, c, &cstructs.FsUploadRequest{
+		AllocID:      alloc.ID,
+		Path:         "alloc/debug",
+		Archive:      true,
+		QueryOptions: queryOpts,
+	}, buf.Bytes())
+	must.Nil(t, msg.Error)
+
+	// Download the directory as an archive
+	entries, rpcErr := testFSArchive(t, c, &cstructs.FsArchiveRequest{
+		AllocID:      alloc.ID,
+		Path:         "alloc/debug",
+		QueryOptions: queryOpts,
+	})
+	must.Nil(t, rpcErr)
+	must.Eq(t, map[string]string{"dump/": "", "dump/heap.prof": "heap"}, entries)
+
+	// Remove the directory
+	must.NoError(t, c.ClientRPC("FileSystem.Remove", &cstructs.FsRemoveRequest{
+		AllocID:      alloc.ID,
+		Path:         "alloc/debug",
+		QueryOptions: queryOpts,
+	}, &cstructs.FsRemoveResponse{}))
+
+	_, rpcErr = testFSArchive(t, c, &cstructs.FsArchiveRequest{
+		AllocID:      alloc.ID,
+		Path:         "alloc/debug",
+		QueryOptions: queryOpts,
+	})
+	must.NotNil(t, rpcErr)
+	must.Eq(t, int64(http.StatusNotFound), *rpcErr.Code)
+
+	// Uploads escaping the alloc dir are rejected
+	msg = testFSUpload(t, c, &cstructs.FsUploadRequest{
+		AllocID:      alloc.ID,
+		Path:         "../escape",
+		QueryOptions: queryOpts,
+	}, []byte("escape"))
+	must.NotNil(t, msg.Error)
+	must.Eq(t, int64(http.StatusBadRequest), *msg.Error.Code)
+}
+
+func TestFS_Upload_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server
+	s, root, cleanupS := nomad.TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanup()
+
+	// A read-fs token can't write
+	policyRead := mock.NamespacePolicy(structs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityReadFS})
+	tokenRead := mock.CreatePolicyAndToken(t, s.State(), 1005, "read", policyRead)
+
+	policyGood := mock.NamespacePolicy(structs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityWriteFS})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "write", policyGood)
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+
+	// Wait for client to be running job
+	alloc := testutil.WaitForRunningWithToken(t, s.RPC, job, root.SecretID)[0]
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "read token",
+			Token:         tokenRead.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:  "good token",
+			Token: tokenGood.SecretID,
+		},
+		{
+			Name:  "root token",
+			Token: root.SecretID,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			queryOpts := structs.QueryOptions{
+				Region:    "global",
+				AuthToken: c.Token,
+				Namespace: structs.DefaultNamespace,
+			}
+
+			msg := testFSUpload(t, client, &cstructs.FsUploadRequest{
+				AllocID:      alloc.ID,
+				Path:         "alloc/data/acl",
+				QueryOptions: queryOpts,
+			}, []byte("acl"))
+
+			err := client.ClientRPC("FileSystem.Remove", &cstructs.FsRemoveRequest{
+				AllocID:      alloc.ID,
+				Path:         "alloc/data/acl",
+				QueryOptions: queryOpts,
+			}, &cstructs.FsRemoveResponse{})
+
+			if c.ExpectedError == "" {
+				must.Nil(t, msg.Error)
+				must.NoError(t, err)
+			} else {
+				must.NotNil(t, msg.Error)
+				must.Eq(t, c.ExpectedError, msg.Error.Error())
+				must.EqError(t, err, c.ExpectedError)
+			}
+		})
+	}
+}
+
 func TestFS_Stream_NoAlloc(t *testing.T) {
 	ci.Parallel(t)
 	ci.SkipSlow(t, "flaky on GHA; #12358")
@@ -37,6 +37,8 @@ type EnvReplacer interface {
 
 // ArtifactGetter is an interface satisfied by the getter package.
 type ArtifactGetter interface {
-	// Get artifact and put it in the task directory.
-	Get(taskEnv EnvReplacer, artifact *structs.TaskArtifact) error
+	// Get artifact and put it in the task directory. The signature of the
+	// artifact is verified against the trusted keys of the client and the
+	// given keys.
+	Get(taskEnv EnvReplacer, artifact *structs.TaskArtifact, keys map[string]string) error
 }
@@ -29,6 +29,22 @@ func (c *logmonClient) Start(cfg *LogConfig) error {
 		MaxFileSizeMb:  uint32(cfg.MaxFileSizeMB),
 		StdoutFifo:     cfg.StdoutFifo,
 		StderrFifo:     cfg.StderrFifo,
+		AllocId:        cfg.AllocID,
+		Namespace:      cfg.Namespace,
+		JobId:          cfg.JobID,
+		TaskGroup:      cfg.TaskGroup,
+		TaskName:       cfg.TaskName,
+	}
+	for _, sink := range cfg.Sinks {
+		req.Sinks = append(req.Sinks, &proto.LogSink{
+			Type:       sink.Type,
+			Address:    sink.Address,
+			Path:       sink.Path,
+			Facility:   sink.Facility,
+			Tag:        sink.Tag,
+			Headers:    sink.Headers,
+			BufferSize: uint32(sink.BufferSize),
+		})
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
 	defer cancel()
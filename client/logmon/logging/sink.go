@@ -0,0 +1,341 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+const (
+	// SinkTypeSyslog ships log lines as RFC5424 messages to a syslog server.
+	SinkTypeSyslog = "syslog"
+
+	// SinkTypeJournald ships log lines to the native socket of journald.
+	SinkTypeJournald = "journald"
+
+	// SinkTypeOTLP ships log lines to an OTLP/HTTP logs endpoint.
+	SinkTypeOTLP = "otlp"
+
+	// SinkTypeFile writes log lines as JSON to a rotated file.
+	SinkTypeFile = "file"
+
+	// StreamStdout and StreamStderr name the stream a log line was written to.
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+
+	// defaultSinkBufferSize is the number of lines buffered for a sink when
+	// its config doesn't set a buffer size.
+	defaultSinkBufferSize = 1024
+
+	// maxSinkLineSize is the size at which a line that isn't terminated by a
+	// new line is split into several entries.
+	maxSinkLineSize = 64 * 1024
+
+	// sinkBatchSize is the maximum number of entries sent at once.
+	sinkBatchSize = 256
+
+	// sinkRetryInterval is how long a sink waits after failing to send a
+	// batch before sending the next one. Lines written in the meantime are
+	// buffered, or dropped once the buffer is full.
+	sinkRetryInterval = 1 * time.Second
+
+	// sinkDropWarnInterval is the minimum interval between warnings about
+	// dropped lines.
+	sinkDropWarnInterval = 1 * time.Minute
+
+	// sinkCloseTimeout is how long Close waits for the buffered lines to be
+	// sent.
+	sinkCloseTimeout = 5 * time.Second
+
+	// sinkWriteTimeout bounds the time spent sending a batch.
+	sinkWriteTimeout = 10 * time.Second
+)
+
+// SinkConfig configures a sink the task logs are shipped to.
+type SinkConfig struct {
+	// Type is the type of the sink, such as SinkTypeSyslog.
+	Type string
+
+	// Address is the address of the syslog server, the path of the journald
+	// socket, or the URL of the OTLP logs endpoint.
+	Address string
+
+	// Path is the name of the file written by the file sink in the log
+	// directory.
+	Path string
+
+	// Facility is the syslog facility of the messages.
+	Facility string
+
+	// Tag is the syslog app name, journald identifier and OTLP service name
+	// of the messages. It defaults to the task name.
+	Tag string
+
+	// Headers are the HTTP headers sent to the OTLP endpoint.
+	Headers map[string]string
+
+	// BufferSize is the number of lines buffered for the sink.
+	BufferSize int
+}
+
+// SinkOptions are the options of the task logger shared by its sinks.
+type SinkOptions struct {
+	// AllocID, Namespace, JobID, TaskGroup and TaskName identify the task
+	// whose logs are shipped. They are attached to every log line.
+	AllocID   string
+	Namespace string
+	JobID     string
+	TaskGroup string
+	TaskName  string
+
+	// LogDir, MaxFiles and MaxFileSize configure the rotation of the file
+	// written by the file sink.
+	LogDir      string
+	MaxFiles    int
+	MaxFileSize int64
+}
+
+// SinkEntry is a line written by the task.
+type SinkEntry struct {
+	Time   time.Time
+	Stream string
+	Line   string
+}
+
+// sinkBackend sends entries to the destination of a sink. Its methods are
+// only called from the goroutine of the sink.
+type sinkBackend interface {
+	Send(entries []*SinkEntry) error
+	Close() error
+}
+
+// Sink buffers the entries enqueued by the task logger and sends them to a
+// backend in the background. Enqueue never blocks: entries are dropped when
+// the buffer is full because the backend is slow or unavailable.
+type Sink struct {
+	backend sinkBackend
+	logger  hclog.Logger
+
+	entries chan *SinkEntry
+	dropped atomic.Uint64
+
+	// closeLock guards closed so entries aren't enqueued once the entries
+	// channel is closed.
+	closeLock sync.RWMutex
+	closed    bool
+
+	// doneCh is closed when the goroutine of the sink exits, and stopCh
+	// interrupts it when Close times out.
+	doneCh chan struct{}
+	stopCh chan struct{}
+}
+
+// NewSink returns a running sink for the given config.
+func NewSink(cfg *SinkConfig, opts *SinkOptions, logger hclog.Logger) (*Sink, error) {
+	var backend sinkBackend
+	var err error
+	switch cfg.Type {
+	case SinkTypeSyslog:
+		backend, err = newSyslogSink(cfg, opts)
+	case SinkTypeJournald:
+		backend, err = newJournaldSink(cfg, opts)
+	case SinkTypeOTLP:
+		backend, err = newOTLPSink(cfg, opts)
+	case SinkTypeFile:
+		backend, err = newFileSink(cfg, opts, logger)
+	default:
+		err = fmt.Errorf("unknown sink type %q", cfg.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newSink(cfg, backend, logger), nil
+}
+
+func newSink(cfg *SinkConfig, backend sinkBackend, logger hclog.Logger) *Sink {
+	size := cfg.BufferSize
+	if size <= 0 {
+		size = defaultSinkBufferSize
+	}
+
+	s := &Sink{
+		backend: backend,
+		logger:  logger.Named("sink").With("type", cfg.Type),
+		entries: make(chan *SinkEntry, size),
+		doneCh:  make(chan struct{}),
+		stopCh:  make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Enqueue buffers an entry to be sent, or drops it if the buffer is full.
+func (s *Sink) Enqueue(e *SinkEntry) {
+	s.closeLock.RLock()
+	defer s.closeLock.RUnlock()
+
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.entries <- e:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of entries dropped and not yet reported.
+func (s *Sink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+func (s *Sink) run() {
+	defer close(s.doneCh)
+
+	var lastWarn time.Time
+	batch := make([]*SinkEntry, 0, sinkBatchSize)
+	for {
+		e, ok := <-s.entries
+		if !ok {
+			return
+		}
+
+		batch = append(batch[:0], e)
+	FILL:
+		for len(batch) < sinkBatchSize {
+			select {
+			case e, ok := <-s.entries:
+				if !ok {
+					break FILL
+				}
+				batch = append(batch, e)
+			default:
+				break FILL
+			}
+		}
+
+		if err := s.backend.Send(batch); err != nil {
+			s.dropped.Add(uint64(len(batch)))
+			s.logger.Warn("failed to send log lines", "error", err)
+
+			select {
+			case <-time.After(sinkRetryInterval):
+			case <-s.stopCh:
+				return
+			}
+		}
+
+		select {
+		case <-s.stopCh:
+			return
+		default:
+		}
+
+		if time.Since(lastWarn) > sinkDropWarnInterval {
+			if dropped := s.dropped.Swap(0); dropped > 0 {
+				s.logger.Warn("dropped log lines", "dropped", dropped)
+				lastWarn = time.Now()
+			}
+		}
+	}
+}
+
+// Close stops accepting entries, waits for the buffered entries to be sent
+// and closes the backend.
+func (s *Sink) Close() error {
+	s.closeLock.Lock()
+	if s.closed {
+		s.closeLock.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.entries)
+	s.closeLock.Unlock()
+
+	select {
+	case <-s.doneCh:
+	case <-time.After(sinkCloseTimeout):
+		s.logger.Warn("timed out sending buffered log lines", "buffered", len(s.entries))
+		close(s.stopCh)
+		<-s.doneCh
+	}
+
+	if dropped := s.dropped.Swap(0); dropped > 0 {
+		s.logger.Warn("dropped log lines", "dropped", dropped)
+	}
+
+	return s.backend.Close()
+}
+
+// SinkWriter splits the output of a task into lines and enqueues them on a
+// set of sinks. Writes never block on the sinks.
+type SinkWriter struct {
+	stream string
+	sinks  []*Sink
+	buf    []byte
+}
+
+// NewSinkWriter returns a writer for the given stream of the task.
+func NewSinkWriter(stream string, sinks []*Sink) *SinkWriter {
+	return &SinkWriter{
+		stream: stream,
+		sinks:  sinks,
+	}
+}
+
+func (w *SinkWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	var start int
+	for {
+		idx := bytes.IndexByte(w.buf[start:], '\n')
+		if idx < 0 {
+			break
+		}
+		w.emit(w.buf[start : start+idx])
+		start += idx + 1
+	}
+	for len(w.buf)-start >= maxSinkLineSize {
+		w.emit(w.buf[start : start+maxSinkLineSize])
+		start += maxSinkLineSize
+	}
+
+	w.buf = append(w.buf[:0], w.buf[start:]...)
+	return len(p), nil
+}
+
+// Flush enqueues the remaining partial line, if any.
+func (w *SinkWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.emit(w.buf)
+		w.buf = w.buf[:0]
+	}
+}
+
+func (w *SinkWriter) emit(line []byte) {
+	e := &SinkEntry{
+		Time:   time.Now(),
+		Stream: w.stream,
+		Line:   string(bytes.TrimSuffix(line, []byte{'\r'})),
+	}
+	for _, s := range w.sinks {
+		s.Enqueue(e)
+	}
+}
+
+// sinkTag returns the tag of the messages sent by a sink.
+func sinkTag(cfg *SinkConfig, opts *SinkOptions) string {
+	if cfg.Tag != "" {
+		return cfg.Tag
+	}
+	return opts.TaskName
+}
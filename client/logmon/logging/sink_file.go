@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+// fileSink writes entries as JSON lines, with the metadata of the task, to a
+// file in the log directory. The file is rotated like the stdout and stderr
+// files of the task.
+type fileSink struct {
+	rotator *FileRotator
+	opts    *SinkOptions
+	buf     bytes.Buffer
+}
+
+// fileSinkLine is a line of the file written by the file sink.
+type fileSinkLine struct {
+	Time      time.Time `json:"time"`
+	Stream    string    `json:"stream"`
+	AllocID   string    `json:"alloc_id,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	JobID     string    `json:"job_id,omitempty"`
+	TaskGroup string    `json:"task_group,omitempty"`
+	Task      string    `json:"task,omitempty"`
+	Message   string    `json:"message"`
+}
+
+func newFileSink(cfg *SinkConfig, opts *SinkOptions, logger hclog.Logger) (*fileSink, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("file sinks require a path")
+	}
+
+	rotator, err := NewFileRotator(opts.LogDir, cfg.Path, opts.MaxFiles, opts.MaxFileSize, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSink{
+		rotator: rotator,
+		opts:    opts,
+	}, nil
+}
+
+func (s *fileSink) Send(entries []*SinkEntry) error {
+	s.buf.Reset()
+	enc := json.NewEncoder(&s.buf)
+	for _, e := range entries {
+		err := enc.Encode(&fileSinkLine{
+			Time:      e.Time.UTC(),
+			Stream:    e.Stream,
+			AllocID:   s.opts.AllocID,
+			Namespace: s.opts.Namespace,
+			JobID:     s.opts.JobID,
+			TaskGroup: s.opts.TaskGroup,
+			Task:      s.opts.TaskName,
+			Message:   e.Line,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := s.rotator.Write(s.buf.Bytes())
+	return err
+}
+
+func (s *fileSink) Close() error {
+	return s.rotator.Close()
+}
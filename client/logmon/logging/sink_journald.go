@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultJournaldSocket is the native socket of journald.
+const defaultJournaldSocket = "/run/systemd/journal/socket"
+
+// journaldSink sends entries to journald using its native protocol, which
+// sends each entry as a datagram of fields.
+type journaldSink struct {
+	address string
+	conn    *net.UnixConn
+
+	// fields are the fields sent with every entry.
+	fields []byte
+}
+
+func newJournaldSink(cfg *SinkConfig, opts *SinkOptions) (*journaldSink, error) {
+	s := &journaldSink{
+		address: cfg.Address,
+	}
+	if s.address == "" {
+		s.address = defaultJournaldSocket
+	}
+
+	var fields bytes.Buffer
+	appendJournaldField(&fields, "SYSLOG_IDENTIFIER", sinkTag(cfg, opts))
+	appendJournaldField(&fields, "NOMAD_ALLOC_ID", opts.AllocID)
+	appendJournaldField(&fields, "NOMAD_NAMESPACE", opts.Namespace)
+	appendJournaldField(&fields, "NOMAD_JOB_ID", opts.JobID)
+	appendJournaldField(&fields, "NOMAD_TASK_GROUP", opts.TaskGroup)
+	appendJournaldField(&fields, "NOMAD_TASK_NAME", opts.TaskName)
+	s.fields = fields.Bytes()
+
+	return s, nil
+}
+
+func (s *journaldSink) Send(entries []*SinkEntry) error {
+	if s.conn == nil {
+		conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: s.address, Net: "unixgram"})
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+	var buf bytes.Buffer
+	for _, e := range entries {
+		priority := syslogSeverityInfo
+		if e.Stream == StreamStderr {
+			priority = syslogSeverityErr
+		}
+
+		buf.Reset()
+		buf.Write(s.fields)
+		appendJournaldField(&buf, "PRIORITY", strconv.Itoa(priority))
+		appendJournaldField(&buf, "NOMAD_LOG_STREAM", e.Stream)
+		appendJournaldField(&buf, "MESSAGE", e.Line)
+
+		if _, err := s.conn.Write(buf.Bytes()); err != nil {
+			s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *journaldSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
+
+// appendJournaldField appends a field to a journald datagram. Values with a
+// new line are serialized with their length rather than terminated by a new
+// line.
+func appendJournaldField(buf *bytes.Buffer, key, value string) {
+	if value == "" {
+		return
+	}
+
+	buf.WriteString(key)
+	if !strings.ContainsRune(value, '\n') {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+
+	buf.WriteByte('\n')
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// otlpSeverityInfo and otlpSeverityError are the severity numbers of the
+	// lines written to stdout and stderr.
+	otlpSeverityInfo  = 9
+	otlpSeverityError = 17
+
+	// otlpScopeName is the instrumentation scope of the log records.
+	otlpScopeName = "nomad.logmon"
+)
+
+// otlpSink sends entries to an OTLP/HTTP logs endpoint, encoded as JSON.
+type otlpSink struct {
+	client   *http.Client
+	endpoint string
+	headers  map[string]string
+	resource otlpResource
+}
+
+func newOTLPSink(cfg *SinkConfig, opts *SinkOptions) (*otlpSink, error) {
+	u, err := url.Parse(cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid otlp address %q: %v", cfg.Address, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("otlp address %q must be an http or https URL", cfg.Address)
+	}
+
+	s := &otlpSink{
+		client:   &http.Client{Timeout: sinkWriteTimeout},
+		endpoint: cfg.Address,
+		headers:  cfg.Headers,
+	}
+	for _, attr := range []struct{ key, value string }{
+		{"service.name", sinkTag(cfg, opts)},
+		{"nomad.alloc.id", opts.AllocID},
+		{"nomad.namespace", opts.Namespace},
+		{"nomad.job.id", opts.JobID},
+		{"nomad.group.name", opts.TaskGroup},
+		{"nomad.task.name", opts.TaskName},
+	} {
+		if attr.value != "" {
+			s.resource.Attributes = append(s.resource.Attributes, otlpString(attr.key, attr.value))
+		}
+	}
+
+	return s, nil
+}
+
+func (s *otlpSink) Send(entries []*SinkEntry) error {
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+	records := make([]otlpLogRecord, len(entries))
+	for i, e := range entries {
+		records[i] = otlpLogRecord{
+			TimeUnixNano:         strconv.FormatInt(e.Time.UnixNano(), 10),
+			ObservedTimeUnixNano: now,
+			SeverityNumber:       otlpSeverityInfo,
+			SeverityText:         "INFO",
+			Body:                 otlpAnyValue{StringValue: e.Line},
+			Attributes:           []otlpKeyValue{otlpString("log.iostream", e.Stream)},
+		}
+		if e.Stream == StreamStderr {
+			records[i].SeverityNumber = otlpSeverityError
+			records[i].SeverityText = "ERROR"
+		}
+	}
+
+	body, err := json.Marshal(&otlpLogsRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: s.resource,
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: otlpScopeName},
+				LogRecords: records,
+			}},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response from otlp endpoint: %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *otlpSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// The types below are the subset of the OTLP logs data model sent by the
+// sink, with the JSON encoding of the OTLP/HTTP protocol.
+
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+func otlpString(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: value}}
+}
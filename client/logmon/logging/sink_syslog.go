@@ -0,0 +1,228 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultSyslogAddress is the socket of the local syslog daemon.
+	defaultSyslogAddress = "unix:///dev/log"
+
+	// syslogStructuredDataID is the SD-ID of the structured data element
+	// holding the task metadata. 32473 is the private enterprise number
+	// reserved for documentation by RFC5612.
+	syslogStructuredDataID = "nomad@32473"
+
+	// syslogSeverityInfo and syslogSeverityErr are the severities of the
+	// lines written to stdout and stderr.
+	syslogSeverityInfo = 6
+	syslogSeverityErr  = 3
+)
+
+// syslogFacilities maps facility names to their codes.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// syslogSink sends entries as RFC5424 messages to a syslog server over a unix
+// socket, TCP or UDP.
+type syslogSink struct {
+	network string
+	address string
+
+	facility int
+	hostname string
+	appName  string
+	sd       string
+
+	conn net.Conn
+
+	// octetCounting frames messages sent over a stream socket with their
+	// length as described by RFC6587.
+	octetCounting bool
+}
+
+func newSyslogSink(cfg *SinkConfig, opts *SinkOptions) (*syslogSink, error) {
+	address := cfg.Address
+	if address == "" {
+		address = defaultSyslogAddress
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid syslog address %q: %v", address, err)
+	}
+
+	s := &syslogSink{
+		network:  u.Scheme,
+		facility: syslogFacilities["user"],
+		appName:  syslogName(sinkTag(cfg, opts), 48),
+		sd:       syslogStructuredData(opts),
+	}
+
+	switch u.Scheme {
+	case "unix":
+		s.address = u.Path
+		if s.address == "" {
+			s.address = u.Opaque
+		}
+	case "tcp", "udp":
+		s.address = u.Host
+	default:
+		return nil, fmt.Errorf("invalid syslog address %q: scheme must be one of unix, tcp or udp", address)
+	}
+
+	if cfg.Facility != "" {
+		facility, ok := syslogFacilities[cfg.Facility]
+		if !ok {
+			return nil, fmt.Errorf("invalid syslog facility %q", cfg.Facility)
+		}
+		s.facility = facility
+	}
+
+	s.hostname, _ = os.Hostname()
+	s.hostname = syslogName(s.hostname, 255)
+
+	return s, nil
+}
+
+// connect dials the syslog server. Like the syslog(3) client, unix sockets
+// are dialed as datagram sockets first, and as stream sockets if that fails.
+func (s *syslogSink) connect() error {
+	var err error
+	switch s.network {
+	case "unix":
+		for _, network := range []string{"unixgram", "unix"} {
+			s.conn, err = net.DialTimeout(network, s.address, sinkWriteTimeout)
+			if err == nil {
+				return nil
+			}
+		}
+	default:
+		s.conn, err = net.DialTimeout(s.network, s.address, sinkWriteTimeout)
+		s.octetCounting = s.network == "tcp"
+	}
+	return err
+}
+
+func (s *syslogSink) Send(entries []*SinkEntry) error {
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+	for _, e := range entries {
+		msg := s.format(e)
+		if s.octetCounting {
+			msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+		}
+		if _, err := s.conn.Write(msg); err != nil {
+			s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+// format returns the RFC5424 message of an entry. The message ID is the
+// stream the line was written to.
+func (s *syslogSink) format(e *SinkEntry) []byte {
+	severity := syslogSeverityInfo
+	if e.Stream == StreamStderr {
+		severity = syslogSeverityErr
+	}
+
+	hostname := s.hostname
+	if hostname == "" {
+		hostname = "-"
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%d>1 %s %s %s - %s %s %s",
+		s.facility*8+severity,
+		e.Time.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		hostname,
+		s.appName,
+		e.Stream,
+		s.sd,
+		e.Line,
+	)
+	return buf.Bytes()
+}
+
+func (s *syslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
+
+// syslogStructuredData returns the structured data element holding the task
+// metadata.
+func syslogStructuredData(opts *SinkOptions) string {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+	var b strings.Builder
+	b.WriteString("[" + syslogStructuredDataID)
+	for _, param := range []struct{ name, value string }{
+		{"alloc_id", opts.AllocID},
+		{"namespace", opts.Namespace},
+		{"job", opts.JobID},
+		{"group", opts.TaskGroup},
+		{"task", opts.TaskName},
+	} {
+		if param.value != "" {
+			fmt.Fprintf(&b, ` %s="%s"`, param.name, escape.Replace(param.value))
+		}
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// syslogName returns a header field which only contains printable ASCII
+// characters and is at most max characters long.
+func syslogName(name string, max int) string {
+	if name == "" {
+		return "-"
+	}
+	b := []byte(name)
+	for i, c := range b {
+		if c < 33 || c > 126 {
+			b[i] = '_'
+		}
+	}
+	if len(b) > max {
+		b = b[:max]
+	}
+	return string(b)
+}
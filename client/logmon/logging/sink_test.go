@@ -0,0 +1,335 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/testlog"
+	"github.com/shoenig/test/must"
+)
+
+var testSinkOptions = &SinkOptions{
+	AllocID:   "a5f6fcb8-ec5c-4b2e-8a36-7a3b8a4e1f2d",
+	Namespace: "default",
+	JobID:     "example",
+	TaskGroup: "cache",
+	TaskName:  "redis",
+}
+
+// testSinkBackend records the entries it's sent. Send notifies sendCh, if
+// set, and then blocks while block is held.
+type testSinkBackend struct {
+	sendCh  chan struct{}
+	block   sync.Mutex
+	lock    sync.Mutex
+	entries []*SinkEntry
+	closed  bool
+}
+
+func (b *testSinkBackend) Send(entries []*SinkEntry) error {
+	if b.sendCh != nil {
+		select {
+		case b.sendCh <- struct{}{}:
+		default:
+		}
+	}
+
+	b.block.Lock()
+	defer b.block.Unlock()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.entries = append(b.entries, entries...)
+	return nil
+}
+
+func (b *testSinkBackend) Close() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.closed = true
+	return nil
+}
+
+func (b *testSinkBackend) lines() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	lines := make([]string, len(b.entries))
+	for i, e := range b.entries {
+		lines[i] = e.Stream + ": " + e.Line
+	}
+	return lines
+}
+
+func TestSinkWriter(t *testing.T) {
+	ci.Parallel(t)
+
+	backend := &testSinkBackend{}
+	sink := newSink(&SinkConfig{Type: "test"}, backend, testlog.HCLogger(t))
+
+	w := NewSinkWriter(StreamStdout, []*Sink{sink})
+	for _, s := range []string{"hello ", "world\r\nsecond\n", "", "\npartial"} {
+		n, err := w.Write([]byte(s))
+		must.NoError(t, err)
+		must.Eq(t, len(s), n)
+	}
+
+	// Lines longer than the max line size are split.
+	n, err := w.Write([]byte(strings.Repeat("x", maxSinkLineSize+1)))
+	must.NoError(t, err)
+	must.Eq(t, maxSinkLineSize+1, n)
+
+	w.Flush()
+	must.NoError(t, sink.Close())
+
+	must.Eq(t, []string{
+		"stdout: hello world",
+		"stdout: second",
+		"stdout: ",
+		"stdout: partial" + strings.Repeat("x", maxSinkLineSize-len("partial")),
+		"stdout: " + strings.Repeat("x", len("partial")+1),
+	}, backend.lines())
+	must.True(t, backend.closed)
+}
+
+func TestSink_DropsWhenFull(t *testing.T) {
+	ci.Parallel(t)
+
+	backend := &testSinkBackend{sendCh: make(chan struct{}, 1)}
+	backend.block.Lock()
+	sink := newSink(&SinkConfig{Type: "test", BufferSize: 2}, backend, testlog.HCLogger(t))
+
+	// The first entry is received by the goroutine of the sink, which then
+	// blocks sending it.
+	sink.Enqueue(&SinkEntry{Line: "first"})
+	select {
+	case <-backend.sendCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for send")
+	}
+
+	// Enqueueing never blocks, and entries are dropped once the buffer is
+	// full.
+	for i := 0; i < 5; i++ {
+		sink.Enqueue(&SinkEntry{Line: "next"})
+	}
+	must.Eq(t, uint64(3), sink.Dropped())
+
+	backend.block.Unlock()
+	must.NoError(t, sink.Close())
+	must.Len(t, 3, backend.lines())
+
+	// Entries enqueued after Close are ignored.
+	sink.Enqueue(&SinkEntry{Line: "closed"})
+	must.Len(t, 3, backend.lines())
+}
+
+func TestSink_Syslog(t *testing.T) {
+	ci.Parallel(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	must.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := NewSink(&SinkConfig{
+		Type:     SinkTypeSyslog,
+		Address:  "udp://" + conn.LocalAddr().String(),
+		Facility: "local0",
+	}, testSinkOptions, testlog.HCLogger(t))
+	must.NoError(t, err)
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	sink.Enqueue(&SinkEntry{Time: ts, Stream: StreamStdout, Line: "hello"})
+	sink.Enqueue(&SinkEntry{Time: ts, Stream: StreamStderr, Line: "failed"})
+
+	hostname, _ := os.Hostname()
+	buf := make([]byte, 1024)
+	for _, exp := range []string{
+		`<134>1 2024-01-02T03:04:05.000006Z ` + syslogName(hostname, 255) + ` redis - stdout ` +
+			`[nomad@32473 alloc_id="a5f6fcb8-ec5c-4b2e-8a36-7a3b8a4e1f2d" namespace="default" job="example" group="cache" task="redis"] hello`,
+		`<131>1 2024-01-02T03:04:05.000006Z ` + syslogName(hostname, 255) + ` redis - stderr ` +
+			`[nomad@32473 alloc_id="a5f6fcb8-ec5c-4b2e-8a36-7a3b8a4e1f2d" namespace="default" job="example" group="cache" task="redis"] failed`,
+	} {
+		must.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		must.NoError(t, err)
+		must.Eq(t, exp, string(buf[:n]))
+	}
+
+	must.NoError(t, sink.Close())
+}
+
+func TestSink_Syslog_TCP(t *testing.T) {
+	ci.Parallel(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	defer ln.Close()
+
+	sink, err := NewSink(&SinkConfig{
+		Type:    SinkTypeSyslog,
+		Address: "tcp://" + ln.Addr().String(),
+		Tag:     "my app",
+	}, &SinkOptions{}, testlog.HCLogger(t))
+	must.NoError(t, err)
+	sink.Enqueue(&SinkEntry{Time: time.Now(), Stream: StreamStdout, Line: "hello"})
+
+	conn, err := ln.Accept()
+	must.NoError(t, err)
+	defer conn.Close()
+	must.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	// Messages are framed with their length.
+	r := bufio.NewReader(conn)
+	prefix, err := r.ReadString(' ')
+	must.NoError(t, err)
+	length, err := strconv.Atoi(strings.TrimSpace(prefix))
+	must.NoError(t, err)
+	msg := make([]byte, length)
+	_, err = io.ReadFull(r, msg)
+	must.NoError(t, err)
+	must.StrHasPrefix(t, "<14>1 ", string(msg))
+	must.StrContains(t, string(msg), " my_app - stdout [nomad@32473] hello")
+
+	must.NoError(t, sink.Close())
+}
+
+func TestSink_Journald(t *testing.T) {
+	ci.Parallel(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("journald requires unix datagram sockets")
+	}
+
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	must.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := NewSink(&SinkConfig{
+		Type:    SinkTypeJournald,
+		Address: path,
+	}, testSinkOptions, testlog.HCLogger(t))
+	must.NoError(t, err)
+	sink.Enqueue(&SinkEntry{Time: time.Now(), Stream: StreamStderr, Line: "failed"})
+
+	buf := make([]byte, 1024)
+	must.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, err := conn.Read(buf)
+	must.NoError(t, err)
+	must.Eq(t, `SYSLOG_IDENTIFIER=redis
+NOMAD_ALLOC_ID=a5f6fcb8-ec5c-4b2e-8a36-7a3b8a4e1f2d
+NOMAD_NAMESPACE=default
+NOMAD_JOB_ID=example
+NOMAD_TASK_GROUP=cache
+NOMAD_TASK_NAME=redis
+PRIORITY=3
+NOMAD_LOG_STREAM=stderr
+MESSAGE=failed
+`, string(buf[:n]))
+
+	must.NoError(t, sink.Close())
+}
+
+func TestSink_OTLP(t *testing.T) {
+	ci.Parallel(t)
+
+	requests := make(chan *otlpLogsRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req otlpLogsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests <- &req
+	}))
+	defer srv.Close()
+
+	sink, err := NewSink(&SinkConfig{
+		Type:    SinkTypeOTLP,
+		Address: srv.URL + "/v1/logs",
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+	}, testSinkOptions, testlog.HCLogger(t))
+	must.NoError(t, err)
+
+	ts := time.Unix(0, 1704164645000000006)
+	sink.Enqueue(&SinkEntry{Time: ts, Stream: StreamStderr, Line: "failed"})
+
+	var req *otlpLogsRequest
+	select {
+	case req = <-requests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for otlp request")
+	}
+	must.NoError(t, sink.Close())
+
+	must.Len(t, 1, req.ResourceLogs)
+	must.SliceContains(t, req.ResourceLogs[0].Resource.Attributes, otlpString("service.name", "redis"))
+	must.SliceContains(t, req.ResourceLogs[0].Resource.Attributes, otlpString("nomad.job.id", "example"))
+
+	records := req.ResourceLogs[0].ScopeLogs[0].LogRecords
+	must.Len(t, 1, records)
+	must.Eq(t, "1704164645000000006", records[0].TimeUnixNano)
+	must.Eq(t, otlpSeverityError, records[0].SeverityNumber)
+	must.Eq(t, "failed", records[0].Body.StringValue)
+	must.Eq(t, []otlpKeyValue{otlpString("log.iostream", "stderr")}, records[0].Attributes)
+}
+
+func TestSink_File(t *testing.T) {
+	ci.Parallel(t)
+
+	opts := *testSinkOptions
+	opts.LogDir = t.TempDir()
+	opts.MaxFiles = 2
+	opts.MaxFileSize = 1024 * 1024
+
+	sink, err := NewSink(&SinkConfig{
+		Type: SinkTypeFile,
+		Path: "redis.json",
+	}, &opts, testlog.HCLogger(t))
+	must.NoError(t, err)
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sink.Enqueue(&SinkEntry{Time: ts, Stream: StreamStdout, Line: "hello"})
+	sink.Enqueue(&SinkEntry{Time: ts, Stream: StreamStderr, Line: `"quoted"`})
+	must.NoError(t, sink.Close())
+
+	b, err := os.ReadFile(filepath.Join(opts.LogDir, "redis.json.0"))
+	must.NoError(t, err)
+	must.Eq(t, `{"time":"2024-01-02T03:04:05Z","stream":"stdout","alloc_id":"a5f6fcb8-ec5c-4b2e-8a36-7a3b8a4e1f2d","namespace":"default","job_id":"example","task_group":"cache","task":"redis","message":"hello"}
+{"time":"2024-01-02T03:04:05Z","stream":"stderr","alloc_id":"a5f6fcb8-ec5c-4b2e-8a36-7a3b8a4e1f2d","namespace":"default","job_id":"example","task_group":"cache","task":"redis","message":"\"quoted\""}
+`, string(b))
+}
+
+func TestNewSink_Invalid(t *testing.T) {
+	ci.Parallel(t)
+
+	for _, cfg := range []*SinkConfig{
+		{Type: "kafka"},
+		{Type: SinkTypeSyslog, Address: "http://127.0.0.1:514"},
+		{Type: SinkTypeSyslog, Facility: "local9"},
+		{Type: SinkTypeOTLP, Address: "127.0.0.1:4318"},
+		{Type: SinkTypeFile},
+	} {
+		_, err := NewSink(cfg, testSinkOptions, testlog.HCLogger(t))
+		must.Error(t, err)
+	}
+}
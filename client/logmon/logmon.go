@@ -44,6 +44,18 @@ type LogConfig struct {
 
 	// MaxFileSizeMB is the max log file size in MB allowed before rotation occures
 	MaxFileSizeMB int
+
+	// Sinks are the destinations the logs are shipped to in addition to the
+	// rotated log files
+	Sinks []*logging.SinkConfig
+
+	// AllocID, Namespace, JobID, TaskGroup and TaskName identify the task
+	// whose logs are shipped to the sinks
+	AllocID   string
+	Namespace string
+	JobID     string
+	TaskGroup string
+	TaskName  string
 }
 
 type LogMon interface {
@@ -109,6 +121,9 @@ type TaskLogger struct {
 
 	// rotator for stderr
 	lre *logRotatorWrapper
+
+	// sinks the logs are shipped to
+	sinks []*logging.Sink
 }
 
 // IsRunning will return true as long as one rotator wrapper is still running
@@ -136,20 +151,50 @@ func (tl *TaskLogger) Close() {
 		}()
 	}
 	wg.Wait()
+
+	// Close the sinks once the rotator wrappers stopped writing to them
+	tl.closeSinks()
+}
+
+func (tl *TaskLogger) closeSinks() {
+	for _, sink := range tl.sinks {
+		sink.Close()
+	}
 }
 
 func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	tl := &TaskLogger{config: cfg}
 
 	logFileSize := int64(cfg.MaxFileSizeMB * 1024 * 1024)
+	sinkOpts := &logging.SinkOptions{
+		AllocID:     cfg.AllocID,
+		Namespace:   cfg.Namespace,
+		JobID:       cfg.JobID,
+		TaskGroup:   cfg.TaskGroup,
+		TaskName:    cfg.TaskName,
+		LogDir:      cfg.LogDir,
+		MaxFiles:    cfg.MaxFiles,
+		MaxFileSize: logFileSize,
+	}
+	for _, sinkCfg := range cfg.Sinks {
+		sink, err := logging.NewSink(sinkCfg, sinkOpts, logger)
+		if err != nil {
+			tl.closeSinks()
+			return nil, fmt.Errorf("failed to create %q log sink: %v", sinkCfg.Type, err)
+		}
+		tl.sinks = append(tl.sinks, sink)
+	}
+
 	lro, err := logging.NewFileRotator(cfg.LogDir, cfg.StdoutLogFile,
 		cfg.MaxFiles, logFileSize, logger)
 	if err != nil {
+		tl.closeSinks()
 		return nil, fmt.Errorf("failed to create stdout logfile for %q: %v", cfg.StdoutLogFile, err)
 	}
 
-	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, lro)
+	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, tl.withSinks(lro, logging.StreamStdout))
 	if err != nil {
+		tl.closeSinks()
 		return nil, err
 	}
 
@@ -158,11 +203,13 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	lre, err := logging.NewFileRotator(cfg.LogDir, cfg.StderrLogFile,
 		cfg.MaxFiles, logFileSize, logger)
 	if err != nil {
+		tl.closeSinks()
 		return nil, fmt.Errorf("failed to create stderr logfile for %q: %v", cfg.StderrLogFile, err)
 	}
 
-	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, lre)
+	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, tl.withSinks(lre, logging.StreamStderr))
 	if err != nil {
+		tl.closeSinks()
 		return nil, err
 	}
 
@@ -172,6 +219,36 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 
 }
 
+// withSinks returns a writer which writes the given stream to the rotator and
+// enqueues its lines on the sinks of the task logger.
+func (tl *TaskLogger) withSinks(rotator io.WriteCloser, stream string) io.WriteCloser {
+	if len(tl.sinks) == 0 {
+		return rotator
+	}
+	return &sinkTeeWriter{
+		rotator: rotator,
+		sinks:   logging.NewSinkWriter(stream, tl.sinks),
+	}
+}
+
+// sinkTeeWriter writes to a rotator and a sink writer. Writes to the sink
+// writer never block or fail, so the rotator is unaffected by the sinks.
+type sinkTeeWriter struct {
+	rotator io.WriteCloser
+	sinks   *logging.SinkWriter
+}
+
+func (w *sinkTeeWriter) Write(p []byte) (int, error) {
+	n, err := w.rotator.Write(p)
+	w.sinks.Write(p[:n])
+	return n, err
+}
+
+func (w *sinkTeeWriter) Close() error {
+	w.sinks.Flush()
+	return w.rotator.Close()
+}
+
 // logRotatorWrapper wraps our log rotator and exposes a pipe that can feed the
 // log rotator data. The processOutWriter should be attached to the process and
 // data will be copied from the reader to the rotator.
@@ -5,14 +5,17 @@ package logmon
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/open-wander/wander/ci"
 	"github.com/open-wander/wander/client/lib/fifo"
+	"github.com/open-wander/wander/client/logmon/logging"
 	"github.com/open-wander/wander/helper/testlog"
 	"github.com/open-wander/wander/helper/uuid"
 	"github.com/open-wander/wander/testutil"
@@ -307,3 +310,80 @@ func TestLogmon_NewError(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, w)
 }
+
+func TestLogmon_Start_sinks(t *testing.T) {
+	ci.Parallel(t)
+
+	require := require.New(t)
+	var stdoutFifoPath, stderrFifoPath string
+
+	dir := t.TempDir()
+
+	if runtime.GOOS == "windows" {
+		stdoutFifoPath = "//./pipe/test-sinks.stdout"
+		stderrFifoPath = "//./pipe/test-sinks.stderr"
+	} else {
+		stdoutFifoPath = filepath.Join(dir, "stdout.fifo")
+		stderrFifoPath = filepath.Join(dir, "stderr.fifo")
+	}
+
+	cfg := &LogConfig{
+		LogDir:        dir,
+		StdoutLogFile: "stdout",
+		StdoutFifo:    stdoutFifoPath,
+		StderrLogFile: "stderr",
+		StderrFifo:    stderrFifoPath,
+		MaxFiles:      2,
+		MaxFileSizeMB: 1,
+		Sinks: []*logging.SinkConfig{
+			{Type: logging.SinkTypeFile, Path: "task.json"},
+		},
+		JobID:    "example",
+		TaskName: "task",
+	}
+
+	lm := NewLogMon(testlog.HCLogger(t))
+	require.NoError(lm.Start(cfg))
+
+	stdout, err := fifo.OpenWriter(stdoutFifoPath)
+	require.NoError(err)
+	stderr, err := fifo.OpenWriter(stderrFifoPath)
+	require.NoError(err)
+
+	_, err = stdout.Write([]byte("hello\npartial"))
+	require.NoError(err)
+	_, err = stderr.Write([]byte("failed\n"))
+	require.NoError(err)
+
+	// The rotated files are still written
+	testutil.WaitForResult(func() (bool, error) {
+		raw, err := os.ReadFile(filepath.Join(dir, "stdout.0"))
+		if err != nil {
+			return false, err
+		}
+		return string(raw) == "hello\npartial", fmt.Errorf("unexpected stdout %q", raw)
+	}, func(err error) {
+		require.NoError(err)
+	})
+
+	// Stopping logmon flushes the partial line and the sinks
+	require.NoError(stdout.Close())
+	require.NoError(stderr.Close())
+	require.NoError(lm.Stop())
+
+	raw, err := os.ReadFile(filepath.Join(dir, "task.json.0"))
+	require.NoError(err)
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(string(raw)), "\n") {
+		var entry map[string]string
+		require.NoError(json.Unmarshal([]byte(line), &entry))
+		require.Equal("example", entry["job_id"])
+		lines = append(lines, entry["stream"]+": "+entry["message"])
+	}
+	require.ElementsMatch([]string{
+		"stdout: hello",
+		"stdout: partial",
+		"stderr: failed",
+	}, lines)
+}
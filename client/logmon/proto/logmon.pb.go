@@ -25,16 +25,22 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type StartRequest struct {
-	LogDir               string   `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
-	StdoutFileName       string   `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
-	StderrFileName       string   `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
-	MaxFiles             uint32   `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
-	MaxFileSizeMb        uint32   `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
-	StdoutFifo           string   `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
-	StderrFifo           string   `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	LogDir               string     `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
+	StdoutFileName       string     `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
+	StderrFileName       string     `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
+	MaxFiles             uint32     `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
+	MaxFileSizeMb        uint32     `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
+	StdoutFifo           string     `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
+	StderrFifo           string     `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
+	Sinks                []*LogSink `protobuf:"bytes,8,rep,name=sinks,proto3" json:"sinks,omitempty"`
+	AllocId              string     `protobuf:"bytes,9,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
+	Namespace            string     `protobuf:"bytes,10,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	JobId                string     `protobuf:"bytes,11,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	TaskGroup            string     `protobuf:"bytes,12,opt,name=task_group,json=taskGroup,proto3" json:"task_group,omitempty"`
+	TaskName             string     `protobuf:"bytes,13,opt,name=task_name,json=taskName,proto3" json:"task_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *StartRequest) Reset()         { *m = StartRequest{} }
@@ -111,6 +117,135 @@ func (m *StartRequest) GetStderrFifo() string {
 	return ""
 }
 
+func (m *StartRequest) GetSinks() []*LogSink {
+	if m != nil {
+		return m.Sinks
+	}
+	return nil
+}
+
+func (m *StartRequest) GetAllocId() string {
+	if m != nil {
+		return m.AllocId
+	}
+	return ""
+}
+
+func (m *StartRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *StartRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *StartRequest) GetTaskGroup() string {
+	if m != nil {
+		return m.TaskGroup
+	}
+	return ""
+}
+
+func (m *StartRequest) GetTaskName() string {
+	if m != nil {
+		return m.TaskName
+	}
+	return ""
+}
+
+type LogSink struct {
+	Type                 string            `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Address              string            `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Path                 string            `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Facility             string            `protobuf:"bytes,4,opt,name=facility,proto3" json:"facility,omitempty"`
+	Tag                  string            `protobuf:"bytes,5,opt,name=tag,proto3" json:"tag,omitempty"`
+	Headers              map[string]string `protobuf:"bytes,6,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	BufferSize           uint32            `protobuf:"varint,7,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *LogSink) Reset()         { *m = LogSink{} }
+func (m *LogSink) String() string { return proto.CompactTextString(m) }
+func (*LogSink) ProtoMessage()    {}
+func (*LogSink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{1}
+}
+
+func (m *LogSink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSink.Unmarshal(m, b)
+}
+func (m *LogSink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSink.Marshal(b, m, deterministic)
+}
+func (m *LogSink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSink.Merge(m, src)
+}
+func (m *LogSink) XXX_Size() int {
+	return xxx_messageInfo_LogSink.Size(m)
+}
+func (m *LogSink) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSink proto.InternalMessageInfo
+
+func (m *LogSink) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *LogSink) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *LogSink) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *LogSink) GetFacility() string {
+	if m != nil {
+		return m.Facility
+	}
+	return ""
+}
+
+func (m *LogSink) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *LogSink) GetHeaders() map[string]string {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
+func (m *LogSink) GetBufferSize() uint32 {
+	if m != nil {
+		return m.BufferSize
+	}
+	return 0
+}
+
 type StartResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -121,7 +256,7 @@ func (m *StartResponse) Reset()         { *m = StartResponse{} }
 func (m *StartResponse) String() string { return proto.CompactTextString(m) }
 func (*StartResponse) ProtoMessage()    {}
 func (*StartResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_be72d5e24d2ecba6, []int{1}
+	return fileDescriptor_be72d5e24d2ecba6, []int{2}
 }
 
 func (m *StartResponse) XXX_Unmarshal(b []byte) error {
@@ -152,7 +287,7 @@ func (m *StopRequest) Reset()         { *m = StopRequest{} }
 func (m *StopRequest) String() string { return proto.CompactTextString(m) }
 func (*StopRequest) ProtoMessage()    {}
 func (*StopRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_be72d5e24d2ecba6, []int{2}
+	return fileDescriptor_be72d5e24d2ecba6, []int{3}
 }
 
 func (m *StopRequest) XXX_Unmarshal(b []byte) error {
@@ -183,7 +318,7 @@ func (m *StopResponse) Reset()         { *m = StopResponse{} }
 func (m *StopResponse) String() string { return proto.CompactTextString(m) }
 func (*StopResponse) ProtoMessage()    {}
 func (*StopResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_be72d5e24d2ecba6, []int{3}
+	return fileDescriptor_be72d5e24d2ecba6, []int{4}
 }
 
 func (m *StopResponse) XXX_Unmarshal(b []byte) error {
@@ -206,6 +341,8 @@ var xxx_messageInfo_StopResponse proto.InternalMessageInfo
 
 func init() {
 	proto.RegisterType((*StartRequest)(nil), "hashicorp.nomad.client.logmon.proto.StartRequest")
+	proto.RegisterType((*LogSink)(nil), "hashicorp.nomad.client.logmon.proto.LogSink")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.client.logmon.proto.LogSink.HeadersEntry")
 	proto.RegisterType((*StartResponse)(nil), "hashicorp.nomad.client.logmon.proto.StartResponse")
 	proto.RegisterType((*StopRequest)(nil), "hashicorp.nomad.client.logmon.proto.StopRequest")
 	proto.RegisterType((*StopResponse)(nil), "hashicorp.nomad.client.logmon.proto.StopResponse")
@@ -216,27 +353,42 @@ func init() {
 }
 
 var fileDescriptor_be72d5e24d2ecba6 = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x90, 0x31, 0x6f, 0xc2, 0x30,
-	0x10, 0x85, 0x1b, 0x0a, 0xa1, 0x1c, 0x0d, 0x45, 0x5e, 0x1a, 0xd1, 0xa1, 0x28, 0x1d, 0xca, 0x14,
-	0x0a, 0xfd, 0x07, 0x55, 0xd5, 0xa9, 0x74, 0x80, 0xad, 0x4b, 0x64, 0xc0, 0x09, 0x96, 0x62, 0x5f,
-	0x6a, 0x1b, 0x09, 0xb1, 0xf6, 0xd7, 0xf6, 0x5f, 0x54, 0x71, 0x4c, 0x94, 0x11, 0x26, 0xeb, 0xee,
-	0x7d, 0x4f, 0xf7, 0xfc, 0x60, 0xbc, 0xc9, 0x39, 0x93, 0x66, 0x9a, 0x63, 0x26, 0x50, 0x4e, 0x0b,
-	0x85, 0x06, 0xdd, 0x10, 0xdb, 0x81, 0x3c, 0xed, 0xa8, 0xde, 0xf1, 0x0d, 0xaa, 0x22, 0x96, 0x28,
-	0xe8, 0x36, 0xae, 0x1c, 0x71, 0x13, 0x8a, 0x7e, 0x5b, 0x70, 0xbb, 0x32, 0x54, 0x99, 0x25, 0xfb,
-	0xd9, 0x33, 0x6d, 0xc8, 0x3d, 0x74, 0x73, 0xcc, 0x92, 0x2d, 0x57, 0xa1, 0x37, 0xf6, 0x26, 0xbd,
-	0xa5, 0x9f, 0x63, 0xf6, 0xce, 0x15, 0x99, 0xc0, 0x50, 0x9b, 0x2d, 0xee, 0x4d, 0x92, 0xf2, 0x9c,
-	0x25, 0x92, 0x0a, 0x16, 0xb6, 0x2c, 0x31, 0xa8, 0xf6, 0x1f, 0x3c, 0x67, 0x5f, 0x54, 0x30, 0x47,
-	0x32, 0xa5, 0x1a, 0xe4, 0x75, 0x4d, 0x32, 0xa5, 0x6a, 0xf2, 0x01, 0x7a, 0x82, 0x1e, 0x2c, 0xa6,
-	0xc3, 0xf6, 0xd8, 0x9b, 0x04, 0xcb, 0x1b, 0x41, 0x0f, 0xa5, 0xae, 0xc9, 0x33, 0x0c, 0x4f, 0x62,
-	0xa2, 0xf9, 0x91, 0x25, 0x62, 0x1d, 0x76, 0x2c, 0x13, 0x38, 0x66, 0xc5, 0x8f, 0x6c, 0xb1, 0x26,
-	0x8f, 0xd0, 0xaf, 0x93, 0xa5, 0x18, 0xfa, 0xf6, 0x14, 0x9c, 0x42, 0xa5, 0xe8, 0x80, 0x2a, 0x50,
-	0x8a, 0x61, 0xb7, 0x06, 0x6c, 0x96, 0x14, 0xa3, 0x3b, 0x08, 0x5c, 0x09, 0xba, 0x40, 0xa9, 0x59,
-	0x14, 0x40, 0x7f, 0x65, 0xb0, 0x70, 0xa5, 0x44, 0x83, 0xb2, 0xa4, 0x72, 0xac, 0xe4, 0xf9, 0x9f,
-	0x07, 0xfe, 0x27, 0x66, 0x0b, 0x94, 0xa4, 0x80, 0x8e, 0xb5, 0x92, 0x59, 0x7c, 0x46, 0xdf, 0x71,
-	0xb3, 0xeb, 0xd1, 0xfc, 0x12, 0x8b, 0x4b, 0x76, 0x45, 0x04, 0xb4, 0xcb, 0x30, 0xe4, 0xe5, 0x4c,
-	0x77, 0xfd, 0x8d, 0xd1, 0xec, 0x02, 0xc7, 0xe9, 0xdc, 0x5b, 0xf7, 0xbb, 0x63, 0xf7, 0x6b, 0xdf,
-	0x3e, 0xaf, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd8, 0xb4, 0x87, 0xfc, 0x7a, 0x02, 0x00, 0x00,
+	// 555 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0xcd, 0x72, 0xd3, 0x3c,
+	0x14, 0xfd, 0xd2, 0xd4, 0x76, 0x7c, 0x13, 0xf7, 0xcb, 0x68, 0x60, 0x10, 0x01, 0x86, 0x4c, 0x58,
+	0x90, 0x05, 0xe3, 0xd2, 0xb0, 0x81, 0x2e, 0x3b, 0xfc, 0x75, 0xa6, 0x65, 0xe1, 0xec, 0xd8, 0x78,
+	0xe4, 0x58, 0x76, 0xd4, 0xd8, 0x96, 0x91, 0x14, 0xa6, 0xe9, 0x2b, 0xf1, 0x38, 0x3c, 0x05, 0x6f,
+	0xc1, 0x48, 0x96, 0x4d, 0x96, 0xc9, 0xca, 0xba, 0xf7, 0x9e, 0x63, 0x1d, 0x9d, 0x23, 0xc1, 0x74,
+	0x55, 0x30, 0x5a, 0xa9, 0xf3, 0x82, 0xe7, 0x25, 0xaf, 0xce, 0x6b, 0xc1, 0x15, 0xb7, 0x45, 0x68,
+	0x0a, 0xf4, 0x6a, 0x4d, 0xe4, 0x9a, 0xad, 0xb8, 0xa8, 0xc3, 0x8a, 0x97, 0x24, 0x0d, 0x1b, 0x46,
+	0xb8, 0x0f, 0x9a, 0xfd, 0xee, 0xc3, 0x68, 0xa9, 0x88, 0x50, 0x11, 0xfd, 0xb1, 0xa5, 0x52, 0xa1,
+	0x27, 0xe0, 0x15, 0x3c, 0x8f, 0x53, 0x26, 0x70, 0x6f, 0xda, 0x9b, 0xfb, 0x91, 0x5b, 0xf0, 0xfc,
+	0x23, 0x13, 0x68, 0x0e, 0x63, 0xa9, 0x52, 0xbe, 0x55, 0x71, 0xc6, 0x0a, 0x1a, 0x57, 0xa4, 0xa4,
+	0xf8, 0xc4, 0x20, 0xce, 0x9a, 0xfe, 0x67, 0x56, 0xd0, 0x6f, 0xa4, 0xa4, 0x16, 0x49, 0x85, 0xd8,
+	0x43, 0xf6, 0x3b, 0x24, 0x15, 0xa2, 0x43, 0x3e, 0x03, 0xbf, 0x24, 0xf7, 0x06, 0x26, 0xf1, 0xe9,
+	0xb4, 0x37, 0x0f, 0xa2, 0x41, 0x49, 0xee, 0xf5, 0x5c, 0xa2, 0xd7, 0x30, 0x6e, 0x87, 0xb1, 0x64,
+	0x0f, 0x34, 0x2e, 0x13, 0xec, 0x18, 0x4c, 0x60, 0x31, 0x4b, 0xf6, 0x40, 0x6f, 0x13, 0xf4, 0x12,
+	0x86, 0x9d, 0xb2, 0x8c, 0x63, 0xd7, 0x6c, 0x05, 0xad, 0xa8, 0x8c, 0x5b, 0x40, 0x23, 0x28, 0xe3,
+	0xd8, 0xeb, 0x00, 0x46, 0x4b, 0xc6, 0xd1, 0x15, 0x38, 0x92, 0x55, 0x1b, 0x89, 0x07, 0xd3, 0xfe,
+	0x7c, 0xb8, 0x78, 0x13, 0x1e, 0x60, 0x5d, 0x78, 0xc3, 0xf3, 0x25, 0xab, 0x36, 0x51, 0x43, 0x45,
+	0x4f, 0x61, 0x40, 0x8a, 0x82, 0xaf, 0x62, 0x96, 0x62, 0xdf, 0xec, 0xe0, 0x99, 0xfa, 0x3a, 0x45,
+	0xcf, 0xc1, 0xd7, 0x26, 0xc8, 0x9a, 0xac, 0x28, 0x06, 0x33, 0xfb, 0xd7, 0x40, 0x8f, 0xc1, 0xbd,
+	0xe3, 0x89, 0xa6, 0x0d, 0xcd, 0xc8, 0xb9, 0xe3, 0xc9, 0x75, 0x8a, 0x5e, 0x00, 0x28, 0x22, 0x37,
+	0x71, 0x2e, 0xf8, 0xb6, 0xc6, 0xa3, 0x86, 0xa5, 0x3b, 0x5f, 0x74, 0x43, 0x5b, 0x67, 0xc6, 0xc6,
+	0xdd, 0xc0, 0x4c, 0x07, 0xba, 0xa1, 0x7d, 0x9d, 0xfd, 0x3a, 0x01, 0xcf, 0xca, 0x43, 0x08, 0x4e,
+	0xd5, 0xae, 0xa6, 0x36, 0x4d, 0xb3, 0x46, 0x18, 0x3c, 0x92, 0xa6, 0x82, 0x4a, 0x69, 0x23, 0x6c,
+	0x4b, 0x8d, 0xae, 0x89, 0x5a, 0xdb, 0xbc, 0xcc, 0x1a, 0x4d, 0x60, 0x90, 0x91, 0x15, 0x2b, 0x98,
+	0xda, 0x99, 0x90, 0xfc, 0xa8, 0xab, 0xd1, 0x18, 0xfa, 0x8a, 0xe4, 0x26, 0x17, 0x3f, 0xd2, 0x4b,
+	0xb4, 0x04, 0x6f, 0x4d, 0x49, 0x4a, 0x85, 0xc4, 0xae, 0x71, 0xf3, 0xc3, 0x31, 0x6e, 0x86, 0x5f,
+	0x1b, 0xee, 0xa7, 0x4a, 0x89, 0x5d, 0xd4, 0xfe, 0x49, 0x27, 0x98, 0x6c, 0xb3, 0x8c, 0x0a, 0x73,
+	0x13, 0x4c, 0x82, 0x41, 0x04, 0x4d, 0x4b, 0xdf, 0x82, 0xc9, 0x25, 0x8c, 0xf6, 0x99, 0x5a, 0xd7,
+	0x86, 0xee, 0xec, 0xa1, 0xf5, 0x12, 0x3d, 0x02, 0xe7, 0x27, 0x29, 0xb6, 0xed, 0xa5, 0x6d, 0x8a,
+	0xcb, 0x93, 0xf7, 0xbd, 0xd9, 0xff, 0x10, 0xd8, 0x27, 0x20, 0x6b, 0x5e, 0x49, 0x3a, 0x0b, 0x60,
+	0xb8, 0x54, 0xbc, 0xb6, 0x4f, 0x62, 0x76, 0x06, 0xa3, 0xa6, 0x6c, 0xc6, 0x8b, 0x3f, 0x3d, 0x70,
+	0x6f, 0x78, 0x7e, 0xcb, 0x2b, 0x54, 0x83, 0x63, 0xa8, 0xe8, 0xe2, 0xa0, 0x43, 0xee, 0xbf, 0xb4,
+	0xc9, 0xe2, 0x18, 0x8a, 0x55, 0xf6, 0x1f, 0x2a, 0xe1, 0x54, 0x8b, 0x41, 0x6f, 0x0f, 0x64, 0x77,
+	0xc7, 0x98, 0x5c, 0x1c, 0xc1, 0x68, 0xb7, 0xbb, 0xf2, 0xbe, 0x3b, 0xa6, 0x9f, 0xb8, 0xe6, 0xf3,
+	0xee, 0xef, 0x00, 0xdf, 0xe6, 0x7a, 0x04, 0x78, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/hashicorp/go-plugin"
+	"github.com/open-wander/wander/client/logmon/logging"
 	"github.com/open-wander/wander/client/logmon/proto"
 )
 
@@ -24,6 +25,22 @@ func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*pro
 		MaxFileSizeMB: int(req.MaxFileSizeMb),
 		StdoutFifo:    req.StdoutFifo,
 		StderrFifo:    req.StderrFifo,
+		AllocID:       req.AllocId,
+		Namespace:     req.Namespace,
+		JobID:         req.JobId,
+		TaskGroup:     req.TaskGroup,
+		TaskName:      req.TaskName,
+	}
+	for _, sink := range req.Sinks {
+		cfg.Sinks = append(cfg.Sinks, &logging.SinkConfig{
+			Type:       sink.Type,
+			Address:    sink.Address,
+			Path:       sink.Path,
+			Facility:   sink.Facility,
+			Tag:        sink.Tag,
+			Headers:    sink.Headers,
+			BufferSize: int(sink.BufferSize),
+		})
 	}
 
 	err := s.impl.Start(cfg)
@@ -72,6 +72,73 @@ type AllocFileInfo struct {
 	ContentType string `json:",omitempty"`
 }
 
+// AllocSnapshotManifest describes the files and directories of an allocation
+// migrated to another node. The content of regular files is transferred in
+// chunks of ChunkSize bytes.
+type AllocSnapshotManifest struct {
+	ChunkSize int64
+	Entries   []*AllocSnapshotEntry
+}
+
+// Chunks returns the total number of chunks of the manifest.
+func (m *AllocSnapshotManifest) Chunks() int {
+	n := 0
+	for _, e := range m.Entries {
+		n += e.Chunks(m.ChunkSize)
+	}
+	return n
+}
+
+// Size returns the total size in bytes of the regular files of the manifest.
+func (m *AllocSnapshotManifest) Size() int64 {
+	var size int64
+	for _, e := range m.Entries {
+		size += e.Size
+	}
+	return size
+}
+
+// AllocSnapshotEntry is a file, directory or symlink of an allocation
+// snapshot. Name is relative to the alloc dir and Typeflag is the tar type
+// of the entry.
+type AllocSnapshotEntry struct {
+	Name     string
+	Typeflag byte
+	Mode     int64
+	Uid      int
+	Gid      int
+	Size     int64
+	ModTime  time.Time
+	Linkname string `json:",omitempty"`
+}
+
+// Chunks returns the number of chunks of the content of the entry. Empty
+// files have no chunk.
+func (e *AllocSnapshotEntry) Chunks(chunkSize int64) int {
+	if e.Size <= 0 || chunkSize <= 0 {
+		return 0
+	}
+	return int((e.Size + chunkSize - 1) / chunkSize)
+}
+
+// MigrateProgress is the progress of the migration of the data of a previous
+// allocation from another node.
+type MigrateProgress struct {
+	// PrevAllocID is the ID of the allocation the data is migrated from.
+	PrevAllocID string
+
+	// Chunks and Bytes are the number of chunks and bytes transferred so
+	// far, out of TotalChunks and TotalBytes.
+	Chunks      int
+	TotalChunks int
+	Bytes       int64
+	TotalBytes  int64
+
+	// Resumed is true if the migration resumed a transfer interrupted
+	// before.
+	Resumed bool
+}
+
 // FsListRequest is used to list an allocation's directory.
 type FsListRequest struct {
 	// AllocID is the allocation to list from
@@ -110,6 +177,58 @@ type FsStatResponse struct {
 	structs.QueryMeta
 }
 
+// FsUploadRequest is the initial request for uploading into an allocation's
+// directory. After the request, the payloads of the StreamErrWrapper frames
+// sent to the client are the content to upload, and a frame with an empty
+// payload ends the upload. The client replies with an empty frame once the
+// content has been written.
+type FsUploadRequest struct {
+	// AllocID is the allocation to upload to
+	AllocID string
+
+	// Path is the path of the file to write, or of the directory to extract
+	// the archive into
+	Path string
+
+	// Archive is whether the content is a tar archive, optionally gzip
+	// compressed, to extract into the directory at Path
+	Archive bool
+
+	// FileMode is the permissions of the file written when the content
+	// isn't an archive
+	FileMode uint32
+
+	structs.QueryOptions
+}
+
+// FsRemoveRequest is used to remove a file or directory
+type FsRemoveRequest struct {
+	// AllocID is the allocation to remove the file from
+	AllocID string
+
+	// Path is the path of the file or directory to remove
+	Path string
+
+	structs.QueryOptions
+}
+
+// FsRemoveResponse is used to return the result of removing a file
+type FsRemoveResponse struct {
+	structs.QueryMeta
+}
+
+// FsArchiveRequest is the initial request for streaming a gzip compressed tar
+// archive of a file or directory of an allocation.
+type FsArchiveRequest struct {
+	// AllocID is the allocation to archive from
+	AllocID string
+
+	// Path is the path of the file or directory to archive
+	Path string
+
+	structs.QueryOptions
+}
+
 // FsStreamRequest is the initial request for streaming the content of a file.
 type FsStreamRequest struct {
 	// AllocID is the allocation to stream logs from
@@ -190,6 +309,30 @@ type AllocExecRequest struct {
 	structs.QueryOptions
 }
 
+// AllocPortForwardRequest is the initial request for forwarding a TCP
+// connection to a port of an Alloc. After the request, the payloads of the
+// StreamErrWrapper frames sent in both directions are the bytes of the
+// connection.
+type AllocPortForwardRequest struct {
+	// AllocID is the allocation to connect to
+	AllocID string
+
+	// Port is the label or number of the port to connect to
+	Port string
+
+	structs.QueryOptions
+}
+
+// ExecSessionRecordingRequest is the initial request for streaming the
+// recording of an exec session. After the request, the payloads of the
+// StreamErrWrapper frames sent by the client are the bytes of the recording.
+type ExecSessionRecordingRequest struct {
+	// SessionID is the exec session to stream the recording of
+	SessionID string
+
+	structs.QueryOptions
+}
+
 // AllocChecksRequest is used to request the latest nomad service discovery
 // check status information of a given allocation.
 type AllocChecksRequest struct {
@@ -128,6 +128,10 @@ const (
 
 	// WorkloadToken is the environment variable for passing the Nomad Workload Identity token
 	WorkloadToken = "NOMAD_TOKEN"
+
+	// WorkloadTokenPrefix is the prefix of the environment variables for
+	// passing named Nomad Workload Identity tokens.
+	WorkloadTokenPrefix = "NOMAD_TOKEN_"
 )
 
 // The node values that can be interpreted.
@@ -434,6 +438,10 @@ type Builder struct {
 	jobName             string
 	jobParentID         string
 
+	// workloadTokens are the named workload identity tokens to inject,
+	// keyed by identity name
+	workloadTokens map[string]string
+
 	// otherPorts for tasks in the same alloc
 	otherPorts map[string]string
 
@@ -579,6 +587,9 @@ func (b *Builder) buildEnv(allocDir, localDir, secretsDir string,
 	if b.injectWorkloadToken && b.workloadToken != "" {
 		envMap[WorkloadToken] = b.workloadToken
 	}
+	for name, token := range b.workloadTokens {
+		envMap[WorkloadTokenPrefix+name] = token
+	}
 
 	// Copy and interpolate task meta
 	for k, v := range b.taskMeta {
@@ -1039,6 +1050,22 @@ func (b *Builder) SetWorkloadToken(token string, inject bool) *Builder {
 	return b
 }
 
+// SetNamedWorkloadToken sets the token of a named workload identity, which is
+// injected as NOMAD_TOKEN_<name> if inject is true.
+func (b *Builder) SetNamedWorkloadToken(name, token string, inject bool) *Builder {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !inject || token == "" {
+		delete(b.workloadTokens, name)
+		return b
+	}
+	if b.workloadTokens == nil {
+		b.workloadTokens = make(map[string]string)
+	}
+	b.workloadTokens[name] = token
+	return b
+}
+
 // addPort keys and values for other tasks to an env var map
 func addPort(m map[string]string, taskName, ip, portLabel string, port int) {
 	key := fmt.Sprintf("%s%s_%s", AddrPrefix, taskName, portLabel)
@@ -535,6 +535,26 @@ func TestEnvironment_VaultToken(t *testing.T) {
 	}
 }
 
+func TestEnvironment_NamedWorkloadToken(t *testing.T) {
+	ci.Parallel(t)
+
+	n := mock.Node()
+	a := mock.Alloc()
+	env := NewBuilder(n, a, a.Job.TaskGroups[0].Tasks[0], "global")
+	env.SetWorkloadToken("default-token", true)
+	env.SetNamedWorkloadToken("consul", "consul-token", true)
+	env.SetNamedWorkloadToken("vault", "vault-token", false)
+
+	act := env.Build().All()
+	require.Equal(t, "default-token", act[WorkloadToken])
+	require.Equal(t, "consul-token", act["NOMAD_TOKEN_consul"])
+	require.NotContains(t, act, "NOMAD_TOKEN_vault")
+
+	// Renewed tokens replace the previous ones.
+	act = env.SetNamedWorkloadToken("consul", "renewed-token", true).Build().All()
+	require.Equal(t, "renewed-token", act["NOMAD_TOKEN_consul"])
+}
+
 func TestEnvironment_Envvars(t *testing.T) {
 	ci.Parallel(t)
 
@@ -5,8 +5,11 @@ package vaultclient
 
 import (
 	"container/heap"
+	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -38,6 +41,10 @@ type VaultClient interface {
 	// returned.
 	DeriveToken(*structs.Allocation, []string) (map[string]string, error)
 
+	// DeriveTokenWithJWT logs in to Vault with a workload identity using the
+	// JWT auth method and returns the Vault token along with its lease.
+	DeriveTokenWithJWT(context.Context, JWTLoginRequest) (*JWTLoginResponse, error)
+
 	// GetConsulACL fetches the Consul ACL token required for the task
 	GetConsulACL(string, string) (*vaultapi.Secret, error)
 
@@ -50,6 +57,32 @@ type VaultClient interface {
 	StopRenewToken(string) error
 }
 
+// JWTLoginRequest is the request to log in to Vault with a workload identity.
+type JWTLoginRequest struct {
+	// JWT is the signed workload identity of the task.
+	JWT string
+
+	// Role is the role of the JWT auth method to log in with. If empty, the
+	// default role of the auth method is used.
+	Role string
+
+	// Namespace is the Vault namespace to log in to. If empty, the namespace
+	// of the client configuration is used.
+	Namespace string
+}
+
+// JWTLoginResponse is the Vault token returned by logging in to Vault with a
+// workload identity.
+type JWTLoginResponse struct {
+	Token string
+
+	// Renewable is true if the token can be renewed.
+	Renewable bool
+
+	// LeaseDuration is the time to live of the token.
+	LeaseDuration time.Duration
+}
+
 // Implementation of VaultClient interface to interact with vault and perform
 // token and lease renewals periodically.
 type vaultClient struct {
@@ -256,6 +289,61 @@ func (c *vaultClient) DeriveToken(alloc *structs.Allocation, taskNames []string)
 	return tokens, nil
 }
 
+// DeriveTokenWithJWT logs in to Vault with the workload identity of a task
+// using the JWT auth method mounted at the configured path. Errors returned by
+// Vault because the login is invalid are not recoverable.
+func (c *vaultClient) DeriveTokenWithJWT(ctx context.Context, req JWTLoginRequest) (*JWTLoginResponse, error) {
+	if !c.config.IsEnabled() {
+		return nil, fmt.Errorf("vault client not enabled")
+	}
+	if !c.isRunning() {
+		return nil, fmt.Errorf("vault client is not running")
+	}
+	if req.JWT == "" {
+		return nil, fmt.Errorf("missing workload identity")
+	}
+
+	namespace := c.config.Namespace
+	if req.Namespace != "" {
+		namespace = req.Namespace
+	}
+
+	// Use a copy of the client so the token and namespace of concurrent
+	// requests don't interfere with each other.
+	c.lock.RLock()
+	client := c.client.WithNamespace(namespace)
+	c.lock.RUnlock()
+	client.ClearToken()
+
+	data := map[string]interface{}{
+		"jwt": req.JWT,
+	}
+	if req.Role != "" {
+		data["role"] = req.Role
+	}
+
+	path := fmt.Sprintf("auth/%s/login", c.config.JWTAuthPath())
+	secret, err := client.Logical().WriteWithContext(ctx, path, data)
+	if err != nil {
+		var respErr *vaultapi.ResponseError
+		if errors.As(err, &respErr) && respErr.StatusCode >= 400 && respErr.StatusCode < 500 &&
+			respErr.StatusCode != http.StatusTooManyRequests {
+			return nil, fmt.Errorf("failed to log in to Vault with workload identity: %w", err)
+		}
+		return nil, structs.NewRecoverableError(
+			fmt.Errorf("failed to log in to Vault with workload identity: %w", err), true)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return nil, fmt.Errorf("failed to log in to Vault with workload identity: no token returned")
+	}
+
+	return &JWTLoginResponse{
+		Token:         secret.Auth.ClientToken,
+		Renewable:     secret.Auth.Renewable,
+		LeaseDuration: time.Duration(secret.Auth.LeaseDuration) * time.Second,
+	}, nil
+}
+
 // GetConsulACL creates a vault API client and reads from vault a consul ACL
 // token used by the task.
 func (c *vaultClient) GetConsulACL(token, path string) (*vaultapi.Secret, error) {
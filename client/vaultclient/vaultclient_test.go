@@ -4,6 +4,10 @@
 package vaultclient
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -14,6 +18,7 @@ import (
 	"github.com/open-wander/wander/helper/testlog"
 	"github.com/open-wander/wander/helper/useragent"
 	"github.com/open-wander/wander/nomad/structs"
+	sconfig "github.com/open-wander/wander/nomad/structs/config"
 	"github.com/open-wander/wander/testutil"
 	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/shoenig/test/must"
@@ -373,3 +378,73 @@ func TestVaultClient_SetUserAgent(t *testing.T) {
 	ua := c.client.Headers().Get("User-Agent")
 	must.Eq(t, useragent.String(), ua)
 }
+
+func TestVaultClient_DeriveTokenWithJWT(t *testing.T) {
+	ci.Parallel(t)
+
+	// Stub the login endpoint of the Vault JWT auth method
+	var gotNamespace string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/jwt-test/login" || r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		gotNamespace = r.Header.Get(vaultapi.NamespaceHeaderName)
+		gotBody = nil
+		must.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+
+		switch gotBody["jwt"] {
+		case "invalid":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors":["error validating token"]}`))
+		case "unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"errors":["Vault is sealed"]}`))
+		default:
+			w.Write([]byte(`{"auth":{"client_token":"s.1234","renewable":true,"lease_duration":3600}}`))
+		}
+	}))
+	defer srv.Close()
+
+	conf := sconfig.DefaultVaultConfig()
+	conf.Enabled = pointer.Of(true)
+	conf.Addr = srv.URL
+	conf.Namespace = "default-ns"
+	conf.JWTAuthBackendPath = "jwt-test"
+	c, err := NewVaultClient(conf, testlog.HCLogger(t), nil)
+	must.NoError(t, err)
+
+	ctx := context.Background()
+	req := JWTLoginRequest{JWT: "valid", Role: "nomad-workloads"}
+	_, err = c.DeriveTokenWithJWT(ctx, req)
+	must.ErrorContains(t, err, "not running")
+
+	c.Start()
+	defer c.Stop()
+
+	resp, err := c.DeriveTokenWithJWT(ctx, req)
+	must.NoError(t, err)
+	must.Eq(t, "s.1234", resp.Token)
+	must.True(t, resp.Renewable)
+	must.Eq(t, time.Hour, resp.LeaseDuration)
+	must.Eq(t, "default-ns", gotNamespace)
+	must.Eq(t, map[string]any{"jwt": "valid", "role": "nomad-workloads"}, gotBody)
+
+	// The task namespace overrides the client namespace and the role is
+	// optional
+	_, err = c.DeriveTokenWithJWT(ctx, JWTLoginRequest{JWT: "valid", Namespace: "task-ns"})
+	must.NoError(t, err)
+	must.Eq(t, "task-ns", gotNamespace)
+	must.Eq(t, map[string]any{"jwt": "valid"}, gotBody)
+
+	// Invalid logins can't be recovered from
+	_, err = c.DeriveTokenWithJWT(ctx, JWTLoginRequest{JWT: "invalid"})
+	must.ErrorContains(t, err, "error validating token")
+	must.False(t, structs.IsRecoverable(err))
+
+	// Vault being unavailable is recoverable
+	_, err = c.DeriveTokenWithJWT(ctx, JWTLoginRequest{JWT: "unavailable"})
+	must.ErrorContains(t, err, "Vault is sealed")
+	must.True(t, structs.IsRecoverable(err))
+}
@@ -4,6 +4,7 @@
 package vaultclient
 
 import (
+	"context"
 	"sync"
 
 	"github.com/open-wander/wander/helper/uuid"
@@ -34,6 +35,14 @@ type MockVaultClient struct {
 	// a token is generated and returned
 	DeriveTokenFn func(a *structs.Allocation, tasks []string) (map[string]string, error)
 
+	// DeriveTokenWithJWTFn allows the caller to control the
+	// DeriveTokenWithJWT function. If not set, a renewable token is generated
+	// and returned.
+	DeriveTokenWithJWTFn func(ctx context.Context, req JWTLoginRequest) (*JWTLoginResponse, error)
+
+	// jwtLogins tracks the JWT login requests
+	jwtLogins []JWTLoginRequest
+
 	mu sync.Mutex
 }
 
@@ -62,6 +71,24 @@ func (vc *MockVaultClient) DeriveToken(a *structs.Allocation, tasks []string) (m
 	return tokens, nil
 }
 
+func (vc *MockVaultClient) DeriveTokenWithJWT(ctx context.Context, req JWTLoginRequest) (*JWTLoginResponse, error) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+
+	vc.jwtLogins = append(vc.jwtLogins, req)
+	if vc.DeriveTokenWithJWTFn != nil {
+		return vc.DeriveTokenWithJWTFn(ctx, req)
+	}
+	return &JWTLoginResponse{Token: uuid.Generate(), Renewable: true}, nil
+}
+
+// JWTLogins tracks the JWT login requests
+func (vc *MockVaultClient) JWTLogins() []JWTLoginRequest {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	return vc.jwtLogins
+}
+
 func (vc *MockVaultClient) SetDeriveTokenError(allocID string, tasks []string, err error) {
 	vc.mu.Lock()
 	defer vc.mu.Unlock()
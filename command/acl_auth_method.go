@@ -105,6 +105,31 @@ func formatAuthMethodConfig(config *api.ACLAuthMethodConfig) string {
 		fmt.Sprintf("Claim mappings|%s", strings.Join(formatMap(config.ClaimMappings), "; ")),
 		fmt.Sprintf("List claim mappings|%s", strings.Join(formatMap(config.ListClaimMappings), "; ")),
 	}
+	if len(config.LDAPURLs) != 0 {
+		out = append(out,
+			fmt.Sprintf("LDAP URLs|%s", strings.Join(config.LDAPURLs, ",")),
+			fmt.Sprintf("LDAP StartTLS|%t", config.LDAPStartTLS),
+			fmt.Sprintf("LDAP insecure TLS|%t", config.LDAPInsecureTLS),
+			fmt.Sprintf("LDAP CA cert|%s", config.LDAPCACert),
+			fmt.Sprintf("LDAP bind DN|%s", config.LDAPBindDN),
+			fmt.Sprintf("LDAP user DN|%s", config.LDAPUserDN),
+			fmt.Sprintf("LDAP user attribute|%s", config.LDAPUserAttr),
+			fmt.Sprintf("LDAP user filter|%s", config.LDAPUserFilter),
+			fmt.Sprintf("LDAP group DN|%s", config.LDAPGroupDN),
+			fmt.Sprintf("LDAP group filter|%s", config.LDAPGroupFilter),
+			fmt.Sprintf("LDAP group attribute|%s", config.LDAPGroupAttr),
+		)
+	}
+	if len(config.TLSCertCACerts) != 0 {
+		out = append(out,
+			fmt.Sprintf("TLS cert CA certs|%s", strings.Join(config.TLSCertCACerts, ",")),
+			fmt.Sprintf("TLS cert allowed common names|%s", strings.Join(config.TLSCertAllowedCommonNames, ",")),
+			fmt.Sprintf("TLS cert allowed DNS SANs|%s", strings.Join(config.TLSCertAllowedDNSSANs, ",")),
+			fmt.Sprintf("TLS cert allowed email SANs|%s", strings.Join(config.TLSCertAllowedEmailSANs, ",")),
+			fmt.Sprintf("TLS cert allowed URI SANs|%s", strings.Join(config.TLSCertAllowedURISANs, ",")),
+			fmt.Sprintf("TLS cert required extensions|%s", strings.Join(config.TLSCertRequiredExtensions, ",")),
+		)
+	}
 	return formatKV(out)
 }
 
@@ -178,6 +178,7 @@ func outputACLToken(ui cli.Ui, token *api.ACLToken) {
 		fmt.Sprintf("Global|%v", token.Global),
 		fmt.Sprintf("Create Time|%v", token.CreateTime),
 		fmt.Sprintf("Expiry Time |%s", expiryTimeString(token.ExpirationTime)),
+		fmt.Sprintf("Last Used|%s", lastUsedString(token.LastUsedTime, token.LastUsedAddress)),
 		fmt.Sprintf("Create Index|%d", token.CreateIndex),
 		fmt.Sprintf("Modify Index|%d", token.ModifyIndex),
 	}
@@ -222,3 +223,14 @@ func expiryTimeString(t *time.Time) string {
 	}
 	return t.String()
 }
+
+// lastUsedString formats when and from where an ACL token was last used.
+func lastUsedString(t *time.Time, address string) string {
+	if t == nil || t.IsZero() {
+		return "<never>"
+	}
+	if address == "" {
+		return t.String()
+	}
+	return fmt.Sprintf("%s from %s", t, address)
+}
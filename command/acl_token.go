@@ -30,6 +30,10 @@ Usage: nomad acl token <subcommand> [options] [args]
 
       $ nomad acl policy info <token_accessor_id>
 
+  Explain the permissions of an ACL token on a namespace:
+
+      $ nomad acl token check -namespace prod <token_accessor_id>
+
   Revoke an ACL token:
 
       $ nomad acl policy delete <token_accessor_id>
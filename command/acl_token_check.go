@@ -0,0 +1,222 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/open-wander/wander/api"
+	"github.com/posener/complete"
+)
+
+type ACLTokenCheckCommand struct {
+	Meta
+}
+
+func (c *ACLTokenCheckCommand) Help() string {
+	helpText := `
+Usage: nomad acl token check [options] [<token_accessor_id>]
+
+  Check is used to explain the effective permissions of an ACL token on a
+  namespace, and optionally a node pool, host volume and variable path within
+  that namespace. For each capability, the output lists whether it is allowed
+  and which policies granted or denied it.
+
+  If no accessor ID is given and no policy is passed with the -policy flag, the
+  currently set ACL token is checked. Checking another token, or simulating a
+  set of policies, requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Check Options:
+
+  -policy=""
+    Simulate a token linked to the named policy instead of checking an existing
+    token. Can be used multiple times.
+
+  -node-pool=""
+    Explain the capabilities on the named node pool.
+
+  -host-volume=""
+    Explain the capabilities on the named host volume.
+
+  -variable=""
+    Explain the capabilities on the variable path within the namespace.
+
+  -json
+    Output the check result in a JSON format.
+
+  -t
+    Format and display the check result using a Go template.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLTokenCheckCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-policy":      complete.PredictAnything,
+			"-node-pool":   complete.PredictAnything,
+			"-host-volume": complete.PredictAnything,
+			"-variable":    complete.PredictAnything,
+			"-json":        complete.PredictNothing,
+			"-t":           complete.PredictAnything,
+		})
+}
+
+func (c *ACLTokenCheckCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLTokenCheckCommand) Synopsis() string {
+	return "Explain the effective permissions of an ACL token"
+}
+
+func (c *ACLTokenCheckCommand) Name() string { return "acl token check" }
+
+func (c *ACLTokenCheckCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+	req := &api.ACLTokenCheckRequest{}
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.Var((funcVar)(func(s string) error {
+		req.Policies = append(req.Policies, s)
+		return nil
+	}), "policy", "")
+	flags.StringVar(&req.NodePool, "node-pool", "", "")
+	flags.StringVar(&req.HostVolume, "host-volume", "", "")
+	flags.StringVar(&req.VariablePath, "variable", "", "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got at most one argument
+	args = flags.Args()
+	switch len(args) {
+	case 0:
+	case 1:
+		req.AccessorID = args[0]
+	default:
+		c.Ui.Error("This command takes at most one argument: <token_accessor_id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if req.AccessorID != "" && len(req.Policies) > 0 {
+		c.Ui.Error("Cannot check both a token and a set of policies")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	check, _, err := client.ACLTokens().Check(req, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error checking ACL token: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, check)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatACLTokenCheck(check))
+	return 0
+}
+
+func formatACLTokenCheck(check *api.ACLTokenCheck) string {
+	accessorID := check.AccessorID
+	if accessorID == "" {
+		accessorID = "<simulated>"
+	}
+	output := []string{formatKV([]string{
+		fmt.Sprintf("Accessor ID|%s", accessorID),
+		fmt.Sprintf("Management|%v", check.Management),
+	})}
+
+	if !check.Management {
+		policies := []string{"<none>"}
+		if len(check.Policies) > 0 {
+			policies = []string{"Name|Role|Missing"}
+			for _, p := range check.Policies {
+				policies = append(policies, fmt.Sprintf("%s|%s|%v",
+					p.Name, valueOrNone(p.Role), p.Missing))
+			}
+		}
+		output = append(output, fmt.Sprintf("Policies\n%s", formatList(policies)))
+	}
+
+	for _, exp := range check.Explanations {
+		capabilities := []string{"Capability|Allowed|Granted By|Denied By"}
+		for _, capExp := range exp.Capabilities {
+			capabilities = append(capabilities, fmt.Sprintf("%s|%v|%s|%s",
+				capExp.Capability, capExp.Allowed,
+				valueOrNone(strings.Join(capExp.GrantedBy, ",")),
+				valueOrNone(strings.Join(capExp.DeniedBy, ","))))
+		}
+		output = append(output, fmt.Sprintf("%s\n%s",
+			formatACLExplanationHeader(check.Management, exp), formatList(capabilities)))
+	}
+
+	return strings.Join(output, "\n\n")
+}
+
+// formatACLExplanationHeader describes the resource explained and the rule
+// matching it.
+func formatACLExplanationHeader(management bool, exp *api.ACLExplanation) string {
+	var resource, rule string
+	switch exp.Resource {
+	case "namespace":
+		resource = fmt.Sprintf("Namespace %q", exp.Name)
+	case "node_pool":
+		resource = fmt.Sprintf("Node Pool %q", exp.Name)
+	case "host_volume":
+		resource = fmt.Sprintf("Host Volume %q", exp.Name)
+	case "variable":
+		resource = fmt.Sprintf("Variable %q in namespace %q", exp.Name, exp.Namespace)
+	default:
+		resource = fmt.Sprintf("%s %q", exp.Resource, exp.Name)
+	}
+
+	switch {
+	case management:
+		rule = "management token"
+	case exp.Rule == "":
+		rule = "no matching rule"
+	case exp.RuleNamespace != "":
+		rule = fmt.Sprintf("rule %q in namespace %q", exp.Rule, exp.RuleNamespace)
+	default:
+		rule = fmt.Sprintf("rule %q", exp.Rule)
+	}
+
+	return fmt.Sprintf("%s (%s)", resource, rule)
+}
+
+func valueOrNone(v string) string {
+	if v == "" {
+		return "<none>"
+	}
+	return v
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/command/agent"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestACLTokenCheckCommand(t *testing.T) {
+	ci.Parallel(t)
+
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer srv.Shutdown()
+
+	state := srv.Agent.Server().State()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	must.NotNil(t, token)
+
+	policy := mock.ACLPolicy()
+	policy.Name = "prod-read"
+	policy.Rules = `namespace "prod-*" { policy = "read" }`
+	policy.SetHash()
+	must.NoError(t, state.UpsertACLPolicies(structs.MsgTypeTestSetup, 1000, []*structs.ACLPolicy{policy}))
+
+	mockToken := mock.ACLToken()
+	mockToken.Policies = []string{policy.Name}
+	mockToken.SetHash()
+	must.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1010, []*structs.ACLToken{mockToken}))
+
+	ui := cli.NewMockUi()
+	cmd := &ACLTokenCheckCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Checking another token without a management token fails
+	code := cmd.Run([]string{"-address=" + url, "-token=" + mockToken.SecretID, token.AccessorID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Permission denied")
+	ui.ErrorWriter.Reset()
+
+	// Check the token with the management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID,
+		"-namespace=prod-web", mockToken.AccessorID})
+	must.Zero(t, code)
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, mockToken.AccessorID)
+	must.StrContains(t, out, `Namespace "prod-web" (rule "prod-*")`)
+	must.RegexMatch(t, regexp.MustCompile(`read-job\s+true\s+prod-read\s+<none>`), out)
+	must.RegexMatch(t, regexp.MustCompile(`submit-job\s+false\s+<none>\s+<none>`), out)
+	ui.OutputWriter.Reset()
+
+	// The token can check itself
+	code = cmd.Run([]string{"-address=" + url, "-token=" + mockToken.SecretID,
+		"-namespace=default", "-node-pool=default"})
+	must.Zero(t, code)
+	out = ui.OutputWriter.String()
+	must.StrContains(t, out, `Namespace "default" (no matching rule)`)
+	must.StrContains(t, out, `Node Pool "default" (no matching rule)`)
+	ui.OutputWriter.Reset()
+
+	// Simulate a set of policies
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID,
+		"-namespace=prod-api", "-policy=" + policy.Name, "-json"})
+	must.Zero(t, code)
+	must.StrContains(t, ui.OutputWriter.String(), `"Rule": "prod-*"`)
+	ui.OutputWriter.Reset()
+
+	// Checking both a token and policies is invalid
+	code = cmd.Run([]string{"-address=" + url, "-policy=foo", mockToken.AccessorID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Cannot check both")
+}
@@ -33,6 +33,11 @@ List Options:
 
   -t
     Format and display the ACL tokens using a Go template.
+
+  -unused-since
+    Only list tokens that have not been used to authenticate a request within
+    the given duration, such as "720h". Tokens created within the duration are
+    not listed.
 `
 
 	return strings.TrimSpace(helpText)
@@ -41,8 +46,9 @@ List Options:
 func (c *ACLTokenListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json": complete.PredictNothing,
-			"-t":    complete.PredictAnything,
+			"-json":         complete.PredictNothing,
+			"-t":            complete.PredictAnything,
+			"-unused-since": complete.PredictAnything,
 		})
 }
 
@@ -59,11 +65,13 @@ func (c *ACLTokenListCommand) Name() string { return "acl token list" }
 func (c *ACLTokenListCommand) Run(args []string) int {
 	var json bool
 	var tmpl string
+	var unusedSince time.Duration
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
+	flags.DurationVar(&unusedSince, "unused-since", 0, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -84,8 +92,15 @@ func (c *ACLTokenListCommand) Run(args []string) int {
 		return 1
 	}
 
+	var q *api.QueryOptions
+	if unusedSince > 0 {
+		q = &api.QueryOptions{
+			Params: map[string]string{"unused_since": unusedSince.String()},
+		}
+	}
+
 	// Fetch info on the policy
-	tokens, _, err := client.ACLTokens().List(nil)
+	tokens, _, err := client.ACLTokens().List(q)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error listing ACL tokens: %s", err))
 		return 1
@@ -112,7 +127,7 @@ func formatTokens(tokens []*api.ACLTokenListStub) string {
 	}
 
 	output := make([]string, 0, len(tokens)+1)
-	output = append(output, "Name|Type|Global|Accessor ID|Expired")
+	output = append(output, "Name|Type|Global|Accessor ID|Expired|Last Used")
 	for _, p := range tokens {
 		expired := false
 		if p.ExpirationTime != nil && !p.ExpirationTime.IsZero() {
@@ -121,8 +136,13 @@ func formatTokens(tokens []*api.ACLTokenListStub) string {
 			}
 		}
 
+		lastUsed := "<never>"
+		if p.LastUsedTime != nil && !p.LastUsedTime.IsZero() {
+			lastUsed = formatTime(*p.LastUsedTime)
+		}
+
 		output = append(output, fmt.Sprintf(
-			"%s|%s|%t|%s|%v", p.Name, p.Type, p.Global, p.AccessorID, expired))
+			"%s|%s|%t|%s|%v|%s", p.Name, p.Type, p.Global, p.AccessorID, expired, lastUsed))
 	}
 
 	return formatList(output)
@@ -59,4 +59,11 @@ func TestACLTokenListCommand(t *testing.T) {
 	out = ui.OutputWriter.String()
 	must.StrContains(t, out, "CreateIndex")
 	ui.OutputWriter.Reset()
+
+	// Tokens created within the window aren't listed as unused
+	must.Zero(t, cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-unused-since=1h"}))
+
+	out = ui.OutputWriter.String()
+	must.StrContains(t, out, "No tokens found")
+	ui.OutputWriter.Reset()
 }
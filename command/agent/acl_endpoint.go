@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/open-wander/wander/nomad/structs"
 )
@@ -124,6 +125,14 @@ func (s *HTTPServer) ACLTokensRequest(resp http.ResponseWriter, req *http.Reques
 		return nil, nil
 	}
 
+	if unusedSince := req.URL.Query().Get("unused_since"); unusedSince != "" {
+		d, err := time.ParseDuration(unusedSince)
+		if err != nil || d <= 0 {
+			return nil, CodedError(http.StatusBadRequest, "unused_since must be a positive duration")
+		}
+		args.UnusedSince = d
+	}
+
 	var out structs.ACLTokenListResponse
 	if err := s.agent.RPC("ACL.ListTokens", &args, &out); err != nil {
 		return nil, err
@@ -174,6 +183,8 @@ func (s *HTTPServer) ACLTokenSpecificRequest(resp http.ResponseWriter, req *http
 		return s.aclTokenUpdate(resp, req, "")
 	case "/v1/acl/token/self":
 		return s.aclTokenSelf(resp, req)
+	case "/v1/acl/token/check":
+		return s.aclTokenCheck(resp, req)
 	}
 
 	accessor := strings.TrimPrefix(path, "/v1/acl/token/")
@@ -219,6 +230,35 @@ func (s *HTTPServer) aclTokenQuery(resp http.ResponseWriter, req *http.Request,
 	return out.Token, nil
 }
 
+// aclTokenCheck explains the effective permissions of a token, or of a set of
+// policies, on the namespace of the request and optionally a node pool, host
+// volume and variable path.
+func (s *HTTPServer) aclTokenCheck(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	query := req.URL.Query()
+	args := structs.ACLTokenCheckRequest{
+		AccessorID:   query.Get("accessor"),
+		Policies:     query["policy"],
+		NodePool:     query.Get("node_pool"),
+		HostVolume:   query.Get("host_volume"),
+		VariablePath: query.Get("variable"),
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ACLTokenCheckResponse
+	if err := s.agent.RPC(structs.ACLCheckTokenRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	return &out, nil
+}
+
 func (s *HTTPServer) aclTokenSelf(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if req.Method != http.MethodGet {
 		return nil, CodedError(405, ErrInvalidMethod)
@@ -889,6 +929,14 @@ func (s *HTTPServer) ACLLoginRequest(resp http.ResponseWriter, req *http.Request
 	if err := decodeBody(req, &args); err != nil {
 		return nil, CodedError(http.StatusBadRequest, err.Error())
 	}
+
+	// Pass along the client certificate chain presented during the TLS
+	// handshake so TLSCert auth methods can validate it.
+	if req.TLS != nil {
+		for _, cert := range req.TLS.PeerCertificates {
+			args.ClientCertificates = append(args.ClientCertificates, cert.Raw)
+		}
+	}
 	var out structs.ACLLoginResponse
 	if err := s.agent.RPC(structs.ACLLoginRPCMethod, &args, &out); err != nil {
 		return nil, err
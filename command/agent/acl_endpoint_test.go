@@ -5,6 +5,8 @@ package agent
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -15,6 +17,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	capOIDC "github.com/hashicorp/cap/oidc"
 	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/tlsutil"
 	"github.com/open-wander/wander/helper/uuid"
 	"github.com/open-wander/wander/nomad/mock"
 	"github.com/open-wander/wander/nomad/structs"
@@ -344,6 +347,30 @@ func TestHTTP_ACLTokenList(t *testing.T) {
 	})
 }
 
+func TestHTTP_ACLTokenList_UnusedSince(t *testing.T) {
+	ci.Parallel(t)
+	httpACLTest(t, nil, func(s *TestAgent) {
+		// Tokens created within the window aren't reported as unused.
+		req, err := http.NewRequest(http.MethodGet, "/v1/acl/tokens?unused_since=1h", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err := s.Server.ACLTokensRequest(respW, req)
+		must.NoError(t, err)
+		must.SliceEmpty(t, obj.([]*structs.ACLTokenListStub))
+
+		// An invalid duration is rejected.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/tokens?unused_since=foo", nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		_, err = s.Server.ACLTokensRequest(respW, req)
+		must.ErrorContains(t, err, "unused_since must be a positive duration")
+	})
+}
+
 func TestHTTP_ACLTokenQuery(t *testing.T) {
 	ci.Parallel(t)
 	httpACLTest(t, nil, func(s *TestAgent) {
@@ -393,6 +420,45 @@ func TestHTTP_ACLTokenQuery(t *testing.T) {
 	})
 }
 
+func TestHTTP_ACLTokenCheck(t *testing.T) {
+	ci.Parallel(t)
+	httpACLTest(t, nil, func(s *TestAgent) {
+		policy := mock.ACLPolicy()
+		args := structs.ACLPolicyUpsertRequest{
+			Policies: []*structs.ACLPolicy{policy},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: s.RootToken.SecretID,
+			},
+		}
+		var resp structs.GenericResponse
+		must.NoError(t, s.Agent.RPC(structs.ACLUpsertPoliciesRPCMethod, &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest(http.MethodGet,
+			"/v1/acl/token/check?namespace=default&node_pool=default&policy="+policy.Name, nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err := s.Server.ACLTokenSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.NotEq(t, "", respW.Result().Header.Get("X-Nomad-Index"))
+
+		out := obj.(*structs.ACLTokenCheckResponse)
+		must.Eq(t, []*structs.ACLTokenCheckPolicy{{Name: policy.Name}}, out.Policies)
+		must.Len(t, 2, out.Explanations)
+		must.Eq(t, "default", out.Explanations[0].Rule)
+		must.Eq(t, "", out.Explanations[1].Rule)
+
+		// Only GET is allowed.
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/token/check", nil)
+		must.NoError(t, err)
+		_, err = s.Server.ACLTokenSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, ErrInvalidMethod)
+	})
+}
+
 func TestHTTP_ACLTokenSelf(t *testing.T) {
 	ci.Parallel(t)
 	httpACLTest(t, nil, func(s *TestAgent) {
@@ -2028,6 +2094,73 @@ func TestHTTPServer_ACLLoginRequest(t *testing.T) {
 				must.Eq(t, mockACLRole.ID, aclTokenResp.Roles[0].ID)
 			},
 		},
+		{
+			name: "tls certificate",
+			testFn: func(testAgent *TestAgent) {
+
+				// Generate a CA and a client certificate signed by it.
+				caPEM, caKeyPEM, err := tlsutil.GenerateCA(tlsutil.CAOpts{})
+				must.NoError(t, err)
+				caSigner, err := tlsutil.ParseSigner(caKeyPEM)
+				must.NoError(t, err)
+				certPEM, _, err := tlsutil.GenerateCert(tlsutil.CertOpts{
+					Signer:      caSigner,
+					CA:          caPEM,
+					Name:        "web-01",
+					Days:        1,
+					DNSNames:    []string{"web-01.example.com"},
+					ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+				})
+				must.NoError(t, err)
+				cert, err := tlsutil.ParseCert(certPEM)
+				must.NoError(t, err)
+
+				// Generate and upsert a TLSCert ACL auth method, a policy and
+				// a binding rule granting it.
+				mockedAuthMethod := mock.ACLTLSCertAuthMethod()
+				mockedAuthMethod.Config.TLSCertCACerts = []string{caPEM}
+				must.NoError(t, testAgent.server.State().UpsertACLAuthMethods(
+					10, []*structs.ACLAuthMethod{mockedAuthMethod}))
+
+				mockACLPolicy := mock.ACLPolicy()
+				must.NoError(t, testAgent.server.State().UpsertACLPolicies(
+					structs.MsgTypeTestSetup, 20, []*structs.ACLPolicy{mockACLPolicy}))
+
+				mockBindingRule := mock.ACLBindingRule()
+				mockBindingRule.AuthMethod = mockedAuthMethod.Name
+				mockBindingRule.BindType = structs.ACLBindingRuleBindTypePolicy
+				mockBindingRule.Selector = `value.common_name == "web-01"`
+				mockBindingRule.BindName = mockACLPolicy.Name
+				must.NoError(t, testAgent.server.State().UpsertACLBindingRules(
+					30, []*structs.ACLBindingRule{mockBindingRule}, true))
+
+				requestBody := structs.ACLLoginRequest{
+					AuthMethodName: mockedAuthMethod.Name,
+					WriteRequest: structs.WriteRequest{
+						Region: "global",
+					},
+				}
+
+				// Without a client certificate the login fails.
+				req, err := http.NewRequest(http.MethodPost, "/v1/acl/login", encodeReq(&requestBody))
+				must.NoError(t, err)
+				_, err = testAgent.Server.ACLLoginRequest(httptest.NewRecorder(), req)
+				must.ErrorContains(t, err, "missing login token or client certificate")
+
+				// The certificate presented during the TLS handshake is used
+				// to log in.
+				req, err = http.NewRequest(http.MethodPost, "/v1/acl/login", encodeReq(&requestBody))
+				must.NoError(t, err)
+				req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+
+				obj, err := testAgent.Server.ACLLoginRequest(httptest.NewRecorder(), req)
+				must.NoError(t, err)
+
+				aclTokenResp, ok := obj.(*structs.ACLToken)
+				must.True(t, ok)
+				must.Eq(t, []string{mockACLPolicy.Name}, aclTokenResp.Policies)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
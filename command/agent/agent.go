@@ -27,6 +27,7 @@ import (
 	"github.com/open-wander/wander/client/state"
 	"github.com/open-wander/wander/command/agent/consul"
 	"github.com/open-wander/wander/command/agent/event"
+	"github.com/open-wander/wander/helper"
 	"github.com/open-wander/wander/helper/bufconndialer"
 	"github.com/open-wander/wander/helper/escapingfs"
 	"github.com/open-wander/wander/helper/pluginutils/loader"
@@ -278,6 +279,10 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	if agentConfig.ACL.TokenMaxExpirationTTL != 0 {
 		conf.ACLTokenMaxExpirationTTL = agentConfig.ACL.TokenMaxExpirationTTL
 	}
+	if agentConfig.ACL.TokenUnusedTTL < 0 {
+		return nil, fmt.Errorf("acl token_unused_ttl must not be negative")
+	}
+	conf.ACLTokenUnusedTTL = agentConfig.ACL.TokenUnusedTTL
 	if agentConfig.Sentinel != nil {
 		conf.SentinelConfig = agentConfig.Sentinel
 	}
@@ -350,6 +355,31 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		conf.JobTrackedVersions = *agentConfig.Server.JobTrackedVersions
 	}
 
+	for _, se := range agentConfig.Server.SchedulerExtensions {
+		ext := &nomad.SchedulerExtensionConfig{Name: se.Name}
+		if se.Timeout != nil {
+			timeout, err := time.ParseDuration(*se.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("scheduler_extension %q timeout can't be parsed: %v", se.Name, err)
+			}
+			if timeout <= 0 {
+				return nil, fmt.Errorf("scheduler_extension %q timeout must be greater than 0", se.Name)
+			}
+			ext.Timeout = timeout
+		}
+		conf.SchedulerExtensions = append(conf.SchedulerExtensions, ext)
+	}
+
+	if err := agentConfig.Server.RPCRateLimit.Validate(); err != nil {
+		return nil, fmt.Errorf("rpc_rate_limit is invalid: %v", err)
+	}
+	conf.RPCRateLimit = agentConfig.Server.RPCRateLimit.Copy()
+
+	if err := config.ValidateKEKProviderConfigs(agentConfig.Server.KEKProviders); err != nil {
+		return nil, fmt.Errorf("keyring is invalid: %v", err)
+	}
+	conf.KEKProviderConfigs = helper.CopySlice(agentConfig.Server.KEKProviders)
+
 	// Set up the bind addresses
 	rpcAddr, err := net.ResolveTCPAddr("tcp", agentConfig.normalizedAddrs.RPC)
 	if err != nil {
@@ -432,6 +462,13 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.DeploymentGCThreshold = dur
 	}
+	if gcThreshold := agentConfig.Server.ExecSessionGCThreshold; gcThreshold != "" {
+		dur, err := time.ParseDuration(gcThreshold)
+		if err != nil {
+			return nil, err
+		}
+		conf.ExecSessionGCThreshold = dur
+	}
 	if gcInterval := agentConfig.Server.CSIVolumeClaimGCInterval; gcInterval != "" {
 		dur, err := time.ParseDuration(gcInterval)
 		if err != nil {
@@ -483,6 +520,12 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.RootKeyRotationThreshold = dur
 	}
+	if issuer := agentConfig.Server.OIDCIssuer; issuer != "" {
+		if _, err := structs.NewOIDCDiscoveryConfig(issuer); err != nil {
+			return nil, fmt.Errorf("failed to parse oidc_issuer: %v", err)
+		}
+		conf.OIDCIssuer = issuer
+	}
 
 	if heartbeatGrace := agentConfig.Server.HeartbeatGrace; heartbeatGrace != 0 {
 		conf.HeartbeatGrace = heartbeatGrace
@@ -615,6 +658,7 @@ func (a *Agent) finalizeServerConfig(c *nomad.Config) {
 	c.Logger = a.logger
 	c.LogOutput = a.logOutput
 	c.AgentShutdown = func() error { return a.Shutdown() }
+	c.PluginLoader = a.pluginLoader
 }
 
 // clientConfig is used to generate a new client configuration struct for
@@ -735,6 +779,23 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 		}
 		conf.MaxKillTimeout = dur
 	}
+	if agentConfig.Client.ExecRecordingDir != "" {
+		conf.ExecRecordingDir = agentConfig.Client.ExecRecordingDir
+	}
+	if agentConfig.Client.ExecRecordingRetention != "" {
+		dur, err := time.ParseDuration(agentConfig.Client.ExecRecordingRetention)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing exec recording retention: %s", err)
+		}
+		conf.ExecRecordingRetention = dur
+	}
+	if agentConfig.Client.MigrateBandwidthLimit != "" {
+		limit, err := humanize.ParseBytes(agentConfig.Client.MigrateBandwidthLimit)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing migrate bandwidth limit: %s", err)
+		}
+		conf.MigrateBandwidthLimit = int64(limit)
+	}
 	conf.ClientMaxPort = uint(agentConfig.Client.ClientMaxPort)
 	conf.ClientMinPort = uint(agentConfig.Client.ClientMinPort)
 	conf.MaxDynamicPort = agentConfig.Client.MaxDynamicPort
@@ -867,6 +928,12 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	}
 	conf.Drain = drainConfig
 
+	evictionConfig, err := clientconfig.EvictionConfigFromAgent(agentConfig.Client.Eviction)
+	if err != nil {
+		return nil, fmt.Errorf("invalid eviction config: %v", err)
+	}
+	conf.Eviction = evictionConfig
+
 	return conf, nil
 }
 
@@ -876,6 +943,14 @@ func (a *Agent) setupServer() error {
 		return nil
 	}
 
+	// Scheduler extensions are loaded by the plugin loader, which must be
+	// set up before the call to serverConfig copies it to the server config.
+	if len(a.config.Server.SchedulerExtensions) > 0 {
+		if err := a.setupPlugins(); err != nil {
+			return err
+		}
+	}
+
 	// Setup the configuration
 	conf, err := a.serverConfig()
 	if err != nil {
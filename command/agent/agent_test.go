@@ -20,6 +20,7 @@ import (
 	cstructs "github.com/open-wander/wander/client/structs"
 	"github.com/open-wander/wander/helper/pointer"
 	"github.com/open-wander/wander/helper/testlog"
+	"github.com/open-wander/wander/nomad"
 	"github.com/open-wander/wander/nomad/structs"
 	"github.com/open-wander/wander/nomad/structs/config"
 	"github.com/open-wander/wander/testutil"
@@ -1728,3 +1729,95 @@ func TestAgent_ServerConfig_JobDefaultPriority_Bad(t *testing.T) {
 		})
 	}
 }
+
+func TestAgent_ServerConfig_SchedulerExtensions(t *testing.T) {
+	ci.Parallel(t)
+
+	conf := DevConfig(nil)
+	must.NoError(t, conf.normalizeAddrs())
+
+	conf.Server.SchedulerExtensions = []*config.SchedulerExtensionConfig{
+		{Name: "inventory", Timeout: pointer.Of("250ms")},
+		{Name: "power"},
+	}
+
+	serverConf, err := convertServerConfig(conf)
+	must.NoError(t, err)
+	must.Eq(t, []*nomad.SchedulerExtensionConfig{
+		{Name: "inventory", Timeout: 250 * time.Millisecond},
+		{Name: "power"},
+	}, serverConf.SchedulerExtensions)
+
+	conf.Server.SchedulerExtensions[0].Timeout = pointer.Of("soon")
+	_, err = convertServerConfig(conf)
+	must.ErrorContains(t, err, `scheduler_extension "inventory" timeout can't be parsed`)
+
+	conf.Server.SchedulerExtensions[0].Timeout = pointer.Of("0s")
+	_, err = convertServerConfig(conf)
+	must.ErrorContains(t, err, "must be greater than 0")
+}
+
+func TestAgent_ServerConfig_RPCRateLimit(t *testing.T) {
+	ci.Parallel(t)
+
+	conf := DevConfig(nil)
+	must.NoError(t, conf.normalizeAddrs())
+
+	serverConf, err := convertServerConfig(conf)
+	must.NoError(t, err)
+	must.Nil(t, serverConf.RPCRateLimit)
+
+	conf.Server.RPCRateLimit = &config.RPCRateLimitConfig{
+		Token: &config.RPCRateLimit{Read: pointer.Of(100)},
+	}
+	serverConf, err = convertServerConfig(conf)
+	must.NoError(t, err)
+	must.Eq(t, conf.Server.RPCRateLimit, serverConf.RPCRateLimit)
+
+	conf.Server.RPCRateLimit.Token.Write = pointer.Of(0)
+	_, err = convertServerConfig(conf)
+	must.ErrorContains(t, err, "token: write limit must be greater than 0")
+}
+
+func TestAgent_ServerConfig_Keyring(t *testing.T) {
+	ci.Parallel(t)
+
+	conf := DevConfig(nil)
+	must.NoError(t, conf.normalizeAddrs())
+
+	serverConf, err := convertServerConfig(conf)
+	must.NoError(t, err)
+	must.SliceEmpty(t, serverConf.KEKProviderConfigs)
+
+	conf.Server.KEKProviders = []*config.KEKProviderConfig{
+		{Provider: config.KEKProviderAEAD},
+		{Provider: config.KEKProviderAWSKMS, Active: true, Config: map[string]string{"kms_key_id": "foo"}},
+	}
+	serverConf, err = convertServerConfig(conf)
+	must.NoError(t, err)
+	must.Eq(t, conf.Server.KEKProviders, serverConf.KEKProviderConfigs)
+
+	conf.Server.KEKProviders[0].Active = true
+	_, err = convertServerConfig(conf)
+	must.ErrorContains(t, err, "exactly one keyring must be active")
+}
+
+func TestAgent_ServerConfig_OIDCIssuer(t *testing.T) {
+	ci.Parallel(t)
+
+	conf := DevConfig(nil)
+	must.NoError(t, conf.normalizeAddrs())
+
+	serverConf, err := convertServerConfig(conf)
+	must.NoError(t, err)
+	must.Eq(t, "", serverConf.OIDCIssuer)
+
+	conf.Server.OIDCIssuer = "https://nomad.example.com"
+	serverConf, err = convertServerConfig(conf)
+	must.NoError(t, err)
+	must.Eq(t, "https://nomad.example.com", serverConf.OIDCIssuer)
+
+	conf.Server.OIDCIssuer = "nomad.example.com"
+	_, err = convertServerConfig(conf)
+	must.ErrorContains(t, err, "failed to parse oidc_issuer")
+}
@@ -4,6 +4,7 @@
 package agent
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -225,11 +226,23 @@ func (s *HTTPServer) ClientAllocRequest(resp http.ResponseWriter, req *http.Requ
 		return s.allocStats(allocID, resp, req)
 	case "exec":
 		return s.allocExec(allocID, resp, req)
+	case "port-forward":
+		return s.allocPortForward(allocID, resp, req)
 	case "snapshot":
 		if s.agent.Client() == nil {
 			return nil, clientNotRunning
 		}
 		return s.allocSnapshot(allocID, resp, req)
+	case "snapshot-manifest":
+		if s.agent.Client() == nil {
+			return nil, clientNotRunning
+		}
+		return s.allocSnapshotManifest(allocID, resp, req)
+	case "snapshot-chunk":
+		if s.agent.Client() == nil {
+			return nil, clientNotRunning
+		}
+		return s.allocSnapshotChunk(allocID, resp, req)
 	case "restart":
 		return s.allocRestart(allocID, resp, req)
 	case "gc":
@@ -410,6 +423,67 @@ func (s *HTTPServer) allocSnapshot(allocID string, resp http.ResponseWriter, req
 	return nil, nil
 }
 
+// allocSnapshotManifest returns the manifest of the snapshot of an allocation,
+// for migrating its data in chunks.
+func (s *HTTPServer) allocSnapshotManifest(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var secret string
+	s.parseToken(req, &secret)
+	if !s.agent.Client().ValidateMigrateToken(allocID, secret) {
+		return nil, structs.ErrPermissionDenied
+	}
+
+	allocFS, err := s.agent.Client().GetAllocFS(allocID)
+	if err != nil {
+		return nil, fmt.Errorf(allocNotFoundErr)
+	}
+	manifest, err := allocFS.SnapshotManifest()
+	if err != nil {
+		return nil, fmt.Errorf("error making snapshot manifest: %v", err)
+	}
+	return manifest, nil
+}
+
+// allocSnapshotChunk streams a gzip compressed chunk of a file of the
+// snapshot manifest of an allocation.
+func (s *HTTPServer) allocSnapshotChunk(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var secret string
+	s.parseToken(req, &secret)
+	if !s.agent.Client().ValidateMigrateToken(allocID, secret) {
+		return nil, structs.ErrPermissionDenied
+	}
+
+	q := req.URL.Query()
+	path := q.Get("path")
+	if path == "" {
+		return nil, CodedError(400, "must provide path")
+	}
+	offset, err := strconv.ParseInt(q.Get("offset"), 10, 64)
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("failed to parse offset: %v", err))
+	}
+	length, err := strconv.ParseInt(q.Get("length"), 10, 64)
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("failed to parse length: %v", err))
+	}
+
+	allocFS, err := s.agent.Client().GetAllocFS(allocID)
+	if err != nil {
+		return nil, fmt.Errorf(allocNotFoundErr)
+	}
+
+	// Buffer the chunk so errors are reported with their status code
+	// rather than as a truncated chunk.
+	var buf bytes.Buffer
+	if err := allocFS.SnapshotChunk(path, offset, length, &buf); err != nil {
+		return nil, CodedError(400, fmt.Sprintf("error reading snapshot chunk: %v", err))
+	}
+	resp.Header().Set("Content-Type", "application/gzip")
+	if _, err := io.Copy(resp, &buf); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
 func (s *HTTPServer) allocStats(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 
 	// Build the request and parse the ACL token
@@ -519,6 +593,33 @@ func (s *HTTPServer) allocExec(allocID string, resp http.ResponseWriter, req *ht
 	return s.execStreamImpl(conn, &args)
 }
 
+func (s *HTTPServer) allocPortForward(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Build the request and parse the ACL token
+	port := req.URL.Query().Get("port")
+	if port == "" {
+		return nil, CodedError(400, "port must be set")
+	}
+
+	args := cstructs.AllocPortForwardRequest{
+		AllocID: allocID,
+		Port:    port,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	conn, err := s.wsUpgrader.Upgrade(resp, req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade connection: %v", err)
+	}
+
+	if err := readWsHandshake(conn.ReadJSON, req, &args.QueryOptions); err != nil {
+		conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(toWsCode(400), err.Error()))
+		return nil, err
+	}
+
+	return s.portForwardStreamImpl(conn, &args)
+}
+
 // readWsHandshake reads the websocket handshake message and sets
 // query authentication token, if request requires a handshake
 func readWsHandshake(readFn func(interface{}) error, req *http.Request, q *structs.QueryOptions) error {
@@ -657,6 +758,126 @@ func (s *HTTPServer) execStreamImpl(ws *websocket.Conn, args *cstructs.AllocExec
 	return nil, codedErr
 }
 
+// portForwardStreamImpl bridges the binary messages of the websocket with
+// the connection to the port of the allocation.
+func (s *HTTPServer) portForwardStreamImpl(ws *websocket.Conn, args *cstructs.AllocPortForwardRequest) (interface{}, error) {
+	allocID := args.AllocID
+	method := "Allocations.PortForward"
+
+	// Get the correct handler
+	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(allocID)
+	var handler structs.StreamingRpcHandler
+	var handlerErr error
+	if localClient {
+		handler, handlerErr = s.agent.Client().StreamingRpcHandler(method)
+	} else if remoteClient {
+		handler, handlerErr = s.agent.Client().RemoteStreamingRpcHandler(method)
+	} else if localServer {
+		handler, handlerErr = s.agent.Server().StreamingRpcHandler(method)
+	}
+
+	if handlerErr != nil {
+		return nil, CodedError(500, handlerErr.Error())
+	}
+
+	// Create a pipe connecting the (possibly remote) handler to the http response
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	// Create a goroutine that closes the pipe if the connection closes.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+	}()
+
+	// Create a channel that decodes the results
+	errCh := make(chan HTTPCodedError, 2)
+
+	// stream response
+	go func() {
+		defer cancel()
+
+		// Send the request
+		if err := encoder.Encode(args); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+
+		go forwardPortForwardInput(encoder, ws, cancel)
+
+		for {
+			var res cstructs.StreamErrWrapper
+			err := decoder.Decode(&res)
+			if isClosedError(err) {
+				ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				errCh <- nil
+				return
+			}
+
+			if err != nil {
+				errCh <- CodedError(500, err.Error())
+				return
+			}
+			decoder.Reset(httpPipe)
+
+			if err := res.Error; err != nil {
+				code := 500
+				if err.Code != nil {
+					code = int(*err.Code)
+				}
+				errCh <- CodedError(code, err.Error())
+				return
+			}
+
+			if err := ws.WriteMessage(websocket.BinaryMessage, res.Payload); err != nil {
+				errCh <- CodedError(500, err.Error())
+				return
+			}
+		}
+	}()
+
+	// start streaming request to streaming RPC - returns when streaming completes or errors
+	handler(handlerPipe)
+	cancel()
+	codedErr := <-errCh
+
+	if codedErr != nil {
+		s.logger.Debug("alloc port forward channel closed with error", "error", codedErr)
+	}
+
+	if isClosedError(codedErr) {
+		codedErr = nil
+	} else if codedErr != nil {
+		ws.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(toWsCode(codedErr.Code()), codedErr.Error()))
+	}
+	ws.Close()
+
+	return nil, codedErr
+}
+
+// forwardPortForwardInput forwards the binary messages of the websocket
+// connection to the streaming RPC connection to client. The session is
+// cancelled once the websocket is closed.
+func forwardPortForwardInput(encoder *codec.Encoder, ws *websocket.Conn, cancel context.CancelFunc) {
+	defer cancel()
+	for {
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		if err := encoder.Encode(&cstructs.StreamErrWrapper{Payload: data}); err != nil {
+			return
+		}
+	}
+}
+
 func toWsCode(httpCode int) int {
 	switch httpCode {
 	case 500:
@@ -5,8 +5,10 @@ package agent
 
 import (
 	"archive/tar"
+	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -14,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/snappy"
 	"github.com/open-wander/wander/acl"
@@ -24,6 +27,7 @@ import (
 	"github.com/open-wander/wander/nomad/mock"
 	"github.com/open-wander/wander/nomad/structs"
 	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/require"
 )
 
@@ -720,6 +724,43 @@ func TestHTTP_AllocSnapshot_WithMigrateToken(t *testing.T) {
 	})
 }
 
+func TestHTTP_AllocSnapshotChunks_WithMigrateToken(t *testing.T) {
+	ci.Parallel(t)
+	httpACLTest(t, nil, func(s *TestAgent) {
+		alloc := mock.Alloc()
+		validMigrateToken, err := structs.GenerateMigrateToken(alloc.ID, s.Agent.Client().Node().SecretID)
+		must.NoError(t, err)
+
+		for _, path := range []string{
+			"snapshot-manifest",
+			"snapshot-chunk?path=alloc/data/foo&offset=0&length=1",
+		} {
+			url := fmt.Sprintf("/v1/client/allocation/%s/%s", alloc.ID, path)
+
+			// Request without a token fails
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			must.NoError(t, err)
+			_, err = s.Server.ClientAllocRequest(httptest.NewRecorder(), req)
+			must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+			// Request with a token is authorized
+			req, err = http.NewRequest(http.MethodGet, url, nil)
+			must.NoError(t, err)
+			req.Header.Set("X-Nomad-Token", validMigrateToken)
+			_, err = s.Server.ClientAllocRequest(httptest.NewRecorder(), req)
+			must.EqError(t, err, allocNotFoundErr)
+		}
+
+		// The chunk must be given
+		url := fmt.Sprintf("/v1/client/allocation/%s/snapshot-chunk?path=alloc/data/foo", alloc.ID)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		must.NoError(t, err)
+		req.Header.Set("X-Nomad-Token", validMigrateToken)
+		_, err = s.Server.ClientAllocRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "failed to parse offset")
+	})
+}
+
 // TestHTTP_AllocSnapshot_Atomic ensures that when a client encounters an error
 // snapshotting a valid tar is not returned.
 func TestHTTP_AllocSnapshot_Atomic(t *testing.T) {
@@ -1123,3 +1164,65 @@ func TestHTTP_ReadWsHandshake(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTP_AllocPortForward(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Start a listener for the allocation to expose
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		must.NoError(t, err)
+		defer l.Close()
+		go func() {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			io.Copy(conn, conn)
+		}()
+
+		a := mockFSAlloc(s.client.NodeID(), map[string]interface{}{
+			"run_for": "20s",
+		})
+		a.AllocatedResources.Shared.Ports = structs.AllocatedPorts{{
+			Label:  "echo",
+			Value:  l.Addr().(*net.TCPAddr).Port,
+			HostIP: "127.0.0.1",
+		}}
+		addAllocToClient(s, a, runningClientAlloc)
+
+		alloc, _, err := s.Client().Allocations().Info(a.ID, nil)
+		must.NoError(t, err)
+
+		local, remote := net.Pipe()
+		defer local.Close()
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- s.Client().Allocations().PortForward(context.Background(), alloc, "echo", remote, nil)
+		}()
+
+		_, err = local.Write([]byte("ping"))
+		must.NoError(t, err)
+
+		buf := make([]byte, 4)
+		_, err = io.ReadFull(local, buf)
+		must.NoError(t, err)
+		must.Eq(t, "ping", string(buf))
+
+		// Closing the local connection ends the session
+		local.Close()
+		select {
+		case err := <-errCh:
+			must.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the session to end")
+		}
+
+		// Unknown ports are rejected
+		local, remote = net.Pipe()
+		defer local.Close()
+		err = s.Client().Allocations().PortForward(context.Background(), alloc, "http", remote, nil)
+		must.ErrorContains(t, err, "is not allocated")
+	})
+}
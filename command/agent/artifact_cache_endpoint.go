@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"net/http"
+
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+func (s *HTTPServer) ArtifactCacheRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case http.MethodGet:
+		return s.artifactCacheList(resp, req)
+	case http.MethodDelete:
+		return s.artifactCachePurge(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) artifactCacheList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Build the request by parsing all common parameters and node id
+	args := structs.NodeSpecificRequest{}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+	parseNode(req, &args.NodeID)
+
+	var reply structs.ArtifactCacheListResponse
+	if err := s.artifactCacheRPC("ArtifactCache.List", args.NodeID, &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *HTTPServer) artifactCachePurge(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Build the request by parsing all common parameters, node id and the
+	// optional key of the artifact to purge
+	args := structs.ArtifactCachePurgeRequest{
+		Key: req.URL.Query().Get("key"),
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+	parseNode(req, &args.NodeID)
+
+	var reply structs.ArtifactCachePurgeResponse
+	if err := s.artifactCacheRPC("ArtifactCache.Purge", args.NodeID, &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// artifactCacheRPC makes the artifact cache RPC against the local client or
+// forwards it to the node.
+func (s *HTTPServer) artifactCacheRPC(method, nodeID string, args, reply interface{}) error {
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForNode(nodeID)
+
+	// Make the RPC
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC(method, args, reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC(method, args, reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC(method, args, reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+		return rpcErr
+	}
+	return nil
+}
@@ -255,6 +255,18 @@ type ClientConfig struct {
 	// MaxKillTimeout allows capping the user-specifiable KillTimeout.
 	MaxKillTimeout string `hcl:"max_kill_timeout"`
 
+	// ExecRecordingDir is the directory where the recordings of exec sessions
+	// are stored. Defaults to a directory of the state dir.
+	ExecRecordingDir string `hcl:"exec_recording_dir"`
+
+	// ExecRecordingRetention is how long exec session recordings are kept.
+	ExecRecordingRetention string `hcl:"exec_recording_retention"`
+
+	// MigrateBandwidthLimit is the maximum rate per second at which the
+	// ephemeral disk of previous allocations is migrated from other nodes,
+	// as a byte size such as "50MB".
+	MigrateBandwidthLimit string `hcl:"migrate_bandwidth_limit"`
+
 	// ClientMaxPort is the upper range of the ports that the client uses for
 	// communicating with plugin subsystems
 	ClientMaxPort int `hcl:"client_max_port"`
@@ -360,6 +372,10 @@ type ClientConfig struct {
 	// Drain specifies whether to drain the client on shutdown; ignored in dev mode.
 	Drain *config.DrainConfig `hcl:"drain_on_shutdown"`
 
+	// Eviction specifies when the client evicts allocations while running
+	// low on resources.
+	Eviction *config.EvictionConfig `hcl:"eviction"`
+
 	// ExtraKeysHCL is used by hcl to surface unexpected keys
 	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
 }
@@ -383,6 +399,7 @@ func (c *ClientConfig) Copy() *ClientConfig {
 	nc.NomadServiceDiscovery = pointer.Copy(c.NomadServiceDiscovery)
 	nc.Artifact = c.Artifact.Copy()
 	nc.Drain = c.Drain.Copy()
+	nc.Eviction = c.Eviction.Copy()
 	nc.ExtraKeysHCL = slices.Clone(c.ExtraKeysHCL)
 	return &nc
 }
@@ -429,6 +446,12 @@ type ACLConfig struct {
 	TokenMaxExpirationTTL    time.Duration
 	TokenMaxExpirationTTLHCL string `hcl:"token_max_expiration_ttl" json:"-"`
 
+	// TokenUnusedTTL is how long a local client token can go unused before
+	// the Nomad servers garbage collect it like an expired token. Unused
+	// tokens are kept if it is zero.
+	TokenUnusedTTL    time.Duration
+	TokenUnusedTTLHCL string `hcl:"token_unused_ttl" json:"-"`
+
 	// ExtraKeysHCL is used by hcl to surface unexpected keys
 	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
 }
@@ -528,6 +551,10 @@ type ServerConfig struct {
 	// be collected by GC.
 	ACLTokenGCThreshold string `hcl:"acl_token_gc_threshold"`
 
+	// ExecSessionGCThreshold controls how long ago an exec session must have
+	// ended to be collected by GC.
+	ExecSessionGCThreshold string `hcl:"exec_session_gc_threshold"`
+
 	// RootKeyGCInterval is how often we dispatch a job to GC
 	// encryption key metadata
 	RootKeyGCInterval string `hcl:"root_key_gc_interval"`
@@ -541,6 +568,11 @@ type ServerConfig struct {
 	// collection interval.
 	RootKeyRotationThreshold string `hcl:"root_key_rotation_threshold"`
 
+	// OIDCIssuer is the URL of the issuer of workload identities. When set,
+	// workload identities include it as their "iss" claim and agents publish
+	// an OIDC discovery document pointing at the JWKS of the cluster.
+	OIDCIssuer string `hcl:"oidc_issuer"`
+
 	// HeartbeatGrace is the grace period beyond the TTL to account for network,
 	// processing delays and clock skew before marking a node as "down".
 	HeartbeatGrace    time.Duration
@@ -682,6 +714,18 @@ type ServerConfig struct {
 
 	// JobTrackedVersions is the number of historic job versions that are kept.
 	JobTrackedVersions *int `hcl:"job_tracked_versions"`
+
+	// SchedulerExtensions are the scheduler plugins consulted, in order,
+	// when ranking nodes for the placement of service and batch jobs.
+	SchedulerExtensions []*config.SchedulerExtensionConfig `hcl:"scheduler_extension"`
+
+	// RPCRateLimit configures the rate limits enforced on RPC requests per
+	// ACL token, namespace and RPC endpoint.
+	RPCRateLimit *config.RPCRateLimitConfig `hcl:"rpc_rate_limit"`
+
+	// KEKProviders configures the providers of the key encryption keys which
+	// wrap the root keys stored in the server's keystore.
+	KEKProviders []*config.KEKProviderConfig `hcl:"keyring"`
 }
 
 func (s *ServerConfig) Copy() *ServerConfig {
@@ -711,6 +755,9 @@ func (s *ServerConfig) Copy() *ServerConfig {
 	ns.JobDefaultPriority = pointer.Copy(s.JobDefaultPriority)
 	ns.JobMaxPriority = pointer.Copy(s.JobMaxPriority)
 	ns.JobTrackedVersions = pointer.Copy(s.JobTrackedVersions)
+	ns.SchedulerExtensions = helper.CopySlice(s.SchedulerExtensions)
+	ns.RPCRateLimit = s.RPCRateLimit.Copy()
+	ns.KEKProviders = helper.CopySlice(s.KEKProviders)
 	return &ns
 }
 
@@ -1875,6 +1922,12 @@ func (a *ACLConfig) Merge(b *ACLConfig) *ACLConfig {
 	if b.TokenMaxExpirationTTLHCL != "" {
 		result.TokenMaxExpirationTTLHCL = b.TokenMaxExpirationTTLHCL
 	}
+	if b.TokenUnusedTTL != 0 {
+		result.TokenUnusedTTL = b.TokenUnusedTTL
+	}
+	if b.TokenUnusedTTLHCL != "" {
+		result.TokenUnusedTTLHCL = b.TokenUnusedTTLHCL
+	}
 	if b.ReplicationToken != "" {
 		result.ReplicationToken = b.ReplicationToken
 	}
@@ -1946,6 +1999,9 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.ACLTokenGCThreshold != "" {
 		result.ACLTokenGCThreshold = b.ACLTokenGCThreshold
 	}
+	if b.ExecSessionGCThreshold != "" {
+		result.ExecSessionGCThreshold = b.ExecSessionGCThreshold
+	}
 	if b.RootKeyGCInterval != "" {
 		result.RootKeyGCInterval = b.RootKeyGCInterval
 	}
@@ -1955,6 +2011,9 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.RootKeyRotationThreshold != "" {
 		result.RootKeyRotationThreshold = b.RootKeyRotationThreshold
 	}
+	if b.OIDCIssuer != "" {
+		result.OIDCIssuer = b.OIDCIssuer
+	}
 	if b.HeartbeatGrace != 0 {
 		result.HeartbeatGrace = b.HeartbeatGrace
 	}
@@ -2065,6 +2124,18 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		result.JobTrackedVersions = b.JobTrackedVersions
 	}
 
+	if len(b.SchedulerExtensions) != 0 {
+		result.SchedulerExtensions = config.SchedulerExtensionConfigSetMerge(result.SchedulerExtensions, b.SchedulerExtensions)
+	}
+
+	if b.RPCRateLimit != nil {
+		result.RPCRateLimit = result.RPCRateLimit.Merge(b.RPCRateLimit)
+	}
+
+	if len(b.KEKProviders) != 0 {
+		result.KEKProviders = config.KEKProviderConfigSetMerge(result.KEKProviders, b.KEKProviders)
+	}
+
 	// Add the schedulers
 	result.EnabledSchedulers = append(result.EnabledSchedulers, b.EnabledSchedulers...)
 
@@ -2121,6 +2192,15 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.MaxKillTimeout != "" {
 		result.MaxKillTimeout = b.MaxKillTimeout
 	}
+	if b.ExecRecordingDir != "" {
+		result.ExecRecordingDir = b.ExecRecordingDir
+	}
+	if b.ExecRecordingRetention != "" {
+		result.ExecRecordingRetention = b.ExecRecordingRetention
+	}
+	if b.MigrateBandwidthLimit != "" {
+		result.MigrateBandwidthLimit = b.MigrateBandwidthLimit
+	}
 	if b.ClientMaxPort != 0 {
 		result.ClientMaxPort = b.ClientMaxPort
 	}
@@ -2249,6 +2329,7 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 
 	result.Artifact = a.Artifact.Merge(b.Artifact)
 	result.Drain = a.Drain.Merge(b.Drain)
+	result.Eviction = a.Eviction.Merge(b.Eviction)
 
 	return &result
 }
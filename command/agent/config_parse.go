@@ -72,6 +72,7 @@ func ParseConfigFile(path string) (*Config, error) {
 		{"acl.policy_ttl", &c.ACL.RoleTTL, &c.ACL.RoleTTLHCL, nil},
 		{"acl.token_min_expiration_ttl", &c.ACL.TokenMinExpirationTTL, &c.ACL.TokenMinExpirationTTLHCL, nil},
 		{"acl.token_max_expiration_ttl", &c.ACL.TokenMaxExpirationTTL, &c.ACL.TokenMaxExpirationTTLHCL, nil},
+		{"acl.token_unused_ttl", &c.ACL.TokenUnusedTTL, &c.ACL.TokenUnusedTTLHCL, nil},
 		{"client.server_join.retry_interval", &c.Client.ServerJoin.RetryInterval, &c.Client.ServerJoin.RetryIntervalHCL, nil},
 		{"server.heartbeat_grace", &c.Server.HeartbeatGrace, &c.Server.HeartbeatGraceHCL, nil},
 		{"server.min_heartbeat_ttl", &c.Server.MinHeartbeatTTL, &c.Server.MinHeartbeatTTLHCL, nil},
@@ -256,6 +257,30 @@ func extraKeys(c *Config) error {
 		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, k)
 	}
 
+	// Remove SchedulerExtension extra keys
+	for _, se := range c.Server.SchedulerExtensions {
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, se.Name)
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, "scheduler_extension")
+	}
+
+	// Remove RPCRateLimit extra keys
+	if rl := c.Server.RPCRateLimit; rl != nil {
+		for _, l := range rl.Namespaces {
+			helper.RemoveEqualFold(&rl.ExtraKeysHCL, l.Name)
+			helper.RemoveEqualFold(&rl.ExtraKeysHCL, "namespace")
+		}
+		for _, l := range rl.Endpoints {
+			helper.RemoveEqualFold(&rl.ExtraKeysHCL, l.Name)
+			helper.RemoveEqualFold(&rl.ExtraKeysHCL, "endpoint")
+		}
+	}
+
+	// Remove KEKProvider extra keys
+	for _, k := range c.Server.KEKProviders {
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, k.Provider)
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, "keyring")
+	}
+
 	for _, k := range []string{"datadog_tags"} {
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, k)
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, "telemetry")
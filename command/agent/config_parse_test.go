@@ -67,13 +67,16 @@ var basicConfig = &Config{
 			"/opt/myapp/etc": "/etc",
 			"/opt/myapp/bin": "/bin",
 		},
-		NetworkInterface: "eth0",
-		NetworkSpeed:     100,
-		CpuCompute:       4444,
-		MemoryMB:         0,
-		MaxKillTimeout:   "10s",
-		ClientMinPort:    1000,
-		ClientMaxPort:    2000,
+		NetworkInterface:       "eth0",
+		NetworkSpeed:           100,
+		CpuCompute:             4444,
+		MemoryMB:               0,
+		MaxKillTimeout:         "10s",
+		ExecRecordingDir:       "/tmp/exec-recordings",
+		ExecRecordingRetention: "168h",
+		MigrateBandwidthLimit:  "50MB",
+		ClientMinPort:          1000,
+		ClientMaxPort:          2000,
 		Reserved: &Resources{
 			CPU:           10,
 			MemoryMB:      10,
@@ -113,6 +116,7 @@ var basicConfig = &Config{
 		CSIVolumeClaimGCThreshold: "12h",
 		CSIPluginGCThreshold:      "12h",
 		ACLTokenGCThreshold:       "12h",
+		ExecSessionGCThreshold:    "12h",
 		HeartbeatGrace:            30 * time.Second,
 		HeartbeatGraceHCL:         "30s",
 		MinHeartbeatTTL:           33 * time.Second,
@@ -155,6 +159,30 @@ var basicConfig = &Config{
 		LicensePath:        "/tmp/nomad.hclic",
 		JobDefaultPriority: pointer.Of(100),
 		JobMaxPriority:     pointer.Of(200),
+		SchedulerExtensions: []*config.SchedulerExtensionConfig{
+			{Name: "inventory", Timeout: pointer.Of("250ms")},
+		},
+		RPCRateLimit: &config.RPCRateLimitConfig{
+			Token: &config.RPCRateLimit{Read: pointer.Of(100), Write: pointer.Of(10)},
+			Namespaces: []*config.NamedRPCRateLimit{
+				{Name: "prod", RPCRateLimit: config.RPCRateLimit{Read: pointer.Of(500)}},
+			},
+			Endpoints: []*config.NamedRPCRateLimit{
+				{Name: "Alloc.List", RPCRateLimit: config.RPCRateLimit{Read: pointer.Of(50)}},
+			},
+		},
+		KEKProviders: []*config.KEKProviderConfig{
+			{Provider: "aead"},
+			{
+				Provider: "transit",
+				Name:     "vault",
+				Active:   true,
+				Config: map[string]string{
+					"address":  "https://vault.example.com:8200",
+					"key_name": "nomad-keyring",
+				},
+			},
+		},
 	},
 	ACL: &ACLConfig{
 		Enabled:                  true,
@@ -168,6 +196,8 @@ var basicConfig = &Config{
 		TokenMinExpirationTTL:    1 * time.Hour,
 		TokenMaxExpirationTTLHCL: "100h",
 		TokenMaxExpirationTTL:    100 * time.Hour,
+		TokenUnusedTTLHCL:        "720h",
+		TokenUnusedTTL:           720 * time.Hour,
 		ReplicationToken:         "foobar",
 	},
 	Audit: &config.AuditConfig{
@@ -241,6 +271,8 @@ var basicConfig = &Config{
 		ConnectionRetryIntv:  config.DefaultVaultConnectRetryIntv,
 		Enabled:              &falseValue,
 		Role:                 "test_role",
+		UseIdentity:          &trueValue,
+		JWTAuthBackendPath:   "jwt-test",
 		TLSCaFile:            "/path/to/ca/file",
 		TLSCaPath:            "/path/to/ca",
 		TLSCertFile:          "/path/to/cert/file",
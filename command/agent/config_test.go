@@ -380,6 +380,7 @@ func TestConfig_Merge(t *testing.T) {
 			RoleTTL:               20 * time.Second,
 			TokenMinExpirationTTL: 20 * time.Second,
 			TokenMaxExpirationTTL: 20 * time.Second,
+			TokenUnusedTTL:        20 * time.Second,
 			ReplicationToken:      "foobar",
 		},
 		Ports: &Ports{
@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/docker/docker/pkg/ioutils"
+	cstructs "github.com/open-wander/wander/client/structs"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+// ExecSessionsRequest lists the exec sessions and is callable via the
+// /v1/exec-sessions HTTP API.
+func (s *HTTPServer) ExecSessionsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	args := structs.ExecSessionListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ExecSessionListResponse
+	if err := s.agent.RPC("ExecSession.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Sessions == nil {
+		out.Sessions = make([]*structs.ExecSession, 0)
+	}
+	return out.Sessions, nil
+}
+
+// ExecSessionSpecificRequest reads an exec session or streams its recording
+// and is callable via the /v1/exec-session/ HTTP API.
+func (s *HTTPServer) ExecSessionSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v1/exec-session/")
+	switch {
+	case strings.HasSuffix(path, "/recording"):
+		sessionID := strings.TrimSuffix(path, "/recording")
+		return s.execSessionRecording(resp, req, sessionID)
+	case path == "" || strings.Contains(path, "/"):
+		return nil, CodedError(http.StatusBadRequest, "invalid URI")
+	default:
+		return s.execSessionQuery(resp, req, path)
+	}
+}
+
+func (s *HTTPServer) execSessionQuery(resp http.ResponseWriter, req *http.Request, sessionID string) (interface{}, error) {
+	args := structs.ExecSessionSpecificRequest{
+		ID: sessionID,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleExecSessionResponse
+	if err := s.agent.RPC("ExecSession.GetSession", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Session == nil {
+		return nil, CodedError(http.StatusNotFound, "exec session not found")
+	}
+	return out.Session, nil
+}
+
+// execSessionRecording streams the asciicast recording of an exec session
+// from the client that ran it.
+func (s *HTTPServer) execSessionRecording(resp http.ResponseWriter, req *http.Request, sessionID string) (interface{}, error) {
+	if sessionID == "" {
+		return nil, CodedError(http.StatusBadRequest, "missing exec session ID")
+	}
+
+	args := &cstructs.ExecSessionRecordingRequest{
+		SessionID: sessionID,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	var handler structs.StreamingRpcHandler
+	var handlerErr error
+	if server := s.agent.Server(); server != nil {
+		handler, handlerErr = server.StreamingRpcHandler("ExecSession.Recording")
+	} else if client := s.agent.Client(); client != nil {
+		handler, handlerErr = client.RemoteStreamingRpcHandler("ExecSession.Recording")
+	} else {
+		handlerErr = fmt.Errorf("misconfigured connection")
+	}
+	if handlerErr != nil {
+		return nil, CodedError(http.StatusInternalServerError, handlerErr.Error())
+	}
+
+	resp.Header().Set("Content-Type", "application/x-asciicast")
+
+	// Create an output that gets flushed on every write
+	output := ioutils.NewWriteFlusher(resp)
+	if err := streamPayloadImpl(req.Context(), handler, args, output); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestHTTP_ExecSessions(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		session := mock.ExecSession()
+		other := mock.ExecSession()
+		store := s.Agent.Server().State()
+		must.NoError(t, store.UpsertExecSession(structs.MsgTypeTestSetup, 1000, session))
+		must.NoError(t, store.UpsertExecSession(structs.MsgTypeTestSetup, 1001, other))
+
+		// List the sessions
+		req, err := http.NewRequest(http.MethodGet, "/v1/exec-sessions", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.ExecSessionsRequest(respW, req)
+		must.NoError(t, err)
+		must.SliceLen(t, 2, obj.([]*structs.ExecSession))
+		must.Eq(t, "1001", respW.Header().Get("X-Nomad-Index"))
+
+		// List the sessions by prefix
+		req, err = http.NewRequest(http.MethodGet, "/v1/exec-sessions?prefix="+session.ID[:8], nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.ExecSessionsRequest(respW, req)
+		must.NoError(t, err)
+		must.SliceLen(t, 1, obj.([]*structs.ExecSession))
+
+		// Read a session
+		req, err = http.NewRequest(http.MethodGet, "/v1/exec-session/"+session.ID, nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.ExecSessionSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.Eq(t, session.ID, obj.(*structs.ExecSession).ID)
+
+		// Unknown sessions are not found
+		req, err = http.NewRequest(http.MethodGet, "/v1/exec-session/"+mock.ExecSession().ID, nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ExecSessionSpecificRequest(respW, req)
+		must.ErrorContains(t, err, "exec session not found")
+
+		// Sessions are read only
+		req, err = http.NewRequest(http.MethodDelete, "/v1/exec-session/"+session.ID, nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ExecSessionSpecificRequest(respW, req)
+		must.ErrorContains(t, err, ErrInvalidMethod)
+	})
+}
@@ -19,6 +19,12 @@ import (
 	"github.com/open-wander/wander/nomad/structs"
 )
 
+const (
+	// fsUploadFrameSize is the maximum size of the payload of the frames
+	// sent by uploads.
+	fsUploadFrameSize = 32 * 1024
+)
+
 var (
 	allocIDNotPresentErr  = CodedError(400, "must provide a valid alloc id")
 	fileNameNotPresentErr = CodedError(400, "must provide a file name")
@@ -41,6 +47,12 @@ func (s *HTTPServer) FsRequest(resp http.ResponseWriter, req *http.Request) (int
 		return s.wrapUntrustedContent(s.FileCatRequest)(resp, req)
 	case strings.HasPrefix(path, "stream/"):
 		return s.Stream(resp, req)
+	case strings.HasPrefix(path, "upload/"):
+		return s.FileUploadRequest(resp, req)
+	case strings.HasPrefix(path, "rm/"):
+		return s.FileRemoveRequest(resp, req)
+	case strings.HasPrefix(path, "archive/"):
+		return s.FileArchiveRequest(resp, req)
 	case strings.HasPrefix(path, "logs/"):
 		// Logs are *trusted* content because the endpoint
 		// explicitly sets the Content-Type to text/plain or
@@ -70,24 +82,9 @@ func (s *HTTPServer) DirectoryListRequest(resp http.ResponseWriter, req *http.Re
 	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
 
 	// Make the RPC
-	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(allocID)
-
 	var reply cstructs.FsListResponse
-	var rpcErr error
-	if localClient {
-		rpcErr = s.agent.Client().ClientRPC("FileSystem.List", &args, &reply)
-	} else if remoteClient {
-		rpcErr = s.agent.Client().RPC("FileSystem.List", &args, &reply)
-	} else if localServer {
-		rpcErr = s.agent.Server().RPC("FileSystem.List", &args, &reply)
-	}
-
-	if rpcErr != nil {
-		if structs.IsErrNoNodeConn(rpcErr) || structs.IsErrUnknownAllocation(rpcErr) || structs.IsErrNoSuchFileOrDirectory(rpcErr) {
-			rpcErr = CodedError(404, rpcErr.Error())
-		}
-
-		return nil, rpcErr
+	if err := s.allocRPC(allocID, "FileSystem.List", &args, &reply); err != nil {
+		return nil, err
 	}
 
 	return reply.Files, nil
@@ -110,24 +107,9 @@ func (s *HTTPServer) FileStatRequest(resp http.ResponseWriter, req *http.Request
 	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
 
 	// Make the RPC
-	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(allocID)
-
 	var reply cstructs.FsStatResponse
-	var rpcErr error
-	if localClient {
-		rpcErr = s.agent.Client().ClientRPC("FileSystem.Stat", &args, &reply)
-	} else if remoteClient {
-		rpcErr = s.agent.Client().RPC("FileSystem.Stat", &args, &reply)
-	} else if localServer {
-		rpcErr = s.agent.Server().RPC("FileSystem.Stat", &args, &reply)
-	}
-
-	if rpcErr != nil {
-		if structs.IsErrNoNodeConn(rpcErr) || structs.IsErrUnknownAllocation(rpcErr) || structs.IsErrNoSuchFileOrDirectory(rpcErr) {
-			rpcErr = CodedError(404, rpcErr.Error())
-		}
-
-		return nil, rpcErr
+	if err := s.allocRPC(allocID, "FileSystem.Stat", &args, &reply); err != nil {
+		return nil, err
 	}
 
 	return reply.Info, nil
@@ -198,6 +180,109 @@ func (s *HTTPServer) FileCatRequest(resp http.ResponseWriter, req *http.Request)
 	return s.fsStreamImpl(resp, req, "FileSystem.Stream", fsReq, fsReq.AllocID)
 }
 
+// FileUploadRequest writes the request body to a file of an allocation, or
+// extracts it into a directory. The parameters are:
+//   - path: path of the file to write, or of the directory to extract into.
+//   - archive: A boolean of whether the body is a tar archive, optionally gzip
+//     compressed, to extract. Defaults to false.
+//   - mode: The octal permissions of the file written. Defaults to 0644.
+func (s *HTTPServer) FileUploadRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var allocID, path string
+	var err error
+
+	if req.Method != http.MethodPut && req.Method != http.MethodPost {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	q := req.URL.Query()
+	if allocID = strings.TrimPrefix(req.URL.Path, "/v1/client/fs/upload/"); allocID == "" {
+		return nil, allocIDNotPresentErr
+	}
+	if path = q.Get("path"); path == "" {
+		return nil, fileNameNotPresentErr
+	}
+
+	var archive bool
+	if archiveStr := q.Get("archive"); archiveStr != "" {
+		if archive, err = strconv.ParseBool(archiveStr); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("failed to parse archive field to boolean: %v", err))
+		}
+	}
+
+	var mode uint64
+	if modeStr := q.Get("mode"); modeStr != "" {
+		if mode, err = strconv.ParseUint(modeStr, 8, 32); err != nil || mode > 0777 {
+			return nil, CodedError(400, fmt.Sprintf("invalid file mode %q", modeStr))
+		}
+	}
+
+	// Create the request arguments
+	fsReq := &cstructs.FsUploadRequest{
+		AllocID:  allocID,
+		Path:     path,
+		Archive:  archive,
+		FileMode: uint32(mode),
+	}
+	s.parse(resp, req, &fsReq.QueryOptions.Region, &fsReq.QueryOptions)
+
+	return nil, s.fsUploadImpl(req.Context(), fsReq, req.Body)
+}
+
+// FileRemoveRequest removes a file or directory of an allocation.
+func (s *HTTPServer) FileRemoveRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var allocID, path string
+
+	if req.Method != http.MethodDelete {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	if allocID = strings.TrimPrefix(req.URL.Path, "/v1/client/fs/rm/"); allocID == "" {
+		return nil, allocIDNotPresentErr
+	}
+	if path = req.URL.Query().Get("path"); path == "" {
+		return nil, fileNameNotPresentErr
+	}
+
+	// Create the request
+	args := &cstructs.FsRemoveRequest{
+		AllocID: allocID,
+		Path:    path,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Make the RPC
+	var reply cstructs.FsRemoveResponse
+	if err := s.allocRPC(allocID, "FileSystem.Remove", &args, &reply); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// FileArchiveRequest streams a gzip compressed tar archive of a file or
+// directory of an allocation.
+func (s *HTTPServer) FileArchiveRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var allocID, path string
+
+	if allocID = strings.TrimPrefix(req.URL.Path, "/v1/client/fs/archive/"); allocID == "" {
+		return nil, allocIDNotPresentErr
+	}
+	if path = req.URL.Query().Get("path"); path == "" {
+		return nil, fileNameNotPresentErr
+	}
+
+	// Create the request arguments
+	fsReq := &cstructs.FsArchiveRequest{
+		AllocID: allocID,
+		Path:    path,
+	}
+	s.parse(resp, req, &fsReq.QueryOptions.Region, &fsReq.QueryOptions)
+
+	resp.Header().Set("Content-Type", "application/gzip")
+
+	// Make the request
+	return s.fsStreamImpl(resp, req, "FileSystem.Archive", fsReq, fsReq.AllocID)
+}
+
 // Stream streams the content of a file blocking on EOF.
 // The parameters are:
 //   - path: path to file to stream.
@@ -345,6 +430,21 @@ func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interfac
 func (s *HTTPServer) fsStreamImpl(resp http.ResponseWriter,
 	req *http.Request, method string, args interface{}, allocID string) (interface{}, error) {
 
+	// Create an output that gets flushed on every write
+	output := ioutils.NewWriteFlusher(resp)
+
+	if err := s.allocStreamImpl(req.Context(), method, args, allocID, output); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// allocStreamImpl makes a streaming filesystem call for an allocation and
+// copies the payload of the results to output until the stream ends or the
+// context is canceled.
+func (s *HTTPServer) allocStreamImpl(ctx context.Context, method string,
+	args interface{}, allocID string, output io.Writer) error {
+
 	// Get the correct handler
 	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(allocID)
 	var handler structs.StreamingRpcHandler
@@ -358,24 +458,30 @@ func (s *HTTPServer) fsStreamImpl(resp http.ResponseWriter,
 	}
 
 	if handlerErr != nil {
-		return nil, CodedError(500, handlerErr.Error())
+		return CodedError(500, handlerErr.Error())
 	}
 
-	// Create a pipe connecting the (possibly remote) handler to the http response
+	return streamPayloadImpl(ctx, handler, args, output)
+}
+
+// streamPayloadImpl sends args to a streaming handler and copies the payload
+// of the StreamErrWrapper results to output until the stream ends or the
+// context is canceled.
+func streamPayloadImpl(ctx context.Context, handler structs.StreamingRpcHandler,
+	args interface{}, output io.Writer) error {
+
+	// Create a pipe connecting the (possibly remote) handler to the output
 	httpPipe, handlerPipe := net.Pipe()
 	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
 	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
 
 	// Create a goroutine that closes the pipe if the connection closes.
-	ctx, cancel := context.WithCancel(req.Context())
+	ctx, cancel := context.WithCancel(ctx)
 	go func() {
 		<-ctx.Done()
 		httpPipe.Close()
 	}()
 
-	// Create an output that gets flushed on every write
-	output := ioutils.NewWriteFlusher(resp)
-
 	// Create a channel that decodes the results
 	errCh := make(chan HTTPCodedError)
 	go func() {
@@ -424,11 +530,122 @@ func (s *HTTPServer) fsStreamImpl(resp http.ResponseWriter,
 	codedErr := <-errCh
 
 	// Ignore EOF and ErrClosedPipe errors.
-	if codedErr != nil &&
-		(codedErr == io.EOF ||
-			strings.Contains(codedErr.Error(), "closed") ||
-			strings.Contains(codedErr.Error(), "EOF")) {
-		codedErr = nil
+	if codedErr == nil ||
+		codedErr == io.EOF ||
+		strings.Contains(codedErr.Error(), "closed") ||
+		strings.Contains(codedErr.Error(), "EOF") {
+		return nil
+	}
+	return codedErr
+}
+
+// fsUploadImpl sends the content read from body to the FileSystem.Upload
+// streaming call and waits for the result of the upload.
+func (s *HTTPServer) fsUploadImpl(ctx context.Context, args *cstructs.FsUploadRequest, body io.Reader) error {
+	method := "FileSystem.Upload"
+
+	// Get the correct handler
+	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(args.AllocID)
+	var handler structs.StreamingRpcHandler
+	var handlerErr error
+	if localClient {
+		handler, handlerErr = s.agent.Client().StreamingRpcHandler(method)
+	} else if remoteClient {
+		handler, handlerErr = s.agent.Client().RemoteStreamingRpcHandler(method)
+	} else if localServer {
+		handler, handlerErr = s.agent.Server().StreamingRpcHandler(method)
+	}
+
+	if handlerErr != nil {
+		return CodedError(500, handlerErr.Error())
+	}
+
+	// Create a pipe connecting the (possibly remote) handler to the request
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	// Create a goroutine that closes the pipe if the connection closes.
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+	}()
+
+	// Send the request and the content, which is ended by an empty frame
+	go func() {
+		if err := encoder.Encode(args); err != nil {
+			return
+		}
+
+		buf := make([]byte, fsUploadFrameSize)
+		for {
+			n, err := body.Read(buf)
+			if n > 0 {
+				if err := encoder.Encode(&cstructs.StreamErrWrapper{Payload: buf[:n]}); err != nil {
+					return
+				}
+			}
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				// Closing the pipe fails the upload
+				cancel()
+				return
+			}
+		}
+		encoder.Encode(&cstructs.StreamErrWrapper{})
+	}()
+
+	// Wait for the result of the upload
+	errCh := make(chan HTTPCodedError, 1)
+	go func() {
+		defer cancel()
+
+		var res cstructs.StreamErrWrapper
+		if err := decoder.Decode(&res); err != nil {
+			errCh <- CodedError(500, fmt.Sprintf("upload failed: %v", err))
+			return
+		}
+		if err := res.Error; err != nil {
+			code := 500
+			if err.Code != nil {
+				code = int(*err.Code)
+			}
+			errCh <- CodedError(code, err.Error())
+			return
+		}
+		errCh <- nil
+	}()
+
+	handler(handlerPipe)
+	cancel()
+	if codedErr := <-errCh; codedErr != nil {
+		return codedErr
+	}
+	return nil
+}
+
+// allocRPC makes a non-streaming filesystem call for an allocation, using
+// the local client if it runs the allocation. Errors for unknown allocations
+// and files are returned as 404s.
+func (s *HTTPServer) allocRPC(allocID, method string, args, reply interface{}) error {
+	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(allocID)
+
+	var rpcErr error
+	if localClient {
+		rpcErr = s.agent.Client().ClientRPC(method, args, reply)
+	} else if remoteClient {
+		rpcErr = s.agent.Client().RPC(method, args, reply)
+	} else if localServer {
+		rpcErr = s.agent.Server().RPC(method, args, reply)
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) || structs.IsErrUnknownAllocation(rpcErr) || structs.IsErrNoSuchFileOrDirectory(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+		return rpcErr
 	}
-	return nil, codedErr
+	return nil
 }
@@ -4,6 +4,8 @@
 package agent
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -19,6 +21,7 @@ import (
 	"github.com/open-wander/wander/nomad/mock"
 	"github.com/open-wander/wander/nomad/structs"
 	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/require"
 )
 
@@ -401,6 +404,105 @@ func TestHTTP_FS_Cat_XSS(t *testing.T) {
 	})
 }
 
+func TestHTTP_FS_Upload_MissingParams(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		req, err := http.NewRequest(http.MethodPut, "/v1/client/fs/upload/", nil)
+		must.NoError(t, err)
+		_, err = s.Server.FileUploadRequest(httptest.NewRecorder(), req)
+		must.EqError(t, err, allocIDNotPresentErr.Error())
+
+		req, err = http.NewRequest(http.MethodPut, "/v1/client/fs/upload/foo", nil)
+		must.NoError(t, err)
+		_, err = s.Server.FileUploadRequest(httptest.NewRecorder(), req)
+		must.EqError(t, err, fileNameNotPresentErr.Error())
+
+		for _, query := range []string{"archive=maybe", "mode=999", "mode=01777"} {
+			req, err = http.NewRequest(http.MethodPut, "/v1/client/fs/upload/foo?path=file&"+query, nil)
+			must.NoError(t, err)
+			_, err = s.Server.FileUploadRequest(httptest.NewRecorder(), req)
+			must.Error(t, err)
+
+			codedErr, ok := err.(HTTPCodedError)
+			must.True(t, ok)
+			must.Eq(t, 400, codedErr.Code())
+		}
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/client/fs/upload/foo?path=file", nil)
+		must.NoError(t, err)
+		_, err = s.Server.FileUploadRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, ErrInvalidMethod)
+
+		req, err = http.NewRequest(http.MethodDelete, "/v1/client/fs/rm/foo", nil)
+		must.NoError(t, err)
+		_, err = s.Server.FileRemoveRequest(httptest.NewRecorder(), req)
+		must.EqError(t, err, fileNameNotPresentErr.Error())
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/client/fs/archive/foo", nil)
+		must.NoError(t, err)
+		_, err = s.Server.FileArchiveRequest(httptest.NewRecorder(), req)
+		must.EqError(t, err, fileNameNotPresentErr.Error())
+	})
+}
+
+func TestHTTP_FS_Upload(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		a := mockFSAlloc(s.client.NodeID(), map[string]interface{}{"run_for": "20s"})
+		addAllocToClient(s, a, runningClientAlloc)
+
+		// Upload a file larger than a frame
+		content := strings.Repeat("config\n", fsUploadFrameSize/4)
+		path := fmt.Sprintf("/v1/client/fs/upload/%s?path=alloc/data/app.conf&mode=0600", a.ID)
+		req, err := http.NewRequest(http.MethodPut, path, strings.NewReader(content))
+		must.NoError(t, err)
+		_, err = s.Server.FileUploadRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+
+		// Download the directory as an archive
+		path = fmt.Sprintf("/v1/client/fs/archive/%s?path=alloc/data", a.ID)
+		req, err = http.NewRequest(http.MethodGet, path, nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.FileArchiveRequest(respW, req)
+		must.NoError(t, err)
+		must.Eq(t, "application/gzip", respW.Header().Get("Content-Type"))
+
+		gr, err := gzip.NewReader(respW.Body)
+		must.NoError(t, err)
+		tr := tar.NewReader(gr)
+		hdr, err := tr.Next()
+		must.NoError(t, err)
+		must.Eq(t, "app.conf", hdr.Name)
+		must.Eq(t, 0600, hdr.Mode)
+		data, err := io.ReadAll(tr)
+		must.NoError(t, err)
+		must.Eq(t, content, string(data))
+		_, err = tr.Next()
+		must.Eq(t, io.EOF, err)
+
+		// Remove the file
+		path = fmt.Sprintf("/v1/client/fs/rm/%s?path=alloc/data/app.conf", a.ID)
+		req, err = http.NewRequest(http.MethodDelete, path, nil)
+		must.NoError(t, err)
+		_, err = s.Server.FileRemoveRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+
+		path = fmt.Sprintf("/v1/client/fs/stat/%s?path=alloc/data/app.conf", a.ID)
+		req, err = http.NewRequest(http.MethodGet, path, nil)
+		must.NoError(t, err)
+		_, err = s.Server.FileStatRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "no such file")
+
+		// Uploads into the secrets directory are rejected
+		path = fmt.Sprintf("/v1/client/fs/upload/%s?path=web/secrets/token", a.ID)
+		req, err = http.NewRequest(http.MethodPut, path, strings.NewReader("secret"))
+		must.NoError(t, err)
+		_, err = s.Server.FileUploadRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "prohibited")
+	})
+}
+
 func TestHTTP_FS_Stream_NoFollow(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
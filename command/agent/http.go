@@ -162,6 +162,12 @@ func NewHTTPServers(agent *Agent, config *Config) ([]*HTTPServer, error) {
 				serverInitializationErrors = multierror.Append(serverInitializationErrors, err)
 				continue
 			}
+			// Request, without requiring, a client certificate so that it
+			// can be used to log in via TLSCert ACL auth methods. It is only
+			// trusted once validated by the auth method.
+			if tlsConfig.ClientAuth == tls.NoClientCert {
+				tlsConfig.ClientAuth = tls.RequestClientCert
+			}
 			ln = tls.NewListener(tcpKeepAliveListener{ln.(*net.TCPListener)}, tlsConfig)
 		}
 
@@ -397,6 +403,9 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/evaluations/count", s.wrap(s.EvalsCountRequest))
 	s.mux.HandleFunc("/v1/evaluation/", s.wrap(s.EvalSpecificRequest))
 
+	s.mux.HandleFunc("/v1/exec-sessions", s.wrap(s.ExecSessionsRequest))
+	s.mux.HandleFunc("/v1/exec-session/", s.wrap(s.ExecSessionSpecificRequest))
+
 	s.mux.HandleFunc("/v1/deployments", s.wrap(s.DeploymentsRequest))
 	s.mux.HandleFunc("/v1/deployment/", s.wrap(s.DeploymentSpecificRequest))
 
@@ -442,6 +451,7 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.Handle("/v1/client/stats", wrapCORS(s.wrap(s.ClientStatsRequest)))
 	s.mux.Handle("/v1/client/allocation/", wrapCORS(s.wrap(s.ClientAllocRequest)))
 	s.mux.Handle("/v1/client/metadata", wrapCORS(s.wrap(s.NodeMetaRequest)))
+	s.mux.Handle("/v1/client/artifact-cache", wrapCORS(s.wrap(s.ArtifactCacheRequest)))
 
 	s.mux.HandleFunc("/v1/agent/self", s.wrap(s.AgentSelfRequest))
 	s.mux.HandleFunc("/v1/agent/join", s.wrap(s.AgentJoinRequest))
@@ -484,6 +494,8 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/operator/license", s.wrap(s.LicenseRequest))
 	s.mux.HandleFunc("/v1/operator/raft/", s.wrap(s.OperatorRequest))
 	s.mux.HandleFunc("/v1/operator/keyring/", s.wrap(s.KeyringRequest))
+	s.mux.HandleFunc(structs.JWKSPath, s.wrap(s.JWKSRequest))
+	s.mux.HandleFunc(structs.OIDCDiscoveryPath, s.wrap(s.OIDCDiscoveryRequest))
 	s.mux.HandleFunc("/v1/operator/autopilot/configuration", s.wrap(s.OperatorAutopilotConfiguration))
 	s.mux.HandleFunc("/v1/operator/autopilot/health", s.wrap(s.OperatorServerHealth))
 	s.mux.HandleFunc("/v1/operator/snapshot", s.wrap(s.SnapshotRequest))
@@ -737,6 +749,7 @@ func (s *HTTPServer) wrap(handler func(resp http.ResponseWriter, req *http.Reque
 				}
 			}
 
+			setRetryAfter(resp, code)
 			resp.WriteHeader(code)
 			resp.Write([]byte(errMsg))
 			if isAPIClientError(code) {
@@ -795,6 +808,7 @@ func (s *HTTPServer) wrapNonJSON(handler func(resp http.ResponseWriter, req *htt
 		// Check for an error
 		if err != nil {
 			code, errMsg := errCodeFromHandler(err)
+			setRetryAfter(resp, code)
 			resp.WriteHeader(code)
 			resp.Write([]byte(errMsg))
 			if isAPIClientError(code) {
@@ -813,6 +827,14 @@ func (s *HTTPServer) wrapNonJSON(handler func(resp http.ResponseWriter, req *htt
 	return f
 }
 
+// setRetryAfter tells clients when to retry requests rejected by the RPC rate
+// limits of the servers.
+func setRetryAfter(resp http.ResponseWriter, code int) {
+	if code == http.StatusTooManyRequests {
+		resp.Header().Set("Retry-After", "1")
+	}
+}
+
 // isAPIClientError returns true if the passed http code represents a client error
 func isAPIClientError(code int) bool {
 	return 400 <= code && code <= 499
@@ -314,6 +314,24 @@ func TestWrapNonJSON_Error(t *testing.T) {
 
 }
 
+func TestWrap_RateLimited(t *testing.T) {
+	ci.Parallel(t)
+	s := makeHTTPServer(t, nil)
+	defer s.Shutdown()
+
+	handler := func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+		return nil, structs.NewErrRateLimited("token")
+	}
+
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/jobs", nil)
+	s.Server.wrap(handler)(resp, req)
+	respBody, _ := io.ReadAll(resp.Body)
+	must.Eq(t, "token rate limit exceeded", string(respBody))
+	must.Eq(t, http.StatusTooManyRequests, resp.Code)
+	must.Eq(t, "1", resp.Header().Get("Retry-After"))
+}
+
 func TestPrettyPrint(t *testing.T) {
 	ci.Parallel(t)
 	testPrettyPrint("pretty=1", true, t)
@@ -77,12 +77,21 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/periodic/force"):
 		jobID := strings.TrimSuffix(path, "/periodic/force")
 		return s.periodicForceRequest(resp, req, jobID)
+	case strings.HasSuffix(path, "/periodic/history"):
+		jobID := strings.TrimSuffix(path, "/periodic/history")
+		return s.periodicHistoryRequest(resp, req, jobID)
 	case strings.HasSuffix(path, "/plan"):
 		jobID := strings.TrimSuffix(path, "/plan")
 		return s.jobPlan(resp, req, jobID)
 	case strings.HasSuffix(path, "/summary"):
 		jobID := strings.TrimSuffix(path, "/summary")
 		return s.jobSummaryRequest(resp, req, jobID)
+	case strings.HasSuffix(path, "/dependencies"):
+		jobID := strings.TrimSuffix(path, "/dependencies")
+		return s.jobDependenciesRequest(resp, req, jobID)
+	case strings.HasSuffix(path, "/explain"):
+		jobID := strings.TrimSuffix(path, "/explain")
+		return s.jobExplainRequest(resp, req, jobID)
 	case strings.HasSuffix(path, "/dispatch"):
 		jobID := strings.TrimSuffix(path, "/dispatch")
 		return s.jobDispatchRequest(resp, req, jobID)
@@ -110,6 +119,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/submission"):
 		jobID := strings.TrimSuffix(path, "/submission")
 		return s.jobSubmissionCRUD(resp, req, jobID)
+	case strings.HasSuffix(path, "/logs"):
+		jobID := strings.TrimSuffix(path, "/logs")
+		return s.jobLogs(resp, req, jobID)
 	default:
 		return s.jobCRUD(resp, req, path)
 	}
@@ -236,6 +248,55 @@ func (s *HTTPServer) periodicForceRequest(resp http.ResponseWriter, req *http.Re
 	return out, nil
 }
 
+func (s *HTTPServer) periodicHistoryRequest(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.PeriodicHistoryRequest{
+		JobID: jobName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.PeriodicHistoryResponse
+	if err := s.agent.RPC("Periodic.History", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Children == nil {
+		out.Children = make([]*structs.PeriodicChild, 0)
+	}
+	return out, nil
+}
+
+func (s *HTTPServer) jobDependenciesRequest(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.JobSpecificRequest{
+		JobID: jobID,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobDependenciesResponse
+	if err := s.agent.RPC("Job.Dependencies", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Run == nil {
+		return nil, CodedError(404, "job has no dependencies")
+	}
+	return out.Run, nil
+}
+
 func (s *HTTPServer) jobAllocations(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
 	if req.Method != http.MethodGet {
 		return nil, CodedError(405, ErrInvalidMethod)
@@ -723,6 +784,28 @@ func (s *HTTPServer) jobSummaryRequest(resp http.ResponseWriter, req *http.Reque
 	return out.JobSummary, nil
 }
 
+func (s *HTTPServer) jobExplainRequest(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.JobExplainRequest{
+		JobID:     jobID,
+		TaskGroup: req.URL.Query().Get("task_group"),
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobExplainResponse
+	if err := s.agent.RPC("Job.Explain", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	return out, nil
+}
+
 func (s *HTTPServer) jobDispatchRequest(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
 	if req.Method != "PUT" && req.Method != "POST" {
 		return nil, CodedError(405, ErrInvalidMethod)
@@ -765,7 +848,7 @@ func (s *HTTPServer) JobsParseRequest(resp http.ResponseWriter, req *http.Reques
 	// Check job parse permissions
 	if aclObj != nil {
 		hasParseJob := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityParseJob)
-		hasSubmitJob := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilitySubmitJob)
+		hasSubmitJob := aclObj.AllowJobSearch(namespace, acl.NamespaceCapabilitySubmitJob)
 
 		allowed := hasParseJob || hasSubmitJob
 		if !allowed {
@@ -1011,6 +1094,14 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		if job.Periodic.Specs != nil {
 			j.Periodic.Specs = job.Periodic.Specs
 		}
+
+		if job.Periodic.Catchup != nil {
+			j.Periodic.Catchup = *job.Periodic.Catchup
+		}
+
+		if job.Periodic.CatchupWindow != nil {
+			j.Periodic.CatchupWindow = *job.Periodic.CatchupWindow
+		}
 	}
 
 	if job.ParameterizedJob != nil {
@@ -1021,6 +1112,15 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		}
 	}
 
+	if l := len(job.DependsOn); l != 0 {
+		j.DependsOn = make([]*structs.JobDependency, l)
+		for i, dep := range job.DependsOn {
+			j.DependsOn[i] = &structs.JobDependency{
+				JobID: dep.JobID,
+			}
+		}
+	}
+
 	if job.Multiregion != nil {
 		j.Multiregion = &structs.Multiregion{}
 		j.Multiregion.Strategy = &structs.MultiregionStrategy{
@@ -1069,6 +1169,10 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		RenderTemplates: *taskGroup.RestartPolicy.RenderTemplates,
 	}
 
+	if taskGroup.RestartPolicy.RestartOnTimeout != nil {
+		tg.RestartPolicy.RestartOnTimeout = *taskGroup.RestartPolicy.RestartOnTimeout
+	}
+
 	if taskGroup.ShutdownDelay != nil {
 		tg.ShutdownDelay = taskGroup.ShutdownDelay
 	}
@@ -1090,6 +1194,10 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 			MaxDelay:      *taskGroup.ReschedulePolicy.MaxDelay,
 			Unlimited:     *taskGroup.ReschedulePolicy.Unlimited,
 		}
+
+		if taskGroup.ReschedulePolicy.RescheduleOnTimeout != nil {
+			tg.ReschedulePolicy.RescheduleOnTimeout = *taskGroup.ReschedulePolicy.RescheduleOnTimeout
+		}
 	}
 
 	if taskGroup.Migrate != nil {
@@ -1200,15 +1308,20 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	structsTask.KillTimeout = *apiTask.KillTimeout
 	structsTask.ShutdownDelay = apiTask.ShutdownDelay
 	structsTask.KillSignal = apiTask.KillSignal
+	structsTask.MaxRunDuration = apiTask.MaxRunDuration
 	structsTask.Kind = structs.TaskKind(apiTask.Kind)
 	structsTask.Constraints = ApiConstraintsToStructs(apiTask.Constraints)
 	structsTask.Affinities = ApiAffinitiesToStructs(apiTask.Affinities)
 	structsTask.CSIPluginConfig = ApiCSIPluginConfigToStructsCSIPluginConfig(apiTask.CSIPluginConfig)
 
 	if apiTask.Identity != nil {
-		structsTask.Identity = &structs.WorkloadIdentity{
-			Env:  apiTask.Identity.Env,
-			File: apiTask.Identity.File,
+		structsTask.Identity = apiWorkloadIdentityToStructs(apiTask.Identity)
+	}
+
+	if len(apiTask.Identities) > 0 {
+		structsTask.Identities = make([]*structs.WorkloadIdentity, len(apiTask.Identities))
+		for i, wi := range apiTask.Identities {
+			structsTask.Identities[i] = apiWorkloadIdentityToStructs(wi)
 		}
 	}
 
@@ -1220,6 +1333,10 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 			Mode:            *apiTask.RestartPolicy.Mode,
 			RenderTemplates: *apiTask.RestartPolicy.RenderTemplates,
 		}
+
+		if apiTask.RestartPolicy.RestartOnTimeout != nil {
+			structsTask.RestartPolicy.RestartOnTimeout = *apiTask.RestartPolicy.RestartOnTimeout
+		}
 	}
 
 	if len(apiTask.VolumeMounts) > 0 {
@@ -1255,14 +1372,23 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	if len(apiTask.Artifacts) > 0 {
 		structsTask.Artifacts = []*structs.TaskArtifact{}
 		for _, ta := range apiTask.Artifacts {
-			structsTask.Artifacts = append(structsTask.Artifacts,
-				&structs.TaskArtifact{
-					GetterSource:  *ta.GetterSource,
-					GetterOptions: maps.Clone(ta.GetterOptions),
-					GetterHeaders: maps.Clone(ta.GetterHeaders),
-					GetterMode:    *ta.GetterMode,
-					RelativeDest:  *ta.RelativeDest,
-				})
+			artifact := &structs.TaskArtifact{
+				GetterSource:  *ta.GetterSource,
+				GetterOptions: maps.Clone(ta.GetterOptions),
+				GetterHeaders: maps.Clone(ta.GetterHeaders),
+				GetterMode:    *ta.GetterMode,
+				RelativeDest:  *ta.RelativeDest,
+				ReadOnly:      *ta.ReadOnly,
+			}
+			if sig := ta.Signature; sig != nil {
+				artifact.Signature = &structs.ArtifactSignature{
+					Source:   *sig.Source,
+					Format:   *sig.Format,
+					Keys:     slices.Clone(sig.Keys),
+					Variable: *sig.Variable,
+				}
+			}
+			structsTask.Artifacts = append(structsTask.Artifacts, artifact)
 		}
 	}
 
@@ -1274,6 +1400,7 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 			DisableFile:  *apiTask.Vault.DisableFile,
 			ChangeMode:   *apiTask.Vault.ChangeMode,
 			ChangeSignal: *apiTask.Vault.ChangeSignal,
+			Role:         apiTask.Vault.Role,
 		}
 	}
 
@@ -1314,6 +1441,16 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 			Sidecar: apiTask.Lifecycle.Sidecar,
 		}
 	}
+
+	if l := len(apiTask.DependsOn); l != 0 {
+		structsTask.DependsOn = make([]*structs.TaskDependency, l)
+		for i, dep := range apiTask.DependsOn {
+			structsTask.DependsOn[i] = &structs.TaskDependency{
+				Task:      dep.Task,
+				Condition: dep.Condition,
+			}
+		}
+	}
 }
 
 // apiWaitConfigToStructsWaitConfig is a copy and type conversion between the API
@@ -1329,6 +1466,22 @@ func apiWaitConfigToStructsWaitConfig(waitConfig *api.WaitConfig) *structs.WaitC
 	}
 }
 
+// apiWorkloadIdentityToStructs is a copy and type conversion between the API
+// representation of a WorkloadIdentity and its struct representation.
+func apiWorkloadIdentityToStructs(in *api.WorkloadIdentity) *structs.WorkloadIdentity {
+	if in == nil {
+		return nil
+	}
+
+	return &structs.WorkloadIdentity{
+		Name:     in.Name,
+		Audience: slices.Clone(in.Audience),
+		Env:      in.Env,
+		File:     in.File,
+		TTL:      in.TTL,
+	}
+}
+
 func apiChangeScriptToStructsChangeScript(changeScript *api.ChangeScript) *structs.ChangeScript {
 	if changeScript == nil {
 		return nil
@@ -1820,11 +1973,26 @@ func apiLogConfigToStructs(in *api.LogConfig) *structs.LogConfig {
 		return nil
 	}
 
-	return &structs.LogConfig{
+	out := &structs.LogConfig{
 		Disabled:      dereferenceBool(in.Disabled),
 		MaxFiles:      dereferenceInt(in.MaxFiles),
 		MaxFileSizeMB: dereferenceInt(in.MaxFileSizeMB),
 	}
+	if len(in.Sinks) > 0 {
+		out.Sinks = make([]*structs.LogSink, len(in.Sinks))
+		for i, sink := range in.Sinks {
+			out.Sinks[i] = &structs.LogSink{
+				Type:       sink.Type,
+				Address:    sink.Address,
+				Path:       sink.Path,
+				Facility:   sink.Facility,
+				Tag:        sink.Tag,
+				Headers:    maps.Clone(sink.Headers),
+				BufferSize: dereferenceInt(sink.BufferSize),
+			}
+		}
+	}
+	return out
 }
 
 func dereferenceBool(in *bool) bool {
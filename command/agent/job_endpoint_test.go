@@ -1341,6 +1341,92 @@ func TestHTTP_JobEvaluate_ForceReschedule(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobExplain(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the job
+		job := mock.Job()
+		args := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		must.NoError(t, s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest(http.MethodGet, "/v1/job/"+job.ID+"/explain?task_group=web", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		must.NoError(t, err)
+
+		out := obj.(structs.JobExplainResponse)
+		must.MapLen(t, 1, out.TaskGroups)
+		must.MapContainsKey(t, out.TaskGroups, "web")
+		must.NotEq(t, "", respW.Result().Header.Get("X-Nomad-Index"))
+
+		// Unknown task groups return a 404
+		req, err = http.NewRequest(http.MethodGet, "/v1/job/"+job.ID+"/explain?task_group=nope", nil)
+		must.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, `task group "nope" not found`)
+
+		// Only GET is allowed
+		req, err = http.NewRequest(http.MethodPut, "/v1/job/"+job.ID+"/explain", nil)
+		must.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, ErrInvalidMethod)
+	})
+}
+
+func TestHTTP_JobDependencies(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create a job depending on another job
+		job := mock.BatchJob()
+		job.DependsOn = []*structs.JobDependency{{JobID: "extract"}}
+		args := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		must.NoError(t, s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest(http.MethodGet, "/v1/job/"+job.ID+"/dependencies", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		must.NoError(t, err)
+
+		run := obj.(*structs.JobDependencyRun)
+		must.Eq(t, job.ID, run.JobID)
+		must.Eq(t, structs.JobDependencyRunStatusBlocked, run.Status)
+		must.Len(t, 1, run.Upstreams)
+		must.NotEq(t, "", respW.Result().Header.Get("X-Nomad-Index"))
+
+		// Jobs without dependencies return a 404
+		req, err = http.NewRequest(http.MethodGet, "/v1/job/extract/dependencies", nil)
+		must.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "job has no dependencies")
+
+		// Only GET is allowed
+		req, err = http.NewRequest(http.MethodPut, "/v1/job/"+job.ID+"/dependencies", nil)
+		must.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, ErrInvalidMethod)
+	})
+}
+
 func TestHTTP_JobEvaluations(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -1708,6 +1794,47 @@ func TestHTTP_PeriodicForce(t *testing.T) {
 	})
 }
 
+func TestHTTP_PeriodicHistory(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create and register a periodic job.
+		job := mock.PeriodicJob()
+		args := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		must.NoError(t, s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Force a launch so there is a child.
+		forceArgs := structs.PeriodicForceRequest{
+			JobID: job.ID,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var forceResp structs.PeriodicForceResponse
+		must.NoError(t, s.Agent.RPC("Periodic.Force", &forceArgs, &forceResp))
+
+		req, err := http.NewRequest(http.MethodGet, "/v1/job/"+job.ID+"/periodic/history", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.NotEq(t, "", respW.Result().Header.Get("X-Nomad-Index"))
+
+		r := obj.(structs.PeriodicHistoryResponse)
+		must.NotNil(t, r.Periodic)
+		must.Len(t, 1, r.Children)
+		must.StrHasPrefix(t, job.ID+structs.PeriodicLaunchSuffix, r.Children[0].JobID)
+	})
+}
+
 func TestHTTP_JobPlan(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -2475,6 +2602,8 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 			SpecType:        pointer.Of("cron"),
 			ProhibitOverlap: pointer.Of(true),
 			TimeZone:        pointer.Of("test zone"),
+			Catchup:         pointer.Of("all"),
+			CatchupWindow:   pointer.Of(time.Hour),
 		},
 		ParameterizedJob: &api.ParameterizedJobConfig{
 			Payload:      "payload",
@@ -2633,6 +2762,12 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						Leader: true,
 						Driver: "docker",
 						User:   "mary",
+						DependsOn: []*api.TaskDependency{
+							{Task: "task0", Condition: "healthy"},
+						},
+						Identities: []*api.WorkloadIdentity{
+							{Name: "consul", Audience: []string{"consul.io"}, File: true, TTL: time.Hour},
+						},
 						Config: map[string]interface{}{
 							"lol": "code",
 						},
@@ -2791,6 +2926,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							DisableFile:  pointer.Of(false),
 							ChangeMode:   pointer.Of("c"),
 							ChangeSignal: pointer.Of("sighup"),
+							Role:         "nomad-workloads",
 						},
 						Templates: []*api.Template{
 							{
@@ -2887,6 +3023,8 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 			SpecType:        "cron",
 			ProhibitOverlap: true,
 			TimeZone:        "test zone",
+			Catchup:         "all",
+			CatchupWindow:   time.Hour,
 		},
 		ParameterizedJob: &structs.ParameterizedJobConfig{
 			Payload:      "payload",
@@ -3050,6 +3188,12 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						Driver: "docker",
 						Leader: true,
 						User:   "mary",
+						DependsOn: []*structs.TaskDependency{
+							{Task: "task0", Condition: "healthy"},
+						},
+						Identities: []*structs.WorkloadIdentity{
+							{Name: "consul", Audience: []string{"consul.io"}, File: true, TTL: time.Hour},
+						},
 						Config: map[string]interface{}{
 							"lol": "code",
 						},
@@ -3214,6 +3358,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							DisableFile:  false,
 							ChangeMode:   "c",
 							ChangeSignal: "sighup",
+							Role:         "nomad-workloads",
 						},
 						Templates: []*structs.Template{
 							{
@@ -3664,6 +3809,30 @@ func TestConversion_apiLogConfigToStructs(t *testing.T) {
 		MaxFileSizeMB: pointer.Of(8),
 	}))
 
+	must.Eq(t, &structs.LogConfig{
+		MaxFiles:      2,
+		MaxFileSizeMB: 8,
+		Sinks: []*structs.LogSink{
+			{
+				Type:       structs.LogSinkTypeOTLP,
+				Address:    "http://127.0.0.1:4318/v1/logs",
+				Headers:    map[string]string{"Authorization": "Bearer secret"},
+				BufferSize: 64,
+			},
+		},
+	}, apiLogConfigToStructs(&api.LogConfig{
+		MaxFiles:      pointer.Of(2),
+		MaxFileSizeMB: pointer.Of(8),
+		Sinks: []*api.LogSink{
+			{
+				Type:       "otlp",
+				Address:    "http://127.0.0.1:4318/v1/logs",
+				Headers:    map[string]string{"Authorization": "Bearer secret"},
+				BufferSize: pointer.Of(64),
+			},
+		},
+	}))
+
 	// COMPAT(1.6.0): verify backwards compatibility fixes
 	// Note: we're intentionally ignoring the Enabled: false case
 	must.Eq(t, &structs.LogConfig{Disabled: false},
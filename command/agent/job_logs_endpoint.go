@@ -0,0 +1,436 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/open-wander/wander/api"
+	cstructs "github.com/open-wander/wander/client/structs"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+const (
+	// jobLogsHeartbeatInterval is how often an empty object is written to a
+	// followed job logs stream, so clients can detect broken connections.
+	jobLogsHeartbeatInterval = 10 * time.Second
+
+	// jobLogsMaxLineSize is the size after which a log line without a line
+	// break is split into several lines.
+	jobLogsMaxLineSize = 64 * 1024
+
+	// jobLogsMaxStreams is the maximum number of task logs streamed
+	// concurrently for a job logs request. Followed task logs are streamed
+	// until the request ends, so at most this many task logs can be followed.
+	jobLogsMaxStreams = 32
+)
+
+// jobLogsArgs are the parameters of a job logs request.
+type jobLogsArgs struct {
+	group    string
+	task     string
+	logType  string
+	follow   bool
+	regex    *regexp.Regexp
+	contains string
+	since    time.Time
+	all      bool
+}
+
+// match returns whether a log line passes the filters of the request.
+func (a *jobLogsArgs) match(line string) bool {
+	if a.contains != "" && !strings.Contains(line, a.contains) {
+		return false
+	}
+	if a.regex != nil && !a.regex.MatchString(line) {
+		return false
+	}
+	return true
+}
+
+// jobLogStream is the log of a task of an allocation of the job.
+type jobLogStream struct {
+	allocID   string
+	taskGroup string
+	task      string
+}
+
+// jobLogs streams the logs of the tasks of all the allocations of a job as
+// newline delimited JSON log lines. The parameters are:
+//   - group: only stream the logs of the allocations of this task group.
+//   - task: only stream the logs of this task.
+//   - type: stdout/stderr to stream. Defaults to stdout.
+//   - follow: A boolean of whether to follow the logs.
+//   - regex: only stream the lines matching this regular expression.
+//   - contains: only stream the lines containing this string.
+//   - since: only stream the log files written to after this time, either a
+//     duration before now or an RFC3339 timestamp.
+//   - all: include the logs of terminal allocations.
+func (s *HTTPServer) jobLogs(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args, err := parseJobLogsArgs(req.URL.Query(), time.Now())
+	if err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+
+	allocsReq := structs.JobSpecificRequest{
+		JobID: jobID,
+	}
+	if s.parse(resp, req, &allocsReq.Region, &allocsReq.QueryOptions) {
+		return nil, nil
+	}
+
+	var allocsResp structs.JobAllocationsResponse
+	if err := s.agent.RPC("Job.Allocations", &allocsReq, &allocsResp); err != nil {
+		return nil, err
+	}
+
+	streams := jobLogStreams(allocsResp.Allocations, args)
+	if len(streams) == 0 {
+		return nil, CodedError(404, "no task logs found for job")
+	}
+	if args.follow && len(streams) > jobLogsMaxStreams {
+		return nil, CodedError(400, fmt.Sprintf(
+			"cannot follow %d task logs, more than the maximum of %d: filter the logs by task group or task",
+			len(streams), jobLogsMaxStreams))
+	}
+
+	// The log requests only reuse the identity of the caller.
+	var logsQuery structs.QueryOptions
+	logsQuery.Region = allocsReq.Region
+	logsQuery.Namespace = allocsReq.Namespace
+	logsQuery.AuthToken = allocsReq.AuthToken
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Cache-Control", "no-cache")
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	lines := make(chan *api.JobLogLine, len(streams))
+	sem := make(chan struct{}, jobLogsMaxStreams)
+	var wg sync.WaitGroup
+	for _, stream := range streams {
+		wg.Add(1)
+		go func(stream *jobLogStream) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			s.streamJobLog(ctx, stream, args, logsQuery, lines)
+		}(stream)
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	var heartbeat <-chan time.Time
+	if args.follow {
+		ticker := time.NewTicker(jobLogsHeartbeatInterval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	// Create an output that gets flushed on every write
+	enc := json.NewEncoder(ioutils.NewWriteFlusher(resp))
+	for {
+		var err error
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return nil, nil
+			}
+			err = enc.Encode(line)
+		case <-heartbeat:
+			err = enc.Encode(struct{}{})
+		}
+
+		// The client went away, stop streaming.
+		if err != nil {
+			return nil, nil
+		}
+	}
+}
+
+// streamJobLog sends the lines of a task log matching the filters of the
+// request to lines. Errors are sent as lines, so they don't interrupt the
+// logs of the other tasks.
+//
+// Log lines aren't timestamped on disk, so only the lines received while
+// following the log are set the time they were received. When following, the
+// log written so far is read first without setting the time of its lines.
+func (s *HTTPServer) streamJobLog(ctx context.Context, stream *jobLogStream,
+	args *jobLogsArgs, query structs.QueryOptions, lines chan<- *api.JobLogLine) {
+
+	live := false
+	send := func(line *api.JobLogLine) {
+		line.AllocID = stream.allocID
+		line.TaskGroup = stream.taskGroup
+		line.Task = stream.task
+		line.Type = args.logType
+		if live {
+			now := time.Now().UTC()
+			line.Time = &now
+		}
+
+		select {
+		case lines <- line:
+		case <-ctx.Done():
+		}
+	}
+
+	logsReq := &cstructs.FsLogsRequest{
+		AllocID:      stream.allocID,
+		Task:         stream.task,
+		LogType:      args.logType,
+		Origin:       "start",
+		PlainText:    true,
+		Follow:       args.follow,
+		QueryOptions: query,
+	}
+
+	if !args.since.IsZero() {
+		listReq := &cstructs.FsListRequest{
+			AllocID:      stream.allocID,
+			Path:         "alloc/logs",
+			QueryOptions: query,
+		}
+		var listResp cstructs.FsListResponse
+		if err := s.allocRPC(stream.allocID, "FileSystem.List", listReq, &listResp); err != nil {
+			send(&api.JobLogLine{Error: err.Error()})
+			return
+		}
+
+		offset, ok := jobLogsSinceOffset(listResp.Files, stream.task, args.logType, args.since)
+		switch {
+		case ok:
+			logsReq.Offset = offset
+		case args.follow:
+			// Nothing was logged since, only stream the new lines.
+			logsReq.Origin = "end"
+		default:
+			return
+		}
+	}
+
+	w := &jobLogWriter{
+		emit: func(line string) {
+			if args.match(line) {
+				send(&api.JobLogLine{Line: line})
+			}
+		},
+	}
+
+	if args.follow && logsReq.Origin == "start" {
+		// Read the log written so far, then follow it from where the read
+		// ended. The last line is only emitted once followed, as it may not
+		// be complete.
+		readReq := *logsReq
+		readReq.Follow = false
+		if err := s.allocStreamImpl(ctx, "FileSystem.Logs", &readReq, stream.allocID, w); err != nil {
+			if ctx.Err() == nil {
+				send(&api.JobLogLine{Error: err.Error()})
+			}
+			return
+		}
+		logsReq.Offset += w.read
+	}
+
+	live = args.follow
+	err := s.allocStreamImpl(ctx, "FileSystem.Logs", logsReq, stream.allocID, w)
+	w.Flush()
+
+	if err != nil && ctx.Err() == nil {
+		send(&api.JobLogLine{Error: err.Error()})
+	}
+}
+
+// parseJobLogsArgs parses the query parameters of a job logs request. Since
+// durations are relative to now.
+func parseJobLogsArgs(q url.Values, now time.Time) (*jobLogsArgs, error) {
+	args := &jobLogsArgs{
+		group:    q.Get("group"),
+		task:     q.Get("task"),
+		logType:  q.Get("type"),
+		contains: q.Get("contains"),
+	}
+
+	switch args.logType {
+	case "stdout", "stderr":
+	case "":
+		args.logType = "stdout"
+	default:
+		return nil, fmt.Errorf("log type must be stdout or stderr")
+	}
+
+	var err error
+	if followStr := q.Get("follow"); followStr != "" {
+		if args.follow, err = strconv.ParseBool(followStr); err != nil {
+			return nil, fmt.Errorf("failed to parse follow field to boolean: %v", err)
+		}
+	}
+	if allStr := q.Get("all"); allStr != "" {
+		if args.all, err = strconv.ParseBool(allStr); err != nil {
+			return nil, fmt.Errorf("failed to parse all field to boolean: %v", err)
+		}
+	}
+
+	if regex := q.Get("regex"); regex != "" {
+		if args.regex, err = regexp.Compile(regex); err != nil {
+			return nil, fmt.Errorf("invalid regex: %v", err)
+		}
+	}
+
+	if since := q.Get("since"); since != "" {
+		if d, err := time.ParseDuration(since); err == nil {
+			if d < 0 {
+				return nil, fmt.Errorf("since duration must be positive")
+			}
+			args.since = now.Add(-d)
+		} else if args.since, err = time.Parse(time.RFC3339, since); err != nil {
+			return nil, fmt.Errorf("since must be a duration or an RFC3339 timestamp: %q", since)
+		}
+	}
+
+	return args, nil
+}
+
+// jobLogStreams returns the task logs to stream for the allocations of a job,
+// sorted by allocation creation time and task name. Only the tasks which have
+// started may have logs.
+func jobLogStreams(allocs []*structs.AllocListStub, args *jobLogsArgs) []*jobLogStream {
+	allocs = append([]*structs.AllocListStub(nil), allocs...)
+	sort.SliceStable(allocs, func(i, j int) bool {
+		return allocs[i].CreateIndex < allocs[j].CreateIndex
+	})
+
+	var streams []*jobLogStream
+	for _, alloc := range allocs {
+		if args.group != "" && alloc.TaskGroup != args.group {
+			continue
+		}
+		if !args.all {
+			switch alloc.ClientStatus {
+			case structs.AllocClientStatusComplete, structs.AllocClientStatusFailed, structs.AllocClientStatusLost:
+				continue
+			}
+		}
+
+		tasks := make([]string, 0, len(alloc.TaskStates))
+		for task, state := range alloc.TaskStates {
+			if args.task != "" && task != args.task {
+				continue
+			}
+			if state == nil || state.State == structs.TaskStatePending {
+				continue
+			}
+			tasks = append(tasks, task)
+		}
+		sort.Strings(tasks)
+
+		for _, task := range tasks {
+			streams = append(streams, &jobLogStream{
+				allocID:   alloc.ID,
+				taskGroup: alloc.TaskGroup,
+				task:      task,
+			})
+		}
+	}
+	return streams
+}
+
+// jobLogsSinceOffset returns the offset from the start of a task log of the
+// first log file written to at or after since. Log lines aren't timestamped,
+// so the log files are the granularity of the time window. It returns false
+// if no log file was written to since.
+func jobLogsSinceOffset(files []*cstructs.AllocFileInfo, task, logType string, since time.Time) (int64, bool) {
+	prefix := fmt.Sprintf("%s.%s.", task, logType)
+
+	var logs []*cstructs.AllocFileInfo
+	indexes := make(map[*cstructs.AllocFileInfo]int)
+	for _, f := range files {
+		if f.IsDir || !strings.HasPrefix(f.Name, prefix) {
+			continue
+		}
+		idx, err := strconv.Atoi(strings.TrimPrefix(f.Name, prefix))
+		if err != nil {
+			continue
+		}
+		indexes[f] = idx
+		logs = append(logs, f)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return indexes[logs[i]] < indexes[logs[j]]
+	})
+
+	var offset int64
+	for _, f := range logs {
+		if !f.ModTime.Before(since) {
+			return offset, true
+		}
+		offset += f.Size
+	}
+	return 0, false
+}
+
+// jobLogWriter splits the log output written to it into lines. Lines longer
+// than jobLogsMaxLineSize are split.
+type jobLogWriter struct {
+	buf  []byte
+	emit func(line string)
+
+	// read is the number of bytes written
+	read int64
+}
+
+func (w *jobLogWriter) Write(p []byte) (int, error) {
+	w.read += int64(len(p))
+	w.buf = append(w.buf, p...)
+
+	start := 0
+	for {
+		i := bytes.IndexByte(w.buf[start:], '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(strings.TrimSuffix(string(w.buf[start:start+i]), "\r"))
+		start += i + 1
+	}
+	for len(w.buf)-start >= jobLogsMaxLineSize {
+		w.emit(string(w.buf[start : start+jobLogsMaxLineSize]))
+		start += jobLogsMaxLineSize
+	}
+
+	w.buf = append(w.buf[:0], w.buf[start:]...)
+	return len(p), nil
+}
+
+// Flush emits the last line if it wasn't terminated by a line break.
+func (w *jobLogWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.emit(string(w.buf))
+		w.buf = w.buf[:0]
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-wander/wander/api"
+	"github.com/open-wander/wander/ci"
+	cstructs "github.com/open-wander/wander/client/structs"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestHTTP_JobLogs(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		a := mockFSAlloc(s.client.NodeID(), map[string]interface{}{
+			"run_for":       "1ms",
+			"stdout_string": "starting\nerror: disk full\nstopping\n",
+		})
+		addAllocToClient(s, a, terminalClientAlloc)
+
+		// Terminal allocations are skipped by default
+		req, err := http.NewRequest(http.MethodGet, "/v1/job/"+a.JobID+"/logs", nil)
+		must.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "no task logs found")
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/job/"+a.JobID+"/logs?all=true&regex=^error", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.JobSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.Eq(t, "application/json", respW.Header().Get("Content-Type"))
+
+		var lines []*api.JobLogLine
+		scanner := bufio.NewScanner(respW.Body)
+		for scanner.Scan() {
+			var line api.JobLogLine
+			must.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, &line)
+		}
+		must.Len(t, 1, lines)
+		must.Eq(t, a.ID, lines[0].AllocID)
+		must.Eq(t, a.TaskGroup, lines[0].TaskGroup)
+		must.Eq(t, "web", lines[0].Task)
+		must.Eq(t, "stdout", lines[0].Type)
+		must.Eq(t, "error: disk full", lines[0].Line)
+		must.Eq(t, "", lines[0].Error)
+
+		// Lines which were logged before the request are not timestamped
+		must.Nil(t, lines[0].Time)
+	})
+}
+
+func TestHTTP_JobLogs_Follow(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		a := mockFSAlloc(s.client.NodeID(), map[string]interface{}{
+			"run_for":                "30s",
+			"stdout_string":          "tick\n",
+			"stdout_repeat":          300,
+			"stdout_repeat_duration": "100ms",
+		})
+		addAllocToClient(s, a, runningClientAlloc)
+
+		// Let the task log a few lines before following its logs
+		time.Sleep(500 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/v1/job/"+a.JobID+"/logs?follow=true", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.JobSpecificRequest(respW, req)
+		must.NoError(t, err)
+
+		var lines []*api.JobLogLine
+		scanner := bufio.NewScanner(respW.Body)
+		for scanner.Scan() {
+			var line api.JobLogLine
+			must.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			must.Eq(t, "tick", line.Line)
+			lines = append(lines, &line)
+		}
+		must.Greater(t, 2, len(lines))
+
+		// Only the lines logged while following are timestamped
+		must.Nil(t, lines[0].Time)
+		must.NotNil(t, lines[len(lines)-1].Time)
+	})
+}
+
+func TestHTTP_JobLogs_InvalidArgs(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		for _, query := range []string{"type=stdin", "regex=(", "since=yesterday", "follow=maybe"} {
+			req, err := http.NewRequest(http.MethodGet, "/v1/job/example/logs?"+query, nil)
+			must.NoError(t, err)
+			_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+			must.Error(t, err)
+
+			codedErr, ok := err.(HTTPCodedError)
+			must.True(t, ok)
+			must.Eq(t, 400, codedErr.Code())
+		}
+	})
+}
+
+func TestJobLogs_ParseArgs(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	args, err := parseJobLogsArgs(url.Values{}, now)
+	must.NoError(t, err)
+	must.Eq(t, "stdout", args.logType)
+	must.False(t, args.follow)
+	must.Nil(t, args.regex)
+	must.True(t, args.since.IsZero())
+
+	args, err = parseJobLogsArgs(url.Values{
+		"group":    {"cache"},
+		"task":     {"redis"},
+		"type":     {"stderr"},
+		"follow":   {"true"},
+		"all":      {"true"},
+		"regex":    {"^WARN"},
+		"contains": {"timeout"},
+		"since":    {"90m"},
+	}, now)
+	must.NoError(t, err)
+	must.Eq(t, "cache", args.group)
+	must.Eq(t, "redis", args.task)
+	must.Eq(t, "stderr", args.logType)
+	must.True(t, args.follow)
+	must.True(t, args.all)
+	must.Eq(t, now.Add(-90*time.Minute), args.since)
+	must.True(t, args.match("WARN request timeout"))
+	must.False(t, args.match("WARN disk full"))
+	must.False(t, args.match("INFO request timeout"))
+
+	args, err = parseJobLogsArgs(url.Values{"since": {"2023-06-01T10:00:00Z"}}, now)
+	must.NoError(t, err)
+	must.Eq(t, now.Add(-2*time.Hour), args.since.UTC())
+
+	_, err = parseJobLogsArgs(url.Values{"since": {"-1h"}}, now)
+	must.ErrorContains(t, err, "must be positive")
+}
+
+func TestJobLogs_Streams(t *testing.T) {
+	ci.Parallel(t)
+
+	running := &structs.TaskState{State: structs.TaskStateRunning}
+	pending := &structs.TaskState{State: structs.TaskStatePending}
+	allocs := []*structs.AllocListStub{
+		{
+			ID:           "b",
+			TaskGroup:    "web",
+			ClientStatus: structs.AllocClientStatusRunning,
+			CreateIndex:  20,
+			TaskStates:   map[string]*structs.TaskState{"server": running, "sidecar": running},
+		},
+		{
+			ID:           "a",
+			TaskGroup:    "web",
+			ClientStatus: structs.AllocClientStatusRunning,
+			CreateIndex:  10,
+			TaskStates:   map[string]*structs.TaskState{"server": running, "init": pending},
+		},
+		{
+			ID:           "c",
+			TaskGroup:    "cache",
+			ClientStatus: structs.AllocClientStatusComplete,
+			CreateIndex:  5,
+			TaskStates:   map[string]*structs.TaskState{"redis": running},
+		},
+	}
+
+	ids := func(streams []*jobLogStream) []string {
+		var out []string
+		for _, s := range streams {
+			out = append(out, s.allocID+"/"+s.task)
+		}
+		return out
+	}
+
+	must.Eq(t, []string{"a/server", "b/server", "b/sidecar"},
+		ids(jobLogStreams(allocs, &jobLogsArgs{})))
+	must.Eq(t, []string{"c/redis", "a/server", "b/server", "b/sidecar"},
+		ids(jobLogStreams(allocs, &jobLogsArgs{all: true})))
+	must.Eq(t, []string{"c/redis"},
+		ids(jobLogStreams(allocs, &jobLogsArgs{all: true, group: "cache"})))
+	must.Eq(t, []string{"a/server", "b/server"},
+		ids(jobLogStreams(allocs, &jobLogsArgs{task: "server"})))
+}
+
+func TestJobLogs_SinceOffset(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+	files := []*cstructs.AllocFileInfo{
+		{Name: "web.stdout.10", Size: 5, ModTime: now},
+		{Name: "web.stdout.2", Size: 100, ModTime: now.Add(-3 * time.Hour)},
+		{Name: "web.stdout.9", Size: 50, ModTime: now.Add(-30 * time.Minute)},
+		{Name: "web.stderr.9", Size: 7, ModTime: now.Add(-2 * time.Hour)},
+		{Name: "sidecar.stdout.0", Size: 1, ModTime: now.Add(-2 * time.Hour)},
+		{Name: "web.stdout.tmp", Size: 1, ModTime: now.Add(-2 * time.Hour)},
+	}
+
+	offset, ok := jobLogsSinceOffset(files, "web", "stdout", now.Add(-4*time.Hour))
+	must.True(t, ok)
+	must.Eq(t, 0, offset)
+
+	offset, ok = jobLogsSinceOffset(files, "web", "stdout", now.Add(-time.Hour))
+	must.True(t, ok)
+	must.Eq(t, 100, offset)
+
+	offset, ok = jobLogsSinceOffset(files, "web", "stdout", now.Add(-time.Minute))
+	must.True(t, ok)
+	must.Eq(t, 150, offset)
+
+	_, ok = jobLogsSinceOffset(files, "web", "stderr", now.Add(-time.Hour))
+	must.False(t, ok)
+}
+
+func TestJobLogs_Writer(t *testing.T) {
+	ci.Parallel(t)
+
+	var lines []string
+	w := &jobLogWriter{emit: func(line string) { lines = append(lines, line) }}
+
+	w.Write([]byte("first\r\nsec"))
+	must.Eq(t, []string{"first"}, lines)
+
+	w.Write([]byte("ond\n\nthird"))
+	must.Eq(t, []string{"first", "second", ""}, lines)
+
+	long := strings.Repeat("x", jobLogsMaxLineSize+10)
+	w.Write([]byte("\n" + long))
+	must.Eq(t, []string{"first", "second", "", "third", long[:jobLogsMaxLineSize]}, lines)
+
+	w.Flush()
+	must.Eq(t, long[jobLogsMaxLineSize:], lines[len(lines)-1])
+	must.Len(t, 6, lines)
+
+	w.Flush()
+	must.Len(t, 6, lines)
+	must.Eq(t, int64(len("first\r\nsecond\n\nthird\n")+len(long)), w.read)
+}
@@ -4,8 +4,11 @@
 package agent
 
 import (
+	"encoding/base64"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/open-wander/wander/nomad/structs"
 )
@@ -95,3 +98,99 @@ func (s *HTTPServer) keyringDeleteRequest(resp http.ResponseWriter, req *http.Re
 	setIndex(resp, out.Index)
 	return out, nil
 }
+
+// oidcMaxAge is the longest time for which the JWKS and OIDC discovery
+// documents may be cached. Keys can be rotated manually at any time, so the
+// JWKS is never cached longer than this even if the next automatic rotation
+// is further away.
+const oidcMaxAge = time.Hour
+
+// jsonWebKey is the JSON Web Key representation of a public key, as described
+// by RFC 7517 and RFC 8037.
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+}
+
+// jsonWebKeySet is a JSON Web Key Set, as described by RFC 7517.
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// JWKSRequest is used to list the public keys that verify the signature of
+// workload identities.
+func (s *HTTPServer) JWKSRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.GenericRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.KeyringListPublicResponse
+	if err := s.agent.RPC("Keyring.ListPublic", &args, &out); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &out.QueryMeta)
+
+	var newestKey int64
+	jwks := jsonWebKeySet{Keys: make([]jsonWebKey, 0, len(out.PublicKeys))}
+	for _, pubKey := range out.PublicKeys {
+		if pubKey.Algorithm != structs.PubKeyAlgEdDSA {
+			return nil, CodedError(500, fmt.Sprintf("unknown public key algorithm %q", pubKey.Algorithm))
+		}
+		if pubKey.CreateTime > newestKey {
+			newestKey = pubKey.CreateTime
+		}
+		jwks.Keys = append(jwks.Keys, jsonWebKey{
+			KeyType:   "OKP",
+			Curve:     "Ed25519",
+			X:         base64.RawURLEncoding.EncodeToString(pubKey.PublicKey),
+			KeyID:     pubKey.KeyID,
+			Use:       pubKey.Use,
+			Algorithm: pubKey.Algorithm,
+		})
+	}
+
+	// The key set changes when the newest key is rotated, so don't let it be
+	// cached past the next automatic rotation.
+	maxAge := oidcMaxAge
+	if newestKey > 0 && out.RotationThreshold > 0 {
+		rotation := time.Unix(0, newestKey).Add(out.RotationThreshold)
+		maxAge = min(maxAge, max(time.Until(rotation), 0))
+	}
+	resp.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+
+	return jwks, nil
+}
+
+// OIDCDiscoveryRequest is used to retrieve the OIDC discovery document of
+// workload identities. It is only available when the servers are configured
+// with an issuer.
+func (s *HTTPServer) OIDCDiscoveryRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.GenericRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.KeyringGetConfigResponse
+	if err := s.agent.RPC("Keyring.GetConfig", &args, &out); err != nil {
+		return nil, err
+	}
+	if out.OIDCDiscovery == nil {
+		return nil, CodedError(404, "OIDC discovery is disabled: no oidc_issuer configured")
+	}
+
+	resp.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(oidcMaxAge.Seconds())))
+	return out.OIDCDiscovery, nil
+}
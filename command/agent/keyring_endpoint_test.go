@@ -4,10 +4,14 @@
 package agent
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-wander/wander/ci"
@@ -77,3 +81,69 @@ func TestHTTP_Keyring_CRUD(t *testing.T) {
 		require.Len(t, listResp, 1)
 	})
 }
+
+func TestHTTP_Keyring_JWKS(t *testing.T) {
+	ci.Parallel(t)
+
+	httpACLTest(t, nil, func(s *TestAgent) {
+
+		// The JWKS is public and served without an ACL token
+		respW := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodGet, structs.JWKSPath, nil)
+		must.NoError(t, err)
+		s.Server.mux.ServeHTTP(respW, req)
+		must.Eq(t, http.StatusOK, respW.Code)
+		must.StrHasPrefix(t, "public, max-age=", respW.Header().Get("Cache-Control"))
+
+		var jwks struct {
+			Keys []map[string]string `json:"keys"`
+		}
+		must.NoError(t, json.Unmarshal(respW.Body.Bytes(), &jwks))
+		must.Len(t, 1, jwks.Keys)
+
+		key := jwks.Keys[0]
+		must.Eq(t, "OKP", key["kty"])
+		must.Eq(t, "Ed25519", key["crv"])
+		must.Eq(t, "EdDSA", key["alg"])
+		must.Eq(t, "sig", key["use"])
+		must.NotEq(t, "", key["kid"])
+
+		x, err := base64.RawURLEncoding.DecodeString(key["x"])
+		must.NoError(t, err)
+		must.Len(t, ed25519.PublicKeySize, x)
+
+		req, err = http.NewRequest(http.MethodPost, structs.JWKSPath, nil)
+		must.NoError(t, err)
+		_, err = s.Server.JWKSRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, ErrInvalidMethod)
+	})
+}
+
+func TestHTTP_Keyring_OIDCDiscovery(t *testing.T) {
+	ci.Parallel(t)
+
+	// Discovery is unavailable without an issuer
+	httpTest(t, nil, func(s *TestAgent) {
+		req, err := http.NewRequest(http.MethodGet, structs.OIDCDiscoveryPath, nil)
+		must.NoError(t, err)
+		_, err = s.Server.OIDCDiscoveryRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "no oidc_issuer configured")
+	})
+
+	httpTest(t, func(c *Config) {
+		c.Server.OIDCIssuer = "https://nomad.example.com"
+	}, func(s *TestAgent) {
+		respW := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodGet, structs.OIDCDiscoveryPath, nil)
+		must.NoError(t, err)
+		s.Server.mux.ServeHTTP(respW, req)
+		must.Eq(t, http.StatusOK, respW.Code)
+		must.Eq(t, "public, max-age=3600", respW.Header().Get("Cache-Control"))
+
+		var disco map[string]any
+		must.NoError(t, json.Unmarshal(respW.Body.Bytes(), &disco))
+		must.Eq(t, "https://nomad.example.com", disco["issuer"])
+		must.Eq(t, "https://nomad.example.com/.well-known/jwks.json", disco["jwks_uri"])
+		must.Eq[any](t, []any{"EdDSA"}, disco["id_token_signing_alg_values_supported"])
+	})
+}
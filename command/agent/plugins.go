@@ -11,8 +11,13 @@ import (
 	"github.com/open-wander/wander/helper/pluginutils/singleton"
 )
 
-// setupPlugins is used to setup the plugin loaders.
+// setupPlugins is used to setup the plugin loaders. The loaders are shared by
+// the server and the client so they are only set up once.
 func (a *Agent) setupPlugins() error {
+	if a.pluginLoader != nil {
+		return nil
+	}
+
 	// Get our internal plugins
 	internal, err := a.internalPluginConfigs()
 	if err != nil {
@@ -80,9 +80,12 @@ client {
     reserved_ports = "1,100,10-12"
   }
 
-  client_min_port  = 1000
-  client_max_port  = 2000
-  max_kill_timeout = "10s"
+  client_min_port          = 1000
+  client_max_port          = 2000
+  max_kill_timeout         = "10s"
+  exec_recording_dir       = "/tmp/exec-recordings"
+  exec_recording_retention = "168h"
+  migrate_bandwidth_limit  = "50MB"
 
   stats {
     data_points         = 35
@@ -123,6 +126,7 @@ server {
   csi_volume_claim_gc_threshold = "12h"
   csi_plugin_gc_threshold       = "12h"
   acl_token_gc_threshold        = "12h"
+  exec_session_gc_threshold     = "12h"
   heartbeat_grace               = "30s"
   min_heartbeat_ttl             = "33s"
   max_heartbeats_per_second     = 11.0
@@ -142,6 +146,39 @@ server {
   job_default_priority          = 100
   job_max_priority              = 200
 
+  scheduler_extension "inventory" {
+    timeout = "250ms"
+  }
+
+  rpc_rate_limit {
+    token {
+      read  = 100
+      write = 10
+    }
+
+    namespace "prod" {
+      read = 500
+    }
+
+    endpoint "Alloc.List" {
+      read = 50
+    }
+  }
+
+  keyring "aead" {
+    active = false
+  }
+
+  keyring "transit" {
+    name   = "vault"
+    active = true
+
+    config {
+      address  = "https://vault.example.com:8200"
+      key_name = "nomad-keyring"
+    }
+  }
+
   plan_rejection_tracker {
     enabled        = true
     node_threshold = 100
@@ -174,6 +211,7 @@ acl {
   role_ttl                 = "60s"
   token_min_expiration_ttl = "1h"
   token_max_expiration_ttl = "100h"
+  token_unused_ttl         = "720h"
   replication_token        = "foobar"
 }
 
@@ -260,6 +298,8 @@ vault {
   tls_server_name       = "foobar"
   tls_skip_verify       = true
   create_from_role      = "test_role"
+  use_identity          = true
+  jwt_auth_backend_path = "jwt-test"
 }
 
 tls {
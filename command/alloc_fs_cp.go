@@ -0,0 +1,379 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/open-wander/wander/api"
+	"github.com/open-wander/wander/api/contexts"
+	"github.com/open-wander/wander/helper/escapingfs"
+	"github.com/posener/complete"
+)
+
+type AllocFSCpCommand struct {
+	Meta
+}
+
+func (c *AllocFSCpCommand) Help() string {
+	helpText := `
+Usage: nomad alloc fs cp [options] <source> <destination>
+
+  Copy files and directories between the local machine and the allocation
+  directory of a running allocation. One of the source or destination is
+  given as <allocation>:<path>, where the path is relative to the root of the
+  alloc dir. The other is a local path, or "-" for stdin or stdout.
+
+  Copying a file replaces the destination file, or copies the file into the
+  destination if it is an existing directory. Copying a directory copies its
+  contents into the destination directory, which is created if it doesn't
+  exist. Files are only overwritten, never removed. Directories are streamed
+  as gzip compressed tar archives; only their regular files and directories
+  are copied. With "-", a tar archive is read from stdin or written to
+  stdout instead.
+
+  Files can't be copied into the secrets and private directories of the
+  tasks, and these directories are left out of downloaded archives.
+
+  When ACLs are enabled, this command requires a token with the 'read-job'
+  and 'list-jobs' capabilities for the allocation's namespace, and the
+  'read-fs' capability to copy from an allocation or the 'write-fs'
+  capability to copy to an allocation.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *AllocFSCpCommand) Synopsis() string {
+	return "Copy files to and from an allocation directory"
+}
+
+func (c *AllocFSCpCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *AllocFSCpCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(
+		complete.PredictFiles("*"),
+		complete.PredictFunc(func(a complete.Args) []string {
+			client, err := c.Meta.Client()
+			if err != nil {
+				return nil
+			}
+
+			resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Allocs, nil)
+			if err != nil {
+				return []string{}
+			}
+			return resp.Matches[contexts.Allocs]
+		}),
+	)
+}
+
+func (c *AllocFSCpCommand) Name() string { return "alloc fs cp" }
+
+// allocFSCpPath is a path given to the cp command. Paths of an allocation
+// directory have an allocation ID prefix.
+type allocFSCpPath struct {
+	allocID string
+	path    string
+}
+
+// parseAllocFSCpPath parses a "<allocation>:<path>" or local path argument.
+// Local paths containing a colon must contain a path separator before it, for
+// example "./a:b".
+func parseAllocFSCpPath(arg string) *allocFSCpPath {
+	allocID, p, found := strings.Cut(arg, ":")
+	if !found || allocID == "" || strings.ContainsAny(allocID, `/\`) {
+		return &allocFSCpPath{path: arg}
+	}
+	if p == "" {
+		p = "/"
+	}
+	return &allocFSCpPath{allocID: allocID, path: p}
+}
+
+func (c *AllocFSCpCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <source> <destination>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	src, dst := parseAllocFSCpPath(args[0]), parseAllocFSCpPath(args[1])
+	if (src.allocID == "") == (dst.allocID == "") {
+		c.Ui.Error("One of the source or destination must be an allocation path: <allocation>:<path>")
+		return 1
+	}
+
+	remote := src
+	if src.allocID == "" {
+		remote = dst
+	}
+	if len(remote.allocID) == 1 {
+		c.Ui.Error("Alloc ID must contain at least two characters")
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
+		return 1
+	}
+
+	allocs, _, err := client.Allocations().PrefixList(sanitizeUUIDPrefix(remote.allocID))
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
+		return 1
+	}
+	if len(allocs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", remote.allocID))
+		return 1
+	}
+	if len(allocs) > 1 {
+		out := formatAllocListStubs(allocs, false, shortId)
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
+		return 1
+	}
+
+	q := &api.QueryOptions{Namespace: allocs[0].Namespace}
+	alloc, _, err := client.Allocations().Info(allocs[0].ID, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return 1
+	}
+
+	if src.allocID != "" {
+		err = c.download(client, alloc, src.path, dst.path, q)
+	} else {
+		err = c.upload(client, alloc, src.path, dst.path, q)
+	}
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	return 0
+}
+
+// download copies the file or directory at the path of the allocation to the
+// local destination.
+func (c *AllocFSCpCommand) download(client *api.Client, alloc *api.Allocation, src, dst string, q *api.QueryOptions) error {
+	file, _, err := client.AllocFS().Stat(alloc, src, q)
+	if err != nil {
+		return fmt.Errorf("Error reading %q: %v", src, err)
+	}
+
+	r, err := client.AllocFS().Archive(alloc, src, q)
+	if err != nil {
+		return fmt.Errorf("Error downloading %q: %v", src, err)
+	}
+	defer r.Close()
+
+	switch {
+	case dst == "-":
+		gz, err := gzip.NewReader(r)
+		if err == nil {
+			_, err = io.Copy(os.Stdout, gz)
+		}
+		if err != nil {
+			return fmt.Errorf("Error downloading %q: %v", src, err)
+		}
+	case file.IsDir:
+		if err := extractAllocFSArchive(r, dst, ""); err != nil {
+			return fmt.Errorf("Error downloading %q: %v", src, err)
+		}
+	default:
+		// Copy files into existing directories
+		if fi, err := os.Stat(dst); err == nil && fi.IsDir() {
+			dst = filepath.Join(dst, file.Name)
+		}
+		if err := extractAllocFSArchive(r, filepath.Dir(dst), filepath.Base(dst)); err != nil {
+			return fmt.Errorf("Error downloading %q: %v", src, err)
+		}
+	}
+	return nil
+}
+
+// upload copies the local file or directory to the path of the allocation.
+func (c *AllocFSCpCommand) upload(client *api.Client, alloc *api.Allocation, src, dst string, q *api.QueryOptions) error {
+	if src == "-" {
+		if _, err := client.AllocFS().Upload(alloc, dst, os.Stdin, true, 0, q); err != nil {
+			return fmt.Errorf("Error uploading to %q: %v", dst, err)
+		}
+		return nil
+	}
+
+	fi, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("Error reading %q: %v", src, err)
+	}
+
+	if fi.IsDir() {
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(writeAllocFSArchive(pw, src))
+		}()
+		defer pr.Close()
+
+		if _, err := client.AllocFS().Upload(alloc, dst, pr, true, 0, q); err != nil {
+			return fmt.Errorf("Error uploading %q: %v", src, err)
+		}
+		return nil
+	}
+	if !fi.Mode().IsRegular() {
+		return fmt.Errorf("Error uploading %q: not a regular file or directory", src)
+	}
+
+	// Copy files into existing directories
+	if existing, _, err := client.AllocFS().Stat(alloc, dst, q); err == nil && existing.IsDir {
+		dst = path.Join(dst, fi.Name())
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("Error reading %q: %v", src, err)
+	}
+	defer f.Close()
+
+	if _, err := client.AllocFS().Upload(alloc, dst, f, false, fi.Mode(), q); err != nil {
+		return fmt.Errorf("Error uploading %q: %v", src, err)
+	}
+	return nil
+}
+
+// writeAllocFSArchive writes a gzip compressed tar archive of the contents of
+// the directory to w.
+func writeAllocFSArchive(w io.Writer, dir string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() && !fi.Mode().IsRegular() {
+			return fmt.Errorf("%q is not a regular file or directory", p)
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// extractAllocFSArchive extracts a gzip compressed tar archive into the
+// directory, creating it if it doesn't exist. Only regular files and
+// directories within the directory are extracted, other entries such as
+// symlinks are skipped. If name is set, the archive is expected to hold a
+// single file which is extracted with the given name.
+func extractAllocFSArchive(r io.Reader, dir, name string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		dst := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if name != "" {
+			dst = filepath.Join(dir, name)
+		}
+		if escapingfs.PathEscapesSandbox(dir, dst) {
+			return fmt.Errorf("archive entry %q escapes the destination directory", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(dst, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, hdr.FileInfo().Mode().Perm())
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
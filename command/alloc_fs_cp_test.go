@@ -0,0 +1,209 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/uuid"
+	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestAllocFSCpCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &AllocFSCpCommand{}
+}
+
+func TestAllocFSCpCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &AllocFSCpCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails without an allocation path
+	code = cmd.Run([]string{"-address=" + url, "./src", "./dst"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "must be an allocation path")
+	ui.ErrorWriter.Reset()
+
+	// Fails with two allocation paths
+	code = cmd.Run([]string{"-address=" + url, "foo:src", "bar:dst"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "must be an allocation path")
+	ui.ErrorWriter.Reset()
+
+	// Fails on short alloc ID
+	code = cmd.Run([]string{"-address=" + url, "f:src", "./dst"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "must contain at least two characters")
+	ui.ErrorWriter.Reset()
+
+	// Fails on unknown alloc ID
+	code = cmd.Run([]string{"-address=" + url, "./src", "26470238-5CF2-438F-8772-DC67CFB0705C:dst"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "No allocation(s) with prefix or id")
+}
+
+func TestAllocFSCpCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Wait for a node to be ready
+	waitForNodes(t, client)
+
+	jobID := uuid.Generate()
+	job := testJob(jobID)
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+	resp, _, err := client.Jobs().Register(job, nil)
+	must.NoError(t, err)
+
+	evalUi := cli.NewMockUi()
+	code := waitForSuccess(evalUi, client, fullId, t, resp.EvalID)
+	must.Zero(t, code)
+
+	allocID := ""
+	testutil.WaitForResult(func() (bool, error) {
+		allocs, _, err := client.Jobs().Allocations(jobID, false, nil)
+		if err != nil {
+			return false, fmt.Errorf("failed to get allocations: %v", err)
+		}
+		if len(allocs) == 0 {
+			return false, fmt.Errorf("no allocations yet")
+		}
+		if allocs[0].ClientStatus != "running" {
+			return false, fmt.Errorf("alloc is not running yet: %v", allocs[0].ClientStatus)
+		}
+		allocID = allocs[0].ID
+		return true, nil
+	}, func(err error) { must.NoError(t, err) })
+
+	src := t.TempDir()
+	must.NoError(t, os.MkdirAll(filepath.Join(src, "conf"), 0755))
+	must.NoError(t, os.WriteFile(filepath.Join(src, "conf", "app.conf"), []byte("debug"), 0600))
+
+	ui := cli.NewMockUi()
+	cmd := &AllocFSCpCommand{Meta: Meta{Ui: ui}}
+
+	// Upload a directory and a file into it
+	code = cmd.Run([]string{"-address=" + url, src, allocID + ":alloc/data/upload"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	code = cmd.Run([]string{"-address=" + url, filepath.Join(src, "conf", "app.conf"), allocID + ":alloc/data/upload"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+
+	// Download the directory and a single file
+	dst := t.TempDir()
+	code = cmd.Run([]string{"-address=" + url, allocID + ":alloc/data/upload", filepath.Join(dst, "dir")})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	code = cmd.Run([]string{"-address=" + url, allocID + ":alloc/data/upload/app.conf", filepath.Join(dst, "file.conf")})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+
+	for _, p := range []string{"dir/conf/app.conf", "dir/app.conf", "file.conf"} {
+		data, err := os.ReadFile(filepath.Join(dst, p))
+		must.NoError(t, err)
+		must.Eq(t, "debug", string(data))
+
+		fi, err := os.Stat(filepath.Join(dst, p))
+		must.NoError(t, err)
+		must.Eq(t, os.FileMode(0600), fi.Mode().Perm())
+	}
+
+	// Missing files can't be downloaded
+	code = cmd.Run([]string{"-address=" + url, allocID + ":alloc/data/missing", dst})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "no such file")
+}
+
+func TestAllocFSCpCommand_ParsePath(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		arg     string
+		allocID string
+		path    string
+	}{
+		{arg: "-", path: "-"},
+		{arg: "local/file", path: "local/file"},
+		{arg: "./a:b", path: "./a:b"},
+		{arg: ":file", path: ":file"},
+		{arg: "5fc98185:alloc/data", allocID: "5fc98185", path: "alloc/data"},
+		{arg: "5fc98185:", allocID: "5fc98185", path: "/"},
+	}
+	for _, tc := range cases {
+		p := parseAllocFSCpPath(tc.arg)
+		must.Eq(t, tc.allocID, p.allocID, must.Sprint(tc.arg))
+		must.Eq(t, tc.path, p.path, must.Sprint(tc.arg))
+	}
+}
+
+func TestAllocFSCpCommand_Archive(t *testing.T) {
+	ci.Parallel(t)
+
+	src := t.TempDir()
+	must.NoError(t, os.MkdirAll(filepath.Join(src, "conf", "empty"), 0755))
+	must.NoError(t, os.WriteFile(filepath.Join(src, "run.sh"), []byte("#!/bin/sh"), 0755))
+	must.NoError(t, os.WriteFile(filepath.Join(src, "conf", "app.conf"), []byte("debug"), 0600))
+
+	var buf bytes.Buffer
+	must.NoError(t, writeAllocFSArchive(&buf, src))
+
+	// Extract the archive into a new directory
+	dst := filepath.Join(t.TempDir(), "dst")
+	must.NoError(t, extractAllocFSArchive(bytes.NewReader(buf.Bytes()), dst, ""))
+
+	data, err := os.ReadFile(filepath.Join(dst, "conf", "app.conf"))
+	must.NoError(t, err)
+	must.Eq(t, "debug", string(data))
+
+	fi, err := os.Stat(filepath.Join(dst, "run.sh"))
+	must.NoError(t, err)
+	must.Eq(t, os.FileMode(0755), fi.Mode().Perm())
+	must.DirExists(t, filepath.Join(dst, "conf", "empty"))
+
+	// Extract a single file archive with another name
+	buf.Reset()
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	must.NoError(t, tw.WriteHeader(&tar.Header{Name: "app.conf", Typeflag: tar.TypeReg, Mode: 0644, Size: 4}))
+	_, err = tw.Write([]byte("file"))
+	must.NoError(t, err)
+	must.NoError(t, tw.Close())
+	must.NoError(t, gw.Close())
+
+	must.NoError(t, extractAllocFSArchive(&buf, dst, "renamed.conf"))
+	data, err = os.ReadFile(filepath.Join(dst, "renamed.conf"))
+	must.NoError(t, err)
+	must.Eq(t, "file", string(data))
+
+	// Entries escaping the destination are rejected
+	buf.Reset()
+	gw = gzip.NewWriter(&buf)
+	tw = tar.NewWriter(gw)
+	must.NoError(t, tw.WriteHeader(&tar.Header{Name: "../escape", Typeflag: tar.TypeReg, Mode: 0644}))
+	must.NoError(t, tw.Close())
+	must.NoError(t, gw.Close())
+
+	err = extractAllocFSArchive(&buf, dst, "")
+	must.ErrorContains(t, err, "escapes the destination directory")
+	must.FileNotExists(t, filepath.Join(filepath.Dir(dst), "escape"))
+}
@@ -0,0 +1,251 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/open-wander/wander/api"
+	"github.com/open-wander/wander/api/contexts"
+	"github.com/posener/complete"
+)
+
+type AllocPortForwardCommand struct {
+	Meta
+}
+
+func (c *AllocPortForwardCommand) Help() string {
+	helpText := `
+Usage: nomad alloc port-forward [options] <allocation> <[local]:remote>...
+
+  Forward local TCP ports to the ports of a running allocation. Connections
+  are tunnelled through the Nomad agent HTTP API, so the ports don't need to
+  be reachable from the machine running the command.
+
+  Each port is given as a local port and a remote port separated by a colon.
+  The remote port is either the label of a port of the allocation or a port
+  number. A single port number forwards the same local port, and an empty
+  local port forwards a random local port.
+
+  Allocations using bridge networking can be forwarded to any port listening
+  on the loopback interface of their network namespace. Allocations using the
+  host network can only be forwarded to their allocated ports.
+
+  When ACLs are enabled, this command requires a token with the
+  'alloc-port-forward', 'read-job', and 'list-jobs' capabilities for the
+  allocation's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Port Forward Options:
+
+  -bind <address>
+    The local address to listen on. Defaults to "127.0.0.1".
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *AllocPortForwardCommand) Synopsis() string {
+	return "Forward local ports to an allocation"
+}
+
+func (c *AllocPortForwardCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-bind": complete.PredictAnything,
+		})
+}
+
+func (c *AllocPortForwardCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Allocs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Allocs]
+	})
+}
+
+func (c *AllocPortForwardCommand) Name() string { return "alloc port-forward" }
+
+// portForwardSpec is a local port forwarded to a port of an allocation.
+type portForwardSpec struct {
+	local  int
+	remote string
+}
+
+// parsePortForwardSpec parses a "[local]:remote" port forward argument.
+func parsePortForwardSpec(arg string) (*portForwardSpec, error) {
+	local, remote, found := strings.Cut(arg, ":")
+	if !found {
+		local, remote = arg, arg
+	}
+	if remote == "" {
+		return nil, fmt.Errorf("invalid port %q: remote port is required", arg)
+	}
+
+	spec := &portForwardSpec{remote: remote}
+	if local == "" {
+		return spec, nil
+	}
+
+	port, err := strconv.Atoi(local)
+	if err != nil {
+		if !found {
+			return nil, fmt.Errorf("invalid port %q: local port is required with a port label", arg)
+		}
+		return nil, fmt.Errorf("invalid local port %q", local)
+	}
+	if port < 1 || port > 65535 {
+		return nil, fmt.Errorf("invalid local port %d", port)
+	}
+	spec.local = port
+	return spec, nil
+}
+
+func (c *AllocPortForwardCommand) Run(args []string) int {
+	var bind string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&bind, "bind", "127.0.0.1", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		c.Ui.Error("This command takes at least two arguments: <allocation> <[local]:remote>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	allocID := args[0]
+	if len(allocID) == 1 {
+		c.Ui.Error("Alloc ID must contain at least two characters")
+		return 1
+	}
+
+	var specs []*portForwardSpec
+	for _, arg := range args[1:] {
+		spec, err := parsePortForwardSpec(arg)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		specs = append(specs, spec)
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
+		return 1
+	}
+
+	allocs, _, err := client.Allocations().PrefixList(sanitizeUUIDPrefix(allocID))
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
+		return 1
+	}
+	if len(allocs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", allocID))
+		return 1
+	}
+	if len(allocs) > 1 {
+		out := formatAllocListStubs(allocs, false, shortId)
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
+		return 1
+	}
+
+	q := &api.QueryOptions{Namespace: allocs[0].Namespace}
+	alloc, _, err := client.Allocations().Info(allocs[0].ID, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return 1
+	}
+	if alloc.ClientStatus != api.AllocClientStatusRunning {
+		c.Ui.Error(fmt.Sprintf("Allocation %q is not running", limit(alloc.ID, shortId)))
+		return 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listeners := make([]net.Listener, 0, len(specs))
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+	for _, spec := range specs {
+		addr := net.JoinHostPort(bind, strconv.Itoa(spec.local))
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error listening on %s: %v", addr, err))
+			return 1
+		}
+		listeners = append(listeners, l)
+
+		c.Ui.Output(fmt.Sprintf("Forwarding from %s -> %s", l.Addr(), spec.remote))
+	}
+
+	errCh := make(chan error, len(specs))
+	for i, spec := range specs {
+		go c.serve(ctx, client, alloc, listeners[i], spec.remote, q, errCh)
+	}
+
+	// Forward until the user interrupts the command
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+
+	select {
+	case <-signalCh:
+		return 0
+	case err := <-errCh:
+		c.Ui.Error(fmt.Sprintf("Error accepting connections: %v", err))
+		return 1
+	}
+}
+
+// serve forwards the connections accepted by the listener to the port of the
+// allocation until the context is cancelled. Errors forwarding a connection
+// are reported without stopping the listener.
+func (c *AllocPortForwardCommand) serve(ctx context.Context, client *api.Client, alloc *api.Allocation,
+	l net.Listener, remote string, q *api.QueryOptions, errCh chan<- error) {
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+				errCh <- err
+			}
+			return
+		}
+
+		c.Ui.Output(fmt.Sprintf("Handling connection for %s", remote))
+		go func() {
+			err := client.Allocations().PortForward(ctx, alloc, remote, conn, q)
+			if err != nil && ctx.Err() == nil {
+				c.Ui.Error(fmt.Sprintf("Error forwarding connection to %s: %v", remote, err))
+			}
+		}()
+	}
+}
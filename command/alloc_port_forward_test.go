@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/open-wander/wander/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestAllocPortForwardCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &AllocPortForwardCommand{}
+}
+
+func TestAllocPortForwardCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	cases := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "misuse",
+			args: []string{"26470238-5CF2-438F-8772-DC67CFB0705C"},
+			err:  commandErrorText(&AllocPortForwardCommand{}),
+		},
+		{
+			name: "short alloc id",
+			args: []string{"2", "8080"},
+			err:  "must contain at least two characters",
+		},
+		{
+			name: "invalid port",
+			args: []string{"26470238-5CF2-438F-8772-DC67CFB0705C", "http"},
+			err:  "local port is required",
+		},
+		{
+			name: "unknown alloc",
+			args: []string{"-address=" + url, "26470238-5CF2-438F-8772-DC67CFB0705C", "8080"},
+			err:  "No allocation(s) with prefix or id",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := &AllocPortForwardCommand{Meta: Meta{Ui: ui}}
+
+			code := cmd.Run(tc.args)
+			must.One(t, code)
+			must.StrContains(t, ui.ErrorWriter.String(), tc.err)
+		})
+	}
+}
+
+func TestAllocPortForwardCommand_ParseSpec(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		arg    string
+		local  int
+		remote string
+		err    string
+	}{
+		{arg: "8080", local: 8080, remote: "8080"},
+		{arg: "9000:8080", local: 9000, remote: "8080"},
+		{arg: "9000:http", local: 9000, remote: "http"},
+		{arg: ":http", local: 0, remote: "http"},
+		{arg: "http", err: "local port is required"},
+		{arg: "9000:", err: "remote port is required"},
+		{arg: "web:http", err: "invalid local port"},
+		{arg: "70000:http", err: "invalid local port"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.arg, func(t *testing.T) {
+			spec, err := parsePortForwardSpec(tc.arg)
+			if tc.err != "" {
+				must.ErrorContains(t, err, tc.err)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.local, spec.local)
+			must.Eq(t, tc.remote, spec.remote)
+		})
+	}
+}
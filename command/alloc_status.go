@@ -441,6 +441,9 @@ func (c *AllocStatusCommand) outputTaskStatus(state *api.TaskState) {
 		fmt.Sprintf("Finished At|%s", formatTaskTimes(state.FinishedAt)),
 		fmt.Sprintf("Total Restarts|%d", state.Restarts),
 		fmt.Sprintf("Last Restart|%s", formatTaskTimes(state.LastRestart))}
+	if state.TimedOut {
+		basic = append(basic, "Timed Out|true")
+	}
 
 	c.Ui.Output("Task Events:")
 	c.Ui.Output(formatKV(basic))
@@ -499,6 +502,24 @@ func buildDisplayMessage(event *api.TaskEvent) string {
 		} else {
 			desc = "Failed to download artifacts"
 		}
+	case api.TaskArtifactVerificationFailed:
+		if event.DownloadError != "" {
+			desc = event.DownloadError
+		} else {
+			desc = "Failed to verify artifact signature"
+		}
+	case api.TaskRunDurationExceeded:
+		if event.Message != "" {
+			desc = event.Message
+		} else {
+			desc = "Task exceeded its max run duration"
+		}
+	case api.TaskDeadlineExceeded:
+		if event.Message != "" {
+			desc = event.Message
+		} else {
+			desc = "Task exceeded the deadline of its job"
+		}
 	case api.TaskKilling:
 		if event.KillReason != "" {
 			desc = fmt.Sprintf("Killing task: %v", event.KillReason)
@@ -205,6 +205,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"acl token check": func() (cli.Command, error) {
+			return &ACLTokenCheckCommand{
+				Meta: meta,
+			}, nil
+		},
 		"acl token create": func() (cli.Command, error) {
 			return &ACLTokenCreateCommand{
 				Meta: meta,
@@ -260,11 +265,21 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"alloc fs cp": func() (cli.Command, error) {
+			return &AllocFSCpCommand{
+				Meta: meta,
+			}, nil
+		},
 		"alloc logs": func() (cli.Command, error) {
 			return &AllocLogsCommand{
 				Meta: meta,
 			}, nil
 		},
+		"alloc port-forward": func() (cli.Command, error) {
+			return &AllocPortForwardCommand{
+				Meta: meta,
+			}, nil
+		},
 		"alloc restart": func() (cli.Command, error) {
 			return &AllocRestartCommand{
 				Meta: meta,
@@ -388,6 +403,21 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"exec-session": func() (cli.Command, error) {
+			return &ExecSessionCommand{
+				Meta: meta,
+			}, nil
+		},
+		"exec-session list": func() (cli.Command, error) {
+			return &ExecSessionListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"exec-session replay": func() (cli.Command, error) {
+			return &ExecSessionReplayCommand{
+				Meta: meta,
+			}, nil
+		},
 		"fmt": func() (cli.Command, error) {
 			return &FormatCommand{
 				Meta: meta,
@@ -426,6 +456,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job dependencies": func() (cli.Command, error) {
+			return &JobDependenciesCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job deployments": func() (cli.Command, error) {
 			return &JobDeploymentsCommand{
 				Meta: meta,
@@ -441,6 +476,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job explain": func() (cli.Command, error) {
+			return &JobExplainCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job history": func() (cli.Command, error) {
 			return &JobHistoryCommand{
 				Meta: meta,
@@ -456,6 +496,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job logs": func() (cli.Command, error) {
+			return &JobLogsCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job periodic": func() (cli.Command, error) {
 			return &JobPeriodicCommand{
 				Meta: meta,
@@ -571,6 +616,21 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"node artifact-cache": func() (cli.Command, error) {
+			return &NodeArtifactCacheCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node artifact-cache list": func() (cli.Command, error) {
+			return &NodeArtifactCacheListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node artifact-cache purge": func() (cli.Command, error) {
+			return &NodeArtifactCachePurgeCommand{
+				Meta: meta,
+			}, nil
+		},
 		"node config": func() (cli.Command, error) {
 			return &NodeConfigCommand{
 				Meta: meta,
@@ -789,6 +849,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"operator root keyring migrate": func() (cli.Command, error) {
+			return &OperatorRootKeyringMigrateCommand{
+				Meta: meta,
+			}, nil
+		},
 		"operator root keyring remove": func() (cli.Command, error) {
 			return &OperatorRootKeyringRemoveCommand{
 				Meta: meta,
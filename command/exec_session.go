@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type ExecSessionCommand struct {
+	Meta
+}
+
+func (c *ExecSessionCommand) Help() string {
+	helpText := `
+Usage: nomad exec-session <subcommand> [options]
+
+  This command groups subcommands for interacting with recorded exec sessions.
+  Exec sessions are recorded for namespaces with exec recording enabled.
+
+  List recorded exec sessions:
+
+      $ nomad exec-session list
+
+  Replay the recording of an exec session:
+
+      $ nomad exec-session replay <session_id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ExecSessionCommand) Name() string { return "exec-session" }
+
+func (c *ExecSessionCommand) Synopsis() string { return "Interact with recorded exec sessions" }
+
+func (c *ExecSessionCommand) Run(_ []string) int { return cli.RunResultHelp }
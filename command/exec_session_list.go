@@ -0,0 +1,196 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/open-wander/wander/api"
+	"github.com/posener/complete"
+)
+
+type ExecSessionListCommand struct {
+	Meta
+}
+
+func (c *ExecSessionListCommand) Name() string {
+	return "exec-session list"
+}
+
+func (c *ExecSessionListCommand) Synopsis() string {
+	return "List recorded exec sessions"
+}
+
+func (c *ExecSessionListCommand) Help() string {
+	helpText := `
+Usage: nomad exec-session list [options]
+
+  List is used to list the recorded exec sessions. Exec sessions are recorded
+  for namespaces with exec recording enabled and are kept until the server
+  garbage collects them.
+
+  When ACLs are enabled, this command requires a token with the
+  'read-exec-recording' capability for the namespaces of the sessions.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+List Options:
+
+  -filter
+    Specifies an expression used to filter results.
+
+  -json
+    Output the exec sessions in JSON format.
+
+  -page-token
+    Where to start pagination.
+
+  -per-page
+    How many results to show per page. If not specified, or set to 0, all
+    results are returned.
+
+  -prefix
+    Only list the exec sessions whose ID matches the prefix.
+
+  -t
+    Format and display the exec sessions using a Go template.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ExecSessionListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-filter":     complete.PredictAnything,
+			"-json":       complete.PredictNothing,
+			"-page-token": complete.PredictAnything,
+			"-per-page":   complete.PredictAnything,
+			"-prefix":     complete.PredictAnything,
+			"-t":          complete.PredictAnything,
+			"-verbose":    complete.PredictNothing,
+		})
+}
+
+func (c *ExecSessionListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ExecSessionListCommand) Run(args []string) int {
+	var json, verbose bool
+	var perPage int
+	var tmpl, pageToken, filter, prefix string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&filter, "filter", "", "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&pageToken, "page-token", "", "")
+	flags.IntVar(&perPage, "per-page", 0, "")
+	flags.StringVar(&prefix, "prefix", "", "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we don't have any arguments.
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	opts := &api.QueryOptions{
+		Filter:    filter,
+		PerPage:   int32(perPage),
+		NextToken: pageToken,
+	}
+	sessions, qm, err := client.ExecSessions().PrefixList(sanitizeUUIDPrefix(prefix), opts)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying exec sessions: %s", err))
+		return 1
+	}
+
+	if json || tmpl != "" {
+		out, err := Format(json, tmpl, sessions)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error formatting output: %s", err))
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if len(sessions) == 0 {
+		c.Ui.Output("No exec sessions found")
+		return 0
+	}
+
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+	c.Ui.Output(formatExecSessions(sessions, length))
+
+	if qm.NextToken != "" {
+		c.Ui.Output(fmt.Sprintf(`
+Results have been paginated. To get the next page run:
+
+%s -page-token %s`, argsWithoutPageToken(os.Args), qm.NextToken))
+	}
+
+	return 0
+}
+
+// formatExecSessions formats a list of exec sessions, limiting their IDs to
+// the given length.
+func formatExecSessions(sessions []*api.ExecSession, length int) string {
+	out := make([]string, len(sessions)+1)
+	out[0] = "ID|Namespace|Alloc ID|Task|Command|Token|Started|Status"
+	for i, s := range sessions {
+		token := s.TokenName
+		if token == "" {
+			token = limit(s.AccessorID, length)
+		}
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s",
+			limit(s.ID, length),
+			s.Namespace,
+			limit(s.AllocID, length),
+			s.TaskName,
+			strings.Join(s.Command, " "),
+			token,
+			formatTime(s.StartedAt),
+			formatExecSessionStatus(s),
+		)
+	}
+	return formatList(out)
+}
+
+// formatExecSessionStatus returns whether the exec session is running or its
+// outcome if it ended.
+func formatExecSessionStatus(s *api.ExecSession) string {
+	switch {
+	case !s.Ended():
+		return "running"
+	case s.Error != "":
+		return "failed"
+	default:
+		return fmt.Sprintf("exited (%d)", s.ExitCode)
+	}
+}
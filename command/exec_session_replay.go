@@ -0,0 +1,222 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/posener/complete"
+)
+
+// execSessionReplayMaxLine is the longest event line of a recording the
+// replay accepts.
+const execSessionReplayMaxLine = 4 * 1024 * 1024
+
+type ExecSessionReplayCommand struct {
+	Meta
+
+	// Stdout is where the recording is replayed, defaulting to os.Stdout.
+	Stdout io.Writer
+}
+
+func (c *ExecSessionReplayCommand) Name() string {
+	return "exec-session replay"
+}
+
+func (c *ExecSessionReplayCommand) Synopsis() string {
+	return "Replay the recording of an exec session"
+}
+
+func (c *ExecSessionReplayCommand) Help() string {
+	helpText := `
+Usage: nomad exec-session replay [options] <session_id>
+
+  Replay is used to replay the output of a recorded exec session in the
+  terminal, with the timing of the original session. The recording is streamed
+  from the client that ran the session and is only available until the client
+  removes it after its retention period.
+
+  When ACLs are enabled, this command requires a token with the
+  'read-exec-recording' capability for the namespace of the session.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Replay Options:
+
+  -raw
+    Output the recording as an asciicast v2 file instead of replaying it. The
+    file includes the input of the session and can be played with asciinema.
+
+  -speed
+    The speed of the replay, relative to the original session. A speed of 0
+    outputs the session without waiting. Defaults to 1.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ExecSessionReplayCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-raw":   complete.PredictNothing,
+			"-speed": complete.PredictAnything,
+		})
+}
+
+func (c *ExecSessionReplayCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		sessions, _, err := client.ExecSessions().PrefixList(sanitizeUUIDPrefix(a.Last), nil)
+		if err != nil {
+			return nil
+		}
+		ids := make([]string, len(sessions))
+		for i, s := range sessions {
+			ids[i] = s.ID
+		}
+		return ids
+	})
+}
+
+func (c *ExecSessionReplayCommand) Run(args []string) int {
+	var raw bool
+	var speed float64
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&raw, "raw", false, "")
+	flags.Float64Var(&speed, "speed", 1, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <session_id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if speed < 0 {
+		c.Ui.Error("Speed must not be negative")
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Resolve the session ID prefix
+	id := args[0]
+	if len(id) < fullId {
+		sessions, _, err := client.ExecSessions().PrefixList(sanitizeUUIDPrefix(id), nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying exec sessions: %s", err))
+			return 1
+		}
+		switch len(sessions) {
+		case 0:
+			c.Ui.Error(fmt.Sprintf("No exec session(s) with prefix %q found", id))
+			return 1
+		case 1:
+			id = sessions[0].ID
+		default:
+			c.Ui.Error(fmt.Sprintf("Prefix matched multiple exec sessions\n\n%s",
+				formatExecSessions(sessions, fullId)))
+			return 1
+		}
+	}
+
+	recording, err := client.ExecSessions().Recording(id, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading exec session recording: %s", err))
+		return 1
+	}
+	defer recording.Close()
+
+	stdout := c.Stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+
+	if raw {
+		if _, err := io.Copy(stdout, recording); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error reading exec session recording: %s", err))
+			return 1
+		}
+		return 0
+	}
+
+	if err := replayExecSession(recording, stdout, speed); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error replaying exec session: %s", err))
+		return 1
+	}
+	return 0
+}
+
+// replayExecSession writes the output events of an asciicast v2 recording to
+// w, waiting between events for their recorded time divided by speed.
+func replayExecSession(r io.Reader, w io.Writer, speed float64) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, execSessionReplayMaxLine)
+
+	// The first line is the header of the recording
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return errors.New("recording is empty")
+	}
+	var header struct {
+		Version int
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return fmt.Errorf("failed to decode recording header: %w", err)
+	}
+	if header.Version != 2 {
+		return fmt.Errorf("unsupported recording version %d", header.Version)
+	}
+
+	var last float64
+	for scanner.Scan() {
+		var event []interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return fmt.Errorf("failed to decode recording event: %w", err)
+		}
+		if len(event) != 3 {
+			return fmt.Errorf("invalid recording event %s", scanner.Text())
+		}
+		elapsed, _ := event[0].(float64)
+		code, _ := event[1].(string)
+		data, _ := event[2].(string)
+		if code != "o" {
+			continue
+		}
+
+		if speed > 0 && elapsed > last {
+			time.Sleep(time.Duration((elapsed - last) / speed * float64(time.Second)))
+		}
+		last = elapsed
+
+		if _, err := io.WriteString(w, data); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/open-wander/wander/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestExecSessionReplayCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &ExecSessionReplayCommand{}
+}
+
+func TestExecSessionReplayCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	cmd := &ExecSessionReplayCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on negative speed
+	code = cmd.Run([]string{"-speed=-1", "abcd"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Speed must not be negative")
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	code = cmd.Run([]string{"-address=nope", "abcd"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Error querying exec sessions")
+}
+
+func TestExecSessionReplay(t *testing.T) {
+	ci.Parallel(t)
+
+	recording := strings.Join([]string{
+		`{"version":2,"width":80,"height":24,"timestamp":1700000000,"nomad":{"session_id":"abcd"}}`,
+		`[0.001,"r","120x40"]`,
+		`[0.002,"i","ls\n"]`,
+		`[0.003,"o","file\n"]`,
+		`[0.004,"o","done\n"]`,
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	must.NoError(t, replayExecSession(strings.NewReader(recording), &out, 0))
+	must.Eq(t, "file\ndone\n", out.String())
+
+	// Only asciicast v2 recordings can be replayed
+	err := replayExecSession(strings.NewReader(`{"version":1}`+"\n"), &out, 0)
+	must.ErrorContains(t, err, "unsupported recording version 1")
+
+	err = replayExecSession(strings.NewReader(""), &out, 0)
+	must.ErrorContains(t, err, "recording is empty")
+
+	err = replayExecSession(strings.NewReader(`{"version":2}`+"\n"+`[0.1,"o"]`+"\n"), &out, 0)
+	must.ErrorContains(t, err, "invalid recording event")
+}
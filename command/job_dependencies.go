@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/open-wander/wander/api"
+	"github.com/open-wander/wander/api/contexts"
+	"github.com/posener/complete"
+)
+
+type JobDependenciesCommand struct {
+	Meta
+}
+
+func (c *JobDependenciesCommand) Help() string {
+	helpText := `
+Usage: nomad job dependencies [options] <job>
+
+  Display the status of the upstream jobs a job depends on. A job with
+  dependencies is only evaluated once all of its upstream jobs complete
+  successfully, and is never evaluated if one of them fails.
+
+  When ACLs are enabled, this command requires a token with the 'read-job'
+  capability for the job's namespace. The 'list-jobs' capability is required to
+  run the command with a job prefix instead of the exact job ID.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Dependencies Options:
+
+  -json
+    Output the dependency status in a JSON format.
+
+  -t
+    Format and display the dependency status using a Go template.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobDependenciesCommand) Synopsis() string {
+	return "Display the status of a job's dependencies"
+}
+
+func (c *JobDependenciesCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *JobDependenciesCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *JobDependenciesCommand) Name() string { return "job dependencies" }
+
+func (c *JobDependenciesCommand) Run(args []string) int {
+	var json, verbose bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one job
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Check if the job exists
+	jobIDPrefix := strings.TrimSpace(args[0])
+	jobID, namespace, err := c.JobIDByPrefix(client, jobIDPrefix, nil)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	q := &api.QueryOptions{Namespace: namespace}
+	run, _, err := client.Jobs().Dependencies(jobID, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving job dependencies: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, run)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	c.Ui.Output(formatJobDependencyRun(run, length))
+	c.Ui.Output(c.Colorize().Color("\n[bold]Upstream Jobs[reset]"))
+	c.Ui.Output(formatJobDependencyUpstreams(run.Upstreams))
+	return 0
+}
+
+// formatJobDependencyRun formats the status of the dependency run of a job.
+func formatJobDependencyRun(run *api.JobDependencyRun, length int) string {
+	evalID := "<none>"
+	if run.EvalID != "" {
+		evalID = limit(run.EvalID, length)
+	}
+
+	out := []string{
+		fmt.Sprintf("ID|%s", run.JobID),
+		fmt.Sprintf("Namespace|%s", run.Namespace),
+		fmt.Sprintf("Status|%s", run.Status),
+		fmt.Sprintf("Description|%s", run.StatusDescription),
+		fmt.Sprintf("Eval ID|%s", evalID),
+	}
+	return formatKV(out)
+}
+
+// formatJobDependencyUpstreams formats the observed status of each upstream
+// job.
+func formatJobDependencyUpstreams(upstreams []*api.JobDependencyUpstream) string {
+	out := make([]string, len(upstreams)+1)
+	out[0] = "ID|Status"
+	for i, upstream := range upstreams {
+		out[i+1] = fmt.Sprintf("%s|%s", upstream.JobID, upstream.Status)
+	}
+	return formatList(out)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobDependenciesCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobDependenciesCommand{}
+}
+
+func TestJobDependenciesCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &JobDependenciesCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Bad job name
+	code = cmd.Run([]string{"-address=" + url, "foo"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), `No job(s) with prefix or ID "foo" found`)
+	ui.ErrorWriter.Reset()
+
+	// Job without dependencies
+	job := mock.BatchJob()
+	state := srv.Agent.Server().State()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 100, nil, job))
+
+	code = cmd.Run([]string{"-address=" + url, job.ID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "job has no dependencies")
+}
+
+func TestJobDependenciesCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	state := srv.Agent.Server().State()
+
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{JobID: "extract"}}
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 100, nil, job))
+
+	ui := cli.NewMockUi()
+	cmd := &JobDependenciesCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, job.ID})
+	must.Zero(t, code)
+
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, "Status      = blocked")
+	must.StrContains(t, out, "Eval ID     = <none>")
+	must.StrContains(t, out, "Upstream Jobs")
+	must.RegexMatch(t, regexp.MustCompile(`extract\s+pending`), out)
+	ui.OutputWriter.Reset()
+
+	// JSON output
+	code = cmd.Run([]string{"-address=" + url, "-json", job.ID})
+	must.Zero(t, code)
+	must.StrContains(t, ui.OutputWriter.String(), `"Status": "blocked"`)
+}
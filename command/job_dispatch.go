@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/open-wander/wander/api"
 	flaghelper "github.com/open-wander/wander/helper/flags"
@@ -66,6 +67,12 @@ Dispatch Options:
   -id-prefix-template
     Optional prefix template for dispatched job IDs.
 
+  -deadline
+    Optional time by which the tasks of the dispatched job must have completed,
+    either as an RFC3339 timestamp or as a duration from now such as "2h".
+    Tasks still running at the deadline are killed with their kill signal and
+    tasks that have not started yet are not started.
+
   -verbose
     Display full information.
 `
@@ -82,6 +89,7 @@ func (c *JobDispatchCommand) AutocompleteFlags() complete.Flags {
 			"-meta":              complete.PredictAnything,
 			"-detach":            complete.PredictNothing,
 			"-idempotency-token": complete.PredictAnything,
+			"-deadline":          complete.PredictAnything,
 			"-verbose":           complete.PredictNothing,
 		})
 }
@@ -116,7 +124,7 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	var detach, verbose bool
 	var idempotencyToken string
 	var meta []string
-	var idPrefixTemplate string
+	var idPrefixTemplate, deadlineStr string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -125,6 +133,7 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	flags.StringVar(&idempotencyToken, "idempotency-token", "", "")
 	flags.Var((*flaghelper.StringFlag)(&meta), "meta", "")
 	flags.StringVar(&idPrefixTemplate, "id-prefix-template", "", "")
+	flags.StringVar(&deadlineStr, "deadline", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -173,6 +182,16 @@ func (c *JobDispatchCommand) Run(args []string) int {
 		metaMap[split[0]] = split[1]
 	}
 
+	var deadline time.Time
+	if deadlineStr != "" {
+		var err error
+		deadline, err = parseDeadline(deadlineStr, time.Now())
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error parsing deadline: %v", err))
+			return 1
+		}
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
@@ -195,7 +214,14 @@ func (c *JobDispatchCommand) Run(args []string) int {
 		IdempotencyToken: idempotencyToken,
 		Namespace:        namespace,
 	}
-	resp, _, err := client.Jobs().Dispatch(jobID, metaMap, payload, idPrefixTemplate, w)
+	req := &api.JobDispatchRequest{
+		JobID:            jobID,
+		Meta:             metaMap,
+		Payload:          payload,
+		IdPrefixTemplate: idPrefixTemplate,
+		Deadline:         deadline,
+	}
+	resp, _, err := client.Jobs().DispatchOpts(req, w)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to dispatch job: %s", err))
 		return 1
@@ -222,3 +248,20 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	mon := newMonitor(c.Ui, client, length)
 	return mon.monitor(resp.EvalID)
 }
+
+// parseDeadline parses a deadline given either as an RFC3339 timestamp or as a
+// duration from now.
+func parseDeadline(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("duration %q must be positive", s)
+		}
+		return now.Add(d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC3339 timestamp nor a duration", s)
+	}
+	return t, nil
+}
@@ -6,6 +6,7 @@ package command
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/open-wander/wander/api"
 	"github.com/open-wander/wander/ci"
@@ -53,6 +54,31 @@ func TestJobDispatchCommand_Fails(t *testing.T) {
 		t.Fatalf("expected failed query error, got: %s", out)
 	}
 	ui.ErrorWriter.Reset()
+
+	// Fails on an invalid deadline
+	must.One(t, cmd.Run([]string{"-deadline=tomorrow", "foo"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "Error parsing deadline")
+	ui.ErrorWriter.Reset()
+}
+
+func TestJobDispatchCommand_parseDeadline(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	deadline, err := parseDeadline("90m", now)
+	must.NoError(t, err)
+	must.Eq(t, now.Add(90*time.Minute), deadline)
+
+	deadline, err = parseDeadline("2023-06-01T18:30:00+02:00", now)
+	must.NoError(t, err)
+	must.True(t, deadline.Equal(time.Date(2023, 6, 1, 16, 30, 0, 0, time.UTC)))
+
+	_, err = parseDeadline("-1h", now)
+	must.ErrorContains(t, err, "must be positive")
+
+	_, err = parseDeadline("tomorrow", now)
+	must.ErrorContains(t, err, "neither an RFC3339 timestamp nor a duration")
 }
 
 func TestJobDispatchCommand_AutocompleteArgs(t *testing.T) {
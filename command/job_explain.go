@@ -0,0 +1,260 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/open-wander/wander/api"
+	"github.com/open-wander/wander/api/contexts"
+	"github.com/posener/complete"
+)
+
+type JobExplainCommand struct {
+	Meta
+}
+
+func (c *JobExplainCommand) Help() string {
+	helpText := `
+Usage: nomad job explain [options] <job>
+
+  Explain why the task groups of a job can or cannot be placed. Every ready
+  node in the job's node pool and datacenters is evaluated against each
+  placement check performed by the scheduler, including constraints, drivers,
+  devices, networks, volumes and available resources. The nodes closest to
+  fitting each task group are marked as closest and the result of each of
+  their checks is displayed.
+
+  When ACLs are enabled, this command requires a token with the 'read-job'
+  capability for the job's namespace. The 'list-jobs' capability is required to
+  run the command with a job prefix instead of the exact job ID.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Explain Options:
+
+  -group <group-name>
+    Only explain the placement of the given task group.
+
+  -json
+    Output the placement explanation in a JSON format.
+
+  -t
+    Format and display the placement explanation using a Go template.
+
+  -verbose
+    Display full node IDs and the result of every check for every node
+    instead of only the nodes closest to fitting the task group.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobExplainCommand) Synopsis() string {
+	return "Explain why a job can or cannot be placed"
+}
+
+func (c *JobExplainCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-group":   complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *JobExplainCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *JobExplainCommand) Name() string { return "job explain" }
+
+func (c *JobExplainCommand) Run(args []string) int {
+	var json, verbose bool
+	var group, tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&group, "group", "", "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one job
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Check if the job exists
+	jobIDPrefix := strings.TrimSpace(args[0])
+	jobID, namespace, err := c.JobIDByPrefix(client, jobIDPrefix, nil)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	q := &api.QueryOptions{Namespace: namespace}
+	resp, _, err := client.Jobs().Explain(jobID, group, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error explaining job placement: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, resp)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	groups := make([]string, 0, len(resp.TaskGroups))
+	for name := range resp.TaskGroups {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+
+	for i, name := range groups {
+		if i > 0 {
+			c.Ui.Output("")
+		}
+		c.Ui.Output(c.Colorize().Color(formatTaskGroupExplanation(resp.TaskGroups[name], verbose, length)))
+	}
+	return 0
+}
+
+// formatTaskGroupExplanation returns the colorized output of the placement
+// explanation of a task group.
+func formatTaskGroupExplanation(tg *api.TaskGroupExplanation, verbose bool, length int) string {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "[bold]Task Group %q[reset]\n", tg.TaskGroup)
+	out.WriteString(formatKV([]string{
+		fmt.Sprintf("Nodes Evaluated|%d", tg.NodesEvaluated),
+		fmt.Sprintf("Nodes Feasible|%d", tg.NodesFeasible),
+		fmt.Sprintf("Requested|%s", formatExplainResources(tg.Requested)),
+	}))
+	out.WriteString("\n")
+
+	if tg.NodesEvaluated == 0 {
+		out.WriteString("\nNo ready nodes in the job's node pool and datacenters")
+		return out.String()
+	}
+
+	// The explanation of each node is omitted for tokens without node read
+	// access, so only the number of nodes failing each check is displayed
+	if len(tg.Nodes) == 0 {
+		out.WriteString("\n[bold]Failed Checks[reset]\n")
+		checks := make([]string, 0, len(tg.NodesFailed))
+		for check := range tg.NodesFailed {
+			checks = append(checks, check)
+		}
+		sort.Strings(checks)
+
+		rows := make([]string, 0, len(checks)+1)
+		rows = append(rows, "Check|Nodes")
+		for _, check := range checks {
+			rows = append(rows, fmt.Sprintf("%s|%d", check, tg.NodesFailed[check]))
+		}
+		out.WriteString(formatList(rows))
+		return out.String()
+	}
+
+	out.WriteString("\n[bold]Nodes[reset]\n")
+	rows := make([]string, 0, len(tg.Nodes)+1)
+	rows = append(rows, "Node ID|Node Name|Datacenter|Closest|Feasible|Score|Available|Failed Checks")
+	for _, node := range tg.Nodes {
+		score := "-"
+		if node.Feasible {
+			score = fmt.Sprintf("%.3g", node.Score)
+		}
+		rows = append(rows, fmt.Sprintf("%s|%s|%s|%v|%v|%s|%s|%s",
+			limit(node.NodeID, length), node.NodeName, node.Datacenter,
+			node.Closest, node.Feasible, score,
+			formatExplainResources(node.Available),
+			strings.Join(explainFailedChecks(node), ", "),
+		))
+	}
+	out.WriteString(formatList(rows))
+
+	for _, node := range tg.Nodes {
+		if !verbose && !node.Closest {
+			continue
+		}
+		fmt.Fprintf(&out, "\n\n[bold]Checks for Node %q (%s)[reset]\n", limit(node.NodeID, length), node.NodeName)
+		checks := make([]string, 0, len(node.Checks)+1)
+		checks = append(checks, "Check|Target|Result|Reason")
+		for _, check := range node.Checks {
+			result := "passed"
+			if !check.Passed {
+				result = "failed"
+			}
+			checks = append(checks, fmt.Sprintf("%s|%s|%s|%s", check.Name, check.Target, result, check.Reason))
+		}
+		out.WriteString(formatList(checks))
+	}
+
+	return out.String()
+}
+
+// explainFailedChecks returns the names of the checks the node failed.
+func explainFailedChecks(node *api.NodeExplanation) []string {
+	var failed []string
+	for _, check := range node.Checks {
+		if check.Passed {
+			continue
+		}
+		if check.Reason != "" {
+			failed = append(failed, fmt.Sprintf("%s (%s)", check.Name, check.Reason))
+		} else {
+			failed = append(failed, check.Name)
+		}
+	}
+	return failed
+}
+
+func formatExplainResources(r *api.ExplainResources) string {
+	if r == nil {
+		return "<none>"
+	}
+	return fmt.Sprintf("%d MHz, %d MiB memory, %d MiB disk", r.CPU, r.MemoryMB, r.DiskMB)
+}
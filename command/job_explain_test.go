@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobExplainCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobExplainCommand{}
+}
+
+func TestJobExplainCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &JobExplainCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Bad job name
+	code = cmd.Run([]string{"-address=" + url, "foo"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), `No job(s) with prefix or ID "foo" found`)
+	ui.ErrorWriter.Reset()
+
+	// Bad task group
+	job := mock.Job()
+	state := srv.Agent.Server().State()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 100, nil, job))
+
+	code = cmd.Run([]string{"-address=" + url, "-group=nope", job.ID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), `task group "nope" not found`)
+}
+
+func TestJobExplainCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	state := srv.Agent.Server().State()
+
+	node := mock.Node()
+	node.Attributes["kernel.name"] = "windows"
+	node.ComputeClass()
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 100, node))
+
+	job := mock.Job()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 101, nil, job))
+
+	ui := cli.NewMockUi()
+	cmd := &JobExplainCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, job.ID})
+	must.Zero(t, code)
+
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, `Task Group "web"`)
+	must.StrContains(t, out, node.ID[:8])
+	must.StrContains(t, out, "constraint (${attr.kernel.name} = linux)")
+	must.StrContains(t, out, `Checks for Node "`+node.ID[:8])
+	ui.OutputWriter.Reset()
+
+	// JSON output
+	code = cmd.Run([]string{"-address=" + url, "-json", job.ID})
+	must.Zero(t, code)
+	must.StrContains(t, ui.OutputWriter.String(), `"NodesEvaluated"`)
+}
@@ -0,0 +1,239 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/open-wander/wander/api"
+	"github.com/open-wander/wander/api/contexts"
+	"github.com/posener/complete"
+)
+
+type JobLogsCommand struct {
+	Meta
+}
+
+func (c *JobLogsCommand) Help() string {
+	helpText := `
+Usage: nomad job logs [options] <job>
+
+  Stream the logs of the tasks of all the allocations of a job, merged into a
+  single stream. Each line is prefixed with the short allocation ID and the
+  task name it was written by. By default the logs of the running allocations
+  are streamed; use -all to include the allocations which have stopped.
+
+  Lines are filtered by the agent serving the request, so only the matching
+  lines are sent to the command. Errors streaming the logs of a task are
+  written to stderr without stopping the other streams, and cause the command
+  to exit with a non-zero status.
+
+  When ACLs are enabled, this command requires a token with the 'read-job' and
+  'read-logs' capabilities for the job's namespace. The 'list-jobs' capability
+  is required to run the command with a job prefix instead of the exact job ID.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Logs Options:
+
+  -group <group-name>
+    Only stream the logs of the allocations of the given task group.
+
+  -task <task-name>
+    Only stream the logs of the given task.
+
+  -stderr
+    Stream the stderr of the tasks instead of their stdout.
+
+  -f
+    Follow the logs of the allocations until they stop. Allocations placed
+    after the command starts are not included.
+
+  -regex <regex>
+    Only output the lines matching the given regular expression.
+
+  -contains <string>
+    Only output the lines containing the given string.
+
+  -since <duration|timestamp>
+    Only output the logs written after the given time, either a duration
+    before now, such as "30m", or an RFC3339 timestamp. Log lines are not
+    timestamped on disk, so the time window applies to whole rotated log
+    files.
+
+  -all
+    Include the logs of the allocations which have stopped.
+
+  -timestamps
+    Prefix each line with the time the agent received it.
+
+  -json
+    Output each line as a JSON object, including the full allocation ID, task
+    group and receive time.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobLogsCommand) Synopsis() string {
+	return "Stream the logs of the allocations of a job"
+}
+
+func (c *JobLogsCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-group":      complete.PredictAnything,
+			"-task":       complete.PredictAnything,
+			"-stderr":     complete.PredictNothing,
+			"-f":          complete.PredictNothing,
+			"-regex":      complete.PredictAnything,
+			"-contains":   complete.PredictAnything,
+			"-since":      complete.PredictAnything,
+			"-all":        complete.PredictNothing,
+			"-timestamps": complete.PredictNothing,
+			"-json":       complete.PredictNothing,
+		})
+}
+
+func (c *JobLogsCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *JobLogsCommand) Name() string { return "job logs" }
+
+func (c *JobLogsCommand) Run(args []string) int {
+	var stderr, follow, all, timestamps, jsonOutput bool
+	opts := &api.JobLogsOptions{}
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&opts.Group, "group", "", "")
+	flags.StringVar(&opts.Task, "task", "", "")
+	flags.BoolVar(&stderr, "stderr", false, "")
+	flags.BoolVar(&follow, "f", false, "")
+	flags.StringVar(&opts.Regex, "regex", "", "")
+	flags.StringVar(&opts.Contains, "contains", "", "")
+	flags.StringVar(&opts.Since, "since", "", "")
+	flags.BoolVar(&all, "all", false, "")
+	flags.BoolVar(&timestamps, "timestamps", false, "")
+	flags.BoolVar(&jsonOutput, "json", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one job
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	opts.Type = api.FSLogNameStdout
+	if stderr {
+		opts.Type = api.FSLogNameStderr
+	}
+	opts.Follow = follow
+	opts.All = all
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Check if the job exists
+	jobIDPrefix := strings.TrimSpace(args[0])
+	jobID, namespace, err := c.JobIDByPrefix(client, jobIDPrefix, nil)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Stop streaming when the user interrupts the command
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+	go func() {
+		select {
+		case <-signalCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	q := &api.QueryOptions{Namespace: namespace}
+	lines, err := client.Jobs().Logs(ctx, jobID, opts, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error streaming job logs: %s", err))
+		return 1
+	}
+
+	code := 0
+	for line := range lines {
+		if line.Error != "" {
+			if line.AllocID != "" {
+				c.Ui.Error(fmt.Sprintf("Error streaming logs of task %q of allocation %q: %s",
+					line.Task, limit(line.AllocID, shortId), line.Error))
+			} else {
+				c.Ui.Error(fmt.Sprintf("Error streaming job logs: %s", line.Error))
+			}
+			code = 1
+			continue
+		}
+
+		if jsonOutput {
+			out, err := json.Marshal(line)
+			if err != nil {
+				c.Ui.Error(fmt.Sprintf("Error formatting log line: %s", err))
+				return 1
+			}
+			c.Ui.Output(string(out))
+			continue
+		}
+
+		c.Ui.Output(formatJobLogLine(line, timestamps))
+	}
+
+	return code
+}
+
+// formatJobLogLine prefixes a log line with its short allocation ID and task
+// name, and optionally the time it was received. Lines logged before the
+// command started have no time.
+func formatJobLogLine(line *api.JobLogLine, timestamps bool) string {
+	prefix := fmt.Sprintf("[%s/%s]", limit(line.AllocID, shortId), line.Task)
+	if timestamps {
+		received := "-"
+		if line.Time != nil {
+			received = line.Time.Local().Format(time.RFC3339Nano)
+		}
+		prefix = received + " " + prefix
+	}
+	return prefix + " " + line.Line
+}
@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/open-wander/wander/api"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobLogsCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobLogsCommand{}
+}
+
+func TestJobLogsCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &JobLogsCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Bad job name
+	code = cmd.Run([]string{"-address=" + url, "foo"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), `No job(s) with prefix or ID "foo" found`)
+	ui.ErrorWriter.Reset()
+
+	job := mock.Job()
+	state := srv.Agent.Server().State()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 100, nil, job))
+
+	// Invalid filter
+	code = cmd.Run([]string{"-address=" + url, "-regex=(", job.ID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "invalid regex")
+	ui.ErrorWriter.Reset()
+
+	// Job without allocations
+	code = cmd.Run([]string{"-address=" + url, job.ID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "no task logs found")
+}
+
+func TestJobLogsCommand_FormatLine(t *testing.T) {
+	ci.Parallel(t)
+
+	line := &api.JobLogLine{
+		AllocID: "0f3c5a8e-7b64-4f6f-b3a1-2b0e0d1a9c55",
+		Task:    "server",
+		Line:    "listening on :8080",
+	}
+	must.Eq(t, "[0f3c5a8e/server] listening on :8080", formatJobLogLine(line, false))
+	must.Eq(t, "- [0f3c5a8e/server] listening on :8080", formatJobLogLine(line, true))
+
+	received := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	line.Time = &received
+	out := formatJobLogLine(line, true)
+	must.StrHasSuffix(t, " [0f3c5a8e/server] listening on :8080", out)
+	must.StrHasPrefix(t, received.Local().Format(time.RFC3339Nano), out)
+}
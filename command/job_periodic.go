@@ -4,20 +4,21 @@
 package command
 
 import (
+	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/mitchellh/cli"
+	"github.com/open-wander/wander/api"
+	"github.com/posener/complete"
 )
 
 type JobPeriodicCommand struct {
 	Meta
 }
 
-func (f *JobPeriodicCommand) Name() string { return "periodic" }
-
-func (f *JobPeriodicCommand) Run(args []string) int {
-	return cli.RunResultHelp
-}
+func (f *JobPeriodicCommand) Name() string { return "job periodic" }
 
 func (f *JobPeriodicCommand) Synopsis() string {
 	return "Interact with periodic jobs"
@@ -26,14 +27,183 @@ func (f *JobPeriodicCommand) Synopsis() string {
 func (f *JobPeriodicCommand) Help() string {
 	helpText := `
 Usage: nomad job periodic <subcommand> [options] [args]
+       nomad job periodic [options] <job id>
+
+  This command groups subcommands for interacting with periodic jobs. When
+  given a job ID, it displays the periodic configuration of the job and the
+  history of its launched child jobs.
+
+  When ACLs are enabled, displaying a periodic job requires a token with the
+  'read-job' capability for the job's namespace. The 'list-jobs' capability is
+  required to run the command with a job prefix instead of the exact job ID.
 
-  This command groups subcommands for interacting with periodic jobs.
+  Display a periodic job's launch history:
+
+      $ nomad job periodic <job_id>
 
   Force a periodic job:
 
       $ nomad job periodic force <job_id>
 
   Please see the individual subcommand help for detailed usage information.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
 `
 	return strings.TrimSpace(helpText)
 }
+
+func (f *JobPeriodicCommand) AutocompleteFlags() complete.Flags {
+	return f.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (f *JobPeriodicCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := f.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Jobs().PrefixList(a.Last)
+		if err != nil {
+			return []string{}
+		}
+
+		// filter this by periodic jobs
+		matches := make([]string, 0, len(resp))
+		for _, job := range resp {
+			if job.Periodic {
+				matches = append(matches, job.ID)
+			}
+		}
+		return matches
+	})
+}
+
+func (f *JobPeriodicCommand) Run(args []string) int {
+	if len(args) == 0 {
+		return cli.RunResultHelp
+	}
+
+	flags := f.Meta.FlagSet(f.Name(), FlagSetClient)
+	flags.Usage = func() { f.Ui.Output(f.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		f.Ui.Error("This command takes one argument: <job id>")
+		f.Ui.Error(commandErrorText(f))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := f.Meta.Client()
+	if err != nil {
+		f.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Check if the job exists
+	jobIDPrefix := strings.TrimSpace(args[0])
+	jobID, namespace, err := f.JobIDByPrefix(client, jobIDPrefix, func(j *api.JobListStub) bool {
+		return j.Periodic && !j.ParameterizedJob
+	})
+	if err != nil {
+		var noPrefixErr *NoJobWithPrefixError
+		if errors.As(err, &noPrefixErr) {
+			err = fmt.Errorf("No periodic job(s) with prefix or ID %q found", jobIDPrefix)
+		}
+		f.Ui.Error(err.Error())
+		return 1
+	}
+
+	q := &api.QueryOptions{Namespace: namespace}
+	history, _, err := client.Jobs().PeriodicHistory(jobID, q)
+	if err != nil {
+		f.Ui.Error(fmt.Sprintf("Error querying periodic job %q: %s", jobID, err))
+		return 1
+	}
+
+	f.Ui.Output(formatPeriodicConfig(jobID, history))
+
+	if len(history.Children) == 0 {
+		f.Ui.Output("\nNo instances of periodic job found")
+		return 0
+	}
+
+	f.Ui.Output(f.Colorize().Color("\n[bold]Launch History[reset]"))
+	f.Ui.Output(formatPeriodicChildren(history.Children))
+	return 0
+}
+
+// formatPeriodicConfig formats the periodic configuration and last launch of
+// a periodic job.
+func formatPeriodicConfig(jobID string, history *api.PeriodicHistory) string {
+	p := history.Periodic
+	specs := p.Specs
+	if p.Spec != nil && *p.Spec != "" {
+		specs = []string{*p.Spec}
+	}
+
+	catchup := api.PeriodicCatchupLast
+	if p.Catchup != nil && *p.Catchup != "" {
+		catchup = *p.Catchup
+	}
+	window := "unlimited"
+	if p.CatchupWindow != nil && *p.CatchupWindow > 0 {
+		window = p.CatchupWindow.String()
+	}
+
+	timeZone := "UTC"
+	if p.TimeZone != nil && *p.TimeZone != "" {
+		timeZone = *p.TimeZone
+	}
+
+	out := []string{
+		fmt.Sprintf("ID|%s", jobID),
+		fmt.Sprintf("Enabled|%v", p.Enabled != nil && *p.Enabled),
+		fmt.Sprintf("Cron|%s", strings.Join(specs, ", ")),
+		fmt.Sprintf("Time Zone|%s", timeZone),
+		fmt.Sprintf("Prohibit Overlap|%v", p.ProhibitOverlap != nil && *p.ProhibitOverlap),
+		fmt.Sprintf("Catchup|%s", catchup),
+		fmt.Sprintf("Catchup Window|%s", window),
+	}
+	if !history.Launch.IsZero() {
+		out = append(out, fmt.Sprintf("Last Launch|%s", formatTime(history.Launch)))
+	}
+
+	if p.Enabled != nil && *p.Enabled {
+		if location, err := p.GetLocation(); err == nil {
+			now := time.Now().In(location)
+			if next, err := p.Next(now); err == nil && !next.IsZero() {
+				out = append(out, fmt.Sprintf("Next Launch|%s (%s from now)",
+					formatTime(next), formatTimeDifference(now, next, time.Second)))
+			}
+		}
+	}
+
+	return formatKV(out)
+}
+
+// formatPeriodicChildren formats the launch history of a periodic job. Child
+// jobs that have been garbage collected are shown with a "gc" status.
+func formatPeriodicChildren(children []*api.PeriodicChild) string {
+	out := make([]string, len(children)+1)
+	out[0] = "ID|Launch Time|Status"
+	for i, child := range children {
+		status := child.Status
+		if status == "" {
+			status = "gc"
+		}
+		out[i+1] = fmt.Sprintf("%s|%s|%s",
+			child.JobID,
+			formatTime(child.Launch),
+			status)
+	}
+	return formatList(out)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/open-wander/wander/api"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobPeriodicCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobPeriodicCommand{}
+}
+
+func TestJobPeriodicCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &JobPeriodicCommand{Meta: Meta{Ui: ui}}
+
+	// Shows help without arguments
+	must.Eq(t, cli.RunResultHelp, cmd.Run(nil))
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=nope", "12"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Error querying job prefix")
+}
+
+func TestJobPeriodicCommand_History(t *testing.T) {
+	ci.Parallel(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	// Register a periodic job and force a launch.
+	j := testJob("job_history_periodic")
+	j.Periodic = &api.PeriodicConfig{
+		SpecType:      pointer.Of(api.PeriodicSpecCron),
+		Specs:         []string{"0 0 * * *", "0 12 * * *"},
+		Catchup:       pointer.Of(api.PeriodicCatchupAll),
+		CatchupWindow: pointer.Of(6 * time.Hour),
+	}
+	_, _, err := client.Jobs().Register(j, nil)
+	must.NoError(t, err)
+	_, _, err = client.Jobs().PeriodicForce("job_history_periodic", nil)
+	must.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &JobPeriodicCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	code := cmd.Run([]string{"-address=" + url, "job_history_periodic"})
+	must.Zero(t, code)
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, "0 0 * * *, 0 12 * * *")
+	must.StrContains(t, out, "Catchup Window   = 6h0m0s")
+	must.StrContains(t, out, "Launch History")
+	must.StrContains(t, out, "job_history_periodic/periodic-")
+}
@@ -5,6 +5,7 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -29,6 +30,7 @@ type LoginCommand struct {
 	authMethodName string
 	callbackAddr   string
 	loginToken     string
+	username       string
 
 	template string
 	json     bool
@@ -58,7 +60,14 @@ Login Options:
 
   -login-token
     Login token used for authentication that will be exchanged for a Nomad ACL
-    Token. It is only required if using auth method type other than OIDC. 
+    Token. It is only required if using auth method type other than OIDC or
+    TLSCert, which uses the certificate given by -client-cert instead. For
+    LDAP auth methods this is the password of the user, which is prompted for
+    when omitted.
+
+  -username
+    The name of the user to login as when using an LDAP auth method. It is
+    prompted for when omitted.
 
   -json
     Output the ACL token in JSON format.
@@ -80,6 +89,7 @@ func (l *LoginCommand) AutocompleteFlags() complete.Flags {
 			"-method":             complete.PredictAnything,
 			"-oidc-callback-addr": complete.PredictAnything,
 			"-login-token":        complete.PredictAnything,
+			"-username":           complete.PredictAnything,
 			"-json":               complete.PredictNothing,
 			"-t":                  complete.PredictAnything,
 		})
@@ -96,6 +106,7 @@ func (l *LoginCommand) Run(args []string) int {
 	flags.StringVar(&l.authMethodName, "method", "", "")
 	flags.StringVar(&l.authMethodType, "type", "", "")
 	flags.StringVar(&l.loginToken, "login-token", "", "")
+	flags.StringVar(&l.username, "username", "", "")
 	flags.StringVar(&l.callbackAddr, "oidc-callback-addr", "localhost:4649", "")
 	flags.BoolVar(&l.json, "json", false, "")
 	flags.StringVar(&l.template, "t", "", "")
@@ -164,8 +175,15 @@ func (l *LoginCommand) Run(args []string) int {
 		}
 	}
 
-	// Make sure we got the login token if we're not using OIDC
-	if methodType != api.ACLAuthMethodTypeOIDC && l.loginToken == "" {
+	// Make sure we got the login token if we're not using OIDC. LDAP logins
+	// prompt for the credentials instead.
+	if methodType == api.ACLAuthMethodTypeLDAP {
+		if err := l.promptLDAPCredentials(); err != nil {
+			l.Ui.Error(fmt.Sprintf("Error reading credentials: %s", err))
+			return 1
+		}
+	} else if methodType != api.ACLAuthMethodTypeOIDC &&
+		methodType != api.ACLAuthMethodTypeTLSCert && l.loginToken == "" {
 		l.Ui.Error("You need to provide a login token.")
 		return 1
 	}
@@ -180,6 +198,10 @@ func (l *LoginCommand) Run(args []string) int {
 		authFn = l.loginOIDC
 	case api.ACLAuthMethodTypeJWT:
 		authFn = l.loginJWT
+	case api.ACLAuthMethodTypeLDAP:
+		authFn = l.loginLDAP
+	case api.ACLAuthMethodTypeTLSCert:
+		authFn = l.loginTLSCert
 	default:
 		l.Ui.Error(fmt.Sprintf("Unsupported authentication type %q", methodType))
 		return 1
@@ -271,6 +293,50 @@ func (l *LoginCommand) loginJWT(ctx context.Context, client *api.Client) (*api.A
 	return token, err
 }
 
+func (l *LoginCommand) loginLDAP(ctx context.Context, client *api.Client) (*api.ACLToken, error) {
+	authArgs := api.ACLLoginRequest{
+		AuthMethodName: l.authMethodName,
+		Username:       l.username,
+		LoginToken:     l.loginToken,
+	}
+	token, _, err := client.ACLAuth().Login(&authArgs, nil)
+	return token, err
+}
+
+// loginTLSCert logs in using the client certificate the API client presents
+// when connecting to the agent, as configured with -client-cert and
+// -client-key.
+func (l *LoginCommand) loginTLSCert(ctx context.Context, client *api.Client) (*api.ACLToken, error) {
+	authArgs := api.ACLLoginRequest{
+		AuthMethodName: l.authMethodName,
+	}
+	token, _, err := client.ACLAuth().Login(&authArgs, nil)
+	return token, err
+}
+
+// promptLDAPCredentials asks the user for the username and password to use
+// with an LDAP auth method, unless they were passed as flags.
+func (l *LoginCommand) promptLDAPCredentials() error {
+	var err error
+	if l.username == "" {
+		if l.username, err = l.Ui.Ask("Username:"); err != nil {
+			return err
+		}
+		if l.username == "" {
+			return errors.New("username is required")
+		}
+	}
+	if l.loginToken == "" {
+		if l.loginToken, err = l.Ui.AskSecret("Password:"); err != nil {
+			return err
+		}
+		if l.loginToken == "" {
+			return errors.New("password is required")
+		}
+	}
+	return nil
+}
+
 const (
 	// oidcErrorVisitURLMsg is a message to show users when opening the OIDC
 	// provider URL automatically fails. This type of message is otherwise not
@@ -4,6 +4,7 @@
 package command
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/open-wander/wander/ci"
@@ -74,6 +75,26 @@ func TestLoginCommand_Run(t *testing.T) {
 	ui.OutputWriter.Reset()
 	ui.ErrorWriter.Reset()
 
+	// Store an LDAP auth method pointing at an unreachable server. The
+	// password is prompted for and the login fails server side.
+	ldapMethod := &structs.ACLAuthMethod{
+		Name: "test-ldap-auth-method",
+		Type: "LDAP",
+		Config: &structs.ACLAuthMethodConfig{
+			LDAPURLs:   []string{"ldap://127.0.0.1:1"},
+			LDAPUserDN: "ou=people,dc=example,dc=org",
+		},
+	}
+	ldapMethod.SetHash()
+	must.NoError(t, state.UpsertACLAuthMethods(1001, []*structs.ACLAuthMethod{ldapMethod}))
+
+	ui.InputReader = strings.NewReader("password\n")
+	must.Eq(t, 1, cmd.Run([]string{"-address=" + agentURL, "-method", ldapMethod.Name, "-username", "alice"}))
+	must.StrContains(t, ui.OutputWriter.String(), "Password:")
+	must.StrContains(t, ui.ErrorWriter.String(), "unable to authenticate with LDAP")
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
 	// TODO(jrasell) find a way to test the full login flow from the CLI
 	//  perspective.
 }
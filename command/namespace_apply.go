@@ -227,6 +227,7 @@ func parseNamespaceSpecImpl(result *api.Namespace, list *ast.ObjectList) error {
 	delete(m, "capabilities")
 	delete(m, "meta")
 	delete(m, "node_pool_config")
+	delete(m, "exec_recording")
 
 	// Decode the rest
 	if err := mapstructure.WeakDecode(m, result); err != nil {
@@ -265,6 +266,22 @@ func parseNamespaceSpecImpl(result *api.Namespace, list *ast.ObjectList) error {
 		}
 	}
 
+	erObj := list.Filter("exec_recording")
+	if len(erObj.Items) > 0 {
+		for _, o := range erObj.Elem().Items {
+			ot, ok := o.Val.(*ast.ObjectType)
+			if !ok {
+				break
+			}
+			var erConfig *api.NamespaceExecRecordingConfiguration
+			if err := hcl.DecodeObject(&erConfig, ot.List); err != nil {
+				return err
+			}
+			result.ExecRecording = erConfig
+			break
+		}
+	}
+
 	if metaO := list.Filter("meta"); len(metaO.Items) > 0 {
 		for _, o := range metaO.Elem().Items {
 			var m map[string]interface{}
@@ -88,6 +88,10 @@ node_pool_config {
   allowed = ["prod*"]
 }
 
+exec_recording {
+  enabled = true
+}
+
 meta {
   dept = "eng"
 }`,
@@ -103,6 +107,9 @@ meta {
 					Default: "dev",
 					Allowed: []string{"prod*"},
 				},
+				ExecRecording: &api.NamespaceExecRecordingConfiguration{
+					Enabled: true,
+				},
 				Meta: map[string]string{
 					"dept": "eng",
 				},
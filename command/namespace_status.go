@@ -164,6 +164,13 @@ func (c *NamespaceStatusCommand) Run(args []string) int {
 		c.Ui.Output(formatKV(npConfigOut))
 	}
 
+	if ns.ExecRecording != nil {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Exec Recording[reset]"))
+		c.Ui.Output(formatKV([]string{
+			fmt.Sprintf("Enabled|%t", ns.ExecRecording.Enabled),
+		}))
+	}
+
 	return 0
 }
 
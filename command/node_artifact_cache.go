@@ -0,0 +1,38 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type NodeArtifactCacheCommand struct {
+	Meta
+}
+
+func (c *NodeArtifactCacheCommand) Help() string {
+	helpText := `
+Usage: nomad node artifact-cache [subcommand]
+
+  Interact with a node's artifact cache. The list subcommand lists the cached
+  artifacts along with the hit rate of the cache. The purge subcommand removes
+  one or all of the cached artifacts. All commands interact directly with a
+  client and allow setting a custom target with the -node-id option.
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeArtifactCacheCommand) Synopsis() string {
+	return "Interact with the node artifact cache"
+}
+
+func (c *NodeArtifactCacheCommand) Name() string { return "node artifact-cache" }
+
+func (c *NodeArtifactCacheCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
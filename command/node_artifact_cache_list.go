@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/open-wander/wander/api"
+	"github.com/posener/complete"
+)
+
+type NodeArtifactCacheListCommand struct {
+	Meta
+}
+
+func (c *NodeArtifactCacheListCommand) Help() string {
+	helpText := `
+Usage: nomad node artifact-cache list [options]
+
+  List the artifacts cached by a node, most recently used first, along with
+  the size and the hit rate of the cache. This command only works on client
+  agents.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Node Artifact Cache List Options:
+
+  -node-id
+    Lists the artifact cache of the specified node. If not specified the node
+    receiving the request will be used by default.
+
+  -verbose
+    Display full artifact keys.
+
+  -json
+    Output the artifact cache in its JSON format.
+
+  -t
+    Format and display the artifact cache using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeArtifactCacheListCommand) Synopsis() string {
+	return "List the artifacts cached by a node"
+}
+
+func (c *NodeArtifactCacheListCommand) Name() string { return "node artifact-cache list" }
+
+func (c *NodeArtifactCacheListCommand) Run(args []string) int {
+	var nodeID, tmpl string
+	var verbose, json bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.BoolVar(&json, "json", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Lookup nodeID
+	if nodeID != "" {
+		nodeID, err = lookupNodeID(client.Nodes(), nodeID)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	cache, err := client.Nodes().ArtifactCache().List(nodeID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing artifact cache: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, cache)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if !cache.Enabled {
+		c.Ui.Output("Artifact cache is disabled")
+		return 0
+	}
+
+	c.Ui.Output(formatKV(formatArtifactCacheStats(cache)))
+
+	if len(cache.Entries) == 0 {
+		c.Ui.Output("\nNo cached artifacts")
+		return 0
+	}
+
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Artifacts[reset]"))
+	rows := make([]string, 0, len(cache.Entries)+1)
+	rows = append(rows, "Key|Source|Mode|Size|Hits|Last Used")
+	for _, e := range cache.Entries {
+		rows = append(rows, fmt.Sprintf("%s|%s|%s|%s|%d|%s",
+			limit(e.Key, length),
+			e.Source,
+			e.Mode,
+			humanize.IBytes(uint64(e.Size)),
+			e.Hits,
+			formatUnixNanoTime(e.LastUseTime),
+		))
+	}
+	c.Ui.Output(formatList(rows))
+	return 0
+}
+
+// formatArtifactCacheStats returns the size and hit rate of the artifact
+// cache as key/value rows.
+func formatArtifactCacheStats(cache *api.ArtifactCacheListResponse) []string {
+	hitRate := "<none>"
+	if lookups := cache.Hits + cache.Misses; lookups > 0 {
+		hitRate = fmt.Sprintf("%.1f%%", float64(cache.Hits)/float64(lookups)*100)
+	}
+
+	return []string{
+		fmt.Sprintf("Size|%s / %s", humanize.IBytes(uint64(cache.Size)), humanize.IBytes(uint64(cache.MaxSize))),
+		fmt.Sprintf("Artifacts|%d", len(cache.Entries)),
+		fmt.Sprintf("Hits|%d", cache.Hits),
+		fmt.Sprintf("Misses|%d", cache.Misses),
+		fmt.Sprintf("Hit Rate|%s", hitRate),
+	}
+}
+
+func (c *NodeArtifactCacheListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": complete.PredictAnything,
+			"-verbose": complete.PredictNothing,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+		})
+}
+
+func (c *NodeArtifactCacheListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
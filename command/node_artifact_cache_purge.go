@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/open-wander/wander/api"
+	"github.com/posener/complete"
+)
+
+type NodeArtifactCachePurgeCommand struct {
+	Meta
+}
+
+func (c *NodeArtifactCachePurgeCommand) Help() string {
+	helpText := `
+Usage: nomad node artifact-cache purge [options] [<key>]
+
+  Remove an artifact from the artifact cache of a node. The artifact is
+  identified by its key or a unique prefix of it, as displayed by the "node
+  artifact-cache list" command. If no key is given, all the cached artifacts
+  are removed, except those being placed into a task directory. Artifacts
+  already placed into task directories are not affected. This command only
+  works on client agents.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Node Artifact Cache Purge Options:
+
+  -node-id
+    Purges the artifact cache of the specified node. If not specified the node
+    receiving the request will be used by default.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeArtifactCachePurgeCommand) Synopsis() string {
+	return "Remove artifacts from the node artifact cache"
+}
+
+func (c *NodeArtifactCachePurgeCommand) Name() string { return "node artifact-cache purge" }
+
+func (c *NodeArtifactCachePurgeCommand) Run(args []string) int {
+	var nodeID string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) > 1 {
+		c.Ui.Error("This command takes at most one argument: [<key>]")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Lookup nodeID
+	if nodeID != "" {
+		nodeID, err = lookupNodeID(client.Nodes(), nodeID)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	// Resolve the key from its prefix
+	var key string
+	if len(args) == 1 {
+		key, err = lookupArtifactCacheKey(client.Nodes().ArtifactCache(), nodeID, args[0])
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	resp, err := client.Nodes().ArtifactCache().Purge(nodeID, key, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error purging artifact cache: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Purged %d artifact(s), freeing %s",
+		len(resp.Purged), humanize.IBytes(uint64(resp.Size))))
+	return 0
+}
+
+// lookupArtifactCacheKey returns the key of the cached artifact matching the
+// prefix.
+func lookupArtifactCacheKey(cache *api.NodeArtifactCache, nodeID, prefix string) (string, error) {
+	list, err := cache.List(nodeID, nil)
+	if err != nil {
+		return "", fmt.Errorf("Error listing artifact cache: %s", err)
+	}
+
+	var matches []string
+	for _, e := range list.Entries {
+		if strings.HasPrefix(e.Key, prefix) {
+			matches = append(matches, e.Key)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("No cached artifact with key prefix %q found", prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("Prefix %q matched multiple cached artifacts:\n\n%s",
+			prefix, strings.Join(matches, "\n"))
+	}
+}
+
+func (c *NodeArtifactCachePurgeCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": complete.PredictAnything,
+		})
+}
+
+func (c *NodeArtifactCachePurgeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
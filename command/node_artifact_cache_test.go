@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/command/agent"
+	"github.com/open-wander/wander/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestNodeArtifactCacheCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &NodeArtifactCacheCommand{}
+	var _ cli.Command = &NodeArtifactCacheListCommand{}
+	var _ cli.Command = &NodeArtifactCachePurgeCommand{}
+}
+
+func TestNodeArtifactCacheCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, true, func(c *agent.Config) {
+		c.Client.Artifact.CacheSize = pointer.Of("1MiB")
+	})
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	list := &NodeArtifactCacheListCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	must.One(t, list.Run([]string{"-address=" + url, "extra"}))
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(list))
+	ui.ErrorWriter.Reset()
+
+	must.Zero(t, list.Run([]string{"-address=" + url}))
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, "0 B / 1.0 MiB")
+	must.StrContains(t, out, "No cached artifacts")
+	ui.OutputWriter.Reset()
+
+	purge := &NodeArtifactCachePurgeCommand{Meta: Meta{Ui: ui}}
+	must.One(t, purge.Run([]string{"-address=" + url, "f3a1c29e"}))
+	must.StrContains(t, ui.ErrorWriter.String(), `No cached artifact with key prefix "f3a1c29e" found`)
+
+	must.Zero(t, purge.Run([]string{"-address=" + url}))
+	must.StrContains(t, ui.OutputWriter.String(), "Purged 0 artifact(s)")
+}
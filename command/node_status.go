@@ -488,6 +488,7 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 		fmt.Sprintf("Drain|%v", formatDrain(node)),
 		fmt.Sprintf("Eligibility|%s", node.SchedulingEligibility),
 		fmt.Sprintf("Status|%s", node.Status),
+		fmt.Sprintf("Conditions|%s", formatNodeConditions(node)),
 		fmt.Sprintf("CSI Controllers|%s", strings.Join(nodeCSIControllerNames(node), ",")),
 		fmt.Sprintf("CSI Drivers|%s", strings.Join(nodeCSINodeNames(node), ",")),
 	}
@@ -538,6 +539,7 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 		c.outputNodeNetworkInfo(node)
 		c.outputNodeCSIVolumeInfo(client, node, runningAllocs)
 		c.outputNodeDriverInfo(node)
+		c.outputNodeConditions(node)
 	}
 
 	// Emit node events
@@ -746,6 +748,36 @@ func (c *NodeStatusCommand) outputNodeDriverInfo(node *api.Node) {
 	c.Ui.Output(formatList(nodeDrivers))
 }
 
+func (c *NodeStatusCommand) outputNodeConditions(node *api.Node) {
+	c.Ui.Output(c.Colorize().Color("\n[bold]Node Conditions"))
+	if len(node.Conditions) == 0 {
+		c.Ui.Output("No resource pressure")
+		return
+	}
+
+	conditions := make([]string, 0, len(node.Conditions)+1)
+	conditions = append(conditions, "Type|Since|Message")
+	for _, condition := range node.Conditions {
+		conditions = append(conditions, fmt.Sprintf("%s|%s|%s",
+			condition.Type, formatTime(condition.Since), condition.Message))
+	}
+	c.Ui.Output(formatList(conditions))
+}
+
+// formatNodeConditions returns the types of the resource pressure conditions
+// of the node.
+func formatNodeConditions(node *api.Node) string {
+	if len(node.Conditions) == 0 {
+		return "none"
+	}
+
+	types := make([]string, 0, len(node.Conditions))
+	for _, condition := range node.Conditions {
+		types = append(types, condition.Type)
+	}
+	return strings.Join(types, ",")
+}
+
 func (c *NodeStatusCommand) outputNodeStatusEvents(node *api.Node) {
 	c.Ui.Output(c.Colorize().Color("\n[bold]Node Events"))
 	c.outputNodeEvent(node.Events)
@@ -15,6 +15,7 @@ import (
 	"github.com/open-wander/wander/testutil"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -154,6 +155,9 @@ func TestNodeStatusCommand_Run(t *testing.T) {
 	if !strings.Contains(out, nodeID) {
 		t.Fatalf("expected full node id %q, got: %s", nodeID, out)
 	}
+	if !strings.Contains(out, "No resource pressure") {
+		t.Fatalf("expected node conditions, got: %s", out)
+	}
 	ui.OutputWriter.Reset()
 
 	// Identifiers with uneven length should produce a query result
@@ -297,3 +301,16 @@ func TestNodeStatusCommand_FormatDrain(t *testing.T) {
 	node.DrainStrategy.IgnoreSystemJobs = true
 	assert.Equal("true; 1970-01-01T00:00:01Z deadline; ignoring system jobs", formatDrain(node))
 }
+
+func TestNodeStatusCommand_formatNodeConditions(t *testing.T) {
+	ci.Parallel(t)
+
+	node := &api.Node{}
+	must.Eq(t, "none", formatNodeConditions(node))
+
+	node.Conditions = []*api.NodeCondition{
+		{Type: api.NodeConditionMemoryPressure},
+		{Type: api.NodeConditionDiskPressure},
+	}
+	must.Eq(t, "MemoryPressure,DiskPressure", formatNodeConditions(node))
+}
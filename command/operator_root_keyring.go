@@ -42,6 +42,10 @@ Usage: nomad operator root keyring [options]
 
       $ nomad operator root keyring remove <key ID>
 
+  Wrap the keystore of a stopped server with its configured KMS providers:
+
+      $ nomad operator root keyring migrate <config>
+
   Please see individual subcommand help for detailed usage information.
 `
 	return strings.TrimSpace(helpText)
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/open-wander/wander/command/agent"
+	"github.com/open-wander/wander/nomad"
+	"github.com/posener/complete"
+)
+
+// OperatorRootKeyringMigrateCommand is a Command implementation that wraps the
+// root keys in the keystore of a stopped server with the key encryption key
+// providers of its configuration.
+type OperatorRootKeyringMigrateCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringMigrateCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring migrate <config>...
+
+  Wrap the root keys in the keystore of a server with the key encryption key
+  providers set by the keyring blocks of the server's configuration, and remove
+  the keys wrapped by providers which are no longer configured. This is used to
+  move the keystore from the default aead provider, which stores the key
+  encryption keys on disk, to an external KMS, or from one KMS to another.
+
+  The arguments are the configuration files or directories of the server, as
+  passed to "nomad agent -config". Each root key is unwrapped by any provider
+  which wrapped it and is still configured, or by the aead provider, so keep
+  the previous provider configured with "active = false" when moving between
+  external KMS providers.
+
+  This command requires file system permissions to access the data directory on
+  disk, and must not be run while the Nomad server is running. Servers wrap
+  their keystore with the configured providers on startup, so this command is
+  only needed to migrate a keystore before the server starts.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringMigrateCommand) Synopsis() string {
+	return "Wraps the keystore with the configured KMS providers"
+}
+
+func (c *OperatorRootKeyringMigrateCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{}
+}
+
+func (c *OperatorRootKeyringMigrateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *OperatorRootKeyringMigrateCommand) Name() string {
+	return "operator root keyring migrate"
+}
+
+func (c *OperatorRootKeyringMigrateCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), 0)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	configPaths := flags.Args()
+	if len(configPaths) < 1 {
+		c.Ui.Error("This command takes at least one argument: <config>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	config := agent.DefaultConfig()
+	var mErr multierror.Error
+	for _, path := range configPaths {
+		fc, err := agent.LoadConfig(path)
+		if err != nil {
+			multierror.Append(&mErr, fmt.Errorf(
+				"Error loading configuration from %s: %s", path, err))
+			continue
+		}
+		config = config.Merge(fc)
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if config.DataDir == "" {
+		c.Ui.Error("The configuration must set data_dir")
+		return 1
+	}
+	keystorePath := filepath.Join(config.DataDir, "server", "keystore")
+
+	keyIDs, err := nomad.MigrateKeystore(context.Background(), nil,
+		keystorePath, config.Server.KEKProviders)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error migrating keystore: %s", err))
+		return 1
+	}
+
+	if len(keyIDs) == 0 {
+		c.Ui.Output(fmt.Sprintf("No root keys found in keystore %s", keystorePath))
+		return 0
+	}
+	for _, keyID := range keyIDs {
+		c.Ui.Output(fmt.Sprintf("Migrated root key %s", keyID))
+	}
+	return 0
+}
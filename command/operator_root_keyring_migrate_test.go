@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/open-wander/wander/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestOperatorRootKeyringMigrateCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &OperatorRootKeyringMigrateCommand{}
+}
+
+func TestOperatorRootKeyringMigrateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	dataDir := t.TempDir()
+	must.NoError(t, os.MkdirAll(filepath.Join(dataDir, "server", "keystore"), 0o700))
+
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, "server.hcl")
+	must.NoError(t, os.WriteFile(configPath,
+		[]byte(fmt.Sprintf("data_dir = %q\n", dataDir)), 0o600))
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorRootKeyringMigrateCommand{Meta: Meta{Ui: ui}}
+
+	// Missing config
+	must.One(t, cmd.Run(nil))
+	must.StrContains(t, ui.ErrorWriter.String(), "This command takes at least one argument")
+	ui.ErrorWriter.Reset()
+
+	// Empty keystore
+	must.Zero(t, cmd.Run([]string{configPath}))
+	must.StrContains(t, ui.OutputWriter.String(), "No root keys found in keystore")
+	ui.OutputWriter.Reset()
+
+	// Invalid keyring configuration
+	must.NoError(t, os.WriteFile(filepath.Join(configDir, "keyring.hcl"), []byte(`
+server {
+  keyring "aead" {}
+}
+`), 0o600))
+	must.One(t, cmd.Run([]string{configDir}))
+	must.StrContains(t, ui.ErrorWriter.String(), "exactly one keyring must be active")
+}
@@ -7,13 +7,15 @@ import (
 	"github.com/open-wander/wander/plugins/base"
 	"github.com/open-wander/wander/plugins/device"
 	"github.com/open-wander/wander/plugins/drivers"
+	"github.com/open-wander/wander/plugins/scheduler"
 )
 
 var (
 	// AgentSupportedApiVersions is the set of API versions supported by the
 	// Nomad agent by plugin type.
 	AgentSupportedApiVersions = map[string][]string{
-		base.PluginTypeDevice: {device.ApiVersion010},
-		base.PluginTypeDriver: {drivers.ApiVersion010},
+		base.PluginTypeDevice:    {device.ApiVersion010},
+		base.PluginTypeDriver:    {drivers.ApiVersion010},
+		base.PluginTypeScheduler: {scheduler.ApiVersion010},
 	}
 )
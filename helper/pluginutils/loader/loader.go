@@ -16,6 +16,7 @@ import (
 	"github.com/open-wander/wander/plugins/base"
 	"github.com/open-wander/wander/plugins/device"
 	"github.com/open-wander/wander/plugins/drivers"
+	"github.com/open-wander/wander/plugins/scheduler"
 	"github.com/open-wander/wander/plugins/shared/hclspec"
 )
 
@@ -275,6 +276,8 @@ func getPluginMap(pluginType string, logger log.Logger) map[string]plugin.Plugin
 		pmap[base.PluginTypeDevice] = &device.PluginDevice{}
 	case base.PluginTypeDriver:
 		pmap[base.PluginTypeDriver] = drivers.NewDriverPlugin(nil, logger)
+	case base.PluginTypeScheduler:
+		pmap[base.PluginTypeScheduler] = &scheduler.PluginScheduler{}
 	}
 
 	return pmap
@@ -0,0 +1,308 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package signature verifies detached signatures of files, as produced by
+// minisign and by cosign's sign-blob command, against trusted public keys.
+package signature
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// FormatMinisign is the format of minisign signatures and public keys.
+	FormatMinisign = "minisign"
+
+	// FormatCosign is the format of the signatures of cosign sign-blob,
+	// verified against PEM encoded public keys.
+	FormatCosign = "cosign"
+)
+
+const (
+	// minisignAlg is the algorithm of minisign keys and of the signatures of
+	// whole files, minisignAlgHashed the algorithm of the signatures of
+	// BLAKE2b-512 digests of files.
+	minisignAlg       = "Ed"
+	minisignAlgHashed = "ED"
+
+	minisignKeyIDSize       = 8
+	minisignUntrustedPrefix = "untrusted comment:"
+	minisignTrustedPrefix   = "trusted comment:"
+)
+
+var (
+	// ErrInvalidSignature is returned when a signature was not made by any of
+	// the trusted keys.
+	ErrInvalidSignature = errors.New("signature was not made by a trusted key")
+
+	// ErrNoKeys is returned when there are no keys of the format of the
+	// signature to verify it against.
+	ErrNoKeys = errors.New("no trusted keys")
+)
+
+// PublicKey is a public key trusted to sign files.
+type PublicKey struct {
+	// Format is the format of the signatures made by the key.
+	Format string
+
+	// keyID is the identifier of a minisign key
+	keyID []byte
+
+	key crypto.PublicKey
+}
+
+// ParseKey parses a minisign public key, with or without its untrusted
+// comment line, or a PEM encoded public key for cosign signatures.
+func ParseKey(s string) (*PublicKey, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "-----BEGIN") {
+		return parseCosignKey(s)
+	}
+	return parseMinisignKey(s)
+}
+
+func parseCosignKey(s string) (*PublicKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("invalid PEM encoded public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+	return &PublicKey{Format: FormatCosign, key: key}, nil
+}
+
+func parseMinisignKey(s string) (*PublicKey, error) {
+	lines := minisignLines(s)
+	if len(lines) == 2 && strings.HasPrefix(lines[0], minisignUntrustedPrefix) {
+		lines = lines[1:]
+	}
+	if len(lines) != 1 {
+		return nil, errors.New("invalid minisign public key")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid minisign public key: %w", err)
+	}
+	if len(b) != len(minisignAlg)+minisignKeyIDSize+ed25519.PublicKeySize || string(b[:2]) != minisignAlg {
+		return nil, errors.New("invalid minisign public key")
+	}
+	return &PublicKey{
+		Format: FormatMinisign,
+		keyID:  b[2 : 2+minisignKeyIDSize],
+		key:    ed25519.PublicKey(b[2+minisignKeyIDSize:]),
+	}, nil
+}
+
+// ParseKeys parses the named public keys.
+func ParseKeys(keys map[string]string) (map[string]*PublicKey, error) {
+	parsed := make(map[string]*PublicKey, len(keys))
+	for name, s := range keys {
+		key, err := ParseKey(s)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", name, err)
+		}
+		parsed[name] = key
+	}
+	return parsed, nil
+}
+
+// VerifyFile verifies the detached signature of the file at path against the
+// keys of the format of the signature, and returns the name of the key that
+// made it.
+func VerifyFile(format, path string, sig []byte, keys map[string]*PublicKey) (string, error) {
+	names := make([]string, 0, len(keys))
+	for name, key := range keys {
+		if key.Format == format {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", ErrNoKeys
+	}
+	sort.Strings(names)
+
+	m := &message{path: path}
+
+	var verify func(*message, []byte, *PublicKey) (bool, error)
+	switch format {
+	case FormatMinisign:
+		verify = verifyMinisign
+	case FormatCosign:
+		verify = verifyCosign
+	default:
+		return "", fmt.Errorf("unsupported signature format %q", format)
+	}
+
+	for _, name := range names {
+		ok, err := verify(m, sig, keys[name])
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return name, nil
+		}
+	}
+	return "", ErrInvalidSignature
+}
+
+// message is a signed file, which is only read once whatever the number of
+// keys its signature is verified against.
+type message struct {
+	path string
+
+	content []byte
+	sha256  []byte
+	blake2b []byte
+}
+
+// digest computes the SHA-256 and BLAKE2b-512 digests of the file without
+// reading it into memory.
+func (m *message) digest() error {
+	if m.sha256 != nil {
+		return nil
+	}
+
+	f, err := os.Open(m.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s := sha256.New()
+	b, err := blake2b.New512(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(io.MultiWriter(s, b), f); err != nil {
+		return err
+	}
+	m.sha256, m.blake2b = s.Sum(nil), b.Sum(nil)
+	return nil
+}
+
+// raw returns the content of the file, for signatures made over the whole
+// file rather than over its digest.
+func (m *message) raw() ([]byte, error) {
+	if m.content != nil {
+		return m.content, nil
+	}
+	b, err := os.ReadFile(m.path)
+	if err != nil {
+		return nil, err
+	}
+	m.content = b
+	return b, nil
+}
+
+// verifyMinisign verifies a minisign signature file, including the global
+// signature over its trusted comment.
+func verifyMinisign(m *message, sig []byte, key *PublicKey) (bool, error) {
+	lines := minisignLines(string(sig))
+	if len(lines) != 4 ||
+		!strings.HasPrefix(lines[0], minisignUntrustedPrefix) ||
+		!strings.HasPrefix(lines[2], minisignTrustedPrefix) {
+		return false, errors.New("invalid minisign signature")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(b) != 2+minisignKeyIDSize+ed25519.SignatureSize {
+		return false, errors.New("invalid minisign signature")
+	}
+	alg, keyID, signature := string(b[:2]), b[2:2+minisignKeyIDSize], b[2+minisignKeyIDSize:]
+
+	global, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return false, errors.New("invalid minisign global signature")
+	}
+
+	if !bytes.Equal(keyID, key.keyID) {
+		return false, nil
+	}
+	pk := key.key.(ed25519.PublicKey)
+
+	var signed []byte
+	switch alg {
+	case minisignAlg:
+		if signed, err = m.raw(); err != nil {
+			return false, err
+		}
+	case minisignAlgHashed:
+		if err := m.digest(); err != nil {
+			return false, err
+		}
+		signed = m.blake2b
+	default:
+		return false, fmt.Errorf("unsupported minisign signature algorithm %q", alg)
+	}
+	if !ed25519.Verify(pk, signed, signature) {
+		return false, nil
+	}
+
+	trusted := strings.TrimPrefix(lines[2], minisignTrustedPrefix)
+	trusted = strings.TrimPrefix(trusted, " ")
+	signed = append(bytes.Clone(signature), trusted...)
+	return ed25519.Verify(pk, signed, global), nil
+}
+
+// verifyCosign verifies a base64 or raw signature of cosign sign-blob.
+func verifyCosign(m *message, sig []byte, key *PublicKey) (bool, error) {
+	if b, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(sig))); err == nil {
+		sig = b
+	}
+
+	if pk, ok := key.key.(ed25519.PublicKey); ok {
+		content, err := m.raw()
+		if err != nil {
+			return false, err
+		}
+		return ed25519.Verify(pk, content, sig), nil
+	}
+
+	if err := m.digest(); err != nil {
+		return false, err
+	}
+	switch pk := key.key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(pk, m.sha256, sig), nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(pk, crypto.SHA256, m.sha256, sig) == nil, nil
+	}
+	return false, fmt.Errorf("unsupported public key type %T", key.key)
+}
+
+// minisignLines returns the non-empty lines of a minisign key or signature.
+func minisignLines(s string) []string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
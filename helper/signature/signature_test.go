@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/shoenig/test/must"
+	"golang.org/x/crypto/blake2b"
+)
+
+var testContent = []byte("#!/bin/sh\necho hello\n")
+
+func writeTestFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "artifact")
+	must.NoError(t, os.WriteFile(path, testContent, 0o644))
+	return path
+}
+
+// minisignKey returns a minisign key pair with the given key id.
+func minisignKey(t *testing.T, id string) (string, ed25519.PrivateKey) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	must.NoError(t, err)
+
+	b := append([]byte(minisignAlg), id...)
+	b = append(b, pub...)
+	return "untrusted comment: minisign public key " + id + "\n" +
+		base64.StdEncoding.EncodeToString(b) + "\n", priv
+}
+
+// minisignSign signs the content like minisign, with the given id and
+// algorithm.
+func minisignSign(priv ed25519.PrivateKey, id, alg string, content []byte) []byte {
+	if alg == minisignAlgHashed {
+		digest := blake2b.Sum512(content)
+		content = digest[:]
+	}
+	sig := ed25519.Sign(priv, content)
+	comment := "timestamp:1700000000\tfile:artifact"
+	global := ed25519.Sign(priv, append(append([]byte{}, sig...), comment...))
+
+	b := append([]byte(alg), id...)
+	b = append(b, sig...)
+	return []byte(fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(b), comment, base64.StdEncoding.EncodeToString(global)))
+}
+
+func pemKey(t *testing.T, pub crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	must.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestParseKey(t *testing.T) {
+	ci.Parallel(t)
+
+	minisign, _ := minisignKey(t, "12345678")
+	key, err := ParseKey(minisign)
+	must.NoError(t, err)
+	must.Eq(t, FormatMinisign, key.Format)
+	must.Eq(t, []byte("12345678"), key.keyID)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must.NoError(t, err)
+	key, err = ParseKey(pemKey(t, ecKey.Public()))
+	must.NoError(t, err)
+	must.Eq(t, FormatCosign, key.Format)
+
+	_, err = ParseKey("RWQ=")
+	must.ErrorContains(t, err, "invalid minisign public key")
+
+	_, err = ParseKey("-----BEGIN PUBLIC KEY-----\nAAAA\n-----END PUBLIC KEY-----\n")
+	must.ErrorContains(t, err, "invalid public key")
+
+	_, err = ParseKeys(map[string]string{"build": minisign, "bad": "bad"})
+	must.ErrorContains(t, err, `key "bad"`)
+}
+
+func TestVerifyFile_Minisign(t *testing.T) {
+	ci.Parallel(t)
+
+	path := writeTestFile(t)
+	pub1, priv1 := minisignKey(t, "key1key1")
+	pub2, priv2 := minisignKey(t, "key2key2")
+	keys, err := ParseKeys(map[string]string{"build": pub1, "release": pub2})
+	must.NoError(t, err)
+
+	for _, alg := range []string{minisignAlg, minisignAlgHashed} {
+		name, err := VerifyFile(FormatMinisign, path, minisignSign(priv2, "key2key2", alg, testContent), keys)
+		must.NoError(t, err)
+		must.Eq(t, "release", name)
+	}
+
+	// Signature of other content
+	sig := minisignSign(priv1, "key1key1", minisignAlgHashed, []byte("other"))
+	_, err = VerifyFile(FormatMinisign, path, sig, keys)
+	must.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Signature by a key with a trusted key id
+	sig = minisignSign(priv2, "key1key1", minisignAlgHashed, testContent)
+	_, err = VerifyFile(FormatMinisign, path, sig, keys)
+	must.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Tampered trusted comment
+	sig = minisignSign(priv1, "key1key1", minisignAlgHashed, testContent)
+	sig = bytes.Replace(sig, []byte("file:artifact"), []byte("file:other"), 1)
+	_, err = VerifyFile(FormatMinisign, path, sig, keys)
+	must.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = VerifyFile(FormatMinisign, path, []byte("garbage"), keys)
+	must.ErrorContains(t, err, "invalid minisign signature")
+
+	_, err = VerifyFile(FormatCosign, path, sig, keys)
+	must.ErrorIs(t, err, ErrNoKeys)
+}
+
+func TestVerifyFile_Cosign(t *testing.T) {
+	ci.Parallel(t)
+
+	path := writeTestFile(t)
+	digest := sha256.Sum256(testContent)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must.NoError(t, err)
+	ecSig, err := ecdsa.SignASN1(rand.Reader, ecKey, digest[:])
+	must.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	must.NoError(t, err)
+	rsaSig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+	must.NoError(t, err)
+
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	must.NoError(t, err)
+	edSig := ed25519.Sign(edPriv, testContent)
+
+	minisign, _ := minisignKey(t, "12345678")
+	keys, err := ParseKeys(map[string]string{
+		"ecdsa":    pemKey(t, ecKey.Public()),
+		"rsa":      pemKey(t, rsaKey.Public()),
+		"ed25519":  pemKey(t, edPub),
+		"minisign": minisign,
+	})
+	must.NoError(t, err)
+
+	cases := map[string][]byte{
+		"ecdsa":   []byte(base64.StdEncoding.EncodeToString(ecSig) + "\n"),
+		"rsa":     []byte(base64.StdEncoding.EncodeToString(rsaSig)),
+		"ed25519": edSig,
+	}
+	for exp, sig := range cases {
+		name, err := VerifyFile(FormatCosign, path, sig, keys)
+		must.NoError(t, err)
+		must.Eq(t, exp, name)
+	}
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must.NoError(t, err)
+	otherSig, err := ecdsa.SignASN1(rand.Reader, otherKey, digest[:])
+	must.NoError(t, err)
+	_, err = VerifyFile(FormatCosign, path, otherSig, keys)
+	must.ErrorIs(t, err, ErrInvalidSignature)
+}
@@ -101,6 +101,7 @@ func parseReschedulePolicy(final **api.ReschedulePolicy, list *ast.ObjectList) e
 		"delay",
 		"max_delay",
 		"delay_function",
+		"reschedule_on_timeout",
 	}
 	if err := checkHCLKeys(obj.Val, valid); err != nil {
 		return err
@@ -514,6 +515,7 @@ func parseVault(result *api.Vault, list *ast.ObjectList) error {
 		"disable_file",
 		"change_mode",
 		"change_signal",
+		"role",
 	}
 	if err := checkHCLKeys(listVal, valid); err != nil {
 		return multierror.Prefix(err, "vault ->")
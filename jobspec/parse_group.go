@@ -322,6 +322,7 @@ func parseRestartPolicy(final **api.RestartPolicy, list *ast.ObjectList) error {
 		"delay",
 		"mode",
 		"render_templates",
+		"restart_on_timeout",
 	}
 	if err := checkHCLKeys(obj.Val, valid); err != nil {
 		return err
@@ -32,6 +32,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	}
 	delete(m, "constraint")
 	delete(m, "affinity")
+	delete(m, "depends_on")
 	delete(m, "meta")
 	delete(m, "migrate")
 	delete(m, "parameterized")
@@ -66,6 +67,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"affinity",
 		"spread",
 		"datacenters",
+		"depends_on",
 		"node_pool",
 		"group",
 		"id",
@@ -125,6 +127,13 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse dependencies on other jobs
+	if o := listVal.Filter("depends_on"); len(o.Items) > 0 {
+		if err := parseJobDependencies(&result.DependsOn, o); err != nil {
+			return multierror.Prefix(err, "depends_on ->")
+		}
+	}
+
 	// If we have a parameterized definition, then parse that
 	if o := listVal.Filter("parameterized"); len(o.Items) > 0 {
 		if err := parseParameterizedJob(&result.ParameterizedJob, o); err != nil {
@@ -238,6 +247,8 @@ func parsePeriodic(result **api.PeriodicConfig, list *ast.ObjectList) error {
 		"crons",
 		"prohibit_overlap",
 		"time_zone",
+		"catchup",
+		"catchup_window",
 	}
 	if err := checkHCLKeys(o.Val, valid); err != nil {
 		return err
@@ -265,13 +276,46 @@ func parsePeriodic(result **api.PeriodicConfig, list *ast.ObjectList) error {
 
 	// Build the constraint
 	var p api.PeriodicConfig
-	if err := mapstructure.WeakDecode(m, &p); err != nil {
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &p,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
 		return err
 	}
 	*result = &p
 	return nil
 }
 
+func parseJobDependencies(result *[]*api.JobDependency, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"job",
+		}
+		if err := checkHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		var d api.JobDependency
+		if err := mapstructure.WeakDecode(m, &d); err != nil {
+			return err
+		}
+
+		*result = append(*result, &d)
+	}
+	return nil
+}
+
 func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
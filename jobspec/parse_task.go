@@ -35,10 +35,12 @@ var (
 		"artifact",
 		"constraint",
 		"affinity",
+		"depends_on",
 		"dispatch_payload",
 		"identity",
 		"lifecycle",
 		"leader",
+		"max_run_duration",
 		"restart",
 		"service",
 		"template",
@@ -105,6 +107,7 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 	delete(m, "config")
 	delete(m, "constraint")
 	delete(m, "affinity")
+	delete(m, "depends_on")
 	delete(m, "dispatch_payload")
 	delete(m, "lifecycle")
 	delete(m, "env")
@@ -264,6 +267,7 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 			"max_file_size",
 			"enabled", // COMPAT(1.6.0): remove in favor of disabled
 			"disabled",
+			"sink",
 		}
 		if err := checkHCLKeys(logsBlock.Val, valid); err != nil {
 			return nil, multierror.Prefix(err, "logs ->")
@@ -272,12 +276,21 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		if err := hcl.DecodeObject(&m, logsBlock.Val); err != nil {
 			return nil, err
 		}
+		delete(m, "sink")
 
 		var log api.LogConfig
 		if err := mapstructure.WeakDecode(m, &log); err != nil {
 			return nil, err
 		}
 
+		if ot, ok := logsBlock.Val.(*ast.ObjectType); ok {
+			if o := ot.List.Filter("sink"); len(o.Items) > 0 {
+				if err := parseLogSinks(&log.Sinks, o); err != nil {
+					return nil, multierror.Prefix(err, "logs ->")
+				}
+			}
+		}
+
 		t.LogConfig = &log
 	}
 
@@ -288,13 +301,11 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		}
 	}
 
-	// Parse identity
+	// Parse identities
 	if o := listVal.Filter("identity"); len(o.Items) > 0 {
-		v := &api.WorkloadIdentity{}
-		if err := parseIdentity(v, o); err != nil {
+		if err := parseIdentities(&t, o); err != nil {
 			return nil, multierror.Prefix(err, "identity ->")
 		}
-		t.Identity = v
 	}
 
 	// Parse templates
@@ -379,9 +390,42 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 			return nil, err
 		}
 	}
+
+	// Parse the task dependencies
+	if o := listVal.Filter("depends_on"); len(o.Items) > 0 {
+		if err := parseTaskDependencies(&t.DependsOn, o); err != nil {
+			return nil, multierror.Prefix(err, "depends_on ->")
+		}
+	}
 	return &t, nil
 }
 
+func parseTaskDependencies(result *[]*api.TaskDependency, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"task",
+			"condition",
+		}
+		if err := checkHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		var d api.TaskDependency
+		if err := mapstructure.WeakDecode(m, &d); err != nil {
+			return err
+		}
+
+		*result = append(*result, &d)
+	}
+	return nil
+}
+
 func parseArtifacts(result *[]*api.TaskArtifact, list *ast.ObjectList) error {
 	for _, o := range list.Elem().Items {
 		// Check for invalid keys
@@ -391,6 +435,8 @@ func parseArtifacts(result *[]*api.TaskArtifact, list *ast.ObjectList) error {
 			"headers",
 			"mode",
 			"destination",
+			"signature",
+			"read_only",
 		}
 		if err := checkHCLKeys(o.Val, valid); err != nil {
 			return err
@@ -402,6 +448,7 @@ func parseArtifacts(result *[]*api.TaskArtifact, list *ast.ObjectList) error {
 		}
 
 		delete(m, "options")
+		delete(m, "signature")
 
 		var ta api.TaskArtifact
 		if err := mapstructure.WeakDecode(m, &ta); err != nil {
@@ -423,12 +470,51 @@ func parseArtifacts(result *[]*api.TaskArtifact, list *ast.ObjectList) error {
 			ta.GetterOptions = options
 		}
 
+		if so := optionList.Filter("signature"); len(so.Items) > 0 {
+			if err := parseArtifactSignature(&ta, so); err != nil {
+				return multierror.Prefix(err, "signature ->")
+			}
+		}
+
 		*result = append(*result, &ta)
 	}
 
 	return nil
 }
 
+func parseArtifactSignature(result *api.TaskArtifact, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'signature' block allowed per artifact")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	// Check for invalid keys
+	valid := []string{
+		"source",
+		"format",
+		"keys",
+		"variable",
+	}
+	if err := checkHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	var sig api.ArtifactSignature
+	if err := mapstructure.WeakDecode(m, &sig); err != nil {
+		return err
+	}
+	result.Signature = &sig
+	return nil
+}
+
 func parseArtifactOption(result map[string]string, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -760,39 +846,92 @@ func parseVolumeMounts(out *[]*api.VolumeMount, list *ast.ObjectList) error {
 	return nil
 }
 
-func parseIdentity(out *api.WorkloadIdentity, list *ast.ObjectList) error {
+// parseIdentities parses the identity blocks of a task. The block without a
+// name, or named "default", is the default identity of the task and the other
+// blocks are its named identities.
+func parseIdentities(t *api.Task, list *ast.ObjectList) error {
 	list = list.Elem()
-	if len(list.Items) == 0 {
-		return nil
-	}
-	if len(list.Items) > 1 {
-		return fmt.Errorf("only one 'identity' block allowed per task")
-	}
 
-	o := list.Items[0]
-	var listVal *ast.ObjectList
-	if ot, ok := o.Val.(*ast.ObjectType); ok {
-		listVal = ot.List
-	} else {
-		return fmt.Errorf("identity: should be an object")
-	}
+	for _, o := range list.Items {
+		var listVal *ast.ObjectList
+		if ot, ok := o.Val.(*ast.ObjectType); ok {
+			listVal = ot.List
+		} else {
+			return fmt.Errorf("identity: should be an object")
+		}
 
-	valid := []string{
-		"env",
-		"file",
-	}
+		valid := []string{
+			"name",
+			"aud",
+			"env",
+			"file",
+			"ttl",
+		}
 
-	if err := checkHCLKeys(listVal, valid); err != nil {
-		return multierror.Prefix(err, "identity ->")
-	}
+		if err := checkHCLKeys(listVal, valid); err != nil {
+			return multierror.Prefix(err, "identity ->")
+		}
 
-	var m map[string]interface{}
-	if err := hcl.DecodeObject(&m, o.Val); err != nil {
-		return err
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		var wi api.WorkloadIdentity
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &wi,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return err
+		}
+
+		if wi.Name != "" && wi.Name != "default" {
+			t.Identities = append(t.Identities, &wi)
+			continue
+		}
+		if t.Identity != nil {
+			return fmt.Errorf("only one default 'identity' block allowed per task")
+		}
+		t.Identity = &wi
 	}
 
-	if err := mapstructure.WeakDecode(m, out); err != nil {
-		return err
+	return nil
+}
+
+func parseLogSinks(result *[]*api.LogSink, list *ast.ObjectList) error {
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("sink block should have exactly one label, the sink type")
+		}
+		sinkType := item.Keys[0].Token.Value().(string)
+
+		valid := []string{
+			"address",
+			"path",
+			"facility",
+			"tag",
+			"headers",
+			"buffer_size",
+		}
+		if err := checkHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("sink %q ->", sinkType))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		sink := &api.LogSink{Type: sinkType}
+		if err := mapstructure.WeakDecode(m, sink); err != nil {
+			return err
+		}
+		*result = append(*result, sink)
 	}
 
 	return nil
@@ -579,6 +579,120 @@ func TestParse(t *testing.T) {
 					Specs:           []string{"*/5 * * *", "*/7 * * *"},
 					ProhibitOverlap: boolToPtr(true),
 					TimeZone:        stringToPtr("Europe/Minsk"),
+					Catchup:         stringToPtr(api.PeriodicCatchupAll),
+					CatchupWindow:   timeToPtr(6 * time.Hour),
+				},
+			},
+			false,
+		},
+
+		{
+			"depends-on.hcl",
+			&api.Job{
+				ID:   stringToPtr("report"),
+				Name: stringToPtr("report"),
+				Type: stringToPtr("batch"),
+				DependsOn: []*api.JobDependency{
+					{JobID: "extract"},
+					{JobID: "transform"},
+				},
+			},
+			false,
+		},
+
+		{
+			"task-depends-on.hcl",
+			&api.Job{
+				ID:   stringToPtr("example"),
+				Name: stringToPtr("example"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("cache"),
+						Tasks: []*api.Task{
+							{
+								Name:   "db",
+								Driver: "docker",
+							},
+							{
+								Name:   "app",
+								Driver: "docker",
+								DependsOn: []*api.TaskDependency{
+									{Task: "db", Condition: "healthy"},
+								},
+							},
+							{
+								Name:   "web",
+								Driver: "docker",
+								DependsOn: []*api.TaskDependency{
+									{Task: "app"},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+
+		{
+			"task-identities.hcl",
+			&api.Job{
+				ID:   stringToPtr("example"),
+				Name: stringToPtr("example"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("cache"),
+						Tasks: []*api.Task{
+							{
+								Name:   "redis",
+								Driver: "docker",
+								Identity: &api.WorkloadIdentity{
+									Env:  true,
+									File: true,
+								},
+								Identities: []*api.WorkloadIdentity{
+									{
+										Name:     "consul",
+										Audience: []string{"consul.io"},
+										File:     true,
+										TTL:      time.Hour,
+									},
+									{
+										Name:     "vault",
+										Audience: []string{"vault.io", "example.com"},
+										Env:      true,
+										TTL:      30 * time.Minute,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+
+		{
+			"vault-role.hcl",
+			&api.Job{
+				ID:   stringToPtr("example"),
+				Name: stringToPtr("example"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("cache"),
+						Tasks: []*api.Task{
+							{
+								Name:   "redis",
+								Driver: "docker",
+								Vault: &api.Vault{
+									Role:        "nomad-workloads",
+									Env:         boolToPtr(true),
+									DisableFile: boolToPtr(false),
+									ChangeMode:  stringToPtr(vaultChangeModeRestart),
+								},
+							},
+						},
+					},
 				},
 			},
 			false,
@@ -654,6 +768,7 @@ func TestParse(t *testing.T) {
 										GetterSource:  stringToPtr("http://foo.com/bam"),
 										GetterOptions: nil,
 										RelativeDest:  stringToPtr("var/foo"),
+										ReadOnly:      boolToPtr(true),
 									},
 									{
 										GetterSource: stringToPtr("https://example.com/file.txt"),
@@ -662,6 +777,15 @@ func TestParse(t *testing.T) {
 											"X-Nomad-Alloc": "alloc",
 										},
 									},
+									{
+										GetterSource: stringToPtr("https://example.com/app.tar.gz"),
+										Signature: &api.ArtifactSignature{
+											Source:   stringToPtr("https://example.com/app.tar.gz.minisig"),
+											Format:   stringToPtr("minisign"),
+											Keys:     []string{"build"},
+											Variable: stringToPtr("nomad/jobs/binstore-storagelocker/keys"),
+										},
+									},
 								},
 							},
 						},
@@ -907,6 +1031,38 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"max-run-duration.hcl",
+			&api.Job{
+				ID:   stringToPtr("foo"),
+				Name: stringToPtr("foo"),
+				Type: stringToPtr("batch"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("bar"),
+						RestartPolicy: &api.RestartPolicy{
+							Attempts:         intToPtr(1),
+							RestartOnTimeout: boolToPtr(true),
+						},
+						ReschedulePolicy: &api.ReschedulePolicy{
+							Attempts:            intToPtr(2),
+							RescheduleOnTimeout: boolToPtr(true),
+						},
+						Tasks: []*api.Task{
+							{
+								Name:           "bar",
+								Driver:         "docker",
+								MaxRunDuration: time.Hour,
+								Config: map[string]interface{}{
+									"image": "hashicorp/image",
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"service-tagged-address.hcl",
 			&api.Job{
@@ -1907,6 +2063,46 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"task-log-sinks.hcl",
+			&api.Job{
+				ID:   stringToPtr("example"),
+				Name: stringToPtr("example"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("cache"),
+						Tasks: []*api.Task{
+							{
+								Name:   "redis",
+								Driver: "docker",
+								LogConfig: &api.LogConfig{
+									MaxFiles: intToPtr(5),
+									Sinks: []*api.LogSink{
+										{
+											Type:     "syslog",
+											Address:  "udp://127.0.0.1:514",
+											Facility: "local0",
+											Tag:      "redis",
+										},
+										{
+											Type:       "otlp",
+											Address:    "http://127.0.0.1:4318/v1/logs",
+											Headers:    map[string]string{"Authorization": "Bearer secret"},
+											BufferSize: intToPtr(4096),
+										},
+										{
+											Type: "file",
+											Path: "redis.json",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 	}
 
 	for _, tc := range cases {
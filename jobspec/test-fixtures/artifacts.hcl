@@ -22,6 +22,7 @@ job "binstore-storagelocker" {
       artifact {
         source      = "http://foo.com/bam"
         destination = "var/foo"
+        read_only   = true
       }
 
       artifact {
@@ -32,6 +33,17 @@ job "binstore-storagelocker" {
           X-Nomad-Alloc = "alloc"
         }
       }
+
+      artifact {
+        source = "https://example.com/app.tar.gz"
+
+        signature {
+          source   = "https://example.com/app.tar.gz.minisig"
+          format   = "minisign"
+          keys     = ["build"]
+          variable = "nomad/jobs/binstore-storagelocker/keys"
+        }
+      }
     }
   }
 }
@@ -0,0 +1,14 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "report" {
+  type = "batch"
+
+  depends_on {
+    job = "extract"
+  }
+
+  depends_on {
+    job = "transform"
+  }
+}
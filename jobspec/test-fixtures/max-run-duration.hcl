@@ -0,0 +1,27 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "foo" {
+  type = "batch"
+
+  group "bar" {
+    restart {
+      attempts           = 1
+      restart_on_timeout = true
+    }
+
+    reschedule {
+      attempts              = 2
+      reschedule_on_timeout = true
+    }
+
+    task "bar" {
+      driver           = "docker"
+      max_run_duration = "1h"
+
+      config {
+        image = "hashicorp/image"
+      }
+    }
+  }
+}
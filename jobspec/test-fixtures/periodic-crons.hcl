@@ -9,5 +9,7 @@ job "foo" {
     ]
     prohibit_overlap = true
     time_zone        = "Europe/Minsk"
+    catchup          = "all"
+    catchup_window   = "6h"
   }
 }
@@ -0,0 +1,27 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "example" {
+  group "cache" {
+    task "db" {
+      driver = "docker"
+    }
+
+    task "app" {
+      driver = "docker"
+
+      depends_on {
+        task      = "db"
+        condition = "healthy"
+      }
+    }
+
+    task "web" {
+      driver = "docker"
+
+      depends_on {
+        task = "app"
+      }
+    }
+  }
+}
@@ -0,0 +1,29 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "example" {
+  group "cache" {
+    task "redis" {
+      driver = "docker"
+
+      identity {
+        env  = true
+        file = true
+      }
+
+      identity {
+        name = "consul"
+        aud  = ["consul.io"]
+        file = true
+        ttl  = "1h"
+      }
+
+      identity {
+        name = "vault"
+        aud  = ["vault.io", "example.com"]
+        env  = true
+        ttl  = "30m"
+      }
+    }
+  }
+}
@@ -0,0 +1,33 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "example" {
+  group "cache" {
+    task "redis" {
+      driver = "docker"
+
+      logs {
+        max_files = 5
+
+        sink "syslog" {
+          address  = "udp://127.0.0.1:514"
+          facility = "local0"
+          tag      = "redis"
+        }
+
+        sink "otlp" {
+          address     = "http://127.0.0.1:4318/v1/logs"
+          buffer_size = 4096
+
+          headers = {
+            Authorization = "Bearer secret"
+          }
+        }
+
+        sink "file" {
+          path = "redis.json"
+        }
+      }
+    }
+  }
+}
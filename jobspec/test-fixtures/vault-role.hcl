@@ -0,0 +1,14 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "example" {
+  group "cache" {
+    task "redis" {
+      driver = "docker"
+
+      vault {
+        role = "nomad-workloads"
+      }
+    }
+  }
+}
@@ -321,13 +321,15 @@ func decodeTask(body hcl.Body, ctx *hcl.EvalContext, val interface{}) hcl.Diagno
 	b, remain, moreDiags := body.PartialContent(&hcl.BodySchema{
 		Blocks: []hcl.BlockHeaderSchema{
 			{Type: "scaling", LabelNames: []string{"name"}},
+			{Type: "identity"},
 		},
 	})
 
 	diags = append(diags, moreDiags...)
-	diags = append(diags, decodeTaskScalingPolicies(b.Blocks, ctx, t)...)
+	diags = append(diags, decodeTaskScalingPolicies(b.Blocks.OfType("scaling"), ctx, t)...)
 
 	decoder := newHCLDecoder()
+	diags = append(diags, decodeTaskIdentities(b.Blocks.OfType("identity"), ctx, decoder, t)...)
 	diags = append(diags, decoder.DecodeBody(remain, ctx, val)...)
 
 	if envAttr != nil {
@@ -405,6 +407,37 @@ func decodeAsAttribute(body hcl.Body, ctx *hcl.EvalContext, name string) (map[st
 	return result, remain, diags
 }
 
+// decodeTaskIdentities decodes the identity blocks of a task. The block
+// without a name, or named "default", is the default identity of the task and
+// the other blocks are its named identities.
+func decodeTaskIdentities(blocks hcl.Blocks, ctx *hcl.EvalContext, decoder *gohcl.Decoder, task *api.Task) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	var defaultBlock *hcl.Block
+	for _, b := range blocks {
+		var wi api.WorkloadIdentity
+		diags = append(diags, decoder.DecodeBody(b.Body, ctx, &wi)...)
+
+		if wi.Name != "" && wi.Name != "default" {
+			task.Identities = append(task.Identities, &wi)
+			continue
+		}
+
+		if defaultBlock != nil {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate identity block",
+				Detail: fmt.Sprintf("Only one default identity block is allowed per task. Another definition is defined at %s.",
+					defaultBlock.DefRange.String()),
+				Subject: &b.DefRange,
+			})
+			continue
+		}
+		defaultBlock = b
+		task.Identity = &wi
+	}
+	return diags
+}
+
 func decodeTaskScalingPolicies(blocks hcl.Blocks, ctx *hcl.EvalContext, task *api.Task) hcl.Diagnostics {
 	if len(blocks) == 0 {
 		return nil
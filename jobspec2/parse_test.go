@@ -1104,3 +1104,83 @@ func TestRestartRenderTemplates(t *testing.T) {
 	require.Nil(t, tg.Tasks[0].RestartPolicy)
 	require.False(t, *tg.Tasks[1].RestartPolicy.RenderTemplates)
 }
+
+func TestParse_TaskIdentities_DuplicateDefault(t *testing.T) {
+	ci.Parallel(t)
+
+	hcl := `
+job "example" {
+  group "cache" {
+    task "redis" {
+      driver = "docker"
+
+      identity {
+        env = true
+      }
+
+      identity {
+        name = "default"
+        file = true
+      }
+    }
+  }
+}
+`
+
+	_, err := ParseWithConfig(&ParseConfig{
+		Path:    "input.hcl",
+		Body:    []byte(hcl),
+		ArgVars: []string{},
+		AllowFS: true,
+	})
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Duplicate identity block")
+}
+
+func TestParse_LogSinks(t *testing.T) {
+	ci.Parallel(t)
+
+	hcl := `
+job "example" {
+  group "cache" {
+    task "redis" {
+      driver = "docker"
+
+      logs {
+        sink "journald" {}
+
+        sink "otlp" {
+          address     = "https://collector.example.com/v1/logs"
+          buffer_size = 64
+
+          headers = {
+            Authorization = "Bearer secret"
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "input.hcl",
+		Body:    []byte(hcl),
+		ArgVars: []string{},
+		AllowFS: true,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []*api.LogSink{
+		{
+			Type: "journald",
+		},
+		{
+			Type:       "otlp",
+			Address:    "https://collector.example.com/v1/logs",
+			Headers:    map[string]string{"Authorization": "Bearer secret"},
+			BufferSize: pointer.Of(64),
+		},
+	}, job.TaskGroups[0].Tasks[0].LogConfig.Sinks)
+}
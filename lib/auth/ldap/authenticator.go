@@ -0,0 +1,343 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/exp/slices"
+
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+const (
+	// defaultUserAttr is the attribute used to match usernames when the auth
+	// method does not configure one.
+	defaultUserAttr = "uid"
+
+	// defaultUserFilter is the template used to search for a user when the
+	// auth method does not configure one.
+	defaultUserFilter = "({{.UserAttr}}={{.Username}})"
+
+	// defaultGroupFilter is the template used to search for the groups of a
+	// user when the auth method does not configure one.
+	defaultGroupFilter = "(|(member={{.UserDN}})(uniqueMember={{.UserDN}}))"
+
+	// defaultGroupAttr is the attribute of a group entry holding its name when
+	// the auth method does not configure one.
+	defaultGroupAttr = "cn"
+
+	// dialTimeout bounds the time spent connecting to each LDAP server.
+	dialTimeout = 10 * time.Second
+)
+
+const (
+	// ClaimUsername, ClaimDN and ClaimGroups are the claims always returned
+	// by Authenticate and which can be referenced by claim mappings.
+	ClaimUsername = "username"
+	ClaimDN       = "dn"
+	ClaimGroups   = "groups"
+)
+
+// ErrInvalidCredentials is returned when the user could not be found or the
+// password does not match.
+var ErrInvalidCredentials = errors.New("invalid LDAP credentials")
+
+// Authenticate binds to the LDAP directory described by methodConf as the
+// passed user, and returns the claims describing that user. The returned
+// claims always include the username, the user DN and the names of the groups
+// the user is a member of; any other user attribute referenced by the claim
+// mappings is also included.
+func Authenticate(ctx context.Context, methodConf *structs.ACLAuthMethodConfig,
+	username, password string) (map[string]any, error) {
+
+	if methodConf == nil {
+		return nil, errors.New("missing LDAP auth method config")
+	}
+
+	// An empty password results in an unauthenticated bind which most
+	// directories accept without checking anything, so it must be refused
+	// before reaching the server.
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := dial(ctx, methodConf)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := serviceBind(conn, methodConf); err != nil {
+		return nil, err
+	}
+
+	entry, err := searchUser(conn, methodConf, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to bind as user: %w", err)
+	}
+
+	// Searching for groups is done with the service account, since users
+	// are not necessarily allowed to read group entries.
+	if err := serviceBind(conn, methodConf); err != nil {
+		return nil, err
+	}
+
+	groups, err := searchGroups(conn, methodConf, username, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := map[string]any{
+		ClaimUsername: username,
+		ClaimDN:       entry.DN,
+		ClaimGroups:   groups,
+	}
+
+	// Copy over the attributes referenced by the claim mappings, keeping
+	// list mappings as lists.
+	for attr := range methodConf.ClaimMappings {
+		if _, ok := claims[attr]; !ok {
+			if v := entry.GetAttributeValue(attr); v != "" {
+				claims[attr] = v
+			}
+		}
+	}
+	for attr := range methodConf.ListClaimMappings {
+		if _, ok := claims[attr]; !ok {
+			if vs := entry.GetAttributeValues(attr); len(vs) != 0 {
+				claims[attr] = toAnySlice(vs)
+			}
+		}
+	}
+
+	return claims, nil
+}
+
+// dial connects to the first reachable LDAP server of the auth method.
+func dial(ctx context.Context, methodConf *structs.ACLAuthMethodConfig) (*ldap.Conn, error) {
+	if len(methodConf.LDAPURLs) == 0 {
+		return nil, errors.New("missing LDAP URLs")
+	}
+
+	var mErr multierror.Error
+	for _, rawURL := range methodConf.LDAPURLs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid LDAP URL %q: %w", rawURL, err))
+			continue
+		}
+
+		tlsConfig, err := tlsConfig(methodConf, u.Hostname())
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := ldap.DialURL(rawURL,
+			ldap.DialWithDialer(&net.Dialer{Timeout: dialTimeout}),
+			ldap.DialWithTLSConfig(tlsConfig))
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("failed to connect to %q: %w", rawURL, err))
+			continue
+		}
+
+		if methodConf.LDAPStartTLS && u.Scheme == "ldap" {
+			if err := conn.StartTLS(tlsConfig); err != nil {
+				conn.Close()
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("failed to start TLS with %q: %w", rawURL, err))
+				continue
+			}
+		}
+
+		return conn, nil
+	}
+
+	return nil, mErr.ErrorOrNil()
+}
+
+// tlsConfig builds the TLS configuration used to talk to the LDAP server.
+func tlsConfig(methodConf *structs.ACLAuthMethodConfig, host string) (*tls.Config, error) {
+	conf := &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: methodConf.LDAPInsecureTLS,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if methodConf.LDAPCACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(methodConf.LDAPCACert)) {
+			return nil, errors.New("could not parse LDAP CA certificate")
+		}
+		conf.RootCAs = pool
+	}
+
+	return conf, nil
+}
+
+// serviceBind binds using the service account of the auth method, or
+// anonymously if none is configured.
+func serviceBind(conn *ldap.Conn, methodConf *structs.ACLAuthMethodConfig) error {
+	var err error
+	if methodConf.LDAPBindDN != "" {
+		err = conn.Bind(methodConf.LDAPBindDN, methodConf.LDAPBindPassword)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to bind to LDAP server: %w", err)
+	}
+	return nil
+}
+
+// searchUser finds the entry of the user under the user DN of the auth
+// method. Exactly one entry must match.
+func searchUser(conn *ldap.Conn, methodConf *structs.ACLAuthMethodConfig, username string) (*ldap.Entry, error) {
+	userAttr := withDefault(methodConf.LDAPUserAttr, defaultUserAttr)
+
+	filter, err := renderFilter(withDefault(methodConf.LDAPUserFilter, defaultUserFilter), map[string]string{
+		"UserAttr": userAttr,
+		"Username": ldap.EscapeFilter(username),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP user filter: %w", err)
+	}
+
+	attrs := []string{userAttr, "memberOf"}
+	for attr := range methodConf.ClaimMappings {
+		attrs = append(attrs, attr)
+	}
+	for attr := range methodConf.ListClaimMappings {
+		attrs = append(attrs, attr)
+	}
+
+	res, err := conn.Search(ldap.NewSearchRequest(
+		methodConf.LDAPUserDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, 0, false, filter, attrs, nil))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to search for LDAP user: %w", err)
+	}
+
+	switch len(res.Entries) {
+	case 0:
+		return nil, ErrInvalidCredentials
+	case 1:
+		return res.Entries[0], nil
+	default:
+		return nil, fmt.Errorf("LDAP user search returned %d entries", len(res.Entries))
+	}
+}
+
+// searchGroups returns the sorted names of the groups the user is a member
+// of. Groups are searched under the group DN of the auth method if set,
+// otherwise they are read from the memberOf attribute of the user entry.
+func searchGroups(conn *ldap.Conn, methodConf *structs.ACLAuthMethodConfig,
+	username string, user *ldap.Entry) ([]any, error) {
+
+	groupAttr := withDefault(methodConf.LDAPGroupAttr, defaultGroupAttr)
+
+	var groups []string
+	if methodConf.LDAPGroupDN == "" {
+		for _, dn := range user.GetAttributeValues("memberOf") {
+			if name := rdnValue(dn, groupAttr); name != "" {
+				groups = append(groups, name)
+			}
+		}
+	} else {
+		filter, err := renderFilter(withDefault(methodConf.LDAPGroupFilter, defaultGroupFilter), map[string]string{
+			"UserDN":   ldap.EscapeFilter(user.DN),
+			"Username": ldap.EscapeFilter(username),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid LDAP group filter: %w", err)
+		}
+
+		res, err := conn.Search(ldap.NewSearchRequest(
+			methodConf.LDAPGroupDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+			0, 0, false, filter, []string{groupAttr}, nil))
+		if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, fmt.Errorf("failed to search for LDAP groups: %w", err)
+		}
+		if res != nil {
+			for _, entry := range res.Entries {
+				name := entry.GetAttributeValue(groupAttr)
+				if name == "" {
+					name = rdnValue(entry.DN, groupAttr)
+				}
+				if name != "" {
+					groups = append(groups, name)
+				}
+			}
+		}
+	}
+
+	slices.Sort(groups)
+	return toAnySlice(slices.Compact(groups)), nil
+}
+
+// rdnValue returns the value of attr in the first RDN of dn, if present.
+func rdnValue(dn, attr string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return ""
+	}
+	for _, a := range parsed.RDNs[0].Attributes {
+		if strings.EqualFold(a.Type, attr) {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// renderFilter executes the filter template with the passed data.
+func renderFilter(filter string, data map[string]string) (string, error) {
+	tmpl, err := template.New("filter").Option("missingkey=error").Parse(filter)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func withDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+func toAnySlice(in []string) []any {
+	out := make([]any, len(in))
+	for i, v := range in {
+		out[i] = v
+	}
+	return out
+}
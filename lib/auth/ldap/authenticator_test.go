@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/jimlambrt/gldap"
+	"github.com/jimlambrt/gldap/testdirectory"
+	"github.com/shoenig/test/must"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+func testDirectory(t *testing.T, users, groups []*gldap.Entry) *testdirectory.Directory {
+	t.Helper()
+	return testdirectory.Start(t,
+		testdirectory.WithLogger(t, hclog.NewNullLogger()),
+		testdirectory.WithDefaults(t, &testdirectory.Defaults{
+			Users:    users,
+			Groups:   groups,
+			UserAttr: "uid",
+			UserDN:   "ou=people,dc=example,dc=org",
+			GroupDN:  "ou=groups,dc=example,dc=org",
+		}),
+	)
+}
+
+func TestAuthenticate(t *testing.T) {
+	ci.Parallel(t)
+
+	defaults := testdirectory.WithDefaults(t, &testdirectory.Defaults{UserAttr: "uid"})
+
+	users := testdirectory.NewUsers(t, []string{"alice", "bob", "svc"}, defaults)
+	users = append(users, testdirectory.NewUsers(t, []string{"carol"}, defaults,
+		testdirectory.WithMembersOf(t, "cn=ops,ou=groups,dc=example,dc=org"))...)
+	groups := []*gldap.Entry{
+		testdirectory.NewGroup(t, "admins", []string{"alice"}, defaults),
+		testdirectory.NewGroup(t, "engineering", []string{"alice", "bob"}, defaults),
+	}
+
+	d := testDirectory(t, users, groups)
+
+	conf := &structs.ACLAuthMethodConfig{
+		LDAPURLs:         []string{fmt.Sprintf("ldaps://%s:%d", d.Host(), d.Port())},
+		LDAPCACert:       d.Cert(),
+		LDAPBindDN:       "uid=svc,ou=people,dc=example,dc=org",
+		LDAPBindPassword: "password",
+		LDAPUserDN:       "ou=people,dc=example,dc=org",
+		LDAPGroupDN:      "ou=groups,dc=example,dc=org",
+		ClaimMappings:    map[string]string{"email": "email"},
+	}
+
+	t.Run("group search", func(t *testing.T) {
+		claims, err := Authenticate(context.Background(), conf, "alice", "password")
+		must.NoError(t, err)
+		must.Eq(t, map[string]any{
+			ClaimUsername: "alice",
+			ClaimDN:       "uid=alice,ou=people,dc=example,dc=org",
+			ClaimGroups:   []any{"admins", "engineering"},
+			"email":       "alice@example.com",
+		}, claims)
+
+		claims, err = Authenticate(context.Background(), conf, "bob", "password")
+		must.NoError(t, err)
+		must.Eq[any](t, []any{"engineering"}, claims[ClaimGroups])
+	})
+
+	t.Run("memberOf", func(t *testing.T) {
+		memberOfConf := conf.Copy()
+		memberOfConf.LDAPGroupDN = ""
+
+		claims, err := Authenticate(context.Background(), memberOfConf, "carol", "password")
+		must.NoError(t, err)
+		must.Eq[any](t, []any{"ops"}, claims[ClaimGroups])
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		_, err := Authenticate(context.Background(), conf, "alice", "wrong")
+		must.ErrorIs(t, err, ErrInvalidCredentials)
+
+		_, err = Authenticate(context.Background(), conf, "alice", "")
+		must.ErrorIs(t, err, ErrInvalidCredentials)
+
+		_, err = Authenticate(context.Background(), conf, "mallory", "password")
+		must.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("bad service account", func(t *testing.T) {
+		badConf := conf.Copy()
+		badConf.LDAPBindPassword = "wrong"
+
+		_, err := Authenticate(context.Background(), badConf, "alice", "password")
+		must.ErrorContains(t, err, "failed to bind to LDAP server")
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		noCAConf := conf.Copy()
+		noCAConf.LDAPCACert = ""
+
+		_, err := Authenticate(context.Background(), noCAConf, "alice", "password")
+		must.ErrorContains(t, err, "failed to connect")
+	})
+}
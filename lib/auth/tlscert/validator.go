@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tlscert
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ryanuber/go-glob"
+
+	"github.com/open-wander/wander/helper"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+const (
+	// The claims returned by Validate, which can be referenced by claim
+	// mappings and binding rule selectors.
+	ClaimCommonName         = "common_name"
+	ClaimSerialNumber       = "serial_number"
+	ClaimIssuerCommonName   = "issuer_common_name"
+	ClaimOrganization       = "organization"
+	ClaimOrganizationalUnit = "organizational_unit"
+	ClaimDNSSANs            = "dns_sans"
+	ClaimEmailSANs          = "email_sans"
+	ClaimURISANs            = "uri_sans"
+	ClaimIPSANs             = "ip_sans"
+	ClaimExtensions         = "extensions"
+)
+
+// Validate verifies the DER encoded client certificate chain, leaf first,
+// against the CA certs and restrictions of the auth method, and returns the
+// claims describing the leaf certificate.
+func Validate(chain [][]byte, methodConf *structs.ACLAuthMethodConfig, now time.Time) (map[string]any, error) {
+	if methodConf == nil {
+		return nil, errors.New("missing TLS certificate auth method config")
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("no client certificate presented")
+	}
+
+	certs := make([]*x509.Certificate, 0, len(chain))
+	for _, der := range chain {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse client certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	roots := x509.NewCertPool()
+	for _, pem := range methodConf.TLSCertCACerts {
+		if !roots.AppendCertsFromPEM([]byte(pem)) {
+			return nil, errors.New("could not parse CA certificate")
+		}
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	leaf := certs[0]
+	if _, err := leaf.Verify(opts); err != nil {
+		return nil, fmt.Errorf("failed to verify client certificate: %w", err)
+	}
+
+	claims := certClaims(leaf)
+
+	if err := matchAny(methodConf.TLSCertAllowedCommonNames,
+		[]string{leaf.Subject.CommonName}); err != nil {
+		return nil, fmt.Errorf("common name %w", err)
+	}
+	if err := matchAny(methodConf.TLSCertAllowedDNSSANs, leaf.DNSNames); err != nil {
+		return nil, fmt.Errorf("DNS SANs %w", err)
+	}
+	if err := matchAny(methodConf.TLSCertAllowedEmailSANs, leaf.EmailAddresses); err != nil {
+		return nil, fmt.Errorf("email SANs %w", err)
+	}
+	if err := matchAny(methodConf.TLSCertAllowedURISANs, uriSANs(leaf)); err != nil {
+		return nil, fmt.Errorf("URI SANs %w", err)
+	}
+
+	extensions := claims[ClaimExtensions].(map[string]any)
+	for _, required := range methodConf.TLSCertRequiredExtensions {
+		oid, pattern, _ := strings.Cut(required, ":")
+		value, ok := extensions[oid].(string)
+		if !ok || !glob.Glob(pattern, value) {
+			return nil, fmt.Errorf("required extension %q not satisfied", required)
+		}
+	}
+
+	return claims, nil
+}
+
+// matchAny returns an error if patterns is not empty and none of the values
+// match one of the patterns.
+func matchAny(patterns, values []string) error {
+	if len(patterns) == 0 {
+		return nil
+	}
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if value != "" && glob.Glob(pattern, value) {
+				return nil
+			}
+		}
+	}
+	return errors.New("not allowed by the auth method")
+}
+
+// certClaims returns the claims describing the certificate.
+func certClaims(cert *x509.Certificate) map[string]any {
+	extensions := make(map[string]any, len(cert.Extensions))
+	for _, ext := range cert.Extensions {
+		// Only extensions holding an ASN.1 string can be expressed as claims.
+		var value string
+		if rest, err := asn1.Unmarshal(ext.Value, &value); err == nil && len(rest) == 0 {
+			extensions[ext.Id.String()] = value
+		}
+	}
+
+	return map[string]any{
+		ClaimCommonName:         cert.Subject.CommonName,
+		ClaimSerialNumber:       hex.EncodeToString(cert.SerialNumber.Bytes()),
+		ClaimIssuerCommonName:   cert.Issuer.CommonName,
+		ClaimOrganization:       toAnySlice(cert.Subject.Organization),
+		ClaimOrganizationalUnit: toAnySlice(cert.Subject.OrganizationalUnit),
+		ClaimDNSSANs:            toAnySlice(cert.DNSNames),
+		ClaimEmailSANs:          toAnySlice(cert.EmailAddresses),
+		ClaimURISANs:            toAnySlice(uriSANs(cert)),
+		ClaimIPSANs:             toAnySlice(helper.ConvertSlice(cert.IPAddresses, net.IP.String)),
+		ClaimExtensions:         extensions,
+	}
+}
+
+func uriSANs(cert *x509.Certificate) []string {
+	return helper.ConvertSlice(cert.URIs, (*url.URL).String)
+}
+
+func toAnySlice(in []string) []any {
+	out := make([]any, len(in))
+	for i, v := range in {
+		out[i] = v
+	}
+	return out
+}
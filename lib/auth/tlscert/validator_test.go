@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tlscert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+// testCA generates a self-signed CA and returns it along with its key.
+func testCA(t *testing.T, name string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	must.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	must.NoError(t, err)
+	return cert, key
+}
+
+// testLeaf generates a client certificate from the template signed by the CA
+// and returns its DER encoding.
+func testLeaf(t *testing.T, template *x509.Certificate, ca *x509.Certificate, caKey *ecdsa.PrivateKey) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(42)
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now().Add(-time.Minute)
+		template.NotAfter = time.Now().Add(time.Hour)
+	}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, key.Public(), caKey)
+	must.NoError(t, err)
+	return der
+}
+
+func pemEncode(cert *x509.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
+func TestValidate(t *testing.T) {
+	ci.Parallel(t)
+
+	ca, caKey := testCA(t, "internal-ca")
+	otherCA, otherCAKey := testCA(t, "other-ca")
+
+	roleValue, err := asn1.Marshal("web")
+	must.NoError(t, err)
+	spiffe, err := url.Parse("spiffe://example.org/web")
+	must.NoError(t, err)
+
+	leaf := testLeaf(t, &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:         "web-01.example.org",
+			Organization:       []string{"Example"},
+			OrganizationalUnit: []string{"platform"},
+		},
+		DNSNames:       []string{"web-01.example.org"},
+		EmailAddresses: []string{"web@example.org"},
+		URIs:           []*url.URL{spiffe},
+		ExtraExtensions: []pkix.Extension{
+			{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1}, Value: roleValue},
+		},
+	}, ca, caKey)
+
+	conf := &structs.ACLAuthMethodConfig{
+		TLSCertCACerts: []string{pemEncode(ca)},
+	}
+
+	t.Run("claims", func(t *testing.T) {
+		claims, err := Validate([][]byte{leaf}, conf, time.Now())
+		must.NoError(t, err)
+		must.Eq(t, map[string]any{
+			ClaimCommonName:         "web-01.example.org",
+			ClaimSerialNumber:       "2a",
+			ClaimIssuerCommonName:   "internal-ca",
+			ClaimOrganization:       []any{"Example"},
+			ClaimOrganizationalUnit: []any{"platform"},
+			ClaimDNSSANs:            []any{"web-01.example.org"},
+			ClaimEmailSANs:          []any{"web@example.org"},
+			ClaimURISANs:            []any{"spiffe://example.org/web"},
+			ClaimIPSANs:             []any{},
+			ClaimExtensions:         map[string]any{"1.3.6.1.4.1.99999.1": "web"},
+		}, claims)
+	})
+
+	t.Run("restrictions", func(t *testing.T) {
+		testCases := []struct {
+			name   string
+			modify func(*structs.ACLAuthMethodConfig)
+			errMsg string
+		}{
+			{
+				name: "matching",
+				modify: func(c *structs.ACLAuthMethodConfig) {
+					c.TLSCertAllowedCommonNames = []string{"web-*.example.org"}
+					c.TLSCertAllowedDNSSANs = []string{"*.example.org"}
+					c.TLSCertAllowedEmailSANs = []string{"*@example.org"}
+					c.TLSCertAllowedURISANs = []string{"spiffe://example.org/*"}
+					c.TLSCertRequiredExtensions = []string{"1.3.6.1.4.1.99999.1:w*"}
+				},
+			},
+			{
+				name: "common name",
+				modify: func(c *structs.ACLAuthMethodConfig) {
+					c.TLSCertAllowedCommonNames = []string{"db-*"}
+				},
+				errMsg: "common name not allowed",
+			},
+			{
+				name: "dns sans",
+				modify: func(c *structs.ACLAuthMethodConfig) {
+					c.TLSCertAllowedDNSSANs = []string{"*.example.com"}
+				},
+				errMsg: "DNS SANs not allowed",
+			},
+			{
+				name: "email sans",
+				modify: func(c *structs.ACLAuthMethodConfig) {
+					c.TLSCertAllowedEmailSANs = []string{"ops@example.org"}
+				},
+				errMsg: "email SANs not allowed",
+			},
+			{
+				name: "uri sans",
+				modify: func(c *structs.ACLAuthMethodConfig) {
+					c.TLSCertAllowedURISANs = []string{"spiffe://example.org/db"}
+				},
+				errMsg: "URI SANs not allowed",
+			},
+			{
+				name: "extension value",
+				modify: func(c *structs.ACLAuthMethodConfig) {
+					c.TLSCertRequiredExtensions = []string{"1.3.6.1.4.1.99999.1:db"}
+				},
+				errMsg: "required extension",
+			},
+			{
+				name: "missing extension",
+				modify: func(c *structs.ACLAuthMethodConfig) {
+					c.TLSCertRequiredExtensions = []string{"1.3.6.1.4.1.99999.2:*"}
+				},
+				errMsg: "required extension",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				c := conf.Copy()
+				tc.modify(c)
+				_, err := Validate([][]byte{leaf}, c, time.Now())
+				if tc.errMsg == "" {
+					must.NoError(t, err)
+				} else {
+					must.ErrorContains(t, err, tc.errMsg)
+				}
+			})
+		}
+	})
+
+	t.Run("untrusted CA", func(t *testing.T) {
+		otherLeaf := testLeaf(t, &x509.Certificate{
+			Subject: pkix.Name{CommonName: "web-01.example.org"},
+		}, otherCA, otherCAKey)
+
+		_, err := Validate([][]byte{otherLeaf}, conf, time.Now())
+		must.ErrorContains(t, err, "failed to verify client certificate")
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := Validate([][]byte{leaf}, conf, time.Now().Add(2*time.Hour))
+		must.ErrorContains(t, err, "failed to verify client certificate")
+	})
+
+	t.Run("no certificate", func(t *testing.T) {
+		_, err := Validate(nil, conf, time.Now())
+		must.ErrorContains(t, err, "no client certificate presented")
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/open-wander/wander/helper"
 	"github.com/open-wander/wander/nomad/state"
 	"github.com/open-wander/wander/nomad/structs"
+	"golang.org/x/exp/slices"
 )
 
 // Authenticate extracts an AuthenticatedIdentity from the request context or
@@ -40,6 +41,9 @@ func (s *Server) Authenticate(ctx *RPCContext, args structs.RequestWithIdentity)
 	case err == nil:
 		// If ACLs are disabled or we have a non-anonymous token, return that.
 		if aclToken == nil || aclToken != structs.AnonymousACLToken {
+			if aclToken != nil {
+				s.recordACLTokenUsage(ctx, args, aclToken)
+			}
 			args.SetIdentity(&structs.AuthenticatedIdentity{ACLToken: aclToken})
 			return nil
 		}
@@ -251,6 +255,11 @@ func (s *Server) VerifyClaim(token string) (*structs.IdentityClaims, error) {
 		return nil, fmt.Errorf("allocation is terminal")
 	}
 
+	// identities signed for other audiences are meant for 3rd party services
+	if len(claims.Audience) > 0 && !slices.Contains(claims.Audience, structs.WorkloadIdentityDefaultAud) {
+		return nil, fmt.Errorf("invalid audience %v", claims.Audience)
+	}
+
 	return claims, nil
 }
 
@@ -24,7 +24,9 @@ import (
 	"github.com/open-wander/wander/helper/uuid"
 	"github.com/open-wander/wander/lib/auth"
 	"github.com/open-wander/wander/lib/auth/jwt"
+	"github.com/open-wander/wander/lib/auth/ldap"
 	"github.com/open-wander/wander/lib/auth/oidc"
+	"github.com/open-wander/wander/lib/auth/tlscert"
 	"github.com/open-wander/wander/nomad/state"
 	"github.com/open-wander/wander/nomad/state/paginator"
 	"github.com/open-wander/wander/nomad/structs"
@@ -902,6 +904,13 @@ func (a *ACL) ListTokens(args *structs.ACLTokenListRequest, reply *structs.ACLTo
 				return err
 			}
 
+			if args.UnusedSince > 0 {
+				cutoff := time.Now().UTC().Add(-args.UnusedSince)
+				iter = memdb.NewFilterIterator(iter, func(raw interface{}) bool {
+					return !raw.(*structs.ACLToken).IsUnusedSince(cutoff)
+				})
+			}
+
 			tokenizer := paginator.NewStructsTokenizer(iter, opts)
 
 			var tokens []*structs.ACLTokenListStub
@@ -1055,6 +1064,202 @@ func (a *ACL) GetTokens(args *structs.ACLTokenSetRequest, reply *structs.ACLToke
 	return a.srv.blockingRPC(&opts)
 }
 
+// CheckToken is used to explain the effective permissions of a token, or of a
+// set of policies, on a namespace and optionally a node pool, host volume and
+// variable path. Checking a token other than the one making the request, or a
+// set of policies, requires a management token.
+func (a *ACL) CheckToken(args *structs.ACLTokenCheckRequest, reply *structs.ACLTokenCheckResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	authErr := a.srv.Authenticate(a.ctx, args)
+	if done, err := a.srv.forward(structs.ACLCheckTokenRPCMethod, args, args, reply); done {
+		return err
+	}
+	a.srv.MeasureRPCRate("acl", structs.RateMetricRead, args)
+	if authErr != nil {
+		return authErr
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "check_token"}, time.Now())
+
+	aclObj, err := a.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+
+	// Ensure ACLs are enabled and this call is made with one
+	if aclObj == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.Policies) > 0 && args.AccessorID != "" {
+		return structs.NewErrRPCCoded(http.StatusBadRequest,
+			"cannot check both a token and a set of policies")
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			var token *structs.ACLToken
+			switch {
+			case len(args.Policies) > 0:
+				if !aclObj.IsManagement() {
+					return structs.ErrPermissionDenied
+				}
+			case args.AccessorID != "":
+				token, err = state.ACLTokenByAccessorID(ws, args.AccessorID)
+				if err != nil {
+					return err
+				}
+				if token == nil {
+					if !aclObj.IsManagement() {
+						return structs.ErrPermissionDenied
+					}
+					return structs.NewErrRPCCodedf(http.StatusNotFound,
+						"ACL token %s not found", args.AccessorID)
+				}
+				if !aclObj.IsManagement() && token.SecretID != args.AuthToken {
+					return structs.ErrPermissionDenied
+				}
+			default:
+				token = args.GetIdentity().GetACLToken()
+				if token == nil {
+					return structs.ErrPermissionDenied
+				}
+			}
+
+			reply.Policies, err = aclCheckPolicies(ws, state, token, args.Policies)
+			if err != nil {
+				return err
+			}
+			if token != nil {
+				reply.AccessorID = token.AccessorID
+				reply.Management = token.Type == structs.ACLManagementToken
+			}
+
+			reply.Explanations, err = aclCheckExplanations(ws, state, args, reply.Management, reply.Policies)
+			if err != nil {
+				return err
+			}
+
+			// Use the last index that affected the tables the explanation
+			// is built from.
+			for _, table := range []string{"acl_token", "acl_policy", "acl_roles"} {
+				index, err := state.Index(table)
+				if err != nil {
+					return err
+				}
+				reply.Index = max(reply.Index, index)
+			}
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// aclCheckPolicies returns the policies linked to the token, either directly
+// or through its roles, or the named policies if token is nil.
+func aclCheckPolicies(ws memdb.WatchSet, state *state.StateStore,
+	token *structs.ACLToken, names []string) ([]*structs.ACLTokenCheckPolicy, error) {
+
+	var policies []*structs.ACLTokenCheckPolicy
+	if token == nil {
+		for _, name := range names {
+			policies = append(policies, &structs.ACLTokenCheckPolicy{Name: name})
+		}
+	} else if token.Type != structs.ACLManagementToken {
+		for _, name := range token.Policies {
+			policies = append(policies, &structs.ACLTokenCheckPolicy{Name: name})
+		}
+		for _, roleLink := range token.Roles {
+			role, err := state.GetACLRoleByID(ws, roleLink.ID)
+			if err != nil {
+				return nil, err
+			}
+			if role == nil {
+				continue
+			}
+			for _, policyLink := range role.Policies {
+				policies = append(policies, &structs.ACLTokenCheckPolicy{
+					Name: policyLink.Name,
+					Role: role.Name,
+				})
+			}
+		}
+	}
+
+	for _, p := range policies {
+		policy, err := state.ACLPolicyByName(ws, p.Name)
+		if err != nil {
+			return nil, err
+		}
+		p.Missing = policy == nil
+	}
+	return policies, nil
+}
+
+// aclCheckExplanations explains the capabilities the policies grant on each
+// resource of the check request.
+func aclCheckExplanations(ws memdb.WatchSet, state *state.StateStore, args *structs.ACLTokenCheckRequest,
+	management bool, checkPolicies []*structs.ACLTokenCheckPolicy) ([]*policy.Explanation, error) {
+
+	// A policy linked several times only needs to be parsed once, and is
+	// only reported once by the explanations.
+	seen := set.New[string](len(checkPolicies))
+	var policies []*policy.NamedPolicy
+	for _, p := range checkPolicies {
+		if p.Missing || !seen.Insert(p.Name) {
+			continue
+		}
+		aclPolicy, err := state.ACLPolicyByName(ws, p.Name)
+		if err != nil {
+			return nil, err
+		}
+		if aclPolicy == nil {
+			continue
+		}
+		parsed, err := policy.Parse(aclPolicy.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %q: %v", aclPolicy.Name, err)
+		}
+		policies = append(policies, &policy.NamedPolicy{Name: aclPolicy.Name, Policy: parsed})
+	}
+
+	ns := args.RequestNamespace()
+	explanations := make([]*policy.Explanation, 0, 4)
+
+	exp, err := policy.ExplainNamespace(management, policies, ns)
+	if err != nil {
+		return nil, err
+	}
+	explanations = append(explanations, exp)
+
+	if args.NodePool != "" {
+		exp, err := policy.ExplainNodePool(management, policies, args.NodePool)
+		if err != nil {
+			return nil, err
+		}
+		explanations = append(explanations, exp)
+	}
+	if args.HostVolume != "" {
+		exp, err := policy.ExplainHostVolume(management, policies, args.HostVolume)
+		if err != nil {
+			return nil, err
+		}
+		explanations = append(explanations, exp)
+	}
+	if args.VariablePath != "" {
+		exp, err := policy.ExplainVariable(management, policies, ns, args.VariablePath)
+		if err != nil {
+			return nil, err
+		}
+		explanations = append(explanations, exp)
+	}
+
+	return explanations, nil
+}
+
 // ResolveToken is used to lookup a specific token by a secret ID.
 //
 // Deprecated: Prior to Nomad 1.5 this RPC was used by clients for
@@ -2804,6 +3009,36 @@ func (a *ACL) OIDCCompleteAuth(
 	return nil
 }
 
+// validateLoginClientCertificates ensures the client certificates of a login
+// request can be trusted, by checking the request was made by a Nomad agent
+// and not sent directly by the caller. Requests are trusted if they were made
+// in process by the HTTP API of a server, or over mTLS by a client of the
+// region or by a server of any region forwarding the request.
+func validateLoginClientCertificates(srv *Server, ctx *RPCContext) error {
+	if ctx == nil {
+		return nil
+	}
+	if !ctx.TLS {
+		return errors.New("client certificates can only be sent over mTLS connections")
+	}
+
+	cert := ctx.Certificate()
+	if cert == nil {
+		return errors.New("missing certificate information")
+	}
+	names := append([]string{cert.Subject.CommonName}, cert.DNSNames...)
+	for _, name := range names {
+		if name == fmt.Sprintf("client.%s.nomad", srv.Region()) {
+			return nil
+		}
+		if strings.HasPrefix(name, "server.") && strings.HasSuffix(name, ".nomad") {
+			return nil
+		}
+	}
+	return fmt.Errorf("certificate of %s is not the certificate of a Nomad agent",
+		strings.Join(names, ","))
+}
+
 // Login RPC performs non-interactive auth using a given AuthMethod. This method
 // can not be used for OIDC login flow.
 func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginResponse) error {
@@ -2813,6 +3048,17 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginRespon
 		return aclDisabled
 	}
 
+	// Client certificates are public, so they can only be trusted if they
+	// were presented to the Nomad agent sending the request during its TLS
+	// handshake. This must be checked before forwarding the request, after
+	// which the connection is the one of the forwarding server.
+	if len(args.ClientCertificates) > 0 {
+		if err := validateLoginClientCertificates(a.srv, a.ctx); err != nil {
+			a.logger.Warn("rejecting login with client certificates", "error", err)
+			return structs.ErrPermissionDenied
+		}
+	}
+
 	// Perform the initial forwarding within the region. This ensures we
 	// respect stale queries.
 	if done, err := a.srv.forward(structs.ACLLoginRPCMethod, args, args, reply); done {
@@ -2886,6 +3132,28 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginRespon
 				err,
 			)
 		}
+	case structs.ACLAuthMethodTypeLDAP:
+		if args.Username == "" {
+			return structs.NewErrRPCCoded(
+				http.StatusBadRequest, "invalid login request: missing username")
+		}
+		claims, err = ldap.Authenticate(ctx, authMethod.Config, args.Username, args.LoginToken)
+		if err != nil {
+			return structs.NewErrRPCCodedf(
+				http.StatusUnauthorized,
+				"unable to authenticate with LDAP: %v",
+				err,
+			)
+		}
+	case structs.ACLAuthMethodTypeTLSCert:
+		claims, err = tlscert.Validate(args.ClientCertificates, authMethod.Config, time.Now())
+		if err != nil {
+			return structs.NewErrRPCCodedf(
+				http.StatusUnauthorized,
+				"unable to validate client certificate: %v",
+				err,
+			)
+		}
 	default:
 		return structs.NewErrRPCCodedf(
 			http.StatusBadRequest,
@@ -2918,7 +3186,7 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginRespon
 	// future we should try and extract out the logic into an interface, or at
 	// least a separate function.
 	token := structs.ACLToken{
-		Name:          "JWT-" + authMethod.Name,
+		Name:          authMethod.Type + "-" + authMethod.Name,
 		Global:        authMethod.TokenLocalityIsGlobal(),
 		ExpirationTTL: authMethod.MaxTokenTTL,
 	}
@@ -2952,3 +3220,42 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginRespon
 
 	return nil
 }
+
+// UpsertTokenUsage is used by servers to record when ACL tokens were last
+// used. Each server batches the usage of the tokens authenticating its
+// requests and periodically sends it to the leader.
+func (a *ACL) UpsertTokenUsage(args *structs.ACLTokenUsageUpsertRequest, reply *structs.GenericResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	authErr := a.srv.Authenticate(a.ctx, args)
+
+	// Ensure the connection was initiated by another server if TLS is used.
+	err := validateTLSCertificateLevel(a.srv, a.ctx, tlsCertificateLevelServer)
+	if err != nil {
+		return err
+	}
+	if done, err := a.srv.forward(structs.ACLUpsertTokenUsageRPCMethod, args, args, reply); done {
+		return err
+	}
+	a.srv.MeasureRPCRate("acl", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_token_usage"}, time.Now())
+
+	if len(args.Usage) == 0 {
+		return nil
+	}
+
+	// Usage is only informational, so servers which don't know the message
+	// type yet can safely ignore it.
+	_, index, err := a.srv.raftApply(
+		structs.ACLTokenUsageUpsertRequestType|structs.IgnoreUnknownTypeFlag, args)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
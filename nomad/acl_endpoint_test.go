@@ -4,6 +4,8 @@
 package nomad
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"net/url"
 	"os"
@@ -13,9 +15,14 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	capOIDC "github.com/hashicorp/cap/oidc"
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/jimlambrt/gldap"
+	"github.com/jimlambrt/gldap/testdirectory"
+	"github.com/open-wander/wander/acl"
 	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/tlsutil"
 	"github.com/open-wander/wander/helper/uuid"
 	"github.com/open-wander/wander/nomad/mock"
 	"github.com/open-wander/wander/nomad/structs"
@@ -792,6 +799,156 @@ func TestACLEndpoint_GetToken(t *testing.T) {
 	assert.Equal(t, token, resp2.Token)
 }
 
+func TestACLEndpoint_CheckToken(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	readPolicy := mock.ACLPolicy()
+	readPolicy.Name = "prod-read"
+	readPolicy.Rules = `namespace "prod-*" { policy = "read" }`
+	readPolicy.SetHash()
+
+	denyPolicy := mock.ACLPolicy()
+	denyPolicy.Name = "prod-api-deny"
+	denyPolicy.Rules = `namespace "prod-api" { policy = "deny" }`
+	denyPolicy.SetHash()
+
+	poolPolicy := mock.ACLPolicy()
+	poolPolicy.Name = "pools"
+	poolPolicy.Rules = `node_pool "*" { policy = "read" }`
+	poolPolicy.SetHash()
+
+	must.NoError(t, s1.fsm.State().UpsertACLPolicies(structs.MsgTypeTestSetup, 1000,
+		[]*structs.ACLPolicy{readPolicy, denyPolicy, poolPolicy}))
+
+	role := mock.ACLRole()
+	role.Policies = []*structs.ACLRolePolicyLink{{Name: poolPolicy.Name}}
+	must.NoError(t, s1.fsm.State().UpsertACLRoles(
+		structs.MsgTypeTestSetup, 1010, []*structs.ACLRole{role}, false))
+
+	token := mock.ACLToken()
+	token.Policies = []string{readPolicy.Name, "missing"}
+	token.Roles = []*structs.ACLTokenRoleLink{{ID: role.ID}}
+	other := mock.ACLToken()
+	must.NoError(t, s1.fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 1020, []*structs.ACLToken{token, other}))
+
+	t.Run("own token", func(t *testing.T) {
+		req := &structs.ACLTokenCheckRequest{
+			NodePool: "default",
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: "prod-web",
+				AuthToken: token.SecretID,
+			},
+		}
+		var resp structs.ACLTokenCheckResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLCheckTokenRPCMethod, req, &resp))
+		must.Eq(t, token.AccessorID, resp.AccessorID)
+		must.False(t, resp.Management)
+		must.Eq(t, []*structs.ACLTokenCheckPolicy{
+			{Name: readPolicy.Name},
+			{Name: "missing", Missing: true},
+			{Name: poolPolicy.Name, Role: role.Name},
+		}, resp.Policies)
+		must.Eq(t, uint64(1020), resp.Index)
+
+		must.Len(t, 2, resp.Explanations)
+		nsExp := resp.Explanations[0]
+		must.Eq(t, "prod-web", nsExp.Name)
+		must.Eq(t, "prod-*", nsExp.Rule)
+		for _, c := range nsExp.Capabilities {
+			if c.Capability == acl.NamespaceCapabilityReadJob {
+				must.True(t, c.Allowed)
+				must.Eq(t, []string{readPolicy.Name}, c.GrantedBy)
+			}
+			if c.Capability == acl.NamespaceCapabilitySubmitJob {
+				must.False(t, c.Allowed)
+			}
+		}
+		poolExp := resp.Explanations[1]
+		must.Eq(t, acl.ExplainResourceNodePool, poolExp.Resource)
+		must.Eq(t, "*", poolExp.Rule)
+
+		// The token can also check itself by accessor ID.
+		req.AccessorID = token.AccessorID
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLCheckTokenRPCMethod, req, &resp))
+		must.Eq(t, token.AccessorID, resp.AccessorID)
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		req := &structs.ACLTokenCheckRequest{
+			AccessorID: other.AccessorID,
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				AuthToken: token.SecretID,
+			},
+		}
+		var resp structs.ACLTokenCheckResponse
+		err := msgpackrpc.CallWithCodec(codec, structs.ACLCheckTokenRPCMethod, req, &resp)
+		must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+		req.AccessorID = ""
+		req.Policies = []string{denyPolicy.Name}
+		err = msgpackrpc.CallWithCodec(codec, structs.ACLCheckTokenRPCMethod, req, &resp)
+		must.EqError(t, err, structs.ErrPermissionDenied.Error())
+	})
+
+	t.Run("simulate policies", func(t *testing.T) {
+		req := &structs.ACLTokenCheckRequest{
+			Policies: []string{readPolicy.Name, denyPolicy.Name},
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: "prod-api",
+				AuthToken: root.SecretID,
+			},
+		}
+		var resp structs.ACLTokenCheckResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLCheckTokenRPCMethod, req, &resp))
+		must.Eq(t, "", resp.AccessorID)
+		must.Len(t, 1, resp.Explanations)
+		must.Eq(t, "prod-api", resp.Explanations[0].Rule)
+		for _, c := range resp.Explanations[0].Capabilities {
+			must.False(t, c.Allowed)
+			must.Eq(t, []string{denyPolicy.Name}, c.DeniedBy)
+		}
+	})
+
+	t.Run("management token", func(t *testing.T) {
+		req := &structs.ACLTokenCheckRequest{
+			AccessorID: root.AccessorID,
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				AuthToken: root.SecretID,
+			},
+		}
+		var resp structs.ACLTokenCheckResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLCheckTokenRPCMethod, req, &resp))
+		must.True(t, resp.Management)
+		must.SliceEmpty(t, resp.Policies)
+		for _, c := range resp.Explanations[0].Capabilities {
+			must.True(t, c.Allowed)
+		}
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		req := &structs.ACLTokenCheckRequest{
+			AccessorID: uuid.Generate(),
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				AuthToken: root.SecretID,
+			},
+		}
+		var resp structs.ACLTokenCheckResponse
+		err := msgpackrpc.CallWithCodec(codec, structs.ACLCheckTokenRPCMethod, req, &resp)
+		must.ErrorContains(t, err, "not found")
+	})
+}
+
 func TestACLEndpoint_GetToken_Blocking(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1241,6 +1398,55 @@ func TestACLEndpoint_ListTokens_PaginationFiltering(t *testing.T) {
 	}
 }
 
+func TestACLEndpoint_ListTokens_UnusedSince(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	now := time.Now().UTC()
+
+	// The tokens were created two days ago and used either never, three
+	// hours ago or a minute ago.
+	neverUsed := mock.ACLToken()
+	neverUsed.CreateTime = now.Add(-48 * time.Hour)
+	usedBefore := mock.ACLToken()
+	usedBefore.CreateTime = now.Add(-48 * time.Hour)
+	usedRecently := mock.ACLToken()
+	usedRecently.CreateTime = now.Add(-48 * time.Hour)
+	must.NoError(t, s1.fsm.State().UpsertACLTokens(structs.MsgTypeTestSetup, 1000,
+		[]*structs.ACLToken{neverUsed, usedBefore, usedRecently}))
+	must.NoError(t, s1.fsm.State().UpsertACLTokenUsage(structs.MsgTypeTestSetup, 1001,
+		[]*structs.ACLTokenUsage{
+			{AccessorID: usedBefore.AccessorID, LastUsedTime: now.Add(-3 * time.Hour)},
+			{AccessorID: usedRecently.AccessorID, LastUsedTime: now.Add(-time.Minute)},
+		}))
+
+	req := &structs.ACLTokenListRequest{
+		UnusedSince: time.Hour,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLTokenListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.ListTokens", req, &resp))
+
+	var accessors []string
+	for _, token := range resp.Tokens {
+		accessors = append(accessors, token.AccessorID)
+	}
+	must.SliceContainsAll(t, []string{neverUsed.AccessorID, usedBefore.AccessorID}, accessors)
+
+	for _, token := range resp.Tokens {
+		if token.AccessorID == usedBefore.AccessorID {
+			must.NotNil(t, token.LastUsedTime)
+		}
+	}
+}
+
 func TestACLEndpoint_ListTokens_Order(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3906,3 +4112,234 @@ func TestACL_Login(t *testing.T) {
 	must.Len(t, 0, completeAuthResp5.ACLToken.Roles)
 	must.Eq(t, structs.ACLManagementToken, completeAuthResp5.ACLToken.Type)
 }
+
+func TestACL_Login_LDAP(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Start an in-process LDAP directory holding a user that belongs to the
+	// engineering group.
+	ldapDefaults := testdirectory.WithDefaults(t, &testdirectory.Defaults{UserAttr: "uid"})
+	directory := testdirectory.Start(t,
+		testdirectory.WithLogger(t, hclog.NewNullLogger()),
+		testdirectory.WithDefaults(t, &testdirectory.Defaults{
+			UserAttr: "uid",
+			Users:    testdirectory.NewUsers(t, []string{"alice", "nomad"}, ldapDefaults),
+			Groups: []*gldap.Entry{
+				testdirectory.NewGroup(t, "engineering", []string{"alice"}, ldapDefaults),
+			},
+		}),
+	)
+
+	mockedAuthMethod := mock.ACLLDAPAuthMethod()
+	mockedAuthMethod.Config.LDAPURLs = []string{
+		fmt.Sprintf("ldaps://%s:%d", directory.Host(), directory.Port())}
+	mockedAuthMethod.Config.LDAPCACert = directory.Cert()
+	mockedAuthMethod.Config.LDAPBindDN = "uid=nomad,ou=people,dc=example,dc=org"
+	must.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(10, []*structs.ACLAuthMethod{mockedAuthMethod}))
+
+	mockACLPolicy := mock.ACLPolicy()
+	must.NoError(t, testServer.fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLPolicy{mockACLPolicy}))
+
+	mockBindingRule := mock.ACLBindingRule()
+	mockBindingRule.AuthMethod = mockedAuthMethod.Name
+	mockBindingRule.BindType = structs.ACLBindingRuleBindTypePolicy
+	mockBindingRule.Selector = "engineering in list.groups"
+	mockBindingRule.BindName = mockACLPolicy.Name
+	must.NoError(t, testServer.fsm.State().UpsertACLBindingRules(
+		30, []*structs.ACLBindingRule{mockBindingRule}, true))
+
+	// The username is required for LDAP auth methods.
+	loginReq := structs.ACLLoginRequest{
+		AuthMethodName: mockedAuthMethod.Name,
+		LoginToken:     "password",
+		WriteRequest: structs.WriteRequest{
+			Region: DefaultRegion,
+		},
+	}
+	var loginResp structs.ACLLoginResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLLoginRPCMethod, &loginReq, &loginResp)
+	must.ErrorContains(t, err, "400")
+	must.ErrorContains(t, err, "missing username")
+
+	// A wrong password must be rejected.
+	loginReq.Username = "alice"
+	loginReq.LoginToken = "wrong"
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLLoginRPCMethod, &loginReq, &loginResp)
+	must.ErrorContains(t, err, "401")
+	must.ErrorContains(t, err, "invalid LDAP credentials")
+
+	// The user is a member of the engineering group, so the binding rule
+	// matches and the token gets the policy.
+	loginReq.LoginToken = "password"
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLLoginRPCMethod, &loginReq, &loginResp)
+	must.NoError(t, err)
+	must.NotNil(t, loginResp.ACLToken)
+	must.Eq(t, "LDAP-"+mockedAuthMethod.Name, loginResp.ACLToken.Name)
+	must.Eq(t, []string{mockACLPolicy.Name}, loginResp.ACLToken.Policies)
+}
+
+func TestACL_Login_TLSCert(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Generate a CA and a client certificate signed by it, along with a
+	// client certificate signed by an untrusted CA.
+	caPEM, caKeyPEM, err := tlsutil.GenerateCA(tlsutil.CAOpts{})
+	must.NoError(t, err)
+	caSigner, err := tlsutil.ParseSigner(caKeyPEM)
+	must.NoError(t, err)
+
+	certPEM, _, err := tlsutil.GenerateCert(tlsutil.CertOpts{
+		Signer:      caSigner,
+		CA:          caPEM,
+		Name:        "web-01",
+		Days:        1,
+		DNSNames:    []string{"web-01.example.com"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	must.NoError(t, err)
+	certBlock, _ := pem.Decode([]byte(certPEM))
+
+	otherCAPEM, otherCAKeyPEM, err := tlsutil.GenerateCA(tlsutil.CAOpts{})
+	must.NoError(t, err)
+	otherCASigner, err := tlsutil.ParseSigner(otherCAKeyPEM)
+	must.NoError(t, err)
+	otherCertPEM, _, err := tlsutil.GenerateCert(tlsutil.CertOpts{
+		Signer:      otherCASigner,
+		CA:          otherCAPEM,
+		Name:        "web-01",
+		Days:        1,
+		DNSNames:    []string{"web-01.example.com"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	must.NoError(t, err)
+	otherCertBlock, _ := pem.Decode([]byte(otherCertPEM))
+
+	mockedAuthMethod := mock.ACLTLSCertAuthMethod()
+	mockedAuthMethod.Config.TLSCertCACerts = []string{caPEM}
+	must.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(10, []*structs.ACLAuthMethod{mockedAuthMethod}))
+
+	mockACLPolicy := mock.ACLPolicy()
+	must.NoError(t, testServer.fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLPolicy{mockACLPolicy}))
+
+	mockBindingRule := mock.ACLBindingRule()
+	mockBindingRule.AuthMethod = mockedAuthMethod.Name
+	mockBindingRule.BindType = structs.ACLBindingRuleBindTypePolicy
+	mockBindingRule.Selector = `"web-01.example.com" in list.dns_sans`
+	mockBindingRule.BindName = mockACLPolicy.Name
+	must.NoError(t, testServer.fsm.State().UpsertACLBindingRules(
+		30, []*structs.ACLBindingRule{mockBindingRule}, true))
+
+	// Logging in without a certificate must fail validation.
+	loginReq := structs.ACLLoginRequest{
+		AuthMethodName: mockedAuthMethod.Name,
+		WriteRequest: structs.WriteRequest{
+			Region: DefaultRegion,
+		},
+	}
+	var loginResp structs.ACLLoginResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLLoginRPCMethod, &loginReq, &loginResp)
+	must.ErrorContains(t, err, "missing login token or client certificate")
+
+	// A valid certificate sent directly to the RPC, rather than presented to
+	// the HTTP API of an agent, must be rejected.
+	loginReq.ClientCertificates = [][]byte{certBlock.Bytes}
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLLoginRPCMethod, &loginReq, &loginResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// The remaining requests are made in process, as done by the HTTP API of
+	// the server agent.
+
+	// A certificate signed by another CA must be rejected.
+	loginReq.ClientCertificates = [][]byte{otherCertBlock.Bytes}
+	err = testServer.RPC(structs.ACLLoginRPCMethod, &loginReq, &loginResp)
+	must.ErrorContains(t, err, "401")
+	must.ErrorContains(t, err, "unable to validate client certificate")
+
+	// A certificate signed by the CA of the auth method gets a token with
+	// the policy of the matching binding rule.
+	loginReq.ClientCertificates = [][]byte{certBlock.Bytes}
+	err = testServer.RPC(structs.ACLLoginRPCMethod, &loginReq, &loginResp)
+	must.NoError(t, err)
+	must.NotNil(t, loginResp.ACLToken)
+	must.Eq(t, "TLSCert-"+mockedAuthMethod.Name, loginResp.ACLToken.Name)
+	must.Eq(t, []string{mockACLPolicy.Name}, loginResp.ACLToken.Policies)
+	must.NotNil(t, loginResp.ACLToken.ExpirationTime)
+}
+
+func TestACL_validateLoginClientCertificates(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, cleanupFn := TestServer(t, nil)
+	defer cleanupFn()
+
+	certContext := func(names ...string) *RPCContext {
+		return &RPCContext{
+			TLS: true,
+			VerifiedChains: [][]*x509.Certificate{{
+				{DNSNames: names},
+			}},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		ctx    *RPCContext
+		expErr string
+	}{
+		{
+			name: "in process",
+			ctx:  nil,
+		},
+		{
+			name:   "plaintext",
+			ctx:    &RPCContext{},
+			expErr: "mTLS",
+		},
+		{
+			name:   "no certificate",
+			ctx:    &RPCContext{TLS: true},
+			expErr: "missing certificate",
+		},
+		{
+			name: "local client",
+			ctx:  certContext("client.global.nomad", "localhost"),
+		},
+		{
+			name:   "remote client",
+			ctx:    certContext("client.other.nomad"),
+			expErr: "not the certificate of a Nomad agent",
+		},
+		{
+			name: "remote server",
+			ctx:  certContext("server.other.nomad"),
+		},
+		{
+			name:   "user certificate",
+			ctx:    certContext("web-01.example.com"),
+			expErr: "not the certificate of a Nomad agent",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateLoginClientCertificates(testServer, tc.ctx)
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/open-wander/wander/helper"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+// aclTokenUsageTracker keeps the last use of each ACL token authenticating
+// requests to the server, so it can be recorded in batches rather than with
+// a raft write per request.
+type aclTokenUsageTracker struct {
+	usage map[string]*structs.ACLTokenUsage
+	lock  sync.Mutex
+}
+
+func newACLTokenUsageTracker() *aclTokenUsageTracker {
+	return &aclTokenUsageTracker{
+		usage: make(map[string]*structs.ACLTokenUsage),
+	}
+}
+
+// record tracks the use of the token at the given time and from the given
+// address, unless a more recent use is already tracked.
+func (t *aclTokenUsageTracker) record(accessorID, address string, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if existing, ok := t.usage[accessorID]; ok {
+		if now.After(existing.LastUsedTime) {
+			existing.LastUsedTime = now
+			existing.LastUsedAddress = address
+		}
+		return
+	}
+
+	t.usage[accessorID] = &structs.ACLTokenUsage{
+		AccessorID:      accessorID,
+		LastUsedTime:    now,
+		LastUsedAddress: address,
+	}
+}
+
+// drain returns the tracked usage and resets the tracker.
+func (t *aclTokenUsageTracker) drain() []*structs.ACLTokenUsage {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	usage := make([]*structs.ACLTokenUsage, 0, len(t.usage))
+	for _, u := range t.usage {
+		usage = append(usage, u)
+	}
+	t.usage = make(map[string]*structs.ACLTokenUsage, len(t.usage))
+	return usage
+}
+
+// restore tracks usage again after it failed to be recorded.
+func (t *aclTokenUsageTracker) restore(usage []*structs.ACLTokenUsage) {
+	for _, u := range usage {
+		t.record(u.AccessorID, u.LastUsedAddress, u.LastUsedTime)
+	}
+}
+
+// recordACLTokenUsage tracks the use of the token authenticating the request.
+// Requests forwarded by another server were already tracked by that server.
+func (s *Server) recordACLTokenUsage(ctx *RPCContext, args structs.RequestWithIdentity, token *structs.ACLToken) {
+	if info, ok := args.(structs.RPCInfo); ok && info.IsForwarded() {
+		return
+	}
+
+	var address string
+	if remoteIP, err := s.remoteIPFromRPCContext(ctx); err == nil && remoteIP != nil {
+		address = remoteIP.String()
+	}
+	s.aclTokenUsage.record(token.AccessorID, address, time.Now().UTC())
+}
+
+// flushACLTokenUsage is a long-lived routine which periodically sends the
+// tracked ACL token usage to the leader, which records it in the state store.
+func (s *Server) flushACLTokenUsage(ctx context.Context) {
+	ticker, stop := helper.NewSafeTimer(s.config.ACLTokenUsageFlushInterval)
+	defer stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		ticker.Reset(s.config.ACLTokenUsageFlushInterval)
+
+		usage := s.aclTokenUsage.drain()
+		if len(usage) == 0 {
+			continue
+		}
+
+		req := structs.ACLTokenUsageUpsertRequest{
+			Usage: usage,
+			WriteRequest: structs.WriteRequest{
+				Region: s.Region(),
+			},
+		}
+		if err := s.RPC(structs.ACLUpsertTokenUsageRPCMethod, &req, &structs.GenericResponse{}); err != nil {
+			s.logger.Warn("failed to record ACL token usage", "error", err)
+			s.aclTokenUsage.restore(usage)
+		}
+	}
+}
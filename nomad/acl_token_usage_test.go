@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+func TestACLTokenUsageTracker(t *testing.T) {
+	ci.Parallel(t)
+
+	tracker := newACLTokenUsageTracker()
+	now := time.Now().UTC()
+
+	tracker.record("a", "10.0.0.1", now)
+	tracker.record("a", "10.0.0.2", now.Add(-time.Second))
+	tracker.record("b", "10.0.0.3", now)
+
+	usage := tracker.drain()
+	must.Len(t, 2, usage)
+	for _, u := range usage {
+		if u.AccessorID == "a" {
+			must.Eq(t, "10.0.0.1", u.LastUsedAddress)
+		}
+	}
+	must.SliceEmpty(t, tracker.drain())
+
+	// Restored usage doesn't override more recent usage.
+	tracker.record("a", "10.0.0.4", now.Add(time.Second))
+	tracker.restore(usage)
+	usage = tracker.drain()
+	must.Len(t, 2, usage)
+	for _, u := range usage {
+		if u.AccessorID == "a" {
+			must.Eq(t, "10.0.0.4", u.LastUsedAddress)
+		}
+	}
+}
+
+func TestACLTokenUsage_Flush(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.ACLTokenUsageFlushInterval = 50 * time.Millisecond
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	token := mock.ACLToken()
+	must.NoError(t, s1.fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{token}))
+
+	req := &structs.ACLTokenSpecificRequest{
+		AccessorID: token.AccessorID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.SingleACLTokenResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetToken", req, &resp))
+
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool {
+			out, err := s1.fsm.State().ACLTokenByAccessorID(nil, token.AccessorID)
+			return err == nil && out.LastUsedTime != nil
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(50*time.Millisecond),
+	))
+
+	out, err := s1.fsm.State().ACLTokenByAccessorID(nil, token.AccessorID)
+	must.NoError(t, err)
+	must.Eq(t, "127.0.0.1", out.LastUsedAddress)
+
+	// The root token wasn't used.
+	out, err = s1.fsm.State().ACLTokenByAccessorID(nil, root.AccessorID)
+	must.NoError(t, err)
+	must.Nil(t, out.LastUsedTime)
+}
@@ -50,10 +50,12 @@ func (a *Alloc) List(args *structs.AllocListRequest, reply *structs.AllocListRes
 	if err != nil {
 		return err
 	}
-	if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+	if !aclObj.AllowJobSearch(namespace, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
-	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityReadJob)
+	allow := func(ns string) bool {
+		return aclObj.AllowJobSearch(ns, acl.NamespaceCapabilityReadJob)
+	}
 
 	// Setup the blocking query
 	sort := state.SortOption(args.Reverse)
@@ -102,6 +104,13 @@ func (a *Alloc) List(args *structs.AllocListRequest, reply *structs.AllocListRes
 					paginator.NamespaceFilter{
 						AllowableNamespaces: allowableNamespaces,
 					},
+					paginator.GenericFilter{
+						Allow: func(raw interface{}) (bool, error) {
+							alloc := raw.(*structs.Allocation)
+							return aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID,
+								alloc.TaskGroup, acl.NamespaceCapabilityReadJob), nil
+						},
+					},
 				}
 
 				var stubs []*structs.AllocListStub
@@ -153,8 +162,6 @@ func (a *Alloc) GetAlloc(args *structs.AllocSpecificRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "alloc", "get_alloc"}, time.Now())
 
-	// Check namespace read-job permissions before performing blocking query.
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadJob)
 	aclObj, err := a.srv.ResolveClientOrACL(args)
 	if err != nil {
 		return err
@@ -174,8 +181,10 @@ func (a *Alloc) GetAlloc(args *structs.AllocSpecificRequest,
 			// Setup the output
 			reply.Alloc = out
 			if out != nil {
-				// Re-check namespace in case it differs from request.
-				if !allowNsOp(aclObj, out.Namespace) {
+				// Check read-job permissions on the allocation's job, as the
+				// namespace may differ from the request.
+				if !aclObj.AllowJobGroupOperation(out.Namespace, out.JobID, out.TaskGroup,
+					acl.NamespaceCapabilityReadJob) {
 					return structs.NewErrUnknownAllocation(args.AllocID)
 				}
 
@@ -292,12 +301,12 @@ func (a *Alloc) Stop(args *structs.AllocStopRequest, reply *structs.AllocStopRes
 		return err
 	}
 
-	// Check for namespace alloc-lifecycle permissions.
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityAllocLifecycle)
+	// Check for alloc-lifecycle permissions.
 	aclObj, err := a.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if !allowNsOp(aclObj, alloc.Namespace) {
+	} else if !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup,
+		acl.NamespaceCapabilityAllocLifecycle) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -399,7 +408,7 @@ func (a *Alloc) GetServiceRegistrations(
 	if err != nil {
 		return err
 	} else if aclObj != nil {
-		if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		if !aclObj.AllowJobSearch(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 			return structs.ErrPermissionDenied
 		}
 	}
@@ -422,6 +431,10 @@ func (a *Alloc) GetServiceRegistrations(
 			if alloc == nil || alloc.Namespace != args.RequestNamespace() {
 				return nil
 			}
+			if !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup,
+				acl.NamespaceCapabilityReadJob) {
+				return structs.ErrPermissionDenied
+			}
 
 			// Perform the state query to get an iterator.
 			iter, err := stateStore.GetServiceRegistrationsByAllocID(ws, args.AllocID)
@@ -445,3 +458,85 @@ func (a *Alloc) GetServiceRegistrations(
 		},
 	})
 }
+
+// SignIdentities signs the requested workload identities of tasks. It is
+// used by clients to fetch the named identities of tasks and to renew
+// identities before they expire.
+func (a *Alloc) SignIdentities(args *structs.AllocIdentitiesRequest, reply *structs.AllocIdentitiesResponse) error {
+
+	authErr := a.srv.Authenticate(a.ctx, args)
+
+	// Ensure the connection was initiated by a client if TLS is used.
+	err := validateTLSCertificateLevel(a.srv, a.ctx, tlsCertificateLevelClient)
+	if err != nil {
+		return err
+	}
+	if done, err := a.srv.forward(structs.AllocSignIdentitiesRPCMethod, args, args, reply); done {
+		return err
+	}
+	a.srv.MeasureRPCRate("alloc", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "sign_identities"}, time.Now())
+
+	// This endpoint is only callable by nodes in the cluster, and only for
+	// the allocations they are running.
+	snap, err := a.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	node, err := snap.NodeBySecretID(nil, args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return structs.ErrTokenNotFound
+	}
+
+	now := time.Now()
+	signed := make([]*structs.SignedWorkloadIdentity, 0, len(args.Identities))
+	for _, idReq := range args.Identities {
+		alloc, err := snap.AllocByID(nil, idReq.AllocID)
+		if err != nil {
+			return err
+		}
+		if alloc == nil || alloc.Job == nil {
+			return fmt.Errorf("allocation %s not found", idReq.AllocID)
+		}
+		if alloc.NodeID != node.ID {
+			return structs.ErrPermissionDenied
+		}
+		if alloc.ClientTerminalStatus() {
+			return fmt.Errorf("allocation %s is terminal", alloc.ID)
+		}
+
+		task := alloc.LookupTask(idReq.TaskName)
+		if task == nil {
+			return fmt.Errorf("task %q not found in allocation %s", idReq.TaskName, alloc.ID)
+		}
+		wi := task.LookupIdentity(idReq.IdentityName)
+		if wi == nil {
+			return fmt.Errorf("identity %q not found for task %q", idReq.IdentityName, task.Name)
+		}
+
+		claims := alloc.ToWorkloadIdentityClaims(alloc.Job, task.Name, wi, now)
+		token, _, err := a.srv.encrypter.SignClaims(claims)
+		if err != nil {
+			return err
+		}
+
+		swi := &structs.SignedWorkloadIdentity{
+			WorkloadIdentityRequest: *idReq,
+			JWT:                     token,
+		}
+		if claims.ExpiresAt != nil {
+			swi.Expiration = claims.ExpiresAt.Time
+		}
+		signed = append(signed, swi)
+	}
+
+	reply.SignedIdentities = signed
+	a.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
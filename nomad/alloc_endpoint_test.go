@@ -16,6 +16,7 @@ import (
 	"github.com/open-wander/wander/nomad/mock"
 	"github.com/open-wander/wander/nomad/structs"
 	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -561,6 +562,59 @@ func TestAllocEndpoint_List_ACL(t *testing.T) {
 	assert.Equal(stubAllocs, resp.Allocations, "Returned alloc list not equal")
 }
 
+func TestAllocEndpoint_List_JobPolicy_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	allowed := mock.Alloc()
+	allowed.JobID = "payments-api"
+	allowed.Job.ID = allowed.JobID
+	other := mock.Alloc()
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000,
+		[]*structs.Allocation{allowed, other}))
+
+	token := mock.CreatePolicyAndToken(t, state, 1001, "test-job-policy", `
+namespace "default" {
+  job "payments-*" {
+    policy = "read"
+  }
+}`)
+
+	get := &structs.AllocListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.AllocListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.List", get, &resp))
+	must.Len(t, 1, resp.Allocations)
+	must.Eq(t, allowed.ID, resp.Allocations[0].ID)
+
+	// Reading a single allocation is also limited to the matching jobs
+	getAlloc := &structs.AllocSpecificRequest{
+		AllocID: allowed.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var allocResp structs.SingleAllocResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.GetAlloc", getAlloc, &allocResp))
+	must.Eq(t, allowed.ID, allocResp.Alloc.ID)
+
+	getAlloc.AllocID = other.ID
+	err := msgpackrpc.CallWithCodec(codec, "Alloc.GetAlloc", getAlloc, &allocResp)
+	must.ErrorContains(t, err, "Unknown allocation")
+}
+
 func TestAllocEndpoint_List_Blocking(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1673,3 +1727,75 @@ func TestAlloc_GetServiceRegistrations(t *testing.T) {
 		})
 	}
 }
+
+func TestAlloc_SignIdentities(t *testing.T) {
+	ci.Parallel(t)
+
+	s, cleanup := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanup()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	node := mock.Node()
+	must.NoError(t, s.State().UpsertNode(structs.MsgTypeTestSetup, 10, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Identities = []*structs.WorkloadIdentity{
+		{Name: "consul", Audience: []string{"consul.io"}, TTL: time.Hour},
+	}
+	must.NoError(t, s.State().UpsertAllocs(structs.MsgTypeTestSetup, 20, []*structs.Allocation{alloc}))
+
+	req := &structs.AllocIdentitiesRequest{
+		Identities: []*structs.WorkloadIdentityRequest{
+			{AllocID: alloc.ID, TaskName: task.Name, IdentityName: "consul"},
+			{AllocID: alloc.ID, TaskName: task.Name, IdentityName: structs.WorkloadIdentityDefaultName},
+		},
+		QueryOptions: structs.QueryOptions{
+			Region:    s.Region(),
+			AuthToken: node.SecretID,
+		},
+	}
+	var resp structs.AllocIdentitiesResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.AllocSignIdentitiesRPCMethod, req, &resp))
+	must.Len(t, 2, resp.SignedIdentities)
+
+	consulID := resp.SignedIdentities[0]
+	must.Eq(t, "consul", consulID.IdentityName)
+	must.False(t, consulID.Expiration.IsZero())
+
+	claims, err := s.encrypter.VerifyClaim(consulID.JWT)
+	must.NoError(t, err)
+	must.Eq(t, alloc.ID, claims.AllocationID)
+	must.Eq(t, task.Name, claims.TaskName)
+	must.Eq(t, []string{"consul.io"}, []string(claims.Audience))
+
+	// Identities for other audiences can not be used to authenticate to
+	// Nomad, unlike the default identity.
+	_, err = s.VerifyClaim(consulID.JWT)
+	must.ErrorContains(t, err, "invalid audience")
+	_, err = s.VerifyClaim(resp.SignedIdentities[1].JWT)
+	must.NoError(t, err)
+
+	// Unknown identities are rejected.
+	req.Identities = []*structs.WorkloadIdentityRequest{
+		{AllocID: alloc.ID, TaskName: task.Name, IdentityName: "vault"},
+	}
+	err = msgpackrpc.CallWithCodec(codec, structs.AllocSignIdentitiesRPCMethod, req, &resp)
+	must.ErrorContains(t, err, `identity "vault" not found`)
+
+	// Only the node running the allocation can sign its identities.
+	otherNode := mock.Node()
+	must.NoError(t, s.State().UpsertNode(structs.MsgTypeTestSetup, 30, otherNode))
+	req.Identities[0].IdentityName = "consul"
+	req.AuthToken = otherNode.SecretID
+	err = msgpackrpc.CallWithCodec(codec, structs.AllocSignIdentitiesRPCMethod, req, &resp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	req.AuthToken = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, structs.AllocSignIdentitiesRPCMethod, req, &resp)
+	must.Error(t, err)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/open-wander/wander/acl"
 	cstructs "github.com/open-wander/wander/client/structs"
 	"github.com/open-wander/wander/helper/pointer"
+	"github.com/open-wander/wander/nomad/state"
 	"github.com/open-wander/wander/nomad/structs"
 )
 
@@ -33,6 +34,7 @@ func NewClientAllocationsEndpoint(srv *Server) *ClientAllocations {
 
 func (a *ClientAllocations) register() {
 	a.srv.streamingRpcs.Register("Allocations.Exec", a.exec)
+	a.srv.streamingRpcs.Register("Allocations.PortForward", a.portForward)
 }
 
 // GarbageCollectAll is used to garbage collect all allocations on a client.
@@ -125,7 +127,7 @@ func (a *ClientAllocations) Signal(args *structs.AllocSignalRequest, reply *stru
 	// Check namespace alloc-lifecycle permission.
 	if aclObj, err := a.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocLifecycle) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityAllocLifecycle) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -183,7 +185,7 @@ func (a *ClientAllocations) GarbageCollect(args *structs.AllocSpecificRequest, r
 	// Check namespace submit-job permission.
 	if aclObj, err := a.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -236,7 +238,7 @@ func (a *ClientAllocations) Restart(args *structs.AllocRestartRequest, reply *st
 	// Check for namespace alloc-lifecycle permissions.
 	if aclObj, err := a.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocLifecycle) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityAllocLifecycle) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -289,7 +291,7 @@ func (a *ClientAllocations) Stats(args *cstructs.AllocStatsRequest, reply *cstru
 	// Check for namespace read-job permissions.
 	if aclObj, err := a.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -349,7 +351,7 @@ func (a *ClientAllocations) Checks(args *cstructs.AllocChecksRequest, reply *cst
 	// Check for namespace read-job permissions.
 	if aclObj, err := a.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -424,13 +426,85 @@ func (a *ClientAllocations) exec(conn io.ReadWriteCloser) {
 	if aclObj, err := a.srv.ResolveACL(&args); err != nil {
 		handleStreamResultError(err, nil, encoder)
 		return
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocExec) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityAllocExec) {
 		// client ultimately checks if AllocNodeExec is required
 		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 		return
 	}
 
-	nodeID := alloc.NodeID
+	forwardStreamToNode(a.srv, conn, encoder, snap, alloc.NodeID, "Allocations.Exec", &args)
+}
+
+// portForward is used to forward a TCP connection to a port of a running
+// allocation
+func (a *ClientAllocations) portForward(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "port_forward"}, time.Now())
+
+	// Decode the arguments
+	var args cstructs.AllocPortForwardRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(500)), encoder)
+		return
+	}
+
+	authErr := a.srv.Authenticate(nil, &args)
+
+	// Check if we need to forward to a different region
+	if r := args.RequestRegion(); r != a.srv.Region() {
+		forwardRegionStreamingRpc(a.srv, conn, encoder, &args, "Allocations.PortForward",
+			args.AllocID, &args.QueryOptions)
+		return
+	}
+	a.srv.MeasureRPCRate("client_allocations", structs.RateMetricWrite, &args)
+	if authErr != nil {
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		return
+	}
+
+	// Verify the arguments.
+	if args.AllocID == "" {
+		handleStreamResultError(errors.New("missing AllocID"), pointer.Of(int64(400)), encoder)
+		return
+	}
+
+	// Retrieve the allocation
+	snap, err := a.srv.State().Snapshot()
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	alloc, err := getAlloc(snap, args.AllocID)
+	if structs.IsErrUnknownAllocation(err) {
+		handleStreamResultError(err, pointer.Of(int64(404)), encoder)
+		return
+	}
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	// Check alloc-port-forward permissions
+	if aclObj, err := a.srv.ResolveACL(&args); err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityAllocPortForward) {
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		return
+	}
+
+	forwardStreamToNode(a.srv, conn, encoder, snap, alloc.NodeID, "Allocations.PortForward", &args)
+}
+
+// forwardStreamToNode sends the request of a streaming RPC to the node
+// running the allocation, directly or through the server connected to it,
+// and bridges the streams.
+func forwardStreamToNode(s *Server, conn io.ReadWriteCloser, encoder *codec.Encoder,
+	snap *state.StateSnapshot, nodeID, method string, args interface{}) {
 
 	// Make sure Node is valid and new enough to support RPC
 	node, err := snap.NodeByID(nil, nodeID)
@@ -453,10 +527,10 @@ func (a *ClientAllocations) exec(conn io.ReadWriteCloser) {
 	// Get the connection to the client either by forwarding to another server
 	// or creating a direct stream
 	var clientConn net.Conn
-	state, ok := a.srv.getNodeConn(nodeID)
+	state, ok := s.getNodeConn(nodeID)
 	if !ok {
 		// Determine the Server that has a connection to the node.
-		srv, err := a.srv.serverWithNodeConn(nodeID, a.srv.Region())
+		srv, err := s.serverWithNodeConn(nodeID, s.Region())
 		if err != nil {
 			var code *int64
 			if structs.IsErrNoNodeConn(err) {
@@ -467,7 +541,7 @@ func (a *ClientAllocations) exec(conn io.ReadWriteCloser) {
 		}
 
 		// Get a connection to the server
-		conn, err := a.srv.streamingRpc(srv, "Allocations.Exec")
+		conn, err := s.streamingRpc(srv, method)
 		if err != nil {
 			handleStreamResultError(err, nil, encoder)
 			return
@@ -475,7 +549,7 @@ func (a *ClientAllocations) exec(conn io.ReadWriteCloser) {
 
 		clientConn = conn
 	} else {
-		stream, err := NodeStreamingRpc(state.Session, "Allocations.Exec")
+		stream, err := NodeStreamingRpc(state.Session, method)
 		if err != nil {
 			handleStreamResultError(err, nil, encoder)
 			return
@@ -25,6 +25,7 @@ import (
 	"github.com/open-wander/wander/plugins/drivers"
 	"github.com/open-wander/wander/testutil"
 	"github.com/kr/pretty"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/require"
 )
 
@@ -1254,3 +1255,88 @@ func decodeFrames(t *testing.T, p1 net.Conn, frames chan<- *drivers.ExecTaskStre
 		frames <- &frame
 	}
 }
+
+func TestAlloc_PortForward(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a listener for the allocation to expose
+	l, err := net.Listen("tcp", "0.0.0.0:0")
+	must.NoError(t, err)
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := client.TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.config.RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Networks = []*nstructs.NetworkResource{{
+		ReservedPorts: []nstructs.Port{{Label: "echo", Value: port}},
+	}}
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+	alloc := testutil.WaitForRunningWithToken(t, s.RPC, job, root.SecretID)[0]
+	must.Eq(t, c.NodeID(), alloc.NodeID)
+
+	policy := mock.NamespacePolicy(nstructs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocExec})
+	token := mock.CreatePolicyAndToken(t, s.State(), 1005, "no-port-forward", policy)
+
+	forward := func(token string) (net.Conn, *codec.Decoder) {
+		handler, err := s.StreamingRpcHandler("Allocations.PortForward")
+		must.NoError(t, err)
+
+		p1, p2 := net.Pipe()
+		t.Cleanup(func() { p1.Close() })
+		go handler(p2)
+
+		encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+		must.NoError(t, encoder.Encode(&cstructs.AllocPortForwardRequest{
+			AllocID: alloc.ID,
+			Port:    "echo",
+			QueryOptions: nstructs.QueryOptions{
+				Region:    "global",
+				AuthToken: token,
+				Namespace: nstructs.DefaultNamespace,
+			},
+		}))
+		return p1, codec.NewDecoder(p1, nstructs.MsgpackHandle)
+	}
+
+	// Tokens without the alloc-port-forward capability are rejected
+	_, decoder := forward(token.SecretID)
+	var frame cstructs.StreamErrWrapper
+	must.NoError(t, decoder.Decode(&frame))
+	must.NotNil(t, frame.Error)
+	must.StrContains(t, frame.Error.Error(), nstructs.ErrPermissionDenied.Error())
+
+	// The connection is forwarded to the client running the allocation
+	conn, decoder := forward(root.SecretID)
+	encoder := codec.NewEncoder(conn, nstructs.MsgpackHandle)
+	must.NoError(t, encoder.Encode(&cstructs.StreamErrWrapper{Payload: []byte("ping")}))
+
+	var received []byte
+	for len(received) < 4 {
+		var frame cstructs.StreamErrWrapper
+		must.NoError(t, decoder.Decode(&frame))
+		must.Nil(t, frame.Error)
+		received = append(received, frame.Payload...)
+	}
+	must.Eq(t, "ping", string(received))
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+// ArtifactCache is used to forward RPC requests to the artifact cache of the
+// targeted Nomad client.
+type ArtifactCache struct {
+	srv    *Server
+	logger log.Logger
+}
+
+func newArtifactCacheEndpoint(srv *Server) *ArtifactCache {
+	return &ArtifactCache{
+		srv:    srv,
+		logger: srv.logger.Named("artifact_cache"),
+	}
+}
+
+func (a *ArtifactCache) List(args *structs.NodeSpecificRequest, reply *structs.ArtifactCacheListResponse) error {
+	const method = "ArtifactCache.List"
+
+	// Prevent infinite loop between leader and
+	// follower-with-the-target-node-connection.
+	args.QueryOptions.AllowStale = true
+
+	authErr := a.srv.Authenticate(nil, args)
+	if done, err := a.srv.forward(method, args, args, reply); done {
+		return err
+	}
+	a.srv.MeasureRPCRate("artifact_cache", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "artifact_cache", "list"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := a.srv.ResolveACL(args); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	return a.srv.forwardClientRPC(method, args.NodeID, args, reply)
+}
+
+func (a *ArtifactCache) Purge(args *structs.ArtifactCachePurgeRequest, reply *structs.ArtifactCachePurgeResponse) error {
+	const method = "ArtifactCache.Purge"
+
+	// Prevent infinite loop between leader and
+	// follower-with-the-target-node-connection.
+	args.QueryOptions.AllowStale = true
+
+	authErr := a.srv.Authenticate(nil, args)
+	if done, err := a.srv.forward(method, args, args, reply); done {
+		return err
+	}
+	a.srv.MeasureRPCRate("artifact_cache", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "artifact_cache", "purge"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := a.srv.ResolveACL(args); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	return a.srv.forwardClientRPC(method, args.NodeID, args, reply)
+}
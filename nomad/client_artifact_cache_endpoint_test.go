@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/client"
+	"github.com/open-wander/wander/client/config"
+	"github.com/open-wander/wander/nomad/structs"
+	sconfig "github.com/open-wander/wander/nomad/structs/config"
+	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestArtifactCache_Local(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server and a client with the artifact cache enabled
+	s, cleanupS := TestServer(t, nil)
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+		ac, err := config.ArtifactConfigFromAgent(sconfig.DefaultArtifactConfig())
+		must.NoError(t, err)
+		ac.CacheSize = 1 << 20
+		c.Artifact = ac
+	})
+	defer cleanupC()
+	testutil.WaitForClient(t, s.RPC, c.NodeID(), c.Region())
+
+	// The node id is required
+	listReq := &structs.NodeSpecificRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.ArtifactCacheListResponse
+	err := msgpackrpc.CallWithCodec(codec, "ArtifactCache.List", listReq, &listResp)
+	must.ErrorContains(t, err, "missing")
+
+	listReq.NodeID = c.NodeID()
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ArtifactCache.List", listReq, &listResp))
+	must.True(t, listResp.Enabled)
+	must.Eq(t, 1<<20, listResp.MaxSize)
+
+	purgeReq := &structs.ArtifactCachePurgeRequest{
+		NodeID:       c.NodeID(),
+		Key:          "unknown",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var purgeResp structs.ArtifactCachePurgeResponse
+	err = msgpackrpc.CallWithCodec(codec, "ArtifactCache.Purge", purgeReq, &purgeResp)
+	must.ErrorContains(t, err, "artifact not found in cache")
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/hashicorp/go-msgpack/codec"
 	"github.com/open-wander/wander/acl"
+	"github.com/open-wander/wander/nomad/state"
 	"github.com/open-wander/wander/nomad/structs"
 )
 
@@ -35,6 +36,8 @@ func NewFileSystemEndpoint(srv *Server) *FileSystem {
 func (f *FileSystem) register() {
 	f.srv.streamingRpcs.Register("FileSystem.Logs", f.logs)
 	f.srv.streamingRpcs.Register("FileSystem.Stream", f.stream)
+	f.srv.streamingRpcs.Register("FileSystem.Upload", f.upload)
+	f.srv.streamingRpcs.Register("FileSystem.Archive", f.archive)
 }
 
 // handleStreamResultError is a helper for sending an error with a potential
@@ -137,12 +140,11 @@ func (f *FileSystem) List(args *cstructs.FsListRequest, reply *cstructs.FsListRe
 		return err
 	}
 
-	// Check namespace filesystem read permissions
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadFS)
+	// Check filesystem read permissions
 	aclObj, err := f.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if !allowNsOp(aclObj, alloc.Namespace) {
+	} else if !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadFS) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -200,7 +202,7 @@ func (f *FileSystem) Stat(args *cstructs.FsStatRequest, reply *cstructs.FsStatRe
 	// Check filesystem read permissions
 	if aclObj, err := f.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadFS) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -277,7 +279,7 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	if aclObj, err := f.srv.ResolveACL(&args); err != nil {
 		handleStreamResultError(err, nil, encoder)
 		return
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadFS) {
 		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 		return
 	}
@@ -398,14 +400,13 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 		return
 	}
 
-	// Check namespace read-logs *or* read-fs permissions.
-	allowNsOp := acl.NamespaceValidator(
-		acl.NamespaceCapabilityReadFS, acl.NamespaceCapabilityReadLogs)
+	// Check read-logs *or* read-fs permissions.
 	aclObj, err := f.srv.ResolveACL(&args)
 	if err != nil {
 		handleStreamResultError(err, nil, encoder)
 		return
-	} else if !allowNsOp(aclObj, alloc.Namespace) {
+	} else if !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadFS) &&
+		!aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityReadLogs) {
 		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 		return
 	}
@@ -473,3 +474,178 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 
 	structs.Bridge(conn, clientConn)
 }
+
+// Remove is used to remove a file or directory from an allocation's
+// directory.
+func (f *FileSystem) Remove(args *cstructs.FsRemoveRequest, reply *cstructs.FsRemoveResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hope
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	authErr := f.srv.Authenticate(nil, args)
+
+	// Potentially forward to a different region.
+	if done, err := f.srv.forward("FileSystem.Remove", args, args, reply); done {
+		return err
+	}
+	f.srv.MeasureRPCRate("file_system", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "file_system", "remove"}, time.Now())
+
+	// Verify the arguments.
+	if args.AllocID == "" {
+		return errors.New("missing allocation ID")
+	}
+
+	// Lookup the allocation
+	snap, err := f.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	alloc, err := getAlloc(snap, args.AllocID)
+	if err != nil {
+		return err
+	}
+
+	// Check filesystem write permissions
+	aclObj, err := f.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, acl.NamespaceCapabilityWriteFS) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Make sure Node is valid and new enough to support RPC
+	_, err = getNodeForRpc(snap, alloc.NodeID)
+	if err != nil {
+		return err
+	}
+
+	// Get the connection to the client
+	state, ok := f.srv.getNodeConn(alloc.NodeID)
+	if !ok {
+		return findNodeConnAndForward(f.srv, alloc.NodeID, "FileSystem.Remove", args, reply)
+	}
+
+	// Make the RPC
+	return NodeRpc(state.Session, "FileSystem.Remove", args, reply)
+}
+
+// upload is used to write a file, or extract a tar archive, into an
+// allocation's directory.
+func (f *FileSystem) upload(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	defer metrics.MeasureSince([]string{"nomad", "file_system", "upload"}, time.Now())
+
+	// Decode the arguments
+	var args cstructs.FsUploadRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(500)), encoder)
+		return
+	}
+
+	authErr := f.srv.Authenticate(nil, &args)
+
+	// Check if we need to forward to a different region
+	if r := args.RequestRegion(); r != f.srv.Region() {
+		forwardRegionStreamingRpc(f.srv, conn, encoder, &args, "FileSystem.Upload",
+			args.AllocID, &args.QueryOptions)
+		return
+	}
+	f.srv.MeasureRPCRate("file_system", structs.RateMetricWrite, &args)
+	if authErr != nil {
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		return
+	}
+
+	snap, alloc, ok := f.streamAlloc(args.AllocID, &args, acl.NamespaceCapabilityWriteFS, encoder)
+	if !ok {
+		return
+	}
+	forwardStreamToNode(f.srv, conn, encoder, snap, alloc.NodeID, "FileSystem.Upload", &args)
+}
+
+// archive is used to stream a gzip compressed tar archive of a file or
+// directory of an allocation's directory.
+func (f *FileSystem) archive(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	defer metrics.MeasureSince([]string{"nomad", "file_system", "archive"}, time.Now())
+
+	// Decode the arguments
+	var args cstructs.FsArchiveRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(500)), encoder)
+		return
+	}
+
+	authErr := f.srv.Authenticate(nil, &args)
+
+	// Check if we need to forward to a different region
+	if r := args.RequestRegion(); r != f.srv.Region() {
+		forwardRegionStreamingRpc(f.srv, conn, encoder, &args, "FileSystem.Archive",
+			args.AllocID, &args.QueryOptions)
+		return
+	}
+	f.srv.MeasureRPCRate("file_system", structs.RateMetricRead, &args)
+	if authErr != nil {
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		return
+	}
+
+	snap, alloc, ok := f.streamAlloc(args.AllocID, &args, acl.NamespaceCapabilityReadFS, encoder)
+	if !ok {
+		return
+	}
+	forwardStreamToNode(f.srv, conn, encoder, snap, alloc.NodeID, "FileSystem.Archive", &args)
+}
+
+// streamAlloc looks up the allocation of a streaming filesystem request and
+// checks the token of the request has the capability for it. Errors are sent
+// to the encoder, in which case it returns false.
+func (f *FileSystem) streamAlloc(allocID string, args structs.RequestWithIdentity, capability string,
+	encoder *codec.Encoder) (*state.StateSnapshot, *structs.Allocation, bool) {
+
+	// Verify the arguments.
+	if allocID == "" {
+		handleStreamResultError(errors.New("missing AllocID"), pointer.Of(int64(400)), encoder)
+		return nil, nil, false
+	}
+
+	// Retrieve the allocation
+	snap, err := f.srv.State().Snapshot()
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return nil, nil, false
+	}
+
+	alloc, err := getAlloc(snap, allocID)
+	if structs.IsErrUnknownAllocation(err) {
+		handleStreamResultError(structs.NewErrUnknownAllocation(allocID), pointer.Of(int64(404)), encoder)
+		return nil, nil, false
+	}
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return nil, nil, false
+	}
+
+	// Check namespace filesystem permissions.
+	if aclObj, err := f.srv.ResolveACL(args); err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return nil, nil, false
+	} else if aclObj != nil && !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID, alloc.TaskGroup, capability) {
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		return nil, nil, false
+	}
+
+	return snap, alloc, true
+}
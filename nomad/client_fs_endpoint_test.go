@@ -22,6 +22,7 @@ import (
 	"github.com/open-wander/wander/nomad/mock"
 	"github.com/open-wander/wander/nomad/structs"
 	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/require"
 )
 
@@ -385,6 +386,120 @@ func TestClientFS_Stat_Local(t *testing.T) {
 	require.NotNil(resp2.Info)
 }
 
+func TestClientFS_Upload_Local(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server and client
+	s, cleanupS := TestServer(t, nil)
+	defer cleanupS()
+	rpcCodec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	// Force an allocation onto the node
+	a := mock.Alloc()
+	a.Job.Type = structs.JobTypeBatch
+	a.NodeID = c.NodeID()
+	a.Job.TaskGroups[0].Count = 1
+	a.Job.TaskGroups[0].Tasks[0] = &structs.Task{
+		Name:   "web",
+		Driver: "mock_driver",
+		Config: map[string]interface{}{
+			"run_for": "20s",
+		},
+		LogConfig: structs.DefaultLogConfig(),
+		Resources: &structs.Resources{
+			CPU:      500,
+			MemoryMB: 256,
+		},
+	}
+
+	// Wait for the client to connect
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Upsert the allocation
+	state := s.State()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 999, nil, a.Job))
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{a}))
+
+	// Wait for the client to run the allocation
+	testutil.WaitForResult(func() (bool, error) {
+		alloc, err := state.AllocByID(nil, a.ID)
+		if err != nil {
+			return false, err
+		}
+		if alloc == nil {
+			return false, fmt.Errorf("unknown alloc")
+		}
+		if alloc.ClientStatus != structs.AllocClientStatusRunning {
+			return false, fmt.Errorf("alloc client status: %v", alloc.ClientStatus)
+		}
+
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("Alloc on node %q not running: %v", c.NodeID(), err)
+	})
+
+	// Get the handler
+	handler, err := s.StreamingRpcHandler("FileSystem.Upload")
+	must.NoError(t, err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	// Start the handler
+	go handler(p2)
+
+	// Send the request, the content, and the empty frame ending the upload
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	must.NoError(t, encoder.Encode(&cstructs.FsUploadRequest{
+		AllocID:      a.ID,
+		Path:         "alloc/data/upload",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}))
+	must.NoError(t, encoder.Encode(&cstructs.StreamErrWrapper{Payload: []byte("uploaded")}))
+	must.NoError(t, encoder.Encode(&cstructs.StreamErrWrapper{}))
+
+	var msg cstructs.StreamErrWrapper
+	decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+	must.NoError(t, decoder.Decode(&msg))
+	must.Nil(t, msg.Error)
+
+	// Check the file was written
+	var resp cstructs.FsStatResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(rpcCodec, "FileSystem.Stat", &cstructs.FsStatRequest{
+		AllocID:      a.ID,
+		Path:         "alloc/data/upload",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}, &resp))
+	must.Eq(t, 8, resp.Info.Size)
+
+	// Remove the file
+	must.NoError(t, msgpackrpc.CallWithCodec(rpcCodec, "FileSystem.Remove", &cstructs.FsRemoveRequest{
+		AllocID:      a.ID,
+		Path:         "alloc/data/upload",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}, &cstructs.FsRemoveResponse{}))
+
+	err = msgpackrpc.CallWithCodec(rpcCodec, "FileSystem.Stat", &cstructs.FsStatRequest{
+		AllocID:      a.ID,
+		Path:         "alloc/data/upload",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}, &resp)
+	must.ErrorContains(t, err, "no such file")
+}
+
 func TestClientFS_Stat_ACL(t *testing.T) {
 	ci.Parallel(t)
 
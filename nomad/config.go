@@ -14,6 +14,8 @@ import (
 	"golang.org/x/exp/slices"
 
 	"github.com/hashicorp/memberlist"
+	"github.com/open-wander/wander/helper"
+	"github.com/open-wander/wander/helper/pluginutils/loader"
 	"github.com/open-wander/wander/helper/pointer"
 	"github.com/open-wander/wander/helper/uuid"
 	"github.com/open-wander/wander/nomad/deploymentwatcher"
@@ -192,6 +194,15 @@ type Config struct {
 	// for GC. This gives users some time to view terminal deployments.
 	DeploymentGCThreshold time.Duration
 
+	// ExecSessionGCInterval is how often we dispatch a job to GC ended exec
+	// sessions.
+	ExecSessionGCInterval time.Duration
+
+	// ExecSessionGCThreshold is how long ago an exec session must have ended
+	// to be eligible for GC. Recordings are kept by the clients for their own
+	// retention period.
+	ExecSessionGCThreshold time.Duration
+
 	// CSIPluginGCInterval is how often we dispatch a job to GC unused plugins.
 	CSIPluginGCInterval time.Duration
 
@@ -219,6 +230,10 @@ type Config struct {
 	// must be to be collected by GC.
 	ACLTokenExpirationGCThreshold time.Duration
 
+	// ACLTokenUsageFlushInterval is how often servers record the last use of
+	// the ACL tokens authenticating their requests.
+	ACLTokenUsageFlushInterval time.Duration
+
 	// RootKeyGCInterval is how often we dispatch a job to GC
 	// encryption key metadata
 	RootKeyGCInterval time.Duration
@@ -231,6 +246,11 @@ type Config struct {
 	// before it's rotated
 	RootKeyRotationThreshold time.Duration
 
+	// OIDCIssuer is the issuer of workload identities. When set, it is
+	// included as the "iss" claim of workload identities and the OIDC
+	// discovery document is published under this URL.
+	OIDCIssuer string
+
 	// VariablesRekeyInterval is how often we dispatch a job to
 	// rekey any variables associated with a key in the Rekeying state
 	VariablesRekeyInterval time.Duration
@@ -342,6 +362,11 @@ type Config struct {
 	// for ACL token expiration.
 	ACLTokenMaxExpirationTTL time.Duration
 
+	// ACLTokenUnusedTTL is how long a local client token can go unused
+	// before it is garbage collected like an expired token. Unused tokens
+	// are kept if it is zero.
+	ACLTokenUnusedTTL time.Duration
+
 	// SentinelGCInterval is the interval that we GC unused policies.
 	SentinelGCInterval time.Duration
 
@@ -362,6 +387,15 @@ type Config struct {
 	// rate is well-controlled but cardinality of requesters is high.
 	DisableRPCRateMetricsLabels bool
 
+	// RPCRateLimit configures the rate limits enforced on the RPC requests
+	// handled by the server. Requests are not limited if nil.
+	RPCRateLimit *config.RPCRateLimitConfig
+
+	// KEKProviderConfigs configures the providers of the key encryption keys
+	// wrapping the root keys in the server's keystore. The keys are wrapped
+	// with a key stored in the keystore if none are configured.
+	KEKProviderConfigs []*config.KEKProviderConfig
+
 	// AutopilotConfig is used to apply the initial autopilot config when
 	// bootstrapping.
 	AutopilotConfig *structs.AutopilotConfig
@@ -419,6 +453,13 @@ type Config struct {
 
 	// JobTrackedVersions is the number of historic Job versions that are kept.
 	JobTrackedVersions int
+
+	// SchedulerExtensions are the scheduler plugins consulted, in order, by
+	// the generic scheduler when ranking nodes.
+	SchedulerExtensions []*SchedulerExtensionConfig
+
+	// PluginLoader is used to launch the scheduler extension plugins.
+	PluginLoader loader.PluginCatalog
 }
 
 func (c *Config) Copy() *Config {
@@ -440,6 +481,7 @@ func (c *Config) Copy() *Config {
 	nc.RaftConfig = pointer.Copy(c.RaftConfig)
 	nc.SerfConfig = pointer.Copy(c.SerfConfig)
 	nc.EnabledSchedulers = slices.Clone(c.EnabledSchedulers)
+	nc.SchedulerExtensions = helper.CopySlice(c.SchedulerExtensions)
 	nc.ConsulConfig = c.ConsulConfig.Copy()
 	nc.VaultConfig = c.VaultConfig.Copy()
 	nc.TLSConfig = c.TLSConfig.Copy()
@@ -447,6 +489,8 @@ func (c *Config) Copy() *Config {
 	nc.AutopilotConfig = c.AutopilotConfig.Copy()
 	nc.LicenseConfig = c.LicenseConfig.Copy()
 	nc.SearchConfig = c.SearchConfig.Copy()
+	nc.RPCRateLimit = c.RPCRateLimit.Copy()
+	nc.KEKProviderConfigs = helper.CopySlice(c.KEKProviderConfigs)
 
 	return &nc
 }
@@ -481,12 +525,15 @@ func DefaultConfig() *Config {
 		NodeGCThreshold:                  24 * time.Hour,
 		DeploymentGCInterval:             5 * time.Minute,
 		DeploymentGCThreshold:            1 * time.Hour,
+		ExecSessionGCInterval:            5 * time.Minute,
+		ExecSessionGCThreshold:           720 * time.Hour, // 30 days
 		CSIPluginGCInterval:              5 * time.Minute,
 		CSIPluginGCThreshold:             1 * time.Hour,
 		CSIVolumeClaimGCInterval:         5 * time.Minute,
 		CSIVolumeClaimGCThreshold:        5 * time.Minute,
 		OneTimeTokenGCInterval:           10 * time.Minute,
 		ACLTokenExpirationGCInterval:     5 * time.Minute,
+		ACLTokenUsageFlushInterval:       1 * time.Minute,
 		ACLTokenExpirationGCThreshold:    1 * time.Hour,
 		RootKeyGCInterval:                10 * time.Minute,
 		RootKeyGCThreshold:               1 * time.Hour,
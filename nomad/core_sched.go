@@ -52,6 +52,8 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.jobGC(eval)
 	case structs.CoreJobDeploymentGC:
 		return c.deploymentGC(eval)
+	case structs.CoreJobExecSessionGC:
+		return c.execSessionGC(eval)
 	case structs.CoreJobCSIVolumeClaimGC:
 		return c.csiVolumeClaimGC(eval)
 	case structs.CoreJobCSIPluginGC:
@@ -84,6 +86,9 @@ func (c *CoreScheduler) forceGC(eval *structs.Evaluation) error {
 	if err := c.deploymentGC(eval); err != nil {
 		return err
 	}
+	if err := c.execSessionGC(eval); err != nil {
+		return err
+	}
 	if err := c.csiPluginGC(eval); err != nil {
 		return err
 	}
@@ -615,6 +620,56 @@ func (c *CoreScheduler) partitionDeploymentReap(deployments []string, batchSize
 	return requests
 }
 
+// execSessionGC is used to garbage collect ended exec sessions.
+func (c *CoreScheduler) execSessionGC(eval *structs.Evaluation) error {
+	ws := memdb.NewWatchSet()
+	iter, err := c.snap.ExecSessions(ws)
+	if err != nil {
+		return err
+	}
+
+	oldThreshold := c.getThreshold(eval, "exec session",
+		"exec_session_gc_threshold", c.srv.config.ExecSessionGCThreshold)
+
+	var gcSessions []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		session := raw.(*structs.ExecSession)
+
+		// Ignore running and recently ended sessions
+		if !session.Ended() || session.ModifyIndex > oldThreshold {
+			continue
+		}
+		gcSessions = append(gcSessions, session.ID)
+	}
+
+	// Fast-path the nothing case
+	if len(gcSessions) == 0 {
+		return nil
+	}
+	c.logger.Debug("exec session GC found eligible sessions", "sessions", len(gcSessions))
+
+	for len(gcSessions) > 0 {
+		batch := gcSessions
+		if len(batch) > structs.MaxUUIDsPerWriteRequest {
+			batch = batch[:structs.MaxUUIDsPerWriteRequest]
+		}
+		gcSessions = gcSessions[len(batch):]
+
+		req := &structs.ExecSessionDeleteRequest{
+			IDs: batch,
+			WriteRequest: structs.WriteRequest{
+				Region:    c.srv.config.Region,
+				AuthToken: eval.LeaderACL,
+			},
+		}
+		if err := c.srv.RPC("ExecSession.Reap", req, &structs.GenericResponse{}); err != nil {
+			c.logger.Error("exec session reap failed", "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
 // allocGCEligible returns if the allocation is eligible to be garbage collected
 // according to its terminal status and its reschedule trackers
 func allocGCEligible(a *structs.Allocation, job *structs.Job, gcTime time.Time, thresholdIndex uint64) bool {
@@ -872,6 +927,17 @@ func (c *CoreScheduler) expiredACLTokenGC(eval *structs.Evaluation, global bool)
 		}
 	}
 
+	// Local client tokens which went unused for longer than the configured
+	// TTL are collected along with the expired tokens. Global tokens may be
+	// used in other regions, which this region doesn't know about.
+	if !global && c.srv.config.ACLTokenUnusedTTL > 0 && num < structs.ACLMaxExpiredBatchSize {
+		unused, err := c.unusedACLTokens(now, structs.ACLMaxExpiredBatchSize-num)
+		if err != nil {
+			return err
+		}
+		expiredAccessorIDs = append(expiredAccessorIDs, unused...)
+	}
+
 	// There is no need to call the RPC endpoint if we do not have any tokens
 	// to delete.
 	if len(expiredAccessorIDs) < 1 {
@@ -896,6 +962,34 @@ func (c *CoreScheduler) expiredACLTokenGC(eval *structs.Evaluation, global bool)
 	return c.srv.RPC(structs.ACLDeleteTokensRPCMethod, req, &structs.GenericResponse{})
 }
 
+// unusedACLTokens returns the accessor IDs of up to limit local client tokens
+// which were neither used nor created within the unused token TTL. Expired
+// tokens are left to the expired token GC.
+func (c *CoreScheduler) unusedACLTokens(now time.Time, limit int) ([]string, error) {
+	iter, err := c.snap.ACLTokensByGlobal(nil, false, state.SortDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := now.Add(-c.srv.config.ACLTokenUnusedTTL)
+
+	var unused []string
+	for raw := iter.Next(); raw != nil && len(unused) < limit; raw = iter.Next() {
+		token := raw.(*structs.ACLToken)
+		if token.Type != structs.ACLClientToken || token.IsExpired(now) {
+			continue
+		}
+		if token.IsUnusedSince(cutoff) {
+			unused = append(unused, token.AccessorID)
+		}
+	}
+
+	if len(unused) > 0 {
+		c.logger.Debug("unused ACL token GC found eligible tokens", "num", len(unused))
+	}
+	return unused, nil
+}
+
 // rootKeyRotateOrGC is used to rotate or garbage collect root keys
 func (c *CoreScheduler) rootKeyRotateOrGC(eval *structs.Evaluation) error {
 
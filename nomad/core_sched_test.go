@@ -1789,6 +1789,60 @@ func TestCoreScheduler_DeploymentGC(t *testing.T) {
 	assert.NotNil(out3, "Terminal Deployment With Allocs")
 }
 
+func TestCoreScheduler_ExecSessionGC(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// COMPAT Remove in 0.6: Reset the FSM time table since we reconcile which sets index 0
+	s1.fsm.timetable.table = make([]TimeTableEntry, 1, 10)
+
+	// Insert an ended, a running, and a recently ended session
+	store := s1.fsm.State()
+	s1Ended, s2Running, s3Recent := mock.ExecSession(), mock.ExecSession(), mock.ExecSession()
+	s1Ended.EndedAt = time.Now().UTC()
+	s3Recent.EndedAt = time.Now().UTC()
+	must.NoError(t, store.UpsertExecSession(structs.MsgTypeTestSetup, 1000, s1Ended))
+	must.NoError(t, store.UpsertExecSession(structs.MsgTypeTestSetup, 1001, s2Running))
+	must.NoError(t, store.UpsertExecSession(structs.MsgTypeTestSetup, 3000, s3Recent))
+
+	// Update the time tables to make this work
+	tt := s1.fsm.TimeTable()
+	tt.Witness(2000, time.Now().UTC().Add(-1*s1.config.ExecSessionGCThreshold))
+
+	snap, err := store.Snapshot()
+	must.NoError(t, err)
+	core := NewCoreScheduler(s1, snap)
+
+	gc := s1.coreJobEval(structs.CoreJobExecSessionGC, 2000)
+	must.NoError(t, core.Process(gc))
+
+	out, err := store.ExecSessionByID(nil, s1Ended.ID)
+	must.NoError(t, err)
+	must.Nil(t, out)
+	out, err = store.ExecSessionByID(nil, s2Running.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	out, err = store.ExecSessionByID(nil, s3Recent.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+
+	// Forcing the GC collects recently ended sessions
+	snap, err = store.Snapshot()
+	must.NoError(t, err)
+	core = NewCoreScheduler(s1, snap)
+	must.NoError(t, core.Process(s1.coreJobEval(structs.CoreJobForceGC, 4000)))
+
+	out, err = store.ExecSessionByID(nil, s3Recent.ID)
+	must.NoError(t, err)
+	must.Nil(t, out)
+	out, err = store.ExecSessionByID(nil, s2Running.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+}
+
 func TestCoreScheduler_DeploymentGC_Force(t *testing.T) {
 	ci.Parallel(t)
 	for _, withAcl := range []bool{false, true} {
@@ -2745,6 +2799,67 @@ func TestCoreScheduler_ExpiredACLTokenGC(t *testing.T) {
 	require.ElementsMatch(t, []*structs.ACLToken{rootACLToken, unexpiredGlobal, unexpiredLocal}, tokens)
 }
 
+func TestCoreScheduler_UnusedACLTokenGC(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerShutdown := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+		c.ACLTokenUnusedTTL = 24 * time.Hour
+	})
+	defer testServerShutdown()
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	now := time.Now().UTC()
+
+	// Local client tokens which went unused for longer than the TTL are
+	// collected. Recently created or used tokens, global tokens and
+	// management tokens are kept.
+	unusedLocal := mock.ACLToken()
+	unusedLocal.CreateTime = now.Add(-48 * time.Hour)
+
+	usedLocal := mock.ACLToken()
+	usedLocal.CreateTime = now.Add(-48 * time.Hour)
+
+	newLocal := mock.ACLToken()
+
+	unusedGlobal := mock.ACLToken()
+	unusedGlobal.Global = true
+	unusedGlobal.CreateTime = now.Add(-48 * time.Hour)
+
+	unusedManagement := mock.ACLManagementToken()
+	unusedManagement.CreateTime = now.Add(-48 * time.Hour)
+
+	must.NoError(t, testServer.State().UpsertACLTokens(structs.MsgTypeTestSetup, 10, []*structs.ACLToken{
+		unusedLocal, usedLocal, newLocal, unusedGlobal, unusedManagement,
+	}))
+	must.NoError(t, testServer.State().UpsertACLTokenUsage(structs.MsgTypeTestSetup, 11, []*structs.ACLTokenUsage{
+		{AccessorID: usedLocal.AccessorID, LastUsedTime: now.Add(-time.Hour)},
+	}))
+
+	snap, err := testServer.State().Snapshot()
+	must.NoError(t, err)
+	coreScheduler := NewCoreScheduler(testServer, snap)
+
+	index, err := testServer.State().LatestIndex()
+	must.NoError(t, err)
+	index++
+
+	localGCEval := testServer.coreJobEval(structs.CoreJobLocalTokenExpiredGC, index)
+	must.NoError(t, coreScheduler.Process(localGCEval))
+
+	iter, err := testServer.State().ACLTokens(nil, state.SortDefault)
+	must.NoError(t, err)
+
+	var accessors []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		accessors = append(accessors, raw.(*structs.ACLToken).AccessorID)
+	}
+	must.SliceContainsAll(t, []string{
+		rootACLToken.AccessorID, usedLocal.AccessorID, newLocal.AccessorID,
+		unusedGlobal.AccessorID, unusedManagement.AccessorID,
+	}, accessors)
+}
+
 func TestCoreScheduler_ExpiredACLTokenGC_Force(t *testing.T) {
 	ci.Parallel(t)
 
@@ -44,12 +44,11 @@ func (d *Deployment) GetDeployment(args *structs.DeploymentSpecificRequest,
 
 	defer metrics.MeasureSince([]string{"nomad", "deployment", "get_deployment"}, time.Now())
 
-	// Check namespace read-job permissions
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadJob)
+	// Check read-job permissions
 	aclObj, err := d.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if !allowNsOp(aclObj, args.RequestNamespace()) {
+	} else if !aclObj.AllowJobSearch(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -69,8 +68,9 @@ func (d *Deployment) GetDeployment(args *structs.DeploymentSpecificRequest,
 				return err
 			}
 
-			// Re-check namespace in case it differs from request.
-			if out != nil && !allowNsOp(aclObj, out.Namespace) {
+			// Re-check permissions on the deployment's job, as the namespace
+			// may differ from the request.
+			if out != nil && !aclObj.AllowJobOp(out.Namespace, out.JobID, acl.NamespaceCapabilityReadJob) {
 				// hide this deployment, caller is not authorized to view it
 				out = nil
 			}
@@ -131,7 +131,7 @@ func (d *Deployment) Fail(args *structs.DeploymentFailRequest, reply *structs.De
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -178,7 +178,7 @@ func (d *Deployment) Pause(args *structs.DeploymentPauseRequest, reply *structs.
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -229,7 +229,7 @@ func (d *Deployment) Promote(args *structs.DeploymentPromoteRequest, reply *stru
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -276,7 +276,7 @@ func (d *Deployment) Run(args *structs.DeploymentRunRequest, reply *structs.Depl
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -323,7 +323,7 @@ func (d *Deployment) Unblock(args *structs.DeploymentUnblockRequest, reply *stru
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -370,7 +370,7 @@ func (d *Deployment) Cancel(args *structs.DeploymentCancelRequest, reply *struct
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -422,7 +422,7 @@ func (d *Deployment) SetAllocHealth(args *structs.DeploymentAllocHealthRequest,
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -455,11 +455,13 @@ func (d *Deployment) List(args *structs.DeploymentListRequest, reply *structs.De
 		return err
 	}
 
-	if aclObj != nil && !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+	if !aclObj.AllowJobSearch(namespace, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
-	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityReadJob)
+	allow := func(ns string) bool {
+		return aclObj.AllowJobSearch(ns, acl.NamespaceCapabilityReadJob)
+	}
 
 	// Setup the blocking query
 	sort := state.SortOption(args.Reverse)
@@ -508,6 +510,12 @@ func (d *Deployment) List(args *structs.DeploymentListRequest, reply *structs.De
 				paginator.NamespaceFilter{
 					AllowableNamespaces: allowableNamespaces,
 				},
+				paginator.GenericFilter{
+					Allow: func(raw interface{}) (bool, error) {
+						deploy := raw.(*structs.Deployment)
+						return aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilityReadJob), nil
+					},
+				},
 			}
 
 			var deploys []*structs.Deployment
@@ -559,14 +567,13 @@ func (d *Deployment) Allocations(args *structs.DeploymentSpecificRequest, reply
 	}
 	defer metrics.MeasureSince([]string{"nomad", "deployment", "allocations"}, time.Now())
 
-	// Check namespace read-job permissions against the request namespace.
-	// Must re-check against the alloc namespace when they return to ensure
-	// there's no namespace mismatch.
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadJob)
+	// Check read-job permissions against the request namespace. Must
+	// re-check against the alloc job when they return to ensure there's no
+	// namespace or job mismatch.
 	aclObj, err := d.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if !allowNsOp(aclObj, args.RequestNamespace()) {
+	} else if !aclObj.AllowJobSearch(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -581,17 +588,20 @@ func (d *Deployment) Allocations(args *structs.DeploymentSpecificRequest, reply
 				return err
 			}
 
-			// Deployments do not span namespaces so just check the
-			// first allocs namespace.
+			// Deployments do not span jobs so just check the first allocs
+			// job.
 			if len(allocs) > 0 {
-				ns := allocs[0].Namespace
-				if ns != args.RequestNamespace() && !allowNsOp(aclObj, ns) {
+				if !aclObj.AllowJobOp(allocs[0].Namespace, allocs[0].JobID, acl.NamespaceCapabilityReadJob) {
 					return structs.ErrPermissionDenied
 				}
 			}
 
 			stubs := make([]*structs.AllocListStub, 0, len(allocs))
 			for _, alloc := range allocs {
+				if !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID,
+					alloc.TaskGroup, acl.NamespaceCapabilityReadJob) {
+					continue
+				}
 				stubs = append(stubs, alloc.Stub(nil))
 			}
 			reply.Allocations = stubs
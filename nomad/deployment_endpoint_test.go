@@ -1262,6 +1262,56 @@ func TestDeploymentEndpoint_List_ACL(t *testing.T) {
 	}
 }
 
+func TestDeploymentEndpoint_List_JobPolicy_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	allowed := mock.Deployment()
+	allowed.JobID = "payments-api"
+	other := mock.Deployment()
+	must.NoError(t, state.UpsertDeployment(1000, allowed))
+	must.NoError(t, state.UpsertDeployment(1001, other))
+
+	alloc := mock.Alloc()
+	alloc.DeploymentID = other.ID
+	alloc.JobID = other.JobID
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1002,
+		[]*structs.Allocation{alloc}))
+
+	token := mock.CreatePolicyAndToken(t, state, 1003, "test-job-policy", `
+namespace "default" {
+  job "payments-*" {
+    policy = "read"
+  }
+}`)
+
+	get := &structs.DeploymentListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.DeploymentListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Deployment.List", get, &resp))
+	must.Len(t, 1, resp.Deployments)
+	must.Eq(t, allowed.ID, resp.Deployments[0].ID)
+
+	// Listing the allocations of a deployment requires access to its job
+	allocs := &structs.DeploymentSpecificRequest{
+		DeploymentID: other.ID,
+		QueryOptions: get.QueryOptions,
+	}
+	var allocsResp structs.AllocListResponse
+	err := msgpackrpc.CallWithCodec(codec, "Deployment.Allocations", allocs, &allocsResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+}
+
 func TestDeploymentEndpoint_List_Blocking(t *testing.T) {
 	ci.Parallel(t)
 
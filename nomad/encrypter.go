@@ -13,6 +13,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -21,11 +22,17 @@ import (
 	log "github.com/hashicorp/go-hclog"
 	kms "github.com/hashicorp/go-kms-wrapping/v2"
 	"github.com/hashicorp/go-kms-wrapping/v2/aead"
+	"github.com/hashicorp/go-kms-wrapping/wrappers/awskms/v2"
+	"github.com/hashicorp/go-kms-wrapping/wrappers/azurekeyvault/v2"
+	"github.com/hashicorp/go-kms-wrapping/wrappers/gcpckms/v2"
+	"github.com/hashicorp/go-kms-wrapping/wrappers/transit/v2"
+	"github.com/hashicorp/go-multierror"
 	"golang.org/x/time/rate"
 
 	"github.com/open-wander/wander/helper"
 	"github.com/open-wander/wander/helper/crypto"
 	"github.com/open-wander/wander/nomad/structs"
+	"github.com/open-wander/wander/nomad/structs/config"
 )
 
 const nomadKeystoreExtension = ".nks.json"
@@ -40,8 +47,16 @@ var _ claimSigner = &Encrypter{}
 // identities.
 type Encrypter struct {
 	srv          *Server
+	ctx          context.Context
+	logger       log.Logger
 	keystorePath string
 
+	// providerConfigs are the key encryption key providers wrapping the root
+	// keys in the keystore, and kekWrappers the wrappers of the external KMS
+	// providers by provider ID.
+	providerConfigs []*config.KEKProviderConfig
+	kekWrappers     map[string]kms.Wrapper
+
 	keyring map[string]*keyset
 	lock    sync.RWMutex
 }
@@ -56,26 +71,142 @@ type keyset struct {
 // encryption keyring with the keys it finds.
 func NewEncrypter(srv *Server, keystorePath string) (*Encrypter, error) {
 
-	encrypter := &Encrypter{
-		srv:          srv,
-		keystorePath: keystorePath,
-		keyring:      make(map[string]*keyset),
+	encrypter, err := newEncrypter(srv.shutdownCtx, srv.logger,
+		keystorePath, srv.config.KEKProviderConfigs)
+	if err != nil {
+		return nil, err
+	}
+	encrypter.srv = srv
+
+	if len(encrypter.kekWrappers) > 0 {
+		go func() {
+			<-srv.shutdownCtx.Done()
+			encrypter.finalizeKEKWrappers()
+		}()
 	}
 
-	err := encrypter.loadKeystore()
+	err = encrypter.loadKeystore()
 	if err != nil {
+		encrypter.finalizeKEKWrappers()
 		return nil, err
 	}
 	return encrypter, nil
 }
 
+// newEncrypter returns an empty keyring which wraps root keys in the keystore
+// with the given key encryption key providers, or the aead provider if none
+// are given.
+func newEncrypter(ctx context.Context, logger log.Logger, keystorePath string,
+	providers []*config.KEKProviderConfig) (*Encrypter, error) {
+
+	if len(providers) == 0 {
+		providers = []*config.KEKProviderConfig{config.DefaultKEKProviderConfig()}
+	}
+	if err := config.ValidateKEKProviderConfigs(providers); err != nil {
+		return nil, err
+	}
+	if logger == nil {
+		logger = log.NewNullLogger()
+	}
+
+	encrypter := &Encrypter{
+		ctx:             ctx,
+		logger:          logger.Named("keyring"),
+		keystorePath:    keystorePath,
+		providerConfigs: providers,
+		kekWrappers:     make(map[string]kms.Wrapper),
+		keyring:         make(map[string]*keyset),
+	}
+
+	for _, provider := range providers {
+		if provider.Provider == config.KEKProviderAEAD {
+			continue
+		}
+		wrapper, err := newKEKWrapper(ctx, provider)
+		if err != nil {
+			encrypter.finalizeKEKWrappers()
+			return nil, fmt.Errorf("failed to configure keyring %q: %w", provider.ID(), err)
+		}
+		encrypter.kekWrappers[provider.ID()] = wrapper
+	}
+	return encrypter, nil
+}
+
+// newKEKWrapper returns the go-kms-wrapping wrapper of an external KMS
+// provider, configured with the provider's config block.
+func newKEKWrapper(ctx context.Context, provider *config.KEKProviderConfig) (kms.Wrapper, error) {
+	var wrapper kms.Wrapper
+	switch provider.Provider {
+	case config.KEKProviderVaultTransit:
+		wrapper = transit.NewWrapper()
+	case config.KEKProviderAWSKMS:
+		wrapper = awskms.NewWrapper()
+	case config.KEKProviderGCPCloudKMS:
+		wrapper = gcpckms.NewWrapper()
+	case config.KEKProviderAzureKeyVault:
+		wrapper = azurekeyvault.NewWrapper()
+	default:
+		return nil, fmt.Errorf("unsupported keyring provider %q", provider.Provider)
+	}
+
+	_, err := wrapper.SetConfig(ctx, kms.WithConfigMap(provider.Config))
+	if err != nil {
+		return nil, err
+	}
+	return wrapper, nil
+}
+
+// finalizeKEKWrappers releases the resources held by the external KMS
+// wrappers, such as the renewal of their Vault token.
+func (e *Encrypter) finalizeKEKWrappers() {
+	for id, wrapper := range e.kekWrappers {
+		var err error
+		switch w := wrapper.(type) {
+		case kms.InitFinalizer:
+			err = w.Finalize(context.Background())
+		case interface{ Finalize(context.Context) error }:
+			// the transit wrapper doesn't implement kms.InitFinalizer
+			err = w.Finalize(context.Background())
+		}
+		if err != nil {
+			e.logger.Warn("failed to finalize keyring provider", "provider", id, "error", err)
+		}
+	}
+}
+
+// loadKeystore adds the root keys of the keystore to the keyring, and wraps
+// them again with the configured providers.
 func (e *Encrypter) loadKeystore() error {
 
-	if err := os.MkdirAll(e.keystorePath, 0o700); err != nil {
+	keys, err := e.loadKeysFromStore()
+	if err != nil {
 		return err
 	}
 
-	return filepath.Walk(e.keystorePath, func(path string, info fs.FileInfo, err error) error {
+	for _, key := range keys {
+		if err := e.addCipher(key); err != nil {
+			return fmt.Errorf("could not add key %s to keystore: %v", key.Meta.KeyID, err)
+		}
+		if err := e.saveKeyToStore(key); err != nil {
+			return fmt.Errorf("could not rewrap key %s in keystore: %v", key.Meta.KeyID, err)
+		}
+	}
+	return nil
+}
+
+// loadKeysFromStore reads the root keys of the keystore. Each root key is
+// unwrapped by the active provider if possible, or else by any other provider
+// that wrapped it and can unwrap it.
+func (e *Encrypter) loadKeysFromStore() ([]*structs.RootKey, error) {
+
+	if err := os.MkdirAll(e.keystorePath, 0o700); err != nil {
+		return nil, err
+	}
+
+	// key files by provider ID, by key ID
+	files := map[string]map[string]string{}
+
+	err := filepath.Walk(e.keystorePath, func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
 			return fmt.Errorf("could not read path %s from keystore: %v", path, err)
 		}
@@ -86,28 +217,75 @@ func (e *Encrypter) loadKeystore() error {
 		if path != e.keystorePath && info.IsDir() {
 			return filepath.SkipDir
 		}
-		if !strings.HasSuffix(path, nomadKeystoreExtension) {
+		id, providerID, ok := parseKeystoreFilename(filepath.Base(path))
+		if !ok {
 			return nil
 		}
-		id := strings.TrimSuffix(filepath.Base(path), nomadKeystoreExtension)
-		if !helper.IsUUID(id) {
-			return nil
+		if files[id] == nil {
+			files[id] = map[string]string{}
+		}
+		files[id][providerID] = path
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*structs.RootKey, 0, len(files))
+	for id, paths := range files {
+		key, err := e.loadKeyFromProviders(id, paths)
+		if err != nil {
+			return nil, err
 		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// loadKeyFromProviders unwraps the root key with the given ID from one of its
+// key files, trying the active provider first.
+func (e *Encrypter) loadKeyFromProviders(id string, paths map[string]string) (*structs.RootKey, error) {
+	providerIDs := make([]string, 0, len(paths))
+	for providerID := range paths {
+		providerIDs = append(providerIDs, providerID)
+	}
+	activeID := e.activeProvider().ID()
+	slices.SortFunc(providerIDs, func(a, b string) int {
+		switch {
+		case a == activeID:
+			return -1
+		case b == activeID:
+			return 1
+		default:
+			return strings.Compare(a, b)
+		}
+	})
 
+	var mErr *multierror.Error
+	for _, providerID := range providerIDs {
+		path := paths[providerID]
 		key, err := e.loadKeyFromStore(path)
 		if err != nil {
-			return fmt.Errorf("could not load key file %s from keystore: %v", path, err)
+			mErr = multierror.Append(mErr,
+				fmt.Errorf("could not load key file %s from keystore: %v", path, err))
+			continue
 		}
 		if key.Meta.KeyID != id {
-			return fmt.Errorf("root key ID %s must match key file %s", key.Meta.KeyID, path)
+			return nil, fmt.Errorf("root key ID %s must match key file %s", key.Meta.KeyID, path)
 		}
+		return key, nil
+	}
+	return nil, mErr.ErrorOrNil()
+}
 
-		err = e.AddKey(key)
-		if err != nil {
-			return fmt.Errorf("could not add key file %s to keystore: %v", path, err)
+// activeProvider returns the configured provider marked as active.
+func (e *Encrypter) activeProvider() *config.KEKProviderConfig {
+	for _, provider := range e.providerConfigs {
+		if provider.Active {
+			return provider
 		}
-		return nil
-	})
+	}
+	return e.providerConfigs[0]
 }
 
 // Encrypt encrypts the clear data with the cipher for the current
@@ -185,6 +363,10 @@ func (e *Encrypter) SignClaims(claim *structs.IdentityClaims) (string, string, e
 		}
 	}
 
+	if issuer := e.srv.config.OIDCIssuer; issuer != "" {
+		claim.Issuer = issuer
+	}
+
 	token := jwt.NewWithClaims(&jwt.SigningMethodEd25519{}, claim)
 	token.Header[keyIDHeader] = keyset.rootKey.Meta.KeyID
 
@@ -230,9 +412,19 @@ func (e *Encrypter) VerifyClaim(tokenString string) (*structs.IdentityClaims, er
 	return claims, nil
 }
 
-// AddKey stores the key in the keystore and creates a new cipher for it.
+// AddKey stores the key in the keystore and creates a new cipher for it. When
+// the key is new to the keyring, such as after a rotation, the other keys of
+// the keystore are wrapped again with the current key encryption keys.
 func (e *Encrypter) AddKey(rootKey *structs.RootKey) error {
 
+	var isNew bool
+	if rootKey != nil && rootKey.Meta != nil {
+		e.lock.RLock()
+		_, exists := e.keyring[rootKey.Meta.KeyID]
+		e.lock.RUnlock()
+		isNew = !exists
+	}
+
 	// note: we don't lock the keyring here but inside addCipher
 	// instead, so that we're not holding the lock while performing
 	// local disk writes
@@ -242,9 +434,33 @@ func (e *Encrypter) AddKey(rootKey *structs.RootKey) error {
 	if err := e.saveKeyToStore(rootKey); err != nil {
 		return err
 	}
+	if isNew {
+		e.rewrapKeystore(rootKey.Meta.KeyID)
+	}
 	return nil
 }
 
+// rewrapKeystore wraps the root keys of the keyring other than the given one
+// again with the configured providers, so that the keystore follows the
+// rotation of the key encryption keys in the external KMS. Failures are only
+// logged, as the previously wrapped keys remain usable.
+func (e *Encrypter) rewrapKeystore(exceptKeyID string) {
+	e.lock.RLock()
+	rootKeys := make([]*structs.RootKey, 0, len(e.keyring))
+	for keyID, keyset := range e.keyring {
+		if keyID != exceptKeyID {
+			rootKeys = append(rootKeys, keyset.rootKey)
+		}
+	}
+	e.lock.RUnlock()
+
+	for _, rootKey := range rootKeys {
+		if err := e.saveKeyToStore(rootKey); err != nil {
+			e.logger.Warn("failed to rewrap root key", "key", rootKey.Meta.KeyID, "error", err)
+		}
+	}
+}
+
 // addCipher stores the key in the keyring and creates a new cipher for it.
 func (e *Encrypter) addCipher(rootKey *structs.RootKey) error {
 
@@ -291,6 +507,25 @@ func (e *Encrypter) GetKey(keyID string) ([]byte, error) {
 	return keyset.rootKey.Key, nil
 }
 
+// GetPublicKey returns the public key of the key with the given ID, which is
+// used to verify the signature of workload identities.
+func (e *Encrypter) GetPublicKey(keyID string) (*structs.KeyringPublicKey, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	keyset, err := e.keysetByIDLocked(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return &structs.KeyringPublicKey{
+		KeyID:      keyID,
+		PublicKey:  keyset.privateKey.Public().(ed25519.PublicKey),
+		Algorithm:  structs.PubKeyAlgEdDSA,
+		Use:        structs.PubKeyUseSig,
+		CreateTime: keyset.rootKey.Meta.CreateTime,
+	}, nil
+}
+
 // activeKeySetLocked returns the keyset that belongs to the key marked as
 // active in the state store (so that it's consistent with raft). The
 // called must read-lock the keyring
@@ -326,38 +561,92 @@ func (e *Encrypter) RemoveKey(keyID string) error {
 	return nil
 }
 
-// saveKeyToStore serializes a root key to the on-disk keystore.
+// saveKeyToStore serializes a root key to the on-disk keystore, wrapped by
+// each of the configured providers. Key files wrapped by providers which are
+// no longer configured are removed once the key has been saved.
 func (e *Encrypter) saveKeyToStore(rootKey *structs.RootKey) error {
 
-	kek, err := crypto.Bytes(32)
-	if err != nil {
-		return fmt.Errorf("failed to generate key wrapper key: %v", err)
-	}
-	wrapper, err := e.newKMSWrapper(rootKey.Meta.KeyID, kek)
-	if err != nil {
-		return fmt.Errorf("failed to create encryption wrapper: %v", err)
-	}
-	blob, err := wrapper.Encrypt(e.srv.shutdownCtx, rootKey.Key)
-	if err != nil {
-		return fmt.Errorf("failed to encrypt root key: %v", err)
+	providerIDs := make(map[string]struct{}, len(e.providerConfigs))
+	for _, provider := range e.providerConfigs {
+		kekWrapper, err := e.wrapRootKey(rootKey, provider)
+		if err != nil {
+			return fmt.Errorf("failed to wrap root key with keyring %q: %v", provider.ID(), err)
+		}
+
+		buf, err := json.Marshal(kekWrapper)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(e.keystorePath, keystoreFilename(rootKey.Meta.KeyID, provider.ID()))
+		err = os.WriteFile(path, buf, 0o600)
+		if err != nil {
+			return err
+		}
+		providerIDs[provider.ID()] = struct{}{}
 	}
 
+	return e.removeStaleKeyFiles(rootKey.Meta.KeyID, providerIDs)
+}
+
+// wrapRootKey encrypts the root key with the key encryption key of the
+// provider.
+func (e *Encrypter) wrapRootKey(rootKey *structs.RootKey, provider *config.KEKProviderConfig) (*structs.KeyEncryptionKeyWrapper, error) {
+
 	kekWrapper := &structs.KeyEncryptionKeyWrapper{
-		Meta:                       rootKey.Meta,
-		EncryptedDataEncryptionKey: blob.Ciphertext,
-		KeyEncryptionKey:           kek,
+		Meta:       rootKey.Meta,
+		ProviderID: provider.ID(),
+	}
+
+	if provider.Provider == config.KEKProviderAEAD {
+		kek, err := crypto.Bytes(32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate key wrapper key: %v", err)
+		}
+		wrapper, err := e.newKMSWrapper(rootKey.Meta.KeyID, kek)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create encryption wrapper: %v", err)
+		}
+		blob, err := wrapper.Encrypt(e.ctx, rootKey.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt root key: %v", err)
+		}
+		kekWrapper.EncryptedDataEncryptionKey = blob.Ciphertext
+		kekWrapper.KeyEncryptionKey = kek
+		return kekWrapper, nil
 	}
 
-	buf, err := json.Marshal(kekWrapper)
+	wrapper, ok := e.kekWrappers[provider.ID()]
+	if !ok {
+		return nil, fmt.Errorf("keyring %q is not configured", provider.ID())
+	}
+	blob, err := wrapper.Encrypt(e.ctx, rootKey.Key)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to encrypt root key: %v", err)
 	}
+	kekWrapper.WrappedDataEncryptionKey = blob
+	return kekWrapper, nil
+}
 
-	path := filepath.Join(e.keystorePath, rootKey.Meta.KeyID+nomadKeystoreExtension)
-	err = os.WriteFile(path, buf, 0o600)
+// removeStaleKeyFiles removes the key files of the root key which were
+// wrapped by providers other than the given ones.
+func (e *Encrypter) removeStaleKeyFiles(keyID string, providerIDs map[string]struct{}) error {
+	entries, err := os.ReadDir(e.keystorePath)
 	if err != nil {
 		return err
 	}
+	for _, entry := range entries {
+		id, providerID, ok := parseKeystoreFilename(entry.Name())
+		if !ok || id != keyID {
+			continue
+		}
+		if _, ok := providerIDs[providerID]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(e.keystorePath, entry.Name())); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -379,17 +668,35 @@ func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
 		return nil, err
 	}
 
-	// the errors that bubble up from this library can be a bit opaque, so make
-	// sure we wrap them with as much context as possible
-	wrapper, err := e.newKMSWrapper(meta.KeyID, kekWrapper.KeyEncryptionKey)
-	if err != nil {
-		return nil, fmt.Errorf("unable to create key wrapper cipher: %v", err)
+	// root keys wrapped by the aead provider carry their key encryption key,
+	// so they can be unwrapped whether or not the provider is configured
+	if kekWrapper.WrappedDataEncryptionKey == nil {
+		// the errors that bubble up from this library can be a bit opaque,
+		// so make sure we wrap them with as much context as possible
+		wrapper, err := e.newKMSWrapper(meta.KeyID, kekWrapper.KeyEncryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create key wrapper cipher: %v", err)
+		}
+		key, err := wrapper.Decrypt(e.ctx, &kms.BlobInfo{
+			Ciphertext: kekWrapper.EncryptedDataEncryptionKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt wrapped root key: %v", err)
+		}
+		return &structs.RootKey{
+			Meta: meta,
+			Key:  key,
+		}, nil
 	}
-	key, err := wrapper.Decrypt(e.srv.shutdownCtx, &kms.BlobInfo{
-		Ciphertext: kekWrapper.EncryptedDataEncryptionKey,
-	})
+
+	wrapper, ok := e.kekWrappers[kekWrapper.ProviderID]
+	if !ok {
+		return nil, fmt.Errorf("keyring %q is not configured", kekWrapper.ProviderID)
+	}
+	key, err := wrapper.Decrypt(e.ctx, kekWrapper.WrappedDataEncryptionKey)
 	if err != nil {
-		return nil, fmt.Errorf("unable to decrypt wrapped root key: %v", err)
+		return nil, fmt.Errorf("unable to decrypt wrapped root key with keyring %q: %v",
+			kekWrapper.ProviderID, err)
 	}
 
 	return &structs.RootKey{
@@ -398,10 +705,69 @@ func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
 	}, nil
 }
 
+// keystoreFilename returns the name of the key file of the root key wrapped by
+// the provider. Root keys wrapped by the default aead provider keep the name
+// they had before providers were configurable.
+func keystoreFilename(keyID, providerID string) string {
+	if providerID == config.KEKProviderAEAD {
+		return keyID + nomadKeystoreExtension
+	}
+	return keyID + "." + providerID + nomadKeystoreExtension
+}
+
+// parseKeystoreFilename returns the root key ID and provider ID of a key file
+// name, or false if the name isn't the name of a key file.
+func parseKeystoreFilename(name string) (string, string, bool) {
+	if !strings.HasSuffix(name, nomadKeystoreExtension) {
+		return "", "", false
+	}
+	id, providerID, _ := strings.Cut(strings.TrimSuffix(name, nomadKeystoreExtension), ".")
+	if !helper.IsUUID(id) {
+		return "", "", false
+	}
+	if providerID == "" {
+		providerID = config.KEKProviderAEAD
+	}
+	return id, providerID, true
+}
+
+// MigrateKeystore wraps the root keys of the keystore at the given path with
+// the given key encryption key providers, and removes the key files wrapped by
+// providers which are no longer configured. It's used to migrate the keystore
+// of a server which isn't running, and returns the IDs of the migrated keys.
+func MigrateKeystore(ctx context.Context, logger log.Logger, keystorePath string,
+	providers []*config.KEKProviderConfig) ([]string, error) {
+
+	if _, err := os.Stat(keystorePath); err != nil {
+		return nil, fmt.Errorf("could not read keystore: %w", err)
+	}
+
+	encrypter, err := newEncrypter(ctx, logger, keystorePath, providers)
+	if err != nil {
+		return nil, err
+	}
+	defer encrypter.finalizeKEKWrappers()
+
+	keys, err := encrypter.loadKeysFromStore()
+	if err != nil {
+		return nil, err
+	}
+
+	keyIDs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if err := encrypter.saveKeyToStore(key); err != nil {
+			return nil, fmt.Errorf("could not rewrap key %s in keystore: %v", key.Meta.KeyID, err)
+		}
+		keyIDs = append(keyIDs, key.Meta.KeyID)
+	}
+	slices.Sort(keyIDs)
+	return keyIDs, nil
+}
+
 // newKMSWrapper returns a go-kms-wrapping interface the caller can use to
-// encrypt the RootKey with a key encryption key (KEK). This is a bit of
-// security theatre for local on-disk key material, but gives us a shim for
-// external KMS providers in the future.
+// encrypt the RootKey with a key encryption key (KEK) stored next to it in the
+// keystore. Servers which shouldn't keep the KEK on disk configure an external
+// KMS provider instead.
 func (e *Encrypter) newKMSWrapper(keyID string, kek []byte) (kms.Wrapper, error) {
 	wrapper := aead.NewWrapper()
 	wrapper.SetConfig(context.Background(),
@@ -6,11 +6,23 @@ package nomad
 import (
 	"bytes"
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/require"
@@ -18,6 +30,7 @@ import (
 	"github.com/open-wander/wander/ci"
 	"github.com/open-wander/wander/nomad/mock"
 	"github.com/open-wander/wander/nomad/structs"
+	"github.com/open-wander/wander/nomad/structs/config"
 	"github.com/open-wander/wander/testutil"
 )
 
@@ -38,7 +51,7 @@ func TestEncrypter_LoadSave(t *testing.T) {
 	ci.Parallel(t)
 
 	tmpDir := t.TempDir()
-	encrypter, err := NewEncrypter(&Server{shutdownCtx: context.Background()}, tmpDir)
+	encrypter, err := NewEncrypter(&Server{shutdownCtx: context.Background(), config: &Config{}}, tmpDir)
 	require.NoError(t, err)
 
 	algos := []structs.EncryptionAlgorithm{
@@ -387,3 +400,244 @@ func TestEncrypter_SignVerify(t *testing.T) {
 	require.Equal(t, alloc.JobID, got.JobID)
 	require.Equal(t, "web", got.TaskName)
 }
+
+func TestEncrypter_SignClaims_Issuer(t *testing.T) {
+
+	ci.Parallel(t)
+	srv, shutdown := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.OIDCIssuer = "https://nomad.example.com"
+	})
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+
+	alloc := mock.Alloc()
+	claim := alloc.ToTaskIdentityClaims(nil, "web")
+	e := srv.encrypter
+
+	out, keyID, err := e.SignClaims(claim)
+	require.NoError(t, err)
+
+	got, err := e.VerifyClaim(out)
+	require.NoError(t, err)
+	require.Equal(t, "https://nomad.example.com", got.Issuer)
+
+	// The public key verifies the signature of the claim
+	pubKey, err := e.GetPublicKey(keyID)
+	require.NoError(t, err)
+	require.Equal(t, keyID, pubKey.KeyID)
+	_, err = jwt.Parse(out, func(token *jwt.Token) (interface{}, error) {
+		return ed25519.PublicKey(pubKey.PublicKey), nil
+	})
+	require.NoError(t, err)
+
+	_, err = e.GetPublicKey("not-a-key")
+	require.Error(t, err)
+}
+
+// testTransitServer is a stand-in for the Vault Transit secrets engine that
+// implements the encrypt and decrypt endpoints for the "nomad" key of the
+// "transit" mount. Calling rotate adds a new version of the key.
+type testTransitServer struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	versions []cipher.AEAD
+}
+
+func newTestTransitServer(t *testing.T) *testTransitServer {
+	t.Helper()
+
+	srv := &testTransitServer{}
+	srv.rotate(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/transit/encrypt/nomad", func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Plaintext string }
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		plaintext, err := base64.StdEncoding.DecodeString(req.Plaintext)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		srv.lock.Lock()
+		version := len(srv.versions)
+		aead := srv.versions[version-1]
+		srv.lock.Unlock()
+
+		nonce := make([]byte, aead.NonceSize())
+		rand.Read(nonce)
+		ciphertext := fmt.Sprintf("vault:v%d:%s", version,
+			base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)))
+		json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"ciphertext": ciphertext},
+		})
+	})
+	mux.HandleFunc("/v1/transit/decrypt/nomad", func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Ciphertext string }
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var version int
+		var encoded string
+		if _, err := fmt.Sscanf(strings.Replace(req.Ciphertext, ":", " ", 2),
+			"vault v%d %s", &version, &encoded); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		srv.lock.Lock()
+		aead := srv.versions[version-1]
+		srv.lock.Unlock()
+
+		plaintext, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"plaintext": base64.StdEncoding.EncodeToString(plaintext)},
+		})
+	})
+
+	srv.Server = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (s *testTransitServer) rotate(t *testing.T) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	must.NoError(t, err)
+	block, err := aes.NewCipher(key)
+	must.NoError(t, err)
+	aead, err := cipher.NewGCM(block)
+	must.NoError(t, err)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.versions = append(s.versions, aead)
+}
+
+func (s *testTransitServer) providerConfig(active bool) *config.KEKProviderConfig {
+	return &config.KEKProviderConfig{
+		Provider: config.KEKProviderVaultTransit,
+		Active:   active,
+		Config: map[string]string{
+			"address":         s.URL,
+			"token":           "root",
+			"mount_path":      "transit",
+			"key_name":        "nomad",
+			"disable_renewal": "true",
+		},
+	}
+}
+
+func readTestKeyFile(t *testing.T, path string) *structs.KeyEncryptionKeyWrapper {
+	t.Helper()
+
+	raw, err := os.ReadFile(path)
+	must.NoError(t, err)
+	kekWrapper := &structs.KeyEncryptionKeyWrapper{}
+	must.NoError(t, json.Unmarshal(raw, kekWrapper))
+	must.NotNil(t, kekWrapper.WrappedDataEncryptionKey)
+	return kekWrapper
+}
+
+// TestEncrypter_MigrateKeystore exercises migrating a keystore from the
+// default aead provider to an external KMS provider
+func TestEncrypter_MigrateKeystore(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx := context.Background()
+	transitSrv := newTestTransitServer(t)
+	tmpDir := t.TempDir()
+
+	// Save a key with the default provider, which stores the KEK on disk
+	encrypter, err := newEncrypter(ctx, nil, tmpDir, nil)
+	must.NoError(t, err)
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	must.NoError(t, err)
+	must.NoError(t, encrypter.saveKeyToStore(key))
+	must.FileExists(t, filepath.Join(tmpDir, key.Meta.KeyID+".nks.json"))
+
+	// Migrate the keystore to the transit provider
+	keyIDs, err := MigrateKeystore(ctx, nil, tmpDir,
+		[]*config.KEKProviderConfig{transitSrv.providerConfig(true)})
+	must.NoError(t, err)
+	must.Eq(t, []string{key.Meta.KeyID}, keyIDs)
+
+	must.FileNotExists(t, filepath.Join(tmpDir, key.Meta.KeyID+".nks.json"))
+	path := filepath.Join(tmpDir, key.Meta.KeyID+".transit.nks.json")
+	must.FileExists(t, path)
+	kekWrapper := readTestKeyFile(t, path)
+	must.Nil(t, kekWrapper.KeyEncryptionKey)
+	must.StrHasPrefix(t, "vault:v1:", string(kekWrapper.WrappedDataEncryptionKey.Ciphertext))
+
+	// The key can only be loaded with the transit provider
+	encrypter, err = newEncrypter(ctx, nil, tmpDir,
+		[]*config.KEKProviderConfig{transitSrv.providerConfig(true)})
+	must.NoError(t, err)
+	keys, err := encrypter.loadKeysFromStore()
+	must.NoError(t, err)
+	must.Len(t, 1, keys)
+	must.Eq(t, key.Key, keys[0].Key)
+
+	encrypter, err = newEncrypter(ctx, nil, tmpDir, nil)
+	must.NoError(t, err)
+	_, err = encrypter.loadKeysFromStore()
+	must.ErrorContains(t, err, `keyring "transit" is not configured`)
+}
+
+// TestEncrypter_RewrapOnRotation exercises wrapping the keystore again with
+// the current version of the KMS key when a root key is added
+func TestEncrypter_RewrapOnRotation(t *testing.T) {
+	ci.Parallel(t)
+
+	transitSrv := newTestTransitServer(t)
+	tmpDir := t.TempDir()
+
+	encrypter, err := newEncrypter(context.Background(), nil, tmpDir,
+		[]*config.KEKProviderConfig{
+			config.DefaultKEKProviderConfig(),
+			transitSrv.providerConfig(false),
+		})
+	must.NoError(t, err)
+	defer encrypter.finalizeKEKWrappers()
+
+	key1, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	must.NoError(t, err)
+	must.NoError(t, encrypter.AddKey(key1))
+
+	// The key is wrapped by both providers
+	must.FileExists(t, filepath.Join(tmpDir, key1.Meta.KeyID+".nks.json"))
+	path := filepath.Join(tmpDir, key1.Meta.KeyID+".transit.nks.json")
+	kekWrapper := readTestKeyFile(t, path)
+	must.StrHasPrefix(t, "vault:v1:", string(kekWrapper.WrappedDataEncryptionKey.Ciphertext))
+
+	// Adding a new root key rewraps the existing ones with the latest
+	// version of the transit key
+	transitSrv.rotate(t)
+	key2, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	must.NoError(t, err)
+	must.NoError(t, encrypter.AddKey(key2))
+
+	kekWrapper = readTestKeyFile(t, path)
+	must.StrHasPrefix(t, "vault:v2:", string(kekWrapper.WrappedDataEncryptionKey.Ciphertext))
+
+	// Keys wrapped by the inactive provider can still be loaded with it
+	must.NoError(t, os.Remove(filepath.Join(tmpDir, key1.Meta.KeyID+".nks.json")))
+	keys, err := encrypter.loadKeysFromStore()
+	must.NoError(t, err)
+	must.Len(t, 2, keys)
+}
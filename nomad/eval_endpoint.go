@@ -56,11 +56,10 @@ func (e *Eval) GetEval(args *structs.EvalSpecificRequest,
 	defer metrics.MeasureSince([]string{"nomad", "eval", "get_eval"}, time.Now())
 
 	// Check for read-job permissions before performing blocking query.
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadJob)
 	aclObj, err := e.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if !allowNsOp(aclObj, args.RequestNamespace()) {
+	} else if !aclObj.AllowJobSearch(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -78,8 +77,9 @@ func (e *Eval) GetEval(args *structs.EvalSpecificRequest,
 			}
 
 			if eval != nil {
-				// Re-check namespace in case it differs from request.
-				if !allowNsOp(aclObj, eval.Namespace) {
+				// Re-check permissions on the eval's job, as the namespace
+				// may differ from the request.
+				if !aclObj.AllowJobOp(eval.Namespace, eval.JobID, acl.NamespaceCapabilityReadJob) {
 					return structs.ErrPermissionDenied
 				}
 
@@ -692,10 +692,12 @@ func (e *Eval) List(args *structs.EvalListRequest, reply *structs.EvalListRespon
 	if err != nil {
 		return err
 	}
-	if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+	if !aclObj.AllowJobSearch(namespace, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
-	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityReadJob)
+	allow := func(ns string) bool {
+		return aclObj.AllowJobSearch(ns, acl.NamespaceCapabilityReadJob)
+	}
 
 	if args.Filter != "" {
 		// Check for incompatible filtering.
@@ -759,6 +761,12 @@ func (e *Eval) List(args *structs.EvalListRequest, reply *structs.EvalListRespon
 					paginator.NamespaceFilter{
 						AllowableNamespaces: allowableNamespaces,
 					},
+					paginator.GenericFilter{
+						Allow: func(raw interface{}) (bool, error) {
+							eval := raw.(*structs.Evaluation)
+							return aclObj.AllowJobOp(eval.Namespace, eval.JobID, acl.NamespaceCapabilityReadJob), nil
+						},
+					},
 				}
 
 				var evals []*structs.Evaluation
@@ -816,10 +824,12 @@ func (e *Eval) Count(args *structs.EvalCountRequest, reply *structs.EvalCountRes
 	if err != nil {
 		return err
 	}
-	if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+	if !aclObj.AllowJobSearch(namespace, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
-	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityReadJob)
+	allow := func(ns string) bool {
+		return aclObj.AllowJobSearch(ns, acl.NamespaceCapabilityReadJob)
+	}
 
 	var filter *bexpr.Evaluator
 	if args.Filter != "" {
@@ -867,6 +877,9 @@ func (e *Eval) Count(args *structs.EvalCountRequest, reply *structs.EvalCountRes
 				if allowableNamespaces != nil && !allowableNamespaces[eval.Namespace] {
 					return true
 				}
+				if !aclObj.AllowJobOp(eval.Namespace, eval.JobID, acl.NamespaceCapabilityReadJob) {
+					return true
+				}
 				if filter != nil {
 					ok, err := filter.Evaluate(eval)
 					if err != nil {
@@ -916,11 +929,10 @@ func (e *Eval) Allocations(args *structs.EvalSpecificRequest,
 	defer metrics.MeasureSince([]string{"nomad", "eval", "allocations"}, time.Now())
 
 	// Check for read-job permissions
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadJob)
 	aclObj, err := e.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if !allowNsOp(aclObj, args.RequestNamespace()) {
+	} else if !aclObj.AllowJobSearch(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -937,15 +949,18 @@ func (e *Eval) Allocations(args *structs.EvalSpecificRequest,
 
 			// Convert to a stub
 			if len(allocs) > 0 {
-				// Evaluations do not span namespaces so just check the
-				// first allocs namespace.
-				ns := allocs[0].Namespace
-				if ns != args.RequestNamespace() && !allowNsOp(aclObj, ns) {
+				// Evaluations do not span jobs so just check the first
+				// allocs job.
+				if !aclObj.AllowJobOp(allocs[0].Namespace, allocs[0].JobID, acl.NamespaceCapabilityReadJob) {
 					return structs.ErrPermissionDenied
 				}
 
 				reply.Allocations = make([]*structs.AllocListStub, 0, len(allocs))
 				for _, alloc := range allocs {
+					if !aclObj.AllowJobGroupOperation(alloc.Namespace, alloc.JobID,
+						alloc.TaskGroup, acl.NamespaceCapabilityReadJob) {
+						continue
+					}
 					reply.Allocations = append(reply.Allocations, alloc.Stub(nil))
 				}
 			}
@@ -1244,6 +1244,65 @@ func TestEvalEndpoint_List_ACL(t *testing.T) {
 	}
 }
 
+func TestEvalEndpoint_List_JobPolicy_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	allowed := mock.Eval()
+	allowed.JobID = "payments-api"
+	other := mock.Eval()
+	must.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1000,
+		[]*structs.Evaluation{allowed, other}))
+
+	token := mock.CreatePolicyAndToken(t, state, 1001, "test-job-policy", `
+namespace "default" {
+  job "payments-*" {
+    policy = "read"
+  }
+}`)
+
+	get := &structs.EvalListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.EvalListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.List", get, &resp))
+	must.Len(t, 1, resp.Evaluations)
+	must.Eq(t, allowed.ID, resp.Evaluations[0].ID)
+
+	// Counting evaluations is limited to the matching jobs
+	count := &structs.EvalCountRequest{QueryOptions: get.QueryOptions}
+	var countResp structs.EvalCountResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.Count", count, &countResp))
+	must.Eq(t, 1, countResp.Count)
+
+	// Listing the allocations of an evaluation requires access to its job
+	allocs := &structs.EvalSpecificRequest{
+		EvalID:       allowed.ID,
+		QueryOptions: get.QueryOptions,
+	}
+	var allocsResp structs.EvalAllocationsResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.Allocations", allocs, &allocsResp))
+
+	alloc := mock.Alloc()
+	alloc.EvalID = other.ID
+	alloc.JobID = other.JobID
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1002,
+		[]*structs.Allocation{alloc}))
+
+	allocs.EvalID = other.ID
+	err := msgpackrpc.CallWithCodec(codec, "Eval.Allocations", allocs, &allocsResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+}
+
 func TestEvalEndpoint_List_Blocking(t *testing.T) {
 	ci.Parallel(t)
 
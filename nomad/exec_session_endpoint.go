@@ -0,0 +1,434 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/go-msgpack/codec"
+
+	"github.com/open-wander/wander/acl"
+	cstructs "github.com/open-wander/wander/client/structs"
+	"github.com/open-wander/wander/helper/pointer"
+	"github.com/open-wander/wander/nomad/state"
+	"github.com/open-wander/wander/nomad/state/paginator"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+// ExecSession is used to register and query the recorded exec sessions into
+// allocations, and to stream their recordings from the clients storing them.
+type ExecSession struct {
+	srv    *Server
+	ctx    *RPCContext
+	logger hclog.Logger
+}
+
+func NewExecSessionEndpoint(srv *Server, ctx *RPCContext) *ExecSession {
+	return &ExecSession{srv: srv, ctx: ctx, logger: srv.logger.Named("exec_session")}
+}
+
+func (e *ExecSession) register() {
+	e.srv.streamingRpcs.Register("ExecSession.Recording", e.recording)
+}
+
+// Start registers an exec session when it starts, if recording is enabled
+// for the namespace of its allocation. This RPC is only callable by the
+// Nomad node running the allocation, which must refuse the session if the
+// RPC fails.
+func (e *ExecSession) Start(args *structs.ExecSessionStartRequest, reply *structs.ExecSessionStartResponse) error {
+	authErr := e.srv.Authenticate(e.ctx, args)
+
+	// Ensure the connection was initiated by a client if TLS is used.
+	if err := validateTLSCertificateLevel(e.srv, e.ctx, tlsCertificateLevelClient); err != nil {
+		return err
+	}
+	if done, err := e.srv.forward("ExecSession.Start", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("exec_session", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "exec_session", "start"}, time.Now())
+
+	if err := args.Session.Validate(); err != nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+	}
+
+	snap, err := e.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	node, err := e.nodeForSession(snap, args.AuthToken, args.Session)
+	if err != nil {
+		return err
+	}
+
+	ns, err := snap.NamespaceByName(nil, args.Session.Namespace)
+	if err != nil {
+		return err
+	}
+	if ns == nil {
+		return fmt.Errorf("namespace %q not found", args.Session.Namespace)
+	}
+	if ns.ExecRecording == nil || !ns.ExecRecording.Enabled {
+		return nil
+	}
+
+	args.Session.NodeID = node.ID
+	args.Session.EndedAt = time.Time{}
+
+	_, index, err := e.srv.raftApply(structs.ExecSessionStartRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	reply.Record = true
+	reply.Index = index
+	return nil
+}
+
+// End updates a registered exec session when it ends. This RPC is only
+// callable by the Nomad node which registered the session.
+func (e *ExecSession) End(args *structs.ExecSessionEndRequest, reply *structs.GenericResponse) error {
+	authErr := e.srv.Authenticate(e.ctx, args)
+
+	// Ensure the connection was initiated by a client if TLS is used.
+	if err := validateTLSCertificateLevel(e.srv, e.ctx, tlsCertificateLevelClient); err != nil {
+		return err
+	}
+	if done, err := e.srv.forward("ExecSession.End", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("exec_session", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "exec_session", "end"}, time.Now())
+
+	if args.Session == nil || args.Session.ID == "" {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "missing exec session ID")
+	}
+
+	snap, err := e.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	existing, err := snap.ExecSessionByID(nil, args.Session.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return structs.NewErrRPCCodedf(http.StatusNotFound, "exec session %q not found", args.Session.ID)
+	}
+	if _, err := e.nodeForSession(snap, args.AuthToken, existing); err != nil {
+		return err
+	}
+
+	// Only the outcome of the session may be updated.
+	session := existing.Copy()
+	session.EndedAt = args.Session.EndedAt
+	if session.EndedAt.IsZero() {
+		session.EndedAt = time.Now().UTC()
+	}
+	session.ExitCode = args.Session.ExitCode
+	session.Error = args.Session.Error
+	session.RecordingSize = args.Session.RecordingSize
+	args.Session = session
+
+	_, index, err := e.srv.raftApply(structs.ExecSessionEndRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// nodeForSession returns the node authenticated by the secret ID, ensuring
+// it is the node running the allocation of the session.
+func (e *ExecSession) nodeForSession(snap *state.StateSnapshot, secretID string, session *structs.ExecSession) (*structs.Node, error) {
+	node, err := snap.NodeBySecretID(nil, secretID)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, structs.ErrTokenNotFound
+	}
+
+	alloc, err := snap.AllocByID(nil, session.AllocID)
+	if err != nil {
+		return nil, err
+	}
+	if alloc == nil || alloc.NodeID != node.ID || alloc.Namespace != session.Namespace || alloc.JobID != session.JobID {
+		return nil, structs.ErrPermissionDenied
+	}
+	return node, nil
+}
+
+// Reap deletes exec sessions. This RPC is only callable by the servers to
+// garbage collect the sessions.
+func (e *ExecSession) Reap(args *structs.ExecSessionDeleteRequest, reply *structs.GenericResponse) error {
+	authErr := e.srv.Authenticate(e.ctx, args)
+
+	// Ensure the connection was initiated by another server if TLS is used.
+	if err := validateTLSCertificateLevel(e.srv, e.ctx, tlsCertificateLevelServer); err != nil {
+		return err
+	}
+	if done, err := e.srv.forward("ExecSession.Reap", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("exec_session", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "exec_session", "reap"}, time.Now())
+
+	_, index, err := e.srv.raftApply(structs.ExecSessionDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// List is used to list the exec sessions of the namespaces the caller may
+// read the recordings of.
+func (e *ExecSession) List(args *structs.ExecSessionListRequest, reply *structs.ExecSessionListResponse) error {
+	authErr := e.srv.Authenticate(e.ctx, args)
+	if done, err := e.srv.forward("ExecSession.List", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("exec_session", structs.RateMetricList, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "exec_session", "list"}, time.Now())
+
+	namespace := args.RequestNamespace()
+
+	aclObj, err := e.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if aclObj != nil && !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadExecRecording) {
+		return structs.ErrPermissionDenied
+	}
+
+	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityReadExecRecording)
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			allowableNamespaces, err := allowedNSes(aclObj, store, allow)
+			if err != nil {
+				if err == structs.ErrPermissionDenied {
+					reply.Sessions = make([]*structs.ExecSession, 0)
+					return nil
+				}
+				return err
+			}
+
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = store.ExecSessionsByIDPrefix(ws, namespace, prefix)
+			} else if namespace != structs.AllNamespacesSentinel {
+				iter, err = store.ExecSessionsByNamespace(ws, namespace)
+			} else {
+				iter, err = store.ExecSessions(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			tokenizer := paginator.NewStructsTokenizer(iter,
+				paginator.StructsTokenizerOptions{WithID: true})
+			filters := []paginator.Filter{
+				paginator.NamespaceFilter{
+					AllowableNamespaces: allowableNamespaces,
+				},
+			}
+
+			sessions := []*structs.ExecSession{}
+			pnator, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
+				func(raw interface{}) error {
+					sessions = append(sessions, raw.(*structs.ExecSession))
+					return nil
+				})
+			if err != nil {
+				return structs.NewErrRPCCodedf(
+					http.StatusBadRequest, "failed to create result paginator: %v", err)
+			}
+
+			nextToken, err := pnator.Page()
+			if err != nil {
+				return structs.NewErrRPCCodedf(
+					http.StatusBadRequest, "failed to read result page: %v", err)
+			}
+
+			reply.QueryMeta.NextToken = nextToken
+			reply.Sessions = sessions
+
+			index, err := store.Index(state.TableExecSessions)
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			e.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		},
+	}
+
+	return e.srv.blockingRPC(&opts)
+}
+
+// GetSession is used to get a single exec session.
+func (e *ExecSession) GetSession(args *structs.ExecSessionSpecificRequest, reply *structs.SingleExecSessionResponse) error {
+	authErr := e.srv.Authenticate(e.ctx, args)
+	if done, err := e.srv.forward("ExecSession.GetSession", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("exec_session", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "exec_session", "get_session"}, time.Now())
+
+	aclObj, err := e.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			if args.ID == "" {
+				return errors.New("missing exec session ID")
+			}
+
+			out, err := store.ExecSessionByID(ws, args.ID)
+			if err != nil {
+				return err
+			}
+
+			// Hide the sessions of namespaces the caller can't read the
+			// recordings of.
+			if out != nil && aclObj != nil &&
+				!aclObj.AllowNsOp(out.Namespace, acl.NamespaceCapabilityReadExecRecording) {
+				out = nil
+			}
+
+			reply.Session = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				index, err := store.Index(state.TableExecSessions)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+
+			e.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		},
+	}
+
+	return e.srv.blockingRPC(&opts)
+}
+
+// recording is used to stream the recording of an exec session from the
+// client storing it.
+func (e *ExecSession) recording(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	defer metrics.MeasureSince([]string{"nomad", "exec_session", "recording"}, time.Now())
+
+	// Decode the arguments
+	var args cstructs.ExecSessionRecordingRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(500)), encoder)
+		return
+	}
+
+	authErr := e.srv.Authenticate(nil, &args)
+
+	// Check if we need to forward to a different region
+	if r := args.RequestRegion(); r != e.srv.Region() {
+		srv, err := e.srv.findRegionServer(r)
+		if err != nil {
+			handleStreamResultError(err, nil, encoder)
+			return
+		}
+		srvConn, err := e.srv.streamingRpc(srv, "ExecSession.Recording")
+		if err != nil {
+			handleStreamResultError(err, nil, encoder)
+			return
+		}
+		defer srvConn.Close()
+
+		outEncoder := codec.NewEncoder(srvConn, structs.MsgpackHandle)
+		if err := outEncoder.Encode(args); err != nil {
+			handleStreamResultError(err, nil, encoder)
+			return
+		}
+		structs.Bridge(conn, srvConn)
+		return
+	}
+	e.srv.MeasureRPCRate("exec_session", structs.RateMetricRead, &args)
+	if authErr != nil {
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		return
+	}
+
+	// Verify the arguments.
+	if args.SessionID == "" {
+		handleStreamResultError(errors.New("missing SessionID"), pointer.Of(int64(400)), encoder)
+		return
+	}
+
+	snap, err := e.srv.State().Snapshot()
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	session, err := snap.ExecSessionByID(nil, args.SessionID)
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	// Check read-exec-recording permissions, hiding the sessions the caller
+	// can't read.
+	aclObj, err := e.srv.ResolveACL(&args)
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+	if session != nil && aclObj != nil &&
+		!aclObj.AllowNsOp(session.Namespace, acl.NamespaceCapabilityReadExecRecording) {
+		session = nil
+	}
+	if session == nil {
+		err := fmt.Errorf("exec session %q not found", args.SessionID)
+		handleStreamResultError(err, pointer.Of(int64(404)), encoder)
+		return
+	}
+
+	forwardStreamToNode(e.srv, conn, encoder, snap, session.NodeID, "ExecSession.Recording", &args)
+}
@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/open-wander/wander/acl"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
+)
+
+// testExecSessionAlloc upserts a node and an allocation running on it in a
+// namespace with exec recording enabled, and returns a session into it.
+func testExecSessionAlloc(t *testing.T, s *Server) (*structs.Node, *structs.ExecSession) {
+	store := s.fsm.State()
+
+	ns := mock.Namespace()
+	ns.ExecRecording = &structs.NamespaceExecRecordingConfiguration{Enabled: true}
+	must.NoError(t, store.UpsertNamespaces(900, []*structs.Namespace{ns}))
+
+	node := mock.Node()
+	must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 901, node))
+
+	alloc := mock.Alloc()
+	alloc.Namespace = ns.Name
+	alloc.Job.Namespace = ns.Name
+	alloc.NodeID = node.ID
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 902, nil, alloc.Job))
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 903, []*structs.Allocation{alloc}))
+
+	session := mock.ExecSession()
+	session.Namespace = alloc.Namespace
+	session.JobID = alloc.JobID
+	session.AllocID = alloc.ID
+	session.NodeID = ""
+	return node, session
+}
+
+func TestExecSession_StartEnd(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node, session := testExecSessionAlloc(t, s1)
+
+	// Sessions can only be started by the node running the allocation
+	other := mock.Node()
+	must.NoError(t, s1.fsm.State().UpsertNode(structs.MsgTypeTestSetup, 904, other))
+
+	req := &structs.ExecSessionStartRequest{
+		Session: session,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: other.SecretID,
+		},
+	}
+	var resp structs.ExecSessionStartResponse
+	err := msgpackrpc.CallWithCodec(codec, "ExecSession.Start", req, &resp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	req.AuthToken = node.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ExecSession.Start", req, &resp))
+	must.True(t, resp.Record)
+
+	out, err := s1.fsm.State().ExecSessionByID(nil, session.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.Eq(t, node.ID, out.NodeID)
+	must.False(t, out.Ended())
+
+	// Ending the session only updates its outcome
+	ended := session.Copy()
+	ended.Command = []string{"/bin/bash"}
+	ended.EndedAt = time.Now().UTC()
+	ended.ExitCode = 3
+	ended.RecordingSize = 1024
+	endReq := &structs.ExecSessionEndRequest{
+		Session: ended,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: node.SecretID,
+		},
+	}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ExecSession.End", endReq, &structs.GenericResponse{}))
+
+	out, err = s1.fsm.State().ExecSessionByID(nil, session.ID)
+	must.NoError(t, err)
+	must.True(t, out.Ended())
+	must.Eq(t, 3, out.ExitCode)
+	must.Eq(t, int64(1024), out.RecordingSize)
+	must.Eq(t, []string{"/bin/sh"}, out.Command)
+
+	// Unknown sessions can't be ended
+	endReq.Session = mock.ExecSession()
+	err = msgpackrpc.CallWithCodec(codec, "ExecSession.End", endReq, &structs.GenericResponse{})
+	must.ErrorContains(t, err, "not found")
+}
+
+func TestExecSession_Start_RecordingDisabled(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node, session := testExecSessionAlloc(t, s1)
+
+	// Disable recording for the namespace of the session
+	ns, err := s1.fsm.State().NamespaceByName(nil, session.Namespace)
+	must.NoError(t, err)
+	ns = ns.Copy()
+	ns.ExecRecording.Enabled = false
+	must.NoError(t, s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	req := &structs.ExecSessionStartRequest{
+		Session: session,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: node.SecretID,
+		},
+	}
+	var resp structs.ExecSessionStartResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ExecSession.Start", req, &resp))
+	must.False(t, resp.Record)
+
+	out, err := s1.fsm.State().ExecSessionByID(nil, session.ID)
+	must.NoError(t, err)
+	must.Nil(t, out)
+}
+
+func TestExecSession_ListGet_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	_, session := testExecSessionAlloc(t, s1)
+	must.NoError(t, store.UpsertExecSession(structs.MsgTypeTestSetup, 1000, session))
+	other := mock.ExecSession()
+	must.NoError(t, store.UpsertExecSession(structs.MsgTypeTestSetup, 1001, other))
+
+	validToken := mock.CreatePolicyAndToken(t, store, 1002, "test-valid",
+		mock.NamespacePolicy(session.Namespace, "", []string{acl.NamespaceCapabilityReadExecRecording}))
+	writeToken := mock.CreatePolicyAndToken(t, store, 1004, "test-write",
+		mock.NamespacePolicy(session.Namespace, acl.PolicyWrite, nil))
+
+	listReq := &structs.ExecSessionListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.AllNamespacesSentinel,
+		},
+	}
+
+	// Listing requires a token
+	var listResp structs.ExecSessionListResponse
+	err := msgpackrpc.CallWithCodec(codec, "ExecSession.List", listReq, &listResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Only the sessions of readable namespaces are listed
+	listReq.AuthToken = validToken.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ExecSession.List", listReq, &listResp))
+	must.Len(t, 1, listResp.Sessions)
+	must.Eq(t, session.ID, listResp.Sessions[0].ID)
+
+	// The write policy doesn't grant read-exec-recording
+	listReq.AuthToken = writeToken.SecretID
+	listReq.Namespace = session.Namespace
+	err = msgpackrpc.CallWithCodec(codec, "ExecSession.List", listReq, &listResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	listReq.AuthToken = root.SecretID
+	listReq.Namespace = structs.AllNamespacesSentinel
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ExecSession.List", listReq, &listResp))
+	must.Len(t, 2, listResp.Sessions)
+	must.Eq(t, uint64(1001), listResp.Index)
+
+	// Sessions of unreadable namespaces are hidden
+	getReq := &structs.ExecSessionSpecificRequest{
+		ID: other.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: validToken.SecretID,
+		},
+	}
+	var getResp structs.SingleExecSessionResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ExecSession.GetSession", getReq, &getResp))
+	must.Nil(t, getResp.Session)
+
+	getReq.ID = session.ID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ExecSession.GetSession", getReq, &getResp))
+	must.NotNil(t, getResp.Session)
+	must.Eq(t, session.ID, getResp.Session.ID)
+}
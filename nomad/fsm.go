@@ -65,6 +65,8 @@ const (
 	ACLAuthMethodSnapshot                SnapshotType = 26
 	ACLBindingRuleSnapshot               SnapshotType = 27
 	NodePoolSnapshot                     SnapshotType = 28
+	JobDependencyRunSnapshot             SnapshotType = 29
+	ExecSessionSnapshot                  SnapshotType = 30
 
 	// Namespace appliers were moved from enterprise and therefore start at 64
 	NamespaceSnapshot SnapshotType = 64
@@ -314,6 +316,16 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyNamespaceUpsert(buf[1:], log.Index)
 	case structs.NamespaceDeleteRequestType:
 		return n.applyNamespaceDelete(buf[1:], log.Index)
+	case structs.JobDependencyRunUpdateRequestType:
+		return n.applyJobDependencyRunUpdate(msgType, buf[1:], log.Index)
+	case structs.ExecSessionStartRequestType:
+		return n.applyExecSessionStart(msgType, buf[1:], log.Index)
+	case structs.ExecSessionEndRequestType:
+		return n.applyExecSessionEnd(msgType, buf[1:], log.Index)
+	case structs.ExecSessionDeleteRequestType:
+		return n.applyExecSessionDelete(msgType, buf[1:], log.Index)
+	case structs.ACLTokenUsageUpsertRequestType:
+		return n.applyACLTokenUsageUpsert(msgType, buf[1:], log.Index)
 	// COMPAT(1.0): These messages were added and removed during the 1.0-beta
 	// series and should not be immediately reused for other purposes
 	case structs.EventSinkUpsertRequestType,
@@ -585,6 +597,74 @@ func (n *nomadFSM) applyNodePoolDelete(msgType structs.MessageType, buf []byte,
 	return nil
 }
 
+func (n *nomadFSM) applyJobDependencyRunUpdate(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_dependency_run_update"}, time.Now())
+	var req structs.JobDependencyRunUpdateRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertJobDependencyRuns(msgType, index, req.Runs, req.Evals); err != nil {
+		n.logger.Error("UpsertJobDependencyRuns failed", "error", err)
+		return err
+	}
+
+	// Only handle the evals that were stored, since evals of stale runs are
+	// dropped.
+	for _, eval := range req.Evals {
+		stored, err := n.state.EvalByID(nil, eval.ID)
+		if err != nil {
+			n.logger.Error("EvalByID failed", "error", err)
+			return err
+		}
+		n.handleUpsertedEval(stored)
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyExecSessionStart(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_exec_session_start"}, time.Now())
+	var req structs.ExecSessionStartRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertExecSession(msgType, index, req.Session); err != nil {
+		n.logger.Error("UpsertExecSession failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyExecSessionEnd(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_exec_session_end"}, time.Now())
+	var req structs.ExecSessionEndRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertExecSession(msgType, index, req.Session); err != nil {
+		n.logger.Error("UpsertExecSession failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyExecSessionDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_exec_session_delete"}, time.Now())
+	var req structs.ExecSessionDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteExecSessions(msgType, index, req.IDs); err != nil {
+		n.logger.Error("DeleteExecSessions failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (n *nomadFSM) applyUpsertJob(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "register_job"}, time.Now())
 	var req structs.JobRegisterRequest
@@ -663,11 +743,17 @@ func (n *nomadFSM) applyUpsertJob(msgType structs.MessageType, buf []byte, index
 				return err
 			}
 
+			prevLaunch, err := n.state.PeriodicLaunchByID(ws, req.Namespace, parentID)
+			if err != nil {
+				n.logger.Error("PeriodicLaunchByID failed", "error", err)
+				return err
+			}
+
 			launch := &structs.PeriodicLaunch{
 				ID:        parentID,
 				Namespace: req.Namespace,
-				Launch:    t,
 			}
+			launch.RecordLaunch(prevLaunch, req.Job.ID, t)
 			if err := n.state.UpsertPeriodicLaunch(index, launch); err != nil {
 				n.logger.Error("UpsertPeriodicLaunch failed", "error", err)
 				return err
@@ -1242,6 +1328,21 @@ func (n *nomadFSM) applyACLTokenUpsert(msgType structs.MessageType, buf []byte,
 	return nil
 }
 
+// applyACLTokenUsageUpsert is used to record the last use of a set of tokens
+func (n *nomadFSM) applyACLTokenUsageUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_usage_upsert"}, time.Now())
+	var req structs.ACLTokenUsageUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLTokenUsage(msgType, index, req.Usage); err != nil {
+		n.logger.Error("UpsertACLTokenUsage failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 // applyACLTokenDelete is used to delete a set of policies
 func (n *nomadFSM) applyACLTokenDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_delete"}, time.Now())
@@ -1882,6 +1983,30 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 				return err
 			}
 
+		case JobDependencyRunSnapshot:
+			run := new(structs.JobDependencyRun)
+
+			if err := dec.Decode(run); err != nil {
+				return err
+			}
+
+			// Perform the restoration.
+			if err := restore.JobDependencyRunRestore(run); err != nil {
+				return err
+			}
+
+		case ExecSessionSnapshot:
+			session := new(structs.ExecSession)
+
+			if err := dec.Decode(session); err != nil {
+				return err
+			}
+
+			// Perform the restoration.
+			if err := restore.ExecSessionRestore(session); err != nil {
+				return err
+			}
+
 		default:
 			// Check if this is an enterprise only object being restored
 			restorer, ok := n.enterpriseRestorers[snapType]
@@ -2448,6 +2573,14 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistJobDependencyRuns(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistExecSessions(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	return nil
 }
 
@@ -2527,6 +2660,48 @@ func (s *nomadSnapshot) persistNodePools(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistJobDependencyRuns(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all job dependency runs.
+	ws := memdb.NewWatchSet()
+	runs, err := s.snap.JobDependencyRuns(ws)
+	if err != nil {
+		return err
+	}
+
+	// Iterate over all runs and persist them.
+	for raw := runs.Next(); raw != nil; raw = runs.Next() {
+		run := raw.(*structs.JobDependencyRun)
+
+		sink.Write([]byte{byte(JobDependencyRunSnapshot)})
+		if err := encoder.Encode(run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistExecSessions(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all exec sessions.
+	ws := memdb.NewWatchSet()
+	sessions, err := s.snap.ExecSessions(ws)
+	if err != nil {
+		return err
+	}
+
+	// Iterate over all sessions and persist them.
+	for raw := sessions.Next(); raw != nil; raw = sessions.Next() {
+		session := raw.(*structs.ExecSession)
+
+		sink.Write([]byte{byte(ExecSessionSnapshot)})
+		if err := encoder.Encode(session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistJobs(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get all the jobs
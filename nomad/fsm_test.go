@@ -1164,6 +1164,81 @@ func TestFSM_UpdateEval(t *testing.T) {
 	}
 }
 
+func TestFSM_JobDependencyRunUpdate(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+	fsm.evalBroker.SetEnabled(true)
+
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{JobID: "extract"}}
+	must.NoError(t, fsm.State().UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	run, err := fsm.State().JobDependencyRunByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+
+	eval := mock.Eval()
+	eval.Namespace = job.Namespace
+	eval.JobID = job.ID
+	eval.Type = job.Type
+
+	run = run.Copy()
+	run.Status = structs.JobDependencyRunStatusTriggered
+	run.EvalID = eval.ID
+
+	req := structs.JobDependencyRunUpdateRequest{
+		Runs:  []*structs.JobDependencyRun{run},
+		Evals: []*structs.Evaluation{eval},
+	}
+	buf, err := structs.Encode(structs.JobDependencyRunUpdateRequestType, req)
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().JobDependencyRunByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobDependencyRunStatusTriggered, out.Status)
+
+	outEval, err := fsm.State().EvalByID(nil, eval.ID)
+	must.NoError(t, err)
+	must.NotNil(t, outEval)
+
+	// Verify enqueued
+	stats := fsm.evalBroker.Stats()
+	must.Eq(t, 1, stats.TotalReady)
+}
+
+func TestFSM_ExecSession(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	session := mock.ExecSession()
+	buf, err := structs.Encode(structs.ExecSessionStartRequestType, structs.ExecSessionStartRequest{Session: session})
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().ExecSessionByID(nil, session.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.False(t, out.Ended())
+
+	ended := out.Copy()
+	ended.EndedAt = time.Now().UTC()
+	buf, err = structs.Encode(structs.ExecSessionEndRequestType, structs.ExecSessionEndRequest{Session: ended})
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().ExecSessionByID(nil, session.ID)
+	must.NoError(t, err)
+	must.True(t, out.Ended())
+
+	buf, err = structs.Encode(structs.ExecSessionDeleteRequestType, structs.ExecSessionDeleteRequest{IDs: []string{session.ID}})
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().ExecSessionByID(nil, session.ID)
+	must.NoError(t, err)
+	must.Nil(t, out)
+}
+
 func TestFSM_UpdateEval_Blocked(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
@@ -2447,6 +2522,41 @@ func TestFSM_SnapshotRestore_NodePools(t *testing.T) {
 	must.Eq(t, pool, out)
 }
 
+func TestFSM_SnapshotRestore_JobDependencyRuns(t *testing.T) {
+	ci.Parallel(t)
+
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{JobID: "extract"}}
+	state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job)
+	run, _ := state.JobDependencyRunByID(nil, job.Namespace, job.ID)
+	must.NotNil(t, run)
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, _ := state2.JobDependencyRunByID(nil, job.Namespace, job.ID)
+	must.Eq(t, run, out)
+}
+
+func TestFSM_SnapshotRestore_ExecSessions(t *testing.T) {
+	ci.Parallel(t)
+
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	session := mock.ExecSession()
+	must.NoError(t, state.UpsertExecSession(structs.MsgTypeTestSetup, 1000, session))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, _ := state2.ExecSessionByID(nil, session.ID)
+	must.Eq(t, session, out)
+}
+
 func TestFSM_SnapshotRestore_Jobs(t *testing.T) {
 	ci.Parallel(t)
 	// Add some state
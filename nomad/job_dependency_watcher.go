@@ -0,0 +1,277 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/open-wander/wander/helper"
+	"github.com/open-wander/wander/helper/uuid"
+	"github.com/open-wander/wander/nomad/state"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+const (
+	// jobDependencyBatchWindow is how long the watcher waits after a change
+	// before reevaluating dependency runs, so that bursts of allocation
+	// updates are handled together.
+	jobDependencyBatchWindow = 250 * time.Millisecond
+
+	// jobDependencyRetryInterval is how long the watcher waits before retrying
+	// after failing to read state or apply an update.
+	jobDependencyRetryInterval = 5 * time.Second
+)
+
+// watchJobDependencies is a long-lived leader routine that releases jobs
+// whose upstream jobs completed and fails jobs whose upstream jobs failed.
+func (s *Server) watchJobDependencies(stopCh chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		store := s.State()
+		ws := memdb.NewWatchSet()
+		ws.Add(store.AbandonCh())
+
+		wait := time.Duration(0)
+		runs, evals, err := jobDependencyUpdates(ws, store)
+		if err != nil {
+			s.logger.Error("failed to compute job dependency updates", "error", err)
+			wait = jobDependencyRetryInterval
+		} else if len(runs) > 0 {
+			req := structs.JobDependencyRunUpdateRequest{
+				Runs:  runs,
+				Evals: evals,
+			}
+			if _, _, err := s.raftApply(structs.JobDependencyRunUpdateRequestType, &req); err != nil {
+				s.logger.Error("failed to update job dependency runs", "error", err)
+				wait = jobDependencyRetryInterval
+			}
+		}
+
+		if wait > 0 {
+			timer, timerCancel := helper.NewSafeTimer(wait)
+			select {
+			case <-ctx.Done():
+				timerCancel()
+				return
+			case <-timer.C:
+			}
+			timerCancel()
+			continue
+		}
+
+		if err := ws.WatchCtx(ctx); err != nil {
+			return
+		}
+
+		timer, timerCancel := helper.NewSafeTimer(jobDependencyBatchWindow)
+		select {
+		case <-ctx.Done():
+			timerCancel()
+			return
+		case <-timer.C:
+		}
+		timerCancel()
+	}
+}
+
+// jobDependencyUpdates returns the blocked dependency runs whose upstream
+// jobs changed status along with the evaluations of the runs that are
+// triggered. The passed watch set fires when any of the inputs change.
+func jobDependencyUpdates(ws memdb.WatchSet, store *state.StateStore) (
+	[]*structs.JobDependencyRun, []*structs.Evaluation, error) {
+
+	iter, err := store.JobDependencyRuns(ws)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var runs []*structs.JobDependencyRun
+	var evals []*structs.Evaluation
+	now := time.Now().UnixNano()
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		run := raw.(*structs.JobDependencyRun)
+		if run.Terminal() {
+			continue
+		}
+
+		job, err := store.JobByID(ws, run.Namespace, run.JobID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Runs of replaced or stopped job versions are updated by the state
+		// store when the job is registered again.
+		if job == nil || job.Stopped() || job.JobModifyIndex != run.JobModifyIndex {
+			continue
+		}
+
+		updated := run.Copy()
+		changed := false
+		complete := true
+		for _, upstream := range updated.Upstreams {
+			status, err := upstreamJobStatus(ws, store, run.Namespace, upstream.JobID, run.JobModifyIndex)
+			if err != nil {
+				return nil, nil, err
+			}
+			if status != upstream.Status {
+				upstream.Status = status
+				changed = true
+			}
+
+			switch status {
+			case structs.JobDependencyStatusFailed:
+				if updated.Status != structs.JobDependencyRunStatusFailed {
+					updated.Status = structs.JobDependencyRunStatusFailed
+					updated.StatusDescription = fmt.Sprintf("upstream job %q failed", upstream.JobID)
+					changed = true
+				}
+				complete = false
+			case structs.JobDependencyStatusComplete:
+			default:
+				complete = false
+			}
+		}
+
+		if complete {
+			eval := &structs.Evaluation{
+				ID:             uuid.Generate(),
+				Namespace:      job.Namespace,
+				Priority:       job.Priority,
+				Type:           job.Type,
+				TriggeredBy:    structs.EvalTriggerJobRegister,
+				JobID:          job.ID,
+				JobModifyIndex: job.JobModifyIndex,
+				Status:         structs.EvalStatusPending,
+				CreateTime:     now,
+				ModifyTime:     now,
+			}
+			updated.Status = structs.JobDependencyRunStatusTriggered
+			updated.StatusDescription = "All upstream jobs completed"
+			updated.EvalID = eval.ID
+			evals = append(evals, eval)
+			changed = true
+		}
+
+		if changed {
+			runs = append(runs, updated)
+		}
+	}
+
+	return runs, evals, nil
+}
+
+// upstreamJobStatus returns the dependency status of an upstream job. The
+// status of a parameterized or periodic job is derived from its children
+// created after the since index, which is the index at which the downstream
+// job version was registered, so that each run only waits on the children
+// dispatched or launched for it.
+func upstreamJobStatus(ws memdb.WatchSet, store *state.StateStore, namespace, jobID string, since uint64) (string, error) {
+	job, err := store.JobByID(ws, namespace, jobID)
+	if err != nil {
+		return "", err
+	}
+	if job == nil {
+		return structs.JobDependencyStatusPending, nil
+	}
+
+	if !job.IsParameterized() && !job.IsPeriodic() {
+		return singleJobDependencyStatus(ws, store, job)
+	}
+
+	iter, err := store.JobsByIDPrefix(ws, namespace, job.ID)
+	if err != nil {
+		return "", err
+	}
+
+	children := 0
+	result := structs.JobDependencyStatusComplete
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		child := raw.(*structs.Job)
+		if child.ParentID != job.ID || child.CreateIndex <= since {
+			continue
+		}
+		children++
+
+		status, err := singleJobDependencyStatus(ws, store, child)
+		if err != nil {
+			return "", err
+		}
+		switch status {
+		case structs.JobDependencyStatusFailed:
+			return structs.JobDependencyStatusFailed, nil
+		case structs.JobDependencyStatusComplete:
+		default:
+			result = structs.JobDependencyStatusRunning
+		}
+	}
+
+	if children == 0 {
+		return structs.JobDependencyStatusPending, nil
+	}
+	return result, nil
+}
+
+// singleJobDependencyStatus returns the dependency status of a job that is
+// neither parameterized nor periodic. A dead job is complete only if the
+// latest allocation of each of its task groups completed successfully.
+func singleJobDependencyStatus(ws memdb.WatchSet, store *state.StateStore, job *structs.Job) (string, error) {
+	if job.HasDependencies() {
+		run, err := store.JobDependencyRunByID(ws, job.Namespace, job.ID)
+		if err != nil {
+			return "", err
+		}
+		if run != nil && run.JobModifyIndex == job.JobModifyIndex {
+			switch run.Status {
+			case structs.JobDependencyRunStatusFailed:
+				return structs.JobDependencyStatusFailed, nil
+			case structs.JobDependencyRunStatusBlocked:
+				return structs.JobDependencyStatusPending, nil
+			}
+		}
+	}
+
+	if job.Stopped() {
+		return structs.JobDependencyStatusFailed, nil
+	}
+
+	switch job.Status {
+	case structs.JobStatusPending:
+		return structs.JobDependencyStatusPending, nil
+	case structs.JobStatusRunning:
+		return structs.JobDependencyStatusRunning, nil
+	}
+
+	allocs, err := store.AllocsByJob(ws, job.Namespace, job.ID, false)
+	if err != nil {
+		return "", err
+	}
+
+	latest := 0
+	for _, alloc := range allocs {
+		if alloc.Job == nil || alloc.Job.Version != job.Version || alloc.NextAllocation != "" {
+			continue
+		}
+		latest++
+		if alloc.ClientStatus != structs.AllocClientStatusComplete {
+			return structs.JobDependencyStatusFailed, nil
+		}
+	}
+
+	if latest == 0 {
+		return structs.JobDependencyStatusFailed, nil
+	}
+	return structs.JobDependencyStatusComplete, nil
+}
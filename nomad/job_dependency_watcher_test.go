@@ -0,0 +1,209 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/state"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+func TestJobDependencyUpdates(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	index := uint64(1000)
+
+	upsertJob := func(job *structs.Job) {
+		index++
+		must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, nil, job))
+	}
+	upsertAlloc := func(job *structs.Job, clientStatus string) {
+		alloc := mock.BatchAlloc()
+		alloc.Namespace = job.Namespace
+		alloc.JobID = job.ID
+		alloc.Job = job
+		alloc.ClientStatus = clientStatus
+		alloc.DesiredStatus = structs.AllocDesiredStatusRun
+		index++
+		must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, index, []*structs.Allocation{alloc}))
+	}
+	updates := func() ([]*structs.JobDependencyRun, []*structs.Evaluation) {
+		runs, evals, err := jobDependencyUpdates(nil, store)
+		must.NoError(t, err)
+		return runs, evals
+	}
+
+	extract := mock.BatchJob()
+	transform := mock.BatchJob()
+	transform.DependsOn = []*structs.JobDependency{{JobID: extract.ID}}
+	report := mock.BatchJob()
+	report.DependsOn = []*structs.JobDependency{{JobID: transform.ID}}
+	upsertJob(transform)
+	upsertJob(report)
+
+	// Nothing changes while the upstream job does not exist.
+	runs, evals := updates()
+	must.Len(t, 0, runs)
+	must.Len(t, 0, evals)
+
+	// The run tracks the status of running upstream jobs.
+	upsertJob(extract)
+	upsertAlloc(extract, structs.AllocClientStatusRunning)
+	runs, evals = updates()
+	must.Len(t, 1, runs)
+	must.Len(t, 0, evals)
+	must.Eq(t, transform.ID, runs[0].JobID)
+	must.Eq(t, structs.JobDependencyRunStatusBlocked, runs[0].Status)
+	must.Eq(t, structs.JobDependencyStatusRunning, runs[0].Upstreams[0].Status)
+
+	// The run is triggered once the upstream job completes.
+	allocs, err := store.AllocsByJob(nil, extract.Namespace, extract.ID, true)
+	must.NoError(t, err)
+	for _, alloc := range allocs {
+		if alloc.ClientStatus == structs.AllocClientStatusRunning {
+			alloc = alloc.Copy()
+			alloc.ClientStatus = structs.AllocClientStatusComplete
+			index++
+			must.NoError(t, store.UpdateAllocsFromClient(structs.MsgTypeTestSetup, index, []*structs.Allocation{alloc}))
+		}
+	}
+
+	runs, evals = updates()
+	must.Len(t, 1, runs)
+	must.Len(t, 1, evals)
+	must.Eq(t, structs.JobDependencyRunStatusTriggered, runs[0].Status)
+	must.Eq(t, evals[0].ID, runs[0].EvalID)
+	must.Eq(t, transform.ID, evals[0].JobID)
+	must.Eq(t, transform.JobModifyIndex, evals[0].JobModifyIndex)
+	must.Eq(t, structs.EvalTriggerJobRegister, evals[0].TriggeredBy)
+
+	// Store the triggered run as if its evaluation was processed.
+	evals[0].Status = structs.EvalStatusComplete
+	index++
+	must.NoError(t, store.UpsertJobDependencyRuns(structs.MsgTypeTestSetup, index, runs, evals))
+
+	// A failed upstream job fails the jobs depending on it.
+	upsertAlloc(transform, structs.AllocClientStatusFailed)
+	runs, evals = updates()
+	must.Len(t, 1, runs)
+	must.Len(t, 0, evals)
+	must.Eq(t, report.ID, runs[0].JobID)
+	must.Eq(t, structs.JobDependencyRunStatusFailed, runs[0].Status)
+	must.Eq(t, fmt.Sprintf("upstream job %q failed", transform.ID), runs[0].StatusDescription)
+}
+
+func TestJobDependencyUpdates_Parameterized(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+
+	index := uint64(1000)
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, nil, parent))
+
+	dispatch := func(clientStatus string) {
+		child := parent.Copy()
+		child.ID = structs.DispatchedID(parent.ID, "", time.Now())
+		child.ParentID = parent.ID
+		child.Dispatched = true
+		index++
+		must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, nil, child))
+
+		alloc := mock.BatchAlloc()
+		alloc.JobID = child.ID
+		alloc.Job = child
+		alloc.ClientStatus = clientStatus
+		index++
+		must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, index, []*structs.Allocation{alloc}))
+	}
+
+	// A child dispatched before the downstream job was registered belongs to
+	// a previous run.
+	dispatch(structs.AllocClientStatusFailed)
+
+	report := mock.BatchJob()
+	report.DependsOn = []*structs.JobDependency{{JobID: parent.ID}}
+	index++
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, nil, report))
+
+	// The upstream job is pending until a child is dispatched.
+	runs, _, err := jobDependencyUpdates(nil, store)
+	must.NoError(t, err)
+	must.Len(t, 0, runs)
+
+	dispatch(structs.AllocClientStatusComplete)
+
+	// All of the children completed.
+	runs, evals, err := jobDependencyUpdates(nil, store)
+	must.NoError(t, err)
+	must.Len(t, 1, runs)
+	must.Len(t, 1, evals)
+	must.Eq(t, structs.JobDependencyRunStatusTriggered, runs[0].Status)
+	must.Eq(t, structs.JobDependencyStatusComplete, runs[0].Upstreams[0].Status)
+}
+
+func TestJobDependencyWatcher(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	upstream := mock.BatchJob()
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, upstream))
+
+	// Register a job that depends on the upstream job
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{JobID: upstream.ID}}
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	must.Eq(t, "", resp.EvalID)
+
+	// Complete the upstream job
+	alloc := mock.BatchAlloc()
+	alloc.JobID = upstream.ID
+	alloc.Job = upstream
+	alloc.ClientStatus = structs.AllocClientStatusComplete
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+	// The job is evaluated once the upstream job is complete
+	var run *structs.JobDependencyRun
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool {
+			var err error
+			run, err = store.JobDependencyRunByID(nil, job.Namespace, job.ID)
+			must.NoError(t, err)
+			return run != nil && run.Status == structs.JobDependencyRunStatusTriggered
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(50*time.Millisecond),
+	))
+
+	eval, err := store.EvalByID(nil, run.EvalID)
+	must.NoError(t, err)
+	must.NotNil(t, eval)
+	must.Eq(t, job.ID, eval.JobID)
+}
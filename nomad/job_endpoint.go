@@ -73,6 +73,7 @@ func NewJobEndpoints(s *Server, ctx *RPCContext) *Job {
 			jobExposeCheckHook{},
 			jobImpliedConstraints{},
 			jobNodePoolMutatingHook{srv: s},
+			jobVaultHook{srv: s},
 		},
 		validators: []jobValidator{
 			jobConnectHook{},
@@ -136,7 +137,7 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	if err != nil {
 		return err
 	} else if aclObj != nil {
-		if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		if !aclObj.AllowJobOp(args.RequestNamespace(), args.Job.ID, acl.NamespaceCapabilitySubmitJob) {
 			return structs.ErrPermissionDenied
 		}
 
@@ -230,6 +231,11 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 
+	// Ensure the job's dependencies do not form a cycle
+	if err := validateJobDependencyCycle(snap, args.Job); err != nil {
+		return err
+	}
+
 	// Ensure that all scaling policies have an appropriate ID
 	if err := propagateScalingPolicyIDs(existingJob, args.Job); err != nil {
 		return err
@@ -340,8 +346,10 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	// Set the submit time
 	args.Job.SubmitTime = now
 
-	// If the job is periodic or parameterized, we don't create an eval.
-	if !(args.Job.IsPeriodic() || args.Job.IsParameterized()) {
+	// If the job is periodic or parameterized, we don't create an eval. Jobs
+	// with dependencies are evaluated by the leader once their upstream jobs
+	// complete.
+	if !(args.Job.IsPeriodic() || args.Job.IsParameterized() || args.Job.HasDependencies()) {
 
 		// Initially set the eval priority to that of the job priority. If the
 		// user supplied an eval priority override, we subsequently use this.
@@ -502,7 +510,7 @@ func (j *Job) Summary(args *structs.JobSummaryRequest, reply *structs.JobSummary
 	// Check for read-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -566,7 +574,7 @@ func (j *Job) Validate(args *structs.JobValidateRequest, reply *structs.JobValid
 	// Check for read-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.Job.ID, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -607,7 +615,7 @@ func (j *Job) Revert(args *structs.JobRevertRequest, reply *structs.JobRegisterR
 	// Check for submit-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -680,7 +688,7 @@ func (j *Job) Stable(args *structs.JobStabilityRequest, reply *structs.JobStabil
 	// Check for read-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -731,7 +739,7 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 	// Check for submit-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -758,6 +766,8 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 		return fmt.Errorf("can't evaluate periodic job")
 	} else if job.IsParameterized() {
 		return fmt.Errorf("can't evaluate parameterized job")
+	} else if job.HasDependencies() {
+		return fmt.Errorf("can't evaluate job with dependencies")
 	}
 
 	forceRescheduleAllocs := make(map[string]*structs.DesiredTransition)
@@ -832,7 +842,7 @@ func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobD
 	// Check for submit-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -944,7 +954,7 @@ func (j *Job) BatchDeregister(args *structs.JobBatchDeregisterRequest, reply *st
 	// Loop through checking for permissions
 	for jobNS := range args.Jobs {
 		// Check for submit-job permissions
-		if aclObj != nil && !aclObj.AllowNsOp(jobNS.Namespace, acl.NamespaceCapabilitySubmitJob) {
+		if aclObj != nil && !aclObj.AllowJobOp(jobNS.Namespace, jobNS.ID, acl.NamespaceCapabilitySubmitJob) {
 			return structs.ErrPermissionDenied
 		}
 	}
@@ -1028,8 +1038,8 @@ func (j *Job) Scale(args *structs.JobScaleRequest, reply *structs.JobRegisterRes
 	}
 
 	if aclObj != nil {
-		hasScaleJob := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityScaleJob)
-		hasSubmitJob := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilitySubmitJob)
+		hasScaleJob := aclObj.AllowJobOp(namespace, args.JobID, acl.NamespaceCapabilityScaleJob)
+		hasSubmitJob := aclObj.AllowJobOp(namespace, args.JobID, acl.NamespaceCapabilitySubmitJob)
 		if !(hasScaleJob || hasSubmitJob) {
 			return structs.ErrPermissionDenied
 		}
@@ -1214,7 +1224,7 @@ func (j *Job) GetJobSubmission(args *structs.JobSubmissionRequest, reply *struct
 	// Check for read-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -1262,7 +1272,7 @@ func (j *Job) GetJob(args *structs.JobSpecificRequest,
 	// Check for read-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -1313,7 +1323,7 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 	// Check for read-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -1424,10 +1434,12 @@ func (j *Job) List(args *structs.JobListRequest, reply *structs.JobListResponse)
 	if err != nil {
 		return err
 	}
-	if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityListJobs) {
+	if !aclObj.AllowJobSearch(namespace, acl.NamespaceCapabilityListJobs) {
 		return structs.ErrPermissionDenied
 	}
-	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityListJobs)
+	allow := func(ns string) bool {
+		return aclObj.AllowJobSearch(ns, acl.NamespaceCapabilityListJobs)
+	}
 
 	// Setup the blocking query
 	opts := blockingOptions{
@@ -1469,6 +1481,12 @@ func (j *Job) List(args *structs.JobListRequest, reply *structs.JobListResponse)
 					paginator.NamespaceFilter{
 						AllowableNamespaces: allowableNamespaces,
 					},
+					paginator.GenericFilter{
+						Allow: func(raw interface{}) (bool, error) {
+							job := raw.(*structs.Job)
+							return aclObj.AllowJobOp(job.Namespace, job.ID, acl.NamespaceCapabilityListJobs), nil
+						},
+					},
 				}
 
 				var jobs []*structs.JobListStub
@@ -1531,7 +1549,7 @@ func (j *Job) Allocations(args *structs.JobSpecificRequest,
 	// Check for read-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -1591,7 +1609,7 @@ func (j *Job) Evaluations(args *structs.JobSpecificRequest,
 	// Check for read-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -1638,7 +1656,7 @@ func (j *Job) Deployments(args *structs.JobSpecificRequest,
 	// Check for read-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -1685,7 +1703,7 @@ func (j *Job) LatestDeployment(args *structs.JobSpecificRequest,
 	// Check for read-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -1721,6 +1739,51 @@ func (j *Job) LatestDeployment(args *structs.JobSpecificRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// Dependencies is used to retrieve the dependency run of a job
+func (j *Job) Dependencies(args *structs.JobSpecificRequest,
+	reply *structs.JobDependenciesResponse) error {
+	authErr := j.srv.Authenticate(j.ctx, args)
+	if done, err := j.srv.forward("Job.Dependencies", args, args, reply); done {
+		return err
+	}
+	j.srv.MeasureRPCRate("job", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "dependencies"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveACL(args); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			run, err := store.JobDependencyRunByID(ws, args.RequestNamespace(), args.JobID)
+			if err != nil {
+				return err
+			}
+			reply.Run = run
+
+			// Use the last index that affected the dependency runs table
+			index, err := store.Index(state.TableJobDependencyRuns)
+			if err != nil {
+				return err
+			}
+			reply.Index = max(1, index)
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
 // Plan is used to cause a dry-run evaluation of the Job and return the results
 // with a potential diff containing annotations.
 func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse) error {
@@ -1753,7 +1816,7 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
 	} else if aclObj != nil {
-		if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		if !aclObj.AllowJobOp(args.RequestNamespace(), args.Job.ID, acl.NamespaceCapabilitySubmitJob) {
 			return structs.ErrPermissionDenied
 		}
 		// Check if override is set and we do not have permissions
@@ -1904,6 +1967,114 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 	return nil
 }
 
+// Explain runs every candidate node of a job through the placement pipeline
+// and returns the result of each check, explaining why the job's task groups
+// can or can't be placed.
+func (j *Job) Explain(args *structs.JobExplainRequest, reply *structs.JobExplainResponse) error {
+	authErr := j.srv.Authenticate(j.ctx, args)
+	if done, err := j.srv.forward("Job.Explain", args, args, reply); done {
+		return err
+	}
+	j.srv.MeasureRPCRate("job", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "explain"}, time.Now())
+
+	// Check for read-job permissions
+	aclObj, err := j.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.JobID == "" {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "missing job ID")
+	}
+
+	// Acquire a snapshot of the state
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	job, err := snap.JobByID(nil, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return structs.NewErrRPCCoded(http.StatusNotFound, fmt.Sprintf("job %q not found", args.JobID))
+	}
+	if args.TaskGroup != "" && job.LookupTaskGroup(args.TaskGroup) == nil {
+		return structs.NewErrRPCCoded(http.StatusNotFound,
+			fmt.Sprintf("task group %q not found in job %q", args.TaskGroup, args.JobID))
+	}
+
+	taskGroups, err := scheduler.ExplainJob(j.logger, snap, job, args.TaskGroup)
+	if err != nil {
+		return err
+	}
+
+	// The explanation of each node exposes its identity and resources, so it
+	// is only returned to tokens allowed to read nodes
+	if aclObj != nil && !aclObj.AllowNodeRead() {
+		for _, tg := range taskGroups {
+			tg.Nodes = nil
+		}
+	}
+
+	index, err := snap.LatestIndex()
+	if err != nil {
+		return err
+	}
+
+	reply.TaskGroups = taskGroups
+	reply.Index = index
+	j.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// validateJobDependencyCycle ensures that registering the job does not create
+// a cycle between jobs depending on each other.
+func validateJobDependencyCycle(snap *state.StateSnapshot, job *structs.Job) error {
+	if len(job.DependsOn) == 0 {
+		return nil
+	}
+
+	visited := make(map[string]struct{})
+	queue := make([]string, 0, len(job.DependsOn))
+	for _, dep := range job.DependsOn {
+		queue = append(queue, dep.JobID)
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		if id == job.ID {
+			return fmt.Errorf("job dependencies form a cycle through job %q", job.ID)
+		}
+		if _, ok := visited[id]; ok {
+			continue
+		}
+		visited[id] = struct{}{}
+
+		upstream, err := snap.JobByID(nil, job.Namespace, id)
+		if err != nil {
+			return err
+		}
+		if upstream == nil {
+			continue
+		}
+		for _, dep := range upstream.DependsOn {
+			queue = append(queue, dep.JobID)
+		}
+	}
+
+	return nil
+}
+
 // validateJobUpdate ensures updates to a job are valid.
 func validateJobUpdate(old, new *structs.Job) error {
 	// Validate Dispatch not set on new Jobs
@@ -1958,7 +2129,7 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	aclObj, err := j.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityDispatchJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityDispatchJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -2045,6 +2216,7 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	dispatchJob.Status = ""
 	dispatchJob.StatusDescription = ""
 	dispatchJob.DispatchIdempotencyToken = args.IdempotencyToken
+	dispatchJob.DispatchDeadline = args.Deadline
 
 	// Merge in the meta data
 	for k, v := range args.Meta {
@@ -2073,8 +2245,8 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	reply.DispatchedJobID = dispatchJob.ID
 	reply.Index = jobCreateIndex
 
-	// If the job is periodic, we don't create an eval.
-	if !dispatchJob.IsPeriodic() {
+	// If the job is periodic or has dependencies, we don't create an eval.
+	if !(dispatchJob.IsPeriodic() || dispatchJob.HasDependencies()) {
 		// Create a new evaluation
 		now := time.Now().UnixNano()
 		eval := &structs.Evaluation{
@@ -2126,6 +2298,11 @@ func validateDispatchRequest(req *structs.JobDispatchRequest, job *structs.Job)
 		return fmt.Errorf("Payload exceeds maximum size; %d > %d", l, DispatchPayloadSizeLimit)
 	}
 
+	// Check the deadline has not already passed
+	if !req.Deadline.IsZero() && !req.Deadline.After(time.Now()) {
+		return fmt.Errorf("Deadline %v has already passed", req.Deadline.UTC().Format(time.RFC3339))
+	}
+
 	// Check if the metadata is a set
 	keys := make(map[string]struct{}, len(req.Meta))
 	for k := range req.Meta {
@@ -2194,8 +2371,8 @@ func (j *Job) ScaleStatus(args *structs.JobScaleStatusRequest,
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
 	} else if aclObj != nil {
-		hasReadJob := aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob)
-		hasReadJobScaling := aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJobScaling)
+		hasReadJob := aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob)
+		hasReadJobScaling := aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJobScaling)
 		if !(hasReadJob || hasReadJobScaling) {
 			return structs.ErrPermissionDenied
 		}
@@ -2321,7 +2498,7 @@ func (j *Job) GetServiceRegistrations(
 	if err != nil {
 		return err
 	} else if aclObj != nil {
-		if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		if !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
 			return structs.ErrPermissionDenied
 		}
 	}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/open-wander/wander/helper"
 	"github.com/open-wander/wander/nomad/structs"
@@ -14,6 +15,11 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+// vaultIdentityTTL is the lifetime of the workload identities added to tasks
+// to log in to Vault. The identity is only used to log in, so it is renewed
+// well before the Vault token it is exchanged for expires.
+const vaultIdentityTTL = time.Hour
+
 // jobVaultHook is an job registration admission controller for Vault blocks.
 type jobVaultHook struct {
 	srv *Server
@@ -23,6 +29,37 @@ func (jobVaultHook) Name() string {
 	return "vault"
 }
 
+// Mutate adds the workload identity used to log in to Vault to tasks with a
+// Vault block when the servers are configured to use workload identities, and
+// sets the default role of the JWT auth method if the task doesn't specify
+// one.
+func (h jobVaultHook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	vconf := h.srv.config.VaultConfig
+	if !vconf.IsEnabled() || !vconf.UsesIdentity() {
+		return job, nil, nil
+	}
+
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			if task.Vault == nil {
+				continue
+			}
+			if task.Vault.Role == "" {
+				task.Vault.Role = vconf.Role
+			}
+			if task.LookupIdentity(structs.WorkloadIdentityVaultName) == nil {
+				task.Identities = append(task.Identities, &structs.WorkloadIdentity{
+					Name:     structs.WorkloadIdentityVaultName,
+					Audience: []string{structs.WorkloadIdentityVaultAud},
+					TTL:      vaultIdentityTTL,
+				})
+			}
+		}
+	}
+
+	return job, nil, nil
+}
+
 func (h jobVaultHook) Validate(job *structs.Job) ([]error, error) {
 	vaultBlocks := job.Vault()
 	if len(vaultBlocks) == 0 {
@@ -39,6 +76,12 @@ func (h jobVaultHook) Validate(job *structs.Job) ([]error, error) {
 		return nil, nil
 	}
 
+	// Tasks log in to Vault with their workload identity, so the Vault role
+	// rather than the submitter's token determines their policies.
+	if vconf.UsesIdentity() {
+		return nil, nil
+	}
+
 	// At this point the job has a vault block and the server requires
 	// authentication, so check if the user has the right permissions.
 	if job.VaultToken == "" {
@@ -1321,6 +1321,95 @@ func TestJobEndpoint_Register_Periodic(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Register_Dependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	register := func(job *structs.Job) (*structs.JobRegisterResponse, error) {
+		req := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+		return &resp, err
+	}
+
+	// Registering a job with dependencies doesn't create an evaluation
+	extract := mock.BatchJob()
+	transform := mock.BatchJob()
+	transform.DependsOn = []*structs.JobDependency{{JobID: extract.ID}}
+	resp, err := register(transform)
+	must.NoError(t, err)
+	must.Eq(t, "", resp.EvalID)
+
+	run, err := state.JobDependencyRunByID(nil, transform.Namespace, transform.ID)
+	must.NoError(t, err)
+	must.NotNil(t, run)
+	must.Eq(t, resp.JobModifyIndex, run.JobModifyIndex)
+
+	// Registering a job that closes a cycle fails
+	extract.DependsOn = []*structs.JobDependency{{JobID: transform.ID}}
+	_, err = register(extract)
+	must.ErrorContains(t, err, fmt.Sprintf("job dependencies form a cycle through job %q", extract.ID))
+}
+
+func TestJobEndpoint_Dependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{JobID: "extract"}}
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	get := &structs.JobSpecificRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Lookup without a token fails
+	var resp structs.JobDependenciesResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Dependencies", get, &resp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Lookup with a read-job token succeeds
+	validToken := mock.CreatePolicyAndToken(t, state, 1001, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	get.AuthToken = validToken.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Dependencies", get, &resp))
+	must.Eq(t, uint64(1000), resp.Index)
+	must.NotNil(t, resp.Run)
+	must.Eq(t, job.ID, resp.Run.JobID)
+	must.Eq(t, structs.JobDependencyRunStatusBlocked, resp.Run.Status)
+
+	// Lookup of a job without dependencies returns no run
+	get.AuthToken = root.SecretID
+	get.JobID = "missing"
+	resp = structs.JobDependenciesResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Dependencies", get, &resp))
+	must.Nil(t, resp.Run)
+}
+
 func TestJobEndpoint_Register_ParameterizedJob(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1723,6 +1812,70 @@ func TestJobEndpoint_Register_Vault_NoToken(t *testing.T) {
 	}
 }
 
+// TestJobEndpoint_Register_Vault_Identity asserts that jobs using Vault are
+// registered without a Vault token when tasks log in with their workload
+// identity, and that the identity and default role are added to tasks.
+func TestJobEndpoint_Register_Vault_Identity(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Enable vault with workload identities
+	s1.config.VaultConfig.Enabled = pointer.Of(true)
+	s1.config.VaultConfig.AllowUnauthenticated = pointer.Of(false)
+	s1.config.VaultConfig.UseIdentity = pointer.Of(true)
+	s1.config.VaultConfig.Role = "nomad-workloads"
+
+	// Create the register request with a job using Vault without policies
+	// nor a Vault token
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Vault = &structs.Vault{
+		ChangeMode: structs.VaultChangeModeRestart,
+	}
+	job.TaskGroups[0].Tasks = append(job.TaskGroups[0].Tasks, job.TaskGroups[0].Tasks[0].Copy())
+	task2 := job.TaskGroups[0].Tasks[1]
+	task2.Name = "web2"
+	task2.Services = nil
+	task2.Vault.Role = "custom"
+	task2.Identities = []*structs.WorkloadIdentity{{
+		Name:     structs.WorkloadIdentityVaultName,
+		Audience: []string{"vault.example.com"},
+	}}
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	var resp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+
+	task1 := out.TaskGroups[0].Tasks[0]
+	must.Eq(t, "nomad-workloads", task1.Vault.Role)
+	must.Eq(t, []*structs.WorkloadIdentity{{
+		Name:     structs.WorkloadIdentityVaultName,
+		Audience: []string{structs.WorkloadIdentityVaultAud},
+		TTL:      vaultIdentityTTL,
+	}}, task1.Identities)
+
+	// Tasks keep their own role and identity
+	task2 = out.TaskGroups[0].Tasks[1]
+	must.Eq(t, "custom", task2.Vault.Role)
+	must.Len(t, 1, task2.Identities)
+	must.Eq(t, []string{"vault.example.com"}, task2.Identities[0].Audience)
+}
+
 func TestJobEndpoint_Register_Vault_Policies(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3337,6 +3490,41 @@ func TestJobEndpoint_Evaluate_Periodic(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Evaluate_Dependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register a job waiting on an upstream job
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{JobID: "extract"}}
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// Forcing an evaluation would bypass the dependencies
+	reEval := &structs.JobEvaluateRequest{
+		JobID: job.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	err := msgpackrpc.CallWithCodec(codec, "Job.Evaluate", reEval, &resp)
+	must.EqError(t, err, "can't evaluate job with dependencies")
+}
+
 func TestJobEndpoint_Evaluate_ParameterizedJob(t *testing.T) {
 	ci.Parallel(t)
 
@@ -5299,6 +5487,79 @@ func TestJobEndpoint_ListJobs_WithACL(t *testing.T) {
 	require.Equal(job.ID, validResp.Jobs[0].ID)
 }
 
+func TestJobEndpoint_JobPolicy_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	allowed := mock.Job()
+	allowed.ID = "payments-api"
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, allowed))
+
+	other := mock.Job()
+	other.ID = "billing"
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, nil, other))
+
+	token := mock.CreatePolicyAndToken(t, state, 1002, "test-job-policy", `
+namespace "default" {
+  job "payments-*" {
+    capabilities = ["list-jobs", "read-job", "submit-job"]
+  }
+}`)
+
+	// Only the jobs matching the policy are listed
+	listReq := &structs.JobListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var listResp structs.JobListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.List", listReq, &listResp))
+	must.Len(t, 1, listResp.Jobs)
+	must.Eq(t, allowed.ID, listResp.Jobs[0].ID)
+
+	// Reading is only allowed for the jobs matching the policy
+	getReq := &structs.JobSpecificRequest{
+		JobID: allowed.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var getResp structs.SingleJobResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJob", getReq, &getResp))
+	must.Eq(t, allowed.ID, getResp.Job.ID)
+
+	getReq.JobID = other.ID
+	err := msgpackrpc.CallWithCodec(codec, "Job.GetJob", getReq, &getResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Registering is only allowed for the jobs matching the policy
+	regReq := &structs.JobRegisterRequest{
+		Job: allowed.Copy(),
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", regReq, &regResp))
+
+	regReq.Job = other.Copy()
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", regReq, &regResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+}
+
 func TestJobEndpoint_ListJobs_Blocking(t *testing.T) {
 	ci.Parallel(t)
 
@@ -6188,6 +6449,91 @@ func TestJobEndpoint_LatestDeployment_Blocking(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Explain(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Resources.MemoryMB = 100000
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, nil, job))
+
+	req := &structs.JobExplainRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Expect failure for request without a token
+	var resp structs.JobExplainResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Explain", req, &resp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Expect failure for request with a token without read-job
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}))
+	req.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Job.Explain", req, &resp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Expect only the aggregate results with a token with read-job
+	validToken := mock.CreatePolicyAndToken(t, state, 1005, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	req.AuthToken = validToken.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Explain", req, &resp))
+	must.Positive(t, resp.Index)
+
+	tg := resp.TaskGroups[job.TaskGroups[0].Name]
+	must.NotNil(t, tg)
+	must.Eq(t, 1, tg.NodesEvaluated)
+	must.Eq(t, 0, tg.NodesFeasible)
+	must.Eq(t, map[string]int{"resources (memory)": 1}, tg.NodesFailed)
+	must.SliceEmpty(t, tg.Nodes)
+
+	// Expect the explanation of each node with a token with node read access
+	nodeToken := mock.CreatePolicyAndToken(t, state, 1007, "test-node",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob})+
+			mock.NodePolicy(acl.PolicyRead))
+	req.AuthToken = nodeToken.SecretID
+	resp = structs.JobExplainResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Explain", req, &resp))
+
+	tg = resp.TaskGroups[job.TaskGroups[0].Name]
+	must.NotNil(t, tg)
+	must.Eq(t, 1, tg.NodesEvaluated)
+	must.Eq(t, 0, tg.NodesFeasible)
+	must.Len(t, 1, tg.Nodes)
+	must.Eq(t, node.ID, tg.Nodes[0].NodeID)
+	must.True(t, tg.Nodes[0].Closest)
+
+	checks := tg.Nodes[0].Checks
+	last := checks[len(checks)-1]
+	must.Eq(t, structs.ExplainCheckResources, last.Name)
+	must.Eq(t, "memory", last.Reason)
+
+	// Unknown task groups and jobs return an error
+	req.AuthToken = root.SecretID
+	req.TaskGroup = "nope"
+	err = msgpackrpc.CallWithCodec(codec, "Job.Explain", req, &resp)
+	must.ErrorContains(t, err, `task group "nope" not found`)
+
+	req.TaskGroup = ""
+	req.JobID = "nope"
+	err = msgpackrpc.CallWithCodec(codec, "Job.Explain", req, &resp)
+	must.ErrorContains(t, err, `job "nope" not found`)
+}
+
 func TestJobEndpoint_Plan_ACL(t *testing.T) {
 	ci.Parallel(t)
 
@@ -7013,6 +7359,23 @@ func TestJobEndpoint_Dispatch(t *testing.T) {
 			},
 			noEval: true,
 		},
+		{
+			name:             "deadline in the future",
+			parameterizedJob: d1,
+			dispatchReq: &structs.JobDispatchRequest{
+				Deadline: time.Now().Add(time.Hour),
+			},
+			err: false,
+		},
+		{
+			name:             "deadline in the past",
+			parameterizedJob: d1,
+			dispatchReq: &structs.JobDispatchRequest{
+				Deadline: time.Now().Add(-time.Minute),
+			},
+			err:    true,
+			errStr: "has already passed",
+		},
 	}
 
 	for _, tc := range cases {
@@ -7116,6 +7479,9 @@ func TestJobEndpoint_Dispatch(t *testing.T) {
 				if out.ParameterizedJob == nil {
 					t.Fatal("parameter job config should exist")
 				}
+				if !out.DispatchDeadline.Equal(tc.dispatchReq.Deadline) {
+					t.Fatalf("bad deadline: %v", out.DispatchDeadline)
+				}
 
 				// Check that the existing job is returned in the case of a supplied idempotency token
 				if tc.idempotencyToken != "" && tc.existingIdempotentJob != nil {
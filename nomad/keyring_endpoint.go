@@ -360,3 +360,78 @@ func (k *Keyring) Delete(args *structs.KeyringDeleteRootKeyRequest, reply *struc
 	reply.Index = index
 	return nil
 }
+
+// ListPublic lists the public keys used to verify workload identities. It
+// includes inactive keys, which may have signed identities that are still
+// valid, until they are garbage collected. This endpoint is unauthenticated so
+// that third parties can verify workload identities.
+func (k *Keyring) ListPublic(args *structs.GenericRequest, reply *structs.KeyringListPublicResponse) error {
+
+	// JWKS is a public endpoint: intentionally ignore auth errors and only
+	// authenticate to measure rate metrics.
+	k.srv.Authenticate(k.ctx, args)
+	if done, err := k.srv.forward("Keyring.ListPublic", args, args, reply); done {
+		return err
+	}
+	k.srv.MeasureRPCRate("keyring", structs.RateMetricList, args)
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "list_public"}, time.Now())
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+
+			// retrieve all the key metadata
+			snap, err := k.srv.fsm.State().Snapshot()
+			if err != nil {
+				return err
+			}
+			iter, err := snap.RootKeyMetas(ws)
+			if err != nil {
+				return err
+			}
+
+			pubKeys := []*structs.KeyringPublicKey{}
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				keyMeta := raw.(*structs.RootKeyMeta)
+
+				pubKey, err := k.encrypter.GetPublicKey(keyMeta.KeyID)
+				if err != nil {
+					// the key may not have been replicated to this server
+					// yet, in which case it can't have signed any identity
+					// that we could verify either
+					k.logger.Debug("skipping public key", "key_id", keyMeta.KeyID, "error", err)
+					continue
+				}
+				pubKeys = append(pubKeys, pubKey)
+			}
+			reply.PublicKeys = pubKeys
+			reply.RotationThreshold = k.srv.config.RootKeyRotationThreshold
+			return k.srv.replySetIndex(state.TableRootKeyMeta, &reply.QueryMeta)
+		},
+	}
+	return k.srv.blockingRPC(&opts)
+}
+
+// GetConfig returns the OIDC discovery document for workload identities. This
+// endpoint is unauthenticated so that third parties can discover the issuer
+// of workload identities.
+func (k *Keyring) GetConfig(args *structs.GenericRequest, reply *structs.KeyringGetConfigResponse) error {
+
+	// OIDC discovery is a public endpoint: intentionally ignore auth errors
+	// and only authenticate to measure rate metrics.
+	k.srv.Authenticate(k.ctx, args)
+	if done, err := k.srv.forward("Keyring.GetConfig", args, args, reply); done {
+		return err
+	}
+	k.srv.MeasureRPCRate("keyring", structs.RateMetricRead, args)
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "get_config"}, time.Now())
+
+	reply.OIDCDiscovery = k.srv.oidcDisco
+	return nil
+}
@@ -4,14 +4,17 @@
 package nomad
 
 import (
+	"crypto/ed25519"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/nomad/mock"
 	"github.com/open-wander/wander/nomad/structs"
 	"github.com/open-wander/wander/testutil"
 )
@@ -295,3 +298,98 @@ func TestKeyringEndpoint_Rotate(t *testing.T) {
 	gotKey := getResp.Key
 	require.Len(t, gotKey.Key, 32)
 }
+
+// TestKeyringEndpoint_ListPublic asserts the Keyring.ListPublic RPC returns
+// all keys which may be used to verify a workload identity, without
+// requiring an ACL token.
+func TestKeyringEndpoint_ListPublic(t *testing.T) {
+
+	ci.Parallel(t)
+	srv, rootToken, shutdown := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+	codec := rpcClient(t, srv)
+
+	// Sign a claim with the bootstrap key before rotating it
+	token, oldKeyID, err := srv.encrypter.SignClaims(mock.Alloc().ToTaskIdentityClaims(nil, "web"))
+	require.NoError(t, err)
+
+	rotateReq := &structs.KeyringRotateRootKeyRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootToken.SecretID,
+		},
+	}
+	var rotateResp structs.KeyringRotateRootKeyResponse
+	err = msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotateReq, &rotateResp)
+	require.NoError(t, err)
+	newKeyID := rotateResp.Key.KeyID
+
+	listReq := &structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+	var listResp structs.KeyringListPublicResponse
+	err = msgpackrpc.CallWithCodec(codec, "Keyring.ListPublic", listReq, &listResp)
+	require.NoError(t, err)
+	require.Equal(t, rotateResp.Index, listResp.Index)
+	require.Equal(t, srv.config.RootKeyRotationThreshold, listResp.RotationThreshold)
+	require.Len(t, listResp.PublicKeys, 2)
+
+	pubKeys := map[string]*structs.KeyringPublicKey{}
+	for _, pubKey := range listResp.PublicKeys {
+		require.Equal(t, structs.PubKeyAlgEdDSA, pubKey.Algorithm)
+		require.Equal(t, structs.PubKeyUseSig, pubKey.Use)
+		require.Len(t, pubKey.PublicKey, ed25519.PublicKeySize)
+		pubKeys[pubKey.KeyID] = pubKey
+	}
+	require.Contains(t, pubKeys, newKeyID)
+	require.Contains(t, pubKeys, oldKeyID)
+
+	// The inactive key can still verify the identities it signed
+	_, err = jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		return ed25519.PublicKey(pubKeys[oldKeyID].PublicKey), nil
+	})
+	require.NoError(t, err)
+}
+
+// TestKeyringEndpoint_GetConfig asserts the Keyring.GetConfig RPC returns the
+// OIDC discovery document only when an issuer is configured.
+func TestKeyringEndpoint_GetConfig(t *testing.T) {
+
+	ci.Parallel(t)
+	srv, shutdown := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+	codec := rpcClient(t, srv)
+
+	req := &structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+	var resp structs.KeyringGetConfigResponse
+	err := msgpackrpc.CallWithCodec(codec, "Keyring.GetConfig", req, &resp)
+	require.NoError(t, err)
+	require.Nil(t, resp.OIDCDiscovery)
+
+	srv2, shutdown2 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.OIDCIssuer = "https://nomad.example.com/"
+	})
+	defer shutdown2()
+	testutil.WaitForLeader(t, srv2.RPC)
+	codec2 := rpcClient(t, srv2)
+
+	err = msgpackrpc.CallWithCodec(codec2, "Keyring.GetConfig", req, &resp)
+	require.NoError(t, err)
+	require.NotNil(t, resp.OIDCDiscovery)
+	require.Equal(t, "https://nomad.example.com/", resp.OIDCDiscovery.Issuer)
+	require.Equal(t, "https://nomad.example.com/.well-known/jwks.json", resp.OIDCDiscovery.JWKS)
+	require.Equal(t, []string{structs.PubKeyAlgEdDSA}, resp.OIDCDiscovery.IDTokenAlgs)
+}
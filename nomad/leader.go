@@ -379,6 +379,9 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// collection.
 	go s.schedulePeriodic(stopCh)
 
+	// Release jobs whose upstream dependencies completed
+	go s.watchJobDependencies(stopCh)
+
 	// Reap any failed evaluations
 	go s.reapFailedEvaluations(stopCh)
 
@@ -937,6 +940,42 @@ func (s *Server) restorePeriodicDispatcher() error {
 			continue
 		}
 
+		policy := job.Periodic.CatchupPolicy()
+		if policy == structs.PeriodicCatchupNone {
+			logger.Debug("skipping missed launches of periodic job due to catchup policy", "job", job.NamespacedID())
+			continue
+		}
+
+		// Without a window the "last" policy always launches, so avoid
+		// walking every missed launch.
+		var missed []time.Time
+		if policy == structs.PeriodicCatchupAll || job.Periodic.CatchupWindow > 0 {
+			missed, err = job.Periodic.MissedLaunches(launch.Launch, now)
+			if err != nil {
+				logger.Error("failed to determine missed periodic launches for job", "job", job.NamespacedID(), "error", err)
+				continue
+			}
+			if len(missed) == 0 {
+				logger.Debug("skipping missed launches of periodic job outside of catchup window", "job", job.NamespacedID())
+				continue
+			}
+		}
+
+		// Jobs that prohibit overlap can only run one instance at a time, so
+		// only the most recent missed launch is backfilled.
+		if policy == structs.PeriodicCatchupAll && !job.Periodic.ProhibitOverlap {
+			for _, t := range missed {
+				if _, err := s.periodicDispatcher.CatchupEval(job.Namespace, job.ID, t); err != nil {
+					logger.Error("catchup run of periodic job failed", "job", job.NamespacedID(), "launch_time", t, "error", err)
+					return fmt.Errorf("catchup run of periodic job %q failed: %v", job.NamespacedID(), err)
+				}
+			}
+
+			logger.Debug("periodic job missed launches run during leadership establishment",
+				"job", job.NamespacedID(), "launches", len(missed))
+			continue
+		}
+
 		if _, err := s.periodicDispatcher.ForceEval(job.Namespace, job.ID); err != nil {
 			logger.Error("force run of periodic job failed", "job", job.NamespacedID(), "error", err)
 			return fmt.Errorf("force run of periodic job %q failed: %v", job.NamespacedID(), err)
@@ -976,6 +1015,8 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer jobGC.Stop()
 	deploymentGC := time.NewTicker(s.config.DeploymentGCInterval)
 	defer deploymentGC.Stop()
+	execSessionGC := time.NewTicker(s.config.ExecSessionGCInterval)
+	defer execSessionGC.Stop()
 	csiPluginGC := time.NewTicker(s.config.CSIPluginGCInterval)
 	defer csiPluginGC.Stop()
 	csiVolumeClaimGC := time.NewTicker(s.config.CSIVolumeClaimGCInterval)
@@ -1010,6 +1051,10 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := s.getLatestIndex(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobDeploymentGC, index))
 			}
+		case <-execSessionGC.C:
+			if index, ok := s.getLatestIndex(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobExecSessionGC, index))
+			}
 		case <-csiPluginGC.C:
 			if index, ok := s.getLatestIndex(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobCSIPluginGC, index))
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -535,6 +536,100 @@ func TestLeader_PeriodicDispatcher_Restore_Evals(t *testing.T) {
 	must.True(t, md.forceEvalCalled, must.Sprint("failed to force job evaluation"))
 }
 
+type recordingJobEvalDispatcher struct {
+	launched []string
+	JobEvalDispatcher
+}
+
+func (r *recordingJobEvalDispatcher) DispatchJob(job *structs.Job) (*structs.Evaluation, error) {
+	r.launched = append(r.launched, job.ID)
+	return mock.Eval(), nil
+}
+
+func TestLeader_PeriodicDispatcher_Restore_Catchup(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	now := time.Now().Truncate(time.Second)
+	missed := []time.Time{now.Add(-3 * time.Hour), now.Add(-2 * time.Hour), now.Add(-1 * time.Hour)}
+	future := now.Add(time.Hour)
+
+	cases := []struct {
+		name     string
+		catchup  string
+		window   time.Duration
+		overlap  bool
+		expected []time.Time
+		forced   bool
+	}{
+		{name: "none", catchup: structs.PeriodicCatchupNone},
+		{name: "last", catchup: structs.PeriodicCatchupLast, forced: true},
+		{name: "default", forced: true},
+		{name: "last outside window", catchup: structs.PeriodicCatchupLast, window: 30 * time.Minute},
+		{name: "all", catchup: structs.PeriodicCatchupAll, expected: missed},
+		{name: "all within window", catchup: structs.PeriodicCatchupAll, window: 90 * time.Minute, expected: missed[2:]},
+		{name: "all prohibit overlap", catchup: structs.PeriodicCatchupAll, overlap: true, forced: true},
+	}
+
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := testPeriodicJob(append(missed, future)...)
+			job.ID = fmt.Sprintf("catchup-%d", i)
+			job.Periodic.Catchup = tc.catchup
+			job.Periodic.CatchupWindow = tc.window
+			job.Periodic.ProhibitOverlap = tc.overlap
+
+			state := s1.fsm.State()
+			index := 1000 + uint64(i*10)
+			must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, index, nil, job))
+			must.NoError(t, state.UpsertPeriodicLaunch(index+1, &structs.PeriodicLaunch{
+				ID:        job.ID,
+				Namespace: job.Namespace,
+				Launch:    now.Add(-4 * time.Hour),
+			}))
+
+			md := &recordingJobEvalDispatcher{JobEvalDispatcher: s1}
+			s1.periodicDispatcher.SetEnabled(false)
+			s1.periodicDispatcher.dispatcher = md
+			s1.periodicDispatcher.SetEnabled(true)
+
+			must.NoError(t, s1.restorePeriodicDispatcher())
+			must.NoError(t, state.DeleteJob(index+2, job.Namespace, job.ID))
+
+			// Other jobs may remain tracked, so only consider this job's
+			// children.
+			var launched []string
+			for _, id := range md.launched {
+				if strings.HasPrefix(id, job.ID+structs.PeriodicLaunchSuffix) {
+					launched = append(launched, id)
+				}
+			}
+
+			if tc.forced {
+				must.Len(t, 1, launched)
+				launch, err := s1.periodicDispatcher.LaunchTime(launched[0])
+				must.NoError(t, err)
+				must.True(t, launch.After(missed[2]))
+				return
+			}
+
+			expected := []string{}
+			for _, launch := range tc.expected {
+				expected = append(expected, s1.periodicDispatcher.derivedJobID(job, launch))
+			}
+			if len(launched) == 0 {
+				launched = []string{}
+			}
+			must.Eq(t, expected, launched)
+		})
+	}
+}
+
 func TestLeader_PeriodicDispatcher_No_Overlaps_No_Running_Job(t *testing.T) {
 	ci.Parallel(t)
 
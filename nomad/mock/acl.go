@@ -318,6 +318,55 @@ func ACLJWTAuthMethod() *structs.ACLAuthMethod {
 	return &method
 }
 
+func ACLLDAPAuthMethod() *structs.ACLAuthMethod {
+	maxTokenTTL, _ := time.ParseDuration("3600s")
+	method := structs.ACLAuthMethod{
+		Name:          fmt.Sprintf("acl-auth-method-%s", uuid.Short()),
+		Type:          "LDAP",
+		TokenLocality: structs.ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   maxTokenTTL,
+		Default:       false,
+		Config: &structs.ACLAuthMethodConfig{
+			LDAPURLs:          []string{"ldap://ldap.example.com"},
+			LDAPBindDN:        "cn=nomad,ou=people,dc=example,dc=org",
+			LDAPBindPassword:  "password",
+			LDAPUserDN:        "ou=people,dc=example,dc=org",
+			LDAPGroupDN:       "ou=groups,dc=example,dc=org",
+			ClaimMappings:     map[string]string{"username": "username"},
+			ListClaimMappings: map[string]string{"groups": "groups"},
+		},
+		CreateTime:  time.Now().UTC(),
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	method.Canonicalize()
+	method.SetHash()
+	return &method
+}
+
+func ACLTLSCertAuthMethod() *structs.ACLAuthMethod {
+	maxTokenTTL, _ := time.ParseDuration("3600s")
+	method := structs.ACLAuthMethod{
+		Name:          fmt.Sprintf("acl-auth-method-%s", uuid.Short()),
+		Type:          "TLSCert",
+		TokenLocality: structs.ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   maxTokenTTL,
+		Default:       false,
+		Config: &structs.ACLAuthMethodConfig{
+			TLSCertCACerts:        []string{"foo"},
+			TLSCertAllowedDNSSANs: []string{"*.example.com"},
+			ClaimMappings:         map[string]string{"common_name": "common_name"},
+			ListClaimMappings:     map[string]string{"dns_sans": "dns_sans"},
+		},
+		CreateTime:  time.Now().UTC(),
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	method.Canonicalize()
+	method.SetHash()
+	return &method
+}
+
 // SampleJWTokenWithKeys takes a set of claims (can be nil) and optionally
 // a private RSA key that should be used for signing the JWT, and returns:
 // - a JWT signed with a randomly generated RSA key
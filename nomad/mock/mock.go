@@ -262,6 +262,24 @@ func NodePool() *structs.NodePool {
 	return pool
 }
 
+// ExecSession returns a started exec session into a task of a mock
+// allocation.
+func ExecSession() *structs.ExecSession {
+	return &structs.ExecSession{
+		ID:         uuid.Generate(),
+		Namespace:  structs.DefaultNamespace,
+		JobID:      "mock-service",
+		AllocID:    uuid.Generate(),
+		TaskName:   "web",
+		NodeID:     uuid.Generate(),
+		Command:    []string{"/bin/sh"},
+		Tty:        true,
+		AccessorID: uuid.Generate(),
+		TokenName:  "operator",
+		StartedAt:  time.Now().UTC(),
+	}
+}
+
 // ServiceRegistrations generates an array containing two unique service
 // registrations.
 func ServiceRegistrations() []*structs.ServiceRegistration {
@@ -21,6 +21,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/open-wander/wander/acl"
+	"github.com/open-wander/wander/helper/pointer"
 	"github.com/open-wander/wander/helper/uuid"
 	"github.com/open-wander/wander/nomad/state"
 	"github.com/open-wander/wander/nomad/state/paginator"
@@ -2286,3 +2287,132 @@ func (n *Node) EmitEvents(args *structs.EmitNodeEventsRequest, reply *structs.Em
 	reply.Index = index
 	return nil
 }
+
+// EvictAllocs is used by a client running low on resources to migrate some of
+// its allocations to other nodes. The allocations are marked for migration
+// and the scheduler replaces them as it does for drained nodes.
+func (n *Node) EvictAllocs(args *structs.NodeEvictAllocsRequest, reply *structs.GenericResponse) error {
+
+	authErr := n.srv.Authenticate(n.ctx, args)
+
+	// Ensure the connection was initiated by another client if TLS is used.
+	err := validateTLSCertificateLevel(n.srv, n.ctx, tlsCertificateLevelClient)
+	if err != nil {
+		return err
+	}
+	if done, err := n.srv.forward("Node.EvictAllocs", args, args, reply); done {
+		return err
+	}
+	n.srv.MeasureRPCRate("node", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "evict_allocs"}, time.Now())
+
+	// Only the client itself or a management token may evict allocations
+	aclObj, err := n.srv.ResolveClientOrACL(args)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.NodeID == "" {
+		return fmt.Errorf("missing node ID")
+	}
+	if len(args.AllocIDs) == 0 {
+		return fmt.Errorf("must evict at least one allocation")
+	}
+
+	snap, err := n.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Clients may only evict their own allocations
+	if aclObj == nil {
+		caller, err := snap.NodeBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if caller == nil || caller.ID != args.NodeID {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	node, err := snap.NodeByID(nil, args.NodeID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve node %s: %v", args.NodeID, err)
+	}
+	if node == nil {
+		return fmt.Errorf("node %s not found", args.NodeID)
+	}
+	if node.UnresponsiveStatus() {
+		return fmt.Errorf("node %s is not allowed to evict allocs while in status %s", args.NodeID, node.Status)
+	}
+
+	// Mark the allocations for migration and create an evaluation for each
+	// of their jobs
+	jobs := make(map[structs.NamespacedID]*structs.Allocation, len(args.AllocIDs))
+	transitions := make(map[string]*structs.DesiredTransition, len(args.AllocIDs))
+	for _, allocID := range args.AllocIDs {
+		alloc, err := snap.AllocByID(nil, allocID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve alloc %s: %v", allocID, err)
+		}
+		if alloc == nil {
+			return fmt.Errorf("alloc %s not found", allocID)
+		}
+		if alloc.NodeID != args.NodeID {
+			return fmt.Errorf("alloc %s is not running on node %s", allocID, args.NodeID)
+		}
+
+		// Stopped or already migrating allocations need no eviction
+		if alloc.TerminalStatus() || alloc.DesiredTransition.ShouldMigrate() {
+			continue
+		}
+
+		transitions[alloc.ID] = &structs.DesiredTransition{
+			Migrate: pointer.Of(true),
+		}
+		jobs[alloc.JobNamespacedID()] = alloc
+	}
+
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	evals := make([]*structs.Evaluation, 0, len(jobs))
+	now := time.Now().UTC().UnixNano()
+	for _, alloc := range jobs {
+		evals = append(evals, &structs.Evaluation{
+			ID:          uuid.Generate(),
+			Namespace:   alloc.Namespace,
+			Priority:    alloc.Job.Priority,
+			Type:        alloc.Job.Type,
+			TriggeredBy: structs.EvalTriggerNodeEviction,
+			JobID:       alloc.JobID,
+			NodeID:      args.NodeID,
+			Status:      structs.EvalStatusPending,
+			CreateTime:  now,
+			ModifyTime:  now,
+		})
+	}
+
+	req := &structs.AllocUpdateDesiredTransitionRequest{
+		Allocs:       transitions,
+		Evals:        evals,
+		WriteRequest: structs.WriteRequest{Region: args.Region},
+	}
+	_, index, err := n.srv.raftApply(structs.AllocUpdateDesiredTransitionRequestType, req)
+	if err != nil {
+		n.logger.Error("evicting allocs failed", "error", err)
+		return err
+	}
+
+	n.logger.Info("evicting allocs from node", "node_id", args.NodeID,
+		"allocs", len(transitions), "reason", args.Reason)
+
+	reply.Index = index
+	return nil
+}
@@ -4323,6 +4323,89 @@ func TestClientEndpoint_EmitEvents(t *testing.T) {
 	require.False(len(out.Events) < 2)
 }
 
+func TestClientEndpoint_EvictAllocs(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS1()
+	state := s1.fsm.State()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 10, node))
+
+	job := mock.Job()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 11, nil, job))
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = node.ID
+
+	stopped := mock.Alloc()
+	stopped.Job = job
+	stopped.JobID = job.ID
+	stopped.NodeID = node.ID
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+
+	other := mock.Alloc()
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 12,
+		[]*structs.Allocation{alloc, stopped, other}))
+
+	evictAs := func(secretID string, allocIDs ...string) error {
+		req := &structs.NodeEvictAllocsRequest{
+			NodeID:   node.ID,
+			AllocIDs: allocIDs,
+			Reason:   "memory usage of 97% is over the eviction threshold of 95%",
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: secretID,
+			},
+		}
+		var resp structs.GenericResponse
+		return msgpackrpc.CallWithCodec(codec, "Node.EvictAllocs", req, &resp)
+	}
+	evict := func(allocIDs ...string) error {
+		return evictAs(node.SecretID, allocIDs...)
+	}
+
+	// Other nodes can't evict the allocations of the node
+	nodeB := mock.Node()
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 13, nodeB))
+	err := evictAs(nodeB.SecretID, alloc.ID)
+	must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+	must.Error(t, evictAs("", alloc.ID))
+
+	// Allocations of other nodes can't be evicted
+	err = evict(alloc.ID, other.ID)
+	must.ErrorContains(t, err, "is not running on node")
+
+	must.NoError(t, evict(alloc.ID, stopped.ID))
+
+	out, err := state.AllocByID(nil, alloc.ID)
+	must.NoError(t, err)
+	must.True(t, out.DesiredTransition.ShouldMigrate())
+
+	out, err = state.AllocByID(nil, stopped.ID)
+	must.NoError(t, err)
+	must.False(t, out.DesiredTransition.ShouldMigrate())
+
+	evals, err := state.EvalsByJob(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Len(t, 1, evals)
+	must.Eq(t, structs.EvalTriggerNodeEviction, evals[0].TriggeredBy)
+	must.Eq(t, node.ID, evals[0].NodeID)
+
+	// Evicting again is a no-op
+	must.NoError(t, evict(alloc.ID))
+	evals, err = state.EvalsByJob(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Len(t, 1, evals)
+}
+
 func TestClientEndpoint_ShouldCreateNodeEval(t *testing.T) {
 	ci.Parallel(t)
 
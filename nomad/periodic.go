@@ -280,6 +280,27 @@ func (p *PeriodicDispatch) removeLocked(jobID structs.NamespacedID) error {
 // ForceEval causes the periodic job to be evaluated immediately and returns the
 // subsequent eval.
 func (p *PeriodicDispatch) ForceEval(namespace, jobID string) (*structs.Evaluation, error) {
+	job, err := p.trackedJob(namespace, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return p.createEval(job, time.Now().In(job.Periodic.GetLocation()))
+}
+
+// CatchupEval launches the periodic job as of the passed launch time and
+// returns the subsequent eval. It is used to backfill launches that were
+// missed while there was no leader.
+func (p *PeriodicDispatch) CatchupEval(namespace, jobID string, launch time.Time) (*structs.Evaluation, error) {
+	job, err := p.trackedJob(namespace, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return p.createEval(job, launch.In(job.Periodic.GetLocation()))
+}
+
+// trackedJob returns the tracked periodic job or an error if the dispatcher
+// is disabled or the job isn't tracked.
+func (p *PeriodicDispatch) trackedJob(namespace, jobID string) (*structs.Job, error) {
 	p.l.Lock()
 
 	// Do nothing if not enabled
@@ -299,7 +320,7 @@ func (p *PeriodicDispatch) ForceEval(namespace, jobID string) (*structs.Evaluati
 	}
 
 	p.l.Unlock()
-	return p.createEval(job, time.Now().In(job.Periodic.GetLocation()))
+	return job, nil
 }
 
 // shouldRun returns whether the long lived run function should run.
@@ -5,6 +5,7 @@ package nomad
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/armon/go-metrics"
@@ -12,6 +13,7 @@ import (
 	"github.com/hashicorp/go-memdb"
 
 	"github.com/open-wander/wander/acl"
+	"github.com/open-wander/wander/nomad/state"
 	"github.com/open-wander/wander/nomad/structs"
 )
 
@@ -42,7 +44,7 @@ func (p *Periodic) Force(args *structs.PeriodicForceRequest, reply *structs.Peri
 	// Check for write-job permissions
 	if aclObj, err := p.srv.ResolveACL(args); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityDispatchJob) && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityDispatchJob) && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -81,3 +83,114 @@ func (p *Periodic) Force(args *structs.PeriodicForceRequest, reply *structs.Peri
 	reply.Index = eval.CreateIndex
 	return nil
 }
+
+// History is used to list the child launches of a periodic job. The launch
+// history recorded for the job is merged with any children still in state.
+func (p *Periodic) History(args *structs.PeriodicHistoryRequest, reply *structs.PeriodicHistoryResponse) error {
+
+	authErr := p.srv.Authenticate(p.ctx, args)
+	if done, err := p.srv.forward("Periodic.History", args, args, reply); done {
+		return err
+	}
+	p.srv.MeasureRPCRate("periodic", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "periodic", "history"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := p.srv.ResolveACL(args); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.JobID == "" {
+		return fmt.Errorf("missing job ID")
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			namespace := args.RequestNamespace()
+			job, err := state.JobByID(ws, namespace, args.JobID)
+			if err != nil {
+				return err
+			}
+			if job == nil {
+				return fmt.Errorf("job not found")
+			}
+			if !job.IsPeriodic() || job.IsParameterized() {
+				return fmt.Errorf("job %q is not a periodic job", job.ID)
+			}
+
+			reply.Periodic = job.Periodic
+			reply.Launch = time.Time{}
+			reply.Children = nil
+
+			launch, err := state.PeriodicLaunchByID(ws, namespace, job.ID)
+			if err != nil {
+				return err
+			}
+
+			children := make(map[string]*structs.PeriodicChild)
+			if launch != nil {
+				reply.Launch = launch.Launch
+				for _, r := range launch.History {
+					children[r.JobID] = &structs.PeriodicChild{JobID: r.JobID, Launch: r.Launch}
+				}
+			}
+
+			iter, err := state.JobsByIDPrefix(ws, namespace, job.ID+structs.PeriodicLaunchSuffix)
+			if err != nil {
+				return err
+			}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				child := raw.(*structs.Job)
+				if child.ParentID != job.ID {
+					continue
+				}
+				c, ok := children[child.ID]
+				if !ok {
+					t, err := p.srv.periodicDispatcher.LaunchTime(child.ID)
+					if err != nil {
+						continue
+					}
+					c = &structs.PeriodicChild{JobID: child.ID, Launch: t}
+					children[child.ID] = c
+				}
+				c.Status = child.Status
+			}
+
+			reply.Children = make([]*structs.PeriodicChild, 0, len(children))
+			for _, c := range children {
+				reply.Children = append(reply.Children, c)
+			}
+			sort.Slice(reply.Children, func(i, j int) bool {
+				if reply.Children[i].Launch.Equal(reply.Children[j].Launch) {
+					return reply.Children[i].JobID > reply.Children[j].JobID
+				}
+				return reply.Children[i].Launch.After(reply.Children[j].Launch)
+			})
+
+			// Use the last index that affected the jobs or launch tables
+			jobIndex, err := state.Index("jobs")
+			if err != nil {
+				return err
+			}
+			launchIndex, err := state.Index("periodic_launch")
+			if err != nil {
+				return err
+			}
+			reply.Index = max(jobIndex, launchIndex)
+
+			// Set the query response
+			p.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+
+	return p.srv.blockingRPC(&opts)
+}
@@ -5,6 +5,7 @@ package nomad
 
 import (
 	"testing"
+	"time"
 
 	memdb "github.com/hashicorp/go-memdb"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
@@ -13,6 +14,7 @@ import (
 	"github.com/open-wander/wander/nomad/mock"
 	"github.com/open-wander/wander/nomad/structs"
 	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -198,3 +200,69 @@ func TestPeriodicEndpoint_Force_NonPeriodic(t *testing.T) {
 		t.Fatalf("Force on non-periodic job should err")
 	}
 }
+
+func TestPeriodicEndpoint_History(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register a periodic job and launch two children through raft so the
+	// launch history is recorded.
+	job := mock.PeriodicJob()
+	_, _, err := s1.raftApply(structs.JobRegisterRequestType, &structs.JobRegisterRequest{
+		Job:          job,
+		WriteRequest: structs.WriteRequest{Namespace: job.Namespace},
+	})
+	must.NoError(t, err)
+
+	first := time.Unix(1700000000, 0)
+	second := first.Add(time.Hour)
+	var children []*structs.Job
+	for _, launch := range []time.Time{first, second} {
+		child, err := s1.periodicDispatcher.deriveJob(job, launch)
+		must.NoError(t, err)
+		_, _, err = s1.raftApply(structs.JobRegisterRequestType, &structs.JobRegisterRequest{
+			Job:          child,
+			WriteRequest: structs.WriteRequest{Namespace: job.Namespace},
+		})
+		must.NoError(t, err)
+		children = append(children, child)
+	}
+
+	// Garbage collect the first child; it should still be listed.
+	_, _, err = s1.raftApply(structs.JobDeregisterRequestType, &structs.JobDeregisterRequest{
+		JobID:        children[0].ID,
+		Purge:        true,
+		WriteRequest: structs.WriteRequest{Namespace: job.Namespace},
+	})
+	must.NoError(t, err)
+
+	req := &structs.PeriodicHistoryRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.PeriodicHistoryResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Periodic.History", req, &resp))
+	must.NotEq(t, 0, resp.Index)
+	must.True(t, second.Equal(resp.Launch))
+	must.Len(t, 2, resp.Children)
+	must.Eq(t, children[1].ID, resp.Children[0].JobID)
+	must.Eq(t, structs.JobStatusPending, resp.Children[0].Status)
+	must.Eq(t, children[0].ID, resp.Children[1].JobID)
+	must.Eq(t, "", resp.Children[1].Status)
+
+	// Non-periodic jobs are rejected.
+	other := mock.Job()
+	must.NoError(t, s1.fsm.State().UpsertJob(structs.MsgTypeTestSetup, 2000, nil, other))
+	req.JobID = other.ID
+	err = msgpackrpc.CallWithCodec(codec, "Periodic.History", req, &resp)
+	must.ErrorContains(t, err, "not a periodic job")
+}
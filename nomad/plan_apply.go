@@ -413,12 +413,15 @@ func signAllocIdentities(signer claimSigner, job *structs.Job, allocations []*st
 			alloc.SignedIdentities = map[string]string{}
 		}
 		tg := job.LookupTaskGroup(alloc.TaskGroup)
+		now := time.Now()
 		for _, task := range tg.Tasks {
 			// skip tasks that already have an identity
 			if _, ok := alloc.SignedIdentities[task.Name]; ok {
 				continue
 			}
-			claims := alloc.ToTaskIdentityClaims(job, task.Name)
+			// only the default identity is signed with the allocation, the
+			// named identities are signed when requested by the client
+			claims := alloc.ToWorkloadIdentityClaims(job, task.Name, task.Identity, now)
 			token, keyID, err := signer.SignClaims(claims)
 			if err != nil {
 				return err
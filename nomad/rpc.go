@@ -60,6 +60,12 @@ type rpcHandler struct {
 	streamLimiter *connlimit.Limiter
 	streamLimit   int
 
+	// rateLimiter is used to limit the rate of RPC requests per token,
+	// namespace and endpoint.
+	//
+	// nil if rate limiting is disabled
+	rateLimiter *rpcRateLimiter
+
 	logger   log.Logger
 	gologger *golog.Logger
 }
@@ -72,6 +78,8 @@ func newRpcHandler(s *Server) *rpcHandler {
 		connLimit: s.config.RPCMaxConnsPerClient,
 		logger:    logger,
 		gologger:  logger.StandardLoggerIntercept(&log.StandardLoggerOptions{InferLevels: true}),
+
+		rateLimiter: newRPCRateLimiter(s.config.RPCRateLimit),
 	}
 
 	// Setup connection limits
@@ -543,6 +551,11 @@ func (r *rpcHandler) forward(method string, info structs.RPCInfo, args interface
 		return true, fmt.Errorf("missing region for target RPC")
 	}
 
+	// Reject requests exceeding the rate limits before doing any work
+	if err := r.checkRPCRateLimit(method, info, args); err != nil {
+		return true, err
+	}
+
 	// Handle region forwarding
 	if region != r.srv.config.Region {
 		// Mark that we are forwarding the RPC
@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"time"
+
+	"github.com/armon/go-metrics"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/time/rate"
+
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/open-wander/wander/nomad/structs/config"
+)
+
+const (
+	// rpcRateLimiterCacheSize is the number of rate limiters kept. The least
+	// recently used limiters, typically those of tokens which stopped making
+	// requests, are dropped first.
+	rpcRateLimiterCacheSize = 8192
+
+	// The following are the scopes of the RPC rate limits, used as error
+	// messages and metric labels.
+	rpcRateLimitScopeToken     = "token"
+	rpcRateLimitScopeNamespace = "namespace"
+	rpcRateLimitScopeEndpoint  = "endpoint"
+)
+
+// rpcRateLimiter enforces the RPC rate limits of the server configuration
+// with a token bucket for each limited token, namespace and endpoint.
+type rpcRateLimiter struct {
+	config   *config.RPCRateLimitConfig
+	limiters *lru.Cache[string, *rate.Limiter]
+}
+
+// newRPCRateLimiter returns a rate limiter enforcing the limits of conf, or
+// nil if conf is nil.
+func newRPCRateLimiter(conf *config.RPCRateLimitConfig) *rpcRateLimiter {
+	if conf == nil {
+		return nil
+	}
+
+	// The cache size is a constant so creating it can't fail.
+	limiters, _ := lru.New[string, *rate.Limiter](rpcRateLimiterCacheSize)
+	return &rpcRateLimiter{
+		config:   conf,
+		limiters: limiters,
+	}
+}
+
+// rpcRateLimitedRequest describes a request to check against the limits.
+type rpcRateLimitedRequest struct {
+	method    string
+	namespace string
+	identity  string
+	write     bool
+}
+
+// allow reserves the request on each limit applying to it. If a limit is
+// exceeded, the reservations are cancelled and the scope of the exceeded
+// limit is returned.
+func (r *rpcRateLimiter) allow(req *rpcRateLimitedRequest, now time.Time) (string, bool) {
+	kind := structs.RateMetricRead
+	if req.write {
+		kind = structs.RateMetricWrite
+	}
+
+	checks := []struct {
+		scope string
+		name  string
+		limit *config.RPCRateLimit
+	}{
+		{rpcRateLimitScopeToken, req.identity, r.config.Token},
+		{rpcRateLimitScopeNamespace, req.namespace, r.config.NamespaceLimit(req.namespace)},
+		{rpcRateLimitScopeEndpoint, req.method, r.config.EndpointLimit(req.method)},
+	}
+
+	reservations := make([]*rate.Reservation, 0, len(checks))
+	for _, check := range checks {
+		limit, ok := check.limit.Limit(req.write)
+		if !ok || check.name == "" {
+			continue
+		}
+
+		limiter := r.limiter(check.scope+"\x00"+kind+"\x00"+check.name, limit)
+		reservation := limiter.ReserveN(now, 1)
+		if !reservation.OK() || reservation.DelayFrom(now) > 0 {
+			reservation.CancelAt(now)
+			for _, previous := range reservations {
+				previous.CancelAt(now)
+			}
+			return check.scope, false
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	return "", true
+}
+
+// limiter returns the limiter stored under key, creating it if necessary.
+func (r *rpcRateLimiter) limiter(key string, limit int) *rate.Limiter {
+	if limiter, ok := r.limiters.Get(key); ok {
+		return limiter
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(limit), limit)
+	if existing, ok, _ := r.limiters.PeekOrAdd(key, limiter); ok {
+		return existing
+	}
+	return limiter
+}
+
+// checkRPCRateLimit returns an error if the request exceeds one of the RPC
+// rate limits of the server, in which case it must be rejected. Requests
+// made by clients and by the leader itself are never limited, and requests
+// forwarded by another server were already checked by that server.
+func (r *rpcHandler) checkRPCRateLimit(method string, info structs.RPCInfo, args interface{}) error {
+	if r.rateLimiter == nil || info.IsForwarded() {
+		return nil
+	}
+
+	var identity *structs.AuthenticatedIdentity
+	if req, ok := args.(structs.RequestWithIdentity); ok {
+		identity = req.GetIdentity()
+	}
+	if identity != nil && (identity.ClientID != "" || identity.ACLToken == structs.LeaderACLToken) {
+		return nil
+	}
+
+	req := &rpcRateLimitedRequest{
+		method: method,
+		write:  !info.IsRead(),
+	}
+	if identity != nil {
+		req.identity = identity.String()
+	}
+	if nsReq, ok := args.(interface{ RequestNamespace() string }); ok {
+		req.namespace = nsReq.RequestNamespace()
+	}
+
+	scope, ok := r.rateLimiter.allow(req, time.Now())
+	if ok {
+		return nil
+	}
+
+	labels := []metrics.Label{
+		{Name: "scope", Value: scope},
+		{Name: "method", Value: method},
+	}
+	if r.srv.config.ACLEnabled && identity != nil && !r.srv.config.DisableRPCRateMetricsLabels {
+		labels = append(labels, metrics.Label{Name: "identity", Value: req.identity})
+	}
+	metrics.IncrCounterWithLabels([]string{"nomad", "rpc", "rate_limit", "rejected"}, 1, labels)
+
+	return structs.NewErrRateLimited(scope)
+}
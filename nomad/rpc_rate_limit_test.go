@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/pointer"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/open-wander/wander/nomad/structs/config"
+	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestRPCRateLimiter_Allow(t *testing.T) {
+	ci.Parallel(t)
+
+	limiter := newRPCRateLimiter(&config.RPCRateLimitConfig{
+		Token: &config.RPCRateLimit{Read: pointer.Of(2), Write: pointer.Of(1)},
+		Namespaces: []*config.NamedRPCRateLimit{
+			{Name: "prod", RPCRateLimit: config.RPCRateLimit{Read: pointer.Of(3)}},
+		},
+		Endpoints: []*config.NamedRPCRateLimit{
+			{Name: "Job.Register", RPCRateLimit: config.RPCRateLimit{Write: pointer.Of(2)}},
+		},
+	})
+	now := time.Now()
+
+	read := func(identity, ns string) (string, bool) {
+		return limiter.allow(&rpcRateLimitedRequest{
+			method: "Job.List", namespace: ns, identity: identity,
+		}, now)
+	}
+	write := func(identity string) (string, bool) {
+		return limiter.allow(&rpcRateLimitedRequest{
+			method: "Job.Register", namespace: "dev", identity: identity, write: true,
+		}, now)
+	}
+
+	// Each token gets its own bucket.
+	for _, identity := range []string{"token:a", "token:b"} {
+		for i := 0; i < 2; i++ {
+			_, ok := read(identity, "dev")
+			must.True(t, ok)
+		}
+		scope, ok := read(identity, "dev")
+		must.False(t, ok)
+		must.Eq(t, rpcRateLimitScopeToken, scope)
+	}
+
+	// Reads and writes are limited separately.
+	_, ok := write("token:a")
+	must.True(t, ok)
+	scope, ok := write("token:a")
+	must.False(t, ok)
+	must.Eq(t, rpcRateLimitScopeToken, scope)
+
+	// The endpoint limit applies across tokens, and a rejected request does
+	// not consume from the other limits.
+	_, ok = write("token:b")
+	must.True(t, ok)
+	scope, ok = write("token:c")
+	must.False(t, ok)
+	must.Eq(t, rpcRateLimitScopeEndpoint, scope)
+	_, ok = read("token:c", "dev")
+	must.True(t, ok)
+
+	// The namespace limit applies across tokens.
+	for _, identity := range []string{"token:d", "token:e", "token:f"} {
+		_, ok = read(identity, "prod")
+		must.True(t, ok)
+	}
+	scope, ok = read("token:g", "prod")
+	must.False(t, ok)
+	must.Eq(t, rpcRateLimitScopeNamespace, scope)
+
+	// The buckets are refilled over time.
+	now = now.Add(time.Second)
+	_, ok = read("token:a", "dev")
+	must.True(t, ok)
+}
+
+func TestRPC_RateLimit(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.RPCRateLimit = &config.RPCRateLimitConfig{
+			Token: &config.RPCRateLimit{Read: pointer.Of(1)},
+		}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	req := &structs.JobListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: root.SecretID,
+		},
+	}
+
+	// The first request uses the burst of the token and the second one is
+	// rejected unless a second has passed in between.
+	var err error
+	for i := 0; i < 3; i++ {
+		var resp structs.JobListResponse
+		err = msgpackrpc.CallWithCodec(codec, "Job.List", req, &resp)
+		if err != nil {
+			break
+		}
+	}
+	must.Error(t, err)
+	must.True(t, structs.IsErrRateLimited(err))
+	code, _, ok := structs.CodeFromRPCCodedErr(err)
+	must.True(t, ok)
+	must.Eq(t, 429, code)
+}
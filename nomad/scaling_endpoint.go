@@ -43,15 +43,11 @@ func (p *Scaling) ListPolicies(args *structs.ScalingPolicyListRequest, reply *st
 		return p.listAllNamespaces(args, reply)
 	}
 
-	if aclObj, err := p.srv.ResolveACL(args); err != nil {
+	aclObj, err := p.srv.ResolveACL(args)
+	if err != nil {
 		return err
-	} else if aclObj != nil {
-		hasListScalingPolicies := aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityListScalingPolicies)
-		hasListAndReadJobs := aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityListJobs) &&
-			aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob)
-		if !(hasListScalingPolicies || hasListAndReadJobs) {
-			return structs.ErrPermissionDenied
-		}
+	} else if !allowScalingPolicySearch(aclObj, args.RequestNamespace(), acl.NamespaceCapabilityListScalingPolicies) {
+		return structs.ErrPermissionDenied
 	}
 
 	// Setup the blocking query
@@ -78,6 +74,9 @@ func (p *Scaling) ListPolicies(args *structs.ScalingPolicyListRequest, reply *st
 			reply.Policies = nil
 			for raw := iter.Next(); raw != nil; raw = iter.Next() {
 				policy := raw.(*structs.ScalingPolicy)
+				if !allowScalingPolicyOp(aclObj, policy, acl.NamespaceCapabilityListScalingPolicies) {
+					continue
+				}
 				reply.Policies = append(reply.Policies, policy.Stub())
 			}
 
@@ -112,15 +111,11 @@ func (p *Scaling) GetPolicy(args *structs.ScalingPolicySpecificRequest,
 	defer metrics.MeasureSince([]string{"nomad", "scaling", "get_policy"}, time.Now())
 
 	// Check for list-job permissions
-	if aclObj, err := p.srv.ResolveACL(args); err != nil {
+	aclObj, err := p.srv.ResolveACL(args)
+	if err != nil {
 		return err
-	} else if aclObj != nil {
-		hasReadScalingPolicy := aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadScalingPolicy)
-		hasListAndReadJobs := aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityListJobs) &&
-			aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob)
-		if !(hasReadScalingPolicy || hasListAndReadJobs) {
-			return structs.ErrPermissionDenied
-		}
+	} else if !allowScalingPolicySearch(aclObj, args.RequestNamespace(), acl.NamespaceCapabilityReadScalingPolicy) {
+		return structs.ErrPermissionDenied
 	}
 
 	// Setup the blocking query
@@ -134,6 +129,12 @@ func (p *Scaling) GetPolicy(args *structs.ScalingPolicySpecificRequest,
 				return err
 			}
 
+			// Re-check permissions on the policy's job, as the namespace
+			// may differ from the request.
+			if p != nil && !allowScalingPolicyOp(aclObj, p, acl.NamespaceCapabilityReadScalingPolicy) {
+				return structs.ErrPermissionDenied
+			}
+
 			reply.Policy = p
 
 			// If the state lookup returned a policy object, use the modify
@@ -161,8 +162,7 @@ func (p *Scaling) listAllNamespaces(args *structs.ScalingPolicyListRequest, repl
 	}
 	prefix := args.QueryOptions.Prefix
 	allow := func(ns string) bool {
-		return aclObj.AllowNsOp(ns, acl.NamespaceCapabilityListScalingPolicies) ||
-			(aclObj.AllowNsOp(ns, acl.NamespaceCapabilityListJobs) && aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob))
+		return allowScalingPolicySearch(aclObj, ns, acl.NamespaceCapabilityListScalingPolicies)
 	}
 
 	// Setup the blocking query
@@ -198,6 +198,9 @@ func (p *Scaling) listAllNamespaces(args *structs.ScalingPolicyListRequest, repl
 					// not permitted to this name namespace
 					continue
 				}
+				if !allowScalingPolicyOp(aclObj, policy, acl.NamespaceCapabilityListScalingPolicies) {
+					continue
+				}
 				if prefix != "" && !strings.HasPrefix(policy.ID, prefix) {
 					continue
 				}
@@ -218,3 +221,24 @@ func (p *Scaling) listAllNamespaces(args *structs.ScalingPolicyListRequest, repl
 		}}
 	return p.srv.blockingRPC(&opts)
 }
+
+// allowScalingPolicySearch returns true if the token may see any scaling
+// policies in the namespace, either through the given namespace capability or
+// by being able to list and read some of its jobs. Callers must filter the
+// policies they return with allowScalingPolicyOp.
+func allowScalingPolicySearch(aclObj *acl.ACL, ns, op string) bool {
+	return aclObj.AllowNsOp(ns, op) ||
+		(aclObj.AllowJobSearch(ns, acl.NamespaceCapabilityListJobs) &&
+			aclObj.AllowJobSearch(ns, acl.NamespaceCapabilityReadJob))
+}
+
+// allowScalingPolicyOp returns true if the token may see the scaling policy,
+// either through the given namespace capability or by being able to list and
+// read the policy's job. A job policy denying the job takes precedence.
+func allowScalingPolicyOp(aclObj *acl.ACL, policy *structs.ScalingPolicy, op string) bool {
+	ns := policy.Target[structs.ScalingTargetNamespace]
+	jobID := policy.Target[structs.ScalingTargetJob]
+	return aclObj.AllowJobOp(ns, jobID, op) ||
+		(aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityListJobs) &&
+			aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityReadJob))
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/open-wander/wander/nomad/mock"
 	"github.com/open-wander/wander/nomad/structs"
 	"github.com/open-wander/wander/testutil"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -312,6 +313,54 @@ func TestScalingEndpoint_ListPolicies_ACL(t *testing.T) {
 	}
 }
 
+func TestScalingEndpoint_ListPolicies_JobPolicy_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	allowed := mock.ScalingPolicy()
+	allowed.Target[structs.ScalingTargetJob] = "payments-api"
+	other := mock.ScalingPolicy()
+	must.NoError(t, state.UpsertScalingPolicies(1000,
+		[]*structs.ScalingPolicy{allowed, other}))
+
+	token := mock.CreatePolicyAndToken(t, state, 1001, "test-job-policy", `
+namespace "default" {
+  job "payments-*" {
+    capabilities = ["list-jobs", "read-job"]
+  }
+}`)
+
+	get := &structs.ScalingPolicyListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.ScalingPolicyListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Scaling.ListPolicies", get, &resp))
+	must.Len(t, 1, resp.Policies)
+	must.Eq(t, allowed.ID, resp.Policies[0].ID)
+
+	// Reading a single policy is also limited to the matching jobs
+	getPolicy := &structs.ScalingPolicySpecificRequest{
+		ID:           allowed.ID,
+		QueryOptions: get.QueryOptions,
+	}
+	var policyResp structs.SingleScalingPolicyResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Scaling.GetPolicy", getPolicy, &policyResp))
+	must.Eq(t, allowed.ID, policyResp.Policy.ID)
+
+	getPolicy.ID = other.ID
+	err := msgpackrpc.CallWithCodec(codec, "Scaling.GetPolicy", getPolicy, &policyResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+}
+
 func TestScalingEndpoint_ListPolicies_Blocking(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -0,0 +1,180 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/open-wander/wander/helper/pluginutils/loader"
+	"github.com/open-wander/wander/plugins/base"
+	schedplugin "github.com/open-wander/wander/plugins/scheduler"
+	"github.com/open-wander/wander/scheduler"
+)
+
+const (
+	// schedulerExtensionRelaunchInterval is the minimum amount of time
+	// between two attempts at launching a scheduler extension plugin that
+	// failed to launch. Scheduling proceeds without the extension meanwhile.
+	schedulerExtensionRelaunchInterval = 10 * time.Second
+)
+
+// SchedulerExtensionConfig configures a scheduler plugin consulted by the
+// generic scheduler when ranking nodes.
+type SchedulerExtensionConfig struct {
+	// Name is the name of the plugin in the plugin catalog.
+	Name string
+
+	// Timeout is the maximum amount of time the scheduler waits for the
+	// plugin to score a node. scheduler.DefaultExtensionTimeout is used if
+	// unset.
+	Timeout time.Duration
+}
+
+func (c *SchedulerExtensionConfig) Copy() *SchedulerExtensionConfig {
+	if c == nil {
+		return nil
+	}
+
+	nc := *c
+	return &nc
+}
+
+// schedulerExtensions launches the configured scheduler extension plugins and
+// provides them to the scheduling workers.
+type schedulerExtensions struct {
+	plugins    []*schedulerExtensionPlugin
+	extensions []*scheduler.Extension
+}
+
+// newSchedulerExtensions launches the scheduler extension plugins. Plugins
+// that fail to launch are logged and relaunched when the schedulers next
+// consult them.
+func newSchedulerExtensions(logger log.Logger, catalog loader.PluginCatalog, configs []*SchedulerExtensionConfig) (*schedulerExtensions, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	if catalog == nil {
+		return nil, errors.New("scheduler extensions require a plugin loader")
+	}
+
+	logger = logger.Named("scheduler_extension")
+	s := &schedulerExtensions{}
+	for _, c := range configs {
+		p := &schedulerExtensionPlugin{
+			name:    c.Name,
+			logger:  logger.With("extension", c.Name),
+			catalog: catalog,
+		}
+		if _, err := p.dispense(); err != nil {
+			p.logger.Error("failed to launch scheduler extension", "error", err)
+		}
+
+		s.plugins = append(s.plugins, p)
+		s.extensions = append(s.extensions, &scheduler.Extension{
+			Name:    c.Name,
+			Scorer:  p,
+			Timeout: c.Timeout,
+		})
+	}
+
+	return s, nil
+}
+
+// Extensions returns the scheduler extensions to consult when ranking nodes.
+func (s *schedulerExtensions) Extensions() []*scheduler.Extension {
+	if s == nil {
+		return nil
+	}
+	return s.extensions
+}
+
+// shutdown kills the scheduler extension plugins.
+func (s *schedulerExtensions) shutdown() {
+	if s == nil {
+		return
+	}
+	for _, p := range s.plugins {
+		p.kill()
+	}
+}
+
+// schedulerExtensionPlugin is a scheduler.ExtensionScorer that launches its
+// plugin lazily and relaunches it if it exits.
+type schedulerExtensionPlugin struct {
+	name    string
+	logger  log.Logger
+	catalog loader.PluginCatalog
+
+	lock        sync.Mutex
+	instance    loader.PluginInstance
+	plugin      schedplugin.SchedulerPlugin
+	lastFailure time.Time
+	killed      bool
+}
+
+func (p *schedulerExtensionPlugin) ScoreNode(ctx context.Context, req *schedplugin.ScoreNodeRequest) (*schedplugin.ScoreNodeResponse, error) {
+	plugin, err := p.dispense()
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ScoreNode(ctx, req)
+}
+
+// dispense returns the running plugin, launching it if required.
+func (p *schedulerExtensionPlugin) dispense() (schedplugin.SchedulerPlugin, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.killed {
+		return nil, errors.New("scheduler extension has been shut down")
+	}
+
+	if p.instance != nil {
+		if !p.instance.Exited() {
+			return p.plugin, nil
+		}
+
+		p.logger.Warn("scheduler extension exited, relaunching it")
+		p.instance.Kill()
+		p.instance, p.plugin = nil, nil
+	}
+
+	if !p.lastFailure.IsZero() && time.Since(p.lastFailure) < schedulerExtensionRelaunchInterval {
+		return nil, fmt.Errorf("scheduler extension failed to launch %v ago", time.Since(p.lastFailure).Round(time.Second))
+	}
+
+	instance, err := p.catalog.Dispense(p.name, base.PluginTypeScheduler, nil, p.logger)
+	if err != nil {
+		p.lastFailure = time.Now()
+		return nil, err
+	}
+
+	plugin, ok := instance.Plugin().(schedplugin.SchedulerPlugin)
+	if !ok {
+		instance.Kill()
+		p.lastFailure = time.Now()
+		return nil, fmt.Errorf("plugin %q is not a scheduler plugin", p.name)
+	}
+
+	p.instance, p.plugin = instance, plugin
+	p.lastFailure = time.Time{}
+	return plugin, nil
+}
+
+// kill stops the plugin and prevents it from being relaunched.
+func (p *schedulerExtensionPlugin) kill() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.killed = true
+	if p.instance != nil {
+		p.instance.Kill()
+		p.instance, p.plugin = nil, nil
+	}
+}
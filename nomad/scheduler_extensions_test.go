@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/pluginutils/loader"
+	"github.com/open-wander/wander/helper/testlog"
+	"github.com/open-wander/wander/plugins/base"
+	schedplugin "github.com/open-wander/wander/plugins/scheduler"
+	"github.com/shoenig/test/must"
+)
+
+// mockSchedulerExtensionCatalog returns a plugin catalog that dispenses the
+// passed scheduler plugin and the list of instances it launched.
+func mockSchedulerExtensionCatalog(impl schedplugin.SchedulerPlugin) (*loader.MockCatalog, *[]*loader.MockInstance) {
+	var instances []*loader.MockInstance
+	catalog := &loader.MockCatalog{
+		DispenseF: func(name, pluginType string, _ *base.AgentConfig, _ log.Logger) (loader.PluginInstance, error) {
+			if name != "inventory" || pluginType != base.PluginTypeScheduler {
+				return nil, errors.New("unknown plugin")
+			}
+			instance := loader.MockBasicExternalPlugin(impl, schedplugin.ApiVersion010)
+			instances = append(instances, instance)
+			return instance, nil
+		},
+	}
+	return catalog, &instances
+}
+
+func TestSchedulerExtensions(t *testing.T) {
+	ci.Parallel(t)
+
+	impl := &schedplugin.MockSchedulerPlugin{
+		ScoreNodeF: schedplugin.StaticScorer(map[string]*schedplugin.ScoreNodeResponse{
+			"node1": {Score: 0.5},
+		}),
+	}
+	catalog, instances := mockSchedulerExtensionCatalog(impl)
+
+	exts, err := newSchedulerExtensions(testlog.HCLogger(t), catalog, []*SchedulerExtensionConfig{
+		{Name: "inventory", Timeout: time.Second},
+	})
+	must.NoError(t, err)
+
+	// The plugin is launched on start.
+	must.Len(t, 1, *instances)
+
+	extensions := exts.Extensions()
+	must.Len(t, 1, extensions)
+	must.Eq(t, "inventory", extensions[0].Name)
+	must.Eq(t, time.Second, extensions[0].Timeout)
+
+	req := &schedplugin.ScoreNodeRequest{Node: &schedplugin.CandidateNode{ID: "node1"}}
+	resp, err := extensions[0].Scorer.ScoreNode(context.Background(), req)
+	must.NoError(t, err)
+	must.Eq(t, 0.5, resp.Score)
+	must.Len(t, 1, *instances)
+
+	// The plugin is relaunched if it exits.
+	(*instances)[0].Kill()
+	resp, err = extensions[0].Scorer.ScoreNode(context.Background(), req)
+	must.NoError(t, err)
+	must.Eq(t, 0.5, resp.Score)
+	must.Len(t, 2, *instances)
+
+	// The plugin is killed on shutdown and not relaunched.
+	exts.shutdown()
+	must.True(t, (*instances)[1].Exited())
+	_, err = extensions[0].Scorer.ScoreNode(context.Background(), req)
+	must.ErrorContains(t, err, "shut down")
+	must.Len(t, 2, *instances)
+}
+
+func TestSchedulerExtensions_LaunchFailure(t *testing.T) {
+	ci.Parallel(t)
+
+	dispensed := 0
+	catalog := &loader.MockCatalog{
+		DispenseF: func(string, string, *base.AgentConfig, log.Logger) (loader.PluginInstance, error) {
+			dispensed++
+			return nil, errors.New("plugin not found")
+		},
+	}
+
+	// A plugin that fails to launch doesn't prevent the server from starting.
+	exts, err := newSchedulerExtensions(testlog.HCLogger(t), catalog, []*SchedulerExtensionConfig{
+		{Name: "inventory"},
+	})
+	must.NoError(t, err)
+	must.Eq(t, 1, dispensed)
+
+	// The launch isn't retried until the relaunch interval has passed.
+	scorer := exts.Extensions()[0].Scorer
+	_, err = scorer.ScoreNode(context.Background(), &schedplugin.ScoreNodeRequest{})
+	must.ErrorContains(t, err, "failed to launch")
+	must.Eq(t, 1, dispensed)
+
+	exts.plugins[0].lastFailure = time.Now().Add(-schedulerExtensionRelaunchInterval)
+	_, err = scorer.ScoreNode(context.Background(), &schedplugin.ScoreNodeRequest{})
+	must.ErrorContains(t, err, "plugin not found")
+	must.Eq(t, 2, dispensed)
+}
+
+func TestSchedulerExtensions_Config(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+
+	exts, err := newSchedulerExtensions(logger, nil, nil)
+	must.NoError(t, err)
+	must.Nil(t, exts.Extensions())
+	exts.shutdown()
+
+	_, err = newSchedulerExtensions(logger, nil, []*SchedulerExtensionConfig{{Name: "inventory"}})
+	must.ErrorContains(t, err, "require a plugin loader")
+}
+
+func TestWorker_SchedulerExtensions(t *testing.T) {
+	ci.Parallel(t)
+
+	impl := &schedplugin.MockSchedulerPlugin{}
+	catalog, _ := mockSchedulerExtensionCatalog(impl)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+		c.PluginLoader = catalog
+		c.SchedulerExtensions = []*SchedulerExtensionConfig{{Name: "inventory"}}
+	})
+	defer cleanupS1()
+
+	w := &Worker{srv: s1}
+	extensions := w.SchedulerExtensions()
+	must.Len(t, 1, extensions)
+	must.Eq(t, "inventory", extensions[0].Name)
+}
@@ -381,11 +381,14 @@ func sortSet(matches []fuzzyMatch) {
 func getResourceIter(context structs.Context, aclObj *acl.ACL, namespace, prefix string, ws memdb.WatchSet, store *state.StateStore) (memdb.ResultIterator, error) {
 	switch context {
 	case structs.Jobs:
-		return store.JobsByIDPrefix(ws, namespace, prefix)
+		iter, err := store.JobsByIDPrefix(ws, namespace, prefix)
+		return nsCapIterFilter(iter, err, aclObj)
 	case structs.Evals:
-		return store.EvalsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		iter, err := store.EvalsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		return nsCapIterFilter(iter, err, aclObj)
 	case structs.Allocs:
-		return store.AllocsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		iter, err := store.AllocsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		return nsCapIterFilter(iter, err, aclObj)
 	case structs.Nodes:
 		return store.NodesByIDPrefix(ws, prefix)
 	case structs.NodePools:
@@ -398,11 +401,13 @@ func getResourceIter(context structs.Context, aclObj *acl.ACL, namespace, prefix
 		}
 		return memdb.NewFilterIterator(iter, nodePoolCapFilter(aclObj)), nil
 	case structs.Deployments:
-		return store.DeploymentsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		iter, err := store.DeploymentsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		return nsCapIterFilter(iter, err, aclObj)
 	case structs.Plugins:
 		return store.CSIPluginsByIDPrefix(ws, prefix)
 	case structs.ScalingPolicies:
-		return store.ScalingPoliciesByIDPrefix(ws, namespace, prefix)
+		iter, err := store.ScalingPoliciesByIDPrefix(ws, namespace, prefix)
+		return nsCapIterFilter(iter, err, aclObj)
 	case structs.Volumes:
 		return store.CSIVolumesByIDPrefix(ws, namespace, prefix)
 	case structs.Namespaces:
@@ -442,14 +447,16 @@ func getFuzzyResourceIterator(context structs.Context, aclObj *acl.ACL, namespac
 			iter, err := store.Jobs(ws)
 			return nsCapIterFilter(iter, err, aclObj)
 		}
-		return store.JobsByNamespace(ws, namespace)
+		iter, err := store.JobsByNamespace(ws, namespace)
+		return nsCapIterFilter(iter, err, aclObj)
 
 	case structs.Allocs:
 		if wildcard(namespace) {
 			iter, err := store.Allocs(ws, state.SortDefault)
 			return nsCapIterFilter(iter, err, aclObj)
 		}
-		return store.AllocsByNamespace(ws, namespace)
+		iter, err := store.AllocsByNamespace(ws, namespace)
+		return nsCapIterFilter(iter, err, aclObj)
 
 	case structs.Variables:
 		if wildcard(namespace) {
@@ -511,10 +518,22 @@ func nsCapFilter(aclObj *acl.ACL) memdb.FilterFunc {
 	return func(v interface{}) bool {
 		switch t := v.(type) {
 		case *structs.Job:
-			return !aclObj.AllowNsOp(t.Namespace, acl.NamespaceCapabilityReadJob)
+			return !aclObj.AllowJobOp(t.Namespace, t.ID, acl.NamespaceCapabilityReadJob)
 
 		case *structs.Allocation:
-			return !aclObj.AllowNsOp(t.Namespace, acl.NamespaceCapabilityReadJob)
+			return !aclObj.AllowJobGroupOperation(t.Namespace, t.JobID, t.TaskGroup,
+				acl.NamespaceCapabilityReadJob)
+
+		case *structs.Evaluation:
+			return !aclObj.AllowJobOp(t.Namespace, t.JobID, acl.NamespaceCapabilityReadJob)
+
+		case *structs.Deployment:
+			return !aclObj.AllowJobOp(t.Namespace, t.JobID, acl.NamespaceCapabilityReadJob)
+
+		case *structs.ScalingPolicy:
+			ns, jobID := t.Target[structs.ScalingTargetNamespace], t.Target[structs.ScalingTargetJob]
+			return !aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityListScalingPolicies) &&
+				!aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityReadJob)
 
 		case *structs.VariableEncrypted:
 			return !aclObj.AllowVariableSearch(t.Namespace)
@@ -685,8 +704,12 @@ func sufficientSearchPerms(aclObj *acl.ACL, namespace string, context structs.Co
 		return aclObj.AllowNodePoolSearch()
 	case structs.Namespaces:
 		return aclObj.AllowNamespace(namespace)
-	case structs.Allocs, structs.Deployments, structs.Evals, structs.Jobs,
-		structs.ScalingPolicies, structs.Recommendations:
+	case structs.Allocs, structs.Jobs, structs.Deployments, structs.Evals,
+		structs.ScalingPolicies:
+		// Job policies may grant access to some of the jobs in the namespace,
+		// which are filtered when iterating over the results.
+		return aclObj.AllowJobSearch(namespace, acl.NamespaceCapabilityReadJob)
+	case structs.Recommendations:
 		return aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob)
 	case structs.Volumes:
 		return acl.NamespaceValidator(acl.NamespaceCapabilityCSIListVolume,
@@ -900,7 +923,7 @@ func filteredSearchContexts(aclObj *acl.ACL, namespace string, context structs.C
 	if aclObj.IsManagement() {
 		return desired
 	}
-	jobRead := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob)
+	jobSearch := aclObj.AllowJobSearch(namespace, acl.NamespaceCapabilityReadJob)
 	allowVolume := acl.NamespaceValidator(acl.NamespaceCapabilityCSIListVolume,
 		acl.NamespaceCapabilityCSIReadVolume,
 		acl.NamespaceCapabilityListJobs,
@@ -913,11 +936,11 @@ func filteredSearchContexts(aclObj *acl.ACL, namespace string, context structs.C
 	for _, c := range desired {
 		switch c {
 		case structs.Allocs, structs.Jobs, structs.Evals, structs.Deployments:
-			if jobRead {
+			if jobSearch {
 				available = append(available, c)
 			}
 		case structs.ScalingPolicies:
-			if policyRead || jobRead {
+			if policyRead || jobSearch {
 				available = append(available, c)
 			}
 		case structs.Namespaces:
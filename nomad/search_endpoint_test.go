@@ -262,6 +262,53 @@ func TestSearch_PrefixSearch_ACL(t *testing.T) {
 	}
 }
 
+func TestSearch_PrefixSearch_JobPolicy_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s, _, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	state := s.fsm.State()
+
+	allowedEval := mock.Eval()
+	allowedEval.ID = "aaaa" + allowedEval.ID[4:]
+	allowedEval.JobID = "payments-api"
+	otherEval := mock.Eval()
+	otherEval.ID = "aaab" + otherEval.ID[4:]
+	must.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1000,
+		[]*structs.Evaluation{allowedEval, otherEval}))
+
+	allowedDeploy := mock.Deployment()
+	allowedDeploy.ID = "aaaa" + allowedDeploy.ID[4:]
+	allowedDeploy.JobID = "payments-api"
+	otherDeploy := mock.Deployment()
+	otherDeploy.ID = "aaab" + otherDeploy.ID[4:]
+	must.NoError(t, state.UpsertDeployment(1001, allowedDeploy))
+	must.NoError(t, state.UpsertDeployment(1002, otherDeploy))
+
+	token := mock.CreatePolicyAndToken(t, state, 1003, "test-job-policy", `
+namespace "default" {
+  job "payments-*" {
+    policy = "read"
+  }
+}`)
+
+	req := &structs.SearchRequest{
+		Prefix:  "aa",
+		Context: structs.All,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.SearchResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Search.PrefixSearch", req, &resp))
+	must.Eq(t, []string{allowedEval.ID}, resp.Matches[structs.Evals])
+	must.Eq(t, []string{allowedDeploy.ID}, resp.Matches[structs.Deployments])
+}
+
 func TestSearch_PrefixSearch_All_JobWithHyphen(t *testing.T) {
 	ci.Parallel(t)
 
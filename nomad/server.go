@@ -233,6 +233,10 @@ type Server struct {
 	// workload identities
 	encrypter *Encrypter
 
+	// oidcDisco is the OIDC discovery document for workload identities, or
+	// nil if no issuer is configured
+	oidcDisco *structs.OIDCDiscoveryConfig
+
 	// periodicDispatcher is used to track and create evaluations for periodic jobs.
 	periodicDispatcher *PeriodicDispatch
 
@@ -256,6 +260,10 @@ type Server struct {
 	// vault is the client for communicating with Vault.
 	vault VaultClient
 
+	// schedulerExtensions are the scheduler plugins consulted by the workers
+	// when ranking nodes
+	schedulerExtensions *schedulerExtensions
+
 	// Worker used for processing
 	workers          []*Worker
 	workerLock       sync.RWMutex
@@ -265,6 +273,10 @@ type Server struct {
 	// aclCache is used to maintain the parsed ACL objects
 	aclCache *structs.ACLCache[*acl.ACL]
 
+	// aclTokenUsage batches the last use of the ACL tokens authenticating
+	// requests until it is recorded in the state store.
+	aclTokenUsage *aclTokenUsageTracker
+
 	// oidcProviderCache maintains a cache of OIDC providers. This is useful as
 	// the provider performs background HTTP requests. When the Nomad server is
 	// shutting down, the oidcProviderCache.Shutdown() function must be called.
@@ -352,6 +364,7 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 		blockedEvals:            NewBlockedEvals(evalBroker, logger),
 		rpcTLS:                  incomingTLS,
 		aclCache:                aclCache,
+		aclTokenUsage:           newACLTokenUsageTracker(),
 		workersEventCh:          make(chan interface{}, 1),
 	}
 
@@ -401,6 +414,13 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 	}
 	s.encrypter = encrypter
 
+	if s.config.OIDCIssuer != "" {
+		s.oidcDisco, err = structs.NewOIDCDiscoveryConfig(s.config.OIDCIssuer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Set up the OIDC provider cache. This is needed by the setupRPC, but must
 	// be done separately so that the server can stop all background processes
 	// when it shuts down itself.
@@ -428,6 +448,14 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 		return nil, fmt.Errorf("Failed to start serf: %v", err)
 	}
 
+	// Launch the scheduler extensions before the workers that use them
+	s.schedulerExtensions, err = newSchedulerExtensions(s.logger, config.PluginLoader, config.SchedulerExtensions)
+	if err != nil {
+		s.Shutdown()
+		s.logger.Error("failed to set up scheduler extensions", "error", err)
+		return nil, fmt.Errorf("Failed to set up scheduler extensions: %v", err)
+	}
+
 	// Initialize the scheduling workers
 	if err := s.setupWorkers(s.shutdownCtx); err != nil {
 		s.Shutdown()
@@ -496,6 +524,11 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 	// Emit raft and state store metrics
 	go s.EmitRaftStats(10*time.Second, s.shutdownCh)
 
+	// Record the last use of ACL tokens
+	if config.ACLEnabled {
+		go s.flushACLTokenUsage(s.shutdownCtx)
+	}
+
 	// Start enterprise background workers
 	s.startEnterpriseBackground()
 
@@ -719,6 +752,9 @@ func (s *Server) Shutdown() error {
 		s.oidcProviderCache.Shutdown()
 	}
 
+	// Kill the scheduler extension plugins
+	s.schedulerExtensions.shutdown()
+
 	return nil
 }
 
@@ -1232,6 +1268,11 @@ func (s *Server) setupStreamingEndpoints(server *rpc.Server) {
 	// be registered
 	operatorEndpoint := NewOperatorEndpoint(s, nil)
 	operatorEndpoint.register()
+
+	// ExecSession takes a RPC context but also has a streaming RPC that
+	// needs to be registered
+	execSessionEndpoint := NewExecSessionEndpoint(s, nil)
+	execSessionEndpoint.register()
 }
 
 // setupRpcServer is used to populate an RPC server with endpoints. This gets
@@ -1241,6 +1282,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	// These endpoints are client RPCs and don't include a connection context
 	_ = server.Register(NewClientStatsEndpoint(s))
 	_ = server.Register(newNodeMetaEndpoint(s))
+	_ = server.Register(newArtifactCacheEndpoint(s))
 
 	// These endpoints have their streaming component registered in
 	// setupStreamingEndpoints, but their non-streaming RPCs are registered
@@ -1249,6 +1291,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	_ = server.Register(NewFileSystemEndpoint(s))
 	_ = server.Register(NewAgentEndpoint(s))
 	_ = server.Register(NewOperatorEndpoint(s, ctx))
+	_ = server.Register(NewExecSessionEndpoint(s, ctx))
 
 	// All other endpoints include the connection context and don't need to be
 	// registered as streaming endpoints
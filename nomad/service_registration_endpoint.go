@@ -129,10 +129,18 @@ func (s *ServiceRegistration) DeleteByID(
 
 	switch err {
 	case nil:
-		// If ACLs are enabled, ensure the caller has the submit-job namespace
-		// capability.
+		// If ACLs are enabled, ensure the caller has the submit-job
+		// capability on the job which registered the service.
 		if aclObj != nil {
-			hasSubmitJob := aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob)
+			reg, err := s.srv.fsm.State().GetServiceRegistrationByID(nil, args.RequestNamespace(), args.ID)
+			if err != nil {
+				return err
+			}
+			var jobID string
+			if reg != nil {
+				jobID = reg.JobID
+			}
+			hasSubmitJob := aclObj.AllowJobOp(args.RequestNamespace(), jobID, acl.NamespaceCapabilitySubmitJob)
 			if !hasSubmitJob {
 				return structs.ErrPermissionDenied
 			}
@@ -220,8 +228,11 @@ func (s *ServiceRegistration) List(
 	if err != nil {
 		return structs.ErrPermissionDenied
 	}
-	if args.GetIdentity().Claims == nil &&
-		!aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	// Workload identities may read any service in their namespace; other
+	// callers only see services registered by jobs they can read.
+	isWorkload := args.GetIdentity().Claims != nil
+	if !isWorkload &&
+		!aclObj.AllowJobSearch(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -242,6 +253,10 @@ func (s *ServiceRegistration) List(
 
 			for raw := iter.Next(); raw != nil; raw = iter.Next() {
 				serviceReg := raw.(*structs.ServiceRegistration)
+				if !isWorkload &&
+					!aclObj.AllowJobOp(serviceReg.Namespace, serviceReg.JobID, acl.NamespaceCapabilityReadJob) {
+					continue
+				}
 				tagSet.add(serviceReg.ServiceName, serviceReg.Tags)
 			}
 
@@ -289,9 +304,9 @@ func (s *ServiceRegistration) listAllServiceRegistrations(
 	}
 
 	// allowFunc checks whether the caller has the read-job capability on the
-	// passed namespace.
+	// passed namespace or on any of its jobs.
 	allowFunc := func(ns string) bool {
-		return aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob)
+		return aclObj.AllowJobSearch(ns, acl.NamespaceCapabilityReadJob)
 	}
 
 	// Set up and return the blocking query.
@@ -333,6 +348,9 @@ func (s *ServiceRegistration) listAllServiceRegistrations(
 				if allowedNSes != nil && !allowedNSes[reg.Namespace] {
 					continue
 				}
+				if !aclObj.AllowJobOp(reg.Namespace, reg.JobID, acl.NamespaceCapabilityReadJob) {
+					continue
+				}
 
 				// Accumulate the set of tags associated with a particular service name in a particular namespace
 				nsSvcTagSet.add(reg.Namespace, reg.ServiceName, reg.Tags)
@@ -385,8 +403,11 @@ func (s *ServiceRegistration) GetService(
 	if err != nil {
 		return structs.ErrPermissionDenied
 	}
-	if args.GetIdentity().Claims == nil &&
-		!aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	// Workload identities may read any service in their namespace; other
+	// callers only see services registered by jobs they can read.
+	isWorkload := args.GetIdentity().Claims != nil
+	if !isWorkload &&
+		!aclObj.AllowJobSearch(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -416,7 +437,17 @@ func (s *ServiceRegistration) GetService(
 
 			// Build the paginator. This includes the function that is
 			// responsible for appending a registration to the services array.
-			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, nil, args.QueryOptions,
+			var filters []paginator.Filter
+			if !isWorkload {
+				filters = append(filters, paginator.GenericFilter{
+					Allow: func(raw interface{}) (bool, error) {
+						reg := raw.(*structs.ServiceRegistration)
+						return aclObj.AllowJobOp(reg.Namespace, reg.JobID, acl.NamespaceCapabilityReadJob), nil
+					},
+				})
+			}
+
+			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
 				func(raw interface{}) error {
 					services = append(services, raw.(*structs.ServiceRegistration))
 					return nil
@@ -949,6 +949,70 @@ func TestServiceRegistration_List(t *testing.T) {
 	}
 }
 
+func TestServiceRegistration_List_JobPolicy_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s, _, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	state := s.fsm.State()
+
+	services := mock.ServiceRegistrations()
+	services[1].Namespace = structs.DefaultNamespace
+	services[1].JobID = "payments-api"
+	must.NoError(t, state.UpsertServiceRegistrations(structs.MsgTypeTestSetup, 1000, services))
+
+	token := mock.CreatePolicyAndToken(t, state, 1001, "test-job-policy", `
+namespace "default" {
+  job "payments-*" {
+    capabilities = ["read-job", "submit-job"]
+  }
+}`)
+
+	listReq := &structs.ServiceRegistrationListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var listResp structs.ServiceRegistrationListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ServiceRegistrationListRPCMethod, listReq, &listResp))
+	must.Len(t, 1, listResp.Services)
+	must.Len(t, 1, listResp.Services[0].Services)
+	must.Eq(t, "countdash-api", listResp.Services[0].Services[0].ServiceName)
+
+	// Reading a service only returns registrations of the matching jobs
+	getReq := &structs.ServiceRegistrationByNameRequest{
+		ServiceName:  "example-cache",
+		QueryOptions: listReq.QueryOptions,
+	}
+	var getResp structs.ServiceRegistrationByNameResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ServiceRegistrationGetServiceRPCMethod, getReq, &getResp))
+	must.Len(t, 0, getResp.Services)
+
+	// Deleting a registration requires submit-job on its job
+	deleteReq := &structs.ServiceRegistrationDeleteByIDRequest{
+		ID: services[0].ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var deleteResp structs.ServiceRegistrationDeleteByIDResponse
+	err := msgpackrpc.CallWithCodec(
+		codec, structs.ServiceRegistrationDeleteByIDRPCMethod, deleteReq, &deleteResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	deleteReq.ID = services[1].ID
+	must.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ServiceRegistrationDeleteByIDRPCMethod, deleteReq, &deleteResp))
+}
+
 func TestServiceRegistration_GetService(t *testing.T) {
 	ci.Parallel(t)
 
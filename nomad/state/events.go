@@ -41,6 +41,9 @@ var MsgTypeEvents = map[structs.MessageType]string{
 	structs.ServiceRegistrationUpsertRequestType:         structs.TypeServiceRegistration,
 	structs.ServiceRegistrationDeleteByIDRequestType:     structs.TypeServiceDeregistration,
 	structs.ServiceRegistrationDeleteByNodeIDRequestType: structs.TypeServiceDeregistration,
+	structs.JobDependencyRunUpdateRequestType:            structs.TypeJobDependencyRunUpdated,
+	structs.ExecSessionStartRequestType:                  structs.TypeExecSessionStarted,
+	structs.ExecSessionEndRequestType:                    structs.TypeExecSessionEnded,
 }
 
 func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
@@ -151,6 +154,19 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 					NodePool: before,
 				},
 			}, true
+		case TableJobDependencyRuns:
+			before, ok := change.Before.(*structs.JobDependencyRun)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic:     structs.TopicJobDependency,
+				Key:       before.JobID,
+				Namespace: before.Namespace,
+				Payload: &structs.JobDependencyRunEvent{
+					Run: before,
+				},
+			}, true
 		case TableServiceRegistrations:
 			before, ok := change.Before.(*structs.ServiceRegistration)
 			if !ok {
@@ -328,6 +344,33 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 				Deployment: after,
 			},
 		}, true
+	case TableJobDependencyRuns:
+		after, ok := change.After.(*structs.JobDependencyRun)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic:     structs.TopicJobDependency,
+			Key:       after.JobID,
+			Namespace: after.Namespace,
+			Payload: &structs.JobDependencyRunEvent{
+				Run: after,
+			},
+		}, true
+	case TableExecSessions:
+		after, ok := change.After.(*structs.ExecSession)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic:      structs.TopicExecSession,
+			Key:        after.ID,
+			Namespace:  after.Namespace,
+			FilterKeys: []string{after.JobID, after.AllocID},
+			Payload: &structs.ExecSessionEvent{
+				Session: after,
+			},
+		}, true
 	case TableServiceRegistrations:
 		after, ok := change.After.(*structs.ServiceRegistration)
 		if !ok {
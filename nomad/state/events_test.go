@@ -446,6 +446,76 @@ func TestEventsFromChanges_NodePoolDeleteRequestType(t *testing.T) {
 	must.Eq(t, pool, payload.NodePool)
 }
 
+func TestEventsFromChanges_JobDependencyRunUpdateRequestType(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	// Create test job with dependencies.
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{JobID: "extract"}}
+	must.NoError(t, s.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	run, err := s.JobDependencyRunByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+
+	// Update test run.
+	updated := run.Copy()
+	updated.Upstreams[0].Status = structs.JobDependencyStatusRunning
+	err = s.UpsertJobDependencyRuns(structs.JobDependencyRunUpdateRequestType, 1001,
+		[]*structs.JobDependencyRun{updated}, nil)
+	must.NoError(t, err)
+
+	// Wait and verify update event.
+	events := WaitForEvents(t, s, 1001, 1, 1*time.Second)
+	must.Len(t, 1, events)
+
+	e := events[0]
+	must.Eq(t, structs.TopicJobDependency, e.Topic)
+	must.Eq(t, structs.TypeJobDependencyRunUpdated, e.Type)
+	must.Eq(t, job.ID, e.Key)
+	must.Eq(t, job.Namespace, e.Namespace)
+
+	payload := e.Payload.(*structs.JobDependencyRunEvent)
+	must.Eq(t, structs.JobDependencyStatusRunning, payload.Run.Upstreams[0].Status)
+}
+
+func TestEventsFromChanges_ExecSession(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	// Start a session.
+	session := mock.ExecSession()
+	err := s.UpsertExecSession(structs.ExecSessionStartRequestType, 1000, session.Copy())
+	must.NoError(t, err)
+
+	events := WaitForEvents(t, s, 1000, 1, 1*time.Second)
+	must.Len(t, 1, events)
+
+	e := events[0]
+	must.Eq(t, structs.TopicExecSession, e.Topic)
+	must.Eq(t, structs.TypeExecSessionStarted, e.Type)
+	must.Eq(t, session.ID, e.Key)
+	must.Eq(t, session.Namespace, e.Namespace)
+	must.Eq(t, []string{session.JobID, session.AllocID}, e.FilterKeys)
+
+	// End the session.
+	ended := session.Copy()
+	ended.EndedAt = time.Now().UTC()
+	ended.ExitCode = 2
+	err = s.UpsertExecSession(structs.ExecSessionEndRequestType, 1001, ended)
+	must.NoError(t, err)
+
+	events = WaitForEvents(t, s, 1001, 1, 1*time.Second)
+	must.Len(t, 1, events)
+
+	e = events[0]
+	must.Eq(t, structs.TypeExecSessionEnded, e.Type)
+	payload := e.Payload.(*structs.ExecSessionEvent)
+	must.Eq(t, 2, payload.Session.ExitCode)
+}
+
 func TestEventsFromChanges_EvalUpdateRequestType(t *testing.T) {
 	ci.Parallel(t)
 	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
@@ -25,6 +25,8 @@ const (
 	TableACLAuthMethods       = "acl_auth_methods"
 	TableACLBindingRules      = "acl_binding_rules"
 	TableAllocs               = "allocs"
+	TableJobDependencyRuns    = "job_dependency_runs"
+	TableExecSessions         = "exec_sessions"
 )
 
 const (
@@ -96,6 +98,8 @@ func init() {
 		aclRolesTableSchema,
 		aclAuthMethodsTableSchema,
 		bindingRulesTableSchema,
+		jobDependencyRunsTableSchema,
+		execSessionsTableSchema,
 	}...)
 }
 
@@ -535,6 +539,61 @@ func periodicLaunchTableSchema() *memdb.TableSchema {
 	}
 }
 
+// jobDependencyRunsTableSchema returns the MemDB schema for the job
+// dependency runs table. This table tracks whether jobs with dependencies
+// have been released for evaluation.
+func jobDependencyRunsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableJobDependencyRuns,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, JobID) is
+				// uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "JobID",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// execSessionsTableSchema returns the MemDB schema for the exec sessions
+// table. This table tracks the recorded exec sessions into allocations.
+func execSessionsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableExecSessions,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "ID",
+				},
+			},
+			"namespace": {
+				Name:         "namespace",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Namespace",
+				},
+			},
+		},
+	}
+}
+
 // evalTableSchema returns the MemDB schema for the eval table.
 // This table is used to store all the evaluations that are pending
 // or recently completed.
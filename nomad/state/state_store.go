@@ -1801,6 +1801,10 @@ func (s *StateStore) upsertJobImpl(index uint64, sub *structs.JobSubmission, job
 		return fmt.Errorf("unable to update job submission: %v", err)
 	}
 
+	if err := s.updateJobDependencyRunTxn(index, job, txn); err != nil {
+		return fmt.Errorf("unable to update job dependency run: %v", err)
+	}
+
 	// Insert the job
 	if err := txn.Insert("jobs", job); err != nil {
 		return fmt.Errorf("job insert failed: %v", err)
@@ -1914,6 +1918,11 @@ func (s *StateStore) DeleteJobTxn(index uint64, namespace, jobID string, txn Txn
 		return fmt.Errorf("deleting job submission failed: %v", err)
 	}
 
+	// Delete the job dependency run
+	if err := s.deleteJobDependencyRunTxn(index, namespace, jobID, txn); err != nil {
+		return err
+	}
+
 	// Delete any remaining job scaling policies
 	if err := s.deleteJobScalingPolicies(index, job, txn); err != nil {
 		return fmt.Errorf("deleting job scaling policies failed: %v", err)
@@ -6069,6 +6078,12 @@ func (s *StateStore) UpsertACLTokens(msgType structs.MessageType, index uint64,
 			token.SecretID = existTK.SecretID
 			token.CreateTime = existTK.CreateTime
 
+			// Keep the last use of the token, which is recorded separately
+			if token.LastUsedTime == nil {
+				token.LastUsedTime = existTK.LastUsedTime
+				token.LastUsedAddress = existTK.LastUsedAddress
+			}
+
 		} else {
 			token.CreateIndex = index
 			token.ModifyIndex = index
@@ -6087,6 +6102,39 @@ func (s *StateStore) UpsertACLTokens(msgType structs.MessageType, index uint64,
 	return txn.Commit()
 }
 
+// UpsertACLTokenUsage records the last use of the tokens. Usage older than
+// the last recorded use of a token, or of a token which doesn't exist
+// anymore, is ignored. The tokens aren't considered modified, so neither
+// their modify index nor the index of the table are updated.
+func (s *StateStore) UpsertACLTokenUsage(msgType structs.MessageType, index uint64, usage []*structs.ACLTokenUsage) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, u := range usage {
+		existing, err := txn.First("acl_token", "id", u.AccessorID)
+		if err != nil {
+			return fmt.Errorf("token lookup failed: %v", err)
+		}
+		if existing == nil {
+			continue
+		}
+
+		existTK := existing.(*structs.ACLToken)
+		if existTK.LastUsedTime != nil && !u.LastUsedTime.After(*existTK.LastUsedTime) {
+			continue
+		}
+
+		token := existTK.Copy()
+		token.LastUsedTime = pointer.Of(u.LastUsedTime)
+		token.LastUsedAddress = u.LastUsedAddress
+		if err := txn.Insert("acl_token", token); err != nil {
+			return fmt.Errorf("upserting token failed: %v", err)
+		}
+	}
+
+	return txn.Commit()
+}
+
 // DeleteACLTokens deletes the tokens with the given accessor ids
 func (s *StateStore) DeleteACLTokens(msgType structs.MessageType, index uint64, ids []string) error {
 	txn := s.db.WriteTxnMsgT(msgType, index)
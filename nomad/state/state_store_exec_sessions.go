@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+// ExecSessions returns an iterator over all exec sessions.
+func (s *StateStore) ExecSessions(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableExecSessions, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("exec sessions lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ExecSessionsByNamespace returns an iterator over the exec sessions of the
+// given namespace.
+func (s *StateStore) ExecSessionsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableExecSessions, "namespace", namespace)
+	if err != nil {
+		return nil, fmt.Errorf("exec sessions lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ExecSessionsByIDPrefix returns an iterator over the exec sessions of the
+// given namespace whose ID matches the prefix. The wildcard namespace matches
+// sessions of all namespaces.
+func (s *StateStore) ExecSessionsByIDPrefix(ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableExecSessions, indexID+"_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("exec sessions lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+
+	filter := func(raw interface{}) bool {
+		session, ok := raw.(*structs.ExecSession)
+		if !ok {
+			return true
+		}
+		return namespace != structs.AllNamespacesSentinel && session.Namespace != namespace
+	}
+	return memdb.NewFilterIterator(iter, filter), nil
+}
+
+// ExecSessionByID returns the exec session with the given ID or nil if it
+// doesn't exist.
+func (s *StateStore) ExecSessionByID(ws memdb.WatchSet, id string) (*structs.ExecSession, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableExecSessions, indexID, id)
+	if err != nil {
+		return nil, fmt.Errorf("exec session lookup failed: %w", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+	return existing.(*structs.ExecSession), nil
+}
+
+// UpsertExecSession inserts an exec session when it starts or updates it when
+// it ends.
+func (s *StateStore) UpsertExecSession(msgType structs.MessageType, index uint64, session *structs.ExecSession) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First(TableExecSessions, indexID, session.ID)
+	if err != nil {
+		return fmt.Errorf("exec session lookup failed: %w", err)
+	}
+
+	session.CreateIndex = index
+	session.ModifyIndex = index
+	if existing != nil {
+		session.CreateIndex = existing.(*structs.ExecSession).CreateIndex
+	}
+
+	if err := txn.Insert(TableExecSessions, session); err != nil {
+		return fmt.Errorf("exec session insert failed: %w", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableExecSessions, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
+
+// DeleteExecSessions deletes the exec sessions with the given IDs. Sessions
+// which don't exist are ignored.
+func (s *StateStore) DeleteExecSessions(msgType structs.MessageType, index uint64, ids []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, id := range ids {
+		if _, err := txn.DeleteAll(TableExecSessions, indexID, id); err != nil {
+			return fmt.Errorf("deleting exec session failed: %w", err)
+		}
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableExecSessions, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
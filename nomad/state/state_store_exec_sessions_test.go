@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_ExecSessions(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	session := mock.ExecSession()
+
+	// Starting a session inserts it.
+	ws := memdb.NewWatchSet()
+	_, err := state.ExecSessionByID(ws, session.ID)
+	must.NoError(t, err)
+
+	must.NoError(t, state.UpsertExecSession(structs.MsgTypeTestSetup, 1000, session.Copy()))
+	must.True(t, watchFired(ws))
+
+	out, err := state.ExecSessionByID(nil, session.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.False(t, out.Ended())
+	must.Eq(t, uint64(1000), out.CreateIndex)
+	must.Eq(t, uint64(1000), out.ModifyIndex)
+
+	// Ending the session updates it.
+	ended := out.Copy()
+	ended.EndedAt = time.Now().UTC()
+	ended.ExitCode = 1
+	must.NoError(t, state.UpsertExecSession(structs.MsgTypeTestSetup, 1001, ended))
+
+	out, err = state.ExecSessionByID(nil, session.ID)
+	must.NoError(t, err)
+	must.True(t, out.Ended())
+	must.Eq(t, 1, out.ExitCode)
+	must.Eq(t, uint64(1000), out.CreateIndex)
+	must.Eq(t, uint64(1001), out.ModifyIndex)
+
+	index, err := state.Index(TableExecSessions)
+	must.NoError(t, err)
+	must.Eq(t, uint64(1001), index)
+
+	// Sessions can be listed by namespace and ID prefix.
+	other := mock.ExecSession()
+	other.Namespace = "other"
+	must.NoError(t, state.UpsertExecSession(structs.MsgTypeTestSetup, 1002, other))
+
+	countSessions := func(iter memdb.ResultIterator, err error) int {
+		must.NoError(t, err)
+		num := 0
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			num++
+		}
+		return num
+	}
+	must.Eq(t, 2, countSessions(state.ExecSessions(nil)))
+	must.Eq(t, 1, countSessions(state.ExecSessionsByNamespace(nil, "other")))
+	must.Eq(t, 1, countSessions(state.ExecSessionsByIDPrefix(nil, structs.DefaultNamespace, session.ID[:4])))
+	must.Eq(t, 0, countSessions(state.ExecSessionsByIDPrefix(nil, "other", session.ID[:4])))
+	must.Eq(t, 1, countSessions(state.ExecSessionsByIDPrefix(nil, structs.AllNamespacesSentinel, session.ID)))
+
+	// Deleting sessions ignores those which don't exist.
+	must.NoError(t, state.DeleteExecSessions(structs.MsgTypeTestSetup, 1003, []string{session.ID, "b6a6aa3b-3bd2-8e9c-e2ec-36c2d0b69c5e"}))
+	out, err = state.ExecSessionByID(nil, session.ID)
+	must.NoError(t, err)
+	must.Nil(t, out)
+	must.Eq(t, 1, countSessions(state.ExecSessions(nil)))
+}
@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/open-wander/wander/nomad/structs"
+)
+
+// JobDependencyRuns returns an iterator over all job dependency runs.
+func (s *StateStore) JobDependencyRuns(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableJobDependencyRuns, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("job dependency runs lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// JobDependencyRunByID returns the dependency run of the given job or nil if
+// the job has no dependencies.
+func (s *StateStore) JobDependencyRunByID(ws memdb.WatchSet, namespace, jobID string) (*structs.JobDependencyRun, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableJobDependencyRuns, indexID, namespace, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("job dependency run lookup failed: %w", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+	return existing.(*structs.JobDependencyRun), nil
+}
+
+// UpsertJobDependencyRuns updates the given dependency runs and inserts the
+// evaluations of triggered runs. Runs created for a job version that has
+// since been replaced or deleted are ignored along with their evaluations.
+func (s *StateStore) UpsertJobDependencyRuns(msgType structs.MessageType, index uint64,
+	runs []*structs.JobDependencyRun, evals []*structs.Evaluation) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	applied := make(map[structs.NamespacedID]struct{}, len(runs))
+	for _, run := range runs {
+		existing, err := txn.First(TableJobDependencyRuns, indexID, run.Namespace, run.JobID)
+		if err != nil {
+			return fmt.Errorf("job dependency run lookup failed: %w", err)
+		}
+		if existing == nil {
+			continue
+		}
+		exist := existing.(*structs.JobDependencyRun)
+		if exist.JobModifyIndex != run.JobModifyIndex {
+			continue
+		}
+
+		run.CreateIndex = exist.CreateIndex
+		run.ModifyIndex = index
+		if err := txn.Insert(TableJobDependencyRuns, run); err != nil {
+			return fmt.Errorf("job dependency run insert failed: %w", err)
+		}
+		applied[structs.NamespacedID{ID: run.JobID, Namespace: run.Namespace}] = struct{}{}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobDependencyRuns, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	var toUpsert []*structs.Evaluation
+	for _, eval := range evals {
+		if _, ok := applied[structs.NamespacedID{ID: eval.JobID, Namespace: eval.Namespace}]; ok {
+			toUpsert = append(toUpsert, eval)
+		}
+	}
+	if len(toUpsert) > 0 {
+		if err := s.UpsertEvalsTxn(index, toUpsert, txn); err != nil {
+			return err
+		}
+	}
+
+	return txn.Commit()
+}
+
+// updateJobDependencyRunTxn creates a blocked dependency run whenever a new
+// version of a job with dependencies is registered. A run for a stopped job
+// is failed so that jobs depending on it are failed too.
+func (s *StateStore) updateJobDependencyRunTxn(index uint64, job *structs.Job, txn *txn) error {
+	existing, err := txn.First(TableJobDependencyRuns, indexID, job.Namespace, job.ID)
+	if err != nil {
+		return fmt.Errorf("job dependency run lookup failed: %w", err)
+	}
+
+	if !job.HasDependencies() {
+		if existing == nil {
+			return nil
+		}
+		return s.deleteJobDependencyRunTxn(index, job.Namespace, job.ID, txn)
+	}
+
+	if existing != nil && existing.(*structs.JobDependencyRun).JobModifyIndex == job.JobModifyIndex {
+		return nil
+	}
+
+	run := structs.NewJobDependencyRun(job)
+	if job.Stopped() {
+		run.Status = structs.JobDependencyRunStatusFailed
+		run.StatusDescription = "Job was stopped"
+	}
+	run.CreateIndex = index
+	run.ModifyIndex = index
+	if existing != nil {
+		run.CreateIndex = existing.(*structs.JobDependencyRun).CreateIndex
+	}
+
+	if err := txn.Insert(TableJobDependencyRuns, run); err != nil {
+		return fmt.Errorf("job dependency run insert failed: %w", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobDependencyRuns, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+	return nil
+}
+
+// deleteJobDependencyRunTxn deletes the dependency run of a job, if any.
+func (s *StateStore) deleteJobDependencyRunTxn(index uint64, namespace, jobID string, txn *txn) error {
+	num, err := txn.DeleteAll(TableJobDependencyRuns, indexID, namespace, jobID)
+	if err != nil {
+		return fmt.Errorf("deleting job dependency run failed: %w", err)
+	}
+	if num == 0 {
+		return nil
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobDependencyRuns, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"testing"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_JobDependencyRun_UpsertJob(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{JobID: "extract"}}
+
+	// Registering a job with dependencies creates a blocked run.
+	ws := memdb.NewWatchSet()
+	_, err := state.JobDependencyRunByID(ws, job.Namespace, job.ID)
+	must.NoError(t, err)
+
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+	must.True(t, watchFired(ws))
+
+	run, err := state.JobDependencyRunByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.NotNil(t, run)
+	must.Eq(t, structs.JobDependencyRunStatusBlocked, run.Status)
+	must.Eq(t, uint64(1000), run.JobModifyIndex)
+	must.Eq(t, uint64(1000), run.CreateIndex)
+	must.Len(t, 1, run.Upstreams)
+
+	index, err := state.Index(TableJobDependencyRuns)
+	must.NoError(t, err)
+	must.Eq(t, uint64(1000), index)
+
+	// A new version of the job creates a new run.
+	job2 := job.Copy()
+	job2.Meta = map[string]string{"version": "2"}
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1002, nil, job2))
+	run, err = state.JobDependencyRunByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, uint64(1002), run.JobModifyIndex)
+	must.Eq(t, uint64(1000), run.CreateIndex)
+	must.Eq(t, uint64(1002), run.ModifyIndex)
+
+	// Stopping the job fails the run.
+	job3 := job2.Copy()
+	job3.Stop = true
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1003, nil, job3))
+	run, err = state.JobDependencyRunByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobDependencyRunStatusFailed, run.Status)
+
+	// Removing the dependencies deletes the run.
+	job4 := job3.Copy()
+	job4.Stop = false
+	job4.DependsOn = nil
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1004, nil, job4))
+	run, err = state.JobDependencyRunByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Nil(t, run)
+}
+
+func TestStateStore_JobDependencyRun_DeleteJob(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{JobID: "extract"}}
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	must.NoError(t, state.DeleteJob(1001, job.Namespace, job.ID))
+
+	run, err := state.JobDependencyRunByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Nil(t, run)
+
+	index, err := state.Index(TableJobDependencyRuns)
+	must.NoError(t, err)
+	must.Eq(t, uint64(1001), index)
+}
+
+func TestStateStore_UpsertJobDependencyRuns(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{JobID: "extract"}}
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	run, err := state.JobDependencyRunByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+
+	// Triggering the run stores its evaluation.
+	eval := mock.Eval()
+	eval.Namespace = job.Namespace
+	eval.JobID = job.ID
+
+	triggered := run.Copy()
+	triggered.Status = structs.JobDependencyRunStatusTriggered
+	triggered.Upstreams[0].Status = structs.JobDependencyStatusComplete
+	triggered.EvalID = eval.ID
+
+	must.NoError(t, state.UpsertJobDependencyRuns(structs.MsgTypeTestSetup, 1001,
+		[]*structs.JobDependencyRun{triggered}, []*structs.Evaluation{eval}))
+
+	out, err := state.JobDependencyRunByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobDependencyRunStatusTriggered, out.Status)
+	must.Eq(t, eval.ID, out.EvalID)
+	must.Eq(t, uint64(1000), out.CreateIndex)
+	must.Eq(t, uint64(1001), out.ModifyIndex)
+
+	outEval, err := state.EvalByID(nil, eval.ID)
+	must.NoError(t, err)
+	must.NotNil(t, outEval)
+
+	// Updates of a replaced job version are ignored along with their evals.
+	job2 := job.Copy()
+	job2.Meta = map[string]string{"version": "2"}
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1002, nil, job2))
+
+	staleEval := mock.Eval()
+	staleEval.Namespace = job.Namespace
+	staleEval.JobID = job.ID
+
+	stale := triggered.Copy()
+	stale.EvalID = staleEval.ID
+	must.NoError(t, state.UpsertJobDependencyRuns(structs.MsgTypeTestSetup, 1003,
+		[]*structs.JobDependencyRun{stale}, []*structs.Evaluation{staleEval}))
+
+	out, err = state.JobDependencyRunByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobDependencyRunStatusBlocked, out.Status)
+	must.Eq(t, uint64(1002), out.JobModifyIndex)
+
+	outEval, err = state.EvalByID(nil, staleEval.ID)
+	must.NoError(t, err)
+	must.Nil(t, outEval)
+}
+
+func TestStateStore_JobDependencyRun_Restore(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{JobID: "extract"}}
+	job.JobModifyIndex = 1000
+	run := structs.NewJobDependencyRun(job)
+
+	restore, err := state.Restore()
+	must.NoError(t, err)
+
+	err = restore.JobDependencyRunRestore(run)
+	must.NoError(t, err)
+
+	restore.Commit()
+
+	ws := memdb.NewWatchSet()
+	out, err := state.JobDependencyRunByID(ws, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, run, out)
+}
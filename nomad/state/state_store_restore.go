@@ -42,6 +42,22 @@ func (r *StateRestore) NodePoolRestore(pool *structs.NodePool) error {
 	return nil
 }
 
+// JobDependencyRunRestore is used to restore a job dependency run
+func (r *StateRestore) JobDependencyRunRestore(run *structs.JobDependencyRun) error {
+	if err := r.txn.Insert(TableJobDependencyRuns, run); err != nil {
+		return fmt.Errorf("job dependency run insert failed: %v", err)
+	}
+	return nil
+}
+
+// ExecSessionRestore is used to restore an exec session
+func (r *StateRestore) ExecSessionRestore(session *structs.ExecSession) error {
+	if err := r.txn.Insert(TableExecSessions, session); err != nil {
+		return fmt.Errorf("exec session insert failed: %v", err)
+	}
+	return nil
+}
+
 // JobRestore is used to restore a job
 func (r *StateRestore) JobRestore(job *structs.Job) error {
 
@@ -9473,6 +9473,51 @@ func TestStateStore_UpsertACLTokens(t *testing.T) {
 	}
 }
 
+func TestStateStore_UpsertACLTokenUsage(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	tk := mock.ACLToken()
+	must.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{tk}))
+
+	now := time.Now().UTC()
+	must.NoError(t, state.UpsertACLTokenUsage(structs.MsgTypeTestSetup, 1001, []*structs.ACLTokenUsage{
+		{AccessorID: tk.AccessorID, LastUsedTime: now, LastUsedAddress: "10.0.0.1"},
+		{AccessorID: uuid.Generate(), LastUsedTime: now},
+	}))
+
+	out, err := state.ACLTokenByAccessorID(nil, tk.AccessorID)
+	must.NoError(t, err)
+	must.NotNil(t, out.LastUsedTime)
+	must.True(t, now.Equal(*out.LastUsedTime))
+	must.Eq(t, "10.0.0.1", out.LastUsedAddress)
+
+	// Recording usage doesn't modify the token.
+	must.Eq(t, 1000, out.ModifyIndex)
+	index, err := state.Index("acl_token")
+	must.NoError(t, err)
+	must.Eq(t, 1000, index)
+
+	// Older usage is ignored.
+	must.NoError(t, state.UpsertACLTokenUsage(structs.MsgTypeTestSetup, 1002, []*structs.ACLTokenUsage{
+		{AccessorID: tk.AccessorID, LastUsedTime: now.Add(-time.Minute), LastUsedAddress: "10.0.0.2"},
+	}))
+	out, err = state.ACLTokenByAccessorID(nil, tk.AccessorID)
+	must.NoError(t, err)
+	must.Eq(t, "10.0.0.1", out.LastUsedAddress)
+
+	// Updating the token keeps its last use.
+	update := tk.Copy()
+	update.LastUsedTime = nil
+	update.LastUsedAddress = ""
+	update.Name = "updated"
+	must.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1003, []*structs.ACLToken{update}))
+	out, err = state.ACLTokenByAccessorID(nil, tk.AccessorID)
+	must.NoError(t, err)
+	must.Eq(t, "updated", out.Name)
+	must.Eq(t, "10.0.0.1", out.LastUsedAddress)
+}
+
 func TestStateStore_DeleteACLTokens(t *testing.T) {
 	ci.Parallel(t)
 
@@ -359,10 +359,15 @@ func aclAllowsSubscription(aclObj *acl.ACL, subReq *SubscribeRequest) bool {
 			structs.TopicEvaluation,
 			structs.TopicAllocation,
 			structs.TopicJob,
+			structs.TopicJobDependency,
 			structs.TopicService:
 			if ok := aclObj.AllowNsOp(subReq.Namespace, acl.NamespaceCapabilityReadJob); !ok {
 				return false
 			}
+		case structs.TopicExecSession:
+			if ok := aclObj.AllowNsOp(subReq.Namespace, acl.NamespaceCapabilityReadExecRecording); !ok {
+				return false
+			}
 		case structs.TopicNode:
 			if ok := aclObj.AllowNodeRead(); !ok {
 				return false
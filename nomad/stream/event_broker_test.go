@@ -1124,3 +1124,75 @@ func consumeSub(ctx context.Context, sub *Subscription) error {
 		}
 	}
 }
+
+func TestEventBroker_ExecSession_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	testCases := []struct {
+		name        string
+		token       *structs.ACLToken
+		policy      *structs.ACLPolicy
+		expectedErr string
+	}{
+		{
+			name: "management token",
+			token: &structs.ACLToken{
+				AccessorID: uuid.Generate(),
+				SecretID:   uuid.Generate(),
+				Type:       structs.ACLManagementToken,
+			},
+		},
+		{
+			name: "namespace write",
+			token: &structs.ACLToken{
+				AccessorID: uuid.Generate(),
+				SecretID:   uuid.Generate(),
+				Type:       structs.ACLClientToken,
+				Policies:   []string{"namespace-write"},
+			},
+			policy: &structs.ACLPolicy{
+				Name:  "namespace-write",
+				Rules: `namespace "default" { policy = "write" }`,
+			},
+			expectedErr: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			name: "read exec recording",
+			token: &structs.ACLToken{
+				AccessorID: uuid.Generate(),
+				SecretID:   uuid.Generate(),
+				Type:       structs.ACLClientToken,
+				Policies:   []string{"read-exec-recording"},
+			},
+			policy: &structs.ACLPolicy{
+				Name:  "read-exec-recording",
+				Rules: `namespace "default" { capabilities = ["read-exec-recording"] }`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenProvider := &fakeACLTokenProvider{token: tc.token, policy: tc.policy}
+			aclDelegate := &fakeACLDelegate{tokenProvider: tokenProvider}
+
+			publisher, err := NewEventBroker(ctx, aclDelegate, EventBrokerCfg{})
+			must.NoError(t, err)
+
+			_, _, err = publisher.SubscribeWithACLCheck(&SubscribeRequest{
+				Topics:    map[structs.Topic][]string{structs.TopicExecSession: {"*"}},
+				Namespace: structs.DefaultNamespace,
+				Token:     tc.token.SecretID,
+			})
+
+			if tc.expectedErr != "" {
+				must.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				must.NoError(t, err)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-bexpr"
@@ -180,6 +181,20 @@ const (
 	// Args: ACLLoginRequest
 	// Reply: ACLLoginResponse
 	ACLLoginRPCMethod = "ACL.Login"
+
+	// ACLCheckTokenRPCMethod is the RPC method for explaining the effective
+	// permissions of an ACL token or a set of ACL policies.
+	//
+	// Args: ACLTokenCheckRequest
+	// Reply: ACLTokenCheckResponse
+	ACLCheckTokenRPCMethod = "ACL.CheckToken"
+
+	// ACLUpsertTokenUsageRPCMethod is the RPC method servers use to record
+	// when ACL tokens were last used.
+	//
+	// Args: ACLTokenUsageUpsertRequest
+	// Reply: GenericResponse
+	ACLUpsertTokenUsageRPCMethod = "ACL.UpsertTokenUsage"
 )
 
 const (
@@ -213,6 +228,15 @@ const (
 	// ACLAuthMethodTypeJWT the ACLAuthMethod.Type and represents an auth-method
 	// which uses the JWT type.
 	ACLAuthMethodTypeJWT = "JWT"
+
+	// ACLAuthMethodTypeLDAP the ACLAuthMethod.Type and represents an
+	// auth-method which authenticates users against an LDAP directory.
+	ACLAuthMethodTypeLDAP = "LDAP"
+
+	// ACLAuthMethodTypeTLSCert the ACLAuthMethod.Type and represents an
+	// auth-method which authenticates callers using their TLS client
+	// certificate.
+	ACLAuthMethodTypeTLSCert = "TLSCert"
 )
 
 var (
@@ -223,7 +247,9 @@ var (
 	ValidACLAuthMethod = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
 
 	// ValidACLAuthMethodTypes lists supported auth method types.
-	ValidACLAuthMethodTypes = []string{ACLAuthMethodTypeOIDC, ACLAuthMethodTypeJWT}
+	ValidACLAuthMethodTypes = []string{
+		ACLAuthMethodTypeOIDC, ACLAuthMethodTypeJWT, ACLAuthMethodTypeLDAP,
+		ACLAuthMethodTypeTLSCert}
 )
 
 type ACLCacheEntry[T any] lang.Pair[T, time.Time]
@@ -400,6 +426,15 @@ func (a *ACLToken) IsExpired(t time.Time) bool {
 	return a.ExpirationTime.Before(t) || t.IsZero()
 }
 
+// IsUnusedSince returns whether the token was neither used nor created after
+// t.
+func (a *ACLToken) IsUnusedSince(t time.Time) bool {
+	if a.LastUsedTime != nil && a.LastUsedTime.After(t) {
+		return false
+	}
+	return !a.CreateTime.After(t)
+}
+
 // HasRoles checks if a given set of role IDs are assigned to the ACL token. It
 // does not account for management tokens, therefore it is the responsibility
 // of the caller to perform this check, if required.
@@ -469,6 +504,20 @@ func (a *ACLToken) UnmarshalJSON(data []byte) (err error) {
 	return nil
 }
 
+// ACLTokenUsage records the last use of an ACL token.
+type ACLTokenUsage struct {
+	AccessorID      string
+	LastUsedTime    time.Time
+	LastUsedAddress string
+}
+
+// ACLTokenUsageUpsertRequest is used by servers to record the last use of a
+// batch of ACL tokens.
+type ACLTokenUsageUpsertRequest struct {
+	Usage []*ACLTokenUsage
+	WriteRequest
+}
+
 // ACLRole is an abstraction for the ACL system which allows the grouping of
 // ACL policies into a single object. ACL tokens can be created and linked to
 // a role; the token then inherits all the permissions granted by the policies.
@@ -795,6 +844,38 @@ func (a *ACLAuthMethod) SetHash() []byte {
 			_, _ = hash.Write([]byte(k))
 			_, _ = hash.Write([]byte(v))
 		}
+		for _, url := range a.Config.LDAPURLs {
+			_, _ = hash.Write([]byte(url))
+		}
+		_, _ = hash.Write([]byte(strconv.FormatBool(a.Config.LDAPStartTLS)))
+		_, _ = hash.Write([]byte(strconv.FormatBool(a.Config.LDAPInsecureTLS)))
+		_, _ = hash.Write([]byte(a.Config.LDAPCACert))
+		_, _ = hash.Write([]byte(a.Config.LDAPBindDN))
+		_, _ = hash.Write([]byte(a.Config.LDAPBindPassword))
+		_, _ = hash.Write([]byte(a.Config.LDAPUserDN))
+		_, _ = hash.Write([]byte(a.Config.LDAPUserAttr))
+		_, _ = hash.Write([]byte(a.Config.LDAPUserFilter))
+		_, _ = hash.Write([]byte(a.Config.LDAPGroupDN))
+		_, _ = hash.Write([]byte(a.Config.LDAPGroupFilter))
+		_, _ = hash.Write([]byte(a.Config.LDAPGroupAttr))
+		for _, ca := range a.Config.TLSCertCACerts {
+			_, _ = hash.Write([]byte(ca))
+		}
+		for _, cn := range a.Config.TLSCertAllowedCommonNames {
+			_, _ = hash.Write([]byte(cn))
+		}
+		for _, san := range a.Config.TLSCertAllowedDNSSANs {
+			_, _ = hash.Write([]byte(san))
+		}
+		for _, san := range a.Config.TLSCertAllowedEmailSANs {
+			_, _ = hash.Write([]byte(san))
+		}
+		for _, san := range a.Config.TLSCertAllowedURISANs {
+			_, _ = hash.Write([]byte(san))
+		}
+		for _, ext := range a.Config.TLSCertRequiredExtensions {
+			_, _ = hash.Write([]byte(ext))
+		}
 	}
 
 	// Finalize the hash.
@@ -937,6 +1018,28 @@ func (a *ACLAuthMethod) Validate(minTTL, maxTTL time.Duration) error {
 			a.MaxTokenTTL.String(), minTTL.String(), maxTTL.String()))
 	}
 
+	if a.Type == ACLAuthMethodTypeLDAP {
+		if a.Config == nil || len(a.Config.LDAPURLs) == 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("missing LDAP URLs"))
+		}
+		if a.Config == nil || a.Config.LDAPUserDN == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("missing LDAP user DN"))
+		}
+	}
+
+	if a.Type == ACLAuthMethodTypeTLSCert {
+		if a.Config == nil || len(a.Config.TLSCertCACerts) == 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("missing TLS certificate CA certs"))
+		} else {
+			for _, ext := range a.Config.TLSCertRequiredExtensions {
+				if oid, _, ok := strings.Cut(ext, ":"); !ok || oid == "" {
+					mErr.Errors = append(mErr.Errors, fmt.Errorf(
+						"invalid required extension %q, must be of the form <oid>:<value>", ext))
+				}
+			}
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -1003,6 +1106,71 @@ type ACLAuthMethodConfig struct {
 	// (value).
 	ClaimMappings     map[string]string
 	ListClaimMappings map[string]string
+
+	// LDAPURLs is the list of LDAP server URLs to try, in order, when
+	// authenticating users of an LDAP auth method. Both ldap:// and ldaps://
+	// schemes are supported.
+	LDAPURLs []string
+
+	// LDAPStartTLS issues a StartTLS command after connecting to an ldap://
+	// URL.
+	LDAPStartTLS bool
+
+	// LDAPInsecureTLS skips verification of the LDAP server certificate.
+	LDAPInsecureTLS bool
+
+	// PEM encoded CA cert for use by the TLS client used to talk with the
+	// LDAP server.
+	LDAPCACert string
+
+	// LDAPBindDN and LDAPBindPassword are the credentials used to search the
+	// directory for users and groups. An anonymous bind is used when they are
+	// empty.
+	LDAPBindDN       string
+	LDAPBindPassword string
+
+	// LDAPUserDN is the base DN under which to search for users.
+	LDAPUserDN string
+
+	// LDAPUserAttr is the attribute holding the username, defaults to "uid".
+	LDAPUserAttr string
+
+	// LDAPUserFilter is a template used to build the user search filter. It
+	// may reference {{.UserAttr}} and {{.Username}} and defaults to
+	// "({{.UserAttr}}={{.Username}})".
+	LDAPUserFilter string
+
+	// LDAPGroupDN is the base DN under which to search for groups. If empty,
+	// group membership is read from the memberOf attribute of the user.
+	LDAPGroupDN string
+
+	// LDAPGroupFilter is a template used to build the group search filter. It
+	// may reference {{.UserDN}} and {{.Username}} and defaults to
+	// "(|(member={{.UserDN}})(uniqueMember={{.UserDN}}))".
+	LDAPGroupFilter string
+
+	// LDAPGroupAttr is the attribute of a group entry holding its name,
+	// defaults to "cn".
+	LDAPGroupAttr string
+
+	// TLSCertCACerts is the list of PEM encoded CA certs client certificates
+	// must chain to when using a TLSCert auth method.
+	TLSCertCACerts []string
+
+	// TLSCertAllowedCommonNames, TLSCertAllowedDNSSANs,
+	// TLSCertAllowedEmailSANs and TLSCertAllowedURISANs restrict the client
+	// certificates accepted by a TLSCert auth method. Each entry is a glob
+	// pattern, and when a list is set at least one of the corresponding
+	// certificate values must match one of its patterns.
+	TLSCertAllowedCommonNames []string
+	TLSCertAllowedDNSSANs     []string
+	TLSCertAllowedEmailSANs   []string
+	TLSCertAllowedURISANs     []string
+
+	// TLSCertRequiredExtensions lists extensions the client certificate must
+	// carry, in the form "<oid>:<glob>". The extension value is decoded as an
+	// ASN.1 string before being matched.
+	TLSCertRequiredExtensions []string
 }
 
 func (a *ACLAuthMethodConfig) Copy() *ACLAuthMethodConfig {
@@ -1020,6 +1188,13 @@ func (a *ACLAuthMethodConfig) Copy() *ACLAuthMethodConfig {
 	c.AllowedRedirectURIs = slices.Clone(a.AllowedRedirectURIs)
 	c.DiscoveryCaPem = slices.Clone(a.DiscoveryCaPem)
 	c.SigningAlgs = slices.Clone(a.SigningAlgs)
+	c.LDAPURLs = slices.Clone(a.LDAPURLs)
+	c.TLSCertCACerts = slices.Clone(a.TLSCertCACerts)
+	c.TLSCertAllowedCommonNames = slices.Clone(a.TLSCertAllowedCommonNames)
+	c.TLSCertAllowedDNSSANs = slices.Clone(a.TLSCertAllowedDNSSANs)
+	c.TLSCertAllowedEmailSANs = slices.Clone(a.TLSCertAllowedEmailSANs)
+	c.TLSCertAllowedURISANs = slices.Clone(a.TLSCertAllowedURISANs)
+	c.TLSCertRequiredExtensions = slices.Clone(a.TLSCertRequiredExtensions)
 
 	return c
 }
@@ -1616,9 +1791,21 @@ type ACLLoginRequest struct {
 	AuthMethodName string
 
 	// LoginToken is the 3rd party token that we use to exchange for Nomad ACL
-	// Token in order to authenticate. This is a required parameter.
+	// Token in order to authenticate. This is a required parameter. For LDAP
+	// auth methods this is the password of the user.
 	LoginToken string
 
+	// Username is the name of the user logging in. It is only used, and
+	// required, by LDAP auth methods.
+	Username string
+
+	// ClientCertificates is the DER encoded certificate chain presented by
+	// the caller during the TLS handshake with the HTTP API, leaf first. It is
+	// set by the HTTP agent and never decoded from the request body, and is
+	// only trusted by servers when the RPC was made by a Nomad agent. It is
+	// only used by TLSCert auth methods.
+	ClientCertificates [][]byte `json:"-"`
+
 	WriteRequest
 }
 
@@ -1631,8 +1818,8 @@ func (a *ACLLoginRequest) Validate() error {
 	if a.AuthMethodName == "" {
 		mErr.Errors = append(mErr.Errors, errors.New("missing auth method name"))
 	}
-	if a.LoginToken == "" {
-		mErr.Errors = append(mErr.Errors, errors.New("missing login token"))
+	if a.LoginToken == "" && len(a.ClientCertificates) == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("missing login token or client certificate"))
 	}
 	return mErr.ErrorOrNil()
 }
@@ -1024,6 +1024,36 @@ func TestACLAuthMethod_Validate(t *testing.T) {
 		{"invalid token locality", &ACLAuthMethod{TokenLocality: "regional"}, true, "invalid token locality"},
 		{"invalid type", &ACLAuthMethod{Type: "groovy"}, true, "invalid token type"},
 		{"invalid max ttl", &ACLAuthMethod{MaxTokenTTL: badTTL}, true, "invalid token type"},
+		{
+			"valid ldap method",
+			&ACLAuthMethod{
+				Name:          "mock-auth-method",
+				Type:          "LDAP",
+				TokenLocality: "local",
+				MaxTokenTTL:   goodTTL,
+				Config: &ACLAuthMethodConfig{
+					LDAPURLs:   []string{"ldaps://ldap.example.com"},
+					LDAPUserDN: "ou=people,dc=example,dc=org",
+				},
+			},
+			false,
+			"",
+		},
+		{"ldap missing urls", &ACLAuthMethod{Type: "LDAP", Config: &ACLAuthMethodConfig{}}, true, "missing LDAP URLs"},
+		{"ldap missing user dn", &ACLAuthMethod{Type: "LDAP"}, true, "missing LDAP user DN"},
+		{"tls cert missing ca certs", &ACLAuthMethod{Type: "TLSCert"}, true, "missing TLS certificate CA certs"},
+		{
+			"tls cert invalid required extension",
+			&ACLAuthMethod{
+				Type: "TLSCert",
+				Config: &ACLAuthMethodConfig{
+					TLSCertCACerts:            []string{"ca"},
+					TLSCertRequiredExtensions: []string{"1.2.3.4"},
+				},
+			},
+			true,
+			"invalid required extension",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -10,6 +10,14 @@ const (
 	// Args: AllocServiceRegistrationsRequest
 	// Reply: AllocServiceRegistrationsResponse
 	AllocServiceRegistrationsRPCMethod = "Alloc.GetServiceRegistrations"
+
+	// AllocSignIdentitiesRPCMethod is the RPC method for signing the
+	// workload identities of tasks. It is only callable by the client
+	// running the allocations.
+	//
+	// Args: AllocIdentitiesRequest
+	// Reply: AllocIdentitiesResponse
+	AllocSignIdentitiesRPCMethod = "Alloc.SignIdentities"
 )
 
 // AllocServiceRegistrationsRequest is the request object used to list all
@@ -26,6 +34,20 @@ type AllocServiceRegistrationsResponse struct {
 	QueryMeta
 }
 
+// AllocIdentitiesRequest is the request object used to sign the workload
+// identities of tasks.
+type AllocIdentitiesRequest struct {
+	Identities []*WorkloadIdentityRequest
+	QueryOptions
+}
+
+// AllocIdentitiesResponse is the response object when signing workload
+// identities.
+type AllocIdentitiesResponse struct {
+	SignedIdentities []*SignedWorkloadIdentity
+	QueryMeta
+}
+
 // ServiceProviderNamespace returns the namespace within which the allocations
 // services should be registered. This takes into account the different
 // providers that can provide service registrations. In the event no services
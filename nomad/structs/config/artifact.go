@@ -5,11 +5,13 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"math"
 	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/open-wander/wander/helper/pointer"
+	"github.com/open-wander/wander/helper/signature"
 )
 
 // ArtifactConfig is the configuration specific to the Artifact block
@@ -59,6 +61,25 @@ type ArtifactConfig struct {
 	// variable names to inherit from the Nomad Client and set in the artifact
 	// download sandbox process.
 	SetEnvironmentVariables *string `hcl:"set_environment_variables"`
+
+	// CacheSize is the maximum size of the artifact cache shared by the
+	// allocations of the Client. Artifacts with a checksum are downloaded
+	// once and linked into the task directories, and the least recently used
+	// artifacts are evicted once the cache is full.
+	//
+	// Default is 0, which disables the cache.
+	CacheSize *string `hcl:"cache_size"`
+
+	// CacheDir is the directory of the artifact cache. It should be on the
+	// same filesystem as the allocation directories so that artifacts can be
+	// linked rather than copied.
+	//
+	// Default is the "artifact_cache" directory of the Client state directory.
+	CacheDir *string `hcl:"cache_dir"`
+
+	// TrustedKeys are the named public keys trusted to sign artifacts, either
+	// minisign public keys or PEM encoded public keys for cosign signatures.
+	TrustedKeys map[string]string `hcl:"trusted_keys"`
 }
 
 func (a *ArtifactConfig) Copy() *ArtifactConfig {
@@ -76,6 +97,9 @@ func (a *ArtifactConfig) Copy() *ArtifactConfig {
 		DecompressionSizeLimit:      pointer.Copy(a.DecompressionSizeLimit),
 		DisableFilesystemIsolation:  pointer.Copy(a.DisableFilesystemIsolation),
 		SetEnvironmentVariables:     pointer.Copy(a.SetEnvironmentVariables),
+		CacheSize:                   pointer.Copy(a.CacheSize),
+		CacheDir:                    pointer.Copy(a.CacheDir),
+		TrustedKeys:                 maps.Clone(a.TrustedKeys),
 	}
 }
 
@@ -86,6 +110,14 @@ func (a *ArtifactConfig) Merge(o *ArtifactConfig) *ArtifactConfig {
 	case o == nil:
 		return a.Copy()
 	default:
+		trustedKeys := maps.Clone(a.TrustedKeys)
+		if len(o.TrustedKeys) > 0 {
+			if trustedKeys == nil {
+				trustedKeys = make(map[string]string, len(o.TrustedKeys))
+			}
+			maps.Copy(trustedKeys, o.TrustedKeys)
+		}
+
 		return &ArtifactConfig{
 			HTTPReadTimeout:             pointer.Merge(a.HTTPReadTimeout, o.HTTPReadTimeout),
 			HTTPMaxSize:                 pointer.Merge(a.HTTPMaxSize, o.HTTPMaxSize),
@@ -97,6 +129,9 @@ func (a *ArtifactConfig) Merge(o *ArtifactConfig) *ArtifactConfig {
 			DecompressionSizeLimit:      pointer.Merge(a.DecompressionSizeLimit, o.DecompressionSizeLimit),
 			DisableFilesystemIsolation:  pointer.Merge(a.DisableFilesystemIsolation, o.DisableFilesystemIsolation),
 			SetEnvironmentVariables:     pointer.Merge(a.SetEnvironmentVariables, o.SetEnvironmentVariables),
+			CacheSize:                   pointer.Merge(a.CacheSize, o.CacheSize),
+			CacheDir:                    pointer.Merge(a.CacheDir, o.CacheDir),
+			TrustedKeys:                 trustedKeys,
 		}
 	}
 }
@@ -126,6 +161,12 @@ func (a *ArtifactConfig) Equal(o *ArtifactConfig) bool {
 		return false
 	case !pointer.Eq(a.SetEnvironmentVariables, o.SetEnvironmentVariables):
 		return false
+	case !pointer.Eq(a.CacheSize, o.CacheSize):
+		return false
+	case !pointer.Eq(a.CacheDir, o.CacheDir):
+		return false
+	case !maps.Equal(a.TrustedKeys, o.TrustedKeys):
+		return false
 	}
 	return true
 }
@@ -213,6 +254,23 @@ func (a *ArtifactConfig) Validate() error {
 		return fmt.Errorf("set_environment_variables must be set")
 	}
 
+	if a.CacheSize == nil {
+		return fmt.Errorf("cache_size must be set")
+	}
+	if v, err := humanize.ParseBytes(*a.CacheSize); err != nil {
+		return fmt.Errorf("cache_size is not a valid size: %w", err)
+	} else if v > math.MaxInt64 {
+		return fmt.Errorf("cache_size must be < %d but found %d", int64(math.MaxInt64), v)
+	}
+
+	if a.CacheDir == nil {
+		return fmt.Errorf("cache_dir must be set")
+	}
+
+	if _, err := signature.ParseKeys(a.TrustedKeys); err != nil {
+		return fmt.Errorf("trusted_keys is not valid: %w", err)
+	}
+
 	return nil
 }
 
@@ -256,5 +314,11 @@ func DefaultArtifactConfig() *ArtifactConfig {
 
 		// No environment variables are inherited from Client by default.
 		SetEnvironmentVariables: pointer.Of(""),
+
+		// The artifact cache is disabled by default.
+		CacheSize: pointer.Of("0"),
+
+		// The cache directory defaults to the Client state directory.
+		CacheDir: pointer.Of(""),
 	}
 }
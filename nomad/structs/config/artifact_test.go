@@ -50,6 +50,9 @@ func TestArtifactConfig_Merge(t *testing.T) {
 				DecompressionSizeLimit:      pointer.Of("100GB"),
 				DisableFilesystemIsolation:  pointer.Of(false),
 				SetEnvironmentVariables:     pointer.Of(""),
+				CacheSize:                   pointer.Of("0"),
+				CacheDir:                    pointer.Of(""),
+				TrustedKeys:                 map[string]string{"build": "key1", "release": "key2"},
 			},
 			other: &ArtifactConfig{
 				HTTPReadTimeout:             pointer.Of("5m"),
@@ -62,6 +65,9 @@ func TestArtifactConfig_Merge(t *testing.T) {
 				DecompressionSizeLimit:      pointer.Of("8GB"),
 				DisableFilesystemIsolation:  pointer.Of(true),
 				SetEnvironmentVariables:     pointer.Of("FOO,BAR"),
+				CacheSize:                   pointer.Of("10GB"),
+				CacheDir:                    pointer.Of("/var/cache/nomad"),
+				TrustedKeys:                 map[string]string{"release": "key3"},
 			},
 			expected: &ArtifactConfig{
 				HTTPReadTimeout:             pointer.Of("5m"),
@@ -74,6 +80,9 @@ func TestArtifactConfig_Merge(t *testing.T) {
 				DecompressionSizeLimit:      pointer.Of("8GB"),
 				DisableFilesystemIsolation:  pointer.Of(true),
 				SetEnvironmentVariables:     pointer.Of("FOO,BAR"),
+				CacheSize:                   pointer.Of("10GB"),
+				CacheDir:                    pointer.Of("/var/cache/nomad"),
+				TrustedKeys:                 map[string]string{"build": "key1", "release": "key3"},
 			},
 		},
 		{
@@ -407,6 +416,34 @@ func TestArtifactConfig_Validate(t *testing.T) {
 			},
 			expErr: "set_environment_variables must be set",
 		},
+		{
+			name: "cache size not set",
+			config: func(a *ArtifactConfig) {
+				a.CacheSize = nil
+			},
+			expErr: "cache_size must be set",
+		},
+		{
+			name: "cache size is invalid",
+			config: func(a *ArtifactConfig) {
+				a.CacheSize = pointer.Of("lots")
+			},
+			expErr: "cache_size is not a valid size",
+		},
+		{
+			name: "cache dir not set",
+			config: func(a *ArtifactConfig) {
+				a.CacheDir = nil
+			},
+			expErr: "cache_dir must be set",
+		},
+		{
+			name: "trusted keys are invalid",
+			config: func(a *ArtifactConfig) {
+				a.TrustedKeys = map[string]string{"build": "not a key"}
+			},
+			expErr: `trusted_keys is not valid: key "build"`,
+		},
 	}
 
 	for _, tc := range testCases {
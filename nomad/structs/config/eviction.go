@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import "github.com/open-wander/wander/helper/pointer"
+
+// EvictionConfig describes when a Node running low on resources evicts its
+// allocations.
+type EvictionConfig struct {
+	// Interval is the duration between checks of the resource usage.
+	Interval *string `hcl:"interval"`
+
+	// MemoryThreshold is the percentage of used memory above which the node
+	// is under memory pressure.
+	MemoryThreshold *float64 `hcl:"memory_threshold"`
+
+	// DiskThreshold is the percentage of used disk space in the alloc dir
+	// above which the node is under disk pressure.
+	DiskThreshold *float64 `hcl:"disk_threshold"`
+
+	// InodeThreshold is the percentage of used inodes in the alloc dir above
+	// which the node is under inode pressure.
+	InodeThreshold *float64 `hcl:"inode_threshold"`
+}
+
+func (e *EvictionConfig) Copy() *EvictionConfig {
+	if e == nil {
+		return nil
+	}
+
+	ne := new(EvictionConfig)
+	*ne = *e
+	return ne
+}
+
+func (e *EvictionConfig) Merge(o *EvictionConfig) *EvictionConfig {
+	switch {
+	case e == nil:
+		return o.Copy()
+	case o == nil:
+		return e.Copy()
+	default:
+		ne := e.Copy()
+		if o.Interval != nil {
+			ne.Interval = pointer.Copy(o.Interval)
+		}
+		if o.MemoryThreshold != nil {
+			ne.MemoryThreshold = pointer.Copy(o.MemoryThreshold)
+		}
+		if o.DiskThreshold != nil {
+			ne.DiskThreshold = pointer.Copy(o.DiskThreshold)
+		}
+		if o.InodeThreshold != nil {
+			ne.InodeThreshold = pointer.Copy(o.InodeThreshold)
+		}
+		return ne
+	}
+}
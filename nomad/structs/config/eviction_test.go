@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestEvictionConfig_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Nil(t, (*EvictionConfig)(nil).Copy())
+
+	input := &EvictionConfig{
+		Interval:        pointer.Of("5s"),
+		MemoryThreshold: pointer.Of(90.0),
+	}
+	output := input.Copy()
+	must.Eq(t, input, output)
+	must.NotEq(t, fmt.Sprintf("%p", input), fmt.Sprintf("%p", output))
+}
+
+func TestEvictionConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name           string
+		input          *EvictionConfig
+		merge          *EvictionConfig
+		expectedOutput *EvictionConfig
+	}{
+		{
+			name:           "nil",
+			input:          nil,
+			merge:          nil,
+			expectedOutput: nil,
+		},
+		{
+			name:  "nil input",
+			input: nil,
+			merge: &EvictionConfig{
+				Interval:      pointer.Of("5s"),
+				DiskThreshold: pointer.Of(90.0),
+			},
+			expectedOutput: &EvictionConfig{
+				Interval:      pointer.Of("5s"),
+				DiskThreshold: pointer.Of(90.0),
+			},
+		},
+		{
+			name: "nil merge",
+			input: &EvictionConfig{
+				InodeThreshold: pointer.Of(80.0),
+			},
+			merge: nil,
+			expectedOutput: &EvictionConfig{
+				InodeThreshold: pointer.Of(80.0),
+			},
+		},
+		{
+			name: "partial",
+			input: &EvictionConfig{
+				Interval:        pointer.Of("5s"),
+				MemoryThreshold: pointer.Of(90.0),
+				DiskThreshold:   pointer.Of(90.0),
+			},
+			merge: &EvictionConfig{
+				MemoryThreshold: pointer.Of(85.0),
+				InodeThreshold:  pointer.Of(80.0),
+			},
+			expectedOutput: &EvictionConfig{
+				Interval:        pointer.Of("5s"),
+				MemoryThreshold: pointer.Of(85.0),
+				DiskThreshold:   pointer.Of(90.0),
+				InodeThreshold:  pointer.Of(80.0),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.expectedOutput, tc.input.Merge(tc.merge))
+		})
+	}
+}
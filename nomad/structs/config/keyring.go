@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	// KEKProviderAEAD wraps root keys with a key encryption key (KEK) that is
+	// generated for each root key and stored next to it in the keystore.
+	KEKProviderAEAD = "aead"
+
+	// KEKProviderVaultTransit wraps root keys with a Vault Transit key.
+	KEKProviderVaultTransit = "transit"
+
+	// KEKProviderAWSKMS wraps root keys with an AWS KMS key.
+	KEKProviderAWSKMS = "awskms"
+
+	// KEKProviderGCPCloudKMS wraps root keys with a Google Cloud KMS key.
+	KEKProviderGCPCloudKMS = "gcpckms"
+
+	// KEKProviderAzureKeyVault wraps root keys with an Azure Key Vault key.
+	KEKProviderAzureKeyVault = "azurekeyvault"
+
+	// KEKProviderPKCS11 would wrap root keys with a key of a PKCS#11 HSM. It
+	// isn't supported because go-kms-wrapping has no open source PKCS#11
+	// wrapper, and is only rejected with a clearer error.
+	KEKProviderPKCS11 = "pkcs11"
+)
+
+// KEKProviders are the supported key encryption key providers.
+var KEKProviders = []string{
+	KEKProviderAEAD,
+	KEKProviderVaultTransit,
+	KEKProviderAWSKMS,
+	KEKProviderGCPCloudKMS,
+	KEKProviderAzureKeyVault,
+}
+
+// KEKProviderConfig configures a provider of the key encryption key (KEK)
+// used by a server to wrap the root keys it stores in its keystore.
+type KEKProviderConfig struct {
+	// Provider is the name of the provider, such as "transit" or "awskms".
+	Provider string `hcl:",key"`
+
+	// Name distinguishes several configurations of the same provider.
+	Name string `hcl:"name"`
+
+	// Active marks the provider used to unwrap root keys when the keystore
+	// is loaded. Root keys are wrapped with every configured provider.
+	Active bool `hcl:"active"`
+
+	// Config is the provider specific configuration passed to the
+	// go-kms-wrapping wrapper, such as the address and key name of a Vault
+	// Transit key.
+	Config map[string]string `hcl:"config"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+// DefaultKEKProviderConfig returns the provider used when none is configured,
+// which keeps the key encryption keys in the keystore.
+func DefaultKEKProviderConfig() *KEKProviderConfig {
+	return &KEKProviderConfig{
+		Provider: KEKProviderAEAD,
+		Active:   true,
+	}
+}
+
+// ID uniquely identifies the provider configuration. It's used to name the
+// keystore files wrapped by the provider.
+func (k *KEKProviderConfig) ID() string {
+	if k.Name == "" {
+		return k.Provider
+	}
+	return k.Provider + "." + k.Name
+}
+
+func (k *KEKProviderConfig) Copy() *KEKProviderConfig {
+	if k == nil {
+		return nil
+	}
+
+	nk := *k
+	nk.Config = maps.Clone(k.Config)
+	nk.ExtraKeysHCL = slices.Clone(k.ExtraKeysHCL)
+	return &nk
+}
+
+func (k *KEKProviderConfig) Merge(o *KEKProviderConfig) *KEKProviderConfig {
+	switch {
+	case k == nil:
+		return o.Copy()
+	case o == nil:
+		return k.Copy()
+	default:
+		nk := k.Copy()
+		nk.Active = o.Active
+		if nk.Config == nil && len(o.Config) > 0 {
+			nk.Config = make(map[string]string, len(o.Config))
+		}
+		for key, v := range o.Config {
+			nk.Config[key] = v
+		}
+		return nk
+	}
+}
+
+func (k *KEKProviderConfig) Validate() error {
+	if k.Provider == KEKProviderPKCS11 {
+		return fmt.Errorf("keyring provider %q is not supported: use the %q provider with a Vault Transit key backed by the HSM instead",
+			k.Provider, KEKProviderVaultTransit)
+	}
+	if !slices.Contains(KEKProviders, k.Provider) {
+		return fmt.Errorf("invalid keyring provider %q: must be one of %s",
+			k.Provider, strings.Join(KEKProviders, ", "))
+	}
+	if strings.ContainsAny(k.Name, "./\\") {
+		return fmt.Errorf("invalid keyring %q name %q: must not contain '.' or path separators",
+			k.Provider, k.Name)
+	}
+	if k.Provider == KEKProviderAEAD && len(k.Config) > 0 {
+		return fmt.Errorf("keyring %q does not accept a config block", k.ID())
+	}
+	return nil
+}
+
+// KEKProviderConfigSetMerge merges two sets of key encryption key provider
+// configs. Configs with the same ID are merged.
+func KEKProviderConfigSetMerge(first, second []*KEKProviderConfig) []*KEKProviderConfig {
+	out := make([]*KEKProviderConfig, 0, len(first)+len(second))
+	index := make(map[string]int, len(first)+len(second))
+
+	for _, set := range [][]*KEKProviderConfig{first, second} {
+		for _, k := range set {
+			if i, ok := index[k.ID()]; ok {
+				out[i] = out[i].Merge(k)
+				continue
+			}
+			index[k.ID()] = len(out)
+			out = append(out, k.Copy())
+		}
+	}
+
+	return out
+}
+
+// ValidateKEKProviderConfigs validates a set of key encryption key provider
+// configs. Exactly one of the configs must be active, and IDs must be unique.
+func ValidateKEKProviderConfigs(providers []*KEKProviderConfig) error {
+	var active int
+	seen := make(map[string]struct{}, len(providers))
+	for _, k := range providers {
+		if err := k.Validate(); err != nil {
+			return err
+		}
+		if _, ok := seen[k.ID()]; ok {
+			return fmt.Errorf("duplicate keyring %q", k.ID())
+		}
+		seen[k.ID()] = struct{}{}
+		if k.Active {
+			active++
+		}
+	}
+	if len(providers) > 0 && active != 1 {
+		return fmt.Errorf("exactly one keyring must be active, found %d", active)
+	}
+	return nil
+}
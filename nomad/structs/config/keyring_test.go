@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestKEKProviderConfigSetMerge(t *testing.T) {
+	ci.Parallel(t)
+
+	first := []*KEKProviderConfig{
+		{Provider: KEKProviderAEAD, Active: true},
+		{Provider: KEKProviderVaultTransit, Config: map[string]string{
+			"address": "https://vault.example.com:8200", "key_name": "nomad"}},
+	}
+	second := []*KEKProviderConfig{
+		{Provider: KEKProviderAEAD},
+		{Provider: KEKProviderVaultTransit, Active: true, Config: map[string]string{
+			"key_name": "nomad-keyring"}},
+		{Provider: KEKProviderVaultTransit, Name: "dr"},
+	}
+
+	out := KEKProviderConfigSetMerge(first, second)
+	must.Eq(t, []*KEKProviderConfig{
+		{Provider: KEKProviderAEAD},
+		{Provider: KEKProviderVaultTransit, Active: true, Config: map[string]string{
+			"address": "https://vault.example.com:8200", "key_name": "nomad-keyring"}},
+		{Provider: KEKProviderVaultTransit, Name: "dr"},
+	}, out)
+	must.Eq(t, "transit.dr", out[2].ID())
+
+	// The inputs are not modified.
+	must.True(t, first[0].Active)
+	must.Eq(t, "nomad", first[1].Config["key_name"])
+}
+
+func TestValidateKEKProviderConfigs(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name      string
+		providers []*KEKProviderConfig
+		expErr    string
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "valid",
+			providers: []*KEKProviderConfig{
+				{Provider: KEKProviderAEAD},
+				{Provider: KEKProviderAWSKMS, Active: true},
+			},
+		},
+		{
+			name: "unknown provider",
+			providers: []*KEKProviderConfig{
+				{Provider: "hsm", Active: true},
+			},
+			expErr: `invalid keyring provider "hsm"`,
+		},
+		{
+			name: "pkcs11",
+			providers: []*KEKProviderConfig{
+				{Provider: KEKProviderPKCS11, Active: true},
+			},
+			expErr: `keyring provider "pkcs11" is not supported`,
+		},
+		{
+			name: "invalid name",
+			providers: []*KEKProviderConfig{
+				{Provider: KEKProviderAWSKMS, Name: "a.b", Active: true},
+			},
+			expErr: `invalid keyring "awskms" name "a.b"`,
+		},
+		{
+			name: "aead config",
+			providers: []*KEKProviderConfig{
+				{Provider: KEKProviderAEAD, Active: true, Config: map[string]string{"key": "x"}},
+			},
+			expErr: `keyring "aead" does not accept a config block`,
+		},
+		{
+			name: "duplicate",
+			providers: []*KEKProviderConfig{
+				{Provider: KEKProviderGCPCloudKMS, Active: true},
+				{Provider: KEKProviderGCPCloudKMS},
+			},
+			expErr: `duplicate keyring "gcpckms"`,
+		},
+		{
+			name: "no active",
+			providers: []*KEKProviderConfig{
+				{Provider: KEKProviderAEAD},
+			},
+			expErr: "exactly one keyring must be active, found 0",
+		},
+		{
+			name: "several active",
+			providers: []*KEKProviderConfig{
+				{Provider: KEKProviderAEAD, Active: true},
+				{Provider: KEKProviderAzureKeyVault, Active: true},
+			},
+			expErr: "exactly one keyring must be active, found 2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateKEKProviderConfigs(tc.providers)
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
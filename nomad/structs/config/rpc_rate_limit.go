@@ -0,0 +1,243 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/open-wander/wander/helper"
+	"github.com/open-wander/wander/helper/pointer"
+)
+
+// RPCRateLimitWildcard is the name of the namespace and endpoint rate limits
+// applying to the namespaces and endpoints without their own limit.
+const RPCRateLimitWildcard = "*"
+
+// RPCRateLimitConfig configures the rate limits servers enforce on the RPC
+// requests they handle. Requests exceeding a limit are rejected with a 429
+// error.
+type RPCRateLimitConfig struct {
+	// Token limits the requests made with each ACL token, or from each
+	// remote address when ACLs are disabled.
+	Token *RPCRateLimit `hcl:"token"`
+
+	// Namespaces limit the requests targeting each namespace, across all
+	// tokens.
+	Namespaces []*NamedRPCRateLimit `hcl:"namespace"`
+
+	// Endpoints limit the requests made to each RPC endpoint, such as
+	// "Alloc.List", across all tokens.
+	Endpoints []*NamedRPCRateLimit `hcl:"endpoint"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+// RPCRateLimit is the number of read and write requests per second allowed
+// for a scope. A limit also allows bursts of the same number of requests. An
+// unset limit does not restrict requests.
+type RPCRateLimit struct {
+	// Read limits the requests which only read state, including blocking
+	// queries.
+	Read *int `hcl:"read"`
+
+	// Write limits the requests which modify state.
+	Write *int `hcl:"write"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+// NamedRPCRateLimit is the rate limit of a namespace or endpoint.
+type NamedRPCRateLimit struct {
+	// Name is the namespace or endpoint the limit applies to, or the
+	// wildcard.
+	Name string `hcl:",key"`
+
+	RPCRateLimit `hcl:",squash"`
+}
+
+func (r *RPCRateLimitConfig) Copy() *RPCRateLimitConfig {
+	if r == nil {
+		return nil
+	}
+
+	nr := *r
+	nr.Token = r.Token.Copy()
+	nr.Namespaces = helper.CopySlice(r.Namespaces)
+	nr.Endpoints = helper.CopySlice(r.Endpoints)
+	nr.ExtraKeysHCL = slices.Clone(r.ExtraKeysHCL)
+	return &nr
+}
+
+func (r *RPCRateLimitConfig) Merge(o *RPCRateLimitConfig) *RPCRateLimitConfig {
+	switch {
+	case r == nil:
+		return o.Copy()
+	case o == nil:
+		return r.Copy()
+	default:
+		nr := r.Copy()
+		nr.Token = nr.Token.Merge(o.Token)
+		nr.Namespaces = RPCRateLimitSetMerge(r.Namespaces, o.Namespaces)
+		nr.Endpoints = RPCRateLimitSetMerge(r.Endpoints, o.Endpoints)
+		return nr
+	}
+}
+
+// Validate returns an error if any of the limits is not positive.
+func (r *RPCRateLimitConfig) Validate() error {
+	if r == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if err := r.Token.validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("token: %w", err))
+	}
+	for _, l := range r.Namespaces {
+		if err := l.RPCRateLimit.validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("namespace %q: %w", l.Name, err))
+		}
+	}
+	for _, l := range r.Endpoints {
+		if err := l.RPCRateLimit.validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("endpoint %q: %w", l.Name, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// NamespaceLimit returns the limit applying to the namespace, if any.
+func (r *RPCRateLimitConfig) NamespaceLimit(namespace string) *RPCRateLimit {
+	return findRPCRateLimit(r.Namespaces, namespace)
+}
+
+// EndpointLimit returns the limit applying to the RPC endpoint, if any.
+func (r *RPCRateLimitConfig) EndpointLimit(endpoint string) *RPCRateLimit {
+	return findRPCRateLimit(r.Endpoints, endpoint)
+}
+
+// findRPCRateLimit returns the limit with the name, or the wildcard limit
+// if there is none.
+func findRPCRateLimit(limits []*NamedRPCRateLimit, name string) *RPCRateLimit {
+	var wildcard *RPCRateLimit
+	for _, l := range limits {
+		switch l.Name {
+		case name:
+			return &l.RPCRateLimit
+		case RPCRateLimitWildcard:
+			wildcard = &l.RPCRateLimit
+		}
+	}
+	return wildcard
+}
+
+func (r *RPCRateLimit) Copy() *RPCRateLimit {
+	if r == nil {
+		return nil
+	}
+
+	nr := *r
+	nr.Read = pointer.Copy(r.Read)
+	nr.Write = pointer.Copy(r.Write)
+	nr.ExtraKeysHCL = slices.Clone(r.ExtraKeysHCL)
+	return &nr
+}
+
+func (r *RPCRateLimit) Merge(o *RPCRateLimit) *RPCRateLimit {
+	switch {
+	case r == nil:
+		return o.Copy()
+	case o == nil:
+		return r.Copy()
+	default:
+		nr := r.Copy()
+		if o.Read != nil {
+			nr.Read = pointer.Copy(o.Read)
+		}
+		if o.Write != nil {
+			nr.Write = pointer.Copy(o.Write)
+		}
+		return nr
+	}
+}
+
+func (r *NamedRPCRateLimit) Copy() *NamedRPCRateLimit {
+	if r == nil {
+		return nil
+	}
+
+	return &NamedRPCRateLimit{
+		Name:         r.Name,
+		RPCRateLimit: *r.RPCRateLimit.Copy(),
+	}
+}
+
+func (r *NamedRPCRateLimit) Merge(o *NamedRPCRateLimit) *NamedRPCRateLimit {
+	switch {
+	case r == nil:
+		return o.Copy()
+	case o == nil:
+		return r.Copy()
+	default:
+		return &NamedRPCRateLimit{
+			Name:         r.Name,
+			RPCRateLimit: *r.RPCRateLimit.Merge(&o.RPCRateLimit),
+		}
+	}
+}
+
+// Limit returns the number of requests per second allowed for reads or
+// writes, and whether that number is limited at all.
+func (r *RPCRateLimit) Limit(write bool) (int, bool) {
+	if r == nil {
+		return 0, false
+	}
+	limit := r.Read
+	if write {
+		limit = r.Write
+	}
+	if limit == nil {
+		return 0, false
+	}
+	return *limit, true
+}
+
+func (r *RPCRateLimit) validate() error {
+	if r == nil {
+		return nil
+	}
+	if r.Read != nil && *r.Read <= 0 {
+		return fmt.Errorf("read limit must be greater than 0")
+	}
+	if r.Write != nil && *r.Write <= 0 {
+		return fmt.Errorf("write limit must be greater than 0")
+	}
+	return nil
+}
+
+// RPCRateLimitSetMerge merges two sets of named rate limits. Limits with the
+// same name are merged and the order in which the limits are first defined is
+// kept.
+func RPCRateLimitSetMerge(first, second []*NamedRPCRateLimit) []*NamedRPCRateLimit {
+	out := make([]*NamedRPCRateLimit, 0, len(first)+len(second))
+	index := make(map[string]int, len(first)+len(second))
+
+	for _, set := range [][]*NamedRPCRateLimit{first, second} {
+		for _, l := range set {
+			if i, ok := index[l.Name]; ok {
+				out[i] = out[i].Merge(l)
+				continue
+			}
+			index[l.Name] = len(out)
+			out = append(out, l.Copy())
+		}
+	}
+
+	return out
+}
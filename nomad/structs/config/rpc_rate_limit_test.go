@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestRPCRateLimitConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := &RPCRateLimitConfig{
+		Token: &RPCRateLimit{Read: pointer.Of(100)},
+		Namespaces: []*NamedRPCRateLimit{
+			{Name: "prod", RPCRateLimit: RPCRateLimit{Read: pointer.Of(50), Write: pointer.Of(5)}},
+		},
+	}
+	b := &RPCRateLimitConfig{
+		Token: &RPCRateLimit{Write: pointer.Of(10)},
+		Namespaces: []*NamedRPCRateLimit{
+			{Name: "prod", RPCRateLimit: RPCRateLimit{Write: pointer.Of(20)}},
+			{Name: "*", RPCRateLimit: RPCRateLimit{Read: pointer.Of(500)}},
+		},
+		Endpoints: []*NamedRPCRateLimit{
+			{Name: "Alloc.List", RPCRateLimit: RPCRateLimit{Read: pointer.Of(1)}},
+		},
+	}
+
+	must.Eq(t, a, a.Merge(nil))
+	must.Eq(t, a, (*RPCRateLimitConfig)(nil).Merge(a))
+	must.Eq(t, &RPCRateLimitConfig{
+		Token: &RPCRateLimit{Read: pointer.Of(100), Write: pointer.Of(10)},
+		Namespaces: []*NamedRPCRateLimit{
+			{Name: "prod", RPCRateLimit: RPCRateLimit{Read: pointer.Of(50), Write: pointer.Of(20)}},
+			{Name: "*", RPCRateLimit: RPCRateLimit{Read: pointer.Of(500)}},
+		},
+		Endpoints: []*NamedRPCRateLimit{
+			{Name: "Alloc.List", RPCRateLimit: RPCRateLimit{Read: pointer.Of(1)}},
+		},
+	}, a.Merge(b))
+
+	// The inputs are not modified.
+	must.Eq(t, 5, *a.Namespaces[0].Write)
+}
+
+func TestRPCRateLimitConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	must.NoError(t, (*RPCRateLimitConfig)(nil).Validate())
+	must.NoError(t, (&RPCRateLimitConfig{
+		Token:     &RPCRateLimit{Read: pointer.Of(1)},
+		Endpoints: []*NamedRPCRateLimit{{Name: "Job.Register", RPCRateLimit: RPCRateLimit{Write: pointer.Of(1)}}},
+	}).Validate())
+
+	err := (&RPCRateLimitConfig{
+		Token:      &RPCRateLimit{Read: pointer.Of(0)},
+		Namespaces: []*NamedRPCRateLimit{{Name: "prod", RPCRateLimit: RPCRateLimit{Write: pointer.Of(-1)}}},
+	}).Validate()
+	must.ErrorContains(t, err, "token: read limit must be greater than 0")
+	must.ErrorContains(t, err, `namespace "prod": write limit must be greater than 0`)
+}
+
+func TestRPCRateLimitConfig_Lookup(t *testing.T) {
+	ci.Parallel(t)
+
+	c := &RPCRateLimitConfig{
+		Namespaces: []*NamedRPCRateLimit{
+			{Name: "*", RPCRateLimit: RPCRateLimit{Read: pointer.Of(500)}},
+			{Name: "prod", RPCRateLimit: RPCRateLimit{Read: pointer.Of(50)}},
+		},
+		Endpoints: []*NamedRPCRateLimit{
+			{Name: "Alloc.List", RPCRateLimit: RPCRateLimit{Read: pointer.Of(1)}},
+		},
+	}
+
+	limit, ok := c.NamespaceLimit("prod").Limit(false)
+	must.True(t, ok)
+	must.Eq(t, 50, limit)
+
+	limit, ok = c.NamespaceLimit("dev").Limit(false)
+	must.True(t, ok)
+	must.Eq(t, 500, limit)
+
+	_, ok = c.NamespaceLimit("dev").Limit(true)
+	must.False(t, ok)
+
+	_, ok = c.EndpointLimit("Job.List").Limit(false)
+	must.False(t, ok)
+}
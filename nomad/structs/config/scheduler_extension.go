@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"slices"
+
+	"github.com/open-wander/wander/helper/pointer"
+)
+
+// SchedulerExtensionConfig configures a scheduler plugin consulted by the
+// servers when ranking nodes. The plugin binary is loaded from the agent's
+// plugin directory and configured with the matching plugin block.
+type SchedulerExtensionConfig struct {
+	// Name is the name of the plugin.
+	Name string `hcl:",key"`
+
+	// Timeout is the maximum amount of time the scheduler waits for the
+	// plugin to score a node before ignoring it.
+	Timeout *string `hcl:"timeout"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+func (s *SchedulerExtensionConfig) Copy() *SchedulerExtensionConfig {
+	if s == nil {
+		return nil
+	}
+
+	ns := *s
+	ns.Timeout = pointer.Copy(s.Timeout)
+	ns.ExtraKeysHCL = slices.Clone(s.ExtraKeysHCL)
+	return &ns
+}
+
+func (s *SchedulerExtensionConfig) Merge(o *SchedulerExtensionConfig) *SchedulerExtensionConfig {
+	switch {
+	case s == nil:
+		return o.Copy()
+	case o == nil:
+		return s.Copy()
+	default:
+		ns := s.Copy()
+		if o.Name != "" {
+			ns.Name = o.Name
+		}
+		if o.Timeout != nil {
+			ns.Timeout = pointer.Copy(o.Timeout)
+		}
+		return ns
+	}
+}
+
+// SchedulerExtensionConfigSetMerge merges two sets of scheduler extension
+// configs. Configs with the same name are merged and the order in which the
+// extensions are first defined is kept.
+func SchedulerExtensionConfigSetMerge(first, second []*SchedulerExtensionConfig) []*SchedulerExtensionConfig {
+	out := make([]*SchedulerExtensionConfig, 0, len(first)+len(second))
+	index := make(map[string]int, len(first)+len(second))
+
+	for _, set := range [][]*SchedulerExtensionConfig{first, second} {
+		for _, s := range set {
+			if i, ok := index[s.Name]; ok {
+				out[i] = out[i].Merge(s)
+				continue
+			}
+			index[s.Name] = len(out)
+			out = append(out, s.Copy())
+		}
+	}
+
+	return out
+}
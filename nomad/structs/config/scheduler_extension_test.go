@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestSchedulerExtensionConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := &SchedulerExtensionConfig{Name: "inventory", Timeout: pointer.Of("100ms")}
+
+	must.Eq(t, a, a.Merge(nil))
+	must.Eq(t, a, (*SchedulerExtensionConfig)(nil).Merge(a))
+	must.Eq(t, a, a.Merge(&SchedulerExtensionConfig{Name: "inventory"}))
+	must.Eq(t, &SchedulerExtensionConfig{Name: "inventory", Timeout: pointer.Of("1s")},
+		a.Merge(&SchedulerExtensionConfig{Timeout: pointer.Of("1s")}))
+}
+
+func TestSchedulerExtensionConfigSetMerge(t *testing.T) {
+	ci.Parallel(t)
+
+	first := []*SchedulerExtensionConfig{
+		{Name: "inventory", Timeout: pointer.Of("100ms")},
+		{Name: "power"},
+	}
+	second := []*SchedulerExtensionConfig{
+		{Name: "licenses"},
+		{Name: "power", Timeout: pointer.Of("2s")},
+	}
+
+	out := SchedulerExtensionConfigSetMerge(first, second)
+	must.Eq(t, []*SchedulerExtensionConfig{
+		{Name: "inventory", Timeout: pointer.Of("100ms")},
+		{Name: "power", Timeout: pointer.Of("2s")},
+		{Name: "licenses"},
+	}, out)
+
+	// The inputs are not modified.
+	must.Nil(t, first[1].Timeout)
+}
@@ -14,6 +14,10 @@ const (
 	// DefaultVaultConnectRetryIntv is the retry interval between trying to
 	// connect to Vault
 	DefaultVaultConnectRetryIntv = 30 * time.Second
+
+	// DefaultVaultJWTAuthBackendPath is the default path at which the Vault
+	// JWT auth method used to log in with workload identities is mounted.
+	DefaultVaultJWTAuthBackendPath = "jwt-nomad"
 )
 
 // VaultConfig contains the configuration information necessary to
@@ -39,8 +43,20 @@ type VaultConfig struct {
 	// capability on "auth/token/create/<create_from_role>". If this value is
 	// unset and the token is created from a role, the value is defaulted to the
 	// role the token is from.
+	//
+	// When using workload identities, it is the default role of the JWT auth
+	// method used by tasks that don't specify one.
 	Role string `hcl:"create_from_role"`
 
+	// UseIdentity enables tasks to log in to Vault with their workload
+	// identity using the JWT auth method, instead of receiving tokens derived
+	// by the servers. Servers don't need a Vault token in this mode.
+	UseIdentity *bool `hcl:"use_identity"`
+
+	// JWTAuthBackendPath is the path at which the Vault JWT auth method used
+	// to log in with workload identities is mounted.
+	JWTAuthBackendPath string `hcl:"jwt_auth_backend_path"`
+
 	// Namespace sets the Vault namespace used for all calls against the
 	// Vault API. If this is unset, then Nomad does not use Vault namespaces.
 	Namespace string `mapstructure:"namespace"`
@@ -99,6 +115,21 @@ func (c *VaultConfig) IsEnabled() bool {
 	return c.Enabled != nil && *c.Enabled
 }
 
+// UsesIdentity returns whether tasks log in to Vault with their workload
+// identity instead of receiving tokens derived by the servers.
+func (c *VaultConfig) UsesIdentity() bool {
+	return c.UseIdentity != nil && *c.UseIdentity
+}
+
+// JWTAuthPath returns the path of the JWT auth method used to log in with
+// workload identities.
+func (c *VaultConfig) JWTAuthPath() string {
+	if c.JWTAuthBackendPath != "" {
+		return c.JWTAuthBackendPath
+	}
+	return DefaultVaultJWTAuthBackendPath
+}
+
 // AllowsUnauthenticated returns whether the config allows unauthenticated
 // access to Vault
 func (c *VaultConfig) AllowsUnauthenticated() bool {
@@ -121,6 +152,12 @@ func (c *VaultConfig) Merge(b *VaultConfig) *VaultConfig {
 	if b.Namespace != "" {
 		result.Namespace = b.Namespace
 	}
+	if b.UseIdentity != nil {
+		result.UseIdentity = b.UseIdentity
+	}
+	if b.JWTAuthBackendPath != "" {
+		result.JWTAuthBackendPath = b.JWTAuthBackendPath
+	}
 	if b.AllowUnauthenticated != nil {
 		result.AllowUnauthenticated = b.AllowUnauthenticated
 	}
@@ -219,6 +256,18 @@ func (c *VaultConfig) Equal(b *VaultConfig) bool {
 		return false
 	}
 
+	if c.UseIdentity == nil || b.UseIdentity == nil {
+		if c.UseIdentity != b.UseIdentity {
+			return false
+		}
+	} else if *c.UseIdentity != *b.UseIdentity {
+		return false
+	}
+
+	if c.JWTAuthBackendPath != b.JWTAuthBackendPath {
+		return false
+	}
+
 	if c.AllowUnauthenticated == nil || b.AllowUnauthenticated == nil {
 		if c.AllowUnauthenticated != b.AllowUnauthenticated {
 			return false
@@ -20,6 +20,8 @@ func TestVaultConfig_Merge(t *testing.T) {
 		Enabled:              pointer.Of(false),
 		Token:                "1",
 		Role:                 "1",
+		UseIdentity:          pointer.Of(false),
+		JWTAuthBackendPath:   "1",
 		AllowUnauthenticated: pointer.Of(true),
 		TaskTokenTTL:         "1",
 		Addr:                 "1",
@@ -35,6 +37,8 @@ func TestVaultConfig_Merge(t *testing.T) {
 		Enabled:              pointer.Of(true),
 		Token:                "2",
 		Role:                 "2",
+		UseIdentity:          pointer.Of(true),
+		JWTAuthBackendPath:   "2",
 		AllowUnauthenticated: pointer.Of(false),
 		TaskTokenTTL:         "2",
 		Addr:                 "2",
@@ -50,6 +54,8 @@ func TestVaultConfig_Merge(t *testing.T) {
 		Enabled:              pointer.Of(true),
 		Token:                "2",
 		Role:                 "2",
+		UseIdentity:          pointer.Of(true),
+		JWTAuthBackendPath:   "2",
 		AllowUnauthenticated: pointer.Of(false),
 		TaskTokenTTL:         "2",
 		Addr:                 "2",
@@ -74,6 +80,8 @@ func TestVaultConfig_Equals(t *testing.T) {
 		Enabled:              pointer.Of(false),
 		Token:                "1",
 		Role:                 "1",
+		UseIdentity:          pointer.Of(false),
+		JWTAuthBackendPath:   "1",
 		Namespace:            "1",
 		AllowUnauthenticated: pointer.Of(true),
 		TaskTokenTTL:         "1",
@@ -91,6 +99,8 @@ func TestVaultConfig_Equals(t *testing.T) {
 		Enabled:              pointer.Of(false),
 		Token:                "1",
 		Role:                 "1",
+		UseIdentity:          pointer.Of(false),
+		JWTAuthBackendPath:   "1",
 		Namespace:            "1",
 		AllowUnauthenticated: pointer.Of(true),
 		TaskTokenTTL:         "1",
@@ -110,6 +120,8 @@ func TestVaultConfig_Equals(t *testing.T) {
 		Enabled:              pointer.Of(true),
 		Token:                "1",
 		Role:                 "1",
+		UseIdentity:          pointer.Of(false),
+		JWTAuthBackendPath:   "1",
 		Namespace:            "1",
 		AllowUnauthenticated: pointer.Of(true),
 		TaskTokenTTL:         "1",
@@ -127,6 +139,8 @@ func TestVaultConfig_Equals(t *testing.T) {
 		Enabled:              pointer.Of(false),
 		Token:                "1",
 		Role:                 "1",
+		UseIdentity:          pointer.Of(false),
+		JWTAuthBackendPath:   "1",
 		Namespace:            "1",
 		AllowUnauthenticated: pointer.Of(true),
 		TaskTokenTTL:         "1",
@@ -74,7 +74,7 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 	diff := &JobDiff{Type: DiffTypeNone}
 	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
 	filter := []string{"ID", "Status", "StatusDescription", "Version", "Stable", "CreateIndex",
-		"ModifyIndex", "JobModifyIndex", "Update", "SubmitTime", "NomadTokenID", "VaultToken"}
+		"ModifyIndex", "JobModifyIndex", "Update", "SubmitTime", "DispatchDeadline", "NomadTokenID", "VaultToken"}
 
 	if j == nil && other == nil {
 		return diff, nil
@@ -128,6 +128,17 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Dependencies diff
+	depsDiff := primitiveObjectSetDiff(
+		interfaceSlice(j.DependsOn),
+		interfaceSlice(other.DependsOn),
+		nil,
+		"DependsOn",
+		contextual)
+	if depsDiff != nil {
+		diff.Objects = append(diff.Objects, depsDiff...)
+	}
+
 	// Task groups diff
 	tgs, err := taskGroupDiffs(j.TaskGroups, other.TaskGroups, contextual)
 	if err != nil {
@@ -515,7 +526,7 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(t.LogConfig, other.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(t.LogConfig, other.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -526,6 +537,17 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, dDiff)
 	}
 
+	// Dependencies diff
+	depsDiff := primitiveObjectSetDiff(
+		interfaceSlice(t.DependsOn),
+		interfaceSlice(other.DependsOn),
+		nil,
+		"DependsOn",
+		contextual)
+	if depsDiff != nil {
+		diff.Objects = append(diff.Objects, depsDiff...)
+	}
+
 	// Artifacts diff
 	diffs := primitiveObjectSetDiff(
 		interfaceSlice(t.Artifacts),
@@ -555,11 +577,16 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 	}
 
 	// Identity diff
-	idDiffs := idDiff(t.Identity, other.Identity, contextual)
+	idDiffs := idDiff(t.Identity, other.Identity, "Identity", contextual)
 	if idDiffs != nil {
 		diff.Objects = append(diff.Objects, idDiffs)
 	}
 
+	// Identities diff
+	if idsDiffs := identitiesDiffs(t.Identities, other.Identities, contextual); idsDiffs != nil {
+		diff.Objects = append(diff.Objects, idsDiffs...)
+	}
+
 	return diff, nil
 }
 
@@ -1518,7 +1545,7 @@ func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(old.LogConfig, new.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(old.LogConfig, new.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -1526,6 +1553,47 @@ func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
 	return diff
 }
 
+// logConfigDiff returns the diff of two LogConfig objects.
+// If contextual diff is enabled, all fields will be returned, even if no diff occurred.
+func logConfigDiff(old, new *LogConfig, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "LogConfig"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+	filter := []string{"Sinks"}
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &LogConfig{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, filter, true)
+	} else if new == nil {
+		new = &LogConfig{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, filter, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, filter, true)
+		newPrimitiveFlat = flatmap.Flatten(new, filter, true)
+	}
+
+	// diff the primitive fields
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	// diff the sinks
+	if sDiffs := primitiveObjectSetDiff(
+		interfaceSlice(old.Sinks),
+		interfaceSlice(new.Sinks),
+		nil, "Sink", contextual); sDiffs != nil {
+		diff.Objects = append(diff.Objects, sDiffs...)
+	}
+
+	if diff.Type == DiffTypeEdited && len(diff.Fields) == 0 && len(diff.Objects) == 0 {
+		return nil
+	}
+
+	return diff
+}
+
 // consulProxyDiff returns the diff of two ConsulProxy objects.
 // If contextual diff is enabled, all fields will be returned, even if no diff occurred.
 func consulProxyDiff(old, new *ConsulProxy, contextual bool) *ObjectDiff {
@@ -2448,30 +2516,68 @@ func configDiff(old, new map[string]interface{}, contextual bool) *ObjectDiff {
 
 // idDiff returns the diff of two identity objects. If contextual diff is
 // enabled, all fields will be returned, even if no diff occurred.
-func idDiff(oldWI, newWI *WorkloadIdentity, contextual bool) *ObjectDiff {
-	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Identity"}
+func idDiff(oldWI, newWI *WorkloadIdentity, name string, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: name}
 	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+	var oldAudience, newAudience []string
 
 	if reflect.DeepEqual(oldWI, newWI) {
 		return nil
 	} else if oldWI == nil {
 		diff.Type = DiffTypeAdded
 		newPrimitiveFlat = flatmap.Flatten(newWI, nil, true)
+		newAudience = newWI.Audience
 	} else if newWI == nil {
 		diff.Type = DiffTypeDeleted
 		oldPrimitiveFlat = flatmap.Flatten(oldWI, nil, true)
+		oldAudience = oldWI.Audience
 	} else {
 		diff.Type = DiffTypeEdited
 		oldPrimitiveFlat = flatmap.Flatten(oldWI, nil, true)
 		newPrimitiveFlat = flatmap.Flatten(newWI, nil, true)
+		oldAudience = oldWI.Audience
+		newAudience = newWI.Audience
 	}
 
 	// Diff the primitive fields.
 	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
 
+	// Audience diff
+	if setDiff := stringSetDiff(oldAudience, newAudience, "Audience", contextual); setDiff != nil {
+		diff.Objects = append(diff.Objects, setDiff)
+	}
+
 	return diff
 }
 
+// identitiesDiffs returns the diffs of two sets of named identities, matched
+// by name. If contextual diff is enabled, all fields will be returned, even
+// if no diff occurred.
+func identitiesDiffs(old, new []*WorkloadIdentity, contextual bool) []*ObjectDiff {
+	oldMap := make(map[string]*WorkloadIdentity, len(old))
+	newMap := make(map[string]*WorkloadIdentity, len(new))
+	names := make([]string, 0, len(old)+len(new))
+	for _, wi := range old {
+		oldMap[wi.Name] = wi
+		names = append(names, wi.Name)
+	}
+	for _, wi := range new {
+		if _, ok := oldMap[wi.Name]; !ok {
+			names = append(names, wi.Name)
+		}
+		newMap[wi.Name] = wi
+	}
+	sort.Strings(names)
+
+	var diffs []*ObjectDiff
+	for _, name := range names {
+		if diff := idDiff(oldMap[name], newMap[name], "Identities", contextual); diff != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs
+}
+
 // ObjectDiff contains the diff of two generic objects.
 type ObjectDiff struct {
 	Type    DiffType
@@ -530,6 +530,12 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "Periodic",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "CatchupWindow",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Enabled",
@@ -584,6 +590,12 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "Periodic",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "CatchupWindow",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Enabled",
@@ -652,6 +664,12 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeDeleted,
 						Name: "Periodic",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "CatchupWindow",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "Enabled",
@@ -1029,6 +1047,18 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "Periodic",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "Catchup",
+								Old:  "",
+								New:  "",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "CatchupWindow",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "Enabled",
@@ -1148,6 +1178,50 @@ func TestJobDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Dependencies edited
+			Old: &Job{
+				DependsOn: []*JobDependency{
+					{JobID: "foo"},
+					{JobID: "bar"},
+				},
+			},
+			New: &Job{
+				DependsOn: []*JobDependency{
+					{JobID: "foo"},
+					{JobID: "baz"},
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "DependsOn",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "JobID",
+								Old:  "",
+								New:  "baz",
+							},
+						},
+					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "DependsOn",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "JobID",
+								Old:  "bar",
+								New:  "",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// Affinities edited
 			Old: &Job{
@@ -2187,6 +2261,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "false",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "RestartOnTimeout",
+								Old:  "",
+								New:  "false",
+							},
 						},
 					},
 				},
@@ -2240,6 +2320,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "false",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "RestartOnTimeout",
+								Old:  "false",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -2364,6 +2450,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "false",
 								New:  "true",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "RestartOnTimeout",
+								Old:  "false",
+								New:  "false",
+							},
 						},
 					},
 				},
@@ -2419,6 +2511,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "20000000000",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "RescheduleOnTimeout",
+								Old:  "",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Unlimited",
@@ -2480,6 +2578,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "20000000000",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "RescheduleOnTimeout",
+								Old:  "false",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "Unlimited",
@@ -2607,6 +2711,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "0",
 								New:  "0",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "RescheduleOnTimeout",
+								Old:  "false",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "Unlimited",
@@ -4140,6 +4250,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "false",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxRunDuration",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "ShutdownDelay",
@@ -4186,6 +4302,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "false",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxRunDuration",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "ShutdownDelay",
@@ -5229,6 +5351,113 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "LogConfig sinks edited",
+			Old: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+				},
+			},
+			New: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+					Sinks: []*LogSink{
+						{Type: LogSinkTypeFile, Path: "task.json", BufferSize: 1024},
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "LogConfig",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Sink",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "BufferSize",
+										Old:  "",
+										New:  "1024",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Path",
+										Old:  "",
+										New:  "task.json",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Type",
+										Old:  "",
+										New:  "file",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "Dependencies edited",
+			Old: &Task{
+				DependsOn: []*TaskDependency{
+					{Task: "db", Condition: TaskDependencyConditionHealthy},
+				},
+			},
+			New: &Task{
+				DependsOn: []*TaskDependency{
+					{Task: "db", Condition: TaskDependencyConditionStarted},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "DependsOn",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Condition",
+								Old:  "",
+								New:  "started",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Task",
+								Old:  "",
+								New:  "db",
+							},
+						},
+					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "DependsOn",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "Condition",
+								Old:  "healthy",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Task",
+								Old:  "db",
+								New:  "",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name: "Artifacts edited",
 			Old: &Task{
@@ -5325,6 +5554,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "bam",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "ReadOnly",
+								Old:  "",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "RelativeDest",
@@ -5361,6 +5596,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "bar",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "ReadOnly",
+								Old:  "false",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "RelativeDest",
@@ -7678,6 +7919,7 @@ func TestTaskDiff(t *testing.T) {
 					DisableFile:  true,
 					ChangeMode:   "signal",
 					ChangeSignal: "SIGUSR1",
+					Role:         "nomad-workloads",
 				},
 			},
 			New: &Task{
@@ -7688,6 +7930,7 @@ func TestTaskDiff(t *testing.T) {
 					DisableFile:  true,
 					ChangeMode:   "signal",
 					ChangeSignal: "SIGUSR1",
+					Role:         "nomad-workloads",
 				},
 			},
 			Expected: &TaskDiff{
@@ -7727,6 +7970,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "ns1",
 								New:  "ns1",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Role",
+								Old:  "nomad-workloads",
+								New:  "nomad-workloads",
+							},
 						},
 						Objects: []*ObjectDiff{
 							{
@@ -8285,6 +8534,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "false",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "TTL",
+								Old:  "",
+								New:  "0",
+							},
 						},
 					},
 				},
@@ -8315,6 +8570,11 @@ func TestTaskDiff(t *testing.T) {
 								Name: "File",
 								Old:  "false",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "TTL",
+								Old:  "0",
+							},
 						},
 					},
 				},
@@ -8351,6 +8611,102 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "Identities edited",
+			Old: &Task{
+				Identities: []*WorkloadIdentity{
+					{
+						Name:     "consul",
+						Audience: []string{"consul.io"},
+						File:     true,
+					},
+					{
+						Name:     "vault",
+						Audience: []string{"vault.io"},
+					},
+				},
+			},
+			New: &Task{
+				Identities: []*WorkloadIdentity{
+					{
+						Name:     "consul",
+						Audience: []string{"consul.io", "example.com"},
+						File:     true,
+						TTL:      time.Hour,
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Identities",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "TTL",
+								Old:  "0",
+								New:  "3600000000000",
+							},
+						},
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Audience",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Audience",
+										Old:  "",
+										New:  "example.com",
+									},
+								},
+							},
+						},
+					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "Identities",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "Env",
+								Old:  "false",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "File",
+								Old:  "false",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Name",
+								Old:  "vault",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "TTL",
+								Old:  "0",
+							},
+						},
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "Audience",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeDeleted,
+										Name: "Audience",
+										Old:  "vault.io",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -6,6 +6,7 @@ package structs
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 )
@@ -26,6 +27,7 @@ const (
 	errMissingAllocID             = "Missing allocation ID"
 	errIncompatibleFiltering      = "Filter expression cannot be used with other filter parameters"
 	errMalformedChooseParameter   = "Parameter for choose must be in form '<number>|<key>'"
+	errRateLimited                = "rate limit exceeded"
 
 	// Prefix based errors that are used to check if the error is of a given
 	// type. These errors should be created with the associated constructor.
@@ -88,6 +90,18 @@ func IsErrNoLeader(err error) bool {
 	return err != nil && strings.Contains(err.Error(), errNoLeader)
 }
 
+// NewErrRateLimited returns the error used to reject a request exceeding the
+// rate limit of the scope.
+func NewErrRateLimited(scope string) error {
+	return NewErrRPCCodedf(http.StatusTooManyRequests, "%s %s", scope, errRateLimited)
+}
+
+// IsErrRateLimited returns whether the error is due to the request exceeding
+// a rate limit.
+func IsErrRateLimited(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errRateLimited)
+}
+
 // IsErrNoRegionPath returns whether the error is due to there being no path to
 // the given region.
 func IsErrNoRegionPath(err error) bool {
@@ -31,6 +31,8 @@ const (
 	TopicACLAuthMethod  Topic = "ACLAuthMethod"
 	TopicACLBindingRule Topic = "ACLBindingRule"
 	TopicService        Topic = "Service"
+	TopicJobDependency  Topic = "JobDependency"
+	TopicExecSession    Topic = "ExecSession"
 	TopicAll            Topic = "*"
 
 	TypeNodeRegistration              = "NodeRegistration"
@@ -63,6 +65,9 @@ const (
 	TypeACLBindingRuleDeleted         = "ACLBindingRuleDeleted"
 	TypeServiceRegistration           = "ServiceRegistration"
 	TypeServiceDeregistration         = "ServiceDeregistration"
+	TypeJobDependencyRunUpdated       = "JobDependencyRunUpdated"
+	TypeExecSessionStarted            = "ExecSessionStarted"
+	TypeExecSessionEnded              = "ExecSessionEnded"
 )
 
 // Event represents a change in Nomads state.
@@ -146,6 +151,16 @@ type ACLTokenEvent struct {
 	secretID string
 }
 
+// JobDependencyRunEvent holds a newly updated or deleted job dependency run.
+type JobDependencyRunEvent struct {
+	Run *JobDependencyRun
+}
+
+// ExecSessionEvent holds a newly started or ended exec session.
+type ExecSessionEvent struct {
+	Session *ExecSession
+}
+
 // ServiceRegistrationStreamEvent holds a newly updated or deleted service
 // registration.
 type ServiceRegistrationStreamEvent struct {
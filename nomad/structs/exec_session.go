@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package structs
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// ExecSession is an exec session into a task of an allocation in a namespace
+// with exec recording enabled. The client running the allocation registers
+// the session when it starts, records its input and output, and updates the
+// session when it ends.
+type ExecSession struct {
+	// ID is the unique ID of the session, also identifying its recording on
+	// the client.
+	ID string
+
+	Namespace string
+	JobID     string
+	AllocID   string
+	TaskName  string
+
+	// NodeID is the ID of the client running the allocation and storing the
+	// recording of the session.
+	NodeID string
+
+	// Command is the command run by the session.
+	Command []string
+
+	// Tty is whether the session used a TTY.
+	Tty bool
+
+	// AccessorID and TokenName identify the ACL token which started the
+	// session, if any.
+	AccessorID string
+	TokenName  string
+
+	StartedAt time.Time
+	EndedAt   time.Time
+
+	// ExitCode is the exit code of the command, once the session ended.
+	ExitCode int
+
+	// Error is the error which ended the session, if any.
+	Error string
+
+	// RecordingSize is the size in bytes of the recording of the session,
+	// once the session ended.
+	RecordingSize int64
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// GetID implements the IDGetter interface required for pagination.
+func (s *ExecSession) GetID() string {
+	if s == nil {
+		return ""
+	}
+	return s.ID
+}
+
+// GetNamespace implements the NamespaceGetter interface required for
+// pagination.
+func (s *ExecSession) GetNamespace() string {
+	if s == nil {
+		return ""
+	}
+	return s.Namespace
+}
+
+// Ended returns whether the session has ended.
+func (s *ExecSession) Ended() bool {
+	return !s.EndedAt.IsZero()
+}
+
+// Validate returns an error if the session registered by a client is invalid.
+func (s *ExecSession) Validate() error {
+	if s == nil {
+		return errors.New("missing exec session")
+	}
+	if s.ID == "" {
+		return errors.New("missing exec session ID")
+	}
+	if s.Namespace == "" || s.JobID == "" || s.AllocID == "" || s.TaskName == "" {
+		return errors.New("exec session must reference an allocation task")
+	}
+	if len(s.Command) == 0 {
+		return errors.New("missing exec session command")
+	}
+	if s.StartedAt.IsZero() {
+		return errors.New("missing exec session start time")
+	}
+	return nil
+}
+
+// Copy returns a deep copy of the session.
+func (s *ExecSession) Copy() *ExecSession {
+	if s == nil {
+		return nil
+	}
+	ns := new(ExecSession)
+	*ns = *s
+	ns.Command = slices.Clone(s.Command)
+	return ns
+}
+
+// ExecSessionStartRequest is used by clients to register an exec session
+// when it starts.
+type ExecSessionStartRequest struct {
+	Session *ExecSession
+	WriteRequest
+}
+
+// ExecSessionStartResponse is the response to an exec session start request.
+type ExecSessionStartResponse struct {
+	// Record is whether the exec session must be recorded. Sessions are only
+	// registered when recording is enabled for their namespace.
+	Record bool
+	WriteMeta
+}
+
+// ExecSessionEndRequest is used by clients to update an exec session when it
+// ends.
+type ExecSessionEndRequest struct {
+	Session *ExecSession
+	WriteRequest
+}
+
+// ExecSessionDeleteRequest is used to delete exec sessions.
+type ExecSessionDeleteRequest struct {
+	IDs []string
+	WriteRequest
+}
+
+// ExecSessionListRequest is used to list exec sessions.
+type ExecSessionListRequest struct {
+	QueryOptions
+}
+
+// ExecSessionListResponse is the response to an exec session list request.
+type ExecSessionListResponse struct {
+	Sessions []*ExecSession
+	QueryMeta
+}
+
+// ExecSessionSpecificRequest is used to make a request for a specific exec
+// session.
+type ExecSessionSpecificRequest struct {
+	ID string
+	QueryOptions
+}
+
+// SingleExecSessionResponse is the response to a specific exec session
+// request.
+type SingleExecSessionResponse struct {
+	Session *ExecSession
+	QueryMeta
+}
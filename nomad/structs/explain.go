@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package structs
+
+import "fmt"
+
+const (
+	// ExplainCheckConstraint and the other ExplainCheck* values are the names
+	// of the checks reported for each node in a placement explanation. They
+	// mirror the iterators and feasibility checkers used by the schedulers.
+	ExplainCheckConstraint       = "constraint"
+	ExplainCheckDrivers          = "drivers"
+	ExplainCheckDevices          = "devices"
+	ExplainCheckNetwork          = "network"
+	ExplainCheckHostVolumes      = "host volumes"
+	ExplainCheckCSIVolumes       = "csi volumes"
+	ExplainCheckDistinctHosts    = "distinct hosts"
+	ExplainCheckDistinctProperty = "distinct property"
+	ExplainCheckResources        = "resources"
+
+	// MaxExplainClosestNodes is the number of nodes marked as closest to
+	// fitting a task group in a placement explanation.
+	MaxExplainClosestNodes = 3
+)
+
+// JobExplainRequest is used to request an explanation of why the task groups
+// of a job can or cannot be placed on the nodes of its node pool.
+type JobExplainRequest struct {
+	JobID string
+
+	// TaskGroup limits the explanation to a single task group. All task
+	// groups of the job are explained if empty.
+	TaskGroup string
+
+	QueryOptions
+}
+
+// JobExplainResponse is used to return the placement explanation of a job.
+type JobExplainResponse struct {
+	// TaskGroups is the explanation for each task group, keyed by name.
+	TaskGroups map[string]*TaskGroupExplanation
+
+	QueryMeta
+}
+
+// TaskGroupExplanation is the result of running every candidate node of a
+// job through the placement pipeline for a single task group.
+type TaskGroupExplanation struct {
+	TaskGroup string
+
+	// NodesEvaluated is the number of ready nodes in the job's node pool and
+	// datacenters that were evaluated.
+	NodesEvaluated int
+
+	// NodesAvailable is the number of evaluated nodes per datacenter.
+	NodesAvailable map[string]int
+
+	// NodesFeasible is the number of nodes on which the task group could be
+	// placed.
+	NodesFeasible int
+
+	// Requested is the amount of resources required by the task group.
+	Requested *ExplainResources
+
+	// NodesFailed is the number of nodes which failed each check, keyed by
+	// the name and reason of the check.
+	NodesFailed map[string]int
+
+	// Nodes is the explanation for each evaluated node, ordered from the node
+	// closest to fitting the task group to the farthest. It is only set if
+	// the token used for the request has node read access.
+	Nodes []*NodeExplanation
+}
+
+// NodeExplanation describes the result of each placement check for a single
+// node.
+type NodeExplanation struct {
+	NodeID     string
+	NodeName   string
+	NodePool   string
+	NodeClass  string
+	Datacenter string
+
+	// Feasible is true if the task group could be placed on the node.
+	Feasible bool
+
+	// Closest marks the nodes that are the best candidates for the task
+	// group, either because they have the highest score or, if no node is
+	// feasible, because they failed the fewest checks.
+	Closest bool
+
+	// Score is the final normalized score of the node. It is only set for
+	// feasible nodes.
+	Score float64
+
+	// Available is the amount of resources left on the node after accounting
+	// for the node's reserved resources and its existing allocations.
+	Available *ExplainResources
+
+	// Checks is the result of each check performed against the node, in the
+	// order they were evaluated.
+	Checks []*NodeExplainCheck
+}
+
+// FailedChecks returns the number of checks the node did not pass.
+func (n *NodeExplanation) FailedChecks() int {
+	failed := 0
+	for _, check := range n.Checks {
+		if !check.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// NodeExplainCheck is the result of a single placement check on a node.
+type NodeExplainCheck struct {
+	// Name is the type of check, such as "constraint" or "resources".
+	Name string
+
+	// Target identifies what was checked, such as the constraint expression
+	// or the set of drivers required.
+	Target string
+
+	// Passed is true if the node satisfied the check.
+	Passed bool
+
+	// Reason is a human readable reason for a failed check, such as the
+	// exhausted resource dimension or the colliding port.
+	Reason string
+}
+
+// String returns the name of the check along with the reason it failed, if
+// any.
+func (c *NodeExplainCheck) String() string {
+	if c.Reason == "" {
+		return c.Name
+	}
+	return fmt.Sprintf("%s (%s)", c.Name, c.Reason)
+}
+
+// ExplainResources is a simplified view of the resources requested by a
+// task group or available on a node.
+type ExplainResources struct {
+	CPU      int64
+	MemoryMB int64
+	DiskMB   int64
+}
+
+// Deficit returns the fraction of the requested resources that are not
+// available, summed over every dimension. A value of zero means the request
+// fits within the available resources.
+func (r *ExplainResources) Deficit(requested *ExplainResources) float64 {
+	if r == nil || requested == nil {
+		return 0
+	}
+
+	deficit := 0.0
+	dims := [][2]int64{
+		{requested.CPU, r.CPU},
+		{requested.MemoryMB, r.MemoryMB},
+		{requested.DiskMB, r.DiskMB},
+	}
+	for _, dim := range dims {
+		want, have := dim[0], dim[1]
+		if want <= 0 || have >= want {
+			continue
+		}
+		if have < 0 {
+			have = 0
+		}
+		deficit += float64(want-have) / float64(want)
+	}
+	return deficit
+}
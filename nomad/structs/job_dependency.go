@@ -0,0 +1,189 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package structs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// JobDependencyRunStatusBlocked is the status of a run that is waiting
+	// for its upstream jobs to complete.
+	JobDependencyRunStatusBlocked = "blocked"
+
+	// JobDependencyRunStatusTriggered is the status of a run whose upstream
+	// jobs all completed successfully and whose job has been evaluated.
+	JobDependencyRunStatusTriggered = "triggered"
+
+	// JobDependencyRunStatusFailed is the status of a run whose job will
+	// never be evaluated because an upstream job failed.
+	JobDependencyRunStatusFailed = "failed"
+)
+
+const (
+	// JobDependencyStatusPending is used when an upstream job does not exist
+	// yet, has not started or is itself waiting on its dependencies.
+	JobDependencyStatusPending = "pending"
+
+	// JobDependencyStatusRunning is used when an upstream job is running.
+	JobDependencyStatusRunning = "running"
+
+	// JobDependencyStatusComplete is used when an upstream job completed
+	// successfully.
+	JobDependencyStatusComplete = "complete"
+
+	// JobDependencyStatusFailed is used when an upstream job failed, was
+	// stopped or could not run because of its own dependencies.
+	JobDependencyStatusFailed = "failed"
+)
+
+// JobDependency declares an upstream job, in the same namespace, that must
+// complete successfully before the job declaring it is evaluated. When the
+// upstream job is parameterized or periodic, all of its children must
+// complete successfully.
+type JobDependency struct {
+	// JobID is the ID of the upstream job.
+	JobID string
+}
+
+func (d *JobDependency) Copy() *JobDependency {
+	if d == nil {
+		return nil
+	}
+	nd := new(JobDependency)
+	*nd = *d
+	return nd
+}
+
+// CopySliceJobDependencies returns a deep copy of the passed dependencies.
+func CopySliceJobDependencies(s []*JobDependency) []*JobDependency {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	c := make([]*JobDependency, l)
+	for i, v := range s {
+		c[i] = v.Copy()
+	}
+	return c
+}
+
+// validateJobDependencies validates the dependencies of the passed job.
+func validateJobDependencies(j *Job) error {
+	var mErr multierror.Error
+
+	if j.Type != JobTypeBatch && j.Type != JobTypeSysBatch {
+		_ = multierror.Append(&mErr, fmt.Errorf(
+			"Dependencies can only be used with %q or %q scheduler", JobTypeBatch, JobTypeSysBatch))
+	}
+	if j.IsPeriodic() {
+		_ = multierror.Append(&mErr, fmt.Errorf("Dependencies can not be used with periodic jobs"))
+	}
+
+	seen := make(map[string]struct{}, len(j.DependsOn))
+	for i, dep := range j.DependsOn {
+		switch {
+		case dep == nil || dep.JobID == "":
+			_ = multierror.Append(&mErr, fmt.Errorf("Dependency %d must specify a job", i+1))
+			continue
+		case dep.JobID == j.ID:
+			_ = multierror.Append(&mErr, fmt.Errorf("Job can not depend on itself"))
+		}
+		if _, ok := seen[dep.JobID]; ok {
+			_ = multierror.Append(&mErr, fmt.Errorf("Duplicate dependency on job %q", dep.JobID))
+		}
+		seen[dep.JobID] = struct{}{}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// JobDependencyRun tracks whether a job with dependencies has been released
+// for evaluation. A run is created blocked whenever a new version of the job
+// is registered, and the leader moves it to triggered or failed as its
+// upstream jobs finish.
+type JobDependencyRun struct {
+	// Namespace and JobID identify the downstream job.
+	Namespace string
+	JobID     string
+
+	// JobModifyIndex is the modify index of the job version this run was
+	// created for.
+	JobModifyIndex uint64
+
+	// Status is the status of the run and StatusDescription explains it.
+	Status            string
+	StatusDescription string
+
+	// Upstreams is the last observed status of each upstream job.
+	Upstreams []*JobDependencyUpstream
+
+	// EvalID is the evaluation created when the run was triggered.
+	EvalID string
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// JobDependencyUpstream is the observed status of an upstream job.
+type JobDependencyUpstream struct {
+	JobID  string
+	Status string
+}
+
+// NewJobDependencyRun returns a blocked run for the passed job.
+func NewJobDependencyRun(job *Job) *JobDependencyRun {
+	run := &JobDependencyRun{
+		Namespace:      job.Namespace,
+		JobID:          job.ID,
+		JobModifyIndex: job.JobModifyIndex,
+		Status:         JobDependencyRunStatusBlocked,
+		Upstreams:      make([]*JobDependencyUpstream, len(job.DependsOn)),
+	}
+	for i, dep := range job.DependsOn {
+		run.Upstreams[i] = &JobDependencyUpstream{
+			JobID:  dep.JobID,
+			Status: JobDependencyStatusPending,
+		}
+	}
+	return run
+}
+
+func (r *JobDependencyRun) Copy() *JobDependencyRun {
+	if r == nil {
+		return nil
+	}
+	nr := new(JobDependencyRun)
+	*nr = *r
+	if r.Upstreams != nil {
+		nr.Upstreams = make([]*JobDependencyUpstream, len(r.Upstreams))
+		for i, u := range r.Upstreams {
+			nu := *u
+			nr.Upstreams[i] = &nu
+		}
+	}
+	return nr
+}
+
+// Terminal returns whether the run has been triggered or failed.
+func (r *JobDependencyRun) Terminal() bool {
+	return r.Status != JobDependencyRunStatusBlocked
+}
+
+// JobDependencyRunUpdateRequest is used by the leader to update the status
+// of dependency runs, creating the evaluations of triggered runs.
+type JobDependencyRunUpdateRequest struct {
+	Runs  []*JobDependencyRun
+	Evals []*Evaluation
+	WriteRequest
+}
+
+// JobDependenciesResponse is used to return the dependency run of a job.
+type JobDependenciesResponse struct {
+	Run *JobDependencyRun
+	QueryMeta
+}
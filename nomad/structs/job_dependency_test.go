@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestJob_ValidateDependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name      string
+		job       func() *Job
+		expectErr string
+	}{
+		{
+			name: "valid batch job",
+			job: func() *Job {
+				return testJobWithDependencies(JobTypeBatch, "extract", "transform")
+			},
+		},
+		{
+			name: "valid sysbatch job",
+			job: func() *Job {
+				return testJobWithDependencies(JobTypeSysBatch, "extract")
+			},
+		},
+		{
+			name: "service job",
+			job: func() *Job {
+				return testJobWithDependencies(JobTypeService, "extract")
+			},
+			expectErr: `Dependencies can only be used with "batch" or "sysbatch" scheduler`,
+		},
+		{
+			name: "periodic job",
+			job: func() *Job {
+				job := testJobWithDependencies(JobTypeBatch, "extract")
+				job.Periodic = &PeriodicConfig{
+					Enabled:  true,
+					SpecType: PeriodicSpecCron,
+					Spec:     "*/5 * * * *",
+				}
+				return job
+			},
+			expectErr: "Dependencies can not be used with periodic jobs",
+		},
+		{
+			name: "missing job",
+			job: func() *Job {
+				return testJobWithDependencies(JobTypeBatch, "")
+			},
+			expectErr: "Dependency 1 must specify a job",
+		},
+		{
+			name: "self",
+			job: func() *Job {
+				return testJobWithDependencies(JobTypeBatch, "report")
+			},
+			expectErr: "Job can not depend on itself",
+		},
+		{
+			name: "duplicate",
+			job: func() *Job {
+				return testJobWithDependencies(JobTypeBatch, "extract", "extract")
+			},
+			expectErr: `Duplicate dependency on job "extract"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateJobDependencies(tc.job())
+			if tc.expectErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expectErr)
+			}
+		})
+	}
+}
+
+func TestJob_HasDependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJobWithDependencies(JobTypeBatch, "extract")
+	must.True(t, job.HasDependencies())
+
+	// Parameterized jobs pass their dependencies on to dispatched children.
+	job.ParameterizedJob = &ParameterizedJobConfig{}
+	must.False(t, job.HasDependencies())
+
+	job.Dispatched = true
+	must.True(t, job.HasDependencies())
+
+	job.DependsOn = nil
+	must.False(t, job.HasDependencies())
+}
+
+func TestJobDependencyRun_New(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJobWithDependencies(JobTypeBatch, "extract", "transform")
+	job.JobModifyIndex = 10
+
+	run := NewJobDependencyRun(job)
+	must.Eq(t, &JobDependencyRun{
+		Namespace:      job.Namespace,
+		JobID:          job.ID,
+		JobModifyIndex: 10,
+		Status:         JobDependencyRunStatusBlocked,
+		Upstreams: []*JobDependencyUpstream{
+			{JobID: "extract", Status: JobDependencyStatusPending},
+			{JobID: "transform", Status: JobDependencyStatusPending},
+		},
+	}, run)
+	must.False(t, run.Terminal())
+
+	runCopy := run.Copy()
+	runCopy.Status = JobDependencyRunStatusTriggered
+	runCopy.Upstreams[0].Status = JobDependencyStatusComplete
+	must.True(t, runCopy.Terminal())
+	must.Eq(t, JobDependencyRunStatusBlocked, run.Status)
+	must.Eq(t, JobDependencyStatusPending, run.Upstreams[0].Status)
+}
+
+func testJobWithDependencies(jobType string, upstreams ...string) *Job {
+	job := &Job{
+		ID:        "report",
+		Namespace: DefaultNamespace,
+		Type:      jobType,
+	}
+	for _, upstream := range upstreams {
+		job.DependsOn = append(job.DependsOn, &JobDependency{JobID: upstream})
+	}
+	return job
+}
@@ -5,8 +5,10 @@ package structs
 
 import (
 	"fmt"
+	"net/url"
 	"time"
 
+	kms "github.com/hashicorp/go-kms-wrapping/v2"
 	"github.com/open-wander/wander/helper"
 	"github.com/open-wander/wander/helper/crypto"
 	"github.com/open-wander/wander/helper/uuid"
@@ -159,9 +161,21 @@ func (rkm *RootKeyMeta) Validate() error {
 // KMS wrapper. This struct includes the server-specific key-wrapping key and
 // should never be sent over RPC.
 type KeyEncryptionKeyWrapper struct {
-	Meta                       *RootKeyMeta
-	EncryptedDataEncryptionKey []byte `json:"DEK"`
-	KeyEncryptionKey           []byte `json:"KEK"`
+	Meta *RootKeyMeta
+
+	// ProviderID is the ID of the key encryption key provider that wrapped
+	// the root key. It's empty for root keys wrapped by the aead provider
+	// before the provider was configurable.
+	ProviderID string `json:",omitempty"`
+
+	// EncryptedDataEncryptionKey and KeyEncryptionKey are the root key and
+	// the key that wraps it when using the aead provider.
+	EncryptedDataEncryptionKey []byte `json:"DEK,omitempty"`
+	KeyEncryptionKey           []byte `json:"KEK,omitempty"`
+
+	// WrappedDataEncryptionKey is the root key wrapped by an external KMS
+	// provider. The key encryption key never leaves the KMS.
+	WrappedDataEncryptionKey *kms.BlobInfo `json:"WrappedDEK,omitempty"`
 }
 
 // EncryptionAlgorithm chooses which algorithm is used for
@@ -243,3 +257,91 @@ type KeyringDeleteRootKeyRequest struct {
 type KeyringDeleteRootKeyResponse struct {
 	WriteMeta
 }
+
+const (
+	// JWKSPath is the path under which the public keys used to sign
+	// workload identities are published as a JSON Web Key Set.
+	JWKSPath = "/.well-known/jwks.json"
+
+	// OIDCDiscoveryPath is the path under which the OIDC discovery document
+	// for workload identities is published.
+	OIDCDiscoveryPath = "/.well-known/openid-configuration"
+
+	// PubKeyAlgEdDSA is the JWA algorithm of the public keys used to sign
+	// workload identities.
+	PubKeyAlgEdDSA = "EdDSA"
+
+	// PubKeyUseSig is the JWK use of public keys that verify signatures.
+	PubKeyUseSig = "sig"
+)
+
+// KeyringPublicKey is the public half of a root key, used by third parties to
+// verify the signature of workload identities.
+type KeyringPublicKey struct {
+	KeyID      string
+	PublicKey  []byte
+	Algorithm  string
+	Use        string
+	CreateTime int64
+}
+
+// KeyringListPublicResponse is the response value of the Keyring.ListPublic
+// RPC.
+type KeyringListPublicResponse struct {
+	PublicKeys []*KeyringPublicKey
+
+	// RotationThreshold is how long an active key is used before being
+	// rotated, so that callers may estimate how long the public keys can be
+	// cached.
+	RotationThreshold time.Duration
+
+	QueryMeta
+}
+
+// OIDCDiscoveryConfig is the OIDC discovery document for workload identities,
+// as described by https://openid.net/specs/openid-connect-discovery-1_0.html
+type OIDCDiscoveryConfig struct {
+	Issuer        string   `json:"issuer"`
+	JWKS          string   `json:"jwks_uri"`
+	IDTokenAlgs   []string `json:"id_token_signing_alg_values_supported"`
+	ResponseTypes []string `json:"response_types_supported"`
+	Subjects      []string `json:"subject_types_supported"`
+}
+
+// NewOIDCDiscoveryConfig returns the OIDC discovery document for the given
+// issuer, which must be an absolute https or http URL.
+func NewOIDCDiscoveryConfig(issuer string) (*OIDCDiscoveryConfig, error) {
+	if issuer == "" {
+		return nil, fmt.Errorf("issuer is required")
+	}
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issuer %q: %v", issuer, err)
+	}
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("invalid issuer %q: must be an http or https URL", issuer)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("invalid issuer %q: must not contain a query or fragment", issuer)
+	}
+
+	jwksURL, err := url.JoinPath(issuer, JWKSPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issuer %q: %v", issuer, err)
+	}
+
+	return &OIDCDiscoveryConfig{
+		Issuer:        issuer,
+		JWKS:          jwksURL,
+		IDTokenAlgs:   []string{PubKeyAlgEdDSA},
+		ResponseTypes: []string{"code"},
+		Subjects:      []string{"public"},
+	}, nil
+}
+
+// KeyringGetConfigResponse is the response value of the Keyring.GetConfig
+// RPC.
+type KeyringGetConfigResponse struct {
+	// OIDCDiscovery is nil if no issuer is configured.
+	OIDCDiscovery *OIDCDiscoveryConfig
+}
@@ -404,3 +404,73 @@ type NodeMetaResponse struct {
 	// Static is the static Node metadata (set via agent configuration)
 	Static map[string]string
 }
+
+// ArtifactCacheEntry is an artifact stored in the artifact cache of a Client
+// agent.
+type ArtifactCacheEntry struct {
+	// Key is the content address of the artifact, derived from its source,
+	// checksum, getter mode and headers.
+	Key string
+
+	// Source is the source URL of the artifact with its credentials and
+	// query parameters other than the checksum removed.
+	Source string
+
+	// Mode is the getter mode the artifact was downloaded with.
+	Mode string
+
+	// Size is the size in bytes of the cached artifact.
+	Size int64
+
+	// Hits is the number of times the artifact was served from the cache.
+	Hits uint64
+
+	// CreateTime and LastUseTime are the times in Unix nanoseconds at which
+	// the artifact was cached and last placed into a task directory.
+	CreateTime  int64
+	LastUseTime int64
+}
+
+// ArtifactCacheListResponse is used to list the artifact cache of a Client
+// agent.
+type ArtifactCacheListResponse struct {
+	// Enabled is whether the artifact cache is enabled on the Client.
+	Enabled bool
+
+	// MaxSize and Size are the configured and current size in bytes of the
+	// artifact cache.
+	MaxSize int64
+	Size    int64
+
+	// Hits and Misses are the number of cacheable artifacts served from and
+	// missing from the cache since the Client started.
+	Hits   uint64
+	Misses uint64
+
+	// Entries are the cached artifacts, most recently used first.
+	Entries []*ArtifactCacheEntry
+}
+
+// ArtifactCachePurgeRequest is used to remove artifacts from the artifact
+// cache of a Client agent.
+type ArtifactCachePurgeRequest struct {
+	QueryOptions // Client RPCs must use QueryOptions to set AllowStale=true
+
+	// NodeID is the node being targeted by this request (or the node
+	// receiving this request if NodeID is empty).
+	NodeID string
+
+	// Key is the key of the artifact to remove. All the artifacts are
+	// removed if it is empty.
+	Key string
+}
+
+// ArtifactCachePurgeResponse is the result of purging the artifact cache of
+// a Client agent.
+type ArtifactCachePurgeResponse struct {
+	// Purged are the keys of the removed artifacts.
+	Purged []string
+
+	// Size is the number of bytes freed.
+	Size int64
+}
@@ -19,7 +19,9 @@ import (
 	"hash/crc32"
 	"math"
 	"net"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"sort"
@@ -41,6 +43,7 @@ import (
 	"github.com/open-wander/wander/helper/constraints/semver"
 	"github.com/open-wander/wander/helper/escapingfs"
 	"github.com/open-wander/wander/helper/pointer"
+	"github.com/open-wander/wander/helper/signature"
 	"github.com/open-wander/wander/helper/uuid"
 	"github.com/open-wander/wander/lib/cpuset"
 	"github.com/open-wander/wander/lib/kheap"
@@ -131,6 +134,12 @@ const (
 	// Namespace types were moved from enterprise and therefore start at 64
 	NamespaceUpsertRequestType MessageType = 64
 	NamespaceDeleteRequestType MessageType = 65
+
+	JobDependencyRunUpdateRequestType MessageType = 66
+	ACLTokenUsageUpsertRequestType    MessageType = 67
+	ExecSessionStartRequestType       MessageType = 68
+	ExecSessionEndRequestType         MessageType = 69
+	ExecSessionDeleteRequestType      MessageType = 70
 )
 
 const (
@@ -928,6 +937,10 @@ type JobDispatchRequest struct {
 	Meta    map[string]string
 	WriteRequest
 	IdPrefixTemplate string
+
+	// Deadline is the time by which the tasks of the dispatched job must
+	// have completed before they are killed.
+	Deadline time.Time
 }
 
 // JobValidateRequest is used to validate a job
@@ -1838,6 +1851,21 @@ type EmitNodeEventsResponse struct {
 	WriteMeta
 }
 
+// NodeEvictAllocsRequest is used by a client under resource pressure to ask
+// for some of its allocations to be migrated to other nodes.
+type NodeEvictAllocsRequest struct {
+	// NodeID is the ID of the node the allocations are evicted from
+	NodeID string
+
+	// AllocIDs is the list of allocations to evict
+	AllocIDs []string
+
+	// Reason is the human readable reason of the eviction
+	Reason string
+
+	WriteRequest
+}
+
 const (
 	NodeEventSubsystemDrain     = "Drain"
 	NodeEventSubsystemDriver    = "Driver"
@@ -1845,8 +1873,44 @@ const (
 	NodeEventSubsystemCluster   = "Cluster"
 	NodeEventSubsystemScheduler = "Scheduler"
 	NodeEventSubsystemStorage   = "Storage"
+	NodeEventSubsystemEviction  = "Eviction"
 )
 
+const (
+	NodeConditionMemoryPressure = "MemoryPressure"
+	NodeConditionDiskPressure   = "DiskPressure"
+	NodeConditionInodePressure  = "InodePressure"
+)
+
+// NodeCondition is a resource pressure condition reported by a client, such
+// as it running low on memory.
+type NodeCondition struct {
+	// Type is the type of the condition, one of the NodeCondition* constants
+	Type string
+
+	// Message is the human readable description of the condition
+	Message string
+
+	// Since is the time the condition was first observed
+	Since time.Time
+}
+
+func (nc *NodeCondition) Copy() *NodeCondition {
+	if nc == nil {
+		return nil
+	}
+	c := new(NodeCondition)
+	*c = *nc
+	return c
+}
+
+func (nc *NodeCondition) Equal(o *NodeCondition) bool {
+	if nc == nil || o == nil {
+		return nc == o
+	}
+	return nc.Type == o.Type && nc.Message == o.Message && nc.Since.Equal(o.Since)
+}
+
 // NodeEvent is a single unit representing a node’s state change
 type NodeEvent struct {
 	Message     string
@@ -2172,6 +2236,10 @@ type Node struct {
 	// LastDrain contains metadata about the most recent drain operation
 	LastDrain *DrainMetadata
 
+	// Conditions is the list of resource pressure conditions currently
+	// reported by the client
+	Conditions []*NodeCondition
+
 	// LastMissedHeartbeatIndex stores the Raft index when the node last missed
 	// a heartbeat. It resets to zero once the node is marked as ready again.
 	LastMissedHeartbeatIndex uint64
@@ -2275,9 +2343,16 @@ func (n *Node) Copy() *Node {
 	nn.HostVolumes = helper.DeepCopyMap(n.HostVolumes)
 	nn.HostNetworks = helper.DeepCopyMap(n.HostNetworks)
 	nn.LastDrain = nn.LastDrain.Copy()
+	nn.Conditions = helper.CopySlice(n.Conditions)
 	return &nn
 }
 
+// UnderPressure returns true if the node reports running low on any of its
+// resources.
+func (n *Node) UnderPressure() bool {
+	return len(n.Conditions) > 0
+}
+
 // UnresponsiveStatus returns true if the node is a status where it is not
 // communicating with the server.
 func (n *Node) UnresponsiveStatus() bool {
@@ -4464,6 +4539,11 @@ type Job struct {
 	// for dispatching.
 	ParameterizedJob *ParameterizedJobConfig
 
+	// DependsOn lists the jobs that must complete successfully before this
+	// job is evaluated. Jobs dispatched from a parameterized job inherit
+	// its dependencies.
+	DependsOn []*JobDependency
+
 	// Dispatched is used to identify if the Job has been dispatched from a
 	// parameterized job.
 	Dispatched bool
@@ -4472,6 +4552,10 @@ type Job struct {
 	// non-terminal siblings which have the same token value.
 	DispatchIdempotencyToken string
 
+	// DispatchDeadline is the time by which the tasks of a dispatched job
+	// must have completed before they are killed.
+	DispatchDeadline time.Time
+
 	// Payload is the payload supplied when the job was dispatched.
 	Payload []byte
 
@@ -4626,6 +4710,7 @@ func (j *Job) Copy() *Job {
 	nj.Periodic = nj.Periodic.Copy()
 	nj.Meta = maps.Clone(nj.Meta)
 	nj.ParameterizedJob = nj.ParameterizedJob.Copy()
+	nj.DependsOn = CopySliceJobDependencies(nj.DependsOn)
 	return nj
 }
 
@@ -4773,6 +4858,12 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if len(j.DependsOn) > 0 {
+		if err := validateJobDependencies(j); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -4925,6 +5016,13 @@ func (j *Job) IsParameterized() bool {
 	return j.ParameterizedJob != nil && !j.Dispatched
 }
 
+// HasDependencies returns whether the job is held until its upstream jobs
+// complete. Parameterized jobs are not held themselves, only the jobs
+// dispatched from them.
+func (j *Job) HasDependencies() bool {
+	return len(j.DependsOn) > 0 && !j.IsParameterized()
+}
+
 // IsMultiregion returns whether a job is multiregion
 func (j *Job) IsMultiregion() bool {
 	return j.Multiregion != nil && j.Multiregion.Regions != nil && len(j.Multiregion.Regions) > 0
@@ -5069,6 +5167,12 @@ func (j *Job) SetSubmitTime() {
 	j.SubmitTime = time.Now().UTC().UnixNano()
 }
 
+// DeadlineExceeded returns true if the job was dispatched with a deadline
+// that has passed.
+func (j *Job) DeadlineExceeded(now time.Time) bool {
+	return j != nil && !j.DispatchDeadline.IsZero() && !now.Before(j.DispatchDeadline)
+}
+
 // JobListStub is used to return a subset of job information
 // for the job list
 type JobListStub struct {
@@ -5382,6 +5486,10 @@ type Namespace struct {
 	// pools.
 	NodePoolConfiguration *NamespaceNodePoolConfiguration
 
+	// ExecRecording is the namespace configuration for recording the exec
+	// sessions of its allocations.
+	ExecRecording *NamespaceExecRecordingConfiguration
+
 	// Meta is the set of metadata key/value pairs that attached to the namespace
 	Meta map[string]string
 
@@ -5423,6 +5531,15 @@ type NamespaceNodePoolConfiguration struct {
 	Denied []string
 }
 
+// NamespaceExecRecordingConfiguration stores configuration about the
+// recording of exec sessions for a namespace.
+type NamespaceExecRecordingConfiguration struct {
+	// Enabled records the input and output of the exec sessions of the
+	// allocations in this namespace, and announces their start and end on the
+	// event stream.
+	Enabled bool
+}
+
 func (n *Namespace) Validate() error {
 	var mErr multierror.Error
 
@@ -5478,6 +5595,9 @@ func (n *Namespace) SetHash() []byte {
 			_, _ = hash.Write([]byte(pool))
 		}
 	}
+	if n.ExecRecording != nil {
+		_, _ = hash.Write([]byte(strconv.FormatBool(n.ExecRecording.Enabled)))
+	}
 
 	// sort keys to ensure hash stability when meta is stored later
 	var keys []string
@@ -5516,6 +5636,10 @@ func (n *Namespace) Copy() *Namespace {
 		np.Allowed = slices.Clone(n.NodePoolConfiguration.Allowed)
 		np.Denied = slices.Clone(n.NodePoolConfiguration.Denied)
 	}
+	if n.ExecRecording != nil {
+		er := *n.ExecRecording
+		nc.ExecRecording = &er
+	}
 	if n.Meta != nil {
 		nc.Meta = make(map[string]string, len(n.Meta))
 		for k, v := range n.Meta {
@@ -5582,6 +5706,24 @@ const (
 	PeriodicSpecTest = "_internal_test"
 )
 
+const (
+	// PeriodicCatchupNone skips any launches that were missed while there
+	// was no leader to dispatch them.
+	PeriodicCatchupNone = "none"
+
+	// PeriodicCatchupLast launches only the most recent missed launch. This
+	// is the default policy.
+	PeriodicCatchupLast = "last"
+
+	// PeriodicCatchupAll launches every missed launch in order, bounded by
+	// the catchup window and PeriodicCatchupMaxLaunches.
+	PeriodicCatchupAll = "all"
+
+	// PeriodicCatchupMaxLaunches bounds the number of missed launches that
+	// are backfilled with the "all" catchup policy.
+	PeriodicCatchupMaxLaunches = 100
+)
+
 // Periodic defines the interval a job should be run at.
 type PeriodicConfig struct {
 	// Enabled determines if the job should be run periodically.
@@ -5608,6 +5750,15 @@ type PeriodicConfig struct {
 	// Reference: https://www.iana.org/time-zones
 	TimeZone string
 
+	// Catchup is the policy used to handle launches that were missed while
+	// there was no leader. It is one of "none", "last" or "all". An empty
+	// value is treated as "last".
+	Catchup string
+
+	// CatchupWindow limits how far in the past missed launches are
+	// considered for catchup. A zero value means there is no limit.
+	CatchupWindow time.Duration
+
 	// location is the time zone to evaluate the launch time against
 	location *time.Location
 }
@@ -5662,9 +5813,58 @@ func (p *PeriodicConfig) Validate() error {
 		_ = multierror.Append(&mErr, fmt.Errorf("Unknown periodic specification type %q", p.SpecType))
 	}
 
+	switch p.Catchup {
+	case "", PeriodicCatchupNone, PeriodicCatchupLast, PeriodicCatchupAll:
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Unknown catchup policy %q", p.Catchup))
+	}
+	if p.CatchupWindow < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Catchup window must be non-negative"))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
+// CatchupPolicy returns the catchup policy to apply, defaulting to "last".
+func (p *PeriodicConfig) CatchupPolicy() string {
+	if p.Catchup == "" {
+		return PeriodicCatchupLast
+	}
+	return p.Catchup
+}
+
+// MissedLaunches returns the launch times strictly after last and no later
+// than now, in chronological order. Launches older than the catchup window
+// are dropped, and at most PeriodicCatchupMaxLaunches of the most recent
+// launches are returned.
+func (p *PeriodicConfig) MissedLaunches(last, now time.Time) ([]time.Time, error) {
+	from := last
+	if p.CatchupWindow > 0 {
+		if cutoff := now.Add(-p.CatchupWindow); cutoff.After(from) {
+			// Next is exclusive, so step back to include a launch that
+			// falls exactly on the cutoff.
+			from = cutoff.Add(-time.Nanosecond)
+		}
+	}
+
+	var launches []time.Time
+	for {
+		next, err := p.Next(from.In(p.GetLocation()))
+		if err != nil {
+			return nil, err
+		}
+		if next.IsZero() || next.After(now) {
+			break
+		}
+		launches = append(launches, next)
+		if len(launches) > PeriodicCatchupMaxLaunches {
+			launches = launches[1:]
+		}
+		from = next
+	}
+	return launches, nil
+}
+
 func (p *PeriodicConfig) Canonicalize() {
 	// Load the location
 	l, err := time.LoadLocation(p.TimeZone)
@@ -5759,6 +5959,10 @@ const (
 	// PeriodicLaunchSuffix is the string appended to the periodic jobs ID
 	// when launching derived instances of it.
 	PeriodicLaunchSuffix = "/periodic-"
+
+	// PeriodicLaunchHistorySize is the number of child launches retained in
+	// a PeriodicLaunch's history.
+	PeriodicLaunchHistorySize = 20
 )
 
 // PeriodicLaunch tracks the last launch time of a periodic job.
@@ -5767,11 +5971,65 @@ type PeriodicLaunch struct {
 	Namespace string    // Namespace of the periodic job
 	Launch    time.Time // The last launch time.
 
+	// History holds the most recent child launches, newest first.
+	History []*PeriodicLaunchRecord
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// PeriodicLaunchRecord records a single child job launched by a periodic job.
+type PeriodicLaunchRecord struct {
+	JobID  string    // ID of the derived child job.
+	Launch time.Time // The launch time of the child.
+}
+
+// RecordLaunch sets the last launch time and prepends the child to the
+// launch history, retaining at most PeriodicLaunchHistorySize entries. The
+// history of prev, if any, is carried over.
+func (p *PeriodicLaunch) RecordLaunch(prev *PeriodicLaunch, childID string, launch time.Time) {
+	p.Launch = launch
+	var history []*PeriodicLaunchRecord
+	if prev != nil {
+		history = prev.History
+	}
+	p.History = make([]*PeriodicLaunchRecord, 0, min(len(history)+1, PeriodicLaunchHistorySize))
+	p.History = append(p.History, &PeriodicLaunchRecord{JobID: childID, Launch: launch})
+	for _, r := range history {
+		if len(p.History) == PeriodicLaunchHistorySize {
+			break
+		}
+		p.History = append(p.History, r)
+	}
+}
+
+// PeriodicHistoryRequest is used to list the child launches of a periodic
+// job.
+type PeriodicHistoryRequest struct {
+	JobID string
+	QueryOptions
+}
+
+// PeriodicChild describes a single child launch of a periodic job.
+type PeriodicChild struct {
+	JobID  string
+	Launch time.Time
+
+	// Status is the status of the child job, or empty if the child has
+	// been garbage collected.
+	Status string
+}
+
+// PeriodicHistoryResponse is used to return the child launches of a
+// periodic job.
+type PeriodicHistoryResponse struct {
+	Periodic *PeriodicConfig
+	Launch   time.Time
+	Children []*PeriodicChild
+	QueryMeta
+}
+
 const (
 	DispatchPayloadForbidden = "forbidden"
 	DispatchPayloadOptional  = "optional"
@@ -6257,6 +6515,10 @@ type RestartPolicy struct {
 
 	// RenderTemplates is flag to explicitly render all templates on task restart
 	RenderTemplates bool
+
+	// RestartOnTimeout restarts tasks killed for exceeding their max run
+	// duration according to the policy. By default they are not restarted.
+	RestartOnTimeout bool
 }
 
 func (r *RestartPolicy) Copy() *RestartPolicy {
@@ -6330,6 +6592,11 @@ type ReschedulePolicy struct {
 	// Unlimited allows infinite rescheduling attempts. Only allowed when delay is set
 	// between reschedule attempts.
 	Unlimited bool
+
+	// RescheduleOnTimeout reschedules allocations whose tasks were killed for
+	// exceeding their max run duration according to the policy. By default
+	// they are not rescheduled.
+	RescheduleOnTimeout bool
 }
 
 func (r *ReschedulePolicy) Copy() *ReschedulePolicy {
@@ -6950,6 +7217,12 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, outer)
 	}
 
+	// Validate the dependencies between tasks
+	if err := tg.validateTaskDependencies(j.Type); err != nil {
+		outer := fmt.Errorf("Task group dependency validation failed: %v", err)
+		mErr.Errors = append(mErr.Errors, outer)
+	}
+
 	// Validate the scaling policy
 	if err := tg.validateScalingPolicy(j); err != nil {
 		outer := fmt.Errorf("Task group scaling policy validation failed: %v", err)
@@ -7383,6 +7656,10 @@ type LogConfig struct {
 	MaxFiles      int
 	MaxFileSizeMB int
 	Disabled      bool
+
+	// Sinks are the destinations logmon ships the task logs to, in addition
+	// to the rotated log files.
+	Sinks []*LogSink
 }
 
 func (l *LogConfig) Equal(o *LogConfig) bool {
@@ -7402,6 +7679,10 @@ func (l *LogConfig) Equal(o *LogConfig) bool {
 		return false
 	}
 
+	if !slices.EqualFunc(l.Sinks, o.Sinks, func(a, b *LogSink) bool { return a.Equal(b) }) {
+		return false
+	}
+
 	return true
 }
 
@@ -7413,6 +7694,7 @@ func (l *LogConfig) Copy() *LogConfig {
 		MaxFiles:      l.MaxFiles,
 		MaxFileSizeMB: l.MaxFileSizeMB,
 		Disabled:      l.Disabled,
+		Sinks:         helper.CopySlice(l.Sinks),
 	}
 }
 
@@ -7438,12 +7720,185 @@ func (l *LogConfig) Validate(disk *EphemeralDisk) error {
 	}
 	if disk != nil {
 		logUsage := (l.MaxFiles * l.MaxFileSizeMB)
+		// file sinks are rotated like the task's stdout and stderr
+		for _, sink := range l.Sinks {
+			if sink.Type == LogSinkTypeFile {
+				logUsage += l.MaxFiles * l.MaxFileSizeMB
+			}
+		}
 		if disk.SizeMB <= logUsage {
 			mErr.Errors = append(mErr.Errors,
 				fmt.Errorf("log storage (%d MB) must be less than requested disk capacity (%d MB)",
 					logUsage, disk.SizeMB))
 		}
 	}
+	if l.Disabled && len(l.Sinks) > 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("log sinks cannot be used when logs are disabled"))
+	}
+	for i, sink := range l.Sinks {
+		if err := sink.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("sink %d: %v", i+1, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+const (
+	// LogSinkTypeSyslog ships logs as RFC5424 messages to a syslog server
+	// over a unix socket, TCP or UDP.
+	LogSinkTypeSyslog = "syslog"
+
+	// LogSinkTypeJournald ships logs to the native socket of journald.
+	LogSinkTypeJournald = "journald"
+
+	// LogSinkTypeOTLP ships logs to an OpenTelemetry collector with the
+	// OTLP/HTTP protocol.
+	LogSinkTypeOTLP = "otlp"
+
+	// LogSinkTypeFile writes logs as JSON lines to a file in the task log
+	// directory.
+	LogSinkTypeFile = "file"
+
+	// DefaultLogSinkBufferSize is the number of log lines buffered for a sink
+	// before new lines are dropped.
+	DefaultLogSinkBufferSize = 1024
+)
+
+// LogSinkTypes are the supported log sink types.
+var LogSinkTypes = []string{
+	LogSinkTypeSyslog,
+	LogSinkTypeJournald,
+	LogSinkTypeOTLP,
+	LogSinkTypeFile,
+}
+
+// syslogFacilities are the facilities accepted by the syslog log sink.
+var syslogFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+// LogSink configures a destination logmon ships the task logs to. Each line
+// written by the task is buffered for the sink, and lines are dropped when
+// the buffer is full so a slow sink never blocks the task.
+type LogSink struct {
+	// Type is the type of the sink, one of LogSinkTypes.
+	Type string
+
+	// Address is the address of the syslog server (unix://, tcp:// or
+	// udp://), the path of the journald socket, or the URL of the OTLP/HTTP
+	// logs endpoint. The syslog and journald sinks default to the local
+	// daemon.
+	Address string
+
+	// Path is the name of the file written by the file sink in the task log
+	// directory. The file is rotated like the task's stdout and stderr files.
+	Path string
+
+	// Facility is the syslog facility of the messages.
+	Facility string
+
+	// Tag is the syslog app name or journald identifier of the messages. It
+	// defaults to the task name.
+	Tag string
+
+	// Headers are the HTTP headers sent to the OTLP endpoint.
+	Headers map[string]string
+
+	// BufferSize is the number of log lines buffered for the sink.
+	BufferSize int
+}
+
+func (l *LogSink) Equal(o *LogSink) bool {
+	if l == nil || o == nil {
+		return l == o
+	}
+	switch {
+	case l.Type != o.Type:
+		return false
+	case l.Address != o.Address:
+		return false
+	case l.Path != o.Path:
+		return false
+	case l.Facility != o.Facility:
+		return false
+	case l.Tag != o.Tag:
+		return false
+	case !maps.Equal(l.Headers, o.Headers):
+		return false
+	case l.BufferSize != o.BufferSize:
+		return false
+	}
+	return true
+}
+
+func (l *LogSink) Copy() *LogSink {
+	if l == nil {
+		return nil
+	}
+	nl := *l
+	nl.Headers = maps.Clone(l.Headers)
+	return &nl
+}
+
+// Validate returns an error if the sink is of an unknown type or sets options
+// which are invalid for its type.
+func (l *LogSink) Validate() error {
+	var mErr multierror.Error
+
+	if l.BufferSize < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("buffer_size must be positive; got %d", l.BufferSize))
+	}
+	if l.Type != LogSinkTypeSyslog && l.Facility != "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("facility is only supported by %q sinks", LogSinkTypeSyslog))
+	}
+	if l.Type != LogSinkTypeOTLP && len(l.Headers) > 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("headers are only supported by %q sinks", LogSinkTypeOTLP))
+	}
+	if l.Type != LogSinkTypeFile && l.Path != "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("path is only supported by %q sinks", LogSinkTypeFile))
+	}
+
+	switch l.Type {
+	case LogSinkTypeSyslog:
+		if l.Address != "" {
+			u, err := url.Parse(l.Address)
+			if err != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid syslog address %q: %v", l.Address, err))
+			} else if !slices.Contains([]string{"unix", "tcp", "udp"}, u.Scheme) {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf(
+					"invalid syslog address %q: scheme must be one of unix, tcp or udp", l.Address))
+			}
+		}
+		if l.Facility != "" && !slices.Contains(syslogFacilities, l.Facility) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid syslog facility %q", l.Facility))
+		}
+	case LogSinkTypeJournald:
+		if l.Address != "" && !filepath.IsAbs(l.Address) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("journald address %q must be an absolute socket path", l.Address))
+		}
+	case LogSinkTypeOTLP:
+		u, err := url.Parse(l.Address)
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid otlp address %q: %v", l.Address, err))
+		} else if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("otlp address %q must be an http or https URL", l.Address))
+		}
+	case LogSinkTypeFile:
+		if l.Address != "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("address is not supported by %q sinks", LogSinkTypeFile))
+		}
+		if l.Path == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("file sinks require a path"))
+		} else if l.Path != filepath.Base(l.Path) || !filepath.IsLocal(l.Path) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("file sink path %q must be a file name in the task log directory", l.Path))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid sink type %q: must be one of %s",
+			l.Type, strings.Join(LogSinkTypes, ", ")))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -7494,6 +7949,10 @@ type Task struct {
 
 	Lifecycle *TaskLifecycleConfig
 
+	// DependsOn is the list of sibling tasks that must meet a condition
+	// before this task is allowed to start.
+	DependsOn []*TaskDependency
+
 	// Meta is used to associate arbitrary metadata with this
 	// task. This is opaque to Nomad.
 	Meta map[string]string
@@ -7528,6 +7987,10 @@ type Task struct {
 	// specification and defaults to SIGINT
 	KillSignal string
 
+	// MaxRunDuration is the maximum duration the task may run for before it
+	// is killed. Zero means the task may run forever.
+	MaxRunDuration time.Duration
+
 	// Used internally to manage tasks according to their TaskKind. Initial use case
 	// is for Consul Connect
 	Kind TaskKind
@@ -7538,6 +8001,11 @@ type Task struct {
 	// Identity controls if and how the workload identity is exposed to
 	// tasks similar to the Vault block.
 	Identity *WorkloadIdentity
+
+	// Identities are the additional named workload identities of the task,
+	// each with their own audience and lifetime, to present to 3rd party
+	// services.
+	Identities []*WorkloadIdentity
 }
 
 // UsesConnect is for conveniently detecting if the Task is able to make use
@@ -7598,8 +8066,17 @@ func (t *Task) Copy() *Task {
 	nt.Meta = maps.Clone(nt.Meta)
 	nt.DispatchPayload = nt.DispatchPayload.Copy()
 	nt.Lifecycle = nt.Lifecycle.Copy()
+	nt.DependsOn = CopySliceTaskDependencies(nt.DependsOn)
 	nt.Identity = nt.Identity.Copy()
 
+	if t.Identities != nil {
+		identities := make([]*WorkloadIdentity, len(t.Identities))
+		for i, wi := range nt.Identities {
+			identities[i] = wi.Copy()
+		}
+		nt.Identities = identities
+	}
+
 	if t.Artifacts != nil {
 		artifacts := make([]*TaskArtifact, 0, len(t.Artifacts))
 		for _, a := range nt.Artifacts {
@@ -7666,6 +8143,33 @@ func (t *Task) Canonicalize(job *Job, tg *TaskGroup) {
 	for _, template := range t.Templates {
 		template.Canonicalize()
 	}
+
+	for _, dep := range t.DependsOn {
+		dep.Canonicalize()
+	}
+
+	t.Identity.Canonicalize()
+	for _, wi := range t.Identities {
+		wi.Canonicalize()
+	}
+}
+
+// LookupIdentity returns the workload identity of the task with the given
+// name, or nil if it does not exist. The default identity always exists even
+// if the task does not have an identity block.
+func (t *Task) LookupIdentity(name string) *WorkloadIdentity {
+	if name == WorkloadIdentityDefaultName {
+		if t.Identity != nil {
+			return t.Identity
+		}
+		return &WorkloadIdentity{Name: WorkloadIdentityDefaultName}
+	}
+	for _, wi := range t.Identities {
+		if wi.Name == name {
+			return wi
+		}
+	}
+	return nil
 }
 
 func (t *Task) GoString() string {
@@ -7705,6 +8209,9 @@ func (t *Task) Validate(jobType string, tg *TaskGroup) error {
 				t.KillTimeout, tg.Update.ProgressDeadline))
 		}
 	}
+	if t.MaxRunDuration < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("MaxRunDuration must be a positive value"))
+	}
 	if t.ShutdownDelay < 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("ShutdownDelay must be a positive value"))
 	}
@@ -7786,6 +8293,35 @@ func (t *Task) Validate(jobType string, tg *TaskGroup) error {
 		}
 	}
 
+	// Validate workload identities.
+	if t.Identity != nil {
+		if !t.Identity.IsDefault() {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Identity must be named %q", WorkloadIdentityDefaultName))
+		} else if err := t.Identity.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Identity validation failed: %v", err))
+		}
+	}
+	identities := make(map[string]int, len(t.Identities))
+	for idx, wi := range t.Identities {
+		if wi == nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Identity %d must not be nil", idx+1))
+			continue
+		}
+		if err := wi.Validate(); err != nil {
+			outer := fmt.Errorf("Identity %d validation failed: %v", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+		if wi.IsDefault() {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Identity %d can not be named %q", idx+1, WorkloadIdentityDefaultName))
+		}
+		if other, ok := identities[wi.Name]; ok {
+			outer := fmt.Errorf("Identity %d has same name as %d", idx+1, other)
+			mErr.Errors = append(mErr.Errors, outer)
+		} else {
+			identities[wi.Name] = idx + 1
+		}
+	}
+
 	// Validate the dispatch payload block if there
 	if t.DispatchPayload != nil {
 		if err := t.DispatchPayload.Validate(); err != nil {
@@ -8585,6 +9121,10 @@ type TaskState struct {
 	// not be started again.
 	FinishedAt time.Time
 
+	// TimedOut marks the last run of the task as having been killed for
+	// exceeding its max run duration or the deadline of its job.
+	TimedOut bool
+
 	// Series of task events that transition the state of the task.
 	Events []*TaskEvent
 
@@ -8652,6 +9192,9 @@ func (ts *TaskState) Equal(o *TaskState) bool {
 	if ts.FinishedAt != o.FinishedAt {
 		return false
 	}
+	if ts.TimedOut != o.TimedOut {
+		return false
+	}
 	if !slices.EqualFunc(ts.Events, o.Events, func(ts, o *TaskEvent) bool {
 		return ts.Equal(o)
 	}) {
@@ -8716,10 +9259,18 @@ const (
 	// failed.
 	TaskArtifactDownloadFailed = "Failed Artifact Download"
 
+	// TaskArtifactVerificationFailed indicates that the signature of an
+	// artifact could not be verified against the trusted keys.
+	TaskArtifactVerificationFailed = "Failed Artifact Verification"
+
 	// TaskBuildingTaskDir indicates that the task directory/chroot is being
 	// built.
 	TaskBuildingTaskDir = "Building Task Directory"
 
+	// TaskMigratingDisk indicates the progress of the migration of the
+	// ephemeral disk of the previous allocation from another node.
+	TaskMigratingDisk = "Migrating Disk"
+
 	// TaskSetup indicates the task runner is setting up the task environment
 	TaskSetup = "Task Setup"
 
@@ -8742,6 +9293,10 @@ const (
 	// TaskMainDead indicates that the main tasks have dead
 	TaskMainDead = "Main Tasks Dead"
 
+	// TaskDependencyFailed indicates that a task will never start because a
+	// task it depends on exited without meeting its condition.
+	TaskDependencyFailed = "Task Dependency Failed"
+
 	// TaskHookFailed indicates that one of the hooks for a task failed.
 	TaskHookFailed = "Task hook failed"
 
@@ -8769,6 +9324,14 @@ const (
 	// TaskSkippingShutdownDelay indicates that the task operation was
 	// configured to ignore the shutdown delay value set for the tas.
 	TaskSkippingShutdownDelay = "Skipping shutdown delay"
+
+	// TaskRunDurationExceeded indicates that the task is being killed for
+	// running longer than its max run duration.
+	TaskRunDurationExceeded = "Run Duration Exceeded"
+
+	// TaskDeadlineExceeded indicates that the task is being killed for
+	// running past the deadline of its dispatched job.
+	TaskDeadlineExceeded = "Deadline Exceeded"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -8910,6 +9473,12 @@ func (e *TaskEvent) PopulateEventDisplayMessage() {
 		} else {
 			desc = "Failed to download artifacts"
 		}
+	case TaskArtifactVerificationFailed:
+		if e.DownloadError != "" {
+			desc = e.DownloadError
+		} else {
+			desc = "Failed to verify artifact signature"
+		}
 	case TaskKilling:
 		if e.KillReason != "" {
 			desc = e.KillReason
@@ -8982,6 +9551,18 @@ func (e *TaskEvent) PopulateEventDisplayMessage() {
 		desc = "Main tasks in the group died"
 	case TaskClientReconnected:
 		desc = "Client reconnected"
+	case TaskRunDurationExceeded:
+		if e.Message != "" {
+			desc = e.Message
+		} else {
+			desc = "Task exceeded its max run duration"
+		}
+	case TaskDeadlineExceeded:
+		if e.Message != "" {
+			desc = e.Message
+		} else {
+			desc = "Task exceeded the deadline of its job"
+		}
 	default:
 		desc = e.Message
 	}
@@ -9212,6 +9793,15 @@ type TaskArtifact struct {
 	// RelativeDest is the download destination given relative to the task's
 	// directory.
 	RelativeDest string
+
+	// Signature is the detached signature the artifact is verified against
+	// before it is placed into the task's directory.
+	Signature *ArtifactSignature
+
+	// ReadOnly allows the files placed from the client artifact cache to be
+	// read-only, so that they can be hardlinked instead of copied when the
+	// filesystem doesn't support reflinks.
+	ReadOnly bool
 }
 
 func (ta *TaskArtifact) Equal(o *TaskArtifact) bool {
@@ -9229,6 +9819,10 @@ func (ta *TaskArtifact) Equal(o *TaskArtifact) bool {
 		return false
 	case ta.RelativeDest != o.RelativeDest:
 		return false
+	case !ta.Signature.Equal(o.Signature):
+		return false
+	case ta.ReadOnly != o.ReadOnly:
+		return false
 	}
 	return true
 }
@@ -9243,6 +9837,8 @@ func (ta *TaskArtifact) Copy() *TaskArtifact {
 		GetterHeaders: maps.Clone(ta.GetterHeaders),
 		GetterMode:    ta.GetterMode,
 		RelativeDest:  ta.RelativeDest,
+		Signature:     ta.Signature.Copy(),
+		ReadOnly:      ta.ReadOnly,
 	}
 }
 
@@ -9283,6 +9879,20 @@ func (ta *TaskArtifact) Hash() string {
 
 	_, _ = h.Write([]byte(ta.GetterMode))
 	_, _ = h.Write([]byte(ta.RelativeDest))
+
+	// Only hash the signature when set, so that the hash of artifacts
+	// without a signature is unchanged.
+	if sig := ta.Signature; sig != nil {
+		_, _ = h.Write([]byte(sig.Source))
+		_, _ = h.Write([]byte(sig.Format))
+		for _, key := range sig.Keys {
+			_, _ = h.Write([]byte(key))
+		}
+		_, _ = h.Write([]byte(sig.Variable))
+	}
+	if ta.ReadOnly {
+		_, _ = h.Write([]byte("read_only"))
+	}
 	return base64.RawStdEncoding.EncodeToString(h.Sum(nil))
 }
 
@@ -9315,6 +9925,12 @@ func (ta *TaskArtifact) Validate() error {
 		mErr.Errors = append(mErr.Errors, err)
 	}
 
+	if ta.Signature != nil {
+		if err := ta.Signature.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, multierror.Prefix(err, "signature:"))
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -9369,6 +9985,81 @@ func (ta *TaskArtifact) validateChecksum() error {
 	return nil
 }
 
+// ArtifactSignature is a detached signature of an artifact, verified against
+// the public keys trusted by the client before the artifact is unpacked.
+type ArtifactSignature struct {
+	// Source is the go-getter source of the signature, downloaded with the
+	// headers of the artifact.
+	Source string
+
+	// Format is the format of the signature, either "minisign" or "cosign".
+	// Defaults to "minisign".
+	Format string
+
+	// Keys are the names of the trusted keys which may have made the
+	// signature. Defaults to any trusted key of the format.
+	Keys []string
+
+	// Variable is the path of a Variable whose items are public keys trusted
+	// in addition to the keys configured on the client. It is read with the
+	// workload identity of the task.
+	Variable string
+}
+
+func (s *ArtifactSignature) Equal(o *ArtifactSignature) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	switch {
+	case s.Source != o.Source:
+		return false
+	case s.Format != o.Format:
+		return false
+	case !slices.Equal(s.Keys, o.Keys):
+		return false
+	case s.Variable != o.Variable:
+		return false
+	}
+	return true
+}
+
+func (s *ArtifactSignature) Copy() *ArtifactSignature {
+	if s == nil {
+		return nil
+	}
+	return &ArtifactSignature{
+		Source:   s.Source,
+		Format:   s.Format,
+		Keys:     slices.Clone(s.Keys),
+		Variable: s.Variable,
+	}
+}
+
+func (s *ArtifactSignature) Validate() error {
+	var mErr multierror.Error
+	if s.Source == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("source must be specified"))
+	}
+
+	switch s.Format {
+	case "":
+		s.Format = signature.FormatMinisign
+	case signature.FormatMinisign, signature.FormatCosign:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid signature format %q; must be one of: %s, %s",
+			s.Format, signature.FormatMinisign, signature.FormatCosign))
+	}
+
+	for _, key := range s.Keys {
+		if key == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("key names must not be empty"))
+			break
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 const (
 	ConstraintDistinctProperty  = "distinct_property"
 	ConstraintDistinctHosts     = "distinct_hosts"
@@ -9826,6 +10517,11 @@ type Vault struct {
 	// ChangeSignal is the signal sent to the task when a new token is
 	// retrieved. This is only valid when using the signal change mode.
 	ChangeSignal string
+
+	// Role is the role of the Vault JWT auth method used to log in with the
+	// workload identity of the task. The policies of the token are set by
+	// the role, so Policies are not required when it is set.
+	Role string
 }
 
 func (v *Vault) Equal(o *Vault) bool {
@@ -9845,6 +10541,8 @@ func (v *Vault) Equal(o *Vault) bool {
 		return false
 	case v.ChangeSignal != o.ChangeSignal:
 		return false
+	case v.Role != o.Role:
+		return false
 	}
 	return true
 }
@@ -9877,7 +10575,7 @@ func (v *Vault) Validate() error {
 	}
 
 	var mErr multierror.Error
-	if len(v.Policies) == 0 {
+	if len(v.Policies) == 0 && v.Role == "" {
 		_ = multierror.Append(&mErr, fmt.Errorf("Policy list cannot be empty"))
 	}
 
@@ -10699,9 +11397,33 @@ func (a *Allocation) ShouldReschedule(reschedulePolicy *ReschedulePolicy, failTi
 // RescheduleEligible returns if the allocation is eligible to be rescheduled according
 // to its ReschedulePolicy and the current state of its reschedule trackers
 func (a *Allocation) RescheduleEligible(reschedulePolicy *ReschedulePolicy, failTime time.Time) bool {
+	if a.timeoutPreventsReschedule(reschedulePolicy, failTime) {
+		return false
+	}
 	return a.RescheduleTracker.RescheduleEligible(reschedulePolicy, failTime)
 }
 
+// TimedOut returns true if the last run of any task of the allocation was
+// killed for exceeding its max run duration or the deadline of its job.
+func (a *Allocation) TimedOut() bool {
+	for _, state := range a.TaskStates {
+		if state != nil && state.TimedOut {
+			return true
+		}
+	}
+	return false
+}
+
+// timeoutPreventsReschedule returns true if the allocation timed out and its
+// reschedule policy does not reschedule timeouts, or if the deadline of its
+// job has passed as its replacement would be killed right away.
+func (a *Allocation) timeoutPreventsReschedule(reschedulePolicy *ReschedulePolicy, now time.Time) bool {
+	if a.Job.DeadlineExceeded(now) {
+		return true
+	}
+	return a.TimedOut() && (reschedulePolicy == nil || !reschedulePolicy.RescheduleOnTimeout)
+}
+
 func (a *Allocation) RescheduleInfo() (int, int) {
 	return a.RescheduleTracker.rescheduleInfo(a.ReschedulePolicy(), a.LastEventTime())
 }
@@ -10758,6 +11480,10 @@ func (a *Allocation) NextRescheduleTime() (time.Time, bool) {
 		return time.Time{}, false
 	}
 
+	if a.timeoutPreventsReschedule(reschedulePolicy, time.Now()) {
+		return time.Time{}, false
+	}
+
 	return a.nextRescheduleTime(failTime, reschedulePolicy)
 }
 
@@ -11169,10 +11895,9 @@ func (a *Allocation) ToIdentityClaims(job *Job) *IdentityClaims {
 		JobID:        a.JobID,
 		AllocationID: a.ID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			// TODO: implement a refresh loop to prevent allocation identities from
-			// expiring before the allocation is terminal. Once that's implemented,
-			// add an ExpiresAt here ExpiresAt: &jwt.NumericDate{}
-			// https://github.com/hashicorp/nomad/issues/16258
+			// Allocation identities do not expire. Task identities with a
+			// TTL get an ExpiresAt from ToWorkloadIdentityClaims and are
+			// renewed by the client.
 			NotBefore: now,
 			IssuedAt:  now,
 		},
@@ -11191,6 +11916,27 @@ func (a *Allocation) ToTaskIdentityClaims(job *Job, taskName string) *IdentityCl
 	return claims
 }
 
+// ToWorkloadIdentityClaims returns the claims of the given workload identity
+// of the task. The audience and expiration of the claims are set from the
+// identity, with the expiration relative to now.
+func (a *Allocation) ToWorkloadIdentityClaims(job *Job, taskName string, wi *WorkloadIdentity, now time.Time) *IdentityClaims {
+	claims := a.ToTaskIdentityClaims(job, taskName)
+	if claims == nil || wi == nil {
+		return claims
+	}
+
+	now = now.UTC()
+	claims.NotBefore = jwt.NewNumericDate(now)
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	if len(wi.Audience) > 0 {
+		claims.Audience = slices.Clone(wi.Audience)
+	}
+	if wi.TTL > 0 {
+		claims.ExpiresAt = jwt.NewNumericDate(now.Add(wi.TTL))
+	}
+	return claims
+}
+
 // IdentityClaims are the input to a JWT identifying a workload. It
 // should never be serialized to msgpack unsigned.
 type IdentityClaims struct {
@@ -11677,6 +12423,7 @@ const (
 	EvalTriggerJobDeregister        = "job-deregister"
 	EvalTriggerPeriodicJob          = "periodic-job"
 	EvalTriggerNodeDrain            = "node-drain"
+	EvalTriggerNodeEviction         = "node-eviction"
 	EvalTriggerNodeUpdate           = "node-update"
 	EvalTriggerAllocStop            = "alloc-stop"
 	EvalTriggerScheduled            = "scheduled"
@@ -11739,6 +12486,11 @@ const (
 	// delete them.
 	CoreJobGlobalTokenExpiredGC = "global-token-expired-gc"
 
+	// CoreJobExecSessionGC is used for the garbage collection of exec
+	// sessions. We periodically scan for sessions which ended before the GC
+	// threshold and delete them.
+	CoreJobExecSessionGC = "exec-session-gc"
+
 	// CoreJobRootKeyRotateGC is used for periodic key rotation and
 	// garbage collection of unused encryption keys.
 	CoreJobRootKeyRotateOrGC = "root-key-rotate-gc"
@@ -12811,6 +13563,14 @@ type ACLToken struct {
 	// creation. This is a string version of a time.Duration like "2m".
 	ExpirationTTL time.Duration
 
+	// LastUsedTime is the last time the token authenticated a request to a
+	// server of the region, and LastUsedAddress the address the request was
+	// received from. The servers record usage in batches, so these lag behind
+	// actual usage by up to a minute. LastUsedTime is nil if the token was
+	// never used.
+	LastUsedTime    *time.Time
+	LastUsedAddress string
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -12869,17 +13629,19 @@ var (
 )
 
 type ACLTokenListStub struct {
-	AccessorID     string
-	Name           string
-	Type           string
-	Policies       []string
-	Roles          []*ACLTokenRoleLink
-	Global         bool
-	Hash           []byte
-	CreateTime     time.Time
-	ExpirationTime *time.Time
-	CreateIndex    uint64
-	ModifyIndex    uint64
+	AccessorID      string
+	Name            string
+	Type            string
+	Policies        []string
+	Roles           []*ACLTokenRoleLink
+	Global          bool
+	Hash            []byte
+	CreateTime      time.Time
+	ExpirationTime  *time.Time
+	LastUsedTime    *time.Time
+	LastUsedAddress string
+	CreateIndex     uint64
+	ModifyIndex     uint64
 }
 
 // SetHash is used to compute and set the hash of the ACL token. It only hashes
@@ -12921,23 +13683,30 @@ func (a *ACLToken) SetHash() []byte {
 
 func (a *ACLToken) Stub() *ACLTokenListStub {
 	return &ACLTokenListStub{
-		AccessorID:     a.AccessorID,
-		Name:           a.Name,
-		Type:           a.Type,
-		Policies:       a.Policies,
-		Roles:          a.Roles,
-		Global:         a.Global,
-		Hash:           a.Hash,
-		CreateTime:     a.CreateTime,
-		ExpirationTime: a.ExpirationTime,
-		CreateIndex:    a.CreateIndex,
-		ModifyIndex:    a.ModifyIndex,
+		AccessorID:      a.AccessorID,
+		Name:            a.Name,
+		Type:            a.Type,
+		Policies:        a.Policies,
+		Roles:           a.Roles,
+		Global:          a.Global,
+		Hash:            a.Hash,
+		CreateTime:      a.CreateTime,
+		ExpirationTime:  a.ExpirationTime,
+		LastUsedTime:    a.LastUsedTime,
+		LastUsedAddress: a.LastUsedAddress,
+		CreateIndex:     a.CreateIndex,
+		ModifyIndex:     a.ModifyIndex,
 	}
 }
 
 // ACLTokenListRequest is used to request a list of tokens
 type ACLTokenListRequest struct {
 	GlobalOnly bool
+
+	// UnusedSince, if set, only lists the tokens which were neither used nor
+	// created within this duration.
+	UnusedSince time.Duration
+
 	QueryOptions
 }
 
@@ -12983,6 +13752,58 @@ type ResolveACLTokenResponse struct {
 	QueryMeta
 }
 
+// ACLTokenCheckRequest is used to explain the effective permissions of a
+// token, or of a set of policies, on the namespace of the request and
+// optionally a node pool, host volume and variable path within it.
+type ACLTokenCheckRequest struct {
+	// AccessorID is the token to check. If neither it nor Policies are set,
+	// the token making the request is checked.
+	AccessorID string
+
+	// Policies simulates a token linked to the named policies.
+	Policies []string
+
+	NodePool     string
+	HostVolume   string
+	VariablePath string
+
+	QueryOptions
+}
+
+// ACLTokenCheckResponse holds the explanation of the effective permissions
+// requested by an ACLTokenCheckRequest.
+type ACLTokenCheckResponse struct {
+	// AccessorID is the accessor of the checked token, empty when simulating
+	// a set of policies.
+	AccessorID string
+
+	// Management is true if the checked token is a management token, in which
+	// case every capability is allowed.
+	Management bool
+
+	// Policies are the policies considered, directly linked or through a
+	// role.
+	Policies []*ACLTokenCheckPolicy
+
+	// Explanations holds the explanation of each resource checked.
+	Explanations []*acl.Explanation
+
+	QueryMeta
+}
+
+// ACLTokenCheckPolicy is a policy considered by an ACL token check.
+type ACLTokenCheckPolicy struct {
+	Name string
+
+	// Role is the name of the role linking the policy to the token, empty if
+	// the policy is linked directly.
+	Role string
+
+	// Missing is true if the policy does not exist, in which case it grants
+	// nothing.
+	Missing bool
+}
+
 // ACLTokenDeleteRequest is used to delete a set of tokens
 type ACLTokenDeleteRequest struct {
 	AccessorIDs []string
@@ -1980,6 +1980,20 @@ func TestTask_Validate(t *testing.T) {
 		"task level: distinct_hosts",
 		"task level: distinct_property",
 	)
+
+	task.Constraints = nil
+	task.MaxRunDuration = -time.Minute
+	err = task.Validate(JobTypeBatch, tg)
+	requireErrors(t, err, "MaxRunDuration must be a positive value")
+
+	// MaxRunDuration doesn't skip the progress deadline check
+	task.KillTimeout = 20 * time.Minute
+	tg.Update = &UpdateStrategy{ProgressDeadline: 10 * time.Minute}
+	err = task.Validate(JobTypeService, tg)
+	requireErrors(t, err,
+		"MaxRunDuration must be a positive value",
+		"KillTimout (20m0s) longer than the group's ProgressDeadline (10m0s)",
+	)
 }
 
 func TestTask_Validate_Resources(t *testing.T) {
@@ -2871,6 +2885,17 @@ func TestLogConfig_Equals(t *testing.T) {
 		require.False(t, a.Equal(b))
 	})
 
+	t.Run("sinks", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Sinks: []*LogSink{
+			{Type: LogSinkTypeOTLP, Address: "http://127.0.0.1:4318/v1/logs", Headers: map[string]string{"a": "b"}},
+		}}
+		b := a.Copy()
+		require.True(t, a.Equal(b))
+
+		b.Sinks[0].Headers["a"] = "c"
+		require.False(t, a.Equal(b))
+	})
+
 	t.Run("same", func(t *testing.T) {
 		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
 		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
@@ -2878,6 +2903,102 @@ func TestLogConfig_Equals(t *testing.T) {
 	})
 }
 
+func TestLogSink_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		sink   *LogSink
+		expErr string
+	}{
+		{
+			name: "syslog default address",
+			sink: &LogSink{Type: LogSinkTypeSyslog},
+		},
+		{
+			name: "syslog",
+			sink: &LogSink{Type: LogSinkTypeSyslog, Address: "tcp://127.0.0.1:514", Facility: "local0"},
+		},
+		{
+			name:   "syslog invalid scheme",
+			sink:   &LogSink{Type: LogSinkTypeSyslog, Address: "http://127.0.0.1:514"},
+			expErr: "scheme must be one of unix, tcp or udp",
+		},
+		{
+			name:   "syslog invalid facility",
+			sink:   &LogSink{Type: LogSinkTypeSyslog, Facility: "local9"},
+			expErr: `invalid syslog facility "local9"`,
+		},
+		{
+			name:   "journald relative socket",
+			sink:   &LogSink{Type: LogSinkTypeJournald, Address: "journal.sock"},
+			expErr: "must be an absolute socket path",
+		},
+		{
+			name: "otlp",
+			sink: &LogSink{Type: LogSinkTypeOTLP, Address: "https://collector:4318/v1/logs", Headers: map[string]string{"a": "b"}},
+		},
+		{
+			name:   "otlp missing address",
+			sink:   &LogSink{Type: LogSinkTypeOTLP},
+			expErr: "must be an http or https URL",
+		},
+		{
+			name:   "headers on syslog",
+			sink:   &LogSink{Type: LogSinkTypeSyslog, Headers: map[string]string{"a": "b"}},
+			expErr: `headers are only supported by "otlp" sinks`,
+		},
+		{
+			name: "file",
+			sink: &LogSink{Type: LogSinkTypeFile, Path: "task.json"},
+		},
+		{
+			name:   "file escapes log dir",
+			sink:   &LogSink{Type: LogSinkTypeFile, Path: "../task.json"},
+			expErr: "must be a file name in the task log directory",
+		},
+		{
+			name:   "file missing path",
+			sink:   &LogSink{Type: LogSinkTypeFile},
+			expErr: "file sinks require a path",
+		},
+		{
+			name:   "negative buffer size",
+			sink:   &LogSink{Type: LogSinkTypeJournald, BufferSize: -1},
+			expErr: "buffer_size must be positive",
+		},
+		{
+			name:   "unknown type",
+			sink:   &LogSink{Type: "kafka"},
+			expErr: `invalid sink type "kafka"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.sink.Validate()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+
+	// Sinks require logs to be enabled
+	logs := DefaultLogConfig()
+	logs.Disabled = true
+	logs.Sinks = []*LogSink{{Type: LogSinkTypeJournald}}
+	must.ErrorContains(t, logs.Validate(nil), "log sinks cannot be used when logs are disabled")
+
+	// File sinks use disk like the rotated stdout and stderr files
+	logs = DefaultLogConfig()
+	disk := &EphemeralDisk{SizeMB: 150}
+	must.NoError(t, logs.Validate(disk))
+	logs.Sinks = []*LogSink{{Type: LogSinkTypeFile, Path: "task.json"}}
+	must.ErrorContains(t, logs.Validate(disk), "log storage (200 MB)")
+}
+
 func TestTask_Validate_CSIPluginConfig(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4265,6 +4386,77 @@ func TestPeriodicConfig_DST(t *testing.T) {
 	require.Equal(e2, n2.UTC())
 }
 
+func TestPeriodicConfig_InvalidCatchup(t *testing.T) {
+	ci.Parallel(t)
+
+	p := &PeriodicConfig{Enabled: true, SpecType: PeriodicSpecCron, Spec: "@hourly", Catchup: "some"}
+	p.Canonicalize()
+	must.ErrorContains(t, p.Validate(), "Unknown catchup policy")
+
+	p.Catchup = PeriodicCatchupAll
+	p.CatchupWindow = -time.Minute
+	must.ErrorContains(t, p.Validate(), "Catchup window must be non-negative")
+
+	p.CatchupWindow = time.Hour
+	must.NoError(t, p.Validate())
+}
+
+func TestPeriodicConfig_MissedLaunches(t *testing.T) {
+	ci.Parallel(t)
+
+	p := &PeriodicConfig{
+		Enabled:  true,
+		SpecType: PeriodicSpecCron,
+		Specs:    []string{"0 * * * *", "30 2 * * *"},
+	}
+	p.Canonicalize()
+
+	last := time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2023, time.May, 1, 3, 0, 0, 0, time.UTC)
+
+	missed, err := p.MissedLaunches(last, now)
+	must.NoError(t, err)
+	must.Eq(t, []time.Time{
+		time.Date(2023, time.May, 1, 1, 0, 0, 0, time.UTC),
+		time.Date(2023, time.May, 1, 2, 0, 0, 0, time.UTC),
+		time.Date(2023, time.May, 1, 2, 30, 0, 0, time.UTC),
+		time.Date(2023, time.May, 1, 3, 0, 0, 0, time.UTC),
+	}, missed)
+
+	// The window drops older launches but keeps one on its boundary.
+	p.CatchupWindow = 30 * time.Minute
+	missed, err = p.MissedLaunches(last, now)
+	must.NoError(t, err)
+	must.Eq(t, []time.Time{
+		time.Date(2023, time.May, 1, 2, 30, 0, 0, time.UTC),
+		time.Date(2023, time.May, 1, 3, 0, 0, 0, time.UTC),
+	}, missed)
+
+	// Only the most recent launches are kept.
+	p.CatchupWindow = 0
+	missed, err = p.MissedLaunches(last.AddDate(0, 0, -30), now)
+	must.NoError(t, err)
+	must.Len(t, PeriodicCatchupMaxLaunches, missed)
+	must.Eq(t, now, missed[len(missed)-1])
+}
+
+func TestPeriodicLaunch_RecordLaunch(t *testing.T) {
+	ci.Parallel(t)
+
+	var prev *PeriodicLaunch
+	start := time.Unix(1700000000, 0)
+	for i := 0; i < PeriodicLaunchHistorySize+5; i++ {
+		launch := &PeriodicLaunch{ID: "foo", Namespace: DefaultNamespace}
+		launch.RecordLaunch(prev, fmt.Sprintf("foo/periodic-%d", i), start.Add(time.Duration(i)*time.Minute))
+		prev = launch
+	}
+
+	must.Len(t, PeriodicLaunchHistorySize, prev.History)
+	must.Eq(t, prev.Launch, prev.History[0].Launch)
+	must.Eq(t, fmt.Sprintf("foo/periodic-%d", PeriodicLaunchHistorySize+4), prev.History[0].JobID)
+	must.Eq(t, "foo/periodic-5", prev.History[PeriodicLaunchHistorySize-1].JobID)
+}
+
 func TestTaskLifecycleConfig_Validate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4680,6 +4872,19 @@ func TestTaskArtifact_Hash(t *testing.T) {
 			GetterMode:   "g",
 			RelativeDest: "i",
 		},
+		{
+			GetterSource: "b",
+			Signature: &ArtifactSignature{
+				Source: "j",
+			},
+		},
+		{
+			GetterSource: "b",
+			Signature: &ArtifactSignature{
+				Source: "j",
+				Keys:   []string{"k"},
+			},
+		},
 	}
 
 	// Map of hash to source
@@ -5007,6 +5212,8 @@ func TestAllocation_ShouldReschedule(t *testing.T) {
 		DesiredStatus      string
 		ReschedulePolicy   *ReschedulePolicy
 		RescheduleTrackers []*RescheduleEvent
+		TimedOut           bool
+		Deadline           time.Time
 		ShouldReschedule   bool
 	}
 	fail := time.Now()
@@ -5118,6 +5325,50 @@ func TestAllocation_ShouldReschedule(t *testing.T) {
 			},
 			ShouldReschedule: false,
 		},
+		{
+			Desc:             "Reschedule timed out allocation",
+			ClientStatus:     AllocClientStatusFailed,
+			DesiredStatus:    AllocDesiredStatusRun,
+			FailTime:         fail,
+			ReschedulePolicy: &ReschedulePolicy{Attempts: 1, Interval: 1 * time.Minute},
+			TimedOut:         true,
+			ShouldReschedule: false,
+		},
+		{
+			Desc:          "Reschedule timed out allocation with policy rescheduling timeouts",
+			ClientStatus:  AllocClientStatusFailed,
+			DesiredStatus: AllocDesiredStatusRun,
+			FailTime:      fail,
+			ReschedulePolicy: &ReschedulePolicy{
+				Attempts:            1,
+				Interval:            1 * time.Minute,
+				RescheduleOnTimeout: true,
+			},
+			TimedOut:         true,
+			ShouldReschedule: true,
+		},
+		{
+			Desc:          "Reschedule past the deadline of the job",
+			ClientStatus:  AllocClientStatusFailed,
+			DesiredStatus: AllocDesiredStatusRun,
+			FailTime:      fail,
+			ReschedulePolicy: &ReschedulePolicy{
+				Attempts:            1,
+				Interval:            1 * time.Minute,
+				RescheduleOnTimeout: true,
+			},
+			Deadline:         fail.Add(-time.Second),
+			ShouldReschedule: false,
+		},
+		{
+			Desc:             "Reschedule before the deadline of the job",
+			ClientStatus:     AllocClientStatusFailed,
+			DesiredStatus:    AllocDesiredStatusRun,
+			FailTime:         fail,
+			ReschedulePolicy: &ReschedulePolicy{Attempts: 1, Interval: 1 * time.Minute},
+			Deadline:         fail.Add(time.Hour),
+			ShouldReschedule: true,
+		},
 	}
 
 	for _, state := range harness {
@@ -5125,6 +5376,8 @@ func TestAllocation_ShouldReschedule(t *testing.T) {
 		alloc.DesiredStatus = state.DesiredStatus
 		alloc.ClientStatus = state.ClientStatus
 		alloc.RescheduleTracker = &RescheduleTracker{state.RescheduleTrackers}
+		alloc.TaskStates = map[string]*TaskState{"web": {TimedOut: state.TimedOut}}
+		alloc.Job = &Job{DispatchDeadline: state.Deadline}
 
 		t.Run(state.Desc, func(t *testing.T) {
 			if got := alloc.ShouldReschedule(state.ReschedulePolicy, state.FailTime); got != state.ShouldReschedule {
@@ -6147,6 +6400,15 @@ func TestVault_Validate(t *testing.T) {
 	if !strings.Contains(err.Error(), "root") {
 		t.Fatalf("Expected root error")
 	}
+
+	// Policies are set by the role when logging in with workload identities
+	v = &Vault{
+		Role:       "nomad-workloads",
+		ChangeMode: VaultChangeModeNoop,
+	}
+	if err := v.Validate(); err != nil {
+		t.Fatalf("Expected no error with role: %v", err)
+	}
 }
 
 func TestVault_Copy(t *testing.T) {
@@ -6859,6 +7121,13 @@ func TestNode_Copy(t *testing.T) {
 				UpdateTime:        time.Now(),
 			},
 		},
+		Conditions: []*NodeCondition{
+			{
+				Type:    NodeConditionMemoryPressure,
+				Message: "memory usage of 97% is over the eviction threshold of 95%",
+				Since:   time.Now(),
+			},
+		},
 	}
 	node.ComputeClass()
 
@@ -6872,6 +7141,9 @@ func TestNode_Copy(t *testing.T) {
 	require.Equal(node.Events, node2.Events)
 	require.Equal(node.DrainStrategy, node2.DrainStrategy)
 	require.Equal(node.Drivers, node2.Drivers)
+	require.Equal(node.Conditions, node2.Conditions)
+	require.NotSame(node.Conditions[0], node2.Conditions[0])
+	require.True(node2.UnderPressure())
 }
 
 func TestNode_GetID(t *testing.T) {
@@ -7653,6 +7925,7 @@ func TestTaskArtifact_Equal(t *testing.T) {
 		GetterHeaders: map[string]string{"b": "B"},
 		GetterMode:    "file",
 		RelativeDest:  "./local",
+		Signature:     &ArtifactSignature{Source: "source.minisig", Format: "minisign"},
 	}, []must.Tweak[*TaskArtifact]{{
 		Field: "GetterSource",
 		Apply: func(ta *TaskArtifact) { ta.GetterSource = "other" },
@@ -7668,9 +7941,58 @@ func TestTaskArtifact_Equal(t *testing.T) {
 	}, {
 		Field: "RelativeDest",
 		Apply: func(ta *TaskArtifact) { ta.RelativeDest = "./alloc" },
+	}, {
+		Field: "Signature",
+		Apply: func(ta *TaskArtifact) { ta.Signature = nil },
+	}})
+}
+
+func TestArtifactSignature_Equal(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Equal[*ArtifactSignature](t, nil, nil)
+	must.NotEqual[*ArtifactSignature](t, nil, new(ArtifactSignature))
+
+	must.StructEqual(t, &ArtifactSignature{
+		Source:   "source.minisig",
+		Format:   "minisign",
+		Keys:     []string{"build"},
+		Variable: "nomad/jobs/example",
+	}, []must.Tweak[*ArtifactSignature]{{
+		Field: "Source",
+		Apply: func(s *ArtifactSignature) { s.Source = "other.minisig" },
+	}, {
+		Field: "Format",
+		Apply: func(s *ArtifactSignature) { s.Format = "cosign" },
+	}, {
+		Field: "Keys",
+		Apply: func(s *ArtifactSignature) { s.Keys = []string{"release"} },
+	}, {
+		Field: "Variable",
+		Apply: func(s *ArtifactSignature) { s.Variable = "nomad/jobs/other" },
 	}})
 }
 
+func TestArtifactSignature_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	sig := &ArtifactSignature{Source: "https://example.com/app.tar.gz.minisig"}
+	must.NoError(t, sig.Validate())
+	must.Eq(t, "minisign", sig.Format)
+
+	sig = &ArtifactSignature{Format: "gpg", Keys: []string{""}}
+	err := sig.Validate()
+	must.ErrorContains(t, err, "source must be specified")
+	must.ErrorContains(t, err, `invalid signature format "gpg"`)
+	must.ErrorContains(t, err, "key names must not be empty")
+
+	artifact := &TaskArtifact{
+		GetterSource: "https://example.com/app.tar.gz",
+		Signature:    &ArtifactSignature{Format: "cosign"},
+	}
+	must.ErrorContains(t, artifact.Validate(), "signature: source must be specified")
+}
+
 func TestVault_Equal(t *testing.T) {
 	ci.Parallel(t)
 
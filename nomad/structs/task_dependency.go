@@ -0,0 +1,277 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package structs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// TaskDependencyConditionStarted is met once the task has started.
+	TaskDependencyConditionStarted = "started"
+
+	// TaskDependencyConditionHealthy is met while all the checks of the
+	// task's services are passing.
+	TaskDependencyConditionHealthy = "healthy"
+
+	// TaskDependencyConditionComplete is met once the task completed
+	// successfully.
+	TaskDependencyConditionComplete = "complete"
+)
+
+// TaskDependency declares a sibling task that must meet a condition before
+// the task declaring it is allowed to start. Tasks are shut down in the
+// reverse order of their dependencies.
+type TaskDependency struct {
+	// Task is the name of the sibling task.
+	Task string
+
+	// Condition is the condition the sibling task must meet. Defaults to
+	// TaskDependencyConditionStarted.
+	Condition string
+}
+
+func (d *TaskDependency) Copy() *TaskDependency {
+	if d == nil {
+		return nil
+	}
+	nd := new(TaskDependency)
+	*nd = *d
+	return nd
+}
+
+func (d *TaskDependency) Equal(o *TaskDependency) bool {
+	if d == nil || o == nil {
+		return d == o
+	}
+	switch {
+	case d.Task != o.Task:
+		return false
+	case d.Condition != o.Condition:
+		return false
+	}
+	return true
+}
+
+func (d *TaskDependency) Canonicalize() {
+	if d == nil {
+		return
+	}
+	if d.Condition == "" {
+		d.Condition = TaskDependencyConditionStarted
+	}
+}
+
+// CopySliceTaskDependencies returns a deep copy of the passed dependencies.
+func CopySliceTaskDependencies(s []*TaskDependency) []*TaskDependency {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	c := make([]*TaskDependency, l)
+	for i, v := range s {
+		c[i] = v.Copy()
+	}
+	return c
+}
+
+// validateTaskDependencies validates the dependencies declared between the
+// tasks of the group and ensures they do not form a cycle.
+func (tg *TaskGroup) validateTaskDependencies(jobType string) error {
+	var mErr multierror.Error
+
+	tasks := make(map[string]*Task, len(tg.Tasks))
+	for _, task := range tg.Tasks {
+		tasks[task.Name] = task
+	}
+
+	for _, task := range tg.Tasks {
+		if len(task.DependsOn) == 0 {
+			continue
+		}
+		if !task.IsMain() {
+			_ = multierror.Append(&mErr, fmt.Errorf(
+				"Task %s: dependencies can only be declared by main tasks", task.Name))
+			continue
+		}
+
+		seen := make(map[string]struct{}, len(task.DependsOn))
+		for i, dep := range task.DependsOn {
+			if dep == nil || dep.Task == "" {
+				_ = multierror.Append(&mErr, fmt.Errorf(
+					"Task %s: dependency %d must specify a task", task.Name, i+1))
+				continue
+			}
+			if _, ok := seen[dep.Task]; ok {
+				_ = multierror.Append(&mErr, fmt.Errorf(
+					"Task %s: duplicate dependency on task %q", task.Name, dep.Task))
+			}
+			seen[dep.Task] = struct{}{}
+
+			upstream, ok := tasks[dep.Task]
+			switch {
+			case dep.Task == task.Name:
+				_ = multierror.Append(&mErr, fmt.Errorf(
+					"Task %s: task can not depend on itself", task.Name))
+				continue
+			case !ok:
+				_ = multierror.Append(&mErr, fmt.Errorf(
+					"Task %s: dependency on undefined task %q", task.Name, dep.Task))
+				continue
+			case !upstream.IsMain():
+				_ = multierror.Append(&mErr, fmt.Errorf(
+					"Task %s: dependency on task %q must be a main task", task.Name, dep.Task))
+			}
+
+			switch dep.Condition {
+			case TaskDependencyConditionStarted:
+			case TaskDependencyConditionComplete:
+				// Main tasks of other job types are restarted when they exit
+				// so they never complete.
+				if jobType != JobTypeBatch && jobType != JobTypeSysBatch {
+					_ = multierror.Append(&mErr, fmt.Errorf(
+						"Task %s: %q condition can only be used with %q or %q scheduler",
+						task.Name, dep.Condition, JobTypeBatch, JobTypeSysBatch))
+				}
+			case TaskDependencyConditionHealthy:
+				if !upstream.hasServiceChecks() {
+					_ = multierror.Append(&mErr, fmt.Errorf(
+						"Task %s: %q condition requires task %q to have service checks",
+						task.Name, dep.Condition, dep.Task))
+				}
+			default:
+				_ = multierror.Append(&mErr, fmt.Errorf(
+					"Task %s: invalid dependency condition %q", task.Name, dep.Condition))
+			}
+		}
+	}
+
+	if cycle := tg.taskDependencyCycle(); len(cycle) > 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf(
+			"Task dependencies form a cycle: %s", strings.Join(cycle, " -> ")))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// taskDependencyCycle returns the names of the tasks forming a dependency
+// cycle, starting and ending with the same task, or nil if there is none.
+func (tg *TaskGroup) taskDependencyCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	tasks := make(map[string]*Task, len(tg.Tasks))
+	for _, task := range tg.Tasks {
+		tasks[task.Name] = task
+	}
+
+	state := make(map[string]int, len(tg.Tasks))
+	var path []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		}
+
+		task, ok := tasks[name]
+		if !ok {
+			return nil
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range task.DependsOn {
+			if dep == nil || dep.Task == name {
+				continue
+			}
+			if cycle := visit(dep.Task); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, task := range tg.Tasks {
+		if cycle := visit(task.Name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// TaskDependencyLevels groups the names of the tasks of the group by their
+// depth in the dependency graph. Tasks without dependencies are in the first
+// level and every other task is one level deeper than its deepest
+// dependency. The group's dependencies must not form a cycle.
+func (tg *TaskGroup) TaskDependencyLevels() [][]string {
+	tasks := make(map[string]*Task, len(tg.Tasks))
+	for _, task := range tg.Tasks {
+		tasks[task.Name] = task
+	}
+
+	depths := make(map[string]int, len(tg.Tasks))
+	var depth func(name string) int
+	depth = func(name string) int {
+		if d, ok := depths[name]; ok {
+			return d
+		}
+
+		// Guard against cycles in case the group was not validated.
+		depths[name] = 0
+
+		d := 0
+		for _, dep := range tasks[name].DependsOn {
+			if dep == nil {
+				continue
+			}
+			if _, ok := tasks[dep.Task]; !ok {
+				continue
+			}
+			d = max(d, depth(dep.Task)+1)
+		}
+		depths[name] = d
+		return d
+	}
+
+	var levels [][]string
+	for _, task := range tg.Tasks {
+		d := depth(task.Name)
+		for len(levels) <= d {
+			levels = append(levels, nil)
+		}
+		levels[d] = append(levels[d], task.Name)
+	}
+	for _, level := range levels {
+		sort.Strings(level)
+	}
+	return levels
+}
+
+// hasServiceChecks returns true if any of the task's services define a check.
+func (t *Task) hasServiceChecks() bool {
+	for _, service := range t.Services {
+		if len(service.Checks) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestTaskGroup_ValidateTaskDependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name      string
+		tg        func() *TaskGroup
+		jobType   string
+		expectErr string
+	}{
+		{
+			name: "valid",
+			tg: func() *TaskGroup {
+				return testTaskGroupWithDependencies()
+			},
+		},
+		{
+			name: "missing task",
+			tg: func() *TaskGroup {
+				tg := testTaskGroupWithDependencies()
+				tg.Tasks[1].DependsOn[0].Task = ""
+				return tg
+			},
+			expectErr: "Task app: dependency 1 must specify a task",
+		},
+		{
+			name: "undefined task",
+			tg: func() *TaskGroup {
+				tg := testTaskGroupWithDependencies()
+				tg.Tasks[1].DependsOn[0].Task = "cache"
+				return tg
+			},
+			expectErr: `Task app: dependency on undefined task "cache"`,
+		},
+		{
+			name: "self",
+			tg: func() *TaskGroup {
+				tg := testTaskGroupWithDependencies()
+				tg.Tasks[1].DependsOn[0].Task = "app"
+				return tg
+			},
+			expectErr: "Task app: task can not depend on itself",
+		},
+		{
+			name: "duplicate",
+			tg: func() *TaskGroup {
+				tg := testTaskGroupWithDependencies()
+				tg.Tasks[1].DependsOn = append(tg.Tasks[1].DependsOn, tg.Tasks[1].DependsOn[0].Copy())
+				return tg
+			},
+			expectErr: `Task app: duplicate dependency on task "db"`,
+		},
+		{
+			name: "invalid condition",
+			tg: func() *TaskGroup {
+				tg := testTaskGroupWithDependencies()
+				tg.Tasks[1].DependsOn[0].Condition = "ready"
+				return tg
+			},
+			expectErr: `Task app: invalid dependency condition "ready"`,
+		},
+		{
+			name: "healthy without checks",
+			tg: func() *TaskGroup {
+				tg := testTaskGroupWithDependencies()
+				tg.Tasks[0].Services = nil
+				return tg
+			},
+			expectErr: `Task app: "healthy" condition requires task "db" to have service checks`,
+		},
+		{
+			name: "lifecycle task declaring dependencies",
+			tg: func() *TaskGroup {
+				tg := testTaskGroupWithDependencies()
+				tg.Tasks[1].Lifecycle = &TaskLifecycleConfig{Hook: TaskLifecycleHookPoststart}
+				return tg
+			},
+			expectErr: "Task app: dependencies can only be declared by main tasks",
+		},
+		{
+			name: "dependency on lifecycle task",
+			tg: func() *TaskGroup {
+				tg := testTaskGroupWithDependencies()
+				tg.Tasks[0].Lifecycle = &TaskLifecycleConfig{Hook: TaskLifecycleHookPrestart}
+				return tg
+			},
+			expectErr: `Task app: dependency on task "db" must be a main task`,
+		},
+		{
+			name: "complete in batch job",
+			tg: func() *TaskGroup {
+				tg := testTaskGroupWithDependencies()
+				tg.Tasks[2].DependsOn[0].Condition = TaskDependencyConditionComplete
+				return tg
+			},
+			jobType: JobTypeBatch,
+		},
+		{
+			name: "complete in service job",
+			tg: func() *TaskGroup {
+				tg := testTaskGroupWithDependencies()
+				tg.Tasks[2].DependsOn[0].Condition = TaskDependencyConditionComplete
+				return tg
+			},
+			expectErr: `Task web: "complete" condition can only be used with "batch" or "sysbatch" scheduler`,
+		},
+		{
+			name: "cycle",
+			tg: func() *TaskGroup {
+				tg := testTaskGroupWithDependencies()
+				tg.Tasks[0].DependsOn = []*TaskDependency{
+					{Task: "web", Condition: TaskDependencyConditionStarted},
+				}
+				return tg
+			},
+			expectErr: "Task dependencies form a cycle: db -> web -> app -> db",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			jobType := tc.jobType
+			if jobType == "" {
+				jobType = JobTypeService
+			}
+			err := tc.tg().validateTaskDependencies(jobType)
+			if tc.expectErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expectErr)
+			}
+		})
+	}
+}
+
+func TestTaskGroup_TaskDependencyLevels(t *testing.T) {
+	ci.Parallel(t)
+
+	tg := testTaskGroupWithDependencies()
+	tg.Tasks = append(tg.Tasks,
+		&Task{Name: "logs"},
+		&Task{
+			Name: "metrics",
+			DependsOn: []*TaskDependency{
+				{Task: "db", Condition: TaskDependencyConditionStarted},
+			},
+		},
+	)
+
+	must.Eq(t, [][]string{
+		{"db", "logs"},
+		{"app", "metrics"},
+		{"web"},
+	}, tg.TaskDependencyLevels())
+
+	// A group without dependencies has a single level.
+	must.Eq(t, [][]string{{"web"}}, MockJob().TaskGroups[0].TaskDependencyLevels())
+}
+
+func TestTask_Canonicalize_Dependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	job := MockJob()
+	tg := job.TaskGroups[0]
+	task := tg.Tasks[0]
+	task.DependsOn = []*TaskDependency{{Task: "db"}}
+
+	task.Canonicalize(job, tg)
+	must.Eq(t, TaskDependencyConditionStarted, task.DependsOn[0].Condition)
+}
+
+// testTaskGroupWithDependencies returns a group where "web" waits for "app"
+// to start, which waits for "db" to be healthy.
+func testTaskGroupWithDependencies() *TaskGroup {
+	return &TaskGroup{
+		Name: "cache",
+		Tasks: []*Task{
+			{
+				Name: "db",
+				Services: []*Service{
+					{
+						Name:   "db",
+						Checks: []*ServiceCheck{{Name: "alive", Type: ServiceCheckTCP}},
+					},
+				},
+			},
+			{
+				Name: "app",
+				DependsOn: []*TaskDependency{
+					{Task: "db", Condition: TaskDependencyConditionHealthy},
+				},
+			},
+			{
+				Name: "web",
+				DependsOn: []*TaskDependency{
+					{Task: "app", Condition: TaskDependencyConditionStarted},
+				},
+			},
+		},
+	}
+}
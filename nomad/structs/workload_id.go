@@ -3,9 +3,52 @@
 
 package structs
 
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// WorkloadIdentityDefaultName is the name of the default workload
+	// identity of a task. It is exposed through NOMAD_TOKEN and the
+	// secrets/nomad_token file, and is used to authenticate to Nomad.
+	WorkloadIdentityDefaultName = "default"
+
+	// WorkloadIdentityDefaultAud is the audience of the default workload
+	// identity. Only identities without an audience or with this audience
+	// are accepted by Nomad.
+	WorkloadIdentityDefaultAud = "nomad.io"
+
+	// WorkloadIdentityVaultName is the name of the workload identity used by
+	// tasks to log in to Vault with the JWT auth method.
+	WorkloadIdentityVaultName = "vault"
+
+	// WorkloadIdentityVaultAud is the default audience of the workload
+	// identity used to log in to Vault.
+	WorkloadIdentityVaultAud = "vault.io"
+)
+
+var (
+	// validWorkloadIdentityName is used to validate workload identity
+	// names. Names are used in environment variables and file names so they
+	// are restricted to alphanumeric characters and underscores.
+	validWorkloadIdentityName = regexp.MustCompile("^[a-zA-Z0-9_]{1,128}$")
+)
+
 // WorkloadIdentity is the jobspec block which determines if and how a workload
 // identity is exposed to tasks similar to the Vault block.
 type WorkloadIdentity struct {
+	// Name is used to refer to the identity. The default identity is named
+	// "default" and additional identities must have a unique name.
+	Name string
+
+	// Audience is the valid recipients of the identity (the "aud" claim).
+	Audience []string
+
 	// Env injects the Workload Identity into the Task's environment if
 	// set.
 	Env bool
@@ -13,6 +56,11 @@ type WorkloadIdentity struct {
 	// File writes the Workload Identity into the Task's secrets directory
 	// if set.
 	File bool
+
+	// TTL is the lifetime of the identity (the "exp" claim). The identity
+	// is renewed by the client before it expires. A zero TTL means the
+	// identity never expires.
+	TTL time.Duration
 }
 
 func (wi *WorkloadIdentity) Copy() *WorkloadIdentity {
@@ -20,8 +68,11 @@ func (wi *WorkloadIdentity) Copy() *WorkloadIdentity {
 		return nil
 	}
 	return &WorkloadIdentity{
-		Env:  wi.Env,
-		File: wi.File,
+		Name:     wi.Name,
+		Audience: slices.Clone(wi.Audience),
+		Env:      wi.Env,
+		File:     wi.File,
+		TTL:      wi.TTL,
 	}
 }
 
@@ -30,6 +81,14 @@ func (wi *WorkloadIdentity) Equal(other *WorkloadIdentity) bool {
 		return wi == other
 	}
 
+	if wi.Name != other.Name {
+		return false
+	}
+
+	if !slices.Equal(wi.Audience, other.Audience) {
+		return false
+	}
+
 	if wi.Env != other.Env {
 		return false
 	}
@@ -38,5 +97,78 @@ func (wi *WorkloadIdentity) Equal(other *WorkloadIdentity) bool {
 		return false
 	}
 
+	if wi.TTL != other.TTL {
+		return false
+	}
+
 	return true
 }
+
+// Canonicalize sets the name of unnamed identities to the default name and
+// the audience of the default identity to the Nomad audience.
+func (wi *WorkloadIdentity) Canonicalize() {
+	if wi == nil {
+		return
+	}
+
+	if wi.Name == "" {
+		wi.Name = WorkloadIdentityDefaultName
+	}
+
+	if wi.IsDefault() && len(wi.Audience) == 0 {
+		wi.Audience = []string{WorkloadIdentityDefaultAud}
+	}
+}
+
+// Validate returns an error if the identity is invalid.
+func (wi *WorkloadIdentity) Validate() error {
+	if wi == nil {
+		return fmt.Errorf("must not be nil")
+	}
+
+	var mErr multierror.Error
+
+	if !wi.IsDefault() && !validWorkloadIdentityName.MatchString(wi.Name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid name %q", wi.Name))
+	}
+
+	if !wi.IsDefault() && len(wi.Audience) == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("identity %q must specify an audience", wi.Name))
+	}
+
+	for i, aud := range wi.Audience {
+		if aud == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("audience %d must not be empty", i+1))
+		}
+	}
+
+	if wi.TTL < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("ttl must be >= 0"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// IsDefault returns true if this is the default identity of a task.
+func (wi *WorkloadIdentity) IsDefault() bool {
+	return wi.Name == WorkloadIdentityDefaultName || wi.Name == ""
+}
+
+// WorkloadIdentityRequest identifies a workload identity of a task to sign.
+type WorkloadIdentityRequest struct {
+	AllocID      string
+	TaskName     string
+	IdentityName string
+}
+
+// SignedWorkloadIdentity is a signed workload identity.
+type SignedWorkloadIdentity struct {
+	WorkloadIdentityRequest
+
+	// JWT is the encoded and signed identity.
+	JWT string
+
+	// Expiration is when the identity expires. It is zero for identities
+	// without a TTL.
+	Expiration time.Time
+}
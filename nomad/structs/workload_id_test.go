@@ -5,8 +5,10 @@ package structs
 
 import (
 	"testing"
+	"time"
 
 	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/uuid"
 	"github.com/shoenig/test/must"
 )
 
@@ -32,4 +34,151 @@ func TestWorkloadIdentity_Equal(t *testing.T) {
 
 	newWI.File = true
 	must.NotEqual(t, orig, newWI)
+
+	newWI.File = false
+	newWI.Audience = []string{"example.com"}
+	must.NotEqual(t, orig, newWI)
+
+	orig.Audience = []string{"example.com"}
+	must.Equal(t, orig, newWI)
+
+	newWI.TTL = time.Hour
+	must.NotEqual(t, orig, newWI)
+
+	orig.TTL = time.Hour
+	orig.Name = "consul"
+	must.NotEqual(t, orig, newWI)
+}
+
+func TestWorkloadIdentity_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	wi := &WorkloadIdentity{}
+	wi.Canonicalize()
+	must.Eq(t, WorkloadIdentityDefaultName, wi.Name)
+	must.Eq(t, []string{WorkloadIdentityDefaultAud}, wi.Audience)
+
+	wi = &WorkloadIdentity{Name: "consul"}
+	wi.Canonicalize()
+	must.Eq(t, "consul", wi.Name)
+	must.Nil(t, wi.Audience)
+}
+
+func TestWorkloadIdentity_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name      string
+		wi        *WorkloadIdentity
+		expectErr string
+	}{
+		{
+			name: "default",
+			wi:   &WorkloadIdentity{Name: WorkloadIdentityDefaultName, Env: true},
+		},
+		{
+			name: "named",
+			wi: &WorkloadIdentity{
+				Name:     "consul_1",
+				Audience: []string{"consul.io"},
+				TTL:      time.Hour,
+			},
+		},
+		{
+			name:      "invalid name",
+			wi:        &WorkloadIdentity{Name: "consul-1", Audience: []string{"consul.io"}},
+			expectErr: `invalid name "consul-1"`,
+		},
+		{
+			name:      "missing audience",
+			wi:        &WorkloadIdentity{Name: "consul"},
+			expectErr: `identity "consul" must specify an audience`,
+		},
+		{
+			name:      "empty audience",
+			wi:        &WorkloadIdentity{Name: "consul", Audience: []string{""}},
+			expectErr: "audience 1 must not be empty",
+		},
+		{
+			name:      "negative ttl",
+			wi:        &WorkloadIdentity{Name: "consul", Audience: []string{"consul.io"}, TTL: -1},
+			expectErr: "ttl must be >= 0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.wi.Validate()
+			if tc.expectErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expectErr)
+			}
+		})
+	}
+}
+
+func TestTask_Validate_Identities(t *testing.T) {
+	ci.Parallel(t)
+
+	job := MockJob()
+	tg := job.TaskGroups[0]
+	task := tg.Tasks[0]
+	task.Identities = []*WorkloadIdentity{
+		{Name: "consul", Audience: []string{"consul.io"}},
+		{Name: "consul", Audience: []string{"consul.io"}},
+		{Name: WorkloadIdentityDefaultName, Audience: []string{"nomad.io"}},
+	}
+	task.Identity = &WorkloadIdentity{Name: "vault"}
+
+	err := task.Validate(job.Type, tg)
+	must.ErrorContains(t, err, "Identity 2 has same name as 1")
+	must.ErrorContains(t, err, `Identity 3 can not be named "default"`)
+	must.ErrorContains(t, err, `Identity must be named "default"`)
+}
+
+func TestTask_LookupIdentity(t *testing.T) {
+	ci.Parallel(t)
+
+	task := &Task{
+		Identities: []*WorkloadIdentity{
+			{Name: "consul", Audience: []string{"consul.io"}},
+		},
+	}
+
+	wi := task.LookupIdentity(WorkloadIdentityDefaultName)
+	must.NotNil(t, wi)
+	must.Eq(t, WorkloadIdentityDefaultName, wi.Name)
+
+	must.Eq(t, task.Identities[0], task.LookupIdentity("consul"))
+	must.Nil(t, task.LookupIdentity("vault"))
+
+	task.Identity = &WorkloadIdentity{Name: WorkloadIdentityDefaultName, Env: true}
+	must.Eq(t, task.Identity, task.LookupIdentity(WorkloadIdentityDefaultName))
+}
+
+func TestAllocation_ToWorkloadIdentityClaims(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := &Allocation{
+		ID:        uuid.Generate(),
+		Namespace: "default",
+		JobID:     "example",
+	}
+	now := time.Now()
+
+	claims := alloc.ToWorkloadIdentityClaims(nil, "web", &WorkloadIdentity{
+		Name:     "consul",
+		Audience: []string{"consul.io"},
+		TTL:      time.Hour,
+	}, now)
+	must.Eq(t, "web", claims.TaskName)
+	must.Eq(t, []string{"consul.io"}, []string(claims.Audience))
+	must.NotNil(t, claims.ExpiresAt)
+	must.Eq(t, now.Add(time.Hour).Unix(), claims.ExpiresAt.Unix())
+
+	// Identities without a TTL do not expire.
+	claims = alloc.ToWorkloadIdentityClaims(nil, "web", &WorkloadIdentity{Name: "default"}, now)
+	must.Nil(t, claims.ExpiresAt)
+	must.Nil(t, claims.Audience)
 }
@@ -289,7 +289,9 @@ func NewVaultClient(c *config.VaultConfig, logger log.Logger, purgeFn PurgeVault
 		entHandler:         delegate,
 	}
 
-	if v.config.IsEnabled() {
+	// When tasks log in with their workload identity the servers don't hold
+	// a Vault token, so there is no connection to establish.
+	if v.config.IsEnabled() && !v.config.UsesIdentity() {
 		if err := v.buildClient(); err != nil {
 			return nil, err
 		}
@@ -403,7 +405,7 @@ func (v *vaultClient) SetConfig(config *config.VaultConfig) error {
 	v.config = config
 
 	// Check if we should relaunch
-	if v.config.IsEnabled() {
+	if v.config.IsEnabled() && !v.config.UsesIdentity() {
 		// Rebuild the client
 		if err := v.buildClient(); err != nil {
 			return err
@@ -950,11 +952,13 @@ func (v *vaultClient) ConnectionEstablished() (bool, error) {
 	return v.connEstablished, v.connEstablishedErr
 }
 
-// Enabled returns whether the client is active
+// Enabled returns whether the client is active. The client is not enabled
+// when tasks log in with their workload identity, as the servers don't derive
+// tokens for them.
 func (v *vaultClient) Enabled() bool {
 	v.l.Lock()
 	defer v.l.Unlock()
-	return v.config.IsEnabled()
+	return v.config.IsEnabled() && !v.config.UsesIdentity()
 }
 
 // Active returns whether the client is active
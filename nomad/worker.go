@@ -637,6 +637,12 @@ func (w *Worker) ServersMeetMinimumVersion(minVersion *version.Version, checkFai
 	return ServersMeetMinimumVersion(w.srv.Members(), w.srv.Region(), minVersion, checkFailedServers)
 }
 
+// SchedulerExtensions returns the scheduler extensions configured on the
+// server. This allows the worker to provide them to the generic scheduler.
+func (w *Worker) SchedulerExtensions() []*scheduler.Extension {
+	return w.srv.schedulerExtensions.Extensions()
+}
+
 // SubmitPlan is used to submit a plan for consideration. This allows
 // the worker to act as the planner for the scheduler.
 func (w *Worker) SubmitPlan(plan *structs.Plan) (*structs.PlanResult, scheduler.State, error) {
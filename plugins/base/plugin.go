@@ -23,6 +23,9 @@ const (
 
 	// PluginTypeDevice implements the device plugin interface
 	PluginTypeDevice = "device"
+
+	// PluginTypeScheduler implements the scheduler extension plugin interface
+	PluginTypeScheduler = "scheduler"
 )
 
 var (
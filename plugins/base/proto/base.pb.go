@@ -29,21 +29,24 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 type PluginType int32
 
 const (
-	PluginType_UNKNOWN PluginType = 0
-	PluginType_DRIVER  PluginType = 2
-	PluginType_DEVICE  PluginType = 3
+	PluginType_UNKNOWN   PluginType = 0
+	PluginType_DRIVER    PluginType = 2
+	PluginType_DEVICE    PluginType = 3
+	PluginType_SCHEDULER PluginType = 4
 )
 
 var PluginType_name = map[int32]string{
 	0: "UNKNOWN",
 	2: "DRIVER",
 	3: "DEVICE",
+	4: "SCHEDULER",
 }
 
 var PluginType_value = map[string]int32{
-	"UNKNOWN": 0,
-	"DRIVER":  2,
-	"DEVICE":  3,
+	"UNKNOWN":   0,
+	"DRIVER":    2,
+	"DEVICE":    3,
+	"SCHEDULER": 4,
 }
 
 func (x PluginType) String() string {
@@ -434,40 +437,41 @@ func init() {
 }
 
 var fileDescriptor_19edef855873449e = []byte{
-	// 521 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0x51, 0x6f, 0x12, 0x41,
-	0x10, 0xee, 0x01, 0xd2, 0x30, 0x40, 0x73, 0x2c, 0x9a, 0x10, 0x12, 0x13, 0x72, 0xb1, 0x09, 0x31,
-	0xcd, 0x12, 0x51, 0xd4, 0x47, 0x85, 0xf2, 0x40, 0x4c, 0xb1, 0x59, 0x14, 0x8d, 0x31, 0xb9, 0x6c,
-	0x8f, 0x2d, 0x77, 0x11, 0xf6, 0xd6, 0xdb, 0x6b, 0x63, 0x4d, 0x7c, 0xf2, 0xd9, 0x5f, 0xe4, 0xa3,
-	0x7f, 0xcc, 0xdc, 0xee, 0x5e, 0x39, 0x5a, 0x4d, 0x8f, 0xa7, 0x1b, 0x66, 0xbe, 0xef, 0x9b, 0x99,
-	0x8f, 0x59, 0x78, 0x28, 0x56, 0x17, 0xcb, 0x80, 0xcb, 0xde, 0x19, 0x95, 0xac, 0x27, 0xa2, 0x30,
-	0x0e, 0x55, 0x88, 0x55, 0x88, 0x1c, 0x9f, 0x4a, 0x3f, 0xf0, 0xc2, 0x48, 0x60, 0x1e, 0xae, 0xe9,
-	0x02, 0x1b, 0x38, 0xde, 0x60, 0xda, 0x87, 0xa9, 0x84, 0xf4, 0x69, 0xc4, 0x16, 0x3d, 0xdf, 0x5b,
-	0x49, 0xc1, 0xbc, 0xe4, 0xeb, 0x26, 0x81, 0x86, 0x39, 0x4d, 0x68, 0x9c, 0x2a, 0xe0, 0x84, 0x9f,
-	0x87, 0x84, 0x7d, 0xbd, 0x60, 0x32, 0x76, 0xfe, 0x58, 0x80, 0xb2, 0x59, 0x29, 0x42, 0x2e, 0x19,
-	0x1a, 0x42, 0x29, 0xbe, 0x12, 0xac, 0x65, 0x75, 0xac, 0xee, 0x41, 0x1f, 0xe3, 0xbb, 0xa7, 0xc0,
-	0x5a, 0xe5, 0xdd, 0x95, 0x60, 0x44, 0x71, 0x11, 0x86, 0xa6, 0x86, 0xb9, 0x54, 0x04, 0xee, 0x25,
-	0x8b, 0x64, 0x10, 0x72, 0xd9, 0x2a, 0x74, 0x8a, 0xdd, 0x0a, 0x69, 0xe8, 0xd2, 0x6b, 0x11, 0xcc,
-	0x4d, 0x01, 0x1d, 0xc2, 0x81, 0xc1, 0x1b, 0x6c, 0xab, 0xd8, 0xb1, 0xba, 0x15, 0x52, 0xd7, 0x59,
-	0x83, 0x43, 0x08, 0x4a, 0x9c, 0xae, 0x59, 0xab, 0xa4, 0x8a, 0x2a, 0x76, 0x1e, 0x40, 0x73, 0x14,
-	0xf2, 0xf3, 0x60, 0x39, 0xf3, 0x7c, 0xb6, 0xa6, 0xe9, 0x72, 0x1f, 0xe1, 0xfe, 0x76, 0xda, 0x6c,
-	0xf7, 0x0a, 0x4a, 0x89, 0x2f, 0x6a, 0xbb, 0x6a, 0xff, 0xe8, 0xbf, 0xdb, 0x69, 0x3f, 0xb1, 0xf1,
-	0x13, 0xcf, 0x04, 0xf3, 0x88, 0x62, 0x3a, 0xbf, 0x2d, 0xb0, 0x67, 0x2c, 0xd6, 0xea, 0xa6, 0x5d,
-	0xb2, 0xc0, 0x5a, 0x2e, 0x05, 0xf5, 0xbe, 0xb8, 0x9e, 0x2a, 0xa8, 0x06, 0x35, 0x52, 0x37, 0x59,
-	0x8d, 0x46, 0x04, 0x6a, 0xaa, 0x4d, 0x0a, 0x2a, 0xa8, 0x29, 0x7a, 0x79, 0x3c, 0x9e, 0x26, 0x05,
-	0xd3, 0xb4, 0xca, 0x37, 0x3f, 0xd0, 0x11, 0xa0, 0xdb, 0x5e, 0x1b, 0xff, 0xec, 0x9b, 0x56, 0x3b,
-	0x9f, 0xa1, 0x9a, 0x51, 0x42, 0x27, 0x50, 0x5e, 0x44, 0xc1, 0x25, 0x8b, 0x8c, 0x21, 0x83, 0xdc,
-	0xa3, 0x1c, 0x2b, 0x9a, 0x19, 0xc8, 0x88, 0x38, 0x2e, 0x34, 0x6e, 0x15, 0xd1, 0x23, 0xa8, 0x8f,
-	0x56, 0x01, 0xe3, 0xf1, 0x09, 0xfd, 0x76, 0x1a, 0x46, 0xb1, 0x6a, 0x55, 0x27, 0xdb, 0xc9, 0x0c,
-	0x2a, 0xe0, 0x0a, 0x55, 0xd8, 0x42, 0xe9, 0x64, 0x72, 0xc8, 0x19, 0xef, 0xf5, 0x7f, 0xfa, 0xf8,
-	0x09, 0xc0, 0xe6, 0x02, 0x51, 0x15, 0xf6, 0xdf, 0x4f, 0xdf, 0x4c, 0xdf, 0x7e, 0x98, 0xda, 0x7b,
-	0x08, 0xa0, 0x7c, 0x4c, 0x26, 0xf3, 0x31, 0xb1, 0x0b, 0x2a, 0x1e, 0xcf, 0x27, 0xa3, 0xb1, 0x5d,
-	0xec, 0xff, 0x2a, 0x02, 0x0c, 0xa9, 0x64, 0x9a, 0x87, 0x7e, 0xa4, 0x0a, 0xc9, 0x4b, 0x40, 0x83,
-	0xfc, 0x37, 0x9f, 0x79, 0x4f, 0xed, 0xe7, 0xbb, 0xd2, 0xf4, 0xf8, 0xce, 0x1e, 0xfa, 0x69, 0x41,
-	0x2d, 0x7b, 0xad, 0xe8, 0x45, 0x1e, 0xa9, 0x7f, 0x9c, 0x7d, 0xfb, 0xe5, 0xee, 0xc4, 0xeb, 0x29,
-	0xbe, 0x43, 0xe5, 0xda, 0x5b, 0xf4, 0x2c, 0x8f, 0xd0, 0xcd, 0x67, 0xd0, 0x1e, 0xec, 0xc8, 0x4a,
-	0x7b, 0x0f, 0xf7, 0x3f, 0xdd, 0x53, 0xc5, 0xb3, 0xb2, 0xfa, 0x3c, 0xfd, 0x1b, 0x00, 0x00, 0xff,
-	0xff, 0xdc, 0x0f, 0xf5, 0x51, 0x1c, 0x05, 0x00, 0x00,
+	// 530 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0xdf, 0x6f, 0x12, 0x41,
+	0x10, 0xee, 0x01, 0xd2, 0x30, 0x40, 0x73, 0x0c, 0x9a, 0x10, 0x12, 0x13, 0x72, 0xb1, 0x09, 0x31,
+	0xcd, 0x91, 0xa0, 0xa8, 0x8f, 0x95, 0x1f, 0x89, 0x44, 0x8b, 0xcd, 0x62, 0xd1, 0x18, 0x13, 0xb2,
+	0x3d, 0xb6, 0x70, 0x11, 0xf6, 0xd6, 0xdb, 0x6b, 0x63, 0x4d, 0x7c, 0xf2, 0xd9, 0xbf, 0xc8, 0x47,
+	0xff, 0x31, 0x73, 0xbb, 0x4b, 0x39, 0x5a, 0x8d, 0xc7, 0xd3, 0x0d, 0xf3, 0x7d, 0xf3, 0xcd, 0xcc,
+	0xc7, 0x0e, 0x3c, 0x14, 0xcb, 0xcb, 0xb9, 0xcf, 0x65, 0xeb, 0x9c, 0x4a, 0xd6, 0x12, 0x61, 0x10,
+	0x05, 0x2a, 0x74, 0x55, 0x88, 0xce, 0x82, 0xca, 0x85, 0xef, 0x05, 0xa1, 0x70, 0x79, 0xb0, 0xa2,
+	0x33, 0xd7, 0xd0, 0xdd, 0x0d, 0xa7, 0x7e, 0xb8, 0x96, 0x90, 0x0b, 0x1a, 0xb2, 0x59, 0x6b, 0xe1,
+	0x2d, 0xa5, 0x60, 0x5e, 0xfc, 0x9d, 0xc6, 0x81, 0xa6, 0x39, 0x55, 0xa8, 0x9c, 0x2a, 0xe2, 0x90,
+	0x5f, 0x04, 0x84, 0x7d, 0xb9, 0x64, 0x32, 0x72, 0x7e, 0x5b, 0x80, 0xc9, 0xac, 0x14, 0x01, 0x97,
+	0x0c, 0xbb, 0x90, 0x8b, 0xae, 0x05, 0xab, 0x59, 0x0d, 0xab, 0x79, 0xd0, 0x76, 0xdd, 0xff, 0x4f,
+	0xe1, 0x6a, 0x95, 0x77, 0xd7, 0x82, 0x11, 0x55, 0x8b, 0x2e, 0x54, 0x35, 0x6d, 0x4a, 0x85, 0x3f,
+	0xbd, 0x62, 0xa1, 0xf4, 0x03, 0x2e, 0x6b, 0x99, 0x46, 0xb6, 0x59, 0x20, 0x15, 0x0d, 0xbd, 0x14,
+	0xfe, 0xc4, 0x00, 0x78, 0x08, 0x07, 0x86, 0x6f, 0xb8, 0xb5, 0x6c, 0xc3, 0x6a, 0x16, 0x48, 0x59,
+	0x67, 0x0d, 0x0f, 0x11, 0x72, 0x9c, 0xae, 0x58, 0x2d, 0xa7, 0x40, 0x15, 0x3b, 0x0f, 0xa0, 0xda,
+	0x0b, 0xf8, 0x85, 0x3f, 0x1f, 0x7b, 0x0b, 0xb6, 0xa2, 0xeb, 0xe5, 0x3e, 0xc0, 0xfd, 0xed, 0xb4,
+	0xd9, 0xee, 0x18, 0x72, 0xb1, 0x2f, 0x6a, 0xbb, 0x62, 0xfb, 0xe8, 0x9f, 0xdb, 0x69, 0x3f, 0x5d,
+	0xe3, 0xa7, 0x3b, 0x16, 0xcc, 0x23, 0xaa, 0xd2, 0xf9, 0x65, 0x81, 0x3d, 0x66, 0x91, 0x56, 0x37,
+	0xed, 0xe2, 0x05, 0x56, 0x72, 0x2e, 0xa8, 0xf7, 0x79, 0xea, 0x29, 0x40, 0x35, 0x28, 0x91, 0xb2,
+	0xc9, 0x6a, 0x36, 0x12, 0x28, 0xa9, 0x36, 0x6b, 0x52, 0x46, 0x4d, 0xd1, 0x4a, 0xe3, 0xf1, 0x28,
+	0x06, 0x4c, 0xd3, 0x22, 0xdf, 0xfc, 0xc0, 0x23, 0xc0, 0xbb, 0x5e, 0x1b, 0xff, 0xec, 0xdb, 0x56,
+	0x3b, 0x9f, 0xa0, 0x98, 0x50, 0xc2, 0x13, 0xc8, 0xcf, 0x42, 0xff, 0x8a, 0x85, 0xc6, 0x90, 0x4e,
+	0xea, 0x51, 0xfa, 0xaa, 0xcc, 0x0c, 0x64, 0x44, 0x9c, 0x29, 0x54, 0xee, 0x80, 0xf8, 0x08, 0xca,
+	0xbd, 0xa5, 0xcf, 0x78, 0x74, 0x42, 0xbf, 0x9e, 0x06, 0x61, 0xa4, 0x5a, 0x95, 0xc9, 0x76, 0x32,
+	0xc1, 0xf2, 0xb9, 0x62, 0x65, 0xb6, 0x58, 0x3a, 0x19, 0x3f, 0xe4, 0x84, 0xf7, 0xfa, 0x3f, 0x7d,
+	0x7c, 0x0c, 0xb0, 0x79, 0x81, 0x58, 0x84, 0xfd, 0xb3, 0xd1, 0xeb, 0xd1, 0xdb, 0xf7, 0x23, 0x7b,
+	0x0f, 0x01, 0xf2, 0x7d, 0x32, 0x9c, 0x0c, 0x88, 0x9d, 0x51, 0xf1, 0x60, 0x32, 0xec, 0x0d, 0xec,
+	0x2c, 0x96, 0xa1, 0x30, 0xee, 0xbd, 0x1a, 0xf4, 0xcf, 0xde, 0x0c, 0x88, 0x9d, 0x6b, 0xff, 0xcc,
+	0x02, 0x74, 0xa9, 0x64, 0x5a, 0x06, 0xbf, 0x03, 0x6c, 0x0e, 0x03, 0x3b, 0xe9, 0x4f, 0x20, 0x71,
+	0x5e, 0xf5, 0x67, 0xbb, 0x96, 0xe9, 0x6d, 0x9c, 0x3d, 0xfc, 0x61, 0x41, 0x29, 0xf9, 0x78, 0xf1,
+	0x79, 0x1a, 0xa9, 0xbf, 0x5c, 0x41, 0xfd, 0xc5, 0xee, 0x85, 0x37, 0x53, 0x7c, 0x83, 0xc2, 0x8d,
+	0xd5, 0xf8, 0x34, 0x8d, 0xd0, 0xed, 0xab, 0xa8, 0x77, 0x76, 0xac, 0x5a, 0xf7, 0xee, 0xee, 0x7f,
+	0xbc, 0xa7, 0xc0, 0xf3, 0xbc, 0xfa, 0x3c, 0xf9, 0x33, 0x00, 0xbe, 0x83, 0xea, 0x78, 0x2b, 0x05,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package scheduler
+
+import (
+	"context"
+
+	"github.com/LK4D4/joincontext"
+	"github.com/open-wander/wander/helper/pluginutils/grpcutils"
+	"github.com/open-wander/wander/plugins/base"
+	"github.com/open-wander/wander/plugins/scheduler/proto"
+)
+
+// schedulerPluginClient implements the client side of a remote scheduler
+// plugin, using gRPC to communicate to the remote plugin.
+type schedulerPluginClient struct {
+	// basePluginClient is embedded to give access to the base plugin methods.
+	*base.BasePluginClient
+
+	client proto.SchedulerPluginClient
+
+	// doneCtx is closed when the plugin exits
+	doneCtx context.Context
+}
+
+// ScoreNode sends the candidate node to the plugin and returns its decision.
+// If the context is cancelled, the error will be propagated.
+func (s *schedulerPluginClient) ScoreNode(ctx context.Context, req *ScoreNodeRequest) (*ScoreNodeResponse, error) {
+	// Join the passed context and the shutdown context
+	joinedCtx, joinedCtxCancel := joincontext.Join(ctx, s.doneCtx)
+	defer joinedCtxCancel()
+
+	resp, err := s.client.ScoreNode(joinedCtx, convertStructScoreNodeRequest(req))
+	if err != nil {
+		return nil, grpcutils.HandleReqCtxGrpcErr(err, ctx, s.doneCtx)
+	}
+
+	return convertProtoScoreNodeResponse(resp), nil
+}
@@ -0,0 +1,35 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package scheduler
+
+import (
+	"context"
+
+	"github.com/open-wander/wander/plugins/base"
+)
+
+type ScoreNodeFn func(context.Context, *ScoreNodeRequest) (*ScoreNodeResponse, error)
+
+// MockSchedulerPlugin is used for testing.
+// Each function can be set as a closure to make assertions about how data
+// is passed through the base plugin layer.
+type MockSchedulerPlugin struct {
+	*base.MockPlugin
+	ScoreNodeF ScoreNodeFn
+}
+
+func (p *MockSchedulerPlugin) ScoreNode(ctx context.Context, req *ScoreNodeRequest) (*ScoreNodeResponse, error) {
+	return p.ScoreNodeF(ctx, req)
+}
+
+// StaticScorer returns a ScoreNodeFn that returns the passed response for the
+// matching node ID and leaves every other node untouched.
+func StaticScorer(responses map[string]*ScoreNodeResponse) ScoreNodeFn {
+	return func(_ context.Context, req *ScoreNodeRequest) (*ScoreNodeResponse, error) {
+		if resp, ok := responses[req.Node.ID]; ok {
+			return resp, nil
+		}
+		return &ScoreNodeResponse{}, nil
+	}
+}
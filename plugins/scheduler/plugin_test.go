@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/plugins/base"
+	"github.com/shoenig/test/must"
+)
+
+func dispenseMock(t *testing.T, mock *MockSchedulerPlugin) SchedulerPlugin {
+	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		base.PluginTypeBase:      &base.PluginBase{Impl: mock},
+		base.PluginTypeScheduler: &PluginScheduler{Impl: mock},
+	})
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	raw, err := client.Dispense(base.PluginTypeScheduler)
+	must.NoError(t, err)
+
+	impl, ok := raw.(SchedulerPlugin)
+	must.True(t, ok)
+	return impl
+}
+
+func TestSchedulerPlugin_PluginInfo(t *testing.T) {
+	ci.Parallel(t)
+
+	mock := &MockSchedulerPlugin{
+		MockPlugin: &base.MockPlugin{
+			PluginInfoF: func() (*base.PluginInfoResponse, error) {
+				return &base.PluginInfoResponse{
+					Type:              base.PluginTypeScheduler,
+					PluginApiVersions: []string{ApiVersion010},
+					PluginVersion:     "v0.1.0",
+					Name:              "mock_scheduler",
+				}, nil
+			},
+		},
+	}
+
+	impl := dispenseMock(t, mock)
+	resp, err := impl.PluginInfo()
+	must.NoError(t, err)
+	must.Eq(t, base.PluginTypeScheduler, resp.Type)
+	must.Eq(t, []string{ApiVersion010}, resp.PluginApiVersions)
+	must.Eq(t, "mock_scheduler", resp.Name)
+}
+
+func TestSchedulerPlugin_ScoreNode(t *testing.T) {
+	ci.Parallel(t)
+
+	var received *ScoreNodeRequest
+	responses := map[string]*ScoreNodeResponse{
+		"node1": {Score: 0.5},
+		"node2": {Filter: true, Reason: "maintenance window"},
+	}
+	static := StaticScorer(responses)
+	mock := &MockSchedulerPlugin{
+		ScoreNodeF: func(ctx context.Context, req *ScoreNodeRequest) (*ScoreNodeResponse, error) {
+			received = req
+			return static(ctx, req)
+		},
+	}
+
+	impl := dispenseMock(t, mock)
+
+	req := &ScoreNodeRequest{
+		EvalID:        "eval",
+		Namespace:     "default",
+		JobID:         "example",
+		JobType:       "service",
+		Priority:      50,
+		TaskGroup:     "web",
+		JobMeta:       map[string]string{"team": "a"},
+		TaskGroupMeta: map[string]string{"tier": "front"},
+		Node: &CandidateNode{
+			ID:         "node1",
+			Name:       "one",
+			Datacenter: "dc1",
+			NodeClass:  "large",
+			NodePool:   "default",
+			Attributes: map[string]string{"kernel.name": "linux"},
+			Meta:       map[string]string{"rack": "r1"},
+			Scores:     []float64{0.7, -0.2},
+		},
+	}
+
+	resp, err := impl.ScoreNode(context.Background(), req)
+	must.NoError(t, err)
+	must.Eq(t, req, received)
+	must.Eq(t, responses["node1"], resp)
+
+	req.Node = &CandidateNode{ID: "node2"}
+	resp, err = impl.ScoreNode(context.Background(), req)
+	must.NoError(t, err)
+	must.Eq(t, responses["node2"], resp)
+
+	req.Node = &CandidateNode{ID: "node3"}
+	resp, err = impl.ScoreNode(context.Background(), req)
+	must.NoError(t, err)
+	must.Eq(t, &ScoreNodeResponse{}, resp)
+}
+
+func TestSchedulerPlugin_ScoreNode_Error(t *testing.T) {
+	ci.Parallel(t)
+
+	mock := &MockSchedulerPlugin{
+		ScoreNodeF: func(context.Context, *ScoreNodeRequest) (*ScoreNodeResponse, error) {
+			return nil, errors.New("broken")
+		},
+	}
+
+	impl := dispenseMock(t, mock)
+	_, err := impl.ScoreNode(context.Background(), &ScoreNodeRequest{})
+	must.ErrorContains(t, err, "broken")
+}
+
+func TestSchedulerPlugin_ScoreNode_Timeout(t *testing.T) {
+	ci.Parallel(t)
+
+	mock := &MockSchedulerPlugin{
+		ScoreNodeF: func(ctx context.Context, _ *ScoreNodeRequest) (*ScoreNodeResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	impl := dispenseMock(t, mock)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := impl.ScoreNode(ctx, &ScoreNodeRequest{})
+	must.ErrorContains(t, err, "deadline exceeded")
+}
+
+func TestScoreNodeResponse_ClampedScore(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Eq(t, MaxScore, (&ScoreNodeResponse{Score: 3}).ClampedScore())
+	must.Eq(t, MinScore, (&ScoreNodeResponse{Score: -2}).ClampedScore())
+	must.Eq(t, 0.25, (&ScoreNodeResponse{Score: 0.25}).ClampedScore())
+}
@@ -0,0 +1,442 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: plugins/scheduler/proto/scheduler.proto
+
+package proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// ScoreNodeRequest is used to score a candidate node for the placement of a
+// task group.
+type ScoreNodeRequest struct {
+	// eval_id is the ID of the evaluation being processed.
+	EvalId string `protobuf:"bytes,1,opt,name=eval_id,json=evalId,proto3" json:"eval_id,omitempty"`
+	// namespace is the namespace of the job being scheduled.
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// job_id is the ID of the job being scheduled.
+	JobId string `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// job_type is the type of the job being scheduled.
+	JobType string `protobuf:"bytes,4,opt,name=job_type,json=jobType,proto3" json:"job_type,omitempty"`
+	// priority is the priority of the job being scheduled.
+	Priority int32 `protobuf:"varint,5,opt,name=priority,proto3" json:"priority,omitempty"`
+	// task_group is the name of the task group being placed.
+	TaskGroup string `protobuf:"bytes,6,opt,name=task_group,json=taskGroup,proto3" json:"task_group,omitempty"`
+	// job_meta is the metadata of the job.
+	JobMeta map[string]string `protobuf:"bytes,7,rep,name=job_meta,json=jobMeta,proto3" json:"job_meta,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// task_group_meta is the metadata of the task group.
+	TaskGroupMeta map[string]string `protobuf:"bytes,8,rep,name=task_group_meta,json=taskGroupMeta,proto3" json:"task_group_meta,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// node is the candidate node for the placement.
+	Node                 *CandidateNode `protobuf:"bytes,9,opt,name=node,proto3" json:"node,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ScoreNodeRequest) Reset()         { *m = ScoreNodeRequest{} }
+func (m *ScoreNodeRequest) String() string { return proto.CompactTextString(m) }
+func (*ScoreNodeRequest) ProtoMessage()    {}
+func (*ScoreNodeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4d5204de0936f6b4, []int{0}
+}
+
+func (m *ScoreNodeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScoreNodeRequest.Unmarshal(m, b)
+}
+func (m *ScoreNodeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScoreNodeRequest.Marshal(b, m, deterministic)
+}
+func (m *ScoreNodeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScoreNodeRequest.Merge(m, src)
+}
+func (m *ScoreNodeRequest) XXX_Size() int {
+	return xxx_messageInfo_ScoreNodeRequest.Size(m)
+}
+func (m *ScoreNodeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScoreNodeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScoreNodeRequest proto.InternalMessageInfo
+
+func (m *ScoreNodeRequest) GetEvalId() string {
+	if m != nil {
+		return m.EvalId
+	}
+	return ""
+}
+
+func (m *ScoreNodeRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ScoreNodeRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *ScoreNodeRequest) GetJobType() string {
+	if m != nil {
+		return m.JobType
+	}
+	return ""
+}
+
+func (m *ScoreNodeRequest) GetPriority() int32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *ScoreNodeRequest) GetTaskGroup() string {
+	if m != nil {
+		return m.TaskGroup
+	}
+	return ""
+}
+
+func (m *ScoreNodeRequest) GetJobMeta() map[string]string {
+	if m != nil {
+		return m.JobMeta
+	}
+	return nil
+}
+
+func (m *ScoreNodeRequest) GetTaskGroupMeta() map[string]string {
+	if m != nil {
+		return m.TaskGroupMeta
+	}
+	return nil
+}
+
+func (m *ScoreNodeRequest) GetNode() *CandidateNode {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+// CandidateNode is a node that passed feasibility checking and ranking.
+type CandidateNode struct {
+	// id is the ID of the node.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// name is the name of the node.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// datacenter is the datacenter of the node.
+	Datacenter string `protobuf:"bytes,3,opt,name=datacenter,proto3" json:"datacenter,omitempty"`
+	// node_class is the class of the node.
+	NodeClass string `protobuf:"bytes,4,opt,name=node_class,json=nodeClass,proto3" json:"node_class,omitempty"`
+	// node_pool is the node pool of the node.
+	NodePool string `protobuf:"bytes,5,opt,name=node_pool,json=nodePool,proto3" json:"node_pool,omitempty"`
+	// attributes are the fingerprinted attributes of the node.
+	Attributes map[string]string `protobuf:"bytes,6,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// meta is the user defined metadata of the node.
+	Meta map[string]string `protobuf:"bytes,7,rep,name=meta,proto3" json:"meta,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// scores are the scores computed by the scheduler so far. Each score is
+	// between -1 and 1.
+	Scores               []float64 `protobuf:"fixed64,8,rep,packed,name=scores,proto3" json:"scores,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CandidateNode) Reset()         { *m = CandidateNode{} }
+func (m *CandidateNode) String() string { return proto.CompactTextString(m) }
+func (*CandidateNode) ProtoMessage()    {}
+func (*CandidateNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4d5204de0936f6b4, []int{1}
+}
+
+func (m *CandidateNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CandidateNode.Unmarshal(m, b)
+}
+func (m *CandidateNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CandidateNode.Marshal(b, m, deterministic)
+}
+func (m *CandidateNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CandidateNode.Merge(m, src)
+}
+func (m *CandidateNode) XXX_Size() int {
+	return xxx_messageInfo_CandidateNode.Size(m)
+}
+func (m *CandidateNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_CandidateNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CandidateNode proto.InternalMessageInfo
+
+func (m *CandidateNode) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *CandidateNode) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CandidateNode) GetDatacenter() string {
+	if m != nil {
+		return m.Datacenter
+	}
+	return ""
+}
+
+func (m *CandidateNode) GetNodeClass() string {
+	if m != nil {
+		return m.NodeClass
+	}
+	return ""
+}
+
+func (m *CandidateNode) GetNodePool() string {
+	if m != nil {
+		return m.NodePool
+	}
+	return ""
+}
+
+func (m *CandidateNode) GetAttributes() map[string]string {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+func (m *CandidateNode) GetMeta() map[string]string {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *CandidateNode) GetScores() []float64 {
+	if m != nil {
+		return m.Scores
+	}
+	return nil
+}
+
+// ScoreNodeResponse returns the decision made by the plugin for a node.
+type ScoreNodeResponse struct {
+	// filter removes the node from the set of candidates when true.
+	Filter bool `protobuf:"varint,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// reason is a human readable reason for filtering the node.
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// score is an adjustment between -1 and 1 added to the node's scores.
+	Score                float64  `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScoreNodeResponse) Reset()         { *m = ScoreNodeResponse{} }
+func (m *ScoreNodeResponse) String() string { return proto.CompactTextString(m) }
+func (*ScoreNodeResponse) ProtoMessage()    {}
+func (*ScoreNodeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4d5204de0936f6b4, []int{2}
+}
+
+func (m *ScoreNodeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScoreNodeResponse.Unmarshal(m, b)
+}
+func (m *ScoreNodeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScoreNodeResponse.Marshal(b, m, deterministic)
+}
+func (m *ScoreNodeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScoreNodeResponse.Merge(m, src)
+}
+func (m *ScoreNodeResponse) XXX_Size() int {
+	return xxx_messageInfo_ScoreNodeResponse.Size(m)
+}
+func (m *ScoreNodeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScoreNodeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScoreNodeResponse proto.InternalMessageInfo
+
+func (m *ScoreNodeResponse) GetFilter() bool {
+	if m != nil {
+		return m.Filter
+	}
+	return false
+}
+
+func (m *ScoreNodeResponse) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *ScoreNodeResponse) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ScoreNodeRequest)(nil), "hashicorp.nomad.plugins.scheduler.ScoreNodeRequest")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.scheduler.ScoreNodeRequest.JobMetaEntry")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.scheduler.ScoreNodeRequest.TaskGroupMetaEntry")
+	proto.RegisterType((*CandidateNode)(nil), "hashicorp.nomad.plugins.scheduler.CandidateNode")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.scheduler.CandidateNode.AttributesEntry")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.scheduler.CandidateNode.MetaEntry")
+	proto.RegisterType((*ScoreNodeResponse)(nil), "hashicorp.nomad.plugins.scheduler.ScoreNodeResponse")
+}
+
+func init() {
+	proto.RegisterFile("plugins/scheduler/proto/scheduler.proto", fileDescriptor_4d5204de0936f6b4)
+}
+
+var fileDescriptor_4d5204de0936f6b4 = []byte{
+	// 557 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0x4b, 0x6f, 0xd3, 0x4c,
+	0x14, 0xfd, 0x9c, 0x87, 0x13, 0xdf, 0x7e, 0x25, 0xe5, 0x8a, 0x87, 0x09, 0x0f, 0x85, 0x6c, 0xc8,
+	0xca, 0x45, 0x29, 0x12, 0x28, 0x12, 0x52, 0xa1, 0x3c, 0x54, 0x24, 0xaa, 0xca, 0xed, 0x06, 0x58,
+	0x84, 0xb1, 0x67, 0x68, 0x9c, 0x3a, 0x9e, 0x61, 0x66, 0x5c, 0x91, 0xdf, 0xc0, 0x0f, 0x63, 0xcd,
+	0x3f, 0x42, 0x33, 0x76, 0x12, 0xb7, 0x2c, 0x68, 0xb2, 0xca, 0x9c, 0x33, 0xb9, 0xe7, 0xdc, 0x3b,
+	0xf7, 0x24, 0xf0, 0x44, 0xa4, 0xf9, 0x59, 0x92, 0xa9, 0x5d, 0x15, 0x4f, 0x18, 0xcd, 0x53, 0x26,
+	0x77, 0x85, 0xe4, 0x9a, 0xaf, 0x70, 0x60, 0x31, 0x3e, 0x9e, 0x10, 0x35, 0x49, 0x62, 0x2e, 0x45,
+	0x90, 0xf1, 0x19, 0xa1, 0x41, 0x59, 0x18, 0x2c, 0xbf, 0xd8, 0xff, 0xd5, 0x80, 0x9d, 0x93, 0x98,
+	0x4b, 0x76, 0xc4, 0x29, 0x0b, 0xd9, 0xf7, 0x9c, 0x29, 0x8d, 0x77, 0xa1, 0xc5, 0x2e, 0x48, 0x3a,
+	0x4e, 0xa8, 0xef, 0xf4, 0x9c, 0x81, 0x17, 0xba, 0x06, 0x1e, 0x52, 0x7c, 0x00, 0x5e, 0x46, 0x66,
+	0x4c, 0x09, 0x12, 0x33, 0xbf, 0x66, 0xaf, 0x56, 0x04, 0xde, 0x06, 0x77, 0xca, 0x23, 0x53, 0x55,
+	0xb7, 0x57, 0xcd, 0x29, 0x8f, 0x0e, 0x29, 0xde, 0x83, 0xb6, 0xa1, 0xf5, 0x5c, 0x30, 0xbf, 0x61,
+	0x2f, 0x5a, 0x53, 0x1e, 0x9d, 0xce, 0x05, 0xc3, 0x2e, 0xb4, 0x85, 0x4c, 0xb8, 0x4c, 0xf4, 0xdc,
+	0x6f, 0xf6, 0x9c, 0x41, 0x33, 0x5c, 0x62, 0x7c, 0x08, 0xa0, 0x89, 0x3a, 0x1f, 0x9f, 0x49, 0x9e,
+	0x0b, 0xdf, 0x2d, 0xcc, 0x0c, 0xf3, 0xde, 0x10, 0xf8, 0xa5, 0x50, 0x9d, 0x31, 0x4d, 0xfc, 0x56,
+	0xaf, 0x3e, 0xd8, 0x1a, 0xee, 0x07, 0xff, 0x1c, 0x37, 0xb8, 0x3a, 0x6a, 0xf0, 0x81, 0x47, 0x1f,
+	0x99, 0x26, 0x6f, 0x33, 0x2d, 0xe7, 0xb6, 0x2f, 0x83, 0x30, 0x83, 0xce, 0xca, 0xbb, 0xf0, 0x68,
+	0x5b, 0x8f, 0x77, 0x9b, 0x78, 0x9c, 0x2e, 0x9a, 0x5e, 0x39, 0x6d, 0xeb, 0x2a, 0x87, 0x6f, 0xa0,
+	0x91, 0x71, 0xca, 0x7c, 0xaf, 0xe7, 0x0c, 0xb6, 0x86, 0x4f, 0xaf, 0x61, 0x72, 0x40, 0x32, 0x9a,
+	0x50, 0xa2, 0x0b, 0x23, 0x5b, 0xdd, 0x1d, 0xc1, 0xff, 0xd5, 0x71, 0x70, 0x07, 0xea, 0xe7, 0x6c,
+	0x5e, 0xae, 0xd0, 0x1c, 0xf1, 0x16, 0x34, 0x2f, 0x48, 0x9a, 0x2f, 0x76, 0x57, 0x80, 0x51, 0xed,
+	0x85, 0xd3, 0xdd, 0x07, 0xfc, 0xbb, 0xcd, 0x75, 0x14, 0xfa, 0xbf, 0xeb, 0xb0, 0x7d, 0xa9, 0x2b,
+	0xbc, 0x01, 0xb5, 0x65, 0x82, 0x6a, 0x09, 0x45, 0x84, 0x86, 0x09, 0x4b, 0x59, 0x6a, 0xcf, 0xf8,
+	0x08, 0x80, 0x12, 0x4d, 0x62, 0x96, 0x69, 0x26, 0xcb, 0xdc, 0x54, 0x18, 0x93, 0x02, 0x33, 0xdb,
+	0x38, 0x4e, 0x89, 0x52, 0x65, 0x7c, 0x3c, 0xc3, 0x1c, 0x18, 0x02, 0xef, 0x83, 0x05, 0x63, 0xc1,
+	0x79, 0x6a, 0x13, 0xe4, 0x85, 0x6d, 0x43, 0x1c, 0x73, 0x9e, 0xe2, 0x57, 0x00, 0xa2, 0xb5, 0x4c,
+	0xa2, 0x5c, 0x33, 0xe5, 0xbb, 0xd7, 0x0e, 0xc9, 0xa5, 0x29, 0x82, 0x57, 0x4b, 0x89, 0x62, 0x75,
+	0x15, 0x4d, 0x3c, 0x82, 0x46, 0x25, 0x80, 0xa3, 0xb5, 0xb5, 0x57, 0x81, 0xb0, 0x3a, 0x78, 0x07,
+	0x5c, 0x65, 0xd2, 0xa3, 0x6c, 0xdc, 0x9c, 0xb0, 0x44, 0xdd, 0x97, 0xd0, 0xb9, 0xd2, 0xc6, 0x5a,
+	0xcb, 0x7d, 0x0e, 0xde, 0x66, 0x3b, 0xfd, 0x04, 0x37, 0x2b, 0x69, 0x56, 0x82, 0x67, 0x8a, 0x99,
+	0x26, 0xbf, 0x25, 0xa9, 0x59, 0x97, 0xd1, 0x68, 0x87, 0x25, 0x32, 0xbc, 0x64, 0x44, 0xf1, 0xac,
+	0xd4, 0x29, 0x91, 0x91, 0xb7, 0x63, 0xd8, 0xed, 0x3a, 0x61, 0x01, 0x86, 0x3f, 0x1d, 0xe8, 0x9c,
+	0x2c, 0x9e, 0xe5, 0xd8, 0xbe, 0x13, 0xfe, 0x00, 0x6f, 0x69, 0x87, 0x7b, 0x1b, 0xfc, 0xd4, 0xba,
+	0xcf, 0xd6, 0x2b, 0x2a, 0x26, 0xea, 0xff, 0xf7, 0xba, 0xf5, 0xb9, 0x69, 0xff, 0x32, 0x23, 0xd7,
+	0x7e, 0xec, 0xfd, 0x19, 0x00, 0x17, 0xeb, 0x72, 0xb6, 0x64, 0x05, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// SchedulerPluginClient is the client API for SchedulerPlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SchedulerPluginClient interface {
+	// ScoreNode is called by the scheduler for each candidate node that
+	// passed feasibility checking and ranking. The plugin can filter out the
+	// node or adjust its score before the scores are normalized.
+	ScoreNode(ctx context.Context, in *ScoreNodeRequest, opts ...grpc.CallOption) (*ScoreNodeResponse, error)
+}
+
+type schedulerPluginClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSchedulerPluginClient(cc grpc.ClientConnInterface) SchedulerPluginClient {
+	return &schedulerPluginClient{cc}
+}
+
+func (c *schedulerPluginClient) ScoreNode(ctx context.Context, in *ScoreNodeRequest, opts ...grpc.CallOption) (*ScoreNodeResponse, error) {
+	out := new(ScoreNodeResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.scheduler.SchedulerPlugin/ScoreNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SchedulerPluginServer is the server API for SchedulerPlugin service.
+type SchedulerPluginServer interface {
+	// ScoreNode is called by the scheduler for each candidate node that
+	// passed feasibility checking and ranking. The plugin can filter out the
+	// node or adjust its score before the scores are normalized.
+	ScoreNode(context.Context, *ScoreNodeRequest) (*ScoreNodeResponse, error)
+}
+
+// UnimplementedSchedulerPluginServer can be embedded to have forward compatible implementations.
+type UnimplementedSchedulerPluginServer struct {
+}
+
+func (*UnimplementedSchedulerPluginServer) ScoreNode(ctx context.Context, req *ScoreNodeRequest) (*ScoreNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScoreNode not implemented")
+}
+
+func RegisterSchedulerPluginServer(s *grpc.Server, srv SchedulerPluginServer) {
+	s.RegisterService(&_SchedulerPlugin_serviceDesc, srv)
+}
+
+func _SchedulerPlugin_ScoreNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScoreNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerPluginServer).ScoreNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.scheduler.SchedulerPlugin/ScoreNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerPluginServer).ScoreNode(ctx, req.(*ScoreNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _SchedulerPlugin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "hashicorp.nomad.plugins.scheduler.SchedulerPlugin",
+	HandlerType: (*SchedulerPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ScoreNode",
+			Handler:    _SchedulerPlugin_ScoreNode_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugins/scheduler/proto/scheduler.proto",
+}
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package scheduler
+
+import (
+	"context"
+
+	"github.com/open-wander/wander/plugins/base"
+)
+
+const (
+	// MinScore and MaxScore bound the score adjustment a plugin can apply to
+	// a node.
+	MinScore = -1.0
+	MaxScore = 1.0
+)
+
+// SchedulerPlugin is the interface for a plugin that can filter and adjust the
+// scores of the nodes considered by the scheduler for a placement.
+type SchedulerPlugin interface {
+	base.BasePlugin
+
+	// ScoreNode is called for each candidate node of a placement and returns
+	// whether the node should be filtered or have its score adjusted.
+	ScoreNode(ctx context.Context, req *ScoreNodeRequest) (*ScoreNodeResponse, error)
+}
+
+// ScoreNodeRequest describes the placement being made and a candidate node
+// for it.
+type ScoreNodeRequest struct {
+	EvalID        string
+	Namespace     string
+	JobID         string
+	JobType       string
+	Priority      int
+	TaskGroup     string
+	JobMeta       map[string]string
+	TaskGroupMeta map[string]string
+
+	// Node is the candidate node that passed feasibility checking and
+	// ranking.
+	Node *CandidateNode
+}
+
+// CandidateNode is a node that is being considered for a placement.
+type CandidateNode struct {
+	ID         string
+	Name       string
+	Datacenter string
+	NodeClass  string
+	NodePool   string
+	Attributes map[string]string
+	Meta       map[string]string
+
+	// Scores are the scores computed by the scheduler so far. Each score is
+	// between -1 and 1.
+	Scores []float64
+}
+
+// ScoreNodeResponse is the decision made by the plugin for a candidate node.
+type ScoreNodeResponse struct {
+	// Filter removes the node from the set of candidates.
+	Filter bool
+
+	// Reason is a human readable reason for filtering the node.
+	Reason string
+
+	// Score is an adjustment between MinScore and MaxScore added to the
+	// scores of the node.
+	Score float64
+}
+
+// ClampedScore returns the score adjustment bounded by MinScore and MaxScore.
+func (r *ScoreNodeResponse) ClampedScore() float64 {
+	switch {
+	case r.Score < MinScore:
+		return MinScore
+	case r.Score > MaxScore:
+		return MaxScore
+	default:
+		return r.Score
+	}
+}
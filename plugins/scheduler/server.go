@@ -0,0 +1,26 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package scheduler
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/open-wander/wander/plugins/scheduler/proto"
+)
+
+// schedulerPluginServer wraps a scheduler plugin and exposes it via gRPC.
+type schedulerPluginServer struct {
+	broker *plugin.GRPCBroker
+	impl   SchedulerPlugin
+}
+
+func (s *schedulerPluginServer) ScoreNode(ctx context.Context, req *proto.ScoreNodeRequest) (*proto.ScoreNodeResponse, error) {
+	resp, err := s.impl.ScoreNode(ctx, convertProtoScoreNodeRequest(req))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertStructScoreNodeResponse(resp), nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package scheduler
+
+import (
+	"github.com/open-wander/wander/plugins/scheduler/proto"
+)
+
+func convertStructScoreNodeRequest(in *ScoreNodeRequest) *proto.ScoreNodeRequest {
+	if in == nil {
+		return nil
+	}
+
+	return &proto.ScoreNodeRequest{
+		EvalId:        in.EvalID,
+		Namespace:     in.Namespace,
+		JobId:         in.JobID,
+		JobType:       in.JobType,
+		Priority:      int32(in.Priority),
+		TaskGroup:     in.TaskGroup,
+		JobMeta:       in.JobMeta,
+		TaskGroupMeta: in.TaskGroupMeta,
+		Node:          convertStructCandidateNode(in.Node),
+	}
+}
+
+func convertProtoScoreNodeRequest(in *proto.ScoreNodeRequest) *ScoreNodeRequest {
+	if in == nil {
+		return nil
+	}
+
+	return &ScoreNodeRequest{
+		EvalID:        in.EvalId,
+		Namespace:     in.Namespace,
+		JobID:         in.JobId,
+		JobType:       in.JobType,
+		Priority:      int(in.Priority),
+		TaskGroup:     in.TaskGroup,
+		JobMeta:       in.JobMeta,
+		TaskGroupMeta: in.TaskGroupMeta,
+		Node:          convertProtoCandidateNode(in.Node),
+	}
+}
+
+func convertStructCandidateNode(in *CandidateNode) *proto.CandidateNode {
+	if in == nil {
+		return nil
+	}
+
+	return &proto.CandidateNode{
+		Id:         in.ID,
+		Name:       in.Name,
+		Datacenter: in.Datacenter,
+		NodeClass:  in.NodeClass,
+		NodePool:   in.NodePool,
+		Attributes: in.Attributes,
+		Meta:       in.Meta,
+		Scores:     in.Scores,
+	}
+}
+
+func convertProtoCandidateNode(in *proto.CandidateNode) *CandidateNode {
+	if in == nil {
+		return nil
+	}
+
+	return &CandidateNode{
+		ID:         in.Id,
+		Name:       in.Name,
+		Datacenter: in.Datacenter,
+		NodeClass:  in.NodeClass,
+		NodePool:   in.NodePool,
+		Attributes: in.Attributes,
+		Meta:       in.Meta,
+		Scores:     in.Scores,
+	}
+}
+
+func convertStructScoreNodeResponse(in *ScoreNodeResponse) *proto.ScoreNodeResponse {
+	if in == nil {
+		return &proto.ScoreNodeResponse{}
+	}
+
+	return &proto.ScoreNodeResponse{
+		Filter: in.Filter,
+		Reason: in.Reason,
+		Score:  in.Score,
+	}
+}
+
+func convertProtoScoreNodeResponse(in *proto.ScoreNodeResponse) *ScoreNodeResponse {
+	if in == nil {
+		return &ScoreNodeResponse{}
+	}
+
+	return &ScoreNodeResponse{
+		Filter: in.Filter,
+		Reason: in.Reason,
+		Score:  in.Score,
+	}
+}
@@ -191,6 +191,11 @@ FieldsLoop:
 			case "Service", "Constraint":
 				continue
 			case "LogConfig":
+				// force a destructive update if the log sinks changed
+				if len(oDiff.Objects) != 0 {
+					destructive = true
+					break ObjectsLoop
+				}
 				for _, fDiff := range oDiff.Fields {
 					switch fDiff.Name {
 					// force a destructive update if logger was enabled or disabled
@@ -357,6 +357,33 @@ func TestAnnotateTask(t *testing.T) {
 			Parent:  &structs.TaskGroupDiff{Type: structs.DiffTypeEdited},
 			Desired: AnnotationForcesDestructiveUpdate,
 		},
+		{
+			Diff: &structs.TaskDiff{
+				Type: structs.DiffTypeEdited,
+				Objects: []*structs.ObjectDiff{
+					{
+						Type: structs.DiffTypeEdited,
+						Name: "LogConfig",
+						Objects: []*structs.ObjectDiff{
+							{
+								Type: structs.DiffTypeAdded,
+								Name: "Sink",
+								Fields: []*structs.FieldDiff{
+									{
+										Type: structs.DiffTypeAdded,
+										Name: "Type",
+										Old:  "",
+										New:  "journald",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Parent:  &structs.TaskGroupDiff{Type: structs.DiffTypeEdited},
+			Desired: AnnotationForcesDestructiveUpdate,
+		},
 		{
 			Diff: &structs.TaskDiff{
 				Type: structs.DiffTypeEdited,
@@ -0,0 +1,398 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/open-wander/wander/helper/uuid"
+	"github.com/open-wander/wander/nomad/structs"
+	"golang.org/x/exp/maps"
+)
+
+// ExplainJob runs every ready node in the job's node pool and datacenters
+// through the placement pipeline used by the schedulers and records the
+// result of each individual check. Unlike a regular evaluation, which stops at
+// the first failed check and only keeps aggregated metrics, every check is
+// evaluated against every node so operators can see exactly why a task group
+// can't be placed.
+//
+// If taskGroup is not empty only that task group is explained.
+func ExplainJob(logger log.Logger, state State, job *structs.Job, taskGroup string) (map[string]*structs.TaskGroupExplanation, error) {
+	var groups []*structs.TaskGroup
+	if taskGroup != "" {
+		tg := job.LookupTaskGroup(taskGroup)
+		if tg == nil {
+			return nil, fmt.Errorf("task group %q not found in job %q", taskGroup, job.ID)
+		}
+		groups = append(groups, tg)
+	} else {
+		groups = job.TaskGroups
+	}
+
+	pool, err := state.NodePoolByName(nil, job.NodePool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job node pool %q: %v", job.NodePool, err)
+	}
+
+	_, schedConfig, err := state.SchedulerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduler configuration: %v", err)
+	}
+
+	nodes, _, byDC, err := readyNodesInDCsAndPool(state, job.Datacenters, job.NodePool)
+	if err != nil {
+		return nil, err
+	}
+
+	explainer := &jobExplainer{
+		logger:      logger.Named("explain"),
+		state:       state,
+		job:         job,
+		schedConfig: schedConfig.WithNodePool(pool),
+	}
+
+	out := make(map[string]*structs.TaskGroupExplanation, len(groups))
+	for _, tg := range groups {
+		tgExplain := &structs.TaskGroupExplanation{
+			TaskGroup:      tg.Name,
+			NodesEvaluated: len(nodes),
+			NodesAvailable: maps.Clone(byDC),
+			Requested:      explainRequested(tg),
+			NodesFailed:    make(map[string]int),
+			Nodes:          make([]*structs.NodeExplanation, 0, len(nodes)),
+		}
+
+		for _, node := range nodes {
+			nodeExplain, err := explainer.explainNode(tg, node)
+			if err != nil {
+				return nil, err
+			}
+			if nodeExplain.Feasible {
+				tgExplain.NodesFeasible++
+			}
+			for _, check := range nodeExplain.Checks {
+				if !check.Passed {
+					tgExplain.NodesFailed[check.String()]++
+				}
+			}
+			tgExplain.Nodes = append(tgExplain.Nodes, nodeExplain)
+		}
+
+		sortNodeExplanations(tgExplain.Requested, tgExplain.Nodes)
+		for i := 0; i < len(tgExplain.Nodes) && i < structs.MaxExplainClosestNodes; i++ {
+			tgExplain.Nodes[i].Closest = true
+		}
+
+		out[tg.Name] = tgExplain
+	}
+
+	return out, nil
+}
+
+// jobExplainer holds the state shared by the explanation of every node.
+type jobExplainer struct {
+	logger      log.Logger
+	state       State
+	job         *structs.Job
+	schedConfig *structs.SchedulerConfiguration
+}
+
+// newContext returns a fresh EvalContext so that results, metrics and
+// computed class eligibility are never shared between nodes.
+func (e *jobExplainer) newContext() *EvalContext {
+	plan := &structs.Plan{
+		EvalID:          uuid.Generate(),
+		Job:             e.job,
+		NodeUpdate:      make(map[string][]*structs.Allocation),
+		NodeAllocation:  make(map[string][]*structs.Allocation),
+		NodePreemptions: make(map[string][]*structs.Allocation),
+	}
+	return NewEvalContext(nil, e.state, plan, e.logger)
+}
+
+// explainNode evaluates each feasibility check individually against the node
+// and, if they all pass, runs the ranking iterators to determine if the
+// node has enough resources left for the task group.
+func (e *jobExplainer) explainNode(tg *structs.TaskGroup, node *structs.Node) (*structs.NodeExplanation, error) {
+	out := &structs.NodeExplanation{
+		NodeID:     node.ID,
+		NodeName:   node.Name,
+		NodePool:   node.NodePool,
+		NodeClass:  node.NodeClass,
+		Datacenter: node.Datacenter,
+	}
+
+	ctx := e.newContext()
+	available, err := explainAvailable(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	out.Available = available
+
+	tgConstr := taskGroupConstraints(tg)
+	constraints := make([]*structs.Constraint, 0, len(e.job.Constraints)+len(tgConstr.constraints))
+	constraints = append(constraints, e.job.Constraints...)
+	constraints = append(constraints, tgConstr.constraints...)
+
+	// The distinct constraints are not handled by the ConstraintChecker, so
+	// they are reported by their own iterators below.
+	for _, c := range constraints {
+		if c.Operand == structs.ConstraintDistinctHosts || c.Operand == structs.ConstraintDistinctProperty {
+			continue
+		}
+		checker := NewConstraintChecker(ctx, []*structs.Constraint{c})
+		out.Checks = append(out.Checks, explainChecker(ctx, structs.ExplainCheckConstraint, c.String(), checker, node))
+	}
+
+	drivers := NewDriverChecker(ctx, tgConstr.drivers)
+	out.Checks = append(out.Checks, explainChecker(ctx, structs.ExplainCheckDrivers,
+		strings.Join(sortedKeys(tgConstr.drivers), ", "), drivers, node))
+
+	if explainHasDevices(tg) {
+		devices := NewDeviceChecker(ctx)
+		devices.SetTaskGroup(tg)
+		out.Checks = append(out.Checks, explainChecker(ctx, structs.ExplainCheckDevices, "", devices, node))
+	}
+
+	if len(tg.Networks) > 0 {
+		network := NewNetworkChecker(ctx)
+		network.SetNetwork(tg.Networks[0])
+		out.Checks = append(out.Checks, explainChecker(ctx, structs.ExplainCheckNetwork, tg.Networks[0].Mode, network, node))
+	}
+
+	if len(tg.Volumes) > 0 {
+		allocName := structs.AllocName(e.job.ID, tg.Name, 0)
+
+		hostVolumes := NewHostVolumeChecker(ctx)
+		hostVolumes.SetVolumes(allocName, tg.Volumes)
+		out.Checks = append(out.Checks, explainChecker(ctx, structs.ExplainCheckHostVolumes, "", hostVolumes, node))
+
+		csiVolumes := NewCSIVolumeChecker(ctx)
+		csiVolumes.SetNamespace(e.job.Namespace)
+		csiVolumes.SetJobID(e.job.ID)
+		csiVolumes.SetVolumes(allocName, tg.Volumes)
+		out.Checks = append(out.Checks, explainChecker(ctx, structs.ExplainCheckCSIVolumes, "", csiVolumes, node))
+	}
+
+	if explainHasOperand(e.job, tg, structs.ConstraintDistinctHosts) {
+		hosts := NewDistinctHostsIterator(ctx, NewStaticIterator(ctx, []*structs.Node{node}))
+		hosts.SetJob(e.job)
+		hosts.SetTaskGroup(tg)
+		out.Checks = append(out.Checks, explainIterator(ctx, structs.ExplainCheckDistinctHosts, hosts))
+	}
+
+	if explainHasOperand(e.job, tg, structs.ConstraintDistinctProperty) {
+		property := NewDistinctPropertyIterator(ctx, NewStaticIterator(ctx, []*structs.Node{node}))
+		property.SetJob(e.job)
+		property.SetTaskGroup(tg)
+		out.Checks = append(out.Checks, explainIterator(ctx, structs.ExplainCheckDistinctProperty, property))
+	}
+
+	// Only run the ranking iterators if the node is feasible, otherwise the
+	// stack would just report the first failed check again.
+	if out.FailedChecks() > 0 {
+		return out, nil
+	}
+
+	option := e.selectNode(ctx, tg, node)
+	check := &structs.NodeExplainCheck{
+		Name:   structs.ExplainCheckResources,
+		Target: tg.Name,
+		Passed: option != nil,
+	}
+	if option == nil {
+		check.Reason = explainExhaustedReason(ctx.Metrics())
+	} else {
+		out.Feasible = true
+		out.Score = option.FinalScore
+	}
+	out.Checks = append(out.Checks, check)
+
+	return out, nil
+}
+
+// selectNode runs the same stack used by the job's scheduler against a
+// single node.
+func (e *jobExplainer) selectNode(ctx *EvalContext, tg *structs.TaskGroup, node *structs.Node) *RankedNode {
+	var stack interface {
+		Stack
+		SetSchedulerConfiguration(*structs.SchedulerConfiguration)
+	}
+	switch e.job.Type {
+	case structs.JobTypeSystem:
+		stack = NewSystemStack(false, ctx)
+	case structs.JobTypeSysBatch:
+		stack = NewSystemStack(true, ctx)
+	default:
+		stack = NewGenericStack(e.job.Type == structs.JobTypeBatch, ctx)
+	}
+
+	stack.SetJob(e.job)
+	stack.SetSchedulerConfiguration(e.schedConfig)
+	stack.SetNodes([]*structs.Node{node})
+	return stack.Select(tg, &SelectOptions{AllocName: structs.AllocName(e.job.ID, tg.Name, 0)})
+}
+
+// explainChecker runs a single feasibility checker against the node.
+func explainChecker(ctx *EvalContext, name, target string, checker FeasibilityChecker, node *structs.Node) *structs.NodeExplainCheck {
+	ctx.Reset()
+	check := &structs.NodeExplainCheck{
+		Name:   name,
+		Target: target,
+		Passed: checker.Feasible(node),
+	}
+	if !check.Passed {
+		check.Reason = explainFilteredReason(ctx.Metrics())
+	}
+	return check
+}
+
+// explainIterator runs a single feasibility iterator whose source only
+// contains the node being explained.
+func explainIterator(ctx *EvalContext, name string, iter FeasibleIterator) *structs.NodeExplainCheck {
+	ctx.Reset()
+	check := &structs.NodeExplainCheck{
+		Name:   name,
+		Passed: iter.Next() != nil,
+	}
+	if !check.Passed {
+		check.Reason = explainFilteredReason(ctx.Metrics())
+	}
+	return check
+}
+
+// explainFilteredReason returns the reasons recorded by feasibility checkers.
+func explainFilteredReason(metrics *structs.AllocMetric) string {
+	return strings.Join(sortedKeys(metrics.ConstraintFiltered), "; ")
+}
+
+// explainExhaustedReason returns the reasons recorded when a node was
+// exhausted by the ranking iterators or the namespace quota.
+func explainExhaustedReason(metrics *structs.AllocMetric) string {
+	reasons := sortedKeys(metrics.DimensionExhausted)
+	for _, dim := range metrics.QuotaExhausted {
+		reasons = append(reasons, fmt.Sprintf("quota: %s", dim))
+	}
+	reasons = append(reasons, sortedKeys(metrics.ConstraintFiltered)...)
+	if len(reasons) == 0 {
+		return "node exhausted"
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// explainAvailable returns the resources left on the node once its reserved
+// resources and the resources of its existing allocations are removed.
+func explainAvailable(ctx *EvalContext, node *structs.Node) (*structs.ExplainResources, error) {
+	proposed, err := ctx.ProposedAllocs(node.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proposed allocations for node %q: %v", node.ID, err)
+	}
+
+	available := node.ComparableResources()
+	available.Subtract(node.ComparableReservedResources())
+	for _, alloc := range proposed {
+		if alloc.ClientTerminalStatus() {
+			continue
+		}
+		available.Subtract(alloc.ComparableResources())
+	}
+
+	return &structs.ExplainResources{
+		CPU:      available.Flattened.Cpu.CpuShares,
+		MemoryMB: available.Flattened.Memory.MemoryMB,
+		DiskMB:   available.Shared.DiskMB,
+	}, nil
+}
+
+// explainRequested returns the resources required by the task group.
+func explainRequested(tg *structs.TaskGroup) *structs.ExplainResources {
+	out := &structs.ExplainResources{}
+	if tg.EphemeralDisk != nil {
+		out.DiskMB = int64(tg.EphemeralDisk.SizeMB)
+	}
+	for _, task := range tg.Tasks {
+		if task.Resources == nil {
+			continue
+		}
+		out.CPU += int64(task.Resources.CPU)
+		out.MemoryMB += int64(task.Resources.MemoryMB)
+	}
+	return out
+}
+
+func explainHasDevices(tg *structs.TaskGroup) bool {
+	for _, task := range tg.Tasks {
+		if task.Resources != nil && len(task.Resources.Devices) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func explainHasOperand(job *structs.Job, tg *structs.TaskGroup, operand string) bool {
+	for _, c := range job.Constraints {
+		if c.Operand == operand {
+			return true
+		}
+	}
+	for _, c := range tg.Constraints {
+		if c.Operand == operand {
+			return true
+		}
+	}
+	return false
+}
+
+// sortNodeExplanations orders the nodes from the closest to fitting the task
+// group to the farthest. Feasible nodes come first, ordered by score. The
+// remaining nodes are ordered by the number of failed feasibility checks and
+// then by how far they are from having enough resources for the task group.
+func sortNodeExplanations(requested *structs.ExplainResources, nodes []*structs.NodeExplanation) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if a.Feasible != b.Feasible {
+			return a.Feasible
+		}
+		if a.Feasible {
+			if a.Score != b.Score {
+				return a.Score > b.Score
+			}
+		} else {
+			if fa, fb := explainFeasibilityFailures(a), explainFeasibilityFailures(b); fa != fb {
+				return fa < fb
+			}
+			if da, db := a.Available.Deficit(requested), b.Available.Deficit(requested); da != db {
+				return da < db
+			}
+		}
+		if a.NodeName != b.NodeName {
+			return a.NodeName < b.NodeName
+		}
+		return a.NodeID < b.NodeID
+	})
+}
+
+// explainFeasibilityFailures returns the number of failed checks excluding
+// the resources check, so that nodes only missing capacity are considered
+// closer than nodes that can never run the task group.
+func explainFeasibilityFailures(n *structs.NodeExplanation) int {
+	failed := 0
+	for _, check := range n.Checks {
+		if !check.Passed && check.Name != structs.ExplainCheckResources {
+			failed++
+		}
+	}
+	return failed
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := maps.Keys(m)
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/testlog"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/state"
+	"github.com/open-wander/wander/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestExplainJob(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+
+	feasible := mock.Node()
+	feasible.Name = "feasible"
+
+	windows := mock.Node()
+	windows.Name = "windows"
+	windows.Attributes["kernel.name"] = "windows"
+	windows.ComputeClass()
+
+	small := mock.Node()
+	small.Name = "small"
+	small.NodeResources.Memory.MemoryMB = 300
+	small.ComputeClass()
+
+	otherDC := mock.Node()
+	otherDC.Datacenter = "dc2"
+	otherDC.ComputeClass()
+
+	for i, node := range []*structs.Node{feasible, windows, small, otherDC} {
+		must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+
+	job := mock.Job()
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 200, nil, job))
+	tgName := job.TaskGroups[0].Name
+
+	out, err := ExplainJob(testlog.HCLogger(t), store, job, "")
+	must.NoError(t, err)
+	must.MapLen(t, 1, out)
+
+	tg := out[tgName]
+	must.NotNil(t, tg)
+	must.Eq(t, 3, tg.NodesEvaluated)
+	must.Eq(t, 1, tg.NodesFeasible)
+	must.Eq(t, map[string]int{"dc1": 3}, tg.NodesAvailable)
+	must.Eq(t, int64(job.TaskGroups[0].Tasks[0].Resources.MemoryMB), tg.Requested.MemoryMB)
+	must.Eq(t, map[string]int{
+		"resources (memory)": 1,
+		fmt.Sprintf("constraint (%s)", job.Constraints[0]): 1,
+	}, tg.NodesFailed)
+	must.Len(t, 3, tg.Nodes)
+
+	// The feasible node is sorted first and has a score.
+	first := tg.Nodes[0]
+	must.Eq(t, feasible.ID, first.NodeID)
+	must.True(t, first.Feasible)
+	must.True(t, first.Closest)
+	must.Positive(t, first.Score)
+	must.Zero(t, first.FailedChecks())
+
+	// The node without enough memory passes every feasibility check so it is
+	// closer than the node with the wrong kernel.
+	second := tg.Nodes[1]
+	must.Eq(t, small.ID, second.NodeID)
+	must.False(t, second.Feasible)
+	must.Eq(t, 1, second.FailedChecks())
+	last := second.Checks[len(second.Checks)-1]
+	must.Eq(t, structs.ExplainCheckResources, last.Name)
+	must.False(t, last.Passed)
+	must.Eq(t, "memory", last.Reason)
+
+	third := tg.Nodes[2]
+	must.Eq(t, windows.ID, third.NodeID)
+	must.False(t, third.Feasible)
+	must.Eq(t, 1, third.FailedChecks())
+
+	var constraintCheck *structs.NodeExplainCheck
+	for _, check := range third.Checks {
+		if !check.Passed {
+			constraintCheck = check
+		}
+		must.NotEq(t, structs.ExplainCheckResources, check.Name)
+	}
+	must.NotNil(t, constraintCheck)
+	must.Eq(t, structs.ExplainCheckConstraint, constraintCheck.Name)
+	must.Eq(t, job.Constraints[0].String(), constraintCheck.Target)
+	must.Eq(t, job.Constraints[0].String(), constraintCheck.Reason)
+}
+
+func TestExplainJob_PortCollision(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+
+	node := mock.Node()
+	must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 100, node))
+
+	job := mock.Job()
+	job.TaskGroups[0].Networks = []*structs.NetworkResource{{
+		ReservedPorts: []structs.Port{{Label: "ssh", Value: 22, HostNetwork: "default"}},
+	}}
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 200, nil, job))
+
+	out, err := ExplainJob(testlog.HCLogger(t), store, job, job.TaskGroups[0].Name)
+	must.NoError(t, err)
+
+	tg := out[job.TaskGroups[0].Name]
+	must.Eq(t, 0, tg.NodesFeasible)
+	must.Len(t, 1, tg.Nodes)
+	must.True(t, tg.Nodes[0].Closest)
+
+	checks := tg.Nodes[0].Checks
+	last := checks[len(checks)-1]
+	must.Eq(t, structs.ExplainCheckResources, last.Name)
+	must.StrContains(t, last.Reason, "port collision")
+}
+
+func TestExplainJob_UnknownTaskGroup(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	job := mock.Job()
+
+	_, err := ExplainJob(testlog.HCLogger(t), store, job, "nope")
+	must.ErrorContains(t, err, `task group "nope" not found`)
+}
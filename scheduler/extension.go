@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/open-wander/wander/nomad/structs"
+	schedplugin "github.com/open-wander/wander/plugins/scheduler"
+)
+
+const (
+	// DefaultExtensionTimeout is the maximum amount of time the scheduler
+	// waits for an extension to score a node when no timeout is configured.
+	DefaultExtensionTimeout = 100 * time.Millisecond
+)
+
+// ExtensionScorer is the subset of a scheduler plugin used to score nodes.
+type ExtensionScorer interface {
+	ScoreNode(ctx context.Context, req *schedplugin.ScoreNodeRequest) (*schedplugin.ScoreNodeResponse, error)
+}
+
+// Extension is an external scorer consulted for every candidate node after
+// it has been ranked and before its scores are normalized.
+type Extension struct {
+	// Name is the name of the extension, used in metrics, logs and the
+	// placement metrics of allocations.
+	Name string
+
+	// Scorer is the plugin used to score nodes.
+	Scorer ExtensionScorer
+
+	// Timeout is the maximum amount of time to wait for the extension to
+	// score a node. DefaultExtensionTimeout is used if unset.
+	Timeout time.Duration
+}
+
+// ExtensionPlanner is an optional interface implemented by planners that
+// provide scheduler extensions to the generic scheduler.
+type ExtensionPlanner interface {
+	// SchedulerExtensions returns the extensions to consult when ranking
+	// nodes.
+	SchedulerExtensions() []*Extension
+}
+
+// ExtensionIterator is a RankIterator that lets scheduler extensions filter
+// out candidate nodes and adjust their scores. Extensions fail open: if an
+// extension returns an error or times out, the node is passed through
+// unchanged and the extension is not consulted again for the remainder of
+// the evaluation.
+type ExtensionIterator struct {
+	ctx        Context
+	source     RankIterator
+	extensions []*Extension
+	failed     map[string]struct{}
+	job        *structs.Job
+	tg         *structs.TaskGroup
+}
+
+// NewExtensionIterator is used to create an ExtensionIterator. The iterator
+// passes nodes through untouched until extensions are set.
+func NewExtensionIterator(ctx Context, source RankIterator) *ExtensionIterator {
+	return &ExtensionIterator{
+		ctx:    ctx,
+		source: source,
+		failed: make(map[string]struct{}),
+	}
+}
+
+func (iter *ExtensionIterator) SetExtensions(extensions []*Extension) {
+	iter.extensions = extensions
+}
+
+func (iter *ExtensionIterator) SetJob(job *structs.Job) {
+	iter.job = job
+}
+
+func (iter *ExtensionIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.tg = tg
+}
+
+func (iter *ExtensionIterator) Next() *RankedNode {
+	for {
+		option := iter.source.Next()
+		if option == nil || len(iter.extensions) == 0 {
+			return option
+		}
+
+		if iter.scoreNode(option) {
+			return option
+		}
+	}
+}
+
+func (iter *ExtensionIterator) Reset() {
+	iter.source.Reset()
+}
+
+// scoreNode consults every healthy extension for the node and returns false
+// if the node was filtered out.
+func (iter *ExtensionIterator) scoreNode(option *RankedNode) bool {
+	req := iter.request(option)
+	for _, ext := range iter.extensions {
+		if _, ok := iter.failed[ext.Name]; ok {
+			continue
+		}
+
+		resp, err := iter.callExtension(ext, req)
+		if err != nil {
+			iter.failed[ext.Name] = struct{}{}
+			iter.ctx.Logger().Warn("scheduler extension failed, ignoring it for the rest of the evaluation",
+				"extension", ext.Name, "node_id", option.Node.ID, "error", err)
+			metrics.IncrCounterWithLabels([]string{"scheduler", "extension", "error"}, 1,
+				[]metrics.Label{{Name: "extension", Value: ext.Name}})
+			continue
+		}
+
+		if resp.Filter {
+			reason := fmt.Sprintf("scheduler extension %s", ext.Name)
+			if resp.Reason != "" {
+				reason = fmt.Sprintf("%s: %s", reason, resp.Reason)
+			}
+			iter.ctx.Metrics().FilterNode(option.Node, reason)
+			return false
+		}
+
+		score := resp.ClampedScore()
+		if score != 0 {
+			option.Scores = append(option.Scores, score)
+		}
+		iter.ctx.Metrics().ScoreNode(option.Node, "extension-"+ext.Name, score)
+	}
+	return true
+}
+
+// callExtension calls the extension with its configured timeout.
+func (iter *ExtensionIterator) callExtension(ext *Extension, req *schedplugin.ScoreNodeRequest) (*schedplugin.ScoreNodeResponse, error) {
+	defer metrics.MeasureSinceWithLabels([]string{"scheduler", "extension", "score_node"}, time.Now(),
+		[]metrics.Label{{Name: "extension", Value: ext.Name}})
+
+	timeout := ext.Timeout
+	if timeout <= 0 {
+		timeout = DefaultExtensionTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	resp, err := ext.Scorer.ScoreNode(ctx, req)
+	if err == nil && resp == nil {
+		err = fmt.Errorf("empty response")
+	}
+	return resp, err
+}
+
+func (iter *ExtensionIterator) request(option *RankedNode) *schedplugin.ScoreNodeRequest {
+	node := option.Node
+	req := &schedplugin.ScoreNodeRequest{
+		EvalID: iter.ctx.Plan().EvalID,
+		Node: &schedplugin.CandidateNode{
+			ID:         node.ID,
+			Name:       node.Name,
+			Datacenter: node.Datacenter,
+			NodeClass:  node.NodeClass,
+			NodePool:   node.NodePool,
+			Attributes: node.Attributes,
+			Meta:       node.Meta,
+			Scores:     option.Scores,
+		},
+	}
+	if iter.job != nil {
+		req.Namespace = iter.job.Namespace
+		req.JobID = iter.job.ID
+		req.JobType = iter.job.Type
+		req.Priority = iter.job.Priority
+		req.JobMeta = iter.job.Meta
+	}
+	if iter.tg != nil {
+		req.TaskGroup = iter.tg.Name
+		req.TaskGroupMeta = iter.tg.Meta
+	}
+	return req
+}
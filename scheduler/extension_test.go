@@ -0,0 +1,226 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-wander/wander/ci"
+	"github.com/open-wander/wander/helper/uuid"
+	"github.com/open-wander/wander/nomad/mock"
+	"github.com/open-wander/wander/nomad/structs"
+	schedplugin "github.com/open-wander/wander/plugins/scheduler"
+	"github.com/shoenig/test/must"
+)
+
+// testExtensionScorer is an ExtensionScorer that records the requests it
+// receives.
+type testExtensionScorer struct {
+	fn schedplugin.ScoreNodeFn
+
+	lock     sync.Mutex
+	requests []*schedplugin.ScoreNodeRequest
+}
+
+func (s *testExtensionScorer) ScoreNode(ctx context.Context, req *schedplugin.ScoreNodeRequest) (*schedplugin.ScoreNodeResponse, error) {
+	s.lock.Lock()
+	s.requests = append(s.requests, req)
+	s.lock.Unlock()
+	return s.fn(ctx, req)
+}
+
+func (s *testExtensionScorer) calls() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.requests)
+}
+
+func TestExtensionIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+	nodes := []*RankedNode{
+		{Node: mock.Node(), Scores: []float64{0.5}},
+		{Node: mock.Node(), Scores: []float64{0.5}},
+		{Node: mock.Node(), Scores: []float64{0.5}},
+	}
+
+	scorer := &testExtensionScorer{
+		fn: schedplugin.StaticScorer(map[string]*schedplugin.ScoreNodeResponse{
+			nodes[0].Node.ID: {Score: 5},
+			nodes[1].Node.ID: {Filter: true, Reason: "over power budget"},
+		}),
+	}
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+
+	iter := NewExtensionIterator(ctx, NewStaticRankIterator(ctx, nodes))
+	iter.SetExtensions([]*Extension{{Name: "inventory", Scorer: scorer}})
+	iter.SetJob(job)
+	iter.SetTaskGroup(tg)
+
+	out := collectRanked(NewScoreNormalizationIterator(ctx, iter))
+	must.Len(t, 2, out)
+
+	// The score adjustment is clamped to 1 before being averaged.
+	must.Eq(t, nodes[0].Node.ID, out[0].Node.ID)
+	must.Eq(t, 0.75, out[0].FinalScore)
+
+	// Nodes without a score adjustment are untouched.
+	must.Eq(t, nodes[2].Node.ID, out[1].Node.ID)
+	must.Eq(t, 0.5, out[1].FinalScore)
+
+	// The filtered node is recorded in the metrics.
+	metrics := ctx.Metrics()
+	must.Eq(t, 1, metrics.NodesFiltered)
+	must.Eq(t, map[string]int{"scheduler extension inventory: over power budget": 1},
+		metrics.ConstraintFiltered)
+
+	// The extension received the placement details.
+	must.Eq(t, 3, scorer.calls())
+	req := scorer.requests[0]
+	must.Eq(t, ctx.Plan().EvalID, req.EvalID)
+	must.Eq(t, job.Namespace, req.Namespace)
+	must.Eq(t, job.ID, req.JobID)
+	must.Eq(t, job.Type, req.JobType)
+	must.Eq(t, job.Priority, req.Priority)
+	must.Eq(t, tg.Name, req.TaskGroup)
+	must.Eq(t, nodes[0].Node.ID, req.Node.ID)
+	must.Eq(t, nodes[0].Node.Datacenter, req.Node.Datacenter)
+	must.Eq(t, nodes[0].Node.Attributes, req.Node.Attributes)
+	must.Eq(t, []float64{0.5}, req.Node.Scores)
+}
+
+func TestExtensionIterator_NoExtensions(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+	nodes := []*RankedNode{
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+	}
+
+	iter := NewExtensionIterator(ctx, NewStaticRankIterator(ctx, nodes))
+	out := collectRanked(iter)
+	must.Eq(t, nodes, out)
+}
+
+func TestExtensionIterator_FailOpen(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name string
+		fn   schedplugin.ScoreNodeFn
+	}{
+		{
+			name: "error",
+			fn: func(context.Context, *schedplugin.ScoreNodeRequest) (*schedplugin.ScoreNodeResponse, error) {
+				return nil, errors.New("inventory unavailable")
+			},
+		},
+		{
+			name: "timeout",
+			fn: func(ctx context.Context, _ *schedplugin.ScoreNodeRequest) (*schedplugin.ScoreNodeResponse, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		},
+		{
+			name: "empty response",
+			fn: func(context.Context, *schedplugin.ScoreNodeRequest) (*schedplugin.ScoreNodeResponse, error) {
+				return nil, nil
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ctx := testContext(t)
+			nodes := []*RankedNode{
+				{Node: mock.Node(), Scores: []float64{0.5}},
+				{Node: mock.Node(), Scores: []float64{0.5}},
+			}
+
+			broken := &testExtensionScorer{fn: tc.fn}
+			healthy := &testExtensionScorer{
+				fn: schedplugin.StaticScorer(map[string]*schedplugin.ScoreNodeResponse{
+					nodes[1].Node.ID: {Filter: true},
+				}),
+			}
+
+			iter := NewExtensionIterator(ctx, NewStaticRankIterator(ctx, nodes))
+			iter.SetExtensions([]*Extension{
+				{Name: "broken", Scorer: broken, Timeout: 10 * time.Millisecond},
+				{Name: "healthy", Scorer: healthy},
+			})
+
+			out := collectRanked(iter)
+
+			// The broken extension is skipped after its first failure while
+			// the healthy extension is still consulted.
+			must.Len(t, 1, out)
+			must.Eq(t, nodes[0].Node.ID, out[0].Node.ID)
+			must.Eq(t, []float64{0.5}, out[0].Scores)
+			must.Eq(t, 1, broken.calls())
+			must.Eq(t, 2, healthy.calls())
+		})
+	}
+}
+
+func TestServiceSched_JobRegister_Extension(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	var nodes []*structs.Node
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Only the last node is allowed by the extension.
+	allowed := nodes[2].ID
+	scorer := &testExtensionScorer{
+		fn: func(_ context.Context, req *schedplugin.ScoreNodeRequest) (*schedplugin.ScoreNodeResponse, error) {
+			if req.Node.ID != allowed {
+				return &schedplugin.ScoreNodeResponse{Filter: true, Reason: "license"}, nil
+			}
+			return &schedplugin.ScoreNodeResponse{Score: 1}, nil
+		},
+	}
+	h.Extensions = []*Extension{{Name: "inventory", Scorer: scorer}}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	must.NoError(t, h.Process(NewServiceScheduler, eval))
+	must.Len(t, 1, h.Plans)
+
+	// Every allocation is placed on the allowed node.
+	plan := h.Plans[0]
+	must.MapLen(t, 1, plan.NodeAllocation)
+	must.Len(t, 2, plan.NodeAllocation[allowed])
+	must.Positive(t, scorer.calls())
+
+	for _, alloc := range plan.NodeAllocation[allowed] {
+		must.Eq(t, 2, alloc.Metrics.ConstraintFiltered["scheduler extension inventory: license"])
+	}
+}
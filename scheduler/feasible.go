@@ -32,6 +32,7 @@ const (
 	FilterConstraintDrivers                        = "missing drivers"
 	FilterConstraintDevices                        = "missing devices"
 	FilterConstraintsCSIPluginTopology             = "did not meet topology requirement"
+	FilterConstraintNodePressure                   = "node under resource pressure"
 )
 
 var (
@@ -134,6 +135,29 @@ func NewRandomIterator(ctx Context, nodes []*structs.Node) *StaticIterator {
 	return NewStaticIterator(ctx, nodes)
 }
 
+// NodePressureChecker is a FeasibilityChecker which returns whether a node is
+// free of resource pressure. Clients under pressure evict their allocations,
+// so placing new ones there would only have them evicted again.
+type NodePressureChecker struct {
+	ctx Context
+}
+
+// NewNodePressureChecker creates a NodePressureChecker
+func NewNodePressureChecker(ctx Context) *NodePressureChecker {
+	return &NodePressureChecker{
+		ctx: ctx,
+	}
+}
+
+func (c *NodePressureChecker) Feasible(candidate *structs.Node) bool {
+	if !candidate.UnderPressure() {
+		return true
+	}
+
+	c.ctx.Metrics().FilterNode(candidate, FilterConstraintNodePressure)
+	return false
+}
+
 // HostVolumeChecker is a FeasibilityChecker which returns whether a node has
 // the host volumes necessary to schedule a task group.
 type HostVolumeChecker struct {
@@ -164,7 +164,7 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 	// Verify the evaluation trigger reason is understood
 	switch eval.TriggeredBy {
 	case structs.EvalTriggerJobRegister, structs.EvalTriggerJobDeregister,
-		structs.EvalTriggerNodeDrain, structs.EvalTriggerNodeUpdate,
+		structs.EvalTriggerNodeDrain, structs.EvalTriggerNodeEviction, structs.EvalTriggerNodeUpdate,
 		structs.EvalTriggerAllocStop,
 		structs.EvalTriggerRollingUpdate, structs.EvalTriggerQueuedAllocs,
 		structs.EvalTriggerPeriodicJob, structs.EvalTriggerMaxPlans,
@@ -281,6 +281,9 @@ func (s *GenericScheduler) process() (bool, error) {
 
 	// Construct the placement stack
 	s.stack = NewGenericStack(s.batch, s.ctx)
+	if ep, ok := s.planner.(ExtensionPlanner); ok {
+		s.stack.SetExtensions(ep.SchedulerExtensions())
+	}
 	if !s.job.Stopped() {
 		s.setJob(s.job)
 	}
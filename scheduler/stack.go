@@ -58,6 +58,7 @@ type GenericStack struct {
 	taskGroupHostVolumes *HostVolumeChecker
 	taskGroupCSIVolumes  *CSIVolumeChecker
 	taskGroupNetwork     *NetworkChecker
+	nodePressure         *NodePressureChecker
 
 	distinctHostsConstraint    *DistinctHostsIterator
 	distinctPropertyConstraint *DistinctPropertyIterator
@@ -68,6 +69,7 @@ type GenericStack struct {
 	maxScore                   *MaxScoreIterator
 	nodeAffinity               *NodeAffinityIterator
 	spread                     *SpreadIterator
+	extensions                 *ExtensionIterator
 	scoreNorm                  *ScoreNormalizationIterator
 }
 
@@ -109,6 +111,7 @@ func (s *GenericStack) SetJob(job *structs.Job) {
 	s.jobAntiAff.SetJob(job)
 	s.nodeAffinity.SetJob(job)
 	s.spread.SetJob(job)
+	s.extensions.SetJob(job)
 	s.ctx.Eligibility().SetJob(job)
 	s.taskGroupCSIVolumes.SetNamespace(job.Namespace)
 	s.taskGroupCSIVolumes.SetJobID(job.ID)
@@ -125,6 +128,11 @@ func (s *GenericStack) SetSchedulerConfiguration(schedConfig *structs.SchedulerC
 	s.binPack.SetSchedulerConfiguration(schedConfig)
 }
 
+// SetExtensions sets the scheduler extensions consulted when ranking nodes.
+func (s *GenericStack) SetExtensions(extensions []*Extension) {
+	s.extensions.SetExtensions(extensions)
+}
+
 func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *RankedNode {
 
 	// This block handles trying to select from preferred nodes if options specify them
@@ -172,6 +180,7 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 	}
 	s.nodeAffinity.SetTaskGroup(tg)
 	s.spread.SetTaskGroup(tg)
+	s.extensions.SetTaskGroup(tg)
 
 	if s.nodeAffinity.hasAffinities() || s.spread.hasSpreads() {
 		// scoring spread across all nodes has quadratic behavior, so
@@ -400,6 +409,9 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// Filter on available client networks
 	s.taskGroupNetwork = NewNetworkChecker(ctx)
 
+	// Filter out clients under resource pressure
+	s.nodePressure = NewNodePressureChecker(ctx)
+
 	// Create the feasibility wrapper which wraps all feasibility checks in
 	// which feasibility checking can be skipped if the computed node class has
 	// previously been marked as eligible or ineligible. Generally this will be
@@ -414,6 +426,7 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	avail := []FeasibilityChecker{
 		s.taskGroupHostVolumes,
 		s.taskGroupCSIVolumes,
+		s.nodePressure,
 	}
 	s.wrappedChecks = NewFeasibilityWrapper(ctx, s.source, jobs, tgs, avail)
 
@@ -454,8 +467,12 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// Add the preemption options scoring iterator
 	preemptionScorer := NewPreemptionScoringIterator(ctx, s.spread)
 
+	// Let the configured scheduler extensions filter and adjust the scores
+	// of the ranked nodes
+	s.extensions = NewExtensionIterator(ctx, preemptionScorer)
+
 	// Normalizes scores by averaging them across various scorers
-	s.scoreNorm = NewScoreNormalizationIterator(ctx, preemptionScorer)
+	s.scoreNorm = NewScoreNormalizationIterator(ctx, s.extensions)
 
 	// Apply a limit function. This is to avoid scanning *every* possible node.
 	s.limit = NewLimitIterator(ctx, s.scoreNorm, 2, skipScoreThreshold, maxSkip)
@@ -420,6 +420,37 @@ func TestServiceStack_Select_CSI(t *testing.T) {
 	}
 }
 
+func TestServiceStack_Select_NodePressure(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+	}
+	for _, node := range nodes[:2] {
+		node.Conditions = []*structs.NodeCondition{{
+			Type:    structs.NodeConditionMemoryPressure,
+			Message: "memory usage of 97% is over the eviction threshold of 95%",
+		}}
+	}
+	free := nodes[2]
+
+	stack := NewGenericStack(false, ctx)
+	stack.SetNodes(nodes)
+
+	job := mock.Job()
+	stack.SetJob(job)
+	node := stack.Select(job.TaskGroups[0], &SelectOptions{})
+	must.NotNil(t, node, must.Sprintf("missing node %#v", ctx.Metrics()))
+
+	// Nodes under pressure are filtered even though they share the computed
+	// class of the selected node
+	must.Eq(t, free, node.Node)
+	must.Eq(t, 2, ctx.Metrics().ConstraintFiltered[FilterConstraintNodePressure])
+}
+
 func TestServiceStack_Select_ConstraintFilter(t *testing.T) {
 	ci.Parallel(t)
 
@@ -65,6 +65,10 @@ type Harness struct {
 
 	optimizePlan              bool
 	serversMeetMinimumVersion bool
+
+	// Extensions are the scheduler extensions returned to the schedulers
+	// created by the harness.
+	Extensions []*Extension
 }
 
 // NewHarness is used to make a new testing harness
@@ -257,6 +261,11 @@ func (h *Harness) ServersMeetMinimumVersion(_ *version.Version, _ bool) bool {
 	return h.serversMeetMinimumVersion
 }
 
+// SchedulerExtensions returns the scheduler extensions set on the harness.
+func (h *Harness) SchedulerExtensions() []*Extension {
+	return h.Extensions
+}
+
 // NextIndex returns the next index
 func (h *Harness) NextIndex() uint64 {
 	h.nextIndexLock.Lock()
@@ -298,6 +298,9 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) comparison {
 		if !slices.EqualFunc(at.VolumeMounts, bt.VolumeMounts, func(a, b *structs.VolumeMount) bool { return a.Equal(b) }) {
 			return difference("task volume mount", at.VolumeMounts, bt.VolumeMounts)
 		}
+		if !slices.EqualFunc(at.DependsOn, bt.DependsOn, func(a, b *structs.TaskDependency) bool { return a.Equal(b) }) {
+			return difference("task dependencies", at.DependsOn, bt.DependsOn)
+		}
 
 		// Check the metadata
 		metaA := jobA.CombinedTaskMeta(taskGroup, at.Name)
@@ -319,6 +322,9 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) comparison {
 		if !at.Identity.Equal(bt.Identity) {
 			return difference("task identity", at.Identity, bt.Identity)
 		}
+		if !slices.EqualFunc(at.Identities, bt.Identities, func(a, b *structs.WorkloadIdentity) bool { return a.Equal(b) }) {
+			return difference("task identities", at.Identities, bt.Identities)
+		}
 
 		// Most LogConfig updates are in-place but if we change Disabled we need
 		// to recreate the task to stop/start log collection and change the
@@ -327,6 +333,12 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) comparison {
 			return difference("task log disabled", at.LogConfig.Disabled, bt.LogConfig.Disabled)
 		}
 
+		// The log sinks are set when logmon starts, so changing them also
+		// requires recreating the task
+		if !slices.EqualFunc(at.LogConfig.Sinks, bt.LogConfig.Sinks, func(a, b *structs.LogSink) bool { return a.Equal(b) }) {
+			return difference("task log sinks", at.LogConfig.Sinks, bt.LogConfig.Sinks)
+		}
+
 		// Check if restart.render_templates is updated
 		if c := renderTemplatesUpdated(at.RestartPolicy, bt.RestartPolicy,
 			"task restart render_templates"); c.modified {
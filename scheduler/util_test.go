@@ -547,6 +547,36 @@ func TestTasksUpdated(t *testing.T) {
 	// Compare changed Template ErrMissingKey
 	j30.TaskGroups[0].Tasks[0].Templates[0].ErrMissingKey = true
 	must.True(t, tasksUpdated(j29, j30, name).modified)
+
+	// Change a task dependency condition
+	j31 := mock.Job()
+	j31.TaskGroups[0].Tasks[0].DependsOn = []*structs.TaskDependency{
+		{Task: "db", Condition: structs.TaskDependencyConditionStarted},
+	}
+	j32 := j31.Copy()
+	must.False(t, tasksUpdated(j31, j32, name).modified)
+	j32.TaskGroups[0].Tasks[0].DependsOn[0].Condition = structs.TaskDependencyConditionHealthy
+	must.True(t, tasksUpdated(j31, j32, name).modified)
+
+	// Change a workload identity audience
+	j33 := mock.Job()
+	j33.TaskGroups[0].Tasks[0].Identities = []*structs.WorkloadIdentity{
+		{Name: "consul", Audience: []string{"consul.io"}},
+	}
+	j34 := j33.Copy()
+	must.False(t, tasksUpdated(j33, j34, name).modified)
+	j34.TaskGroups[0].Tasks[0].Identities[0].Audience = []string{"example.com"}
+	must.True(t, tasksUpdated(j33, j34, name).modified)
+
+	// Change a log sink
+	j35 := mock.Job()
+	j35.TaskGroups[0].Tasks[0].LogConfig.Sinks = []*structs.LogSink{
+		{Type: structs.LogSinkTypeSyslog, Address: "udp://127.0.0.1:514"},
+	}
+	j36 := j35.Copy()
+	must.False(t, tasksUpdated(j35, j36, name).modified)
+	j36.TaskGroups[0].Tasks[0].LogConfig.Sinks[0].Address = "udp://10.0.0.1:514"
+	must.True(t, tasksUpdated(j35, j36, name).modified)
 }
 
 func TestTasksUpdated_connectServiceUpdated(t *testing.T) {